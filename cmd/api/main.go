@@ -14,15 +14,25 @@ import (
 
 	"github.com/Raymond9734/campaign-messaging-backend/internal/config"
 	"github.com/Raymond9734/campaign-messaging-backend/internal/db"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/events"
 	"github.com/Raymond9734/campaign-messaging-backend/internal/handler"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/pubsub"
 	"github.com/Raymond9734/campaign-messaging-backend/internal/queue"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/ratelimit"
 	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
 	"github.com/Raymond9734/campaign-messaging-backend/internal/service"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/tracing"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/worker"
 )
 
+// liveStatsSchemaVersion is the migration that notifies message status changes
+const liveStatsSchemaVersion = 31
+
 func main() {
-	// Initialize logger
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	// Initialize logger; the level can be changed by a config reload
+	logLevel := new(slog.LevelVar)
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: logLevel}))
 	slog.SetDefault(logger)
 
 	logger.Info("starting CampaignManager API server")
@@ -33,16 +43,35 @@ func main() {
 		logger.Error("failed to load config", slog.String("error", err.Error()))
 		os.Exit(1)
 	}
+	logLevel.Set(cfg.Log.Level)
+
+	// Export spans to the OTLP collector, if one is configured
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, "campaign-api")
+	if err != nil {
+		logger.Error("failed to set up tracing", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Error("failed to flush traces", slog.String("error", err.Error()))
+		}
+	}()
+	if cfg.Tracing.Endpoint != "" {
+		logger.Info("tracing enabled", slog.String("endpoint", cfg.Tracing.Endpoint))
+	}
 
 	// Connect to database
-	database, err := db.New(db.Config{
+	dbConfig := db.Config{
 		Host:     cfg.Database.Host,
 		Port:     cfg.Database.Port,
 		User:     cfg.Database.User,
 		Password: cfg.Database.Password,
 		DBName:   cfg.Database.DBName,
 		SSLMode:  cfg.Database.SSLMode,
-	})
+	}
+	database, err := db.New(dbConfig)
 	if err != nil {
 		logger.Error("failed to connect to database", slog.String("error", err.Error()))
 		os.Exit(1)
@@ -51,57 +80,446 @@ func main() {
 
 	logger.Info("connected to database")
 
-	// Connect to Redis queue
-	queueClient, err := queue.NewRedisClient(queue.RedisConfig{
-		URL:       cfg.Queue.RedisURL,
-		QueueName: cfg.Queue.QueueName,
-	}, logger)
+	// Connect to the queue, on Redis or on the database
+	queueClient, err := queue.New(cfg.Queue, database.DB, logger)
 	if err != nil {
-		logger.Error("failed to connect to Redis", slog.String("error", err.Error()))
+		logger.Error("failed to connect to queue", slog.String("error", err.Error()))
 		os.Exit(1)
 	}
 	defer queueClient.Close()
 
-	logger.Info("connected to Redis queue")
+	logger.Info("connected to queue", slog.String("backend", cfg.Queue.Backend))
+
+	// Progress events travel between processes over Redis pub/sub or
+	// Postgres LISTEN/NOTIFY
+	progressBus, err := pubsub.New(cfg.Queue, database.DB, dbConfig, logger)
+	if err != nil {
+		logger.Error("failed to connect progress bus", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	defer progressBus.Close()
+
+	// Account usage is read from the workers' daily send counters
+	quota, err := ratelimit.NewQuota(cfg.Queue, database.DB)
+	if err != nil {
+		logger.Error("failed to create quota counter", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	defer quota.Close()
 
 	// Initialize repositories
 	customerRepo := repository.NewCustomerRepository(database.DB)
 	campaignRepo := repository.NewCampaignRepository(database.DB)
 	messageRepo := repository.NewOutboundMessageRepository(database.DB)
+	messageNoteRepo := repository.NewMessageNoteRepository(database.DB)
+	messageEventRepo := repository.NewMessageEventRepository(database.DB)
+	senderWarmupRepo := repository.NewSenderWarmupRepository(database.DB)
+	simulationRepo := repository.NewSimulationRepository(database.DB)
+	revisionRepo := repository.NewCampaignRevisionRepository(database.DB)
+	customerEventRepo := repository.NewCustomerEventRepository(database.DB)
+	changeLogRepo := repository.NewChangeLogRepository(database.DB)
+	segmentRepo := repository.NewSegmentRepository(database.DB)
+	senderRegistrationRepo := repository.NewSenderRegistrationRepository(database.DB)
+	previewLinkRepo := repository.NewPreviewLinkRepository(database.DB)
+	shortDomainRepo := repository.NewShortDomainRepository(database.DB)
+	partialRepo := repository.NewTemplatePartialRepository(database.DB)
+	messageTemplateRepo := repository.NewMessageTemplateRepository(database.DB)
+	catalogRepo := repository.NewTemplateCatalogRepository(database.DB)
+	userRepo := repository.NewUserRepository(database.DB)
+	accountRepo := repository.NewAccountRepository(database.DB)
 
 	// Initialize services
-	templateSvc := service.NewTemplateService()
+	templateSvc := service.NewTemplateService(partialRepo, accountRepo)
+
+	// Receive the progress events published by workers for progress streams
+	listenCtx, stopListening := context.WithCancel(context.Background())
+	defer stopListening()
+	go func() {
+		if err := progressBus.Run(listenCtx); err != nil {
+			logger.Error("progress bus stopped", slog.String("error", err.Error()))
+		}
+	}()
+
+	// Serve the stats of campaigns being watched from memory, kept current by
+	// the status change notifications that migration 031 added
+	var liveStats *service.LiveStats
+	if version, err := db.SchemaVersion(listenCtx, database.DB); err != nil || version < liveStatsSchemaVersion {
+		logger.Warn("live campaign stats disabled, stats are counted on every read",
+			slog.Int("schema_version", version),
+			slog.Any("error", err),
+		)
+	} else {
+		liveStats = service.NewLiveStats(campaignRepo, logger)
+		go liveStats.Run(listenCtx)
+		go func() {
+			if err := db.Listen(listenCtx, dbConfig, models.MessageStatsChannel, liveStats, logger); err != nil {
+				logger.Error("message stats listener stopped", slog.String("error", err.Error()))
+			}
+		}()
+	}
+
+	// New campaigns are published as domain events for the event webhook
+	// and activity reports
+	eventBus := events.NewBus(logger)
+	eventTally := events.NewTally()
+	eventBus.SubscribeAll(eventTally.Handle)
+	go eventTally.Report(listenCtx, time.Minute, logger)
+	if cfg.Events.WebhookURL != "" {
+		eventWebhook := events.NewWebhook(cfg.Events.WebhookURL, logger)
+		err := eventWebhook.SetFilter(events.WebhookFilter{
+			Events:    cfg.Events.WebhookEvents,
+			Labels:    cfg.Events.WebhookLabels,
+			Fields:    cfg.Events.WebhookFields,
+			Campaigns: campaignRepo,
+		})
+		if err != nil {
+			logger.Error("invalid event webhook filter", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		eventBus.SubscribeAll(eventWebhook.Handle)
+		go eventWebhook.Run(listenCtx)
+	}
+
+	campaignConfig := service.CampaignServiceConfig{
+		SendBatchSize:     cfg.API.SendBatchSize,
+		RenderConcurrency: cfg.API.RenderConcurrency,
+		ConfirmThreshold:  cfg.API.SendConfirmThreshold,
+		ApprovalThreshold: cfg.API.SendApprovalThreshold,
+		MaxRetryCount:     cfg.Worker.MaxRetryCount,
+		SendJobs:          repository.NewSendJobRepository(database.DB),
+		Recommender:       service.NewRecommender(cfg.API.RecommendationURL, cfg.API.RecommendationTimeout),
+		LiveStats:         liveStats,
+		Events:            eventBus,
+		Templates:         messageTemplateRepo,
+		Catalog:           catalogRepo,
+		Reports:           repository.NewCampaignReportRepository(database.DB),
+		MessageCosts:      cfg.Worker.MessageCosts,
+	}
 
 	campaignSvc := service.NewCampaignService(
 		campaignRepo,
 		customerRepo,
 		messageRepo,
+		segmentRepo,
 		templateSvc,
 		queueClient,
+		campaignConfig,
+		logger,
+	)
+
+	// Simulations always use the mock sender so they never reach a provider
+	simulationSvc := service.NewSimulationService(
+		campaignRepo,
+		customerRepo,
+		segmentRepo,
+		simulationRepo,
+		templateSvc,
+		worker.NewMockSender(0.92),
+		campaignConfig,
+		service.SimulationServiceConfig{
+			SendConcurrency:   cfg.API.SimulationConcurrency,
+			WorkerConcurrency: cfg.Worker.Concurrency,
+		},
 		logger,
 	)
 
+	// The worker enforces these rules; the API reports senders they block
+	registrationRules, err := models.EnforcedRegistrationRules(cfg.Worker.SenderRegistrationCountries)
+	if err != nil {
+		logger.Error("invalid sender registration countries", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	senderSvc := service.NewSenderService(senderWarmupRepo, senderRegistrationRepo, registrationRules, logger)
+	customerSvc := service.NewCustomerService(customerRepo, messageRepo, customerEventRepo, logger)
+	messageSvc := service.NewMessageService(messageRepo, campaignRepo, logger)
+	messageNoteSvc := service.NewMessageNoteService(messageNoteRepo, messageRepo, logger)
+	messageEventSvc := service.NewMessageEventService(messageEventRepo, messageRepo)
+	draftSvc := service.NewDraftService(campaignRepo, revisionRepo, templateSvc, logger)
+	changeSvc := service.NewChangeService(changeLogRepo, logger)
+	deliveryReportSvc := service.NewDeliveryReportService(messageRepo, logger)
+	complianceSvc := service.NewComplianceService(customerRepo, customerEventRepo, logger)
+	analyticsSvc := service.NewAnalyticsService(repository.NewReportsRepository(database.DB), logger)
+	segmentSvc := service.NewSegmentService(segmentRepo, customerRepo, logger)
+	partialSvc := service.NewTemplatePartialService(partialRepo, templateSvc, logger)
+	messageTemplateSvc := service.NewMessageTemplateService(messageTemplateRepo, templateSvc, logger)
+	catalogSvc := service.NewTemplateCatalogService(catalogRepo, templateSvc)
+	previewLinkSvc := service.NewPreviewLinkService(previewLinkRepo, campaignRepo, templateSvc, cfg.API.PublicURL, logger)
+	shortDomainSvc := service.NewShortDomainService(shortDomainRepo, campaignRepo, logger)
+	userSvc := service.NewUserService(userRepo, logger)
+	accountSvc := service.NewAccountService(accountRepo, quota, logger)
+
+	// Operator auth is on once a JWT secret is configured
+	var authSvc service.AuthService
+	if cfg.API.JWTSecret != "" {
+		authSvc = service.NewAuthService(userRepo, cfg.API.JWTSecret, cfg.API.JWTTTL, logger)
+		if cfg.API.AdminEmail != "" {
+			if err := authSvc.EnsureAdmin(context.Background(), cfg.API.AdminEmail, cfg.API.AdminPassword); err != nil {
+				logger.Error("failed to create admin user", slog.String("error", err.Error()))
+				os.Exit(1)
+			}
+		}
+	} else {
+		logger.Warn("JWT_SECRET is not set; the API is open to anyone who can reach it")
+	}
+	authz := handler.NewAuthorizer(authSvc, logger)
+
+	// Long-lived streaming responses are drained on shutdown
+	streams := handler.NewStreamRegistry(logger)
+	progressHandler := handler.NewProgressHandler(campaignSvc, progressBus, streams, logger)
+
 	// Initialize handlers
-	campaignHandler := handler.NewCampaignHandler(campaignSvc, logger)
+	campaignHandler := handler.NewCampaignHandler(campaignSvc, streams, logger)
 	healthHandler := handler.NewHealthHandler(database.DB, queueClient, logger)
+	adminHandler := handler.NewAdminHandler(queueClient, logger)
+	senderHandler := handler.NewSenderHandler(senderSvc, logger)
+	simulationHandler := handler.NewSimulationHandler(simulationSvc, logger)
+	draftHandler := handler.NewDraftHandler(draftSvc, logger)
+	templateHandler := handler.NewTemplateHandler(templateSvc, logger)
+	customerHandler := handler.NewCustomerHandler(customerSvc, logger)
+	messageHandler := handler.NewMessageHandler(messageSvc, logger)
+	messageNoteHandler := handler.NewMessageNoteHandler(messageNoteSvc, logger)
+	messageEventHandler := handler.NewMessageEventHandler(messageEventSvc, logger)
+	changeHandler := handler.NewChangeHandler(changeSvc, logger)
+	webhookHandler := handler.NewWebhookHandler(deliveryReportSvc, complianceSvc, cfg.API.WebhookToken, logger)
+	complianceHandler := handler.NewComplianceHandler(complianceSvc, logger)
+	analyticsHandler := handler.NewAnalyticsHandler(analyticsSvc, logger)
+	segmentHandler := handler.NewSegmentHandler(segmentSvc, logger)
+	previewLinkHandler := handler.NewPreviewLinkHandler(previewLinkSvc, logger)
+	shortDomainHandler := handler.NewShortDomainHandler(shortDomainSvc, logger)
+	partialHandler := handler.NewPartialHandler(partialSvc, logger)
+	messageTemplateHandler := handler.NewMessageTemplateHandler(messageTemplateSvc, logger)
+	catalogHandler := handler.NewTemplateCatalogHandler(catalogSvc, logger)
+	userHandler := handler.NewUserHandler(userSvc, logger)
+	accountHandler := handler.NewAccountHandler(accountSvc, logger)
 
 	// Setup router
 	r := chi.NewRouter()
 
 	// Apply middleware
+	r.Use(handler.TracingMiddleware)
 	r.Use(handler.RecoveryMiddleware(logger))
 	r.Use(handler.LoggingMiddleware(logger))
 	r.Use(handler.CORSMiddleware)
+	r.Use(authz.Authenticate)
+	r.Use(handler.MaintenanceMiddleware(queueClient, logger))
+
+	// Per-request deadlines by route class
+	readDeadline := handler.DeadlineMiddleware(cfg.API.RequestTimeout)
+	bulkDeadline := handler.DeadlineMiddleware(cfg.API.BulkRequestTimeout)
 
 	// Register routes
-	r.Get("/health", healthHandler.Health)
+	r.With(readDeadline).Get("/health", healthHandler.Health)
+
+	if authSvc != nil {
+		r.With(readDeadline).Post("/auth/login", handler.NewAuthHandler(authSvc, logger).Login)
+	}
 
 	r.Route("/api/campaigns", func(r chi.Router) {
-		r.Post("/", campaignHandler.CreateCampaign)
-		r.Get("/", campaignHandler.ListCampaigns)
-		r.Get("/{id}", campaignHandler.GetCampaign)
-		r.Post("/{id}/send", campaignHandler.SendCampaign)
-		r.Post("/{id}/personalized-preview", campaignHandler.PreviewPersonalized)
+		r.Group(func(r chi.Router) {
+			r.Use(readDeadline)
+			r.Post("/", campaignHandler.CreateCampaign)
+			r.Post("/import", campaignHandler.ImportCampaign)
+			r.Put("/by-key/{external_key}", campaignHandler.ProvisionCampaign)
+			r.Get("/", campaignHandler.ListCampaigns)
+			r.Get("/updated-since", campaignHandler.ListUpdatedSince)
+			r.Get("/{id}", campaignHandler.GetCampaign)
+			r.Get("/by-external-id/{external_id}", campaignHandler.GetCampaignByExternalID)
+			r.Get("/slug/{slug}", campaignHandler.GetCampaignBySlug)
+			r.Get("/{id}/export", campaignHandler.ExportCampaign)
+			r.Put("/{id}/max-cost", campaignHandler.SetMaxCost)
+			r.Put("/{id}/max-in-flight", campaignHandler.SetMaxInFlight)
+			r.Put("/{id}/validate-numbers", campaignHandler.SetValidateNumbers)
+			r.Put("/{id}/utm", campaignHandler.SetUTM)
+			r.Put("/{id}/short-domain", shortDomainHandler.SetCampaignShortDomain)
+			r.Get("/{id}/short-domain", shortDomainHandler.GetCampaignShortDomain)
+			r.Post("/{id}/personalized-preview", campaignHandler.PreviewPersonalized)
+			r.Post("/{id}/preview-sample", campaignHandler.PreviewSample)
+			r.Post("/{id}/estimate", campaignHandler.Estimate)
+			r.Post("/{id}/preview-link", previewLinkHandler.CreateLink)
+			r.Delete("/{id}/preview-link", previewLinkHandler.RevokeLinks)
+			r.Get("/{id}/messages", messageHandler.ListCampaignMessages)
+			r.Get("/{id}/bounces", messageHandler.GetCampaignBounces)
+			r.Get("/{id}/report", campaignHandler.GetReport)
+			r.With(authz.Require(models.RoleSender)).Post("/{id}/simulate", simulationHandler.Simulate)
+			r.Get("/{id}/simulations/{simulationID}", simulationHandler.GetSimulation)
+			r.Put("/{id}/draft", draftHandler.SaveDraft)
+			r.Get("/{id}/revisions", draftHandler.ListRevisions)
+			r.Post("/{id}/revisions/{revision}/revert", draftHandler.RevertRevision)
+		})
+
+		// Operations that walk a whole audience or message history
+		r.Group(func(r chi.Router) {
+			r.Use(bulkDeadline)
+			r.Delete("/{id}", campaignHandler.DeleteCampaign)
+			r.With(authz.Require(models.RoleSender)).Post("/{id}/send", campaignHandler.SendCampaign)
+			r.With(authz.Require(models.RoleSender)).Post("/{id}/pause", campaignHandler.PauseCampaign)
+			r.With(authz.Require(models.RoleSender)).Post("/{id}/resume", campaignHandler.ResumeCampaign)
+			r.With(authz.Require(models.RoleSender)).Post("/{id}/cancel", campaignHandler.CancelCampaign)
+			r.With(authz.Require(models.RoleSender)).Post("/{id}/retry-failed", campaignHandler.RetryCampaignFailed)
+		})
+
+		// Streams are bounded per page rather than per request
+		r.Get("/{id}/messages/stream", campaignHandler.StreamMessages)
+		r.Get("/{id}/failures/export", campaignHandler.ExportFailures)
+		r.Get("/{id}/progress", progressHandler.StreamProgress)
+	})
+
+	// Approving a held send queues its messages
+	r.With(bulkDeadline, authz.Require(models.RoleSender)).Post("/api/send-jobs/{id}/approve", campaignHandler.ApproveSendJob)
+
+	r.Route("/api/customers", func(r chi.Router) {
+		r.Use(readDeadline)
+		r.Post("/", customerHandler.CreateCustomer)
+		r.Get("/", customerHandler.ListCustomers)
+		r.Post("/lookup", customerHandler.LookupCustomers)
+		r.Get("/{id}", customerHandler.GetCustomer)
+		r.Get("/by-external-id/{external_id}", customerHandler.GetCustomerByExternalID)
+		r.Put("/{id}", customerHandler.UpdateCustomer)
+		r.Delete("/{id}", customerHandler.DeleteCustomer)
+		r.Put("/{id}/snooze", customerHandler.SnoozeCustomer)
+		r.Get("/{id}/timeline", customerHandler.GetTimeline)
+	})
+
+	r.Route("/api/segments", func(r chi.Router) {
+		r.Use(readDeadline)
+		r.Post("/", segmentHandler.CreateSegment)
+		r.Get("/", segmentHandler.ListSegments)
+		r.Get("/{id}", segmentHandler.GetSegment)
+		r.Put("/{id}", segmentHandler.UpdateSegment)
+		r.Delete("/{id}", segmentHandler.DeleteSegment)
+		r.Get("/{id}/members", segmentHandler.ListMembers)
+		r.Post("/{id}/count", segmentHandler.RefreshCount)
+	})
+
+	r.Route("/api/short-domains", func(r chi.Router) {
+		r.Group(func(r chi.Router) {
+			r.Use(readDeadline)
+			r.Post("/", shortDomainHandler.CreateShortDomain)
+			r.Get("/", shortDomainHandler.ListShortDomains)
+			r.Get("/{id}", shortDomainHandler.GetShortDomain)
+			r.Put("/{id}", shortDomainHandler.UpdateShortDomain)
+			r.Delete("/{id}", shortDomainHandler.DeleteShortDomain)
+		})
+
+		// A health check waits on the domain's DNS and HTTPS answers
+		r.With(bulkDeadline).Post("/{id}/check", shortDomainHandler.CheckShortDomain)
+	})
+
+	r.Route("/api/messages", func(r chi.Router) {
+		r.Use(readDeadline)
+		r.Get("/", messageHandler.ListMessages)
+		r.Get("/updated-since", messageHandler.ListUpdatedSince)
+		r.Get("/{id}", messageHandler.GetMessage)
+		r.Post("/{id}/notes", messageNoteHandler.CreateNote)
+		r.Get("/{id}/notes", messageNoteHandler.ListNotes)
+		r.Get("/{id}/events", messageEventHandler.ListEvents)
+	})
+
+	r.With(readDeadline).Get("/api/changes", changeHandler.ListChanges)
+	r.With(readDeadline).Get("/api/receipts", messageHandler.ListReceipts)
+
+	r.Route("/api/templates", func(r chi.Router) {
+		r.Use(readDeadline)
+		r.Post("/preview", templateHandler.Preview)
+		r.Post("/encoding", templateHandler.CheckEncoding)
+		r.Post("/partials", partialHandler.CreatePartial)
+		r.Get("/partials", partialHandler.ListPartials)
+		r.Get("/partials/{name}", partialHandler.GetPartial)
+		r.Put("/partials/{name}", partialHandler.UpdatePartial)
+		r.Delete("/partials/{name}", partialHandler.DeletePartial)
+		r.Post("/", messageTemplateHandler.CreateTemplate)
+		r.Get("/", messageTemplateHandler.ListTemplates)
+		r.Get("/{id}", messageTemplateHandler.GetTemplate)
+		r.Put("/{id}", messageTemplateHandler.UpdateTemplate)
+		r.Delete("/{id}", messageTemplateHandler.DeleteTemplate)
+		r.Get("/{id}/versions", messageTemplateHandler.ListVersions)
+	})
+
+	r.Route("/api/template-catalog", func(r chi.Router) {
+		r.Use(readDeadline)
+		r.Get("/", catalogHandler.ListTemplates)
+		r.Get("/{slug}", catalogHandler.GetTemplate)
+	})
+
+	r.Route("/api/senders", func(r chi.Router) {
+		r.Use(readDeadline)
+		r.Put("/{senderID}/warmup", senderHandler.SetWarmup)
+		r.Get("/{senderID}/warmup", senderHandler.GetWarmup)
+		r.Delete("/{senderID}/warmup", senderHandler.DeleteWarmup)
+		r.Get("/{senderID}/registrations", senderHandler.ListRegistrations)
+		r.Put("/{senderID}/registrations/{country}", senderHandler.SetRegistration)
+		r.Delete("/{senderID}/registrations/{country}", senderHandler.DeleteRegistration)
+	})
+
+	r.Route("/api/compliance", func(r chi.Router) {
+		r.Use(readDeadline)
+		r.Get("/opt-outs", complianceHandler.OptOutReport)
+	})
+
+	r.Route("/api/analytics", func(r chi.Router) {
+		r.Use(readDeadline)
+		r.Get("/overview", analyticsHandler.Overview)
+	})
+
+	// Quarantine, maintenance and failed message retries span every account
+	r.Route("/api/admin", func(r chi.Router) {
+		r.Use(authz.Require(models.RoleAdmin))
+		r.Use(authz.RequireOperator)
+
+		r.Group(func(r chi.Router) {
+			r.Use(readDeadline)
+			r.Get("/quarantine", adminHandler.ListQuarantine)
+			r.Delete("/quarantine", adminHandler.PurgeQuarantine)
+			r.Get("/maintenance", adminHandler.GetMaintenance)
+			r.Put("/maintenance", adminHandler.EnableMaintenance)
+			r.Delete("/maintenance", adminHandler.DisableMaintenance)
+		})
+
+		r.With(bulkDeadline).Post("/messages/retry-failed", campaignHandler.RetryFailedMessages)
+	})
+
+	r.Route("/api/users", func(r chi.Router) {
+		r.Use(readDeadline)
+		r.Use(authz.Require(models.RoleAdmin))
+		r.Post("/", userHandler.CreateUser)
+		r.Get("/", userHandler.ListUsers)
+		r.Put("/{id}/role", userHandler.UpdateUserRole)
+		r.Delete("/{id}", userHandler.DeleteUser)
+	})
+
+	r.Route("/api/accounts", func(r chi.Router) {
+		r.Use(readDeadline)
+		r.Use(authz.Require(models.RoleAdmin))
+		r.Use(authz.RequireOperator)
+		r.Post("/", accountHandler.CreateAccount)
+		r.Get("/", accountHandler.ListAccounts)
+		r.Put("/{id}/daily-send-cap", accountHandler.SetDailySendCap)
+		r.Get("/{id}/usage", accountHandler.GetUsage)
+	})
+
+	// The signed-in account's own usage and the customer attributes its
+	// templates may use
+	r.Route("/api/account", func(r chi.Router) {
+		r.Use(readDeadline)
+		r.Get("/usage", accountHandler.GetOwnUsage)
+		r.Get("/customer-attributes", accountHandler.GetCustomerAttributes)
+		r.With(authz.Require(models.RoleAdmin)).Put("/customer-attributes", accountHandler.SetCustomerAttributes)
+		r.Get("/bounce-policy", accountHandler.GetBouncePolicy)
+		r.With(authz.Require(models.RoleAdmin)).Put("/bounce-policy", accountHandler.SetBouncePolicy)
+	})
+
+	// Preview links are opened by reviewers without API access; the token is the credential
+	r.With(readDeadline).Get("/preview/{token}", previewLinkHandler.ShowPreview)
+
+	// Provider callbacks, outside /api as providers call them directly
+	r.Route("/webhooks", func(r chi.Router) {
+		r.Use(readDeadline)
+		r.Post("/delivery-reports", webhookHandler.ReceiveDeliveryReports)
+		r.Get("/delivery-reports", webhookHandler.VerifyMeta)
+		r.Post("/inbound", webhookHandler.ReceiveInbound)
+		r.Post("/meta", webhookHandler.ReceiveMeta)
+		r.Get("/meta", webhookHandler.VerifyMeta)
 	})
 
 	// Create server
@@ -113,6 +531,16 @@ func main() {
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
+	// Shutdown waits for active connections; tell streams to finish so they do not hold it up
+	server.RegisterOnShutdown(streams.Drain)
+
+	// Apply tunables on SIGHUP without restarting the server
+	reloadCtx, stopReload := context.WithCancel(context.Background())
+	defer stopReload()
+	go config.Watch(reloadCtx, logger, func(reloaded *config.Config) {
+		logLevel.Set(reloaded.Log.Level)
+		logger.Info("api tunables applied", slog.String("log_level", reloaded.Log.Level.String()))
+	})
 
 	// Start server in goroutine
 	serverErrors := make(chan error, 1)
@@ -138,7 +566,10 @@ func main() {
 		defer cancel()
 
 		if err := server.Shutdown(ctx); err != nil {
-			logger.Error("server shutdown failed", slog.String("error", err.Error()))
+			logger.Error("server shutdown failed",
+				slog.String("error", err.Error()),
+				slog.Int("open_streams", streams.Active()),
+			)
 			os.Exit(1)
 		}
 
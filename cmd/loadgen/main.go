@@ -0,0 +1,555 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/config"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/db"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// loadgenPhonePrefix marks synthetic customers so they can be cleaned up afterwards
+const loadgenPhonePrefix = "+999"
+
+// options holds the command-line flags
+type options struct {
+	apiURL    string
+	customers int
+	rate      float64
+	batch     int
+	timeout   time.Duration
+	cleanup   bool
+}
+
+// lagSample is a point-in-time measurement of the pipeline backlog
+type lagSample struct {
+	queueDepth int64
+	oldest     time.Duration
+}
+
+func main() {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	var opts options
+	flag.StringVar(&opts.apiURL, "api", "http://localhost:8080", "base URL of the API server")
+	flag.IntVar(&opts.customers, "customers", 1000, "number of synthetic customers (and messages) to generate")
+	flag.Float64Var(&opts.rate, "rate", 100, "target send rate in messages per second")
+	flag.IntVar(&opts.batch, "batch", 100, "recipients per send request")
+	flag.DurationVar(&opts.timeout, "timeout", 10*time.Minute, "max time to wait for the pipeline to drain")
+	flag.BoolVar(&opts.cleanup, "cleanup", true, "delete the campaign and synthetic customers when done")
+	flag.Parse()
+
+	if opts.customers < 1 || opts.rate <= 0 || opts.batch < 1 {
+		fmt.Fprintln(os.Stderr, "customers, rate and batch must be positive")
+		os.Exit(2)
+	}
+
+	// Database and queue settings come from the same environment as the API and worker
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Error("failed to load config", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	database, err := db.New(db.Config{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		User:     cfg.Database.User,
+		Password: cfg.Database.Password,
+		DBName:   cfg.Database.DBName,
+		SSLMode:  cfg.Database.SSLMode,
+	})
+	if err != nil {
+		logger.Error("failed to connect to database", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	defer database.Close()
+
+	queueDepth, closeQueue, err := newCampaignDepth(cfg.Queue, database.DB)
+	if err != nil {
+		logger.Error("invalid REDIS_URL", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	defer closeQueue()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, opts, database.DB, queueDepth, logger); err != nil {
+		logger.Error("load test failed", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+}
+
+// run seeds customers, drives sends through the API at the target rate, waits
+// for the worker to drain the campaign and prints the report
+func run(ctx context.Context, opts options, database *sql.DB, queueDepth campaignDepthFunc, logger *slog.Logger) error {
+	api := &apiClient{baseURL: opts.apiURL, http: &http.Client{Timeout: 60 * time.Second}}
+
+	seedStart := time.Now()
+	customerIDs, err := seedCustomers(ctx, database, opts.customers)
+	if err != nil {
+		return err
+	}
+	logger.Info("synthetic customers created",
+		slog.Int("count", len(customerIDs)),
+		slog.Duration("took", time.Since(seedStart)),
+	)
+
+	if opts.cleanup {
+		defer func() {
+			if err := deleteCustomers(context.WithoutCancel(ctx), database); err != nil {
+				logger.Error("failed to delete synthetic customers", slog.String("error", err.Error()))
+			}
+		}()
+	}
+
+	campaignID, err := api.createCampaign(ctx)
+	if err != nil {
+		return err
+	}
+	logger.Info("campaign created", slog.Int64("campaign_id", campaignID))
+
+	if opts.cleanup {
+		defer func() {
+			if err := api.deleteCampaign(context.WithoutCancel(ctx), campaignID); err != nil {
+				logger.Error("failed to delete campaign", slog.String("error", err.Error()))
+			}
+		}()
+	}
+
+	// Sample the backlog while sending and draining
+	samples := make(chan lagSample, 1024)
+	samplerCtx, stopSampler := context.WithCancel(ctx)
+	samplerDone := make(chan []lagSample)
+	go func() {
+		samplerDone <- collectSamples(samples)
+	}()
+	go sampleLag(samplerCtx, database, queueDepth, campaignID, samples)
+
+	sendStart := time.Now()
+	requestLatencies, err := driveSends(ctx, api, campaignID, customerIDs, opts)
+	sendElapsed := time.Since(sendStart)
+	if err != nil {
+		stopSampler()
+		<-samplerDone
+		return err
+	}
+	logger.Info("all send requests issued",
+		slog.Int("requests", len(requestLatencies)),
+		slog.Duration("took", sendElapsed),
+	)
+
+	drainCtx, cancel := context.WithTimeout(ctx, opts.timeout)
+	defer cancel()
+	err = waitForDrain(drainCtx, database, campaignID)
+	totalElapsed := time.Since(sendStart)
+	stopSampler()
+	lag := <-samplerDone
+	if err != nil {
+		return fmt.Errorf("pipeline did not drain: %w", err)
+	}
+
+	endToEnd, sent, failed, err := messageLatencies(ctx, database, campaignID)
+	if err != nil {
+		return err
+	}
+
+	printReport(os.Stdout, opts, sendElapsed, totalElapsed, requestLatencies, endToEnd, sent, failed, lag)
+	return nil
+}
+
+// seedCustomers bulk-loads synthetic customers with COPY and returns their IDs.
+// They belong to the default account, which the API acts for without auth.
+func seedCustomers(ctx context.Context, database *sql.DB, count int) ([]int64, error) {
+	tx, err := database.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback() // Rollback is safe to call even after Commit
+	}()
+
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn("customers", "account_id", "phone", "first_name", "last_name", "location", "preferred_product"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare copy: %w", err)
+	}
+
+	runID := time.Now().Unix() % 100000
+	for i := 0; i < count; i++ {
+		phone := fmt.Sprintf("%s%05d%07d", loadgenPhonePrefix, runID, i)
+		if _, err := stmt.ExecContext(ctx, models.DefaultAccountID, phone, fmt.Sprintf("Load%d", i), "Test", "Nairobi", "Load Test Bundle"); err != nil {
+			stmt.Close()
+			return nil, fmt.Errorf("failed to copy customer: %w", err)
+		}
+	}
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		stmt.Close()
+		return nil, fmt.Errorf("failed to flush copy: %w", err)
+	}
+	if err := stmt.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close copy: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx,
+		`SELECT id FROM customers WHERE phone LIKE $1 ORDER BY id`,
+		fmt.Sprintf("%s%05d%%", loadgenPhonePrefix, runID),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read customer ids: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]int64, 0, count)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan customer id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read customer ids: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return ids, nil
+}
+
+// deleteCustomers removes every synthetic customer that no longer has messages
+func deleteCustomers(ctx context.Context, database *sql.DB) error {
+	_, err := database.ExecContext(ctx, `
+		DELETE FROM customers c
+		WHERE c.phone LIKE $1
+		AND NOT EXISTS (SELECT 1 FROM outbound_messages m WHERE m.customer_id = c.id)`,
+		loadgenPhonePrefix+"%",
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete customers: %w", err)
+	}
+	return nil
+}
+
+// driveSends posts the audience to the send endpoint in chunks of opts.batch,
+// pacing requests so the overall rate matches opts.rate messages per second.
+// It returns the latency of each send request.
+func driveSends(ctx context.Context, api *apiClient, campaignID int64, customerIDs []int64, opts options) ([]time.Duration, error) {
+	interval := time.Duration(float64(opts.batch) / opts.rate * float64(time.Second))
+	if interval < time.Millisecond {
+		interval = time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	latencies := make([]time.Duration, 0, len(customerIDs)/opts.batch+1)
+	for start := 0; start < len(customerIDs); start += opts.batch {
+		end := start + opts.batch
+		if end > len(customerIDs) {
+			end = len(customerIDs)
+		}
+
+		requestStart := time.Now()
+		if err := api.sendCampaign(ctx, campaignID, customerIDs[start:end]); err != nil {
+			return latencies, err
+		}
+		latencies = append(latencies, time.Since(requestStart))
+
+		if end == len(customerIDs) {
+			break
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return latencies, ctx.Err()
+		}
+	}
+
+	return latencies, nil
+}
+
+// campaignDepthFunc counts the ready jobs of a campaign
+type campaignDepthFunc func(ctx context.Context, campaignID int64) (int64, error)
+
+// newCampaignDepth returns how to count a campaign's ready jobs on the
+// configured queue backend, and a function closing its connection
+func newCampaignDepth(cfg config.QueueConfig, database *sql.DB) (campaignDepthFunc, func(), error) {
+	if cfg.Backend == config.QueueBackendPostgres {
+		return func(ctx context.Context, campaignID int64) (int64, error) {
+			var depth int64
+			err := database.QueryRowContext(ctx, `
+				SELECT COUNT(*) FROM queue_jobs
+				WHERE queue = $1 AND campaign_id = $2 AND consumer IS NULL AND turn IS NOT NULL`,
+				cfg.QueueName, campaignID,
+			).Scan(&depth)
+			return depth, err
+		}, func() {}, nil
+	}
+
+	redisOpts, err := redis.ParseURL(cfg.RedisURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	redisClient := redis.NewClient(redisOpts)
+
+	return func(ctx context.Context, campaignID int64) (int64, error) {
+		// The campaign's jobs wait on its own lane of the queue
+		return redisClient.LLen(ctx, fmt.Sprintf("%s:campaign:%d", cfg.QueueName, campaignID)).Result()
+	}, func() { redisClient.Close() }, nil
+}
+
+// sampleLag records the campaign's queue depth and the age of the oldest unfinished
+// message of the campaign once a second
+func sampleLag(ctx context.Context, database *sql.DB, queueDepth campaignDepthFunc, campaignID int64, samples chan<- lagSample) {
+	defer close(samples)
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		depth, err := queueDepth(ctx, campaignID)
+		if err != nil {
+			continue
+		}
+
+		var oldestSeconds sql.NullFloat64
+		err = database.QueryRowContext(ctx, `
+			SELECT EXTRACT(EPOCH FROM (CURRENT_TIMESTAMP - MIN(created_at)))
+			FROM outbound_messages
+			WHERE campaign_id = $1 AND status IN ('pending', 'sending')`,
+			campaignID,
+		).Scan(&oldestSeconds)
+		if err != nil {
+			continue
+		}
+
+		samples <- lagSample{
+			queueDepth: depth,
+			oldest:     time.Duration(oldestSeconds.Float64 * float64(time.Second)),
+		}
+	}
+}
+
+// collectSamples gathers lag samples until the sampler closes the channel
+func collectSamples(samples <-chan lagSample) []lagSample {
+	var collected []lagSample
+	for sample := range samples {
+		collected = append(collected, sample)
+	}
+	return collected
+}
+
+// waitForDrain polls until no message of the campaign is pending or sending
+func waitForDrain(ctx context.Context, database *sql.DB, campaignID int64) error {
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		var remaining int64
+		err := database.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM outbound_messages
+			WHERE campaign_id = $1 AND status IN ('pending', 'sending')`,
+			campaignID,
+		).Scan(&remaining)
+		if err != nil {
+			return fmt.Errorf("failed to count remaining messages: %w", err)
+		}
+		if remaining == 0 {
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return fmt.Errorf("%d messages still in flight: %w", remaining, ctx.Err())
+		}
+	}
+}
+
+// messageLatencies returns the time from message creation to its final status
+// for every finished message, along with sent and failed counts
+func messageLatencies(ctx context.Context, database *sql.DB, campaignID int64) ([]time.Duration, int, int, error) {
+	rows, err := database.QueryContext(ctx, `
+		SELECT status, EXTRACT(EPOCH FROM (updated_at - created_at))
+		FROM outbound_messages
+		WHERE campaign_id = $1 AND status IN ('sent', 'failed')`,
+		campaignID,
+	)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to read message timings: %w", err)
+	}
+	defer rows.Close()
+
+	var latencies []time.Duration
+	sent, failed := 0, 0
+	for rows.Next() {
+		var status string
+		var seconds float64
+		if err := rows.Scan(&status, &seconds); err != nil {
+			return nil, 0, 0, fmt.Errorf("failed to scan message timing: %w", err)
+		}
+		latencies = append(latencies, time.Duration(seconds*float64(time.Second)))
+		if status == "sent" {
+			sent++
+		} else {
+			failed++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to read message timings: %w", err)
+	}
+
+	return latencies, sent, failed, nil
+}
+
+// percentile returns the p-th percentile (0-100) of sorted durations using the nearest-rank method
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p/100*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+// printReport writes the load test summary
+func printReport(
+	w io.Writer,
+	opts options,
+	sendElapsed, totalElapsed time.Duration,
+	requestLatencies, endToEnd []time.Duration,
+	sent, failed int,
+	lag []lagSample,
+) {
+	sort.Slice(requestLatencies, func(i, j int) bool { return requestLatencies[i] < requestLatencies[j] })
+	sort.Slice(endToEnd, func(i, j int) bool { return endToEnd[i] < endToEnd[j] })
+
+	var maxDepth int64
+	var maxLag time.Duration
+	for _, sample := range lag {
+		if sample.queueDepth > maxDepth {
+			maxDepth = sample.queueDepth
+		}
+		if sample.oldest > maxLag {
+			maxLag = sample.oldest
+		}
+	}
+
+	finished := sent + failed
+	fmt.Fprintf(w, "Load test: %d messages, target %.0f msg/s, %d per request\n", opts.customers, opts.rate, opts.batch)
+	fmt.Fprintf(w, "  send phase:     %v (%.1f msg/s achieved)\n", sendElapsed.Round(time.Millisecond), float64(opts.customers)/sendElapsed.Seconds())
+	fmt.Fprintf(w, "  drained after:  %v (%.1f msg/s end to end)\n", totalElapsed.Round(time.Millisecond), float64(finished)/totalElapsed.Seconds())
+	fmt.Fprintf(w, "  results:        %d sent, %d failed\n", sent, failed)
+	fmt.Fprintf(w, "  send request:   p50=%v p90=%v p99=%v max=%v\n",
+		percentile(requestLatencies, 50), percentile(requestLatencies, 90),
+		percentile(requestLatencies, 99), percentile(requestLatencies, 100))
+	fmt.Fprintf(w, "  end to end:     p50=%v p90=%v p99=%v max=%v\n",
+		percentile(endToEnd, 50).Round(time.Millisecond), percentile(endToEnd, 90).Round(time.Millisecond),
+		percentile(endToEnd, 99).Round(time.Millisecond), percentile(endToEnd, 100).Round(time.Millisecond))
+	fmt.Fprintf(w, "  queue lag:      max depth=%d, oldest unfinished message=%v\n", maxDepth, maxLag.Round(time.Millisecond))
+}
+
+// apiClient calls the campaign endpoints of the API server
+type apiClient struct {
+	baseURL string
+	http    *http.Client
+}
+
+// createCampaign creates a draft SMS campaign for the run
+func (c *apiClient) createCampaign(ctx context.Context) (int64, error) {
+	body := map[string]string{
+		"name":          fmt.Sprintf("loadgen %s", time.Now().UTC().Format(time.RFC3339)),
+		"channel":       "sms",
+		"base_template": "Hi {first_name}, this is a load test message for {preferred_product}.",
+	}
+
+	var campaign struct {
+		ID int64 `json:"id"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/api/campaigns", body, http.StatusCreated, &campaign); err != nil {
+		return 0, fmt.Errorf("failed to create campaign: %w", err)
+	}
+	return campaign.ID, nil
+}
+
+// sendCampaign sends the campaign to a chunk of customers
+func (c *apiClient) sendCampaign(ctx context.Context, campaignID int64, customerIDs []int64) error {
+	body := map[string][]int64{"customer_ids": customerIDs}
+	path := fmt.Sprintf("/api/campaigns/%d/send", campaignID)
+	if err := c.do(ctx, http.MethodPost, path, body, http.StatusOK, nil); err != nil {
+		return fmt.Errorf("failed to send campaign: %w", err)
+	}
+	return nil
+}
+
+// deleteCampaign deletes the campaign together with its messages
+func (c *apiClient) deleteCampaign(ctx context.Context, campaignID int64) error {
+	path := fmt.Sprintf("/api/campaigns/%d?force=true", campaignID)
+	if err := c.do(ctx, http.MethodDelete, path, nil, http.StatusNoContent, nil); err != nil {
+		return fmt.Errorf("failed to delete campaign: %w", err)
+	}
+	return nil
+}
+
+// do performs a JSON request and decodes the response into out when it is not nil
+func (c *apiClient) do(ctx context.Context, method, path string, body interface{}, wantStatus int, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != wantStatus {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s returned %d: %s", method, path, resp.StatusCode, bytes.TrimSpace(message))
+	}
+
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
@@ -2,23 +2,36 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"flag"
 	"log/slog"
+	"maps"
 	"os"
 	"os/signal"
+	"slices"
 	"syscall"
 	"time"
 
 	"github.com/Raymond9734/campaign-messaging-backend/internal/config"
 	"github.com/Raymond9734/campaign-messaging-backend/internal/db"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/events"
 	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/pubsub"
 	"github.com/Raymond9734/campaign-messaging-backend/internal/queue"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/ratelimit"
 	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/service"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/tracing"
 	"github.com/Raymond9734/campaign-messaging-backend/internal/worker"
 )
 
 func main() {
-	// Initialize logger
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	checkLag := flag.Bool("check-lag", false, "report whether the queue lag is within QUEUE_LAG_TARGET and exit: 0 within, 2 over, 1 on error")
+	flag.Parse()
+
+	// Initialize logger; the level can be changed by a config reload
+	logLevel := new(slog.LevelVar)
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: logLevel}))
 	slog.SetDefault(logger)
 
 	logger.Info("starting CampaignManager worker")
@@ -29,16 +42,32 @@ func main() {
 		logger.Error("failed to load config", slog.String("error", err.Error()))
 		os.Exit(1)
 	}
+	logLevel.Set(cfg.Log.Level)
+
+	if *checkLag {
+		os.Exit(checkQueueLag(cfg, logger))
+	}
+
+	// Export spans to the OTLP collector, if one is configured
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, "campaign-worker")
+	if err != nil {
+		logger.Error("failed to set up tracing", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Error("failed to flush traces", slog.String("error", err.Error()))
+		}
+	}()
+	if cfg.Tracing.Endpoint != "" {
+		logger.Info("tracing enabled", slog.String("endpoint", cfg.Tracing.Endpoint))
+	}
 
 	// Connect to database
-	database, err := db.New(db.Config{
-		Host:     cfg.Database.Host,
-		Port:     cfg.Database.Port,
-		User:     cfg.Database.User,
-		Password: cfg.Database.Password,
-		DBName:   cfg.Database.DBName,
-		SSLMode:  cfg.Database.SSLMode,
-	})
+	dbConfig := databaseConfig(cfg)
+	database, err := db.New(dbConfig)
 	if err != nil {
 		logger.Error("failed to connect to database", slog.String("error", err.Error()))
 		os.Exit(1)
@@ -47,26 +76,73 @@ func main() {
 
 	logger.Info("connected to database")
 
-	// Connect to Redis queue
-	queueClient, err := queue.NewRedisClient(queue.RedisConfig{
-		URL:       cfg.Queue.RedisURL,
-		QueueName: cfg.Queue.QueueName,
-	}, logger)
+	// Connect to the queue, on Redis or on the database
+	queueClient, err := queue.New(cfg.Queue, database.DB, logger)
 	if err != nil {
-		logger.Error("failed to connect to Redis", slog.String("error", err.Error()))
+		logger.Error("failed to connect to queue", slog.String("error", err.Error()))
 		os.Exit(1)
 	}
 	defer queueClient.Close()
 
-	logger.Info("connected to Redis queue")
+	logger.Info("connected to queue", slog.String("backend", cfg.Queue.Backend))
+
+	// Progress events travel between processes over Redis pub/sub or
+	// Postgres LISTEN/NOTIFY
+	progressBus, err := pubsub.New(cfg.Queue, database.DB, dbConfig, logger)
+	if err != nil {
+		logger.Error("failed to connect progress bus", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	defer progressBus.Close()
 
 	// Initialize repositories
 	messageRepo := repository.NewOutboundMessageRepository(database.DB)
 	campaignRepo := repository.NewCampaignRepository(database.DB)
 	customerRepo := repository.NewCustomerRepository(database.DB)
 
-	// Initialize mock sender (92% success rate)
-	sender := worker.NewMockSender(0.92)
+	// Route each channel to its configured provider; the mock provider succeeds 92% of the time
+	sender, err := worker.NewChannelSender(providerConfigs(cfg.Worker, cfg.Worker.ProviderCredentials, false))
+	if err != nil {
+		logger.Error("failed to create message sender", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	logger.Info("message senders ready",
+		slog.String("sms_provider", cfg.Worker.SenderProvider),
+		slog.String("whatsapp_provider", cfg.Worker.WhatsAppProvider),
+	)
+
+	// Track provider health per channel; jobs are deferred while a circuit is open
+	breaker := worker.NewCircuitBreaker(cfg.Worker.BreakerFailureThreshold, cfg.Worker.BreakerCooldown)
+	sender = breaker.Wrap(sender)
+
+	// Throttle sends per channel and in total across all workers. The limiter
+	// is always installed so that limits can be added by a config reload;
+	// channels without a configured rate are not throttled.
+	limiter, err := ratelimit.NewLimiter(cfg.Queue, database.DB, worker.SendRates(cfg.Worker.ProviderRateLimits, cfg.Worker.SenderMaxRPS), logger)
+	if err != nil {
+		logger.Error("failed to create rate limiter", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	defer limiter.Close()
+
+	sender = worker.NewRateLimitedSender(sender, limiter)
+
+	if len(cfg.Worker.ProviderRateLimits) > 0 {
+		logger.Info("provider rate limits enabled", slog.Any("limits", cfg.Worker.ProviderRateLimits))
+	}
+	if cfg.Worker.SenderMaxRPS > 0 {
+		logger.Info("global send rate limit enabled", slog.Float64("max_rps", cfg.Worker.SenderMaxRPS))
+	}
+
+	// Shared daily counters for sender warm-up and account send caps
+	quota, err := ratelimit.NewQuota(cfg.Queue, database.DB)
+	if err != nil {
+		logger.Error("failed to create quota counter", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	defer quota.Close()
+
+	alerter := worker.NewAlerter(cfg.Worker.AlertWebhookURL, logger)
 
 	// Initialize message processor
 	processor := worker.NewMessageProcessor(
@@ -74,14 +150,245 @@ func main() {
 		campaignRepo,
 		customerRepo,
 		sender,
+		queueClient,
 		cfg.Worker.MaxRetryCount,
 		logger,
+		// Quota-consuming gates last so deferred jobs don't use up warm-up caps
+		worker.NewDeliveryWindowGate(),
+		breaker,
+		worker.NewWarmupGate(repository.NewSenderWarmupRepository(database.DB), quota, logger),
+		worker.NewDailyCapGate(repository.NewAccountRepository(database.DB), quota, logger),
+	)
+	processor.SetRetryBackoff(cfg.Worker.RetryBaseDelay, cfg.Worker.RetryMaxDelay)
+
+	// Test campaigns go to the providers' sandboxes, outside the live circuit
+	// breaker and rate limits. A real provider needs sandbox credentials; without
+	// them its test messages fail rather than reach a live handset.
+	sandbox, err := worker.NewChannelSender(providerConfigs(cfg.Worker, cfg.Worker.ProviderTestCredentials, true))
+	if err != nil {
+		logger.Error("failed to create sandbox sender", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	processor.SetSandboxSender(sandbox)
+	logger.Info("provider credentials loaded",
+		slog.Any("live_channels", slices.Sorted(maps.Keys(cfg.Worker.ProviderCredentials))),
+		slog.Any("test_channels", slices.Sorted(maps.Keys(cfg.Worker.ProviderTestCredentials))),
 	)
 
+	// Book message costs and pause campaigns at their cost cap
+	costGuard := worker.NewCostGuard(campaignRepo, alerter, cfg.Worker.MessageCosts, logger)
+	processor.SetCostGuard(costGuard)
+
+	// Shared send slots for campaigns with a max_in_flight limit
+	semaphore, err := ratelimit.NewSemaphore(cfg.Queue, database.DB)
+	if err != nil {
+		logger.Error("failed to create send semaphore", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	defer semaphore.Close()
+	processor.SetConcurrencyLimit(worker.NewConcurrencyLimit(semaphore, logger))
+
+	// Message outcomes and campaign completions are published as domain
+	// events; progress streams, alerts, the event webhook and activity
+	// reports subscribe to them
+	eventBus := events.NewBus(logger)
+	pubsub.ForwardProgress(eventBus, progressBus, logger)
+	worker.AlertOnFailedCampaigns(eventBus, alerter, logger)
+	eventTally := events.NewTally()
+	eventBus.SubscribeAll(eventTally.Handle)
+	var eventWebhook *events.Webhook
+	if cfg.Events.WebhookURL != "" {
+		eventWebhook = events.NewWebhook(cfg.Events.WebhookURL, logger)
+		err := eventWebhook.SetFilter(events.WebhookFilter{
+			Events:    cfg.Events.WebhookEvents,
+			Labels:    cfg.Events.WebhookLabels,
+			Fields:    cfg.Events.WebhookFields,
+			Campaigns: campaignRepo,
+		})
+		if err != nil {
+			logger.Error("invalid event webhook filter", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		eventBus.SubscribeAll(eventWebhook.Handle)
+	}
+	processor.SetEvents(eventBus)
+	processor.SetMessageHistory(repository.NewMessageEventRepository(database.DB))
+
+	// Fail SMS from senders not registered where registration is enforced
+	if len(cfg.Worker.SenderRegistrationCountries) > 0 {
+		registrationCheck, err := worker.NewRegistrationCheck(
+			repository.NewSenderRegistrationRepository(database.DB),
+			cfg.Worker.SenderRegistrationCountries,
+		)
+		if err != nil {
+			logger.Error("invalid sender registration countries", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		processor.SetRegistrationCheck(registrationCheck)
+		logger.Info("sender registration enforced", slog.Any("countries", cfg.Worker.SenderRegistrationCountries))
+	}
+
+	// Look up the numbers of campaigns flagged validate_numbers before sending
+	if cfg.Worker.NumberLookupProvider != "" {
+		lookupProvider, err := worker.NewNumberLookupProvider(cfg.Worker.NumberLookupProvider, cfg.Worker.NumberLookupCredential)
+		if err != nil {
+			logger.Error("invalid number lookup provider", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		processor.SetNumberCheck(worker.NewNumberCheck(repository.NewNumberLookupRepository(database.DB), lookupProvider, logger))
+		logger.Info("number lookups enabled", slog.String("provider", cfg.Worker.NumberLookupProvider))
+	}
+
 	// Create context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Pause campaigns whose provider stays down
+	outageMonitor := worker.NewOutageMonitor(
+		breaker,
+		campaignRepo,
+		alerter,
+		cfg.Worker.OutagePauseAfter,
+		logger,
+	)
+	go outageMonitor.Run(ctx)
+
+	// Signal autoscalers when jobs wait in the queue longer than the target
+	lagMonitor := worker.NewLagMonitor(queueClient, cfg.Worker.QueueLagTarget, cfg.Worker.ScaleWebhookURL, logger)
+	go lagMonitor.Run(ctx)
+
+	// Send scheduled campaigns with a bound audience once they are due, using
+	// the same send path as POST /api/campaigns/{id}/send. Campaigns with
+	// prebuild_minutes are built that long ahead.
+	campaignService := service.NewCampaignService(
+		campaignRepo,
+		customerRepo,
+		messageRepo,
+		repository.NewSegmentRepository(database.DB),
+		service.NewTemplateService(repository.NewTemplatePartialRepository(database.DB), repository.NewAccountRepository(database.DB)),
+		queueClient,
+		service.CampaignServiceConfig{
+			SendBatchSize:     cfg.API.SendBatchSize,
+			RenderConcurrency: cfg.API.RenderConcurrency,
+			ConfirmThreshold:  cfg.API.SendConfirmThreshold,
+			ApprovalThreshold: cfg.API.SendApprovalThreshold,
+			SendJobs:          repository.NewSendJobRepository(database.DB),
+			Recommender:       service.NewRecommender(cfg.API.RecommendationURL, cfg.API.RecommendationTimeout),
+			Events:            eventBus,
+		},
+		logger,
+	)
+	scheduler := worker.NewScheduler(
+		campaignRepo,
+		messageRepo,
+		func(ctx context.Context, campaignID int64) (int, error) {
+			result, err := campaignService.SendCampaign(ctx, campaignID, &service.SendCampaignRequest{})
+			if err != nil {
+				return 0, err
+			}
+			return result.MessagesQueued, nil
+		},
+		func(ctx context.Context, campaignID int64) (int, error) {
+			result, err := campaignService.Prebuild(ctx, campaignID)
+			if err != nil {
+				return 0, err
+			}
+			return result.MessagesBuilt, nil
+		},
+		func(ctx context.Context, campaignID int64) (int, error) {
+			result, err := campaignService.Resume(ctx, campaignID)
+			if err != nil {
+				return 0, err
+			}
+			return result.MessagesRequeued, nil
+		},
+		alerter,
+		cfg.Worker.SchedulerInterval,
+		logger,
+	)
+	go scheduler.Run(ctx)
+
+	// Publish messages that were stored but never queued, e.g. by an API
+	// instance that stopped in the middle of a send
+	relay := worker.NewOutboxRelay(messageRepo, queueClient, logger)
+	go relay.Run(ctx)
+
+	// Send soft bounced messages again once their account's retry delay passed
+	bounceRetrier := worker.NewBounceRetrier(messageRepo, logger)
+	go bounceRetrier.Run(ctx)
+
+	// Redact the content of old messages; delivery records are kept
+	redactor := worker.NewContentRedactor(messageRepo, retentionPeriod(cfg.Worker.ContentRetentionDays), logger)
+	go redactor.Run(ctx)
+
+	// Drop change log records that incremental syncs have had time to read
+	pruner := worker.NewChangeLogPruner(
+		repository.NewChangeLogRepository(database.DB),
+		retentionPeriod(cfg.Worker.ChangeLogRetentionDays),
+		logger,
+	)
+	go pruner.Run(ctx)
+
+	// Keep the cached customer counts of segments recent
+	segmentCounter := worker.NewSegmentCounter(
+		repository.NewSegmentRepository(database.DB),
+		customerRepo,
+		cfg.Worker.SegmentCountInterval,
+		logger,
+	)
+	go segmentCounter.Run(ctx)
+
+	// Move campaigns off short domains that stop resolving or serving HTTPS
+	shortDomainRepo := repository.NewShortDomainRepository(database.DB)
+	shortDomainService := service.NewShortDomainService(shortDomainRepo, campaignRepo, logger)
+	shortDomainChecker := worker.NewShortDomainChecker(
+		shortDomainRepo,
+		func(ctx context.Context, domainID int64) error {
+			_, err := shortDomainService.Check(ctx, domainID)
+			return err
+		},
+		cfg.Worker.ShortDomainCheckInterval,
+		logger,
+	)
+	go shortDomainChecker.Run(ctx)
+
+	// Periodically report throttling metrics and domain event counts
+	go reportRateLimitStats(ctx, limiter, logger)
+	go eventTally.Report(ctx, time.Minute, logger)
+	if eventWebhook != nil {
+		go eventWebhook.Run(ctx)
+	}
+
+	// Apply tunables on SIGHUP without interrupting the consumer or in-flight jobs
+	go config.Watch(ctx, logger, func(reloaded *config.Config) {
+		logLevel.Set(reloaded.Log.Level)
+		limiter.SetRates(worker.SendRates(reloaded.Worker.ProviderRateLimits, reloaded.Worker.SenderMaxRPS))
+		costGuard.SetRates(reloaded.Worker.MessageCosts)
+		breaker.SetThresholds(reloaded.Worker.BreakerFailureThreshold, reloaded.Worker.BreakerCooldown)
+		processor.SetRetryBackoff(reloaded.Worker.RetryBaseDelay, reloaded.Worker.RetryMaxDelay)
+		outageMonitor.SetPauseAfter(reloaded.Worker.OutagePauseAfter)
+		redactor.SetRetention(retentionPeriod(reloaded.Worker.ContentRetentionDays))
+		pruner.SetRetention(retentionPeriod(reloaded.Worker.ChangeLogRetentionDays))
+		scheduler.SetInterval(reloaded.Worker.SchedulerInterval)
+		lagMonitor.SetTarget(reloaded.Worker.QueueLagTarget)
+
+		logger.Info("worker tunables applied",
+			slog.String("log_level", reloaded.Log.Level.String()),
+			slog.Any("provider_rate_limits", reloaded.Worker.ProviderRateLimits),
+			slog.Float64("sender_max_rps", reloaded.Worker.SenderMaxRPS),
+			slog.Any("message_costs", reloaded.Worker.MessageCosts),
+			slog.Int("breaker_failure_threshold", reloaded.Worker.BreakerFailureThreshold),
+			slog.Duration("breaker_cooldown", reloaded.Worker.BreakerCooldown),
+			slog.Duration("retry_base_delay", reloaded.Worker.RetryBaseDelay),
+			slog.Duration("retry_max_delay", reloaded.Worker.RetryMaxDelay),
+			slog.Duration("outage_pause_after", reloaded.Worker.OutagePauseAfter),
+			slog.Int("content_retention_days", reloaded.Worker.ContentRetentionDays),
+			slog.Int("change_log_retention_days", reloaded.Worker.ChangeLogRetentionDays),
+			slog.Duration("scheduler_interval", reloaded.Worker.SchedulerInterval),
+			slog.Duration("queue_lag_target", reloaded.Worker.QueueLagTarget),
+		)
+	})
+
 	// Start consuming messages
 	consumerErrors := make(chan error, 1)
 	go func() {
@@ -90,13 +397,8 @@ func main() {
 			slog.Int("concurrency", cfg.Worker.Concurrency),
 		)
 
-		// Define message handler
-		handler := func(ctx context.Context, job *models.MessageJob) error {
-			return processor.Process(ctx, job)
-		}
-
 		// Start consuming with configured concurrency
-		consumerErrors <- queueClient.Consume(ctx, handler, cfg.Worker.Concurrency)
+		consumerErrors <- queueClient.Consume(ctx, processor.Process, cfg.Worker.Concurrency)
 	}()
 
 	// Wait for interrupt signal or consumer error
@@ -113,12 +415,128 @@ func main() {
 	case sig := <-quit:
 		logger.Info("shutting down worker", slog.String("signal", sig.String()))
 
-		// Cancel context to stop consumer
+		// Cancel context to stop taking jobs; the consumer returns once those
+		// in flight have finished or the drain timeout has passed
 		cancel()
-
-		// Give consumer time to finish current job
-		time.Sleep(5 * time.Second)
+		if err := <-consumerErrors; err != nil && err != context.Canceled {
+			logger.Error("consumer error during shutdown", slog.String("error", err.Error()))
+		}
 
 		logger.Info("worker stopped gracefully")
 	}
 }
+
+// providerConfigs configures the sender of each channel with the given
+// credentials. A real provider is left out when it has no credential for the
+// sandbox, so that test messages on its channel fail.
+func providerConfigs(cfg config.WorkerConfig, credentials map[string]string, sandbox bool) map[string]worker.ProviderConfig {
+	providers := map[string]worker.ProviderConfig{
+		models.ChannelSMS: {
+			Provider: cfg.SenderProvider,
+			From:     cfg.SenderFrom,
+		},
+		models.ChannelWhatsApp: {
+			Provider: cfg.WhatsAppProvider,
+			Template: cfg.WhatsAppTemplate,
+		},
+	}
+
+	configs := make(map[string]worker.ProviderConfig, len(providers))
+	for channel, provider := range providers {
+		credential, ok := credentials[channel]
+		if sandbox && !ok && provider.Provider != worker.ProviderMock {
+			continue
+		}
+		provider.Credential = credential
+		provider.Sandbox = sandbox
+		configs[channel] = provider
+	}
+
+	return configs
+}
+
+// databaseConfig returns the database connection settings of cfg
+func databaseConfig(cfg *config.Config) db.Config {
+	return db.Config{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		User:     cfg.Database.User,
+		Password: cfg.Database.Password,
+		DBName:   cfg.Database.DBName,
+		SSLMode:  cfg.Database.SSLMode,
+	}
+}
+
+// checkQueueLag reads the queue lag once for autoscalers and health probes
+// that go by exit code. It returns 0 when the lag is within the target or
+// the target is 0, 2 when it is over, and 1 when the lag cannot be read.
+func checkQueueLag(cfg *config.Config, logger *slog.Logger) int {
+	// Only the Postgres queue needs the database
+	var database *sql.DB
+	if cfg.Queue.Backend == config.QueueBackendPostgres {
+		conn, err := db.New(databaseConfig(cfg))
+		if err != nil {
+			logger.Error("failed to connect to database", slog.String("error", err.Error()))
+			return 1
+		}
+		defer conn.Close()
+		database = conn.DB
+	}
+
+	queueClient, err := queue.New(cfg.Queue, database, logger)
+	if err != nil {
+		logger.Error("failed to connect to queue", slog.String("error", err.Error()))
+		return 1
+	}
+	defer queueClient.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	lag, err := queueClient.Lag(ctx)
+	if err != nil {
+		logger.Error("failed to read queue lag", slog.String("error", err.Error()))
+		return 1
+	}
+
+	target := cfg.Worker.QueueLagTarget
+	attrs := []any{
+		slog.Float64("lag_seconds", lag.Seconds()),
+		slog.Float64("target_seconds", target.Seconds()),
+	}
+	if target > 0 && lag > target {
+		logger.Warn("queue lag over target", attrs...)
+		return 2
+	}
+
+	logger.Info("queue lag within target", attrs...)
+	return 0
+}
+
+// retentionPeriod converts a retention in days to a duration
+func retentionPeriod(days int) time.Duration {
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// reportRateLimitStats logs throttle wait metrics per channel every minute
+func reportRateLimitStats(ctx context.Context, limiter ratelimit.Limiter, logger *slog.Logger) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for channel, stats := range limiter.Stats() {
+				logger.Info("rate limiter stats",
+					slog.String("channel", channel),
+					slog.Int64("acquired", stats.Acquired),
+					slog.Int64("throttled", stats.Throttled),
+					slog.Duration("total_wait", stats.TotalWait),
+					slog.Duration("max_wait", stats.MaxWait),
+				)
+			}
+		}
+	}
+}
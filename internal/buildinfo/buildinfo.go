@@ -0,0 +1,66 @@
+// Package buildinfo describes the running binary: the commit and time it was
+// built from and how long the process has been up.
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// Commit and BuildTime are stamped at build time with
+//
+//	-ldflags "-X github.com/Raymond9734/campaign-messaging-backend/internal/buildinfo.Commit=<sha> -X github.com/Raymond9734/campaign-messaging-backend/internal/buildinfo.BuildTime=<RFC3339>"
+//
+// When left empty they fall back to the VCS information embedded by the Go toolchain.
+var (
+	Commit    string
+	BuildTime string
+)
+
+// startedAt is when the process started, close enough to package initialisation
+var startedAt = time.Now()
+
+// Info is a snapshot of the build and process information
+type Info struct {
+	Commit        string    `json:"commit"`
+	BuildTime     string    `json:"build_time"`
+	GoVersion     string    `json:"go_version"`
+	StartedAt     time.Time `json:"started_at"`
+	UptimeSeconds int64     `json:"uptime_seconds"`
+}
+
+// Get returns the build information of the running binary
+func Get() Info {
+	info := Info{
+		Commit:        Commit,
+		BuildTime:     BuildTime,
+		GoVersion:     runtime.Version(),
+		StartedAt:     startedAt,
+		UptimeSeconds: int64(time.Since(startedAt).Seconds()),
+	}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range build.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.time":
+				if info.BuildTime == "" {
+					info.BuildTime = setting.Value
+				}
+			}
+		}
+	}
+
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = "unknown"
+	}
+
+	return info
+}
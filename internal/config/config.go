@@ -2,16 +2,59 @@ package config
 
 import (
 	"fmt"
+	"log/slog"
+	"net/url"
 	"os"
+	"runtime"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 )
 
-// Config holds all application configuration
+// Config holds all application configuration.
+//
+// Values come from environment variables. When CONFIG_FILE points at a file of
+// KEY=VALUE lines, its entries take precedence; see Watch for which of them can
+// be changed at runtime.
 type Config struct {
 	Database DatabaseConfig
 	Queue    QueueConfig
 	API      APIConfig
 	Worker   WorkerConfig
+	Log      LogConfig
+	Tracing  TracingConfig
+	Events   EventsConfig
+}
+
+// LogConfig holds logging configuration
+type LogConfig struct {
+	Level slog.Level
+}
+
+// EventsConfig holds domain event configuration
+type EventsConfig struct {
+	// WebhookURL receives every domain event of the API and worker as JSON
+	// (optional)
+	WebhookURL string
+	// WebhookEvents limits the webhook to these event names; empty posts
+	// every event
+	WebhookEvents []string
+	// WebhookLabels limits the webhook to the events of campaigns with one of
+	// these labels; empty posts events of every campaign
+	WebhookLabels []string
+	// WebhookFields limits the data posted for each event to these fields;
+	// empty posts every field
+	WebhookFields []string
+}
+
+// TracingConfig holds OpenTelemetry tracing configuration. Settings other
+// than the endpoint, such as OTEL_EXPORTER_OTLP_HEADERS, OTEL_SERVICE_NAME and
+// OTEL_TRACES_SAMPLER, are read from the environment by the SDK itself.
+type TracingConfig struct {
+	// Endpoint is the base URL of the OTLP/HTTP collector spans are exported
+	// to, e.g. http://otel-collector:4318; empty turns tracing off
+	Endpoint string
 }
 
 // DatabaseConfig holds database connection configuration
@@ -24,64 +67,482 @@ type DatabaseConfig struct {
 	SSLMode  string
 }
 
-// QueueConfig holds queue configuration (Redis)
+// Queue backends, chosen with QUEUE_BACKEND
+const (
+	QueueBackendRedis    = "redis"
+	QueueBackendPostgres = "postgres"
+)
+
+// QueueConfig holds queue configuration
 type QueueConfig struct {
+	// Backend keeps the queue, progress events, rate limits, quotas and send
+	// slots in Redis or in the Postgres database
+	Backend   string
 	RedisURL  string
 	QueueName string
+	// VisibilityTimeout is how long a worker may go silent before the jobs it
+	// was processing are returned to the queue
+	VisibilityTimeout time.Duration
+	// DrainTimeout is how long a stopping worker waits for its in-flight jobs
+	// before returning them to the queue
+	DrainTimeout time.Duration
 }
 
 // APIConfig holds API server configuration
 type APIConfig struct {
-	Port int
+	Port              int
+	SendBatchSize     int
+	RenderConcurrency int
+	// SendConfirmThreshold is the audience size above which a send must be
+	// confirmed with confirm_recipient_count; 0 never asks
+	SendConfirmThreshold int64
+	// SendApprovalThreshold is the audience size above which a send is held
+	// until a second user approves it; 0 never holds one. It needs operator
+	// login, to tell the approver from the sender.
+	SendApprovalThreshold int64
+	// SimulationConcurrency is the number of mock sends in flight during a simulation
+	SimulationConcurrency int
+	// RequestTimeout is the deadline for ordinary reads and writes
+	RequestTimeout time.Duration
+	// BulkRequestTimeout is the deadline for requests that build or requeue a whole audience
+	BulkRequestTimeout time.Duration
+	// WebhookToken, when set, must be passed as the token query parameter on
+	// provider webhooks; it is also the verify token for Meta's subscription check
+	WebhookToken string
+	// PublicURL is the base URL the API is reachable at from outside, used to
+	// build links such as campaign preview links
+	PublicURL string
+	// RecommendationURL, when set, is posted each audience batch to fill
+	// {recommended_product}; RecommendationTimeout bounds each call
+	RecommendationURL     string
+	RecommendationTimeout time.Duration
+	// JWTSecret, when set, turns on operator login: /api routes then need a
+	// token from /auth/login, and what they allow depends on the user's role
+	JWTSecret string
+	// JWTTTL is how long an issued token stays valid
+	JWTTTL time.Duration
+	// AdminEmail and AdminPassword, when set, create an admin user at startup
+	// unless one with that email exists
+	AdminEmail    string
+	AdminPassword string
 }
 
 // WorkerConfig holds worker configuration
 type WorkerConfig struct {
 	Concurrency   int
 	MaxRetryCount int
+	// RetryBaseDelay is the wait before retrying a failed send; it doubles with
+	// every further attempt up to RetryMaxDelay
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	// ProviderRateLimits maps a channel to its max messages per second across all workers
+	ProviderRateLimits map[string]float64
+	// SenderMaxRPS caps the messages per second sent on every channel together
+	// across all workers; 0 means no global cap
+	SenderMaxRPS float64
+	// ProviderCredentials maps a channel to its live provider credentials, and
+	// ProviderTestCredentials to its sandbox credentials used by test campaigns
+	ProviderCredentials     map[string]string
+	ProviderTestCredentials map[string]string
+	// SenderProvider sends SMS: "mock", "africastalking" or "twilio"
+	SenderProvider string
+	// SenderFrom is the sender ID or number SMS are sent from (optional for
+	// Africa's Talking, required for Twilio)
+	SenderFrom string
+	// WhatsAppProvider sends WhatsApp messages: "mock" or "meta"
+	WhatsAppProvider string
+	// WhatsAppTemplate is the approved template, as "name:language", that
+	// carries WhatsApp messages; empty sends plain text
+	WhatsAppTemplate string
+	// MessageCosts maps a channel to its price per SMS segment, or per message
+	// on other channels, for campaign cost caps
+	MessageCosts map[string]float64
+	// BreakerFailureThreshold consecutive send failures open a channel's circuit
+	BreakerFailureThreshold int
+	// BreakerCooldown is the wait between trial sends while a circuit is open
+	BreakerCooldown time.Duration
+	// OutagePauseAfter is how long a circuit may stay open before its campaigns are paused
+	OutagePauseAfter time.Duration
+	// AlertWebhookURL receives operational alerts as JSON (optional)
+	AlertWebhookURL string
+	// QueueLagTarget is how long the oldest queued job may wait before more
+	// workers are signalled for; 0 turns lag monitoring off
+	QueueLagTarget time.Duration
+	// ScaleWebhookURL receives scaling signals as JSON while the queue lag
+	// is over target (optional)
+	ScaleWebhookURL string
+	// ContentRetentionDays is how long the rendered content of sent and failed
+	// messages is kept before it is redacted; 0 keeps it forever
+	ContentRetentionDays int
+	// ChangeLogRetentionDays is how long change log records are kept for
+	// incremental syncs; 0 keeps them forever
+	ChangeLogRetentionDays int
+	// SchedulerInterval is how often the worker looks for due scheduled
+	// campaigns; 0 leaves them for a manual send
+	SchedulerInterval time.Duration
+	// SegmentCountInterval is how old a segment's cached customer count may
+	// get before the worker recounts it; 0 leaves counts to be refreshed on
+	// demand
+	SegmentCountInterval time.Duration
+	// ShortDomainCheckInterval is how often the worker checks that each
+	// enabled short domain resolves and answers over HTTPS; 0 leaves checks
+	// to be run on demand
+	ShortDomainCheckInterval time.Duration
+	// SenderRegistrationCountries lists the destination countries, as ISO
+	// alpha-2 codes, whose sender registration rules are enforced on SMS
+	SenderRegistrationCountries []string
+	// NumberLookupProvider looks up the numbers of campaigns flagged
+	// validate_numbers before sending: "mock" or "twilio"; empty turns
+	// lookups off
+	NumberLookupProvider string
+	// NumberLookupCredential is the lookup provider's credential, as
+	// "account_sid:auth_token" for Twilio
+	NumberLookupCredential string
 }
 
 // Load reads configuration from environment variables
 func Load() (*Config, error) {
-	dbPort, err := strconv.Atoi(getEnv("DB_PORT", "5432"))
+	env, err := newSource()
+	if err != nil {
+		return nil, err
+	}
+
+	logLevel, err := parseLogLevel(env.get("LOG_LEVEL", "info"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOG_LEVEL: %w", err)
+	}
+
+	dbPort, err := strconv.Atoi(env.get("DB_PORT", "5432"))
 	if err != nil {
 		return nil, fmt.Errorf("invalid DB_PORT: %w", err)
 	}
 
-	apiPort, err := strconv.Atoi(getEnv("API_PORT", "8080"))
+	apiPort, err := strconv.Atoi(env.get("API_PORT", "8080"))
 	if err != nil {
 		return nil, fmt.Errorf("invalid API_PORT: %w", err)
 	}
 
-	workerConcurrency, err := strconv.Atoi(getEnv("WORKER_CONCURRENCY", "5"))
+	sendBatchSize, err := strconv.Atoi(env.get("SEND_BATCH_SIZE", "1000"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SEND_BATCH_SIZE: %w", err)
+	}
+	if sendBatchSize < 1 {
+		return nil, fmt.Errorf("invalid SEND_BATCH_SIZE: must be at least 1")
+	}
+
+	renderConcurrency, err := strconv.Atoi(env.get("RENDER_CONCURRENCY", strconv.Itoa(runtime.NumCPU())))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RENDER_CONCURRENCY: %w", err)
+	}
+	if renderConcurrency < 1 {
+		return nil, fmt.Errorf("invalid RENDER_CONCURRENCY: must be at least 1")
+	}
+
+	sendConfirmThreshold, err := strconv.ParseInt(env.get("SEND_CONFIRM_THRESHOLD", "10000"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SEND_CONFIRM_THRESHOLD: %w", err)
+	}
+	if sendConfirmThreshold < 0 {
+		return nil, fmt.Errorf("invalid SEND_CONFIRM_THRESHOLD: must not be negative")
+	}
+
+	sendApprovalThreshold, err := strconv.ParseInt(env.get("SEND_APPROVAL_THRESHOLD", "0"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SEND_APPROVAL_THRESHOLD: %w", err)
+	}
+	if sendApprovalThreshold < 0 {
+		return nil, fmt.Errorf("invalid SEND_APPROVAL_THRESHOLD: must not be negative")
+	}
+
+	simulationConcurrency, err := strconv.Atoi(env.get("SIMULATION_CONCURRENCY", "100"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SIMULATION_CONCURRENCY: %w", err)
+	}
+	if simulationConcurrency < 1 {
+		return nil, fmt.Errorf("invalid SIMULATION_CONCURRENCY: must be at least 1")
+	}
+
+	requestTimeout, err := time.ParseDuration(env.get("REQUEST_TIMEOUT", "5s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid REQUEST_TIMEOUT: %w", err)
+	}
+	if requestTimeout <= 0 {
+		return nil, fmt.Errorf("invalid REQUEST_TIMEOUT: must be positive")
+	}
+
+	bulkRequestTimeout, err := time.ParseDuration(env.get("BULK_REQUEST_TIMEOUT", "60s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid BULK_REQUEST_TIMEOUT: %w", err)
+	}
+	if bulkRequestTimeout <= 0 {
+		return nil, fmt.Errorf("invalid BULK_REQUEST_TIMEOUT: must be positive")
+	}
+
+	visibilityTimeout, err := time.ParseDuration(env.get("QUEUE_VISIBILITY_TIMEOUT", "60s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid QUEUE_VISIBILITY_TIMEOUT: %w", err)
+	}
+	if visibilityTimeout < 3*time.Second {
+		return nil, fmt.Errorf("invalid QUEUE_VISIBILITY_TIMEOUT: must be at least 3s")
+	}
+
+	drainTimeout, err := time.ParseDuration(env.get("QUEUE_DRAIN_TIMEOUT", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid QUEUE_DRAIN_TIMEOUT: %w", err)
+	}
+	if drainTimeout <= 0 {
+		return nil, fmt.Errorf("invalid QUEUE_DRAIN_TIMEOUT: must be positive")
+	}
+
+	queueBackend, err := parseProvider(env.get("QUEUE_BACKEND", QueueBackendRedis), QueueBackendRedis, QueueBackendPostgres)
+	if err != nil {
+		return nil, fmt.Errorf("invalid QUEUE_BACKEND: %w", err)
+	}
+
+	workerConcurrency, err := strconv.Atoi(env.get("WORKER_CONCURRENCY", "5"))
 	if err != nil {
 		return nil, fmt.Errorf("invalid WORKER_CONCURRENCY: %w", err)
 	}
 
-	maxRetryCount, err := strconv.Atoi(getEnv("MAX_RETRY_COUNT", "3"))
+	maxRetryCount, err := strconv.Atoi(env.get("MAX_RETRY_COUNT", "3"))
 	if err != nil {
 		return nil, fmt.Errorf("invalid MAX_RETRY_COUNT: %w", err)
 	}
 
+	retryBaseDelay, err := time.ParseDuration(env.get("RETRY_BASE_DELAY", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RETRY_BASE_DELAY: %w", err)
+	}
+	if retryBaseDelay <= 0 {
+		return nil, fmt.Errorf("invalid RETRY_BASE_DELAY: must be positive")
+	}
+
+	retryMaxDelay, err := time.ParseDuration(env.get("RETRY_MAX_DELAY", "30m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RETRY_MAX_DELAY: %w", err)
+	}
+	if retryMaxDelay < retryBaseDelay {
+		return nil, fmt.Errorf("invalid RETRY_MAX_DELAY: must not be less than RETRY_BASE_DELAY")
+	}
+
+	providerRateLimits, err := parseRateLimits(env.get("PROVIDER_RATE_LIMITS", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PROVIDER_RATE_LIMITS: %w", err)
+	}
+
+	senderMaxRPS, err := strconv.ParseFloat(env.get("SENDER_MAX_RPS", "0"), 64)
+	if err != nil || senderMaxRPS < 0 {
+		return nil, fmt.Errorf("invalid SENDER_MAX_RPS: must be a non-negative number")
+	}
+
+	providerCredentials, err := parseCredentials(env.get("PROVIDER_CREDENTIALS", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PROVIDER_CREDENTIALS: %w", err)
+	}
+
+	providerTestCredentials, err := parseCredentials(env.get("PROVIDER_TEST_CREDENTIALS", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PROVIDER_TEST_CREDENTIALS: %w", err)
+	}
+
+	senderProvider, err := parseProvider(env.get("SENDER_PROVIDER", "mock"), "mock", "africastalking", "twilio")
+	if err != nil {
+		return nil, fmt.Errorf("invalid SENDER_PROVIDER: %w", err)
+	}
+
+	whatsAppProvider, err := parseProvider(env.get("WHATSAPP_PROVIDER", "mock"), "mock", "meta")
+	if err != nil {
+		return nil, fmt.Errorf("invalid WHATSAPP_PROVIDER: %w", err)
+	}
+
+	messageCosts, err := parseRateLimits(env.get("MESSAGE_COSTS", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MESSAGE_COSTS: %w", err)
+	}
+
+	breakerFailureThreshold, err := strconv.Atoi(env.get("BREAKER_FAILURE_THRESHOLD", "20"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid BREAKER_FAILURE_THRESHOLD: %w", err)
+	}
+	if breakerFailureThreshold < 1 {
+		return nil, fmt.Errorf("invalid BREAKER_FAILURE_THRESHOLD: must be at least 1")
+	}
+
+	breakerCooldown, err := time.ParseDuration(env.get("BREAKER_COOLDOWN", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid BREAKER_COOLDOWN: %w", err)
+	}
+
+	outagePauseAfter, err := time.ParseDuration(env.get("OUTAGE_PAUSE_AFTER", "5m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OUTAGE_PAUSE_AFTER: %w", err)
+	}
+
+	queueLagTarget, err := time.ParseDuration(env.get("QUEUE_LAG_TARGET", "60s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid QUEUE_LAG_TARGET: %w", err)
+	}
+	if queueLagTarget < 0 {
+		return nil, fmt.Errorf("invalid QUEUE_LAG_TARGET: must not be negative")
+	}
+
+	contentRetentionDays, err := strconv.Atoi(env.get("CONTENT_RETENTION_DAYS", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CONTENT_RETENTION_DAYS: %w", err)
+	}
+	if contentRetentionDays < 0 {
+		return nil, fmt.Errorf("invalid CONTENT_RETENTION_DAYS: must not be negative")
+	}
+
+	changeLogRetentionDays, err := strconv.Atoi(env.get("CHANGE_LOG_RETENTION_DAYS", "7"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CHANGE_LOG_RETENTION_DAYS: %w", err)
+	}
+	if changeLogRetentionDays < 0 {
+		return nil, fmt.Errorf("invalid CHANGE_LOG_RETENTION_DAYS: must not be negative")
+	}
+
+	schedulerInterval, err := time.ParseDuration(env.get("SCHEDULER_INTERVAL", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SCHEDULER_INTERVAL: %w", err)
+	}
+	if schedulerInterval < 0 {
+		return nil, fmt.Errorf("invalid SCHEDULER_INTERVAL: must not be negative")
+	}
+
+	segmentCountInterval, err := time.ParseDuration(env.get("SEGMENT_COUNT_INTERVAL", "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SEGMENT_COUNT_INTERVAL: %w", err)
+	}
+	if segmentCountInterval < 0 {
+		return nil, fmt.Errorf("invalid SEGMENT_COUNT_INTERVAL: must not be negative")
+	}
+
+	shortDomainCheckInterval, err := time.ParseDuration(env.get("SHORT_DOMAIN_CHECK_INTERVAL", "15m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SHORT_DOMAIN_CHECK_INTERVAL: %w", err)
+	}
+	if shortDomainCheckInterval < 0 {
+		return nil, fmt.Errorf("invalid SHORT_DOMAIN_CHECK_INTERVAL: must not be negative")
+	}
+
+	recommendationTimeout, err := time.ParseDuration(env.get("RECOMMENDATION_TIMEOUT", "2s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RECOMMENDATION_TIMEOUT: %w", err)
+	}
+	if recommendationTimeout <= 0 {
+		return nil, fmt.Errorf("invalid RECOMMENDATION_TIMEOUT: must be positive")
+	}
+
+	jwtSecret := env.get("JWT_SECRET", "")
+	if jwtSecret != "" && len(jwtSecret) < 32 {
+		return nil, fmt.Errorf("invalid JWT_SECRET: must be at least 32 characters")
+	}
+	if sendApprovalThreshold > 0 && jwtSecret == "" {
+		return nil, fmt.Errorf("invalid SEND_APPROVAL_THRESHOLD: needs JWT_SECRET, so approvers can be told apart from senders")
+	}
+
+	jwtTTL, err := time.ParseDuration(env.get("JWT_TTL", "12h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT_TTL: %w", err)
+	}
+	if jwtTTL <= 0 {
+		return nil, fmt.Errorf("invalid JWT_TTL: must be positive")
+	}
+
+	senderRegistrationCountries, err := parseCountries(env.get("SENDER_REGISTRATION_COUNTRIES", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SENDER_REGISTRATION_COUNTRIES: %w", err)
+	}
+
+	numberLookupProvider := env.get("NUMBER_LOOKUP_PROVIDER", "")
+	if numberLookupProvider != "" {
+		numberLookupProvider, err = parseProvider(numberLookupProvider, "mock", "twilio")
+		if err != nil {
+			return nil, fmt.Errorf("invalid NUMBER_LOOKUP_PROVIDER: %w", err)
+		}
+	}
+
+	tracingEndpoint, err := parseTracingEndpoint(env.get("OTEL_EXPORTER_OTLP_ENDPOINT", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTEL_EXPORTER_OTLP_ENDPOINT: %w", err)
+	}
+
 	return &Config{
 		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
+			Host:     env.get("DB_HOST", "localhost"),
 			Port:     dbPort,
-			User:     getEnv("DB_USER", "campaign_manager"),
-			Password: getEnv("DB_PASSWORD", "campaign_manager"),
-			DBName:   getEnv("DB_NAME", "campaign_manager"),
-			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+			User:     env.get("DB_USER", "campaign_manager"),
+			Password: env.get("DB_PASSWORD", "campaign_manager"),
+			DBName:   env.get("DB_NAME", "campaign_manager"),
+			SSLMode:  env.get("DB_SSLMODE", "disable"),
 		},
 		Queue: QueueConfig{
-			RedisURL:  getEnv("REDIS_URL", "redis://localhost:6379/0"),
-			QueueName: getEnv("QUEUE_NAME", "campaign_sends"),
+			Backend:           queueBackend,
+			RedisURL:          env.get("REDIS_URL", "redis://localhost:6379/0"),
+			QueueName:         env.get("QUEUE_NAME", "campaign_sends"),
+			VisibilityTimeout: visibilityTimeout,
+			DrainTimeout:      drainTimeout,
 		},
 		API: APIConfig{
-			Port: apiPort,
+			Port:                  apiPort,
+			SendBatchSize:         sendBatchSize,
+			RenderConcurrency:     renderConcurrency,
+			SendConfirmThreshold:  sendConfirmThreshold,
+			SendApprovalThreshold: sendApprovalThreshold,
+			SimulationConcurrency: simulationConcurrency,
+			RequestTimeout:        requestTimeout,
+			BulkRequestTimeout:    bulkRequestTimeout,
+			WebhookToken:          env.get("WEBHOOK_TOKEN", ""),
+			RecommendationURL:     env.get("RECOMMENDATION_URL", ""),
+			RecommendationTimeout: recommendationTimeout,
+			PublicURL:             strings.TrimRight(env.get("PUBLIC_URL", fmt.Sprintf("http://localhost:%d", apiPort)), "/"),
+			JWTSecret:             jwtSecret,
+			JWTTTL:                jwtTTL,
+			AdminEmail:            env.get("ADMIN_EMAIL", ""),
+			AdminPassword:         env.get("ADMIN_PASSWORD", ""),
+		},
+		Log: LogConfig{
+			Level: logLevel,
+		},
+		Tracing: TracingConfig{
+			Endpoint: tracingEndpoint,
+		},
+		Events: EventsConfig{
+			WebhookURL:    env.get("EVENT_WEBHOOK_URL", ""),
+			WebhookEvents: parseList(env.get("EVENT_WEBHOOK_EVENTS", "")),
+			WebhookLabels: parseList(env.get("EVENT_WEBHOOK_LABELS", "")),
+			WebhookFields: parseList(env.get("EVENT_WEBHOOK_FIELDS", "")),
 		},
 		Worker: WorkerConfig{
-			Concurrency:   workerConcurrency,
-			MaxRetryCount: maxRetryCount,
+			Concurrency:              workerConcurrency,
+			MaxRetryCount:            maxRetryCount,
+			RetryBaseDelay:           retryBaseDelay,
+			RetryMaxDelay:            retryMaxDelay,
+			ProviderRateLimits:       providerRateLimits,
+			SenderMaxRPS:             senderMaxRPS,
+			ProviderCredentials:      providerCredentials,
+			ProviderTestCredentials:  providerTestCredentials,
+			SenderProvider:           senderProvider,
+			SenderFrom:               env.get("SENDER_FROM", ""),
+			WhatsAppProvider:         whatsAppProvider,
+			WhatsAppTemplate:         env.get("WHATSAPP_TEMPLATE", ""),
+			MessageCosts:             messageCosts,
+			BreakerFailureThreshold:  breakerFailureThreshold,
+			BreakerCooldown:          breakerCooldown,
+			OutagePauseAfter:         outagePauseAfter,
+			AlertWebhookURL:          env.get("ALERT_WEBHOOK_URL", ""),
+			QueueLagTarget:           queueLagTarget,
+			ScaleWebhookURL:          env.get("SCALE_WEBHOOK_URL", ""),
+			ContentRetentionDays:     contentRetentionDays,
+			ChangeLogRetentionDays:   changeLogRetentionDays,
+			SchedulerInterval:        schedulerInterval,
+			SegmentCountInterval:     segmentCountInterval,
+			ShortDomainCheckInterval: shortDomainCheckInterval,
+
+			SenderRegistrationCountries: senderRegistrationCountries,
+			NumberLookupProvider:        numberLookupProvider,
+			NumberLookupCredential:      env.get("NUMBER_LOOKUP_CREDENTIAL", ""),
 		},
 	}, nil
 }
@@ -94,10 +555,177 @@ func (d *DatabaseConfig) DSN() string {
 	)
 }
 
-// getEnv retrieves an environment variable or returns a default value
-func getEnv(key, defaultValue string) string {
+// parseRateLimits parses a comma-separated list of channel=rate pairs,
+// e.g. "sms=100,whatsapp=80"
+func parseRateLimits(value string) (map[string]float64, error) {
+	limits := make(map[string]float64)
+	if strings.TrimSpace(value) == "" {
+		return limits, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		key, rate, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("expected channel=rate, got %q", pair)
+		}
+
+		perSecond, err := strconv.ParseFloat(strings.TrimSpace(rate), 64)
+		if err != nil || perSecond <= 0 {
+			return nil, fmt.Errorf("rate for %q must be a positive number", key)
+		}
+
+		limits[strings.TrimSpace(key)] = perSecond
+	}
+
+	return limits, nil
+}
+
+// parseCredentials parses a comma-separated list of channel=credential pairs,
+// e.g. "sms=key_live_123,whatsapp=token_456". A credential may contain '='
+// but not ','.
+func parseCredentials(value string) (map[string]string, error) {
+	credentials := make(map[string]string)
+	if strings.TrimSpace(value) == "" {
+		return credentials, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		key, credential, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			// Never echo the pair, it holds a secret
+			return nil, fmt.Errorf("expected channel=credential")
+		}
+		if strings.TrimSpace(credential) == "" {
+			return nil, fmt.Errorf("credential for %q is empty", strings.TrimSpace(key))
+		}
+
+		credentials[strings.TrimSpace(key)] = strings.TrimSpace(credential)
+	}
+
+	return credentials, nil
+}
+
+// parseCountries parses a comma-separated list of ISO 3166-1 alpha-2 country
+// codes, e.g. "US,KE", in any case
+func parseCountries(value string) ([]string, error) {
+	countries := []string{}
+	if strings.TrimSpace(value) == "" {
+		return countries, nil
+	}
+
+	for _, code := range strings.Split(value, ",") {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if len(code) != 2 || strings.Trim(code, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+			return nil, fmt.Errorf("expected two-letter country codes, got %q", code)
+		}
+		if !slices.Contains(countries, code) {
+			countries = append(countries, code)
+		}
+	}
+
+	return countries, nil
+}
+
+// parseList parses a comma-separated list, dropping blank and repeated entries
+func parseList(value string) []string {
+	list := []string{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry != "" && !slices.Contains(list, entry) {
+			list = append(list, entry)
+		}
+	}
+	return list
+}
+
+// source resolves configuration values: entries from CONFIG_FILE take
+// precedence over the process environment, which cannot change after start-up
+type source struct {
+	file map[string]string
+}
+
+// newSource reads CONFIG_FILE, if set
+func newSource() (*source, error) {
+	src := &source{file: map[string]string{}}
+
+	path := os.Getenv("CONFIG_FILE")
+	if path == "" {
+		return src, nil
+	}
+
+	values, err := readConfigFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CONFIG_FILE: %w", err)
+	}
+	src.file = values
+	return src, nil
+}
+
+// get retrieves a configuration value or returns a default value
+func (s *source) get(key, defaultValue string) string {
+	if value := s.file[key]; value != "" {
+		return value
+	}
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
+
+// readConfigFile parses a file of KEY=VALUE lines in the same format as .env.
+// Blank lines and lines starting with # are ignored.
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", i+1)
+		}
+		values[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+
+	return values, nil
+}
+
+// parseProvider parses a provider name, which must be one of valid
+func parseProvider(value string, valid ...string) (string, error) {
+	provider := strings.ToLower(strings.TrimSpace(value))
+	if !slices.Contains(valid, provider) {
+		return "", fmt.Errorf("must be one of %s", strings.Join(valid, ", "))
+	}
+	return provider, nil
+}
+
+// parseTracingEndpoint parses the base URL of an OTLP/HTTP collector; empty
+// is returned as is
+func parseTracingEndpoint(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+
+	endpoint, err := url.Parse(value)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return "", fmt.Errorf("must be an http:// or https:// URL")
+	}
+	return strings.TrimRight(value, "/"), nil
+}
+
+// parseLogLevel parses debug, info, warn or error
+func parseLogLevel(value string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		return 0, fmt.Errorf("must be one of debug, info, warn, error")
+	}
+	return level, nil
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad_ConfigFileOverridesEnvironment(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tunables.env")
+	content := `# runtime tunables
+LOG_LEVEL=debug
+PROVIDER_RATE_LIMITS="sms=50"
+
+BREAKER_FAILURE_THRESHOLD=7
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("CONFIG_FILE", path)
+	t.Setenv("LOG_LEVEL", "error")
+	t.Setenv("BREAKER_FAILURE_THRESHOLD", "3")
+	t.Setenv("WORKER_CONCURRENCY", "4")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Log.Level != slog.LevelDebug {
+		t.Errorf("Log.Level = %v, want DEBUG from the file", cfg.Log.Level)
+	}
+	if cfg.Worker.BreakerFailureThreshold != 7 {
+		t.Errorf("BreakerFailureThreshold = %d, want 7 from the file", cfg.Worker.BreakerFailureThreshold)
+	}
+	if cfg.Worker.ProviderRateLimits["sms"] != 50 {
+		t.Errorf("ProviderRateLimits = %v, want sms=50 from the file", cfg.Worker.ProviderRateLimits)
+	}
+	if cfg.Worker.Concurrency != 4 {
+		t.Errorf("Concurrency = %d, want 4 from the environment", cfg.Worker.Concurrency)
+	}
+}
+
+func TestLoad_InvalidConfigFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "missing separator", content: "LOG_LEVEL debug\n"},
+		{name: "invalid log level", content: "LOG_LEVEL=verbose\n"},
+		{name: "whatsapp provider for sms", content: "SENDER_PROVIDER=meta\n"},
+		{name: "negative global send rate", content: "SENDER_MAX_RPS=-5\n"},
+		{name: "tracing endpoint without scheme", content: "OTEL_EXPORTER_OTLP_ENDPOINT=otel-collector:4318\n"},
+		{name: "unknown queue backend", content: "QUEUE_BACKEND=kafka\n"},
+		{name: "send approval without operator login", content: "SEND_APPROVAL_THRESHOLD=50000\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "tunables.env")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			t.Setenv("CONFIG_FILE", path)
+
+			if _, err := Load(); err == nil {
+				t.Error("Load() error = nil, want error")
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		t.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "missing.env"))
+		if _, err := Load(); err == nil {
+			t.Error("Load() error = nil, want error")
+		}
+	})
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Watch reloads the configuration on SIGHUP until ctx is done and passes every
+// successfully loaded configuration to apply. A configuration that fails to load
+// is logged and ignored, so the running values stay in effect.
+//
+// Only tunables are meant to be applied at runtime (log level, provider rate
+// limits, circuit breaker, outage and content retention settings). Connection
+// settings such as database and Redis addresses, ports and worker concurrency
+// still need a restart.
+func Watch(ctx context.Context, logger *slog.Logger, apply func(cfg *Config)) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			cfg, err := Load()
+			if err != nil {
+				logger.Error("config reload failed, keeping current values", slog.String("error", err.Error()))
+				continue
+			}
+
+			apply(cfg)
+			logger.Info("config reloaded")
+		}
+	}
+}
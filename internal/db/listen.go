@@ -0,0 +1,80 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// listenPingInterval is how often an idle listener connection is checked, so
+// that a dead connection is noticed and replaced
+const listenPingInterval = 90 * time.Second
+
+// Subscriber receives the notifications of a Postgres channel
+type Subscriber interface {
+	// Notify is called with the payload of each notification
+	Notify(payload string)
+	// Listening is called with true once notifications are being received,
+	// again after each reconnect, and with false when the connection drops.
+	// Notifications sent while the connection is down are lost.
+	Listening(listening bool)
+}
+
+// Listen delivers the notifications sent on a channel to subscriber until ctx
+// is done, reconnecting with a dedicated connection when it drops
+func Listen(ctx context.Context, cfg Config, channel string, subscriber Subscriber, logger *slog.Logger) error {
+	listener := pq.NewListener(cfg.DSN(), time.Second, 30*time.Second, func(event pq.ListenerEventType, err error) {
+		switch event {
+		case pq.ListenerEventDisconnected:
+			logger.Warn("lost notification listener connection", slog.String("channel", channel), slog.Any("error", err))
+			subscriber.Listening(false)
+		case pq.ListenerEventConnectionAttemptFailed:
+			logger.Warn("failed to connect notification listener", slog.String("channel", channel), slog.Any("error", err))
+		}
+	})
+
+	// Listen blocks until connected; closing the listener releases it
+	stop := context.AfterFunc(ctx, func() { listener.Close() })
+	defer func() {
+		if stop() {
+			listener.Close()
+		}
+	}()
+
+	if err := listener.Listen(channel); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("failed to listen on %s: %w", channel, err)
+	}
+	subscriber.Listening(true)
+
+	ticker := time.NewTicker(listenPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case notification, ok := <-listener.Notify:
+			if !ok {
+				return nil
+			}
+			// A nil notification follows a reconnect, once the channel is
+			// listened on again
+			if notification == nil {
+				logger.Info("notification listener reconnected", slog.String("channel", channel))
+				subscriber.Listening(true)
+				continue
+			}
+			subscriber.Notify(notification.Extra)
+		case <-ticker.C:
+			if err := listener.Ping(); err != nil {
+				logger.Warn("notification listener ping failed", slog.String("channel", channel), slog.String("error", err.Error()))
+			}
+		}
+	}
+}
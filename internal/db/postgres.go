@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/XSAM/otelsql"
 	_ "github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
 )
 
 // DB wraps the database connection
@@ -24,14 +26,22 @@ type Config struct {
 	SSLMode  string
 }
 
-// New creates a new database connection with proper pooling
-func New(cfg Config) (*DB, error) {
-	dsn := fmt.Sprintf(
+// DSN returns the connection string for the configuration
+func (cfg Config) DSN() string {
+	return fmt.Sprintf(
 		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode,
 	)
+}
 
-	db, err := sql.Open("postgres", dsn)
+// New creates a new database connection with proper pooling
+func New(cfg Config) (*DB, error) {
+	// Every query gets a span under the request or job that ran it; row
+	// iteration is left out so a page of results is one span, not one per row
+	db, err := otelsql.Open("postgres", cfg.DSN(),
+		otelsql.WithAttributes(semconv.DBSystemNamePostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{OmitRows: true, OmitConnResetSession: true}),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -84,3 +94,13 @@ func (db *DB) Health(ctx context.Context) error {
 
 	return nil
 }
+
+// SchemaVersion returns the highest migration version recorded in schema_version
+func SchemaVersion(ctx context.Context, db *sql.DB) (int, error) {
+	var version int
+	err := db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_version").Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
@@ -0,0 +1,83 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// Handler receives the events it was subscribed to
+type Handler func(ctx context.Context, event Event)
+
+// Bus delivers published events to their subscribers. Delivery is
+// synchronous, in the publisher's goroutine and in subscription order, so
+// handlers must be quick; slow work such as an HTTP call is queued by the
+// handler and done elsewhere. A nil *Bus drops every event.
+type Bus struct {
+	logger *slog.Logger
+
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+	all      []Handler
+}
+
+// NewBus creates a new event bus
+func NewBus(logger *slog.Logger) *Bus {
+	return &Bus{
+		logger:   logger,
+		handlers: make(map[string][]Handler),
+	}
+}
+
+// Subscribe registers handler for the events of type E
+func Subscribe[E Event](b *Bus, handler func(ctx context.Context, event E)) {
+	var zero E
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	name := zero.EventName()
+	b.handlers[name] = append(b.handlers[name], func(ctx context.Context, event Event) {
+		handler(ctx, event.(E))
+	})
+}
+
+// SubscribeAll registers handler for every event
+func (b *Bus) SubscribeAll(handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.all = append(b.all, handler)
+}
+
+// Publish delivers event to its subscribers. A handler that panics is logged
+// and skipped, so a broken subscriber cannot fail the publisher's work.
+func (b *Bus) Publish(ctx context.Context, event Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.RLock()
+	named := b.handlers[event.EventName()]
+	handlers := make([]Handler, 0, len(named)+len(b.all))
+	handlers = append(append(handlers, named...), b.all...)
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		b.deliver(ctx, handler, event)
+	}
+}
+
+// deliver runs one handler, recovering a panic
+func (b *Bus) deliver(ctx context.Context, handler Handler, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.logger.Error("event handler panicked",
+				slog.String("event", event.EventName()),
+				slog.Any("panic", r),
+			)
+		}
+	}()
+
+	handler(ctx, event)
+}
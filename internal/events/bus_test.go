@@ -0,0 +1,50 @@
+package events
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func TestBus_DeliversTypedEventsInOrder(t *testing.T) {
+	bus := NewBus(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	var got []string
+	Subscribe(bus, func(ctx context.Context, event MessageSent) {
+		got = append(got, "sent:"+event.ProviderMessageID)
+	})
+	Subscribe(bus, func(ctx context.Context, event MessageFailed) {
+		panic("broken subscriber")
+	})
+	Subscribe(bus, func(ctx context.Context, event MessageFailed) {
+		got = append(got, "failed:"+event.Error)
+	})
+	bus.SubscribeAll(func(ctx context.Context, event Event) {
+		got = append(got, "all:"+event.EventName())
+	})
+
+	ctx := context.Background()
+	bus.Publish(ctx, MessageSent{MessageID: 1, ProviderMessageID: "ATXid_1"})
+	bus.Publish(ctx, MessageFailed{MessageID: 2, Error: "timeout"})
+	bus.Publish(ctx, CampaignCreated{CampaignID: 3})
+
+	want := []string{
+		"sent:ATXid_1", "all:message.sent",
+		"failed:timeout", "all:message.failed",
+		"all:campaign.created",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("delivered %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("delivery %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestBus_NilDropsEvents(t *testing.T) {
+	var bus *Bus
+	bus.Publish(context.Background(), MessageSent{MessageID: 1})
+}
@@ -0,0 +1,73 @@
+// Package events is an in-process bus of typed domain events. Services and
+// the worker publish what happened, and subsystems such as progress streams,
+// alerts, the event webhook and activity reports subscribe to the events they
+// need instead of being called directly.
+package events
+
+import (
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// Event names, as sent to the event webhook
+const (
+	NameCampaignCreated   = "campaign.created"
+	NameMessageSent       = "message.sent"
+	NameMessageFailed     = "message.failed"
+	NameCampaignCompleted = "campaign.completed"
+)
+
+// Event is a domain event. Events are published and received by value.
+type Event interface {
+	EventName() string
+}
+
+// CampaignCreated is published once a campaign has been stored
+type CampaignCreated struct {
+	CampaignID int64     `json:"campaign_id"`
+	AccountID  int64     `json:"account_id"`
+	Name       string    `json:"name"`
+	Channel    string    `json:"channel"`
+	Status     string    `json:"status"`
+	At         time.Time `json:"at"`
+}
+
+// MessageSent is published once a provider has accepted a message
+type MessageSent struct {
+	MessageID         int64     `json:"message_id"`
+	CampaignID        int64     `json:"campaign_id"`
+	ProviderMessageID string    `json:"provider_message_id,omitempty"`
+	At                time.Time `json:"at"`
+}
+
+// MessageFailed is published when a send fails. While Retrying, the message
+// is pending again and a later attempt may still send it.
+type MessageFailed struct {
+	MessageID  int64     `json:"message_id"`
+	CampaignID int64     `json:"campaign_id"`
+	Error      string    `json:"error"`
+	Retrying   bool      `json:"retrying"`
+	At         time.Time `json:"at"`
+}
+
+// CampaignCompleted is published when the last message of a campaign is
+// settled and the campaign is marked sent or failed
+type CampaignCompleted struct {
+	CampaignID int64                `json:"campaign_id"`
+	Status     string               `json:"status"`
+	Stats      models.CampaignStats `json:"stats"`
+	At         time.Time            `json:"at"`
+}
+
+// EventName returns the name of the event
+func (CampaignCreated) EventName() string { return NameCampaignCreated }
+
+// EventName returns the name of the event
+func (MessageSent) EventName() string { return NameMessageSent }
+
+// EventName returns the name of the event
+func (MessageFailed) EventName() string { return NameMessageFailed }
+
+// EventName returns the name of the event
+func (CampaignCompleted) EventName() string { return NameCampaignCompleted }
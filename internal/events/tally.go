@@ -0,0 +1,63 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Tally counts events by name for activity reports
+type Tally struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+// NewTally creates an empty tally
+func NewTally() *Tally {
+	return &Tally{counts: make(map[string]int64)}
+}
+
+// Handle counts event
+func (t *Tally) Handle(ctx context.Context, event Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.counts[event.EventName()]++
+}
+
+// Take returns the counts since the last call and starts counting afresh
+func (t *Tally) Take() map[string]int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	counts := t.counts
+	t.counts = make(map[string]int64)
+	return counts
+}
+
+// Report logs the events counted over every interval until ctx is done.
+// Intervals without events are not logged.
+func (t *Tally) Report(ctx context.Context, interval time.Duration, logger *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			counts := t.Take()
+			if len(counts) == 0 {
+				continue
+			}
+
+			attrs := make([]any, 0, len(counts)+1)
+			attrs = append(attrs, slog.Duration("interval", interval))
+			for name, count := range counts {
+				attrs = append(attrs, slog.Int64(name, count))
+			}
+			logger.Info("domain events", attrs...)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// webhookBuffer is how many events may wait for delivery before further
+// events are dropped
+const webhookBuffer = 1000
+
+// Webhook posts every event it is handed to a URL as JSON, or those its filter
+// keeps. Events are queued and posted one at a time by Run, so a slow receiver
+// never holds up sends. An event that cannot be posted is logged and not
+// retried.
+type Webhook struct {
+	url     string
+	client  *http.Client
+	pending chan Event
+	filter  WebhookFilter
+	labels  map[int64]cachedLabels
+	logger  *slog.Logger
+}
+
+// webhookPayload is the body posted for an event
+type webhookPayload struct {
+	Type string `json:"type"`
+	Data any    `json:"data"`
+}
+
+// NewWebhook creates a webhook that posts events to url
+func NewWebhook(url string, logger *slog.Logger) *Webhook {
+	return &Webhook{
+		url:     url,
+		client:  &http.Client{Timeout: 5 * time.Second},
+		pending: make(chan Event, webhookBuffer),
+		logger:  logger,
+	}
+}
+
+// Handle queues event for delivery, dropping it if the queue is full or the
+// filter does not want its type
+func (w *Webhook) Handle(ctx context.Context, event Event) {
+	if !w.wants(event) {
+		return
+	}
+
+	select {
+	case w.pending <- event:
+	default:
+		w.logger.Warn("event webhook queue full, dropping event",
+			slog.String("event", event.EventName()),
+		)
+	}
+}
+
+// Run posts queued events until ctx is done
+func (w *Webhook) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-w.pending:
+			matches, err := w.matchesLabels(ctx, event)
+			if err != nil {
+				w.logger.Error("failed to filter event for webhook, dropping event",
+					slog.String("event", event.EventName()),
+					slog.String("error", err.Error()),
+				)
+				continue
+			}
+			if !matches {
+				continue
+			}
+			if err := w.post(ctx, event); err != nil {
+				w.logger.Error("failed to post event to webhook",
+					slog.String("event", event.EventName()),
+					slog.String("error", err.Error()),
+				)
+			}
+		}
+	}
+}
+
+// post posts one event and fails on a non-2xx response
+func (w *Webhook) post(ctx context.Context, event Event) error {
+	data, err := w.payloadData(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	body, err := json.Marshal(webhookPayload{Type: event.EventName(), Data: data})
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
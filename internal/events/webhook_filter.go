@@ -0,0 +1,141 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// labelCacheTTL is how long the webhook trusts the labels it looked up for a
+// campaign; a label change reaches the filter within this time
+const labelCacheTTL = time.Minute
+
+// maxCachedCampaigns bounds the label cache, which is emptied when full
+const maxCachedCampaigns = 10000
+
+// Names lists every event name
+var Names = []string{NameCampaignCreated, NameMessageSent, NameMessageFailed, NameCampaignCompleted}
+
+// CampaignSource looks up the campaign an event is about
+type CampaignSource interface {
+	GetByID(ctx context.Context, id int64) (*models.Campaign, error)
+}
+
+// WebhookFilter narrows what a webhook posts. Empty lists do not filter.
+type WebhookFilter struct {
+	// Events are the names of the events to post
+	Events []string
+	// Labels keeps the events of campaigns with at least one of these labels
+	Labels []string
+	// Fields are the fields of an event's data to post; others are left out
+	Fields []string
+	// Campaigns looks up campaign labels; required with Labels
+	Campaigns CampaignSource
+}
+
+// Validate checks the event names and that labels can be looked up
+func (f WebhookFilter) Validate() error {
+	for _, name := range f.Events {
+		if !slices.Contains(Names, name) {
+			return fmt.Errorf("unknown event %q, expected one of %v", name, Names)
+		}
+	}
+	if len(f.Labels) > 0 && f.Campaigns == nil {
+		return fmt.Errorf("filtering by label needs a campaign source")
+	}
+	return nil
+}
+
+// cachedLabels are a campaign's labels as of a lookup
+type cachedLabels struct {
+	labels []string
+	at     time.Time
+}
+
+// campaignEvent is an event about a campaign or one of its messages
+type campaignEvent interface {
+	campaignID() int64
+}
+
+func (e CampaignCreated) campaignID() int64   { return e.CampaignID }
+func (e MessageSent) campaignID() int64       { return e.CampaignID }
+func (e MessageFailed) campaignID() int64     { return e.CampaignID }
+func (e CampaignCompleted) campaignID() int64 { return e.CampaignID }
+
+// SetFilter makes the webhook post only the events filter keeps. Call it
+// before Run.
+func (w *Webhook) SetFilter(filter WebhookFilter) error {
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+	w.filter = filter
+	w.labels = make(map[int64]cachedLabels)
+	return nil
+}
+
+// wants reports whether the event type is one the webhook posts
+func (w *Webhook) wants(event Event) bool {
+	return len(w.filter.Events) == 0 || slices.Contains(w.filter.Events, event.EventName())
+}
+
+// matchesLabels reports whether the event's campaign has one of the filter's
+// labels. It runs in Run's goroutine, which alone uses the label cache.
+func (w *Webhook) matchesLabels(ctx context.Context, event Event) (bool, error) {
+	if len(w.filter.Labels) == 0 {
+		return true, nil
+	}
+	about, ok := event.(campaignEvent)
+	if !ok {
+		return false, nil
+	}
+
+	id := about.campaignID()
+	cached, ok := w.labels[id]
+	if !ok || time.Since(cached.at) > labelCacheTTL {
+		campaign, err := w.filter.Campaigns.GetByID(ctx, id)
+		if err != nil {
+			return false, fmt.Errorf("failed to look up campaign labels: %w", err)
+		}
+		if len(w.labels) >= maxCachedCampaigns {
+			clear(w.labels)
+		}
+		cached = cachedLabels{labels: campaign.Labels, at: time.Now()}
+		w.labels[id] = cached
+	}
+
+	for _, label := range cached.labels {
+		if slices.Contains(w.filter.Labels, label) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// payloadData returns the data posted for event, keeping only the filter's
+// fields when it has any
+func (w *Webhook) payloadData(event Event) (any, error) {
+	if len(w.filter.Fields) == 0 {
+		return event, nil
+	}
+
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]json.RawMessage, len(w.filter.Fields))
+	for _, name := range w.filter.Fields {
+		if value, ok := fields[name]; ok {
+			selected[name] = value
+		}
+	}
+	return selected, nil
+}
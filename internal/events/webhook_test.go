@@ -0,0 +1,112 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestWebhook_PostsEvents(t *testing.T) {
+	received := make(chan map[string]any, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+		received <- body
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(server.URL, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go webhook.Run(ctx)
+
+	webhook.Handle(ctx, CampaignCompleted{CampaignID: 7, Status: "sent"})
+
+	select {
+	case body := <-received:
+		data, _ := body["data"].(map[string]any)
+		if body["type"] != NameCampaignCompleted || data["campaign_id"] != float64(7) || data["status"] != "sent" {
+			t.Errorf("posted %v, want campaign 7 completed as sent", body)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("event was not posted")
+	}
+}
+
+// campaignLabels is a CampaignSource of campaigns with fixed labels
+type campaignLabels map[int64][]string
+
+func (c campaignLabels) GetByID(ctx context.Context, id int64) (*models.Campaign, error) {
+	return &models.Campaign{ID: id, Labels: c[id]}, nil
+}
+
+func TestWebhook_Filter(t *testing.T) {
+	received := make(chan map[string]any, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+		received <- body
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(server.URL, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	err := webhook.SetFilter(WebhookFilter{
+		Events:    []string{NameCampaignCompleted},
+		Labels:    []string{"transactional"},
+		Fields:    []string{"campaign_id", "status"},
+		Campaigns: campaignLabels{7: {"transactional"}, 8: {"promo"}},
+	})
+	if err != nil {
+		t.Fatalf("SetFilter() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go webhook.Run(ctx)
+
+	// Only the completion of the transactional campaign is posted
+	webhook.Handle(ctx, MessageSent{MessageID: 1, CampaignID: 7})
+	webhook.Handle(ctx, CampaignCompleted{CampaignID: 8, Status: "sent"})
+	webhook.Handle(ctx, CampaignCompleted{CampaignID: 7, Status: "sent", Stats: models.CampaignStats{Total: 3}})
+
+	select {
+	case body := <-received:
+		data, _ := body["data"].(map[string]any)
+		if body["type"] != NameCampaignCompleted || data["campaign_id"] != float64(7) || data["status"] != "sent" {
+			t.Errorf("posted %v, want campaign 7 completed as sent", body)
+		}
+		if _, ok := data["stats"]; ok || len(data) != 2 {
+			t.Errorf("posted data %v, want only campaign_id and status", data)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("event was not posted")
+	}
+
+	select {
+	case body := <-received:
+		t.Errorf("posted %v, want only one event", body)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestWebhookFilter_Validate(t *testing.T) {
+	if err := (WebhookFilter{Events: []string{"campaign.finished"}}).Validate(); err == nil {
+		t.Error("Validate() of an unknown event = nil, want an error")
+	}
+	if err := (WebhookFilter{Labels: []string{"transactional"}}).Validate(); err == nil {
+		t.Error("Validate() of labels without a campaign source = nil, want an error")
+	}
+	if err := (WebhookFilter{Events: Names}).Validate(); err != nil {
+		t.Errorf("Validate() of every event error = %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/service"
+)
+
+// AccountHandler handles account management HTTP requests
+type AccountHandler struct {
+	accountService service.AccountService
+	logger         *slog.Logger
+}
+
+// NewAccountHandler creates a new account handler
+func NewAccountHandler(accountService service.AccountService, logger *slog.Logger) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+		logger:         logger,
+	}
+}
+
+// AccountListResponse lists accounts
+type AccountListResponse struct {
+	Data []*models.Account `json:"data"`
+}
+
+// CustomerAttributesResponse lists the customer attribute keys an account
+// declares
+type CustomerAttributesResponse struct {
+	Attributes []string `json:"attributes"`
+}
+
+// CreateAccount handles POST /accounts
+func (h *AccountHandler) CreateAccount(w http.ResponseWriter, r *http.Request) {
+	var req service.CreateAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	result, err := h.accountService.Create(r.Context(), &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondCreated(w, result)
+}
+
+// ListAccounts handles GET /accounts
+func (h *AccountHandler) ListAccounts(w http.ResponseWriter, r *http.Request) {
+	accounts, err := h.accountService.List(r.Context())
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, AccountListResponse{Data: accounts})
+}
+
+// SetDailySendCap handles PUT /accounts/{id}/daily-send-cap
+func (h *AccountHandler) SetDailySendCap(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid account ID")
+		return
+	}
+
+	var req service.SetDailySendCapRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	account, err := h.accountService.SetDailySendCap(r.Context(), id, &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, account)
+}
+
+// GetUsage handles GET /accounts/{id}/usage
+func (h *AccountHandler) GetUsage(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid account ID")
+		return
+	}
+
+	h.respondUsage(w, r, id)
+}
+
+// GetOwnUsage handles GET /account/usage for the signed-in account
+func (h *AccountHandler) GetOwnUsage(w http.ResponseWriter, r *http.Request) {
+	h.respondUsage(w, r, models.AccountIDFromContext(r.Context()))
+}
+
+// respondUsage writes an account's usage
+func (h *AccountHandler) respondUsage(w http.ResponseWriter, r *http.Request, id int64) {
+	usage, err := h.accountService.Usage(r.Context(), id)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, usage)
+}
+
+// GetCustomerAttributes handles GET /account/customer-attributes for the
+// signed-in account
+func (h *AccountHandler) GetCustomerAttributes(w http.ResponseWriter, r *http.Request) {
+	attributes, err := h.accountService.CustomerAttributes(r.Context(), models.AccountIDFromContext(r.Context()))
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, CustomerAttributesResponse{Attributes: attributes})
+}
+
+// SetCustomerAttributes handles PUT /account/customer-attributes for the
+// signed-in account
+func (h *AccountHandler) SetCustomerAttributes(w http.ResponseWriter, r *http.Request) {
+	var req service.SetCustomerAttributesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	attributes, err := h.accountService.SetCustomerAttributes(r.Context(), models.AccountIDFromContext(r.Context()), &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, CustomerAttributesResponse{Attributes: attributes})
+}
+
+// GetBouncePolicy handles GET /account/bounce-policy for the signed-in account
+func (h *AccountHandler) GetBouncePolicy(w http.ResponseWriter, r *http.Request) {
+	policy, err := h.accountService.BouncePolicy(r.Context(), models.AccountIDFromContext(r.Context()))
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, policy)
+}
+
+// SetBouncePolicy handles PUT /account/bounce-policy for the signed-in account
+func (h *AccountHandler) SetBouncePolicy(w http.ResponseWriter, r *http.Request) {
+	var req service.SetBouncePolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	policy, err := h.accountService.SetBouncePolicy(r.Context(), models.AccountIDFromContext(r.Context()), &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, policy)
+}
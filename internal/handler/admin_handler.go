@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/queue"
+)
+
+// defaultQuarantineLimit is the number of quarantined payloads returned when no limit is given
+const defaultQuarantineLimit = 50
+
+// AdminHandler handles operational/admin HTTP requests
+type AdminHandler struct {
+	queueClient queue.Client
+	logger      *slog.Logger
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(queueClient queue.Client, logger *slog.Logger) *AdminHandler {
+	return &AdminHandler{
+		queueClient: queueClient,
+		logger:      logger,
+	}
+}
+
+// QuarantineResponse lists quarantined queue payloads
+type QuarantineResponse struct {
+	Data []queue.QuarantinedJob `json:"data"`
+}
+
+// PurgeQuarantineResponse reports how many payloads were purged
+type PurgeQuarantineResponse struct {
+	Purged int64 `json:"purged"`
+}
+
+// ListQuarantine handles GET /admin/quarantine
+func (h *AdminHandler) ListQuarantine(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 {
+		limit = defaultQuarantineLimit
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	jobs, err := h.queueClient.ListQuarantined(r.Context(), limit)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, QuarantineResponse{Data: jobs})
+}
+
+// PurgeQuarantine handles DELETE /admin/quarantine
+func (h *AdminHandler) PurgeQuarantine(w http.ResponseWriter, r *http.Request) {
+	purged, err := h.queueClient.PurgeQuarantine(r.Context())
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	h.logger.Info("quarantine purged", slog.Int64("purged", purged))
+
+	respondSuccess(w, PurgeQuarantineResponse{Purged: purged})
+}
+
+// MaintenanceRequest represents a request to turn maintenance mode on
+type MaintenanceRequest struct {
+	Reason string `json:"reason"`
+}
+
+// GetMaintenance handles GET /admin/maintenance
+func (h *AdminHandler) GetMaintenance(w http.ResponseWriter, r *http.Request) {
+	maintenance, err := h.queueClient.Maintenance(r.Context())
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, maintenance)
+}
+
+// EnableMaintenance handles PUT /admin/maintenance; the body is optional
+func (h *AdminHandler) EnableMaintenance(w http.ResponseWriter, r *http.Request) {
+	var req MaintenanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	maintenance, err := h.queueClient.SetMaintenance(r.Context(), req.Reason)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	h.logger.Warn("maintenance mode enabled", slog.String("reason", req.Reason))
+
+	respondSuccess(w, maintenance)
+}
+
+// DisableMaintenance handles DELETE /admin/maintenance
+func (h *AdminHandler) DisableMaintenance(w http.ResponseWriter, r *http.Request) {
+	if err := h.queueClient.ClearMaintenance(r.Context()); err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	h.logger.Info("maintenance mode disabled")
+
+	respondNoContent(w)
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/service"
+)
+
+// AnalyticsHandler handles account-wide analytics HTTP requests
+type AnalyticsHandler struct {
+	analyticsService service.AnalyticsService
+	logger           *slog.Logger
+}
+
+// NewAnalyticsHandler creates a new analytics handler
+func NewAnalyticsHandler(analyticsService service.AnalyticsService, logger *slog.Logger) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+		logger:           logger,
+	}
+}
+
+// Overview handles GET /analytics/overview
+// Supports ?interval=day|week and ?from= and ?to= as YYYY-MM-DD dates
+func (h *AnalyticsHandler) Overview(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	overview, err := h.analyticsService.Overview(r.Context(), &service.AnalyticsOverviewRequest{
+		Interval: query.Get("interval"),
+		From:     query.Get("from"),
+		To:       query.Get("to"),
+	})
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, overview)
+}
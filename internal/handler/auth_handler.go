@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/service"
+)
+
+// AuthHandler handles operator login
+type AuthHandler struct {
+	authService service.AuthService
+	logger      *slog.Logger
+}
+
+// NewAuthHandler creates a new auth handler
+func NewAuthHandler(authService service.AuthService, logger *slog.Logger) *AuthHandler {
+	return &AuthHandler{
+		authService: authService,
+		logger:      logger,
+	}
+}
+
+// Login handles POST /auth/login
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var req service.LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	result, err := h.authService.Login(r.Context(), &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, result)
+}
+
+// authClaimsKey is the context key the authenticated user's claims are stored under
+type authClaimsKey struct{}
+
+// UserFromContext returns the claims of the user making the request, or nil
+// when authentication is disabled
+func UserFromContext(ctx context.Context) *service.AuthClaims {
+	claims, _ := ctx.Value(authClaimsKey{}).(*service.AuthClaims)
+	return claims
+}
+
+// Authorizer enforces operator authentication and roles on /api routes.
+// With no auth service it lets every request through, so deployments that
+// have not configured a JWT secret keep working as before.
+type Authorizer struct {
+	authService service.AuthService
+	logger      *slog.Logger
+}
+
+// NewAuthorizer creates a new authorizer; authService may be nil to disable auth
+func NewAuthorizer(authService service.AuthService, logger *slog.Logger) *Authorizer {
+	return &Authorizer{
+		authService: authService,
+		logger:      logger,
+	}
+}
+
+// Authenticate requires a valid bearer token on /api routes and checks the
+// user's role against the request method: reads need viewer, deletes need
+// admin and other changes need editor. Routes that need more, such as sends,
+// add Require. The request then acts for the user's account; with auth
+// disabled, it acts for the default account.
+func (a *Authorizer) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/api/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if a.authService == nil {
+			next.ServeHTTP(w, r.WithContext(models.WithAccountID(r.Context(), models.DefaultAccountID)))
+			return
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+			respondError(w, http.StatusUnauthorized, "UNAUTHORIZED", "A bearer token from /auth/login is required")
+			return
+		}
+
+		claims, err := a.authService.Authenticate(token)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="api", error="invalid_token"`)
+			handleError(w, err, a.logger)
+			return
+		}
+
+		if !a.allow(w, r, claims, methodRole(r.Method)) {
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), authClaimsKey{}, claims)
+		next.ServeHTTP(w, r.WithContext(models.WithAccountID(ctx, claims.AccountID)))
+	})
+}
+
+// RequireOperator rejects requests from users of accounts other than the
+// default one, for routes that act across accounts. It must run after
+// Authenticate.
+func (a *Authorizer) RequireOperator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a.authService == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		claims := UserFromContext(r.Context())
+		if claims == nil {
+			respondError(w, http.StatusUnauthorized, "UNAUTHORIZED", "A bearer token from /auth/login is required")
+			return
+		}
+		if claims.AccountID != models.DefaultAccountID {
+			a.logger.Warn("request forbidden outside the operator account",
+				slog.Int64("user_id", claims.UserID()),
+				slog.Int64("account_id", claims.AccountID),
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+			)
+			respondError(w, http.StatusForbidden, "FORBIDDEN", "This is only available to the operator's account")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Require rejects requests from users whose role does not grant role. It
+// must run after Authenticate.
+func (a *Authorizer) Require(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if a.authService == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			claims := UserFromContext(r.Context())
+			if claims == nil {
+				respondError(w, http.StatusUnauthorized, "UNAUTHORIZED", "A bearer token from /auth/login is required")
+				return
+			}
+			if !a.allow(w, r, claims, role) {
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// allow responds 403 and returns false when claims do not grant role
+func (a *Authorizer) allow(w http.ResponseWriter, r *http.Request, claims *service.AuthClaims, role string) bool {
+	if models.RoleAllows(claims.Role, role) {
+		return true
+	}
+
+	a.logger.Warn("request forbidden",
+		slog.Int64("user_id", claims.UserID()),
+		slog.String("role", claims.Role),
+		slog.String("required_role", role),
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+	)
+	respondError(w, http.StatusForbidden, "FORBIDDEN", "This requires the "+role+" role")
+	return false
+}
+
+// methodRole is the role a request method needs by default
+func methodRole(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return models.RoleViewer
+	case http.MethodDelete:
+		return models.RoleAdmin
+	default:
+		return models.RoleEditor
+	}
+}
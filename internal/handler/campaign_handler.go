@@ -1,10 +1,15 @@
 package handler
 
 import (
+	"encoding/csv"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
@@ -12,16 +17,33 @@ import (
 	"github.com/Raymond9734/campaign-messaging-backend/internal/service"
 )
 
+// streamWriteTimeout bounds how long a single streamed page may take to write
+const streamWriteTimeout = 30 * time.Second
+
+// errStreamDraining stops a stream early because the server is shutting down
+var errStreamDraining = errors.New("server shutting down")
+
+// streamEnd is the final NDJSON line of a stream cut short by a server
+// shutdown. Clients resume by requesting the stream again with
+// ?after_id=<resume_after_id>.
+type streamEnd struct {
+	Event           string `json:"event"`
+	MessagesWritten int    `json:"messages_written"`
+	ResumeAfterID   int64  `json:"resume_after_id"`
+}
+
 // CampaignHandler handles campaign HTTP requests
 type CampaignHandler struct {
 	campaignService service.CampaignService
+	streams         *StreamRegistry
 	logger          *slog.Logger
 }
 
 // NewCampaignHandler creates a new campaign handler
-func NewCampaignHandler(campaignService service.CampaignService, logger *slog.Logger) *CampaignHandler {
+func NewCampaignHandler(campaignService service.CampaignService, streams *StreamRegistry, logger *slog.Logger) *CampaignHandler {
 	return &CampaignHandler{
 		campaignService: campaignService,
+		streams:         streams,
 		logger:          logger,
 	}
 }
@@ -53,10 +75,11 @@ func (h *CampaignHandler) ListCampaigns(w http.ResponseWriter, r *http.Request)
 	pageSize, _ := strconv.Atoi(query.Get("page_size"))
 
 	filter := models.CampaignFilter{
-		Channel:  query.Get("channel"),
-		Status:   query.Get("status"),
-		Page:     page,
-		PageSize: pageSize,
+		Channel:     query.Get("channel"),
+		Status:      query.Get("status"),
+		Environment: query.Get("environment"),
+		Page:        page,
+		PageSize:    pageSize,
 	}
 
 	result, err := h.campaignService.List(r.Context(), filter)
@@ -68,6 +91,21 @@ func (h *CampaignHandler) ListCampaigns(w http.ResponseWriter, r *http.Request)
 	respondSuccess(w, result)
 }
 
+// ListUpdatedSince handles GET /campaigns/updated-since
+// Supports ?cursor= and ?limit= for polling integrations
+func (h *CampaignHandler) ListUpdatedSince(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+
+	result, err := h.campaignService.ListUpdatedSince(r.Context(), query.Get("cursor"), limit)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, result)
+}
+
 // GetCampaign handles GET /campaigns/{id}
 func (h *CampaignHandler) GetCampaign(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
@@ -86,6 +124,33 @@ func (h *CampaignHandler) GetCampaign(w http.ResponseWriter, r *http.Request) {
 	respondSuccess(w, campaign)
 }
 
+// GetCampaignByExternalID handles GET /campaigns/by-external-id/{external_id}
+func (h *CampaignHandler) GetCampaignByExternalID(w http.ResponseWriter, r *http.Request) {
+	externalID, ok := externalIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	campaign, err := h.campaignService.GetByExternalID(r.Context(), externalID)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, campaign)
+}
+
+// GetCampaignBySlug handles GET /campaigns/slug/{slug}
+func (h *CampaignHandler) GetCampaignBySlug(w http.ResponseWriter, r *http.Request) {
+	campaign, err := h.campaignService.GetBySlug(r.Context(), chi.URLParam(r, "slug"))
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, campaign)
+}
+
 // SendCampaign handles POST /campaigns/{id}/send
 func (h *CampaignHandler) SendCampaign(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
@@ -95,12 +160,19 @@ func (h *CampaignHandler) SendCampaign(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// An empty body sends to the campaign's bound audience
 	var req service.SendCampaignRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
 		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
 		return
 	}
 
+	// A send held for approval must be approved by someone else
+	if claims := UserFromContext(r.Context()); claims != nil {
+		userID := claims.UserID()
+		req.RequestedBy = &userID
+	}
+
 	result, err := h.campaignService.SendCampaign(r.Context(), id, &req)
 	if err != nil {
 		handleError(w, err, h.logger)
@@ -110,6 +182,218 @@ func (h *CampaignHandler) SendCampaign(w http.ResponseWriter, r *http.Request) {
 	respondSuccess(w, result)
 }
 
+// ApproveSendJob handles POST /send-jobs/{id}/approve. The signed-in user
+// approves a send held for a second approval, which releases its messages.
+func (h *CampaignHandler) ApproveSendJob(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid send job ID")
+		return
+	}
+
+	var approverID *int64
+	if claims := UserFromContext(r.Context()); claims != nil {
+		userID := claims.UserID()
+		approverID = &userID
+	}
+
+	result, err := h.campaignService.ApproveSendJob(r.Context(), id, approverID)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, result)
+}
+
+// PauseCampaign handles POST /campaigns/{id}/pause; the body is optional
+func (h *CampaignHandler) PauseCampaign(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid campaign ID")
+		return
+	}
+
+	var req service.PauseCampaignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	result, err := h.campaignService.Pause(r.Context(), id, &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, result)
+}
+
+// ResumeCampaign handles POST /campaigns/{id}/resume
+func (h *CampaignHandler) ResumeCampaign(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid campaign ID")
+		return
+	}
+
+	result, err := h.campaignService.Resume(r.Context(), id)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, result)
+}
+
+// CancelCampaign handles POST /campaigns/{id}/cancel
+func (h *CampaignHandler) CancelCampaign(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid campaign ID")
+		return
+	}
+
+	result, err := h.campaignService.Cancel(r.Context(), id)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, result)
+}
+
+// RetryFailedMessages handles POST /admin/messages/retry-failed
+func (h *CampaignHandler) RetryFailedMessages(w http.ResponseWriter, r *http.Request) {
+	var req service.RetryFailedRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	result, err := h.campaignService.RetryFailed(r.Context(), &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, result)
+}
+
+// RetryCampaignFailed handles POST /campaigns/{id}/retry-failed
+func (h *CampaignHandler) RetryCampaignFailed(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid campaign ID")
+		return
+	}
+
+	result, err := h.campaignService.RetryCampaignFailed(r.Context(), id)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, result)
+}
+
+// SetMaxCost handles PUT /campaigns/{id}/max-cost
+func (h *CampaignHandler) SetMaxCost(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid campaign ID")
+		return
+	}
+
+	var req service.SetMaxCostRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	campaign, err := h.campaignService.SetMaxCost(r.Context(), id, &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, campaign)
+}
+
+// SetMaxInFlight handles PUT /campaigns/{id}/max-in-flight
+func (h *CampaignHandler) SetMaxInFlight(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid campaign ID")
+		return
+	}
+
+	var req service.SetMaxInFlightRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	campaign, err := h.campaignService.SetMaxInFlight(r.Context(), id, &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, campaign)
+}
+
+// SetValidateNumbers handles PUT /campaigns/{id}/validate-numbers
+func (h *CampaignHandler) SetValidateNumbers(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid campaign ID")
+		return
+	}
+
+	var req service.SetValidateNumbersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	campaign, err := h.campaignService.SetValidateNumbers(r.Context(), id, &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, campaign)
+}
+
+// SetUTM handles PUT /campaigns/{id}/utm
+func (h *CampaignHandler) SetUTM(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid campaign ID")
+		return
+	}
+
+	var req service.SetUTMRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	campaign, err := h.campaignService.SetUTM(r.Context(), id, &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, campaign)
+}
+
 // PreviewPersonalized handles POST /campaigns/{id}/personalized-preview
 func (h *CampaignHandler) PreviewPersonalized(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
@@ -133,3 +417,334 @@ func (h *CampaignHandler) PreviewPersonalized(w http.ResponseWriter, r *http.Req
 
 	respondSuccess(w, result)
 }
+
+// PreviewSample handles POST /campaigns/{id}/preview-sample
+func (h *CampaignHandler) PreviewSample(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid campaign ID")
+		return
+	}
+
+	var req service.PreviewSampleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	result, err := h.campaignService.PreviewSample(r.Context(), id, &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, result)
+}
+
+// Estimate handles POST /campaigns/{id}/estimate
+// The body is optional
+func (h *CampaignHandler) Estimate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid campaign ID")
+		return
+	}
+
+	var req service.EstimateCampaignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	result, err := h.campaignService.Estimate(r.Context(), id, &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, result)
+}
+
+// GetReport handles GET /campaigns/{id}/report
+// Supports ?bucket=minute (the default) or hour for the funnel
+func (h *CampaignHandler) GetReport(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid campaign ID")
+		return
+	}
+
+	report, err := h.campaignService.Report(r.Context(), id, r.URL.Query().Get("bucket"))
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, report)
+}
+
+// DeleteCampaign handles DELETE /campaigns/{id}
+// Campaigns with message history require ?force=true
+func (h *CampaignHandler) DeleteCampaign(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid campaign ID")
+		return
+	}
+
+	force, _ := strconv.ParseBool(r.URL.Query().Get("force"))
+
+	if err := h.campaignService.Delete(r.Context(), id, force); err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondNoContent(w)
+}
+
+// StreamMessages handles GET /campaigns/{id}/messages/stream
+// Messages are written as newline-delimited JSON and flushed page by page, so
+// arbitrarily large campaigns can be exported without buffering the response.
+// When the server shuts down the stream stops after the current page and ends
+// with a shutdown event telling the client where to resume.
+func (h *CampaignHandler) StreamMessages(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid campaign ID")
+		return
+	}
+
+	var afterID int64
+	if raw := r.URL.Query().Get("after_id"); raw != "" {
+		afterID, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil || afterID < 0 {
+			respondError(w, http.StatusBadRequest, "INVALID_AFTER_ID", "after_id must be a non-negative integer")
+			return
+		}
+	}
+
+	draining, done := h.streams.Track()
+	defer done()
+
+	rc := http.NewResponseController(w)
+	encoder := json.NewEncoder(w)
+	started := false
+	count := 0
+	lastID := afterID
+
+	err = h.campaignService.StreamMessages(r.Context(), id, afterID, func(page []*models.OutboundMessage) error {
+		if !started {
+			// Exports outlive the server-wide write timeout; each page gets a fresh deadline instead
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
+		_ = rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+
+		for _, message := range page {
+			if err := encoder.Encode(message); err != nil {
+				return err
+			}
+		}
+		count += len(page)
+		lastID = page[len(page)-1].ID
+
+		if err := rc.Flush(); err != nil {
+			return err
+		}
+
+		select {
+		case <-draining:
+			return errStreamDraining
+		default:
+			return nil
+		}
+	})
+
+	if errors.Is(err, errStreamDraining) {
+		_ = rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		_ = encoder.Encode(streamEnd{Event: "shutdown", MessagesWritten: count, ResumeAfterID: lastID})
+		_ = rc.Flush()
+
+		h.logger.Info("message stream closed for shutdown",
+			slog.Int64("campaign_id", id),
+			slog.Int("messages_written", count),
+			slog.Int64("resume_after_id", lastID),
+		)
+		return
+	}
+
+	if err != nil {
+		if !started {
+			handleError(w, err, h.logger)
+			return
+		}
+		// Headers are already sent; the client sees a truncated stream
+		h.logger.Error("message stream aborted",
+			slog.Int64("campaign_id", id),
+			slog.Int("messages_written", count),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	if !started {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// failureExportHeader is the header row of a failed recipient export. The
+// customer columns come first so the file can be fed back into a follow-up
+// campaign once the data issues behind the failures are fixed.
+var failureExportHeader = []string{
+	"customer_id", "external_id", "phone", "first_name", "last_name",
+	"message_id", "status", "reason", "retry_count", "queued_at", "last_attempt_at",
+}
+
+// ExportFailures handles GET /campaigns/{id}/failures/export
+// Failed and undelivered recipients are written as CSV and flushed page by page
+func (h *CampaignHandler) ExportFailures(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid campaign ID")
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	writer := csv.NewWriter(w)
+	started := false
+	count := 0
+
+	start := func() {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="campaign-%d-failures.csv"`, id))
+		w.WriteHeader(http.StatusOK)
+		_ = writer.Write(failureExportHeader)
+		started = true
+	}
+
+	err = h.campaignService.StreamFailures(r.Context(), id, func(page []*models.FailedRecipient) error {
+		if !started {
+			start()
+		}
+		_ = rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+
+		for _, recipient := range page {
+			if err := writer.Write(failureExportRow(recipient)); err != nil {
+				return err
+			}
+		}
+		count += len(page)
+
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return err
+		}
+		return rc.Flush()
+	})
+
+	if err != nil {
+		if !started {
+			handleError(w, err, h.logger)
+			return
+		}
+		// Headers are already sent; the client sees a truncated file
+		h.logger.Error("failure export aborted",
+			slog.Int64("campaign_id", id),
+			slog.Int("recipients_written", count),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	if !started {
+		start()
+	}
+	writer.Flush()
+}
+
+// failureExportRow formats one failed recipient as a CSV record
+func failureExportRow(recipient *models.FailedRecipient) []string {
+	return []string{
+		strconv.FormatInt(recipient.CustomerID, 10),
+		derefString(recipient.ExternalID),
+		recipient.Phone,
+		recipient.FirstName,
+		recipient.LastName,
+		strconv.FormatInt(recipient.MessageID, 10),
+		recipient.Status,
+		derefString(recipient.LastError),
+		strconv.Itoa(recipient.RetryCount),
+		recipient.CreatedAt.UTC().Format(time.RFC3339),
+		recipient.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// derefString returns the value of s, or "" when it is nil
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// ExportCampaign handles GET /campaigns/{id}/export
+func (h *CampaignHandler) ExportCampaign(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid campaign ID")
+		return
+	}
+
+	definition, err := h.campaignService.Export(r.Context(), id)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, definition)
+}
+
+// ImportCampaign handles POST /campaigns/import
+func (h *CampaignHandler) ImportCampaign(w http.ResponseWriter, r *http.Request) {
+	var definition service.CampaignDefinition
+
+	if err := json.NewDecoder(r.Body).Decode(&definition); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	campaign, err := h.campaignService.Import(r.Context(), &definition)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondCreated(w, campaign)
+}
+
+// ProvisionCampaign handles PUT /campaigns/by-key/{external_key}
+// Returns 201 when the campaign was created and 200 when it was updated or already matched
+func (h *CampaignHandler) ProvisionCampaign(w http.ResponseWriter, r *http.Request) {
+	var definition service.CampaignDefinition
+
+	if err := json.NewDecoder(r.Body).Decode(&definition); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	result, err := h.campaignService.Provision(r.Context(), chi.URLParam(r, "external_key"), &definition)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	if result.Outcome == service.ProvisionCreated {
+		respondCreated(w, result)
+		return
+	}
+	respondSuccess(w, result)
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/service"
+)
+
+// ChangeHandler handles change log HTTP requests
+type ChangeHandler struct {
+	changeService service.ChangeService
+	logger        *slog.Logger
+}
+
+// NewChangeHandler creates a new change handler
+func NewChangeHandler(changeService service.ChangeService, logger *slog.Logger) *ChangeHandler {
+	return &ChangeHandler{
+		changeService: changeService,
+		logger:        logger,
+	}
+}
+
+// ListChanges handles GET /changes
+// Supports ?after_id=, ?entity= and ?limit=
+func (h *ChangeHandler) ListChanges(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var afterID int64
+	if raw := query.Get("after_id"); raw != "" {
+		var err error
+		afterID, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil || afterID < 0 {
+			respondError(w, http.StatusBadRequest, "INVALID_AFTER_ID", "after_id must be a non-negative integer")
+			return
+		}
+	}
+	limit, _ := strconv.Atoi(query.Get("limit"))
+
+	result, err := h.changeService.List(r.Context(), afterID, query.Get("entity"), limit)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, result)
+}
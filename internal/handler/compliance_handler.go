@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/service"
+)
+
+// ComplianceHandler handles compliance reporting HTTP requests
+type ComplianceHandler struct {
+	complianceService service.ComplianceService
+	logger            *slog.Logger
+}
+
+// NewComplianceHandler creates a new compliance handler
+func NewComplianceHandler(complianceService service.ComplianceService, logger *slog.Logger) *ComplianceHandler {
+	return &ComplianceHandler{
+		complianceService: complianceService,
+		logger:            logger,
+	}
+}
+
+// OptOutReport handles GET /compliance/opt-outs
+// Supports ?from= and ?to= as YYYY-MM-DD dates
+func (h *ComplianceHandler) OptOutReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	report, err := h.complianceService.OptOutReport(r.Context(), &service.OptOutReportRequest{
+		From: query.Get("from"),
+		To:   query.Get("to"),
+	})
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, report)
+}
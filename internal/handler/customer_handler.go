@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/service"
+)
+
+// CustomerHandler handles customer HTTP requests
+type CustomerHandler struct {
+	customerService service.CustomerService
+	logger          *slog.Logger
+}
+
+// NewCustomerHandler creates a new customer handler
+func NewCustomerHandler(customerService service.CustomerService, logger *slog.Logger) *CustomerHandler {
+	return &CustomerHandler{
+		customerService: customerService,
+		logger:          logger,
+	}
+}
+
+// CreateCustomer handles POST /customers
+func (h *CustomerHandler) CreateCustomer(w http.ResponseWriter, r *http.Request) {
+	var customer models.Customer
+	if err := json.NewDecoder(r.Body).Decode(&customer); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+	customer.ID = 0
+
+	created, err := h.customerService.Create(r.Context(), &customer)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondCreated(w, created)
+}
+
+// ListCustomers handles GET /customers
+// Supports ?phone= (partial match), ?location=, ?page= and ?page_size=
+func (h *CustomerHandler) ListCustomers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	page, _ := strconv.Atoi(query.Get("page"))
+	pageSize, _ := strconv.Atoi(query.Get("page_size"))
+
+	filter := models.CustomerFilter{
+		Phone:    phoneQueryParam(r),
+		Location: query.Get("location"),
+		Page:     page,
+		PageSize: pageSize,
+	}
+
+	customers, pagination, err := h.customerService.List(r.Context(), filter)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, &service.CustomerListResult{
+		Data:       customers,
+		Pagination: pagination,
+	})
+}
+
+// GetCustomer handles GET /customers/{id}
+func (h *CustomerHandler) GetCustomer(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid customer ID")
+		return
+	}
+
+	customer, err := h.customerService.GetByID(r.Context(), id)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, customer)
+}
+
+// GetCustomerByExternalID handles GET /customers/by-external-id/{external_id}
+func (h *CustomerHandler) GetCustomerByExternalID(w http.ResponseWriter, r *http.Request) {
+	externalID, ok := externalIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	customer, err := h.customerService.GetByExternalID(r.Context(), externalID)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, customer)
+}
+
+// UpdateCustomer handles PUT /customers/{id}
+// The body replaces every customer field
+func (h *CustomerHandler) UpdateCustomer(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid customer ID")
+		return
+	}
+
+	var customer models.Customer
+	if err := json.NewDecoder(r.Body).Decode(&customer); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+	customer.ID = id
+
+	updated, err := h.customerService.Update(r.Context(), &customer)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, updated)
+}
+
+// DeleteCustomer handles DELETE /customers/{id}
+// Customers with message history require ?anonymize=true
+func (h *CustomerHandler) DeleteCustomer(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid customer ID")
+		return
+	}
+
+	anonymize, _ := strconv.ParseBool(r.URL.Query().Get("anonymize"))
+
+	if err := h.customerService.Delete(r.Context(), id, anonymize); err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondNoContent(w)
+}
+
+// SnoozeCustomer handles PUT /customers/{id}/snooze
+func (h *CustomerHandler) SnoozeCustomer(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid customer ID")
+		return
+	}
+
+	var req service.SnoozeCustomerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	customer, err := h.customerService.Snooze(r.Context(), id, &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, customer)
+}
+
+// LookupCustomers handles POST /customers/lookup
+func (h *CustomerHandler) LookupCustomers(w http.ResponseWriter, r *http.Request) {
+	var req service.CustomerLookupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	result, err := h.customerService.LookupByPhones(r.Context(), &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, result)
+}
+
+// GetTimeline handles GET /customers/{id}/timeline
+func (h *CustomerHandler) GetTimeline(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid customer ID")
+		return
+	}
+
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+
+	var before *time.Time
+	if raw := query.Get("before"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "INVALID_BEFORE", "before must be an RFC 3339 timestamp")
+			return
+		}
+		before = &parsed
+	}
+
+	timeline, err := h.customerService.Timeline(r.Context(), id, before, limit)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, timeline)
+}
+
+// externalIDParam reads the external_id path parameter, which may contain
+// escaped characters. It writes a 400 response and returns false when invalid.
+func externalIDParam(w http.ResponseWriter, r *http.Request) (string, bool) {
+	externalID, err := url.PathUnescape(chi.URLParam(r, "external_id"))
+	if err != nil || externalID == "" || len(externalID) > models.MaxExternalIDLength {
+		respondError(w, http.StatusBadRequest, "INVALID_EXTERNAL_ID", "Invalid external ID")
+		return "", false
+	}
+	return externalID, true
+}
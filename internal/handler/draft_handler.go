@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/service"
+)
+
+// DraftHandler handles campaign draft and revision HTTP requests
+type DraftHandler struct {
+	draftService service.DraftService
+	logger       *slog.Logger
+}
+
+// NewDraftHandler creates a new draft handler
+func NewDraftHandler(draftService service.DraftService, logger *slog.Logger) *DraftHandler {
+	return &DraftHandler{
+		draftService: draftService,
+		logger:       logger,
+	}
+}
+
+// SaveDraft handles PUT /campaigns/{id}/draft
+func (h *DraftHandler) SaveDraft(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid campaign ID")
+		return
+	}
+
+	var draft models.CampaignDraft
+	if err := json.NewDecoder(r.Body).Decode(&draft); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	revision, err := h.draftService.SaveDraft(r.Context(), id, &draft)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, revision)
+}
+
+// ListRevisions handles GET /campaigns/{id}/revisions
+func (h *DraftHandler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid campaign ID")
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	revisions, err := h.draftService.ListRevisions(r.Context(), id, limit)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, revisions)
+}
+
+// RevertRevision handles POST /campaigns/{id}/revisions/{revision}/revert
+func (h *DraftHandler) RevertRevision(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid campaign ID")
+		return
+	}
+
+	number, err := strconv.Atoi(chi.URLParam(r, "revision"))
+	if err != nil || number < 1 {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid revision number")
+		return
+	}
+
+	revision, err := h.draftService.Revert(r.Context(), id, number)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, revision)
+}
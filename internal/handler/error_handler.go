@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"errors"
 	"log/slog"
 	"net/http"
@@ -26,6 +27,15 @@ func handleError(w http.ResponseWriter, err error, logger *slog.Logger) {
 	case errors.Is(err, models.ErrConflict):
 		respondError(w, http.StatusConflict, "CONFLICT", err.Error())
 
+	case errors.Is(err, context.DeadlineExceeded):
+		logger.Warn("request deadline exceeded", slog.String("error", err.Error()))
+		respondError(w, http.StatusGatewayTimeout, "TIMEOUT", "The request took too long and was stopped")
+
+	case errors.Is(err, context.Canceled):
+		// The client went away; nobody reads this response
+		logger.Info("request canceled by client", slog.String("error", err.Error()))
+		respondError(w, http.StatusServiceUnavailable, "CANCELED", "The request was canceled")
+
 	default:
 		// Log internal errors but don't expose details to client
 		logger.Error("internal server error",
@@ -42,7 +52,7 @@ func mapErrorCodeToHTTPStatus(code string) int {
 		return http.StatusBadRequest
 	case "NOT_FOUND":
 		return http.StatusNotFound
-	case "CONFLICT":
+	case "CONFLICT", "CONFIRMATION_REQUIRED":
 		return http.StatusConflict
 	case "UNAUTHORIZED":
 		return http.StatusUnauthorized
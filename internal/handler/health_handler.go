@@ -5,8 +5,11 @@ import (
 	"database/sql"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/Raymond9734/campaign-messaging-backend/internal/buildinfo"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/db"
 	"github.com/Raymond9734/campaign-messaging-backend/internal/queue"
 )
 
@@ -30,6 +33,17 @@ func NewHealthHandler(db *sql.DB, queueClient queue.Client, logger *slog.Logger)
 type HealthResponse struct {
 	Status   string            `json:"status"`
 	Services map[string]string `json:"services"`
+	// Details is only included with ?verbose=true
+	Details *HealthDetails `json:"details,omitempty"`
+}
+
+// HealthDetails describes exactly what is deployed and how much work is queued
+type HealthDetails struct {
+	Build         buildinfo.Info `json:"build"`
+	SchemaVersion *int           `json:"schema_version"`
+	QueueDepth    *queue.Depth   `json:"queue_depth"`
+	// QueueLagSeconds is how long the oldest ready job has been waiting
+	QueueLagSeconds *float64 `json:"queue_lag_seconds"`
 }
 
 // Health handles GET /health
@@ -64,6 +78,11 @@ func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
 		response.Services["queue"] = "not_configured"
 	}
 
+	verbose, _ := strconv.ParseBool(r.URL.Query().Get("verbose"))
+	if verbose {
+		response.Details = h.details(ctx)
+	}
+
 	// Return appropriate status code
 	if response.Status == "healthy" {
 		respondSuccess(w, response)
@@ -71,3 +90,31 @@ func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
 		respondJSON(w, http.StatusServiceUnavailable, response)
 	}
 }
+
+// details collects build, schema and queue information. Values that cannot be
+// read are left null; they don't change the overall health status.
+func (h *HealthHandler) details(ctx context.Context) *HealthDetails {
+	details := &HealthDetails{Build: buildinfo.Get()}
+
+	if version, err := db.SchemaVersion(ctx, h.db); err != nil {
+		h.logger.Warn("failed to read schema version", slog.String("error", err.Error()))
+	} else {
+		details.SchemaVersion = &version
+	}
+
+	if h.queueClient != nil {
+		if depth, err := h.queueClient.Depth(ctx); err != nil {
+			h.logger.Warn("failed to read queue depth", slog.String("error", err.Error()))
+		} else {
+			details.QueueDepth = depth
+		}
+		if lag, err := h.queueClient.Lag(ctx); err != nil {
+			h.logger.Warn("failed to read queue lag", slog.String("error", err.Error()))
+		} else {
+			seconds := lag.Seconds()
+			details.QueueLagSeconds = &seconds
+		}
+	}
+
+	return details
+}
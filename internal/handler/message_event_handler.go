@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/service"
+)
+
+// MessageEventHandler handles message event HTTP requests
+type MessageEventHandler struct {
+	eventService service.MessageEventService
+	logger       *slog.Logger
+}
+
+// NewMessageEventHandler creates a new message event handler
+func NewMessageEventHandler(eventService service.MessageEventService, logger *slog.Logger) *MessageEventHandler {
+	return &MessageEventHandler{
+		eventService: eventService,
+		logger:       logger,
+	}
+}
+
+// MessageEventListResponse lists the events of a message
+type MessageEventListResponse struct {
+	Data []*models.MessageEvent `json:"data"`
+}
+
+// ListEvents handles GET /messages/{id}/events
+func (h *MessageEventHandler) ListEvents(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid message ID")
+		return
+	}
+
+	events, err := h.eventService.List(r.Context(), id)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, MessageEventListResponse{Data: events})
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/service"
+)
+
+// MessageHandler handles outbound message HTTP requests
+type MessageHandler struct {
+	messageService service.MessageService
+	logger         *slog.Logger
+}
+
+// NewMessageHandler creates a new message handler
+func NewMessageHandler(messageService service.MessageService, logger *slog.Logger) *MessageHandler {
+	return &MessageHandler{
+		messageService: messageService,
+		logger:         logger,
+	}
+}
+
+// ListMessages handles GET /messages
+// Supports ?campaign_id=, ?customer_id=, ?status=, ?page= and ?page_size=
+func (h *MessageHandler) ListMessages(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	campaignID, _ := strconv.ParseInt(query.Get("campaign_id"), 10, 64)
+	customerID, _ := strconv.ParseInt(query.Get("customer_id"), 10, 64)
+	page, _ := strconv.Atoi(query.Get("page"))
+	pageSize, _ := strconv.Atoi(query.Get("page_size"))
+
+	filter := models.OutboundMessageFilter{
+		CampaignID: campaignID,
+		CustomerID: customerID,
+		Status:     query.Get("status"),
+		Page:       page,
+		PageSize:   pageSize,
+	}
+
+	result, err := h.messageService.List(r.Context(), filter)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, result)
+}
+
+// ListUpdatedSince handles GET /messages/updated-since
+// Supports ?cursor= and ?limit= for polling integrations
+func (h *MessageHandler) ListUpdatedSince(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+
+	result, err := h.messageService.ListUpdatedSince(r.Context(), query.Get("cursor"), limit)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, result)
+}
+
+// ListReceipts handles GET /receipts
+// Supports ?since=, ?cursor= and ?limit= for reconciliation jobs
+func (h *MessageHandler) ListReceipts(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+
+	var since *time.Time
+	if raw := query.Get("since"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "INVALID_SINCE", "since must be an RFC 3339 timestamp")
+			return
+		}
+		since = &parsed
+	}
+
+	result, err := h.messageService.ListReceipts(r.Context(), since, query.Get("cursor"), limit)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, result)
+}
+
+// GetMessage handles GET /messages/{id}
+func (h *MessageHandler) GetMessage(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid message ID")
+		return
+	}
+
+	message, err := h.messageService.GetByID(r.Context(), id)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, message)
+}
+
+// ListCampaignMessages handles GET /campaigns/{id}/messages
+// Supports ?phone=, ?customer_id=, ?status=, ?page= and ?page_size=
+func (h *MessageHandler) ListCampaignMessages(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid campaign ID")
+		return
+	}
+
+	query := r.URL.Query()
+	customerID, _ := strconv.ParseInt(query.Get("customer_id"), 10, 64)
+	page, _ := strconv.Atoi(query.Get("page"))
+	pageSize, _ := strconv.Atoi(query.Get("page_size"))
+
+	filter := models.OutboundMessageFilter{
+		CustomerID: customerID,
+		Phone:      phoneQueryParam(r),
+		Status:     query.Get("status"),
+		Page:       page,
+		PageSize:   pageSize,
+	}
+
+	result, err := h.messageService.ListCampaignRecipients(r.Context(), id, filter)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, result)
+}
+
+// GetCampaignBounces handles GET /campaigns/{id}/bounces
+func (h *MessageHandler) GetCampaignBounces(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid campaign ID")
+		return
+	}
+
+	stats, err := h.messageService.CampaignBounces(r.Context(), id)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, stats)
+}
+
+// phoneQueryParam returns the phone query parameter without form decoding,
+// which would turn the leading "+" of an unescaped E.164 number into a space
+func phoneQueryParam(r *http.Request) string {
+	for _, pair := range strings.Split(r.URL.RawQuery, "&") {
+		key, value, _ := strings.Cut(pair, "=")
+		if key != "phone" {
+			continue
+		}
+		phone, err := url.PathUnescape(value)
+		if err != nil {
+			return value
+		}
+		return phone
+	}
+	return ""
+}
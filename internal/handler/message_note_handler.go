@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/service"
+)
+
+// MessageNoteHandler handles message note HTTP requests
+type MessageNoteHandler struct {
+	noteService service.MessageNoteService
+	logger      *slog.Logger
+}
+
+// NewMessageNoteHandler creates a new message note handler
+func NewMessageNoteHandler(noteService service.MessageNoteService, logger *slog.Logger) *MessageNoteHandler {
+	return &MessageNoteHandler{
+		noteService: noteService,
+		logger:      logger,
+	}
+}
+
+// MessageNoteListResponse lists the notes of a message
+type MessageNoteListResponse struct {
+	Data []*models.MessageNote `json:"data"`
+}
+
+// CreateNote handles POST /messages/{id}/notes. The note is attributed to the
+// signed-in user.
+func (h *MessageNoteHandler) CreateNote(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid message ID")
+		return
+	}
+
+	var req service.MessageNoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	var author *string
+	if claims := UserFromContext(r.Context()); claims != nil {
+		author = &claims.Email
+	}
+
+	note, err := h.noteService.Create(r.Context(), id, author, &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondCreated(w, note)
+}
+
+// ListNotes handles GET /messages/{id}/notes
+func (h *MessageNoteHandler) ListNotes(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid message ID")
+		return
+	}
+
+	notes, err := h.noteService.List(r.Context(), id)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, MessageNoteListResponse{Data: notes})
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/service"
+)
+
+// MessageTemplateHandler handles message template HTTP requests
+type MessageTemplateHandler struct {
+	templateService service.MessageTemplateService
+	logger          *slog.Logger
+}
+
+// NewMessageTemplateHandler creates a new message template handler
+func NewMessageTemplateHandler(templateService service.MessageTemplateService, logger *slog.Logger) *MessageTemplateHandler {
+	return &MessageTemplateHandler{
+		templateService: templateService,
+		logger:          logger,
+	}
+}
+
+// MessageTemplateListResponse lists message templates
+type MessageTemplateListResponse struct {
+	Data []*models.MessageTemplate `json:"data"`
+}
+
+// MessageTemplateVersionListResponse lists the versions of a message template
+type MessageTemplateVersionListResponse struct {
+	Data []*models.MessageTemplateVersion `json:"data"`
+}
+
+// CreateTemplate handles POST /templates
+func (h *MessageTemplateHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	var req service.MessageTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	template, err := h.templateService.Create(r.Context(), &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondCreated(w, template)
+}
+
+// ListTemplates handles GET /templates
+func (h *MessageTemplateHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.templateService.List(r.Context())
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, MessageTemplateListResponse{Data: templates})
+}
+
+// GetTemplate handles GET /templates/{id}
+func (h *MessageTemplateHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid template ID")
+		return
+	}
+
+	template, err := h.templateService.GetByID(r.Context(), id)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, template)
+}
+
+// UpdateTemplate handles PUT /templates/{id}
+// Changed content becomes a new version of the template
+func (h *MessageTemplateHandler) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid template ID")
+		return
+	}
+
+	var req service.MessageTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	template, err := h.templateService.Update(r.Context(), id, &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, template)
+}
+
+// DeleteTemplate handles DELETE /templates/{id}
+func (h *MessageTemplateHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid template ID")
+		return
+	}
+
+	if err := h.templateService.Delete(r.Context(), id); err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondNoContent(w)
+}
+
+// ListVersions handles GET /templates/{id}/versions
+func (h *MessageTemplateHandler) ListVersions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid template ID")
+		return
+	}
+
+	versions, err := h.templateService.ListVersions(r.Context(), id)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, MessageTemplateVersionListResponse{Data: versions})
+}
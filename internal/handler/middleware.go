@@ -1,9 +1,21 @@
 package handler
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/queue"
 )
 
 // responseWriter wraps http.ResponseWriter to capture status code
@@ -17,6 +29,11 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap exposes the underlying writer to http.ResponseController (flushing, deadlines)
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // LoggingMiddleware logs HTTP requests
 func LoggingMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -45,6 +62,44 @@ func LoggingMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
 	}
 }
 
+// TracingMiddleware starts a server span for each request, continuing the
+// trace of a caller that sent a traceparent header. Spans are named after the
+// matched route rather than the path, so requests for different IDs group.
+func TracingMiddleware(next http.Handler) http.Handler {
+	tracer := otel.Tracer("github.com/Raymond9734/campaign-messaging-backend/internal/handler")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracer.Start(ctx, r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(r.Method),
+				semconv.URLPath(r.URL.Path),
+			),
+		)
+		defer span.End()
+
+		wrapped := &responseWriter{
+			ResponseWriter: w,
+			statusCode:     http.StatusOK,
+		}
+
+		next.ServeHTTP(wrapped, r.WithContext(ctx))
+
+		// The route is known once the router has matched it
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			if route := rctx.RoutePattern(); route != "" {
+				span.SetName(r.Method + " " + route)
+				span.SetAttributes(semconv.HTTPRoute(route))
+			}
+		}
+		span.SetAttributes(semconv.HTTPResponseStatusCode(wrapped.statusCode))
+		if wrapped.statusCode >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(wrapped.statusCode))
+		}
+	})
+}
+
 // RecoveryMiddleware recovers from panics and returns 500
 func RecoveryMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -81,3 +136,84 @@ func CORSMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// deadlineWriteGrace is how long past the request deadline the response may
+// still be written, so a handler that hit the deadline can report it
+const deadlineWriteGrace = 5 * time.Second
+
+// DeadlineMiddleware bounds each request by timeout. The deadline is carried
+// by the request context, so repositories and service loops stop working on
+// requests that ran out of time or whose client went away. The connection's
+// write deadline is moved to match, since bulk routes may outlive the server's
+// default WriteTimeout.
+func DeadlineMiddleware(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(timeout + deadlineWriteGrace))
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// maintenanceCacheTTL bounds how stale the API's view of the maintenance flag may be
+const maintenanceCacheTTL = 1 * time.Second
+
+// MaintenanceMiddleware returns 503 for mutating requests while maintenance mode
+// is on. Reads stay available, and /api/admin stays writable so operators can
+// still manage the system (including turning maintenance off). The flag is cached
+// for maintenanceCacheTTL; if it cannot be read, requests are let through.
+func MaintenanceMiddleware(queueClient queue.Client, logger *slog.Logger) func(http.Handler) http.Handler {
+	var (
+		mu        sync.Mutex
+		cached    *queue.Maintenance
+		checkedAt time.Time
+	)
+
+	current := func(ctx context.Context) *queue.Maintenance {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if cached != nil && time.Since(checkedAt) < maintenanceCacheTTL {
+			return cached
+		}
+
+		maintenance, err := queueClient.Maintenance(ctx)
+		if err != nil {
+			logger.Error("failed to read maintenance flag", slog.String("error", err.Error()))
+			return &queue.Maintenance{}
+		}
+		cached = maintenance
+		checkedAt = time.Now()
+		return cached
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(w, r)
+				return
+			}
+			if strings.HasPrefix(r.URL.Path, "/api/admin/") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if maintenance := current(r.Context()); maintenance.Enabled {
+				message := "The service is in maintenance mode; changes are temporarily disabled"
+				if maintenance.Reason != "" {
+					message += ": " + maintenance.Reason
+				}
+				w.Header().Set("Retry-After", "60")
+				respondError(w, http.StatusServiceUnavailable, "MAINTENANCE", message)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
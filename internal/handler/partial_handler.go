@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/service"
+)
+
+// PartialHandler handles template partial HTTP requests
+type PartialHandler struct {
+	partialService service.TemplatePartialService
+	logger         *slog.Logger
+}
+
+// NewPartialHandler creates a new template partial handler
+func NewPartialHandler(partialService service.TemplatePartialService, logger *slog.Logger) *PartialHandler {
+	return &PartialHandler{
+		partialService: partialService,
+		logger:         logger,
+	}
+}
+
+// PartialListResponse lists template partials
+type PartialListResponse struct {
+	Data []*models.TemplatePartial `json:"data"`
+}
+
+// CreatePartial handles POST /templates/partials
+func (h *PartialHandler) CreatePartial(w http.ResponseWriter, r *http.Request) {
+	var req service.TemplatePartialRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	partial, err := h.partialService.Create(r.Context(), &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondCreated(w, partial)
+}
+
+// ListPartials handles GET /templates/partials
+func (h *PartialHandler) ListPartials(w http.ResponseWriter, r *http.Request) {
+	partials, err := h.partialService.List(r.Context())
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, PartialListResponse{Data: partials})
+}
+
+// GetPartial handles GET /templates/partials/{name}
+func (h *PartialHandler) GetPartial(w http.ResponseWriter, r *http.Request) {
+	partial, err := h.partialService.GetByName(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, partial)
+}
+
+// UpdatePartial handles PUT /templates/partials/{name}
+func (h *PartialHandler) UpdatePartial(w http.ResponseWriter, r *http.Request) {
+	var req service.TemplatePartialRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	partial, err := h.partialService.Update(r.Context(), chi.URLParam(r, "name"), &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, partial)
+}
+
+// DeletePartial handles DELETE /templates/partials/{name}
+// Partials included by a campaign that has not been sent yet are refused
+func (h *PartialHandler) DeletePartial(w http.ResponseWriter, r *http.Request) {
+	if err := h.partialService.Delete(r.Context(), chi.URLParam(r, "name")); err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondNoContent(w)
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/service"
+)
+
+// devicePreviewPage renders a DevicePreview as a phone-sized conversation,
+// one message bubble per persona, styled after the campaign's channel
+var devicePreviewPage = template.Must(template.New("preview").Funcs(template.FuncMap{
+	"inc": func(i int) int { return i + 1 },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Preview: {{.Name}}</title>
+<style>
+body { margin: 0; font-family: -apple-system, "Segoe UI", Roboto, sans-serif; background: #f2f2f7; color: #1c1c1e; }
+.phone { max-width: 420px; margin: 0 auto; min-height: 100vh; display: flex; flex-direction: column; }
+header { padding: 16px; text-align: center; background: #fff; border-bottom: 1px solid #d1d1d6; }
+header h1 { margin: 0; font-size: 17px; }
+header p { margin: 4px 0 0; font-size: 13px; color: #8e8e93; }
+.thread { flex: 1; padding: 16px; }
+.whatsapp .thread { background: #efeae2; }
+.persona { margin: 20px 0 6px; font-size: 12px; color: #8e8e93; }
+.bubble { max-width: 85%; padding: 10px 14px; border-radius: 18px; font-size: 15px; line-height: 1.35; white-space: pre-wrap; overflow-wrap: anywhere; }
+.sms .bubble { background: #e9e9eb; border-bottom-left-radius: 4px; }
+.whatsapp .bubble { background: #fff; border-radius: 8px; border-top-left-radius: 0; box-shadow: 0 1px 1px rgba(0,0,0,.1); }
+.segment { display: block; margin: 8px 0; border-top: 1px dashed #8e8e93; padding-top: 2px; font-size: 11px; color: #8e8e93; }
+.meta { margin-top: 4px; font-size: 11px; color: #8e8e93; }
+footer { padding: 12px; text-align: center; font-size: 12px; color: #8e8e93; }
+</style>
+</head>
+<body>
+<div class="phone {{.Channel}}">
+<header>
+<h1>{{if .SenderID}}{{.SenderID}}{{else}}{{.Name}}{{end}}</h1>
+<p>{{.Name}} &middot; {{if eq .Channel "whatsapp"}}WhatsApp{{else}}SMS{{end}}</p>
+</header>
+<div class="thread">
+{{range .Messages}}{{$parts := len .Parts}}
+<div class="persona">{{.Description}}</div>
+<div class="bubble">{{range $i, $part := .Parts}}{{if $i}}<span class="segment">segment {{inc $i}} of {{$parts}}</span>{{end}}{{$part}}{{end}}</div>
+<div class="meta">{{.Length}} characters{{if .Encoding}} &middot; {{.Encoding}} &middot; {{$parts}} segment{{if gt $parts 1}}s{{end}}{{end}}</div>
+{{end}}
+</div>
+<footer>Sample customers, not real data. This link expires {{.ExpiresAt.Format "2 Jan 2006 15:04 MST"}}.</footer>
+</div>
+</body>
+</html>
+`))
+
+// PreviewLinkHandler handles campaign preview link HTTP requests
+type PreviewLinkHandler struct {
+	previewLinkService service.PreviewLinkService
+	logger             *slog.Logger
+}
+
+// NewPreviewLinkHandler creates a new preview link handler
+func NewPreviewLinkHandler(previewLinkService service.PreviewLinkService, logger *slog.Logger) *PreviewLinkHandler {
+	return &PreviewLinkHandler{
+		previewLinkService: previewLinkService,
+		logger:             logger,
+	}
+}
+
+// CreateLink handles POST /campaigns/{id}/preview-link; the body is optional
+func (h *PreviewLinkHandler) CreateLink(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid campaign ID")
+		return
+	}
+
+	var req service.PreviewLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	link, err := h.previewLinkService.Create(r.Context(), id, &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondCreated(w, link)
+}
+
+// RevokeLinks handles DELETE /campaigns/{id}/preview-link
+func (h *PreviewLinkHandler) RevokeLinks(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid campaign ID")
+		return
+	}
+
+	if err := h.previewLinkService.Revoke(r.Context(), id); err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondNoContent(w)
+}
+
+// ShowPreview handles GET /preview/{token}
+// Renders an HTML page for browsers; ?format=json returns the preview as JSON
+func (h *PreviewLinkHandler) ShowPreview(w http.ResponseWriter, r *http.Request) {
+	preview, err := h.previewLinkService.Render(r.Context(), chi.URLParam(r, "token"))
+	asJSON := r.URL.Query().Get("format") == "json"
+
+	if err != nil {
+		if asJSON || !errors.Is(err, models.ErrNotFound) {
+			handleError(w, err, h.logger)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, "This preview link is invalid or has expired. Ask for a new one.\n")
+		return
+	}
+
+	if asJSON {
+		respondSuccess(w, preview)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	// The token is in the URL; keep it out of caches and Referer headers
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	if err := devicePreviewPage.Execute(w, preview); err != nil {
+		h.logger.Error("failed to render preview page", slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/pubsub"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/service"
+)
+
+const (
+	// progressInterval is the least time between two progress events, so a
+	// fast campaign costs one stats read per interval rather than per message
+	progressInterval = time.Second
+	// progressRefresh is how often progress is re-read without any event, so
+	// a stream catches up with events lost while the bus was reconnecting
+	progressRefresh = 15 * time.Second
+)
+
+// ProgressHandler streams campaign progress as server-sent events
+type ProgressHandler struct {
+	campaignService service.CampaignService
+	bus             pubsub.Bus
+	streams         *StreamRegistry
+	logger          *slog.Logger
+}
+
+// NewProgressHandler creates a new progress handler
+func NewProgressHandler(campaignService service.CampaignService, bus pubsub.Bus, streams *StreamRegistry, logger *slog.Logger) *ProgressHandler {
+	return &ProgressHandler{
+		campaignService: campaignService,
+		bus:             bus,
+		streams:         streams,
+		logger:          logger,
+	}
+}
+
+// StreamProgress handles GET /campaigns/{id}/progress
+// Sends a progress event with the campaign's status and stats on connect and
+// whenever a worker reports a change, and a complete event once the campaign
+// is sent or failed, after which the stream ends. Workers publish on a shared
+// bus, so any API replica can serve the stream.
+func (h *ProgressHandler) StreamProgress(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid campaign ID")
+		return
+	}
+
+	// Subscribe before the first read so no change falls in between
+	events, unsubscribe := h.bus.Subscribe(id)
+	defer unsubscribe()
+
+	campaign, err := h.campaignService.GetByID(r.Context(), id)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	draining, done := h.streams.Track()
+	defer done()
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Keep proxies from buffering the stream
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+
+	changed := false
+	lastSent := time.Now()
+	for {
+		if err := h.writeProgress(rc, w, campaign); err != nil {
+			return
+		}
+		changed = false
+		lastSent = time.Now()
+
+		if models.IsFinalCampaignStatus(campaign.Status) {
+			return
+		}
+
+		// Wait until a change is due to be sent
+		for !changed || time.Since(lastSent) < progressInterval {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-draining:
+				// Clients reconnect, reaching a replica that is not shutting down
+				return
+			case <-events:
+				changed = true
+			case <-ticker.C:
+				if time.Since(lastSent) >= progressRefresh {
+					changed = true
+				}
+			}
+		}
+
+		campaign, err = h.campaignService.GetByID(r.Context(), id)
+		if err != nil {
+			h.logger.Error("failed to read campaign progress",
+				slog.Int64("campaign_id", id),
+				slog.String("error", err.Error()),
+			)
+			return
+		}
+	}
+}
+
+// writeProgress writes the campaign's progress as one event, named complete
+// once the campaign has finished
+func (h *ProgressHandler) writeProgress(rc *http.ResponseController, w http.ResponseWriter, campaign *models.CampaignWithStats) error {
+	event := "progress"
+	if models.IsFinalCampaignStatus(campaign.Status) {
+		event = "complete"
+	}
+
+	data, err := json.Marshal(campaignProgress{
+		CampaignID:  campaign.ID,
+		Status:      campaign.Status,
+		Stats:       campaign.Stats,
+		CostAccrued: campaign.CostAccrued,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Streams outlive the server-wide write timeout; each event gets a fresh deadline instead
+	_ = rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data); err != nil {
+		return err
+	}
+	return rc.Flush()
+}
+
+// campaignProgress is the data of a progress event
+type campaignProgress struct {
+	CampaignID  int64                `json:"campaign_id"`
+	Status      string               `json:"status"`
+	Stats       models.CampaignStats `json:"stats"`
+	CostAccrued float64              `json:"cost_accrued"`
+}
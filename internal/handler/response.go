@@ -49,3 +49,13 @@ func respondSuccess(w http.ResponseWriter, data interface{}) {
 func respondCreated(w http.ResponseWriter, data interface{}) {
 	respondJSON(w, http.StatusCreated, data)
 }
+
+// respondAccepted writes a successful response with 202 Accepted
+func respondAccepted(w http.ResponseWriter, data interface{}) {
+	respondJSON(w, http.StatusAccepted, data)
+}
+
+// respondNoContent writes an empty response with 204 No Content
+func respondNoContent(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/service"
+)
+
+// SegmentHandler handles customer segment HTTP requests
+type SegmentHandler struct {
+	segmentService service.SegmentService
+	logger         *slog.Logger
+}
+
+// NewSegmentHandler creates a new segment handler
+func NewSegmentHandler(segmentService service.SegmentService, logger *slog.Logger) *SegmentHandler {
+	return &SegmentHandler{
+		segmentService: segmentService,
+		logger:         logger,
+	}
+}
+
+// CreateSegment handles POST /segments
+func (h *SegmentHandler) CreateSegment(w http.ResponseWriter, r *http.Request) {
+	var req service.SegmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	segment, err := h.segmentService.Create(r.Context(), &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondCreated(w, segment)
+}
+
+// ListSegments handles GET /segments
+// Supports ?page= and ?page_size=
+func (h *SegmentHandler) ListSegments(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	page, _ := strconv.Atoi(query.Get("page"))
+	pageSize, _ := strconv.Atoi(query.Get("page_size"))
+
+	result, err := h.segmentService.List(r.Context(), page, pageSize)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, result)
+}
+
+// GetSegment handles GET /segments/{id}
+func (h *SegmentHandler) GetSegment(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid segment ID")
+		return
+	}
+
+	segment, err := h.segmentService.GetByID(r.Context(), id)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, segment)
+}
+
+// UpdateSegment handles PUT /segments/{id}
+// The body replaces the segment's name, description and filter
+func (h *SegmentHandler) UpdateSegment(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid segment ID")
+		return
+	}
+
+	var req service.SegmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	segment, err := h.segmentService.Update(r.Context(), id, &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, segment)
+}
+
+// DeleteSegment handles DELETE /segments/{id}
+// Segments bound to a campaign that has not been sent yet are refused
+func (h *SegmentHandler) DeleteSegment(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid segment ID")
+		return
+	}
+
+	if err := h.segmentService.Delete(r.Context(), id); err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondNoContent(w)
+}
+
+// ListMembers handles GET /segments/{id}/members
+// Supports ?page= and ?page_size=
+func (h *SegmentHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid segment ID")
+		return
+	}
+
+	query := r.URL.Query()
+	page, _ := strconv.Atoi(query.Get("page"))
+	pageSize, _ := strconv.Atoi(query.Get("page_size"))
+
+	result, err := h.segmentService.ListMembers(r.Context(), id, page, pageSize)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, result)
+}
+
+// RefreshCount handles POST /segments/{id}/count
+// Counts the segment's customers now and caches the count
+func (h *SegmentHandler) RefreshCount(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid segment ID")
+		return
+	}
+
+	segment, err := h.segmentService.RefreshCount(r.Context(), id)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, segment)
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/service"
+)
+
+// SenderHandler handles sender ID HTTP requests
+type SenderHandler struct {
+	senderService service.SenderService
+	logger        *slog.Logger
+}
+
+// NewSenderHandler creates a new sender handler
+func NewSenderHandler(senderService service.SenderService, logger *slog.Logger) *SenderHandler {
+	return &SenderHandler{
+		senderService: senderService,
+		logger:        logger,
+	}
+}
+
+// SetWarmup handles PUT /senders/{senderID}/warmup
+func (h *SenderHandler) SetWarmup(w http.ResponseWriter, r *http.Request) {
+	senderID := chi.URLParam(r, "senderID")
+
+	var req service.SenderWarmupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	status, err := h.senderService.SetWarmup(r.Context(), senderID, &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, status)
+}
+
+// GetWarmup handles GET /senders/{senderID}/warmup
+func (h *SenderHandler) GetWarmup(w http.ResponseWriter, r *http.Request) {
+	status, err := h.senderService.GetWarmup(r.Context(), chi.URLParam(r, "senderID"))
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, status)
+}
+
+// DeleteWarmup handles DELETE /senders/{senderID}/warmup
+func (h *SenderHandler) DeleteWarmup(w http.ResponseWriter, r *http.Request) {
+	if err := h.senderService.DeleteWarmup(r.Context(), chi.URLParam(r, "senderID")); err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondNoContent(w)
+}
+
+// SetRegistration handles PUT /senders/{senderID}/registrations/{country}
+func (h *SenderHandler) SetRegistration(w http.ResponseWriter, r *http.Request) {
+	var req service.SenderRegistrationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	registration, err := h.senderService.SetRegistration(r.Context(), chi.URLParam(r, "senderID"), chi.URLParam(r, "country"), &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, registration)
+}
+
+// ListRegistrations handles GET /senders/{senderID}/registrations
+func (h *SenderHandler) ListRegistrations(w http.ResponseWriter, r *http.Request) {
+	registrations, err := h.senderService.ListRegistrations(r.Context(), chi.URLParam(r, "senderID"))
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, registrations)
+}
+
+// DeleteRegistration handles DELETE /senders/{senderID}/registrations/{country}
+func (h *SenderHandler) DeleteRegistration(w http.ResponseWriter, r *http.Request) {
+	if err := h.senderService.DeleteRegistration(r.Context(), chi.URLParam(r, "senderID"), chi.URLParam(r, "country")); err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondNoContent(w)
+}
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/service"
+)
+
+// ShortDomainHandler handles short domain HTTP requests
+type ShortDomainHandler struct {
+	shortDomainService service.ShortDomainService
+	logger             *slog.Logger
+}
+
+// NewShortDomainHandler creates a new short domain handler
+func NewShortDomainHandler(shortDomainService service.ShortDomainService, logger *slog.Logger) *ShortDomainHandler {
+	return &ShortDomainHandler{
+		shortDomainService: shortDomainService,
+		logger:             logger,
+	}
+}
+
+// CreateShortDomain handles POST /short-domains
+func (h *ShortDomainHandler) CreateShortDomain(w http.ResponseWriter, r *http.Request) {
+	var req service.ShortDomainRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	domain, err := h.shortDomainService.Create(r.Context(), &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondCreated(w, domain)
+}
+
+// ListShortDomains handles GET /short-domains
+func (h *ShortDomainHandler) ListShortDomains(w http.ResponseWriter, r *http.Request) {
+	domains, err := h.shortDomainService.List(r.Context())
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, domains)
+}
+
+// GetShortDomain handles GET /short-domains/{id}
+func (h *ShortDomainHandler) GetShortDomain(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid short domain ID")
+		return
+	}
+
+	domain, err := h.shortDomainService.GetByID(r.Context(), id)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, domain)
+}
+
+// UpdateShortDomain handles PUT /short-domains/{id}
+// The body may set is_default and enabled
+func (h *ShortDomainHandler) UpdateShortDomain(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid short domain ID")
+		return
+	}
+
+	var req service.UpdateShortDomainRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	domain, err := h.shortDomainService.Update(r.Context(), id, &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, domain)
+}
+
+// DeleteShortDomain handles DELETE /short-domains/{id}
+// Campaigns that chose the domain go back to the account's default
+func (h *ShortDomainHandler) DeleteShortDomain(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid short domain ID")
+		return
+	}
+
+	if err := h.shortDomainService.Delete(r.Context(), id); err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondNoContent(w)
+}
+
+// CheckShortDomain handles POST /short-domains/{id}/check
+// Runs a health check of the domain now
+func (h *ShortDomainHandler) CheckShortDomain(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid short domain ID")
+		return
+	}
+
+	domain, err := h.shortDomainService.Check(r.Context(), id)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, domain)
+}
+
+// SetCampaignShortDomain handles PUT /campaigns/{id}/short-domain
+func (h *ShortDomainHandler) SetCampaignShortDomain(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid campaign ID")
+		return
+	}
+
+	var req service.SetShortDomainRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	result, err := h.shortDomainService.SetForCampaign(r.Context(), id, &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, result)
+}
+
+// GetCampaignShortDomain handles GET /campaigns/{id}/short-domain
+// Returns the short domain the campaign's links would use now
+func (h *ShortDomainHandler) GetCampaignShortDomain(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid campaign ID")
+		return
+	}
+
+	result, err := h.shortDomainService.ForCampaign(r.Context(), id)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, result)
+}
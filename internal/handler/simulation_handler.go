@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/service"
+)
+
+// SimulationHandler handles campaign simulation HTTP requests
+type SimulationHandler struct {
+	simulationService service.SimulationService
+	logger            *slog.Logger
+}
+
+// NewSimulationHandler creates a new simulation handler
+func NewSimulationHandler(simulationService service.SimulationService, logger *slog.Logger) *SimulationHandler {
+	return &SimulationHandler{
+		simulationService: simulationService,
+		logger:            logger,
+	}
+}
+
+// Simulate handles POST /campaigns/{id}/simulate
+func (h *SimulationHandler) Simulate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid campaign ID")
+		return
+	}
+
+	// An empty body sends to the campaign's bound audience
+	var req service.SendCampaignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	run, err := h.simulationService.Start(r.Context(), id, &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondAccepted(w, run)
+}
+
+// GetSimulation handles GET /campaigns/{id}/simulations/{simulationID}
+func (h *SimulationHandler) GetSimulation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid campaign ID")
+		return
+	}
+
+	simulationID, err := strconv.ParseInt(chi.URLParam(r, "simulationID"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid simulation ID")
+		return
+	}
+
+	run, err := h.simulationService.Get(r.Context(), id, simulationID)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, run)
+}
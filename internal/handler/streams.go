@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"log/slog"
+	"sync"
+)
+
+// StreamRegistry tracks long-lived streaming responses so that a shutting down
+// server can ask them to finish cleanly instead of cutting them mid-stream.
+// Drain is meant to be registered with http.Server.RegisterOnShutdown; the
+// server's Shutdown then waits for the streams to close their connections.
+type StreamRegistry struct {
+	mu       sync.Mutex
+	active   int
+	draining chan struct{}
+	drained  bool
+	logger   *slog.Logger
+}
+
+// NewStreamRegistry creates an empty stream registry
+func NewStreamRegistry(logger *slog.Logger) *StreamRegistry {
+	return &StreamRegistry{
+		draining: make(chan struct{}),
+		logger:   logger,
+	}
+}
+
+// Track registers a stream. The returned channel is closed once the server
+// starts draining; the stream must stop at its next checkpoint, write its
+// final event and return. done must be called when the stream ends.
+func (r *StreamRegistry) Track() (draining <-chan struct{}, done func()) {
+	r.mu.Lock()
+	r.active++
+	r.mu.Unlock()
+
+	var once sync.Once
+	return r.draining, func() {
+		once.Do(func() {
+			r.mu.Lock()
+			r.active--
+			r.mu.Unlock()
+		})
+	}
+}
+
+// Drain notifies every tracked stream, and any stream started afterwards, that
+// the server is shutting down. It is safe to call more than once.
+func (r *StreamRegistry) Drain() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.drained {
+		return
+	}
+	r.drained = true
+	close(r.draining)
+
+	r.logger.Info("draining streaming connections", slog.Int("active", r.active))
+}
+
+// Active returns the number of streams currently open
+func (r *StreamRegistry) Active() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.active
+}
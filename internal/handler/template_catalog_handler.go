@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/service"
+)
+
+// TemplateCatalogHandler handles template catalog HTTP requests
+type TemplateCatalogHandler struct {
+	catalogService service.TemplateCatalogService
+	logger         *slog.Logger
+}
+
+// NewTemplateCatalogHandler creates a new template catalog handler
+func NewTemplateCatalogHandler(catalogService service.TemplateCatalogService, logger *slog.Logger) *TemplateCatalogHandler {
+	return &TemplateCatalogHandler{
+		catalogService: catalogService,
+		logger:         logger,
+	}
+}
+
+// CatalogTemplateListResponse lists catalog templates
+type CatalogTemplateListResponse struct {
+	Data []*models.CatalogTemplate `json:"data"`
+}
+
+// ListTemplates handles GET /template-catalog
+// Supports ?category= to list one category
+func (h *TemplateCatalogHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.catalogService.List(r.Context(), r.URL.Query().Get("category"))
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, CatalogTemplateListResponse{Data: templates})
+}
+
+// GetTemplate handles GET /template-catalog/{slug}
+func (h *TemplateCatalogHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	template, err := h.catalogService.Get(r.Context(), chi.URLParam(r, "slug"))
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, template)
+}
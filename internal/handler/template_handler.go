@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/service"
+)
+
+// TemplateHandler handles template HTTP requests
+type TemplateHandler struct {
+	templateService service.TemplateService
+	logger          *slog.Logger
+}
+
+// NewTemplateHandler creates a new template handler
+func NewTemplateHandler(templateService service.TemplateService, logger *slog.Logger) *TemplateHandler {
+	return &TemplateHandler{
+		templateService: templateService,
+		logger:          logger,
+	}
+}
+
+// Preview handles POST /templates/preview
+func (h *TemplateHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	var req service.TemplatePreviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	result, err := h.templateService.PreviewPersonas(r.Context(), &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, result)
+}
+
+// CheckEncoding handles POST /templates/encoding
+func (h *TemplateHandler) CheckEncoding(w http.ResponseWriter, r *http.Request) {
+	var req service.TemplateEncodingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	result, err := h.templateService.CheckEncoding(r.Context(), &req)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, result)
+}
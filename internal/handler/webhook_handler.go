@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/service"
+)
+
+// maxWebhookBodyBytes bounds a provider callback body
+const maxWebhookBodyBytes = 1 << 20
+
+// WebhookHandler handles callbacks from messaging providers
+type WebhookHandler struct {
+	deliveryReportService service.DeliveryReportService
+	complianceService     service.ComplianceService
+	// token, when set, must match the token query parameter
+	token  string
+	logger *slog.Logger
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(
+	deliveryReportService service.DeliveryReportService,
+	complianceService service.ComplianceService,
+	token string,
+	logger *slog.Logger,
+) *WebhookHandler {
+	return &WebhookHandler{
+		deliveryReportService: deliveryReportService,
+		complianceService:     complianceService,
+		token:                 token,
+		logger:                logger,
+	}
+}
+
+// ReceiveDeliveryReports handles POST /webhooks/delivery-reports?provider=...
+func (h *WebhookHandler) ReceiveDeliveryReports(w http.ResponseWriter, r *http.Request) {
+	if !h.validToken(r.URL.Query().Get("token")) {
+		respondError(w, http.StatusUnauthorized, "INVALID_TOKEN", "Invalid or missing webhook token")
+		return
+	}
+
+	body, ok := h.readBody(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.deliveryReportService.Receive(r.Context(), r.URL.Query().Get("provider"), body)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, result)
+}
+
+// ReceiveInbound handles POST /webhooks/inbound?provider=..., customer replies
+// such as STOP and START
+func (h *WebhookHandler) ReceiveInbound(w http.ResponseWriter, r *http.Request) {
+	if !h.validToken(r.URL.Query().Get("token")) {
+		respondError(w, http.StatusUnauthorized, "INVALID_TOKEN", "Invalid or missing webhook token")
+		return
+	}
+
+	body, ok := h.readBody(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.complianceService.ReceiveInbound(r.Context(), r.URL.Query().Get("provider"), body)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, result)
+}
+
+// ReceiveMeta handles POST /webhooks/meta. A Meta app has a single callback
+// URL, whose webhooks carry both message statuses and customer replies.
+func (h *WebhookHandler) ReceiveMeta(w http.ResponseWriter, r *http.Request) {
+	if !h.validToken(r.URL.Query().Get("token")) {
+		respondError(w, http.StatusUnauthorized, "INVALID_TOKEN", "Invalid or missing webhook token")
+		return
+	}
+
+	body, ok := h.readBody(w, r)
+	if !ok {
+		return
+	}
+
+	reports, err := h.deliveryReportService.Receive(r.Context(), "meta", body)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+	replies, err := h.complianceService.ReceiveInbound(r.Context(), "meta", body)
+	if err != nil {
+		handleError(w, err, h.logger)
+		return
+	}
+
+	respondSuccess(w, &service.MetaWebhookResult{DeliveryReports: reports, Inbound: replies})
+}
+
+// VerifyMeta handles GET on the Meta callback URLs, Meta's subscription
+// check: it echoes hub.challenge when hub.verify_token matches
+func (h *WebhookHandler) VerifyMeta(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("hub.mode") != "subscribe" || !h.validToken(query.Get("hub.verify_token")) {
+		respondError(w, http.StatusForbidden, "INVALID_TOKEN", "Invalid verify token")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(query.Get("hub.challenge")))
+}
+
+// readBody reads a callback body, responding with an error when it can't
+func (h *WebhookHandler) readBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodyBytes))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "INVALID_BODY", "Could not read request body")
+		return nil, false
+	}
+	return body, true
+}
+
+// validToken checks a webhook token; any token is accepted when none is configured
+func (h *WebhookHandler) validToken(token string) bool {
+	if h.token == "" {
+		return true
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) == 1
+}
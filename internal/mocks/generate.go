@@ -0,0 +1,31 @@
+// Package mocks holds gomock mocks of the repository and queue interfaces.
+//
+// The files are generated; regenerate them after changing an interface with:
+//
+//	go generate ./internal/mocks
+package mocks
+
+//go:generate mockgen -source=../repository/account_repository.go -destination=account_repository.go -package=mocks
+//go:generate mockgen -source=../repository/campaign_report_repository.go -destination=campaign_report_repository.go -package=mocks
+//go:generate mockgen -source=../repository/campaign_repository.go -destination=campaign_repository.go -package=mocks
+//go:generate mockgen -source=../repository/campaign_revision_repository.go -destination=campaign_revision_repository.go -package=mocks
+//go:generate mockgen -source=../repository/change_log_repository.go -destination=change_log_repository.go -package=mocks
+//go:generate mockgen -source=../repository/customer_event_repository.go -destination=customer_event_repository.go -package=mocks
+//go:generate mockgen -source=../repository/customer_repository.go -destination=customer_repository.go -package=mocks
+//go:generate mockgen -source=../repository/message_event_repository.go -destination=message_event_repository.go -package=mocks
+//go:generate mockgen -source=../repository/message_note_repository.go -destination=message_note_repository.go -package=mocks
+//go:generate mockgen -source=../repository/message_template_repository.go -destination=message_template_repository.go -package=mocks
+//go:generate mockgen -source=../repository/number_lookup_repository.go -destination=number_lookup_repository.go -package=mocks
+//go:generate mockgen -source=../repository/outbound_message_repository.go -destination=outbound_message_repository.go -package=mocks
+//go:generate mockgen -source=../repository/preview_link_repository.go -destination=preview_link_repository.go -package=mocks
+//go:generate mockgen -source=../repository/reports_repository.go -destination=reports_repository.go -package=mocks
+//go:generate mockgen -source=../repository/sender_registration_repository.go -destination=sender_registration_repository.go -package=mocks
+//go:generate mockgen -source=../repository/sender_warmup_repository.go -destination=sender_warmup_repository.go -package=mocks
+//go:generate mockgen -source=../repository/segment_repository.go -destination=segment_repository.go -package=mocks
+//go:generate mockgen -source=../repository/send_job_repository.go -destination=send_job_repository.go -package=mocks
+//go:generate mockgen -source=../repository/short_domain_repository.go -destination=short_domain_repository.go -package=mocks
+//go:generate mockgen -source=../repository/simulation_repository.go -destination=simulation_repository.go -package=mocks
+//go:generate mockgen -source=../repository/template_catalog_repository.go -destination=template_catalog_repository.go -package=mocks
+//go:generate mockgen -source=../repository/template_partial_repository.go -destination=template_partial_repository.go -package=mocks
+//go:generate mockgen -source=../repository/user_repository.go -destination=user_repository.go -package=mocks
+//go:generate mockgen -source=../queue/client.go -destination=queue_client.go -package=mocks
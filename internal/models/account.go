@@ -0,0 +1,63 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultAccountID is the account of the deployment's operator. Data from
+// before accounts existed belongs to it, and when authentication is disabled
+// every request acts for it. Its admins manage the other accounts.
+const DefaultAccountID int64 = 1
+
+// PauseReasonDailyCap is the paused_reason of campaigns paused because their
+// account reached its daily send cap. They are resumed the next UTC day.
+const PauseReasonDailyCap = "daily_cap_reached"
+
+// Account is a tenant: every customer, campaign and message belongs to one
+type Account struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// DailySendCap limits how many messages the account's live campaigns may
+	// send per UTC day; nil means no limit
+	DailySendCap *int `json:"daily_send_cap"`
+	// CustomerAttributes are the customer attribute keys the account's
+	// templates may use as placeholders
+	CustomerAttributes []string `json:"customer_attributes"`
+	// BouncePolicy is how the account's bounced messages are handled
+	BouncePolicy BouncePolicy `json:"bounce_policy"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+// DailySendQuotaKey is the quota key counting an account's sends per day
+func DailySendQuotaKey(accountID int64) string {
+	return fmt.Sprintf("account:%d", accountID)
+}
+
+// Validate performs basic validation on account data
+func (a *Account) Validate() error {
+	if strings.TrimSpace(a.Name) == "" || len(a.Name) > 255 {
+		return ErrInvalidInput("name must be between 1 and 255 characters")
+	}
+	return nil
+}
+
+// accountKey is the context key the acting account is stored under
+type accountKey struct{}
+
+// WithAccountID returns a copy of ctx acting for an account. Repositories only
+// read and change that account's data under the returned context.
+func WithAccountID(ctx context.Context, accountID int64) context.Context {
+	return context.WithValue(ctx, accountKey{}, accountID)
+}
+
+// AccountIDFromContext returns the account ctx acts for, or 0 when it acts for
+// none in particular. That is the case for background work such as the worker
+// and provider webhooks, which handle every account's messages by ID.
+func AccountIDFromContext(ctx context.Context) int64 {
+	accountID, _ := ctx.Value(accountKey{}).(int64)
+	return accountID
+}
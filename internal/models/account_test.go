@@ -0,0 +1,20 @@
+package models
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAccountIDFromContext(t *testing.T) {
+	if got := AccountIDFromContext(context.Background()); got != 0 {
+		t.Errorf("AccountIDFromContext(background) = %d, want 0", got)
+	}
+
+	ctx := WithAccountID(context.Background(), 4)
+	if got := AccountIDFromContext(ctx); got != 4 {
+		t.Errorf("AccountIDFromContext() = %d, want 4", got)
+	}
+	if got := AccountIDFromContext(WithAccountID(ctx, 9)); got != 9 {
+		t.Errorf("AccountIDFromContext(rescoped) = %d, want 9", got)
+	}
+}
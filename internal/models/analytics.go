@@ -0,0 +1,92 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Analytics overview intervals
+const (
+	AnalyticsIntervalDay  = "day"
+	AnalyticsIntervalWeek = "week"
+)
+
+// ValidateAnalyticsInterval checks that interval is a supported interval
+func ValidateAnalyticsInterval(interval string) error {
+	if interval != AnalyticsIntervalDay && interval != AnalyticsIntervalWeek {
+		return ErrInvalidInput(fmt.Sprintf("invalid interval: %s (must be '%s' or '%s')", interval, AnalyticsIntervalDay, AnalyticsIntervalWeek))
+	}
+	return nil
+}
+
+// AnalyticsOverview sums up an account's sends over a period, in total and
+// per day or week, for dashboards
+type AnalyticsOverview struct {
+	// From is the first day covered and To the last
+	From     time.Time       `json:"from"`
+	To       time.Time       `json:"to"`
+	Interval string          `json:"interval"`
+	Totals   AnalyticsTotals `json:"totals"`
+	// Periods holds every day or week of the overview, oldest first,
+	// including those without messages
+	Periods []*AnalyticsPeriod `json:"periods"`
+}
+
+// AnalyticsTotals counts the campaigns started and the messages created in a
+// period
+type AnalyticsTotals struct {
+	// CampaignsSent counts the campaigns whose first message was created in
+	// the period
+	CampaignsSent     int64            `json:"campaigns_sent"`
+	Messages          int64            `json:"messages"`
+	MessagesByStatus  map[string]int64 `json:"messages_by_status"`
+	MessagesByChannel map[string]int64 `json:"messages_by_channel"`
+	// SuccessRate is the fraction of finished messages that were sent or
+	// delivered; nil until a message has finished
+	SuccessRate *float64 `json:"success_rate"`
+}
+
+// NewAnalyticsTotals returns totals with no campaigns or messages
+func NewAnalyticsTotals() AnalyticsTotals {
+	return AnalyticsTotals{
+		MessagesByStatus:  make(map[string]int64),
+		MessagesByChannel: make(map[string]int64),
+	}
+}
+
+// AddMessages counts messages of a channel and status
+func (t *AnalyticsTotals) AddMessages(channel, status string, messages int64) {
+	t.Messages += messages
+	t.MessagesByStatus[status] += messages
+	t.MessagesByChannel[channel] += messages
+}
+
+// Succeeded counts the messages that were sent or delivered
+func (t *AnalyticsTotals) Succeeded() int64 {
+	return t.MessagesByStatus[MessageStatusSent] + t.MessagesByStatus[MessageStatusDelivered]
+}
+
+// Finished counts the messages that were sent, delivered, undelivered or failed
+func (t *AnalyticsTotals) Finished() int64 {
+	return t.Succeeded() + t.MessagesByStatus[MessageStatusUndelivered] + t.MessagesByStatus[MessageStatusFailed]
+}
+
+// AnalyticsPeriod holds the totals of one day or week
+type AnalyticsPeriod struct {
+	Start time.Time `json:"start"`
+	AnalyticsTotals
+}
+
+// AnalyticsMessageCount counts the messages of one period, channel and status
+type AnalyticsMessageCount struct {
+	Start    time.Time
+	Channel  string
+	Status   string
+	Messages int64
+}
+
+// AnalyticsCampaignCount counts the campaigns started in one period
+type AnalyticsCampaignCount struct {
+	Start     time.Time
+	Campaigns int64
+}
@@ -0,0 +1,55 @@
+package models
+
+import "fmt"
+
+// Bounce types of an undelivered message
+const (
+	// BounceSoft is a temporary failure, such as a switched off handset, that
+	// a later attempt may get past
+	BounceSoft = "soft"
+	// BounceHard is a permanent failure, such as a number that does not exist
+	BounceHard = "hard"
+)
+
+// Limits on a bounce policy
+const (
+	MinSoftBounceRetryHours = 1
+	MaxSoftBounceRetryHours = 72
+	MaxSoftBounceRetries    = 10
+)
+
+// BouncePolicy is how an account handles bounced messages
+type BouncePolicy struct {
+	// SoftRetryHours is how long after a soft bounce the message is sent again
+	SoftRetryHours int `json:"soft_retry_hours"`
+	// SoftMaxRetries is how many times a soft bounced message is sent again;
+	// 0 leaves soft bounces undelivered
+	SoftMaxRetries int `json:"soft_max_retries"`
+	// SuppressHard keeps a number that hard bounced out of later campaigns
+	SuppressHard bool `json:"suppress_hard"`
+}
+
+// Validate checks the policy's limits
+func (p *BouncePolicy) Validate() error {
+	if p.SoftRetryHours < MinSoftBounceRetryHours || p.SoftRetryHours > MaxSoftBounceRetryHours {
+		return ErrInvalidInput(fmt.Sprintf("soft_retry_hours must be between %d and %d", MinSoftBounceRetryHours, MaxSoftBounceRetryHours))
+	}
+	if p.SoftMaxRetries < 0 || p.SoftMaxRetries > MaxSoftBounceRetries {
+		return ErrInvalidInput(fmt.Sprintf("soft_max_retries must be between 0 and %d", MaxSoftBounceRetries))
+	}
+	return nil
+}
+
+// BounceStats counts a campaign's bounced messages
+type BounceStats struct {
+	CampaignID int64 `json:"campaign_id"`
+	// Soft and Hard count messages whose last report was a bounce of that type
+	Soft int64 `json:"soft"`
+	Hard int64 `json:"hard"`
+	// RetryScheduled counts soft bounces waiting to be sent again
+	RetryScheduled int64 `json:"retry_scheduled"`
+	// Retried counts messages sent again after a soft bounce, and Recovered
+	// those of them that were then delivered
+	Retried   int64 `json:"retried"`
+	Recovered int64 `json:"recovered"`
+}
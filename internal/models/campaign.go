@@ -2,16 +2,28 @@ package models
 
 import (
 	"fmt"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // Campaign status constants
 const (
 	CampaignStatusDraft     = "draft"
 	CampaignStatusScheduled = "scheduled"
+	// CampaignStatusReady is a scheduled campaign whose messages were built
+	// ahead of scheduled_at and wait to be queued
+	CampaignStatusReady     = "ready"
 	CampaignStatusSending   = "sending"
+	CampaignStatusPaused    = "paused"
 	CampaignStatusSent      = "sent"
 	CampaignStatusFailed    = "failed"
+	CampaignStatusCancelled = "cancelled"
+
+	// CampaignStatusAwaitingApproval is a campaign whose messages were built
+	// for a send above the approval threshold and wait for a second user to
+	// approve its send job
+	CampaignStatusAwaitingApproval = "awaiting_approval"
 )
 
 // Campaign channel constants
@@ -20,44 +32,161 @@ const (
 	ChannelWhatsApp = "whatsapp"
 )
 
+// Campaign environment constants. Test campaigns are sent with the providers'
+// sandbox credentials and are left out of billing and default listings.
+const (
+	CampaignEnvironmentLive = "live"
+	CampaignEnvironmentTest = "test"
+	// CampaignEnvironmentAll lists campaigns of every environment
+	CampaignEnvironmentAll = "all"
+)
+
 // Campaign represents a messaging campaign
 type Campaign struct {
-	ID           int64      `json:"id"`
-	Name         string     `json:"name"`
-	Channel      string     `json:"channel"`
-	Status       string     `json:"status"`
-	BaseTemplate string     `json:"base_template"`
-	ScheduledAt  *time.Time `json:"scheduled_at"`
-	CreatedAt    time.Time  `json:"created_at"`
+	ID              int64             `json:"id"`
+	AccountID       int64             `json:"-"`
+	Name            string            `json:"name"`
+	Slug            string            `json:"slug"`
+	Channel         string            `json:"channel"`
+	Status          string            `json:"status"`
+	Environment     string            `json:"environment"`
+	Locale          string            `json:"locale"`
+	ValidateNumbers bool              `json:"validate_numbers"`
+	BaseTemplate    string            `json:"base_template"`
+	TemplateID      *int64            `json:"template_id,omitempty"`
+	TemplateVersion *int              `json:"template_version,omitempty"`
+	SenderID        *string           `json:"sender_id"`
+	DeliveryWindows DeliveryWindows   `json:"delivery_windows"`
+	Timezone        string            `json:"timezone"`
+	ScheduledAt     *time.Time        `json:"scheduled_at"`
+	Labels          []string          `json:"labels"`
+	Audience        *CampaignAudience `json:"audience,omitempty"`
+	UTM             *CampaignUTM      `json:"utm,omitempty"`
+	ShortDomainID   *int64            `json:"short_domain_id,omitempty"`
+	MaxCost         *float64          `json:"max_cost,omitempty"`
+	MaxInFlight     *int              `json:"max_in_flight,omitempty"`
+	PrebuildMinutes *int              `json:"prebuild_minutes,omitempty"`
+	ExternalKey     *string           `json:"external_key,omitempty"`
+	ExternalID      *string           `json:"external_id,omitempty"`
+	PausedReason    *string           `json:"paused_reason,omitempty"`
+	PausedAt        *time.Time        `json:"paused_at,omitempty"`
+	ResumeAt        *time.Time        `json:"resume_at,omitempty"`
+	CreatedAt       time.Time         `json:"created_at"`
+}
+
+// CampaignChange is a campaign as returned by the change feed, with the time
+// it was last updated
+type CampaignChange struct {
+	*Campaign
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // CampaignFilter holds filtering options for listing campaigns
 type CampaignFilter struct {
-	Channel  string
-	Status   string
-	Page     int
-	PageSize int
+	Channel string
+	Status  string
+	// Environment defaults to live; "all" lists every environment
+	Environment string
+	Page        int
+	PageSize    int
 }
 
 // CampaignStats holds statistics for a campaign
 type CampaignStats struct {
 	Total   int64 `json:"total"`
 	Pending int64 `json:"pending"`
-	Sending int64 `json:"sending"` // Always 0 in our implementation (no in-flight status)
-	Sent    int64 `json:"sent"`
+	Sending int64 `json:"sending"` // Messages claimed by a worker with the send in flight
+	Sent    int64 `json:"sent"`    // Messages the provider accepted, including delivered and undelivered ones
 	Failed  int64 `json:"failed"`
+	// Delivered and Undelivered count the sent messages with a delivery report
+	Delivered   int64 `json:"delivered"`
+	Undelivered int64 `json:"undelivered"`
+	// Skipped messages were still pending when their campaign was cancelled
+	Skipped int64 `json:"skipped"`
+}
+
+// FinalStatus returns the status a campaign ends in once none of its messages
+// are pending or being sent: failed when every message failed, sent
+// otherwise. It returns false while some still are.
+func (s CampaignStats) FinalStatus() (string, bool) {
+	if s.Total == 0 || s.Pending > 0 || s.Sending > 0 {
+		return "", false
+	}
+	if s.Failed > 0 && s.Sent == 0 {
+		return CampaignStatusFailed, true
+	}
+	return CampaignStatusSent, true
 }
 
 // CampaignWithStats combines campaign details with statistics
 type CampaignWithStats struct {
-	ID           int64         `json:"id"`
-	Name         string        `json:"name"`
-	Channel      string        `json:"channel"`
-	Status       string        `json:"status"`
-	BaseTemplate string        `json:"base_template"`
-	ScheduledAt  *time.Time    `json:"scheduled_at"`
-	CreatedAt    time.Time     `json:"created_at"`
-	Stats        CampaignStats `json:"stats"`
+	ID              int64             `json:"id"`
+	Name            string            `json:"name"`
+	Slug            string            `json:"slug"`
+	Channel         string            `json:"channel"`
+	Status          string            `json:"status"`
+	Environment     string            `json:"environment"`
+	Locale          string            `json:"locale"`
+	ValidateNumbers bool              `json:"validate_numbers"`
+	BaseTemplate    string            `json:"base_template"`
+	TemplateID      *int64            `json:"template_id,omitempty"`
+	TemplateVersion *int              `json:"template_version,omitempty"`
+	SenderID        *string           `json:"sender_id"`
+	DeliveryWindows DeliveryWindows   `json:"delivery_windows"`
+	Timezone        string            `json:"timezone"`
+	ScheduledAt     *time.Time        `json:"scheduled_at"`
+	Labels          []string          `json:"labels"`
+	Audience        *CampaignAudience `json:"audience,omitempty"`
+	UTM             *CampaignUTM      `json:"utm,omitempty"`
+	ShortDomainID   *int64            `json:"short_domain_id,omitempty"`
+	MaxCost         *float64          `json:"max_cost,omitempty"`
+	MaxInFlight     *int              `json:"max_in_flight,omitempty"`
+	PrebuildMinutes *int              `json:"prebuild_minutes,omitempty"`
+	ExternalKey     *string           `json:"external_key,omitempty"`
+	ExternalID      *string           `json:"external_id,omitempty"`
+	PausedReason    *string           `json:"paused_reason,omitempty"`
+	PausedAt        *time.Time        `json:"paused_at,omitempty"`
+	ResumeAt        *time.Time        `json:"resume_at,omitempty"`
+	CreatedAt       time.Time         `json:"created_at"`
+	Stats           CampaignStats     `json:"stats"`
+	// CostAccrued is the cost of the messages sent or being sent so far
+	CostAccrued float64 `json:"cost_accrued"`
+}
+
+// NewCampaignWithStats combines a campaign with its statistics
+func NewCampaignWithStats(campaign *Campaign, stats CampaignStats, costAccrued float64) *CampaignWithStats {
+	return &CampaignWithStats{
+		ID:              campaign.ID,
+		Name:            campaign.Name,
+		Slug:            campaign.Slug,
+		Channel:         campaign.Channel,
+		Status:          campaign.Status,
+		Environment:     campaign.Environment,
+		Locale:          campaign.Locale,
+		ValidateNumbers: campaign.ValidateNumbers,
+		BaseTemplate:    campaign.BaseTemplate,
+		TemplateID:      campaign.TemplateID,
+		TemplateVersion: campaign.TemplateVersion,
+		SenderID:        campaign.SenderID,
+		DeliveryWindows: campaign.DeliveryWindows,
+		Timezone:        campaign.Timezone,
+		ScheduledAt:     campaign.ScheduledAt,
+		Labels:          campaign.Labels,
+		Audience:        campaign.Audience,
+		UTM:             campaign.UTM,
+		ShortDomainID:   campaign.ShortDomainID,
+		MaxCost:         campaign.MaxCost,
+		MaxInFlight:     campaign.MaxInFlight,
+		PrebuildMinutes: campaign.PrebuildMinutes,
+		ExternalKey:     campaign.ExternalKey,
+		ExternalID:      campaign.ExternalID,
+		PausedReason:    campaign.PausedReason,
+		PausedAt:        campaign.PausedAt,
+		ResumeAt:        campaign.ResumeAt,
+		CreatedAt:       campaign.CreatedAt,
+		Stats:           stats,
+		CostAccrued:     costAccrued,
+	}
 }
 
 // Validate performs validation on campaign data
@@ -74,21 +203,112 @@ func (c *Campaign) Validate() error {
 	if c.BaseTemplate == "" {
 		return ErrInvalidInput("base_template is required")
 	}
+	if c.SenderID != nil && len(*c.SenderID) > 32 {
+		return ErrInvalidInput("sender_id must be at most 32 characters")
+	}
+	if err := c.DeliveryWindows.Validate(); err != nil {
+		return err
+	}
+	if c.Timezone != "" {
+		if err := ValidateTimezone(c.Timezone); err != nil {
+			return err
+		}
+	}
+	if err := ValidateExternalID(c.ExternalID); err != nil {
+		return err
+	}
+	if c.Environment != "" && !IsValidEnvironment(c.Environment) {
+		return ErrInvalidInput(fmt.Sprintf("invalid environment: %s (must be 'live' or 'test')", c.Environment))
+	}
 	if c.Status != "" && !IsValidCampaignStatus(c.Status) {
 		return ErrInvalidInput(fmt.Sprintf("invalid status: %s", c.Status))
 	}
 	return nil
 }
 
+// Campaign label limits
+const (
+	MaxCampaignLabels     = 20
+	MaxCampaignLabelChars = 50
+)
+
+// NormalizeLabels trims labels and drops empty and repeated ones, keeping their order
+func NormalizeLabels(labels []string) ([]string, error) {
+	normalized := make([]string, 0, len(labels))
+	seen := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		if label == "" || seen[label] {
+			continue
+		}
+		if utf8.RuneCountInString(label) > MaxCampaignLabelChars {
+			return nil, ErrInvalidInput(fmt.Sprintf("label %q is longer than %d characters", label, MaxCampaignLabelChars))
+		}
+		seen[label] = true
+		normalized = append(normalized, label)
+	}
+
+	if len(normalized) > MaxCampaignLabels {
+		return nil, ErrInvalidInput(fmt.Sprintf("a campaign can have at most %d labels", MaxCampaignLabels))
+	}
+
+	return normalized, nil
+}
+
+// MaxSlugBaseChars is the longest slug derived from a name, leaving room in
+// the 100-character column for a numeric suffix
+const MaxSlugBaseChars = 80
+
+// Slugify derives the base slug for a campaign name: lowercase ASCII letters
+// and digits, with every other run of characters replaced by a single '-'.
+// Names with no such characters get "campaign". Migration 021 backfills
+// existing campaigns with the same rules.
+func Slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+
+	slug := b.String()
+	if len(slug) > MaxSlugBaseChars {
+		slug = slug[:MaxSlugBaseChars]
+	}
+	slug = strings.Trim(slug, "-")
+	if slug == "" {
+		return "campaign"
+	}
+	return slug
+}
+
 // IsValidChannel checks if the channel is valid
 func IsValidChannel(channel string) bool {
 	return channel == ChannelSMS || channel == ChannelWhatsApp
 }
 
+// IsValidEnvironment checks if the campaign environment is valid
+func IsValidEnvironment(environment string) bool {
+	return environment == CampaignEnvironmentLive || environment == CampaignEnvironmentTest
+}
+
+// IsTest reports whether the campaign is QA traffic
+func (c *Campaign) IsTest() bool {
+	return c.Environment == CampaignEnvironmentTest
+}
+
 // IsValidCampaignStatus checks if the campaign status is valid
 func IsValidCampaignStatus(status string) bool {
 	switch status {
-	case CampaignStatusDraft, CampaignStatusScheduled, CampaignStatusSending, CampaignStatusSent, CampaignStatusFailed:
+	case CampaignStatusDraft, CampaignStatusScheduled, CampaignStatusReady, CampaignStatusAwaitingApproval, CampaignStatusSending,
+		CampaignStatusPaused, CampaignStatusSent, CampaignStatusFailed, CampaignStatusCancelled:
 		return true
 	default:
 		return false
@@ -101,3 +321,43 @@ func IsValidCampaignStatus(status string) bool {
 func (c *Campaign) CanBeSent() bool {
 	return c.Status == CampaignStatusDraft || c.Status == CampaignStatusScheduled
 }
+
+// Location returns the timezone the campaign's delivery windows are in. An
+// unset or unknown timezone is UTC.
+func (c *Campaign) Location() *time.Location {
+	if c.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := loadLocation(c.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// AwaitsWindow reports whether the campaign was paused outside its delivery
+// windows and is resumed by the scheduler once the next window opens
+func (c *Campaign) AwaitsWindow() bool {
+	return c.Status == CampaignStatusPaused && c.ResumeAt != nil
+}
+
+// CanBeCancelled reports whether a campaign is scheduled or under way, and so
+// can still be stopped
+func (c *Campaign) CanBeCancelled() bool {
+	switch c.Status {
+	case CampaignStatusScheduled, CampaignStatusReady, CampaignStatusAwaitingApproval, CampaignStatusSending, CampaignStatusPaused:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsFinalCampaignStatus reports whether a campaign in the status is finished
+func IsFinalCampaignStatus(status string) bool {
+	switch status {
+	case CampaignStatusSent, CampaignStatusFailed, CampaignStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// CampaignAudience is a set of recipients in the same shape as a send request:
+// customer_ids, target "all", a segment_id or an ad hoc filter. Bound to a campaign, it is used by
+// sends that name no recipients of their own.
+type CampaignAudience struct {
+	CustomerIDs []int64        `json:"customer_ids,omitempty"`
+	Target      string         `json:"target,omitempty"`
+	SegmentID   *int64         `json:"segment_id,omitempty"`
+	Filter      *SegmentFilter `json:"filter,omitempty"`
+}
+
+// Value implements driver.Valuer, storing the audience as JSON
+func (a *CampaignAudience) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	return json.Marshal(a)
+}
+
+// Scan implements sql.Scanner for JSON stored audiences
+func (a *CampaignAudience) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, a)
+	case string:
+		return json.Unmarshal([]byte(v), a)
+	default:
+		return fmt.Errorf("cannot scan %T into CampaignAudience", src)
+	}
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Campaign report bucket sizes
+const (
+	ReportBucketMinute = "minute"
+	ReportBucketHour   = "hour"
+)
+
+// MaxReportFailureReasons is how many of the most common failure reasons a
+// campaign report lists
+const MaxReportFailureReasons = 20
+
+// ValidateReportBucket checks that bucket is a supported bucket size
+func ValidateReportBucket(bucket string) error {
+	if bucket != ReportBucketMinute && bucket != ReportBucketHour {
+		return ErrInvalidInput(fmt.Sprintf("invalid bucket: %s (must be '%s' or '%s')", bucket, ReportBucketMinute, ReportBucketHour))
+	}
+	return nil
+}
+
+// CampaignReport breaks a campaign's sends down over time and by outcome
+type CampaignReport struct {
+	CampaignID int64         `json:"campaign_id"`
+	Stats      CampaignStats `json:"stats"`
+	// Bucket is the size of the funnel's time buckets
+	Bucket string          `json:"bucket"`
+	Funnel []*FunnelBucket `json:"funnel"`
+	// FailureReasons are the most common errors of failed and undelivered
+	// messages, most common first
+	FailureReasons []*FailureReasonCount `json:"failure_reasons"`
+	// Retries counts messages by how many failed attempts they had
+	Retries []*RetryCount `json:"retries"`
+	Latency SendLatency   `json:"latency"`
+}
+
+// FunnelBucket counts the message events of one time bucket
+type FunnelBucket struct {
+	Start       time.Time `json:"start"`
+	Queued      int64     `json:"queued"`
+	Sent        int64     `json:"sent"`
+	Delivered   int64     `json:"delivered"`
+	Undelivered int64     `json:"undelivered"`
+	Failed      int64     `json:"failed"`
+}
+
+// FailureReasonCount counts the messages that failed with one error
+type FailureReasonCount struct {
+	Reason   string `json:"reason"`
+	Messages int64  `json:"messages"`
+}
+
+// RetryCount counts the messages that had a number of failed attempts
+type RetryCount struct {
+	Retries  int   `json:"retries"`
+	Messages int64 `json:"messages"`
+}
+
+// SendLatency is how long messages took from being created to being accepted
+// by the provider
+type SendLatency struct {
+	// Messages counts the sent messages measured
+	Messages       int64    `json:"messages"`
+	AverageSeconds *float64 `json:"average_seconds"`
+	MaxSeconds     *float64 `json:"max_seconds"`
+}
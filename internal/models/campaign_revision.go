@@ -0,0 +1,58 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Campaign revision source constants
+const (
+	RevisionSourceAutosave = "autosave"
+	RevisionSourceRevert   = "revert"
+)
+
+// CampaignDraft is a snapshot of the editable parts of a campaign. Drafts may be
+// incomplete or invalid while the campaign is being edited.
+type CampaignDraft struct {
+	Name            string            `json:"name"`
+	BaseTemplate    string            `json:"base_template"`
+	SenderID        *string           `json:"sender_id"`
+	DeliveryWindows DeliveryWindows   `json:"delivery_windows"`
+	ScheduledAt     *time.Time        `json:"scheduled_at"`
+	Audience        *CampaignAudience `json:"audience,omitempty"`
+}
+
+// Value implements driver.Valuer, storing the draft as JSON
+func (d CampaignDraft) Value() (driver.Value, error) {
+	return json.Marshal(d)
+}
+
+// Scan implements sql.Scanner for JSON stored drafts
+func (d *CampaignDraft) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, d)
+	case string:
+		return json.Unmarshal([]byte(v), d)
+	default:
+		return fmt.Errorf("cannot scan %T into CampaignDraft", src)
+	}
+}
+
+// CampaignRevision is one saved version of a campaign draft. Revisions are
+// numbered per campaign starting at 1 and are never modified; a revert adds a
+// new revision copying an older one.
+type CampaignRevision struct {
+	ID           int64         `json:"id"`
+	CampaignID   int64         `json:"campaign_id"`
+	Revision     int           `json:"revision"`
+	Source       string        `json:"source"`
+	RevertedFrom *int          `json:"reverted_from,omitempty"`
+	Draft        CampaignDraft `json:"draft"`
+	// Applied reports whether the draft was valid and copied onto the campaign
+	Applied         bool      `json:"applied"`
+	ValidationError *string   `json:"validation_error,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MessageStatsChannel is the Postgres notification channel that carries
+// MessageStatsChange payloads
+const MessageStatsChannel = "message_stats"
+
+// MessageStatsChange is the net change in a campaign's messages per status
+// made by one statement, as notified by the database
+type MessageStatsChange struct {
+	// XID is the ID of the transaction that made the change
+	XID        uint64           `json:"xid,string"`
+	CampaignID int64            `json:"campaign_id"`
+	Counts     map[string]int64 `json:"counts"`
+}
+
+// Add applies the net change in messages per status to the stats
+func (s *CampaignStats) Add(counts map[string]int64) {
+	for status, n := range counts {
+		s.Total += n
+		switch status {
+		case MessageStatusPending:
+			s.Pending += n
+		case MessageStatusSending:
+			s.Sending += n
+		case MessageStatusSent:
+			s.Sent += n
+		case MessageStatusDelivered:
+			s.Sent += n
+			s.Delivered += n
+		case MessageStatusUndelivered:
+			s.Sent += n
+			s.Undelivered += n
+		case MessageStatusFailed:
+			s.Failed += n
+		case MessageStatusSkipped:
+			s.Skipped += n
+		}
+	}
+}
+
+// TxSnapshot is a Postgres snapshot, which tells which transactions were
+// committed when a query ran
+type TxSnapshot struct {
+	// Xmin is the earliest transaction still running; all before it are done
+	Xmin uint64
+	// Xmax is the first transaction not yet started
+	Xmax uint64
+	// InProgress are the transactions between Xmin and Xmax still running
+	InProgress map[uint64]bool
+}
+
+// ParseTxSnapshot parses the text form of pg_current_snapshot(),
+// xmin:xmax:xip_list
+func ParseTxSnapshot(s string) (TxSnapshot, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return TxSnapshot{}, fmt.Errorf("invalid snapshot %q", s)
+	}
+
+	xmin, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return TxSnapshot{}, fmt.Errorf("invalid snapshot xmin %q: %w", parts[0], err)
+	}
+	xmax, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return TxSnapshot{}, fmt.Errorf("invalid snapshot xmax %q: %w", parts[1], err)
+	}
+
+	snapshot := TxSnapshot{Xmin: xmin, Xmax: xmax, InProgress: map[uint64]bool{}}
+	if parts[2] != "" {
+		for _, field := range strings.Split(parts[2], ",") {
+			xid, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				return TxSnapshot{}, fmt.Errorf("invalid snapshot xid %q: %w", field, err)
+			}
+			snapshot.InProgress[xid] = true
+		}
+	}
+
+	return snapshot, nil
+}
+
+// Visible reports whether the changes of a committed transaction are seen by
+// queries that ran in the snapshot
+func (s TxSnapshot) Visible(xid uint64) bool {
+	if xid < s.Xmin {
+		return true
+	}
+	if xid >= s.Xmax {
+		return false
+	}
+	return !s.InProgress[xid]
+}
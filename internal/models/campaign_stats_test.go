@@ -0,0 +1,68 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCampaignStats_Add(t *testing.T) {
+	stats := CampaignStats{Total: 10, Pending: 4, Sending: 2, Sent: 4, Delivered: 1}
+
+	// Two sends complete, one fails, one delivery report arrives
+	stats.Add(map[string]int64{"sending": -2, "sent": 1, "failed": 1})
+	stats.Add(map[string]int64{"sent": -1, "delivered": 1})
+
+	want := CampaignStats{Total: 10, Pending: 4, Sending: 0, Sent: 5, Failed: 1, Delivered: 2}
+	if stats != want {
+		t.Errorf("Add() = %+v, want %+v", stats, want)
+	}
+}
+
+func TestMessageStatsChange_Unmarshal(t *testing.T) {
+	var change MessageStatsChange
+	payload := `{"xid" : "7531", "campaign_id" : 12, "counts" : {"pending": -3, "sending": 3}}`
+	if err := json.Unmarshal([]byte(payload), &change); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if change.XID != 7531 || change.CampaignID != 12 || change.Counts["sending"] != 3 {
+		t.Errorf("Unmarshal() = %+v", change)
+	}
+}
+
+func TestTxSnapshot_Visible(t *testing.T) {
+	snapshot, err := ParseTxSnapshot("100:110:100,105")
+	if err != nil {
+		t.Fatalf("ParseTxSnapshot() error = %v", err)
+	}
+
+	tests := []struct {
+		xid  uint64
+		want bool
+	}{
+		{99, true},
+		{100, false},
+		{102, true},
+		{105, false},
+		{110, false},
+		{120, false},
+	}
+	for _, tt := range tests {
+		if got := snapshot.Visible(tt.xid); got != tt.want {
+			t.Errorf("Visible(%d) = %v, want %v", tt.xid, got, tt.want)
+		}
+	}
+
+	empty, err := ParseTxSnapshot("100:100:")
+	if err != nil {
+		t.Fatalf("ParseTxSnapshot() error = %v", err)
+	}
+	if !empty.Visible(99) || empty.Visible(100) {
+		t.Error("Expected only transactions before 100 to be visible")
+	}
+
+	for _, invalid := range []string{"", "100:110", "a:110:", "100:110:x"} {
+		if _, err := ParseTxSnapshot(invalid); err == nil {
+			t.Errorf("ParseTxSnapshot(%q) expected error", invalid)
+		}
+	}
+}
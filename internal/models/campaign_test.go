@@ -0,0 +1,60 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "October Promo", want: "october-promo"},
+		{name: "  Black Friday -- 50% off!  ", want: "black-friday-50-off"},
+		{name: "Ofertă de toamnă", want: "ofert-de-toamn"},
+		{name: "🎉🎉", want: "campaign"},
+		{name: strings.Repeat("a", 79) + " b", want: strings.Repeat("a", 79)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Slugify(tt.name); got != tt.want {
+				t.Errorf("Slugify(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTimezone(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{name: "UTC"},
+		{name: "Africa/Nairobi"},
+		{name: "America/New_York"},
+		{name: "", wantErr: true},
+		{name: "Local", wantErr: true},
+		{name: "Mars/Olympus_Mons", wantErr: true},
+		{name: "+03:00", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateTimezone(tt.name); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTimezone(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCampaign_Location(t *testing.T) {
+	if got := (&Campaign{}).Location(); got != time.UTC {
+		t.Errorf("Location() without timezone = %v, want UTC", got)
+	}
+	if got := (&Campaign{Timezone: "Africa/Nairobi"}).Location(); got.String() != "Africa/Nairobi" {
+		t.Errorf("Location() = %v, want Africa/Nairobi", got)
+	}
+}
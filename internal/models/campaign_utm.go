@@ -0,0 +1,80 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// maxUTMValueLength bounds each UTM parameter of a campaign
+const maxUTMValueLength = 100
+
+// CampaignUTM holds the UTM parameters appended to every link in a campaign's
+// messages, so web analytics can attribute the traffic to the campaign
+type CampaignUTM struct {
+	Source string `json:"source"`
+	Medium string `json:"medium,omitempty"`
+	// Campaign defaults to the campaign's slug
+	Campaign string `json:"campaign,omitempty"`
+	Term     string `json:"term,omitempty"`
+	Content  string `json:"content,omitempty"`
+}
+
+// Validate checks that source is set and that no parameter is too long
+func (u *CampaignUTM) Validate() error {
+	if strings.TrimSpace(u.Source) == "" {
+		return ErrInvalidInput("utm source is required")
+	}
+	for name, value := range map[string]string{
+		"source": u.Source, "medium": u.Medium, "campaign": u.Campaign, "term": u.Term, "content": u.Content,
+	} {
+		if len(value) > maxUTMValueLength {
+			return ErrInvalidInput(fmt.Sprintf("utm %s must be at most %d characters", name, maxUTMValueLength))
+		}
+	}
+	return nil
+}
+
+// Params returns the utm_ query parameters to append, in their usual order,
+// leaving out those not set. slug stands in for an unset campaign.
+func (u *CampaignUTM) Params(slug string) [][2]string {
+	campaign := u.Campaign
+	if campaign == "" {
+		campaign = slug
+	}
+
+	var params [][2]string
+	for _, param := range [][2]string{
+		{"utm_source", u.Source},
+		{"utm_medium", u.Medium},
+		{"utm_campaign", campaign},
+		{"utm_term", u.Term},
+		{"utm_content", u.Content},
+	} {
+		if value := strings.TrimSpace(param[1]); value != "" {
+			params = append(params, [2]string{param[0], value})
+		}
+	}
+	return params
+}
+
+// Value implements driver.Valuer, storing the parameters as JSON
+func (u *CampaignUTM) Value() (driver.Value, error) {
+	if u == nil {
+		return nil, nil
+	}
+	return json.Marshal(u)
+}
+
+// Scan implements sql.Scanner for JSON stored parameters
+func (u *CampaignUTM) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, u)
+	case string:
+		return json.Unmarshal([]byte(v), u)
+	default:
+		return fmt.Errorf("cannot scan %T into CampaignUTM", src)
+	}
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Change log entity names
+const (
+	ChangeEntityCustomer        = "customer"
+	ChangeEntityCampaign        = "campaign"
+	ChangeEntityOutboundMessage = "outbound_message"
+	ChangeEntitySenderWarmup    = "sender_warmup"
+)
+
+// Change log operations
+const (
+	ChangeOperationInsert = "insert"
+	ChangeOperationUpdate = "update"
+	ChangeOperationDelete = "delete"
+)
+
+// ChangeRecord is one insert, update or delete of a synced entity
+type ChangeRecord struct {
+	ID        int64  `json:"id"`
+	Entity    string `json:"entity"`
+	EntityID  string `json:"entity_id"`
+	Operation string `json:"operation"`
+	// Data is the row after the change. It is null for deletes and for
+	// customers that were later anonymized.
+	Data      json.RawMessage `json:"data"`
+	ChangedAt time.Time       `json:"changed_at"`
+}
+
+// IsValidChangeEntity checks if an entity name is recorded in the change log
+func IsValidChangeEntity(entity string) bool {
+	switch entity {
+	case ChangeEntityCustomer, ChangeEntityCampaign, ChangeEntityOutboundMessage, ChangeEntitySenderWarmup:
+		return true
+	}
+	return false
+}
@@ -1,5 +1,10 @@
 package models
 
+import (
+	"fmt"
+	"time"
+)
+
 // Customer represents a customer in the system
 type Customer struct {
 	ID               int64  `json:"id"`
@@ -8,6 +13,35 @@ type Customer struct {
 	LastName         string `json:"last_name"`
 	Location         string `json:"location"`
 	PreferredProduct string `json:"preferred_product"`
+	// ExternalID is the customer's ID in an external system such as a CRM
+	ExternalID *string `json:"external_id,omitempty"`
+	// OptedOutAt is when the customer replied STOP; opted-out customers are
+	// sent no campaign messages
+	OptedOutAt *time.Time `json:"opted_out_at,omitempty"`
+	// SnoozedUntil keeps the customer out of campaigns until then without
+	// opting them out
+	SnoozedUntil *time.Time `json:"snoozed_until,omitempty"`
+	// BouncedAt is when a message to the number hard bounced. Campaigns skip
+	// the customer until their phone changes.
+	BouncedAt *time.Time `json:"bounced_at,omitempty"`
+	// Attributes are custom fields such as a loyalty tier, usable in
+	// templates as {key} placeholders
+	Attributes CustomerAttributes `json:"attributes,omitempty"`
+}
+
+// IsOptedOut reports whether the customer has opted out of campaign messages
+func (c *Customer) IsOptedOut() bool {
+	return c.OptedOutAt != nil
+}
+
+// IsSnoozed reports whether the customer is kept out of campaigns at now
+func (c *Customer) IsSnoozed(now time.Time) bool {
+	return c.SnoozedUntil != nil && now.Before(*c.SnoozedUntil)
+}
+
+// IsBounced reports whether the customer's number hard bounced
+func (c *Customer) IsBounced() bool {
+	return c.BouncedAt != nil
 }
 
 // CustomerFilter holds filtering options for listing customers
@@ -23,5 +57,19 @@ func (c *Customer) Validate() error {
 	if c.Phone == "" {
 		return ErrInvalidInput("phone is required")
 	}
+	if err := c.Attributes.Validate(); err != nil {
+		return err
+	}
+	return ValidateExternalID(c.ExternalID)
+}
+
+// MaxExternalIDLength is the maximum length of a customer or campaign external ID
+const MaxExternalIDLength = 100
+
+// ValidateExternalID checks an optional external ID
+func ValidateExternalID(externalID *string) error {
+	if externalID != nil && (*externalID == "" || len(*externalID) > MaxExternalIDLength) {
+		return ErrInvalidInput(fmt.Sprintf("external_id must be between 1 and %d characters", MaxExternalIDLength))
+	}
 	return nil
 }
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// Limits on customer attributes
+const (
+	MaxCustomerAttributes        = 50
+	MaxCustomerAttributeKeyLen   = 50
+	MaxCustomerAttributeValueLen = 500
+)
+
+// attributeKeyPattern matches keys usable as template placeholders
+var attributeKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// reservedAttributeKeys are the built-in placeholders, which an attribute of
+// the same name could never be rendered in place of
+var reservedAttributeKeys = map[string]bool{
+	"first_name":          true,
+	"last_name":           true,
+	"location":            true,
+	"preferred_product":   true,
+	"recommended_product": true,
+	"phone":               true,
+}
+
+// CustomerAttributes are a customer's custom attributes by key. Templates use
+// them as {key} placeholders once the account declares the key.
+type CustomerAttributes map[string]string
+
+// ValidateAttributeKey checks that key can name a customer attribute
+func ValidateAttributeKey(key string) error {
+	if len(key) > MaxCustomerAttributeKeyLen || !attributeKeyPattern.MatchString(key) {
+		return ErrInvalidInput(fmt.Sprintf("attribute key %q must be up to %d lowercase letters, digits and underscores, starting with a letter", key, MaxCustomerAttributeKeyLen))
+	}
+	if reservedAttributeKeys[key] {
+		return ErrInvalidInput(fmt.Sprintf("attribute key %q is a built-in customer field", key))
+	}
+	return nil
+}
+
+// Validate checks the attribute keys and the length of their values
+func (a CustomerAttributes) Validate() error {
+	if len(a) > MaxCustomerAttributes {
+		return ErrInvalidInput(fmt.Sprintf("a customer can have at most %d attributes", MaxCustomerAttributes))
+	}
+	for key, value := range a {
+		if err := ValidateAttributeKey(key); err != nil {
+			return err
+		}
+		if len(value) > MaxCustomerAttributeValueLen {
+			return ErrInvalidInput(fmt.Sprintf("attribute %s must be at most %d characters", key, MaxCustomerAttributeValueLen))
+		}
+	}
+	return nil
+}
+
+// Value implements driver.Valuer, storing the attributes as a JSON object
+func (a CustomerAttributes) Value() (driver.Value, error) {
+	if a == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(a)
+}
+
+// Scan implements sql.Scanner for JSON stored attributes
+func (a *CustomerAttributes) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, a)
+	case string:
+		return json.Unmarshal([]byte(v), a)
+	default:
+		return fmt.Errorf("cannot scan %T into CustomerAttributes", src)
+	}
+}
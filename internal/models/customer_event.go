@@ -0,0 +1,73 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Customer event type constants
+const (
+	CustomerEventCreated        = "created"
+	CustomerEventProfileUpdated = "profile_updated"
+	CustomerEventAnonymized     = "anonymized"
+	CustomerEventOptedIn        = "opted_in"
+	CustomerEventOptedOut       = "opted_out"
+	CustomerEventImported       = "imported"
+	CustomerEventSnoozed        = "snoozed"
+	CustomerEventUnsnoozed      = "unsnoozed"
+)
+
+// Timeline entry types derived from outbound messages
+const (
+	TimelineMessageQueued = "message_queued"
+	TimelineMessageSent   = "message_sent"
+	TimelineMessageFailed = "message_failed"
+)
+
+// CustomerEvent records something that happened to a customer outside of
+// message delivery
+type CustomerEvent struct {
+	ID         int64  `json:"id"`
+	CustomerID int64  `json:"customer_id"`
+	Type       string `json:"type"`
+	// CampaignID is the campaign an opt-out is attributed to
+	CampaignID *int64          `json:"campaign_id,omitempty"`
+	Details    json.RawMessage `json:"details,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// TimelineEntry is one item of a customer's activity timeline: either a
+// customer event or a step in the life of one of their messages
+type TimelineEntry struct {
+	OccurredAt time.Time       `json:"occurred_at"`
+	Type       string          `json:"type"`
+	CampaignID *int64          `json:"campaign_id,omitempty"`
+	MessageID  *int64          `json:"message_id,omitempty"`
+	Details    json.RawMessage `json:"details,omitempty"`
+}
+
+// OptOutReport summarizes opt-outs and re-subscriptions over a period, for
+// compliance audits. Rates are fractions of the messages sent.
+type OptOutReport struct {
+	// From and To are the first and last day covered
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	OptOuts int64     `json:"opt_outs"`
+	// UnattributedOptOuts came from customers who had never been sent a campaign
+	UnattributedOptOuts int64 `json:"unattributed_opt_outs"`
+	// Resubscribed counts the customers who opted back in
+	Resubscribed int64 `json:"resubscribed"`
+	// AverageOptOutRate is the mean opt-out rate of the campaigns that sent
+	// messages in the period
+	AverageOptOutRate float64            `json:"average_opt_out_rate"`
+	Campaigns         []*CampaignOptOuts `json:"campaigns"`
+}
+
+// CampaignOptOuts are the opt-outs attributed to one campaign in a period
+type CampaignOptOuts struct {
+	CampaignID   int64   `json:"campaign_id"`
+	Name         string  `json:"name"`
+	MessagesSent int64   `json:"messages_sent"`
+	OptOuts      int64   `json:"opt_outs"`
+	OptOutRate   float64 `json:"opt_out_rate"`
+}
@@ -0,0 +1,175 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	// Embedded zone data, as the container images ship without it
+	_ "time/tzdata"
+)
+
+// DefaultTimezone is the timezone of campaigns created without one
+const DefaultTimezone = "UTC"
+
+// locations caches loaded timezones by name; workers look one up for every
+// message of a campaign with delivery windows
+var locations sync.Map
+
+// weekdays maps the day names accepted in delivery windows to time.Weekday
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// DeliveryWindow allows sending on the given days between StartHour (inclusive)
+// and EndHour (exclusive), in the campaign's timezone
+type DeliveryWindow struct {
+	Days      []string `json:"days"`
+	StartHour int      `json:"start_hour"`
+	EndHour   int      `json:"end_hour"`
+}
+
+// Validate performs validation on a delivery window
+func (w *DeliveryWindow) Validate() error {
+	if len(w.Days) == 0 {
+		return ErrInvalidInput("delivery window days are required")
+	}
+	for _, day := range w.Days {
+		if _, ok := weekdays[strings.ToLower(day)]; !ok {
+			return ErrInvalidInput(fmt.Sprintf("invalid delivery window day: %s (must be one of sun, mon, tue, wed, thu, fri, sat)", day))
+		}
+	}
+	if w.StartHour < 0 || w.StartHour > 23 {
+		return ErrInvalidInput("delivery window start_hour must be between 0 and 23")
+	}
+	if w.EndHour <= w.StartHour || w.EndHour > 24 {
+		return ErrInvalidInput("delivery window end_hour must be after start_hour and at most 24")
+	}
+	return nil
+}
+
+// allows reports whether the window is open on the given weekday
+func (w *DeliveryWindow) allows(day time.Weekday) bool {
+	for _, name := range w.Days {
+		if weekdays[strings.ToLower(name)] == day {
+			return true
+		}
+	}
+	return false
+}
+
+// DeliveryWindows is the set of windows a campaign may send in.
+// An empty set means the campaign may send at any time.
+type DeliveryWindows []DeliveryWindow
+
+// Validate performs validation on every window
+func (ws DeliveryWindows) Validate() error {
+	for i := range ws {
+		if err := ws[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NextOpen returns t if sending is allowed at t, otherwise the start of the
+// next window, with the windows in UTC
+func (ws DeliveryWindows) NextOpen(t time.Time) time.Time {
+	return ws.NextOpenIn(t, time.UTC)
+}
+
+// NextOpenIn returns t if sending is allowed at t, otherwise the start of the
+// next window, with the windows' days and hours taken in loc. Hours that a
+// daylight saving change skips open the window at the first hour after the change.
+func (ws DeliveryWindows) NextOpenIn(t time.Time, loc *time.Location) time.Time {
+	if len(ws) == 0 {
+		return t
+	}
+
+	local := t.In(loc)
+	year, month, date := local.Date()
+
+	var next time.Time
+	for d := 0; d <= 7; d++ {
+		day := time.Date(year, month, date+d, 0, 0, 0, 0, loc)
+		for i := range ws {
+			if !ws[i].allows(day.Weekday()) {
+				continue
+			}
+
+			open := time.Date(year, month, date+d, ws[i].StartHour, 0, 0, 0, loc)
+			end := time.Date(year, month, date+d, ws[i].EndHour, 0, 0, 0, loc)
+			if !t.Before(end) {
+				continue
+			}
+			if !t.Before(open) {
+				return t
+			}
+			if next.IsZero() || open.Before(next) {
+				next = open
+			}
+		}
+		if !next.IsZero() {
+			return next.UTC()
+		}
+	}
+
+	// Unreachable for validated windows; never block sending forever
+	return t
+}
+
+// ValidateTimezone checks that name is an IANA timezone such as Africa/Nairobi
+func ValidateTimezone(name string) error {
+	if name == "" || name == "Local" {
+		return ErrInvalidInput("timezone must be an IANA name such as Africa/Nairobi")
+	}
+	if _, err := loadLocation(name); err != nil {
+		return ErrInvalidInput(fmt.Sprintf("unknown timezone: %s", name))
+	}
+	return nil
+}
+
+// loadLocation loads the named timezone, caching it
+func loadLocation(name string) (*time.Location, error) {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	locations.Store(name, loc)
+	return loc, nil
+}
+
+// Value implements driver.Valuer, storing the windows as JSON
+func (ws DeliveryWindows) Value() (driver.Value, error) {
+	if len(ws) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(ws)
+}
+
+// Scan implements sql.Scanner for JSON stored windows
+func (ws *DeliveryWindows) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*ws = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, ws)
+	case string:
+		return json.Unmarshal([]byte(v), ws)
+	default:
+		return fmt.Errorf("cannot scan %T into DeliveryWindows", src)
+	}
+}
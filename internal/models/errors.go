@@ -55,3 +55,28 @@ func ErrConflictWithMsg(message string) error {
 		Err:     ErrConflict,
 	}
 }
+
+// ErrConfirmationRequired creates an error for an action that must be repeated
+// with an explicit confirmation
+func ErrConfirmationRequired(message string) error {
+	return &AppError{
+		Code:    "CONFIRMATION_REQUIRED",
+		Message: message,
+	}
+}
+
+// ErrUnauthorized creates an error for a request without valid credentials
+func ErrUnauthorized(message string) error {
+	return &AppError{
+		Code:    "UNAUTHORIZED",
+		Message: message,
+	}
+}
+
+// ErrForbidden creates an error for a request whose credentials do not allow it
+func ErrForbidden(message string) error {
+	return &AppError{
+		Code:    "FORBIDDEN",
+		Message: message,
+	}
+}
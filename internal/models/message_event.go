@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Message event type constants, in the order a message usually meets them
+const (
+	MessageEventQueued    = "queued"
+	MessageEventAttempted = "attempted"
+	// MessageEventRetried is a failed attempt, or a soft bounce, that will be
+	// sent again
+	MessageEventRetried     = "retried"
+	MessageEventFailed      = "failed"
+	MessageEventSent        = "sent"
+	MessageEventDelivered   = "delivered"
+	MessageEventUndelivered = "undelivered"
+	MessageEventSkipped     = "skipped"
+)
+
+// MessageEvent is one step in the send history of a message
+type MessageEvent struct {
+	ID        int64  `json:"id"`
+	MessageID int64  `json:"message_id"`
+	Type      string `json:"type"`
+	// Detail is the error of a failed attempt, the provider's message ID of
+	// a send or similar context
+	Detail    *string   `json:"detail,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxMessageNoteLength is the maximum length of a message note
+const MaxMessageNoteLength = 2000
+
+// MessageNote is a support agent's note on a message, such as the outcome of
+// an investigation into its delivery
+type MessageNote struct {
+	ID        int64 `json:"id"`
+	MessageID int64 `json:"message_id"`
+	// Author is the email of the user who wrote the note; nil when
+	// authentication is disabled
+	Author    *string   `json:"author"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Validate performs basic validation on note data
+func (n *MessageNote) Validate() error {
+	if strings.TrimSpace(n.Body) == "" || len(n.Body) > MaxMessageNoteLength {
+		return ErrInvalidInput(fmt.Sprintf("body must be between 1 and %d characters", MaxMessageNoteLength))
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// MessageReceipt is the compact record of a message that has left the queue,
+// returned to clients reconciling their own send records. The recipient is
+// identified only by a hash of their phone number.
+type MessageReceipt struct {
+	MessageID         int64     `json:"message_id"`
+	CampaignID        int64     `json:"campaign_id"`
+	PhoneHash         string    `json:"phone_hash"`
+	Status            string    `json:"status"`
+	ProviderMessageID *string   `json:"provider_message_id,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// HashPhone returns the hex-encoded SHA-256 of an E.164 phone number, so
+// clients can match receipts against numbers they hold without the API
+// returning the numbers themselves
+func HashPhone(phone string) string {
+	sum := sha256.Sum256([]byte(phone))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Message template limits
+const (
+	MaxTemplateNameLength    = 100
+	MaxTemplateContentLength = 5000
+)
+
+// MessageTemplate is a named base template that campaigns are created from.
+// Every change to its content adds a version.
+type MessageTemplate struct {
+	ID        int64     `json:"id"`
+	AccountID int64     `json:"-"`
+	Name      string    `json:"name"`
+	Content   string    `json:"content"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// MessageTemplateVersion is the content of a message template at one version
+type MessageTemplateVersion struct {
+	TemplateID int64     `json:"template_id"`
+	Version    int       `json:"version"`
+	Content    string    `json:"content"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Validate performs basic validation on template data. Placeholders in the
+// content are checked by the template service.
+func (t *MessageTemplate) Validate() error {
+	if strings.TrimSpace(t.Name) == "" || len(t.Name) > MaxTemplateNameLength {
+		return ErrInvalidInput(fmt.Sprintf("name must be between 1 and %d characters", MaxTemplateNameLength))
+	}
+	if t.Content == "" || len(t.Content) > MaxTemplateContentLength {
+		return ErrInvalidInput(fmt.Sprintf("content must be between 1 and %d characters", MaxTemplateContentLength))
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// NumberLookupTTL is how long a number lookup result is trusted before the
+// number is looked up again
+const NumberLookupTTL = 30 * 24 * time.Hour
+
+// NumberLookup is what a number lookup (HLR) provider reported about a phone
+type NumberLookup struct {
+	Phone string `json:"phone"`
+	// Reachable reports whether messages to the number can be delivered
+	Reachable bool `json:"reachable"`
+	// Status is the provider's word on the number, such as "mobile",
+	// "landline" or "invalid"
+	Status    string    `json:"status"`
+	CheckedAt time.Time `json:"checked_at"`
+}
@@ -5,47 +5,128 @@ import "time"
 // Outbound message status constants
 const (
 	MessageStatusPending = "pending"
+	MessageStatusSending = "sending" // Claimed by a worker, send in flight
 	MessageStatusSent    = "sent"
 	MessageStatusFailed  = "failed"
+	// Final statuses from the provider's delivery report of a sent message
+	MessageStatusDelivered   = "delivered"
+	MessageStatusUndelivered = "undelivered"
+	// MessageStatusSkipped is final for a message whose campaign was cancelled before it was sent
+	MessageStatusSkipped = "skipped"
 )
 
 // OutboundMessage represents a message to be sent to a customer
 type OutboundMessage struct {
-	ID              int64     `json:"id"`
-	CampaignID      int64     `json:"campaign_id"`
-	CustomerID      int64     `json:"customer_id"`
-	Status          string    `json:"status"`
-	RenderedContent string    `json:"rendered_content"`
-	LastError       *string   `json:"last_error,omitempty"`
-	RetryCount      int       `json:"retry_count"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ID              int64   `json:"id"`
+	CampaignID      int64   `json:"campaign_id"`
+	CustomerID      int64   `json:"customer_id"`
+	Status          string  `json:"status"`
+	RenderedContent string  `json:"rendered_content"`
+	LastError       *string `json:"last_error,omitempty"`
+	RetryCount      int     `json:"retry_count"`
+	// ProviderMessageID is the provider's ID for the sent message, used by delivery reports
+	ProviderMessageID *string `json:"provider_message_id,omitempty"`
+	// ContentRedactedAt is set once RenderedContent was cleared by the retention policy
+	ContentRedactedAt *time.Time `json:"content_redacted_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
 }
 
 // OutboundMessageFilter holds filtering options for listing messages
 type OutboundMessageFilter struct {
 	CampaignID int64
 	CustomerID int64
+	Phone      string // Recipient's exact phone number; only used when listing recipients
 	Status     string
 	Page       int
 	PageSize   int
 }
 
+// MessageRecipient is an outbound message together with the customer it is addressed to
+type MessageRecipient struct {
+	*OutboundMessage
+	Phone     string `json:"phone"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+// FailedMessageWindow selects failed messages for bulk remediation: those
+// that last failed between From (inclusive) and To (exclusive) with an error
+// containing ErrorContains, ignoring case. Channel, when set, keeps only the
+// messages of campaigns on that channel.
+type FailedMessageWindow struct {
+	From          time.Time
+	To            time.Time
+	ErrorContains string
+	Channel       string
+}
+
+// FailedRecipient is a failed or undelivered message together with the
+// customer it was addressed to, as exported for a follow-up campaign
+type FailedRecipient struct {
+	MessageID  int64
+	CustomerID int64
+	ExternalID *string
+	Phone      string
+	FirstName  string
+	LastName   string
+	Status     string
+	LastError  *string
+	RetryCount int
+	// CreatedAt is when the message was queued, UpdatedAt when it last failed
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// MessageJobVersion is the payload version written by this build.
+// Bump it (and register an upgrader in the queue package) whenever the
+// MessageJob payload changes shape.
+const MessageJobVersion = 1
+
 // MessageJob represents a job to be queued for processing
 type MessageJob struct {
+	Version           int   `json:"version"`
 	OutboundMessageID int64 `json:"outbound_message_id"`
+	// CampaignID lets the jobs of a cancelled campaign be removed from the
+	// queue. It is optional; jobs without it are skipped by the worker instead.
+	CampaignID int64 `json:"campaign_id,omitempty"`
+	// ReadyAt is when the job became ready to be taken, stamped by the queue
+	// on publish. It only measures queue lag, so it is optional and older
+	// payloads without it are read as before.
+	ReadyAt *time.Time `json:"ready_at,omitempty"`
+	// TraceContext is the W3C trace context of the request that queued the
+	// job, so the worker's span can link to it. Like ReadyAt it is optional.
+	TraceContext map[string]string `json:"trace_context,omitempty"`
 }
 
 // IsValidMessageStatus checks if the message status is valid
 func IsValidMessageStatus(status string) bool {
 	switch status {
-	case MessageStatusPending, MessageStatusSent, MessageStatusFailed:
+	case MessageStatusPending, MessageStatusSending, MessageStatusSent, MessageStatusFailed,
+		MessageStatusDelivered, MessageStatusUndelivered, MessageStatusSkipped:
 		return true
 	default:
 		return false
 	}
 }
 
+// WasSent reports whether the provider has accepted the message, whatever
+// its delivery report said since
+func (m *OutboundMessage) WasSent() bool {
+	return m.Status == MessageStatusSent || m.Status == MessageStatusDelivered || m.Status == MessageStatusUndelivered
+}
+
+// DeliveryReport is a provider's final word on a sent message
+type DeliveryReport struct {
+	ProviderMessageID string
+	// Status is MessageStatusDelivered or MessageStatusUndelivered
+	Status string
+	// Reason explains an undelivered message, when the provider gives one
+	Reason *string
+	// Bounce is BounceSoft or BounceHard for an undelivered message
+	Bounce string
+}
+
 // CanRetry checks if a message can be retried
 func (m *OutboundMessage) CanRetry(maxRetries int) bool {
 	return m.Status == MessageStatusFailed && m.RetryCount < maxRetries
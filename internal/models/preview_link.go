@@ -0,0 +1,38 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// Preview link lifetimes
+const (
+	DefaultPreviewLinkTTL = 72 * time.Hour
+	MaxPreviewLinkTTL     = 30 * 24 * time.Hour
+)
+
+// PreviewLink grants read-only access to a campaign's device preview to
+// anyone holding its token, until it expires
+type PreviewLink struct {
+	Token      string    `json:"token"`
+	CampaignID int64     `json:"campaign_id"`
+	AccountID  int64     `json:"-"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Expired reports whether the link can no longer be used at now
+func (l *PreviewLink) Expired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
+
+// NewPreviewToken returns a random, URL-safe preview link token
+func NewPreviewToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate preview token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
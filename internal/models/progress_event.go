@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Progress event types
+const (
+	// ProgressEventMessage reports that a message reached a status
+	ProgressEventMessage = "message"
+	// ProgressEventCampaign reports that a campaign's status changed
+	ProgressEventCampaign = "campaign"
+)
+
+// ProgressEvent is a change in a campaign's progress, published by workers so
+// that every API server can update the progress streams it serves
+type ProgressEvent struct {
+	Type       string    `json:"type"`
+	CampaignID int64     `json:"campaign_id"`
+	MessageID  int64     `json:"message_id,omitempty"`
+	Status     string    `json:"status"`
+	At         time.Time `json:"at"`
+}
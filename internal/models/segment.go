@@ -0,0 +1,103 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// MaxSegmentNameLength is the maximum length of a segment name
+const MaxSegmentNameLength = 255
+
+// Segment is a saved customer filter. Sends that reference it reach the
+// customers matching the filter at the time of the send.
+type Segment struct {
+	ID          int64         `json:"id"`
+	AccountID   int64         `json:"-"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Filter      SegmentFilter `json:"filter"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+// Validate performs basic validation on segment data
+func (s *Segment) Validate() error {
+	if s.Name == "" || len(s.Name) > MaxSegmentNameLength {
+		return ErrInvalidInput(fmt.Sprintf("name must be between 1 and %d characters", MaxSegmentNameLength))
+	}
+	return s.Filter.Validate()
+}
+
+// SegmentCount is the number of customers a segment matched when it was last
+// counted
+type SegmentCount struct {
+	SegmentID     int64     `json:"segment_id"`
+	CustomerCount int64     `json:"customer_count"`
+	CountedAt     time.Time `json:"counted_at"`
+}
+
+// SegmentFilter selects customers by their profile. A customer matches when
+// every criterion that is set matches: location and preferred_product exactly,
+// phone_prefix as the start of the phone number, and each of the conditions.
+type SegmentFilter struct {
+	Location         string             `json:"location,omitempty"`
+	PreferredProduct string             `json:"preferred_product,omitempty"`
+	PhonePrefix      string             `json:"phone_prefix,omitempty"`
+	Conditions       []SegmentCondition `json:"conditions,omitempty"`
+}
+
+// Validate checks that the filter sets at least one criterion; a segment of
+// every customer is target "all"
+func (f *SegmentFilter) Validate() error {
+	if f.Location == "" && f.PreferredProduct == "" && f.PhonePrefix == "" && len(f.Conditions) == 0 {
+		return ErrInvalidInput("filter must set at least one of location, preferred_product, phone_prefix or conditions")
+	}
+	if f.PhonePrefix != "" && !isPhonePrefix(f.PhonePrefix) {
+		return ErrInvalidInput(fmt.Sprintf("invalid phone_prefix %q (must be digits, optionally starting with '+')", f.PhonePrefix))
+	}
+	if len(f.Conditions) > MaxSegmentConditions {
+		return ErrInvalidInput(fmt.Sprintf("filter can have at most %d conditions", MaxSegmentConditions))
+	}
+	for _, condition := range f.Conditions {
+		if err := condition.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isPhonePrefix reports whether prefix is digits with an optional leading '+'
+func isPhonePrefix(prefix string) bool {
+	digits := prefix
+	if digits[0] == '+' {
+		digits = digits[1:]
+	}
+	if digits == "" {
+		return false
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Value implements driver.Valuer, storing the filter as JSON
+func (f SegmentFilter) Value() (driver.Value, error) {
+	return json.Marshal(f)
+}
+
+// Scan implements sql.Scanner for JSON stored filters
+func (f *SegmentFilter) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, f)
+	case string:
+		return json.Unmarshal([]byte(v), f)
+	default:
+		return fmt.Errorf("cannot scan %T into SegmentFilter", src)
+	}
+}
@@ -0,0 +1,172 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// Segment condition operators
+const (
+	SegmentOpEquals      = "equals"
+	SegmentOpContains    = "contains"
+	SegmentOpIn          = "in"
+	SegmentOpExists      = "exists"
+	SegmentOpGreaterThan = "greater_than"
+	SegmentOpLessThan    = "less_than"
+	SegmentOpBefore      = "before"
+	SegmentOpAfter       = "after"
+)
+
+// Limits on segment conditions
+const (
+	MaxSegmentConditions = 20
+	MaxSegmentInValues   = 100
+)
+
+// SegmentTextFields are the customer columns conditions can match as text
+var SegmentTextFields = []string{"first_name", "last_name", "location", "preferred_product", "phone", "external_id"}
+
+// SegmentDateFields are the customer columns conditions can compare as dates
+var SegmentDateFields = []string{"created_at"}
+
+// segmentDateLayouts are the accepted forms of a date value
+var segmentDateLayouts = []string{time.DateOnly, time.RFC3339}
+
+// SegmentCondition matches customers by a field and an operator. Field names
+// a customer column or, when it is not one, a custom attribute key.
+// Attributes are stored as text, so greater_than and less_than compare those
+// that hold a number and before and after those that hold a date; other
+// values never match.
+type SegmentCondition struct {
+	Field    string `json:"field"`
+	Operator string `json:"operator"`
+	// Value is a string for equals and contains, a list of strings for in, a
+	// number for greater_than and less_than, and a date (2006-01-02 or
+	// RFC 3339) for before and after. exists takes none.
+	Value any `json:"value,omitempty"`
+}
+
+// IsAttribute reports whether the condition matches a custom attribute
+// rather than a customer column
+func (c SegmentCondition) IsAttribute() bool {
+	return !slices.Contains(SegmentTextFields, c.Field) && !slices.Contains(SegmentDateFields, c.Field)
+}
+
+// Validate checks the field, that the operator applies to it and that the
+// value suits the operator
+func (c SegmentCondition) Validate() error {
+	if c.Field == "" {
+		return ErrInvalidInput("condition field is required")
+	}
+	if c.IsAttribute() {
+		if err := ValidateAttributeKey(c.Field); err != nil {
+			return ErrInvalidInput(fmt.Sprintf("condition field %q is neither a customer field nor a valid attribute key", c.Field))
+		}
+	}
+
+	var err error
+	switch c.Operator {
+	case SegmentOpEquals, SegmentOpContains:
+		if slices.Contains(SegmentDateFields, c.Field) {
+			return c.unsupported()
+		}
+		var text string
+		if text, err = c.Text(); err == nil && text == "" {
+			err = ErrInvalidInput(fmt.Sprintf("condition on %s: %s needs a non-empty value", c.Field, c.Operator))
+		}
+	case SegmentOpIn:
+		if slices.Contains(SegmentDateFields, c.Field) {
+			return c.unsupported()
+		}
+		_, err = c.List()
+	case SegmentOpExists:
+		if c.Value != nil {
+			err = ErrInvalidInput(fmt.Sprintf("condition on %s: exists takes no value", c.Field))
+		}
+	case SegmentOpGreaterThan, SegmentOpLessThan:
+		if !c.IsAttribute() {
+			return c.unsupported()
+		}
+		_, err = c.Number()
+	case SegmentOpBefore, SegmentOpAfter:
+		if slices.Contains(SegmentTextFields, c.Field) {
+			return c.unsupported()
+		}
+		_, err = c.Time()
+	default:
+		return ErrInvalidInput(fmt.Sprintf("invalid operator %q on %s (must be one of equals, contains, in, exists, greater_than, less_than, before, after)", c.Operator, c.Field))
+	}
+	return err
+}
+
+// unsupported reports an operator that does not apply to the field
+func (c SegmentCondition) unsupported() error {
+	return ErrInvalidInput(fmt.Sprintf("operator %s does not apply to %s", c.Operator, c.Field))
+}
+
+// Text returns the string value of an equals or contains condition
+func (c SegmentCondition) Text() (string, error) {
+	text, ok := c.Value.(string)
+	if !ok {
+		return "", ErrInvalidInput(fmt.Sprintf("condition on %s: %s needs a string value", c.Field, c.Operator))
+	}
+	return text, nil
+}
+
+// List returns the values of an in condition
+func (c SegmentCondition) List() ([]string, error) {
+	invalid := ErrInvalidInput(fmt.Sprintf("condition on %s: in needs a list of 1 to %d strings", c.Field, MaxSegmentInValues))
+
+	var values []string
+	switch v := c.Value.(type) {
+	case []string:
+		values = v
+	case []any:
+		values = make([]string, 0, len(v))
+		for _, item := range v {
+			text, ok := item.(string)
+			if !ok {
+				return nil, invalid
+			}
+			values = append(values, text)
+		}
+	default:
+		return nil, invalid
+	}
+	if len(values) == 0 || len(values) > MaxSegmentInValues {
+		return nil, invalid
+	}
+	return values, nil
+}
+
+// Number returns the value of a greater_than or less_than condition
+func (c SegmentCondition) Number() (float64, error) {
+	var number float64
+	switch v := c.Value.(type) {
+	case float64:
+		number = v
+	case int:
+		number = float64(v)
+	default:
+		return 0, ErrInvalidInput(fmt.Sprintf("condition on %s: %s needs a number value", c.Field, c.Operator))
+	}
+	if math.IsNaN(number) || math.IsInf(number, 0) {
+		return 0, ErrInvalidInput(fmt.Sprintf("condition on %s: %s needs a finite number", c.Field, c.Operator))
+	}
+	return number, nil
+}
+
+// Time returns the value of a before or after condition. A date without a
+// time is midnight UTC.
+func (c SegmentCondition) Time() (time.Time, error) {
+	if text, ok := c.Value.(string); ok {
+		for _, layout := range segmentDateLayouts {
+			if t, err := time.Parse(layout, text); err == nil {
+				return t.UTC(), nil
+			}
+		}
+	}
+	return time.Time{}, ErrInvalidInput(fmt.Sprintf("condition on %s: %s needs a date such as 2025-06-01 or 2025-06-01T09:00:00Z", c.Field, c.Operator))
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSegmentFilter_ValidateConditions(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  string
+		wantErr bool
+	}{
+		{name: "conditions alone", filter: `{"conditions":[{"field":"tier","operator":"equals","value":"gold"}]}`},
+		{name: "core contains", filter: `{"conditions":[{"field":"first_name","operator":"contains","value":"ann"}]}`},
+		{name: "in list", filter: `{"conditions":[{"field":"location","operator":"in","value":["Nairobi","Mombasa"]}]}`},
+		{name: "exists", filter: `{"conditions":[{"field":"email","operator":"exists"}]}`},
+		{name: "number", filter: `{"conditions":[{"field":"orders","operator":"greater_than","value":3}]}`},
+		{name: "date", filter: `{"conditions":[{"field":"created_at","operator":"after","value":"2025-06-01"}]}`},
+		{name: "timestamp", filter: `{"conditions":[{"field":"last_order","operator":"before","value":"2025-06-01T09:00:00+03:00"}]}`},
+		{name: "unknown operator", filter: `{"conditions":[{"field":"tier","operator":"like","value":"gold"}]}`, wantErr: true},
+		{name: "invalid attribute key", filter: `{"conditions":[{"field":"tier level","operator":"equals","value":"gold"}]}`, wantErr: true},
+		{name: "empty equals", filter: `{"conditions":[{"field":"tier","operator":"equals","value":""}]}`, wantErr: true},
+		{name: "empty in", filter: `{"conditions":[{"field":"tier","operator":"in","value":[]}]}`, wantErr: true},
+		{name: "number as text", filter: `{"conditions":[{"field":"orders","operator":"greater_than","value":"3"}]}`, wantErr: true},
+		{name: "number on a column", filter: `{"conditions":[{"field":"location","operator":"greater_than","value":3}]}`, wantErr: true},
+		{name: "date on a text column", filter: `{"conditions":[{"field":"phone","operator":"before","value":"2025-06-01"}]}`, wantErr: true},
+		{name: "text on a date column", filter: `{"conditions":[{"field":"created_at","operator":"equals","value":"2025-06-01"}]}`, wantErr: true},
+		{name: "bad date", filter: `{"conditions":[{"field":"created_at","operator":"after","value":"June 1"}]}`, wantErr: true},
+		{name: "exists with value", filter: `{"conditions":[{"field":"email","operator":"exists","value":"x"}]}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var filter SegmentFilter
+			if err := json.Unmarshal([]byte(tt.filter), &filter); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if err := filter.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package models
+
+import (
+	"strings"
+	"unicode/utf16"
+)
+
+// GSM 03.38 character sets. Extension characters take two septets.
+const (
+	gsm7Basic     = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+	gsm7Extension = "^{}\\[~]|€\f"
+)
+
+// SMS encodings. GSM-7 fits 160 characters in a single segment and 153 in each
+// part of a longer message; UCS-2 fits 70 and 67.
+const (
+	SMSEncodingGSM7 = "GSM-7"
+	SMSEncodingUCS2 = "UCS-2"
+)
+
+// gsm7Substitutes replaces common characters outside the GSM 7-bit alphabet,
+// mostly inserted by word processors and phone keyboards, with GSM look-alikes
+var gsm7Substitutes = strings.NewReplacer(
+	"\u2018", "'", // left single quote
+	"\u2019", "'", // right single quote, apostrophe
+	"\u201A", "'", // low single quote
+	"\u201C", "\"", // left double quote
+	"\u201D", "\"", // right double quote
+	"\u201E", "\"", // low double quote
+	"\u2013", "-", // en dash
+	"\u2014", "-", // em dash
+	"\u2212", "-", // minus sign
+	"\u2026", "...", // ellipsis
+	"\u00A0", " ", // non-breaking space
+	"\u2009", " ", // thin space
+	"\u200B", "", // zero-width space
+	"\u2022", "-", // bullet
+)
+
+// SMSSegments returns the number of SMS segments needed to deliver content.
+// Text that fits the GSM 7-bit alphabet takes 160 characters in one segment and
+// 153 per segment once split; any other character switches the whole message
+// to UCS-2, which takes 70 and 67 UTF-16 units.
+func SMSSegments(content string) int {
+	if content == "" {
+		return 0
+	}
+
+	encoding, length := SMSLength(content)
+	if encoding == SMSEncodingUCS2 {
+		return segmentCount(length, 70, 67)
+	}
+	return segmentCount(length, 160, 153)
+}
+
+// SMSLength returns the encoding content is sent in and its length in that
+// encoding's units: septets for GSM-7, where extension characters such as €
+// take two, and UTF-16 units for UCS-2, where emoji take two
+func SMSLength(content string) (encoding string, length int) {
+	septets := 0
+	for _, r := range content {
+		switch {
+		case strings.ContainsRune(gsm7Basic, r):
+			septets++
+		case strings.ContainsRune(gsm7Extension, r):
+			septets += 2
+		default:
+			return SMSEncodingUCS2, len(utf16.Encode([]rune(content)))
+		}
+	}
+
+	return SMSEncodingGSM7, septets
+}
+
+// MessageCost returns the price of sending content on channel at rate, which
+// is per SMS segment on SMS and per message on other channels
+func MessageCost(channel, content string, rate float64) float64 {
+	if channel == ChannelSMS {
+		return rate * float64(SMSSegments(content))
+	}
+	return rate
+}
+
+// segmentCount splits length units into segments of single, or of multi once
+// the message does not fit a single segment
+func segmentCount(length, single, multi int) int {
+	if length <= single {
+		return 1
+	}
+	return (length + multi - 1) / multi
+}
+
+// SMSEncoding returns the encoding content is sent in and the characters that
+// fit in a single segment and in each part of a multipart message
+func SMSEncoding(content string) (encoding string, single, multi int) {
+	if len(UCS2Characters(content)) > 0 {
+		return SMSEncodingUCS2, 70, 67
+	}
+	return SMSEncodingGSM7, 160, 153
+}
+
+// UCS2Characters returns the distinct characters of content outside the GSM
+// 7-bit alphabet, in order of first appearance. Any one of them sends the
+// whole message as UCS-2.
+func UCS2Characters(content string) []string {
+	var found []string
+	seen := make(map[rune]bool)
+	for _, r := range content {
+		if seen[r] || strings.ContainsRune(gsm7Basic, r) || strings.ContainsRune(gsm7Extension, r) {
+			continue
+		}
+		seen[r] = true
+		found = append(found, string(r))
+	}
+	return found
+}
+
+// SubstituteGSM7 replaces smart quotes, dashes, ellipses and unusual spaces
+// with their GSM 7-bit equivalents. Other characters, such as emoji or
+// non-Latin letters, are left as they are.
+func SubstituteGSM7(content string) string {
+	return gsm7Substitutes.Replace(content)
+}
+
+// SplitSMSSegments splits content into the parts it is delivered in. An
+// extension character or a UTF-16 surrogate pair is never split across
+// parts, as handsets could not reassemble it, so a message whose boundary
+// falls inside one can take a part more than SMSSegments counts.
+func SplitSMSSegments(content string) []string {
+	if content == "" {
+		return nil
+	}
+
+	encoding, single, multi := SMSEncoding(content)
+	units := func(r rune) int {
+		if encoding == SMSEncodingUCS2 {
+			return len(utf16.Encode([]rune{r}))
+		}
+		if strings.ContainsRune(gsm7Extension, r) {
+			return 2
+		}
+		return 1
+	}
+
+	total := 0
+	for _, r := range content {
+		total += units(r)
+	}
+	if total <= single {
+		return []string{content}
+	}
+
+	var parts []string
+	start, used := 0, 0
+	for i, r := range content {
+		n := units(r)
+		if used+n > multi {
+			parts = append(parts, content[start:i])
+			start, used = i, 0
+		}
+		used += n
+	}
+	return append(parts, content[start:])
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSMSSegments(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int
+	}{
+		{name: "empty", content: "", want: 0},
+		{name: "short gsm", content: "Hi Alice, 20% off today!", want: 1},
+		{name: "gsm at limit", content: strings.Repeat("a", 160), want: 1},
+		{name: "gsm split", content: strings.Repeat("a", 161), want: 2},
+		{name: "gsm three parts", content: strings.Repeat("a", 307), want: 3},
+		{name: "extension chars count twice", content: strings.Repeat("€", 80), want: 1},
+		{name: "extension chars split", content: strings.Repeat("€", 81), want: 2},
+		{name: "unicode at limit", content: strings.Repeat("ł", 70), want: 1},
+		{name: "unicode split", content: strings.Repeat("ł", 71), want: 2},
+		{name: "emoji are two units", content: strings.Repeat("🎉", 35), want: 1},
+		{name: "one emoji switches encoding", content: strings.Repeat("a", 100) + "🎉", want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SMSSegments(tt.content); got != tt.want {
+				t.Errorf("SMSSegments() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSMSLength(t *testing.T) {
+	tests := []struct {
+		content      string
+		wantEncoding string
+		wantLength   int
+	}{
+		{content: "", wantEncoding: SMSEncodingGSM7, wantLength: 0},
+		{content: "Hi Alice", wantEncoding: SMSEncodingGSM7, wantLength: 8},
+		{content: "Only 5€", wantEncoding: SMSEncodingGSM7, wantLength: 8},
+		{content: "Zoë’s", wantEncoding: SMSEncodingUCS2, wantLength: 5},
+		{content: "Party 🎉", wantEncoding: SMSEncodingUCS2, wantLength: 8},
+	}
+
+	for _, tt := range tests {
+		encoding, length := SMSLength(tt.content)
+		if encoding != tt.wantEncoding || length != tt.wantLength {
+			t.Errorf("SMSLength(%q) = %s %d, want %s %d", tt.content, encoding, length, tt.wantEncoding, tt.wantLength)
+		}
+	}
+}
+
+func TestUCS2Characters(t *testing.T) {
+	got := UCS2Characters("Don’t miss out — 20% off 🎉 at Café Zoë’s")
+	want := []string{"’", "—", "🎉", "ë"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("UCS2Characters() = %q, want %q", got, want)
+	}
+
+	if got := UCS2Characters("Hi {first_name}, €5 off at Café Müller"); len(got) != 0 {
+		t.Errorf("UCS2Characters() on GSM-7 text = %q, want none", got)
+	}
+}
+
+func TestSubstituteGSM7(t *testing.T) {
+	got := SubstituteGSM7("“Don’t wait” – offer ends soon…")
+	if want := "\"Don't wait\" - offer ends soon..."; got != want {
+		t.Errorf("SubstituteGSM7() = %q, want %q", got, want)
+	}
+	if encoding, single, _ := SMSEncoding(got); encoding != SMSEncodingGSM7 || single != 160 {
+		t.Errorf("SMSEncoding() = %s/%d, want GSM-7/160", encoding, single)
+	}
+
+	// Emoji have no GSM-7 equivalent
+	if got := SubstituteGSM7("Party time 🎉"); got != "Party time 🎉" {
+		t.Errorf("SubstituteGSM7() = %q, want emoji kept", got)
+	}
+}
+
+func TestSplitSMSSegments(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []int // rune length of each part
+	}{
+		{name: "empty", content: "", want: nil},
+		{name: "single", content: strings.Repeat("a", 160), want: []int{160}},
+		{name: "gsm split", content: strings.Repeat("a", 307), want: []int{153, 153, 1}},
+		{name: "extension char kept whole", content: strings.Repeat("a", 152) + "€" + strings.Repeat("a", 10), want: []int{152, 11}},
+		{name: "unicode split", content: strings.Repeat("ł", 71), want: []int{67, 4}},
+		{name: "surrogate pair kept whole", content: strings.Repeat("ł", 66) + "🎉" + strings.Repeat("ł", 5), want: []int{66, 6}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts := SplitSMSSegments(tt.content)
+			got := make([]int, 0, len(parts))
+			for _, part := range parts {
+				got = append(got, len([]rune(part)))
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("SplitSMSSegments() part lengths = %v, want %v", got, tt.want)
+			}
+			if strings.Join(parts, "") != tt.content {
+				t.Error("SplitSMSSegments() parts do not join back to the content")
+			}
+		})
+	}
+}
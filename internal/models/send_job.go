@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Send job statuses
+const (
+	// SendJobStatusAwaitingApproval is a send whose messages are built and
+	// held until a second user approves it
+	SendJobStatusAwaitingApproval = "awaiting_second_approval"
+	// SendJobStatusApproved is a send a second user approved, whose messages
+	// were released to the queue
+	SendJobStatusApproved = "approved"
+)
+
+// SendJob is a send to an audience above the approval threshold. Its
+// messages are not queued until a user other than the one who requested it
+// approves it.
+type SendJob struct {
+	ID         int64  `json:"id"`
+	AccountID  int64  `json:"-"`
+	CampaignID int64  `json:"campaign_id"`
+	Status     string `json:"status"`
+	// RecipientCount is the size of the audience before exclusions, as
+	// checked against the approval threshold
+	RecipientCount int64 `json:"recipient_count"`
+	// RequestedBy is the user who sent the campaign; nil for sends by the
+	// scheduler, which any other user can approve
+	RequestedBy *int64     `json:"requested_by"`
+	ApprovedBy  *int64     `json:"approved_by,omitempty"`
+	ApprovedAt  *time.Time `json:"approved_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// RequestedByUser reports whether userID requested the send
+func (j *SendJob) RequestedByUser(userID int64) bool {
+	return j.RequestedBy != nil && *j.RequestedBy == userID
+}
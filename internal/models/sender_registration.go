@@ -0,0 +1,131 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Sender registration types
+const (
+	// RegistrationTypeSenderID registers the sender ID itself with a country's
+	// carriers or regulator, as alphanumeric sender ID and DLT header schemes do
+	RegistrationTypeSenderID = "sender_id"
+	// RegistrationTypeCampaign links the sending number to a registered
+	// messaging campaign, as in 10DLC-style regimes
+	RegistrationTypeCampaign = "campaign"
+)
+
+// MaxRegistrationIDLength is the maximum length of a registration ID
+const MaxRegistrationIDLength = 100
+
+// SenderRegistration records that a sender ID is registered to send SMS to a
+// destination country
+type SenderRegistration struct {
+	SenderID string `json:"sender_id"`
+	// Country is the ISO 3166-1 alpha-2 code of the destination country
+	Country string `json:"country"`
+	Type    string `json:"type"`
+	// RegistrationID is the reference issued by the registry, e.g. a 10DLC
+	// campaign ID or a DLT header ID
+	RegistrationID string    `json:"registration_id"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// Validate performs validation on sender registration data
+func (r *SenderRegistration) Validate() error {
+	if r.SenderID == "" || len(r.SenderID) > 32 {
+		return ErrInvalidInput("sender_id must be between 1 and 32 characters")
+	}
+	if !IsCountryCode(r.Country) {
+		return ErrInvalidInput(fmt.Sprintf("invalid country %q (must be an ISO 3166-1 alpha-2 code such as KE)", r.Country))
+	}
+	if r.Type != RegistrationTypeSenderID && r.Type != RegistrationTypeCampaign {
+		return ErrInvalidInput(fmt.Sprintf("invalid type %q (must be '%s' or '%s')", r.Type, RegistrationTypeSenderID, RegistrationTypeCampaign))
+	}
+	if r.RegistrationID == "" || len(r.RegistrationID) > MaxRegistrationIDLength {
+		return ErrInvalidInput(fmt.Sprintf("registration_id must be between 1 and %d characters", MaxRegistrationIDLength))
+	}
+	return nil
+}
+
+// IsCountryCode reports whether code looks like an ISO 3166-1 alpha-2 code
+func IsCountryCode(code string) bool {
+	if len(code) != 2 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// SenderRegistrationRule is what a destination country requires of a sender
+type SenderRegistrationRule struct {
+	Country string `json:"country"`
+	// DialCode is the country calling code that destination phones start with
+	DialCode string `json:"dial_code"`
+	Type     string `json:"type"`
+	// AlphanumericOnly limits the rule to alphanumeric sender IDs; numeric
+	// senders are not checked
+	AlphanumericOnly bool `json:"alphanumeric_only"`
+}
+
+// Applies reports whether the rule covers a sender ID
+func (r SenderRegistrationRule) Applies(senderID string) bool {
+	return !r.AlphanumericOnly || IsAlphanumericSender(senderID)
+}
+
+// SenderRegistrationRules are the registration regimes the worker can enforce,
+// keyed by country. Which of them are enforced is configured, since whether a
+// rule binds depends on the account and route as well as the country.
+var SenderRegistrationRules = map[string]SenderRegistrationRule{
+	"US": {Country: "US", DialCode: "+1", Type: RegistrationTypeCampaign},
+	"IN": {Country: "IN", DialCode: "+91", Type: RegistrationTypeSenderID},
+	"KE": {Country: "KE", DialCode: "+254", Type: RegistrationTypeSenderID, AlphanumericOnly: true},
+	"NG": {Country: "NG", DialCode: "+234", Type: RegistrationTypeSenderID, AlphanumericOnly: true},
+	"AE": {Country: "AE", DialCode: "+971", Type: RegistrationTypeSenderID, AlphanumericOnly: true},
+	"SA": {Country: "SA", DialCode: "+966", Type: RegistrationTypeSenderID, AlphanumericOnly: true},
+}
+
+// RuleForPhone returns the rule among rules whose dial code the phone starts
+// with, preferring the longest dial code
+func RuleForPhone(rules []SenderRegistrationRule, phone string) (SenderRegistrationRule, bool) {
+	var match SenderRegistrationRule
+	found := false
+	for _, rule := range rules {
+		if strings.HasPrefix(phone, rule.DialCode) && len(rule.DialCode) > len(match.DialCode) {
+			match = rule
+			found = true
+		}
+	}
+	return match, found
+}
+
+// IsAlphanumericSender reports whether a sender ID contains letters, as
+// opposed to a phone number or short code
+func IsAlphanumericSender(senderID string) bool {
+	for _, r := range senderID {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// EnforcedRegistrationRules looks up the rules for a list of countries
+func EnforcedRegistrationRules(countries []string) ([]SenderRegistrationRule, error) {
+	rules := make([]SenderRegistrationRule, 0, len(countries))
+	for _, country := range countries {
+		rule, ok := SenderRegistrationRules[country]
+		if !ok {
+			return nil, fmt.Errorf("no sender registration rule for country %q", country)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
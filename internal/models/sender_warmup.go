@@ -0,0 +1,61 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Warm-up ramp defaults for newly provisioned sender IDs
+const (
+	DefaultWarmupInitialDailyCap = 200
+	DefaultWarmupMaxDailyCap     = 50000
+)
+
+// SenderWarmup is a warm-up ramp policy for a sender ID.
+// The daily cap starts at InitialDailyCap on StartedOn and doubles every day
+// until it reaches MaxDailyCap.
+type SenderWarmup struct {
+	SenderID        string    `json:"sender_id"`
+	StartedOn       time.Time `json:"started_on"`
+	InitialDailyCap int       `json:"initial_daily_cap"`
+	MaxDailyCap     int       `json:"max_daily_cap"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// Validate performs validation on warm-up policy data
+func (w *SenderWarmup) Validate() error {
+	if w.SenderID == "" {
+		return ErrInvalidInput("sender_id is required")
+	}
+	if len(w.SenderID) > 32 {
+		return ErrInvalidInput("sender_id must be at most 32 characters")
+	}
+	if w.InitialDailyCap < 1 {
+		return ErrInvalidInput("initial_daily_cap must be at least 1")
+	}
+	if w.MaxDailyCap < w.InitialDailyCap {
+		return ErrInvalidInput(fmt.Sprintf("max_daily_cap must be at least initial_daily_cap (%d)", w.InitialDailyCap))
+	}
+	return nil
+}
+
+// DailyCap returns the number of messages the sender may send on the UTC day containing now
+func (w *SenderWarmup) DailyCap(now time.Time) int {
+	days := int(StartOfDay(now).Sub(StartOfDay(w.StartedOn)) / (24 * time.Hour))
+
+	limit := w.InitialDailyCap
+	for i := 0; i < days && limit < w.MaxDailyCap; i++ {
+		limit *= 2
+	}
+
+	if limit > w.MaxDailyCap {
+		limit = w.MaxDailyCap
+	}
+	return limit
+}
+
+// StartOfDay truncates t to midnight UTC
+func StartOfDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
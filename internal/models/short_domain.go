@@ -0,0 +1,88 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Short domain health statuses
+const (
+	// ShortDomainStatusUnchecked is a domain not checked since it was added
+	ShortDomainStatusUnchecked = "unchecked"
+	// ShortDomainStatusHealthy is a domain that resolved and answered over
+	// HTTPS when last checked
+	ShortDomainStatusHealthy = "healthy"
+	// ShortDomainStatusUnhealthy is a domain whose last check failed
+	ShortDomainStatusUnhealthy = "unhealthy"
+)
+
+// MaxShortDomainLength is the maximum length of a short domain name
+const MaxShortDomainLength = 253
+
+// ShortDomain is a domain an account uses for the links in its messages
+type ShortDomain struct {
+	ID        int64  `json:"id"`
+	AccountID int64  `json:"-"`
+	Domain    string `json:"domain"`
+	// IsDefault makes the domain the one campaigns fall back to when they
+	// have no healthy domain of their own
+	IsDefault bool `json:"is_default"`
+	// Enabled domains are health checked and can be used; disabling one
+	// takes it out of use without losing the campaigns that chose it
+	Enabled   bool       `json:"enabled"`
+	Status    string     `json:"status"`
+	LastError *string    `json:"last_error,omitempty"`
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// Usable reports whether links can be put on the domain: it is enabled and
+// passed its last health check
+func (d *ShortDomain) Usable() bool {
+	return d.Enabled && d.Status == ShortDomainStatusHealthy
+}
+
+// Validate performs validation on short domain data
+func (d *ShortDomain) Validate() error {
+	if !IsHostname(d.Domain) {
+		return ErrInvalidInput(fmt.Sprintf("invalid domain %q (must be a lowercase hostname such as go.example.com)", d.Domain))
+	}
+	return nil
+}
+
+// IsHostname reports whether name is a lowercase DNS hostname of at least two
+// labels. IP addresses, ports, paths and wildcards are not hostnames.
+func IsHostname(name string) bool {
+	if name == "" || len(name) > MaxShortDomainLength {
+		return false
+	}
+
+	labels := strings.Split(name, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, label := range labels {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+				return false
+			}
+		}
+	}
+
+	// A top-level label of digits is an IPv4 address
+	tld := labels[len(labels)-1]
+	return strings.Trim(tld, "0123456789") != ""
+}
+
+// ShortDomainCheck is the result of checking a short domain's health
+type ShortDomainCheck struct {
+	DomainID  int64
+	Status    string
+	Error     *string
+	CheckedAt time.Time
+}
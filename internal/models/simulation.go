@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Simulation run status constants
+const (
+	SimulationStatusRunning   = "running"
+	SimulationStatusCompleted = "completed"
+	SimulationStatusFailed    = "failed"
+)
+
+// SimulationRun is a dry run of a campaign against the mock sender.
+// Projected timing assumes the audience is processed by WorkerConcurrency
+// workers with the observed average send latency.
+type SimulationRun struct {
+	ID                  int64      `json:"id"`
+	CampaignID          int64      `json:"campaign_id"`
+	Status              string     `json:"status"`
+	AudienceSize        int        `json:"audience_size"`
+	SimulatedSent       int        `json:"simulated_sent"`
+	SimulatedFailed     int        `json:"simulated_failed"`
+	RenderMs            int64      `json:"render_ms"`
+	SendMs              int64      `json:"send_ms"`
+	AvgSendLatencyMs    float64    `json:"avg_send_latency_ms"`
+	WorkerConcurrency   int        `json:"worker_concurrency"`
+	ProjectedDurationMs int64      `json:"projected_duration_ms"`
+	Error               *string    `json:"error,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	CompletedAt         *time.Time `json:"completed_at"`
+}
+
+// SimulatedMessage is the shadow copy of an outbound message produced by a simulation
+type SimulatedMessage struct {
+	SimulationID    int64   `json:"simulation_id"`
+	CustomerID      int64   `json:"customer_id"`
+	Status          string  `json:"status"`
+	RenderedContent string  `json:"rendered_content"`
+	LastError       *string `json:"last_error,omitempty"`
+	LatencyMs       int     `json:"latency_ms"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// CatalogTemplate is a read-only starter template that any account can create
+// a campaign from. The catalog is seeded by migrations.
+type CatalogTemplate struct {
+	Slug        string `json:"slug"`
+	Name        string `json:"name"`
+	Category    string `json:"category"`
+	Description string `json:"description"`
+	// Channel is the channel the content is written for, used by campaigns
+	// created from the template that name none
+	Channel string `json:"channel"`
+	Content string `json:"content"`
+	// Placeholders are the fields the content uses, filled by the service
+	Placeholders []string  `json:"placeholders"`
+	CreatedAt    time.Time `json:"created_at"`
+}
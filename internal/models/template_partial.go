@@ -0,0 +1,43 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Template partial limits
+const (
+	MaxPartialNameLength    = 64
+	MaxPartialContentLength = 1000
+)
+
+// partialNamePattern is the form of a partial name: lowercase letters, digits
+// and underscores, starting with a letter
+var partialNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// TemplatePartial is a named snippet, such as a standard footer, that
+// templates include with {>name}
+type TemplatePartial struct {
+	Name      string    `json:"name"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate performs basic validation on partial data. Placeholders in the
+// content are checked by the template service.
+func (p *TemplatePartial) Validate() error {
+	if len(p.Name) > MaxPartialNameLength || !partialNamePattern.MatchString(p.Name) {
+		return ErrInvalidInput(fmt.Sprintf("invalid name %q (must be up to %d lowercase letters, digits and underscores, starting with a letter)", p.Name, MaxPartialNameLength))
+	}
+	if p.Content == "" || len(p.Content) > MaxPartialContentLength {
+		return ErrInvalidInput(fmt.Sprintf("content must be between 1 and %d characters", MaxPartialContentLength))
+	}
+	// Only templates include partials, which keeps expansion a single pass
+	if strings.Contains(p.Content, "{>") {
+		return ErrInvalidInput("partials cannot include other partials")
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"fmt"
+	"net/mail"
+	"time"
+)
+
+// User roles, from least to most privileged. Each role may do everything the
+// roles before it may.
+const (
+	// RoleViewer can read
+	RoleViewer = "viewer"
+	// RoleEditor can also create and edit
+	RoleEditor = "editor"
+	// RoleSender can also trigger sends
+	RoleSender = "sender"
+	// RoleAdmin can also delete and administer, including users
+	RoleAdmin = "admin"
+)
+
+// roleRanks orders the roles by privilege
+var roleRanks = map[string]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleSender: 3,
+	RoleAdmin:  4,
+}
+
+// MinPasswordLength is the minimum length of a user password
+const MinPasswordLength = 10
+
+// User is an operator who signs in to the API
+type User struct {
+	ID           int64     `json:"id"`
+	AccountID    int64     `json:"account_id"`
+	Email        string    `json:"email"`
+	PasswordHash string    `json:"-"`
+	Role         string    `json:"role"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Validate performs basic validation on user data
+func (u *User) Validate() error {
+	if len(u.Email) > 255 {
+		return ErrInvalidInput("email must be at most 255 characters")
+	}
+	if address, err := mail.ParseAddress(u.Email); err != nil || address.Address != u.Email {
+		return ErrInvalidInput(fmt.Sprintf("invalid email %q", u.Email))
+	}
+	if !IsValidRole(u.Role) {
+		return ErrInvalidInput(fmt.Sprintf("invalid role %q (must be 'viewer', 'editor', 'sender' or 'admin')", u.Role))
+	}
+	return nil
+}
+
+// IsValidRole checks if the role is valid
+func IsValidRole(role string) bool {
+	_, ok := roleRanks[role]
+	return ok
+}
+
+// RoleAllows reports whether role grants everything required grants
+func RoleAllows(role, required string) bool {
+	rank, ok := roleRanks[role]
+	return ok && rank >= roleRanks[required]
+}
@@ -0,0 +1,44 @@
+package models
+
+import "testing"
+
+func TestRoleAllows(t *testing.T) {
+	tests := []struct {
+		role, required string
+		want           bool
+	}{
+		{RoleViewer, RoleViewer, true},
+		{RoleViewer, RoleEditor, false},
+		{RoleEditor, RoleViewer, true},
+		{RoleEditor, RoleSender, false},
+		{RoleSender, RoleEditor, true},
+		{RoleSender, RoleAdmin, false},
+		{RoleAdmin, RoleSender, true},
+		{"owner", RoleViewer, false},
+		{"", RoleViewer, false},
+	}
+
+	for _, tt := range tests {
+		if got := RoleAllows(tt.role, tt.required); got != tt.want {
+			t.Errorf("RoleAllows(%q, %q) = %v, want %v", tt.role, tt.required, got, tt.want)
+		}
+	}
+}
+
+func TestUser_Validate(t *testing.T) {
+	valid := &User{Email: "ops@example.com", Role: RoleEditor}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	invalid := []*User{
+		{Email: "not-an-email", Role: RoleEditor},
+		{Email: "Ops <ops@example.com>", Role: RoleEditor},
+		{Email: "ops@example.com", Role: "owner"},
+	}
+	for _, user := range invalid {
+		if err := user.Validate(); err == nil {
+			t.Errorf("Validate(%+v) error = nil, want error", user)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package pubsub
+
+import (
+	"context"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// Publisher announces campaign progress to every API server
+type Publisher interface {
+	// Publish delivers the event to the servers subscribed at the time;
+	// nothing is stored for servers that subscribe later
+	Publish(ctx context.Context, event *models.ProgressEvent) error
+
+	// Close releases the publisher's connection
+	Close() error
+}
+
+// Bus fans the progress events published by any process out to the local
+// subscribers of each API server, so a progress stream works on whichever
+// replica the load balancer picked
+type Bus interface {
+	Publisher
+
+	// Subscribe returns the events of a campaign until cancel is called.
+	// Events are dropped while the subscriber's buffer is full, so
+	// subscribers must treat an event as a hint to re-read the progress.
+	Subscribe(campaignID int64) (events <-chan *models.ProgressEvent, cancel func())
+
+	// Run receives published events and hands them to local subscribers
+	// until ctx is done
+	Run(ctx context.Context) error
+}
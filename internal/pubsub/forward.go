@@ -0,0 +1,57 @@
+package pubsub
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/events"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// ForwardProgress publishes the progress events that the API's progress
+// streams wait for as message and campaign events happen on bus. Progress
+// streams also poll, so an event that cannot be published is only logged.
+func ForwardProgress(bus *events.Bus, publisher Publisher, logger *slog.Logger) {
+	publish := func(ctx context.Context, event *models.ProgressEvent) {
+		if err := publisher.Publish(ctx, event); err != nil {
+			logger.Warn("failed to publish progress event",
+				slog.Int64("campaign_id", event.CampaignID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	events.Subscribe(bus, func(ctx context.Context, event events.MessageSent) {
+		publish(ctx, &models.ProgressEvent{
+			Type:       models.ProgressEventMessage,
+			CampaignID: event.CampaignID,
+			MessageID:  event.MessageID,
+			Status:     models.MessageStatusSent,
+			At:         event.At,
+		})
+	})
+
+	events.Subscribe(bus, func(ctx context.Context, event events.MessageFailed) {
+		// A message with retries left is pending again
+		status := models.MessageStatusFailed
+		if event.Retrying {
+			status = models.MessageStatusPending
+		}
+		publish(ctx, &models.ProgressEvent{
+			Type:       models.ProgressEventMessage,
+			CampaignID: event.CampaignID,
+			MessageID:  event.MessageID,
+			Status:     status,
+			At:         event.At,
+		})
+	})
+
+	events.Subscribe(bus, func(ctx context.Context, event events.CampaignCompleted) {
+		publish(ctx, &models.ProgressEvent{
+			Type:       models.ProgressEventCampaign,
+			CampaignID: event.CampaignID,
+			Status:     event.Status,
+			At:         event.At,
+		})
+	})
+}
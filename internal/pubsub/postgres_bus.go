@@ -0,0 +1,88 @@
+package pubsub
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/config"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/db"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// postgresBus implements Bus with Postgres LISTEN/NOTIFY. Events are sent
+// over the shared database pool; each process listens on one dedicated
+// connection however many local subscribers it has.
+type postgresBus struct {
+	subscribers
+
+	db      *sql.DB
+	listen  db.Config
+	channel string
+	logger  *slog.Logger
+}
+
+// NewPostgresBus creates a progress event bus on the database. listen is the
+// configuration of database, used to open the listening connection. The
+// database stays open when the bus is closed.
+func NewPostgresBus(database *sql.DB, listen db.Config, channel string, logger *slog.Logger) Bus {
+	return &postgresBus{
+		subscribers: newSubscribers(),
+		db:          database,
+		listen:      listen,
+		channel:     channel,
+		logger:      logger,
+	}
+}
+
+// New creates the bus of the configured queue backend. The channel is named
+// after the queue, so deployments that share a Redis instance or database
+// through different queue names do not see each other's events.
+func New(cfg config.QueueConfig, database *sql.DB, listen db.Config, logger *slog.Logger) (Bus, error) {
+	if cfg.Backend == config.QueueBackendPostgres {
+		return NewPostgresBus(database, listen, cfg.QueueName+":events", logger), nil
+	}
+	return NewRedisBus(RedisConfigFor(cfg), logger)
+}
+
+// Publish notifies the channel of the event
+func (b *postgresBus) Publish(ctx context.Context, event *models.ProgressEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal progress event: %w", err)
+	}
+
+	if _, err := b.db.ExecContext(ctx, `SELECT pg_notify($1, $2)`, b.channel, string(data)); err != nil {
+		return fmt.Errorf("failed to publish progress event: %w", err)
+	}
+
+	return nil
+}
+
+// Run listens on the channel and dispatches its events until ctx is done.
+// The listener reconnects by itself after a dropped connection; events
+// published in the meantime are lost.
+func (b *postgresBus) Run(ctx context.Context) error {
+	return db.Listen(ctx, b.listen, b.channel, b, b.logger)
+}
+
+// Notify implements db.Subscriber, dispatching a notified event
+func (b *postgresBus) Notify(payload string) {
+	var event models.ProgressEvent
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		b.logger.Warn("invalid progress event", slog.String("error", err.Error()))
+		return
+	}
+	b.dispatch(&event)
+}
+
+// Listening implements db.Subscriber. Subscribers re-read the progress on
+// every event, so those missed while the connection was down need no replay.
+func (b *postgresBus) Listening(bool) {}
+
+// Close does nothing; the database belongs to the caller
+func (b *postgresBus) Close() error {
+	return nil
+}
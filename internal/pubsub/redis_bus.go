@@ -0,0 +1,119 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/config"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// RedisConfig holds configuration for the Redis bus
+type RedisConfig struct {
+	URL string
+	// Channel is the Redis pub/sub channel events are published on
+	Channel string
+}
+
+// RedisConfigFor returns the bus settings for cfg. The channel is named after
+// the queue, so deployments that share a Redis instance through different
+// queue names do not see each other's events.
+func RedisConfigFor(cfg config.QueueConfig) RedisConfig {
+	return RedisConfig{
+		URL:     cfg.RedisURL,
+		Channel: cfg.QueueName + ":events",
+	}
+}
+
+// redisBus implements Bus with a Redis pub/sub channel. Each process holds a
+// single Redis subscription however many local subscribers it has.
+type redisBus struct {
+	subscribers
+
+	client  *redis.Client
+	channel string
+	logger  *slog.Logger
+}
+
+// NewRedisBus creates a new Redis-backed progress event bus
+func NewRedisBus(cfg RedisConfig, logger *slog.Logger) (Bus, error) {
+	opts, err := redis.ParseURL(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Redis URL: %w", err)
+	}
+
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	return &redisBus{
+		subscribers: newSubscribers(),
+		client:      client,
+		channel:     cfg.Channel,
+		logger:      logger,
+	}, nil
+}
+
+// Publish publishes an event on the channel
+func (b *redisBus) Publish(ctx context.Context, event *models.ProgressEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal progress event: %w", err)
+	}
+
+	if err := b.client.Publish(ctx, b.channel, data).Err(); err != nil {
+		return fmt.Errorf("failed to publish progress event: %w", err)
+	}
+
+	return nil
+}
+
+// Run subscribes to the channel and dispatches its events. The Redis client
+// resubscribes by itself after a dropped connection; events published in the
+// meantime are lost.
+func (b *redisBus) Run(ctx context.Context) error {
+	sub := b.client.Subscribe(ctx, b.channel)
+	defer sub.Close()
+
+	if _, err := sub.Receive(ctx); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("failed to subscribe to %s: %w", b.channel, err)
+	}
+
+	messages := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case message, ok := <-messages:
+			if !ok {
+				return nil
+			}
+
+			var event models.ProgressEvent
+			if err := json.Unmarshal([]byte(message.Payload), &event); err != nil {
+				b.logger.Warn("invalid progress event", slog.String("error", err.Error()))
+				continue
+			}
+			b.dispatch(&event)
+		}
+	}
+}
+
+// Close closes the Redis connection
+func (b *redisBus) Close() error {
+	return b.client.Close()
+}
@@ -0,0 +1,80 @@
+package pubsub
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestRedisBus_FansOutAcrossProcesses(t *testing.T) {
+	mr := miniredis.RunT(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := RedisConfig{URL: "redis://" + mr.Addr(), Channel: "sends:events"}
+
+	// Two API replicas and a worker, each with its own connection
+	var replicas []Bus
+	for range 2 {
+		bus, err := NewRedisBus(cfg, logger)
+		if err != nil {
+			t.Fatalf("NewRedisBus() error = %v", err)
+		}
+		defer bus.Close()
+		replicas = append(replicas, bus)
+	}
+	worker, err := NewRedisBus(cfg, logger)
+	if err != nil {
+		t.Fatalf("NewRedisBus() error = %v", err)
+	}
+	defer worker.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var streams []<-chan *models.ProgressEvent
+	for _, bus := range replicas {
+		go bus.Run(ctx)
+
+		events, unsubscribe := bus.Subscribe(7)
+		defer unsubscribe()
+		streams = append(streams, events)
+	}
+	other, unsubscribe := replicas[0].Subscribe(8)
+	defer unsubscribe()
+
+	// Wait for both replicas to subscribe in Redis
+	deadline := time.Now().Add(2 * time.Second)
+	for mr.PubSubNumSub(cfg.Channel)[cfg.Channel] < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("replicas did not subscribe")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	event := &models.ProgressEvent{Type: models.ProgressEventMessage, CampaignID: 7, MessageID: 42, Status: models.MessageStatusSent}
+	if err := worker.Publish(ctx, event); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	for i, events := range streams {
+		select {
+		case got := <-events:
+			if got.MessageID != 42 || got.Status != models.MessageStatusSent {
+				t.Errorf("replica %d received %+v", i, got)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("replica %d did not receive the event", i)
+		}
+	}
+
+	select {
+	case got := <-other:
+		t.Errorf("subscriber of another campaign received %+v", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,59 @@
+package pubsub
+
+import (
+	"sync"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// subscriberBuffer is how many events a subscriber may fall behind by before
+// further events are dropped for it
+const subscriberBuffer = 64
+
+// subscribers holds the local subscribers of a bus by campaign
+type subscribers struct {
+	mu         sync.Mutex
+	byCampaign map[int64]map[chan *models.ProgressEvent]struct{}
+}
+
+func newSubscribers() subscribers {
+	return subscribers{byCampaign: make(map[int64]map[chan *models.ProgressEvent]struct{})}
+}
+
+// Subscribe registers a local subscriber for a campaign's events
+func (s *subscribers) Subscribe(campaignID int64) (<-chan *models.ProgressEvent, func()) {
+	events := make(chan *models.ProgressEvent, subscriberBuffer)
+
+	s.mu.Lock()
+	if s.byCampaign[campaignID] == nil {
+		s.byCampaign[campaignID] = make(map[chan *models.ProgressEvent]struct{})
+	}
+	s.byCampaign[campaignID][events] = struct{}{}
+	s.mu.Unlock()
+
+	var once sync.Once
+	return events, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			delete(s.byCampaign[campaignID], events)
+			if len(s.byCampaign[campaignID]) == 0 {
+				delete(s.byCampaign, campaignID)
+			}
+		})
+	}
+}
+
+// dispatch hands an event to the local subscribers of its campaign
+func (s *subscribers) dispatch(event *models.ProgressEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for events := range s.byCampaign[event.CampaignID] {
+		select {
+		case events <- event:
+		default:
+		}
+	}
+}
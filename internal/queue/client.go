@@ -2,7 +2,12 @@ package queue
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
 
+	"github.com/Raymond9734/campaign-messaging-backend/internal/config"
 	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
 )
 
@@ -11,16 +16,100 @@ type Client interface {
 	// Publish sends a message job to the queue
 	Publish(ctx context.Context, job *models.MessageJob) error
 
+	// PublishDelayed schedules a message job to be published once at has passed
+	PublishDelayed(ctx context.Context, job *models.MessageJob, at time.Time) error
+
 	// Consume receives messages from the queue and processes them with the handler
-	// concurrency controls how many messages can be processed simultaneously
+	// concurrency controls how many messages can be processed simultaneously.
+	// Every job it hands out is acknowledged once the handler returns.
 	Consume(ctx context.Context, handler MessageHandler, concurrency int) error
 
+	// Ack marks a job handed out by Consume as done, removing it from the
+	// consumer's in-flight list
+	Ack(ctx context.Context, job *models.MessageJob) error
+
+	// Nack returns a job handed out by Consume to the queue
+	Nack(ctx context.Context, job *models.MessageJob) error
+
 	// Close closes the queue connection
 	Close() error
 
 	// Health checks if the queue is healthy
 	Health(ctx context.Context) error
+
+	// Depth returns how many jobs are waiting in the queue, the delayed set and quarantine
+	Depth(ctx context.Context) (*Depth, error)
+
+	// Lag returns how long the oldest ready job has been waiting to be taken,
+	// or 0 when none is waiting
+	Lag(ctx context.Context) (time.Duration, error)
+
+	// Maintenance returns the current maintenance mode state
+	Maintenance(ctx context.Context) (*Maintenance, error)
+
+	// SetMaintenance turns maintenance mode on; consumers stop taking new jobs
+	SetMaintenance(ctx context.Context, reason string) (*Maintenance, error)
+
+	// ClearMaintenance turns maintenance mode off
+	ClearMaintenance(ctx context.Context) error
+
+	// ListQuarantined returns up to limit payloads that could not be decoded, newest first
+	ListQuarantined(ctx context.Context, limit int) ([]QuarantinedJob, error)
+
+	// PurgeQuarantine removes all quarantined payloads and returns how many were removed
+	PurgeQuarantine(ctx context.Context) (int64, error)
+
+	// RemoveCampaignJobs removes the ready and delayed jobs of a campaign that
+	// no consumer has taken yet and returns how many were removed
+	RemoveCampaignJobs(ctx context.Context, campaignID int64) (int64, error)
 }
 
-// MessageHandler is a function that processes a message job
+// QuarantinedJob is a queue payload that could not be decoded into a MessageJob,
+// or whose handler panicked. It is kept verbatim, together with the error, for
+// later inspection.
+type QuarantinedJob struct {
+	Payload       string    `json:"payload"`
+	Error         string    `json:"error"`
+	QuarantinedAt time.Time `json:"quarantined_at"`
+}
+
+// Depth is a snapshot of the number of jobs held by the queue
+type Depth struct {
+	Ready   int64 `json:"ready"`
+	Delayed int64 `json:"delayed"`
+	// InFlight jobs have been taken by a consumer but not yet acknowledged
+	InFlight    int64 `json:"in_flight"`
+	Quarantined int64 `json:"quarantined"`
+}
+
+// Maintenance is the global maintenance mode state shared by the API and workers.
+// While enabled, the API refuses mutating requests and consumers stop taking jobs
+// (in-flight jobs still finish), so migrations can run without racing sends.
+type Maintenance struct {
+	Enabled   bool       `json:"enabled"`
+	Reason    string     `json:"reason,omitempty"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+}
+
+// MessageHandler is a function that processes a message job.
+// Returning an error that wraps ErrRequeue asks the consumer to nack the job,
+// putting it back on the queue; any other error is logged and the job is
+// acked and dropped.
 type MessageHandler func(ctx context.Context, job *models.MessageJob) error
+
+// ErrRequeue is wrapped by handler errors to request that the job be published again
+var ErrRequeue = errors.New("job requeued")
+
+// ErrNotInFlight is returned by Ack and Nack for jobs this client did not hand
+// out, or has already settled
+var ErrNotInFlight = errors.New("job is not in flight")
+
+// New creates the client of the configured queue backend. The API (producer)
+// and the worker (consumer) both build their client through it so they always
+// agree on where jobs are kept. database is only used by the Postgres backend.
+func New(cfg config.QueueConfig, database *sql.DB, logger *slog.Logger) (Client, error) {
+	if cfg.Backend == config.QueueBackendPostgres {
+		return NewPostgresClient(database, PostgresConfigFor(cfg), logger)
+	}
+	return NewRedisClient(RedisConfigFor(cfg), logger)
+}
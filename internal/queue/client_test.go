@@ -0,0 +1,30 @@
+package queue
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/config"
+)
+
+func TestNew_SelectsBackend(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	mr := miniredis.RunT(t)
+
+	client, err := New(config.QueueConfig{Backend: config.QueueBackendRedis, RedisURL: "redis://" + mr.Addr(), QueueName: "sends"}, nil, logger)
+	if err != nil {
+		t.Fatalf("New(redis) error = %v", err)
+	}
+	defer client.Close()
+	if _, ok := client.(*redisClient); !ok {
+		t.Errorf("New(redis) = %T, want a Redis client", client)
+	}
+
+	// The Postgres queue cannot run without the database
+	if _, err := New(config.QueueConfig{Backend: config.QueueBackendPostgres, QueueName: "sends"}, nil, logger); err == nil {
+		t.Error("New(postgres) without a database error = nil, want an error")
+	}
+}
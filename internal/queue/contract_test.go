@@ -0,0 +1,360 @@
+package queue_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/config"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/queue"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/service"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/worker"
+)
+
+// These contract tests publish jobs the way the API does (campaign service)
+// and consume them the way the worker does (message processor) through a real
+// Redis protocol implementation. They catch drift in the job payload or the
+// queue wiring between cmd/api and cmd/worker.
+
+// sharedStore stands in for the database both processes read and write
+type sharedStore struct {
+	mu        sync.Mutex
+	campaign  *models.Campaign
+	customers map[int64]*models.Customer
+	messages  map[int64]*models.OutboundMessage
+	nextID    int64
+}
+
+func newSharedStore() *sharedStore {
+	return &sharedStore{
+		campaign: &models.Campaign{ID: 1, Name: "Contract", Channel: models.ChannelSMS, Status: models.CampaignStatusDraft, BaseTemplate: "Hi {first_name}"},
+		customers: map[int64]*models.Customer{
+			1: {ID: 1, Phone: "+254700000001", FirstName: "Ann"},
+			2: {ID: 2, Phone: "+254700000002", FirstName: "Ben"},
+			3: {ID: 3, Phone: "+254700000003", FirstName: "Cy"},
+		},
+		messages: map[int64]*models.OutboundMessage{},
+	}
+}
+
+// recordingSender records every delivered message
+type recordingSender struct {
+	mu   sync.Mutex
+	sent map[string]string // phone -> content
+}
+
+func (s *recordingSender) Send(ctx context.Context, channel, phone, content string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent[phone] = content
+	return "recorded-" + phone, nil
+}
+
+func (s *recordingSender) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sent)
+}
+
+// loadQueueConfig loads the configuration each process would read from the
+// shared environment, pointed at the test Redis
+func loadQueueConfig(t *testing.T, mr *miniredis.Miniredis) config.QueueConfig {
+	t.Helper()
+	t.Setenv("REDIS_URL", "redis://"+mr.Addr()+"/0")
+	t.Setenv("QUEUE_NAME", "contract_sends")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("config.Load() error = %v", err)
+	}
+	return cfg.Queue
+}
+
+// newQueueClient connects a client the way cmd/api and cmd/worker do
+func newQueueClient(t *testing.T, cfg config.QueueConfig, logger *slog.Logger) queue.Client {
+	t.Helper()
+	client, err := queue.NewRedisClient(queue.RedisConfigFor(cfg), logger)
+	if err != nil {
+		t.Fatalf("NewRedisClient() error = %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+// newAPIService builds the campaign service used by the API on top of the shared store
+func newAPIService(ctrl *gomock.Controller, store *sharedStore, queueClient queue.Client, logger *slog.Logger) service.CampaignService {
+	campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+	customerRepo := mocks.NewMockCustomerRepository(ctrl)
+	messageRepo := mocks.NewMockOutboundMessageRepository(ctrl)
+
+	campaignRepo.EXPECT().GetByID(gomock.Any(), store.campaign.ID).Return(store.campaign, nil).AnyTimes()
+	campaignRepo.EXPECT().TransitionStatus(gomock.Any(), store.campaign.ID, gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
+	campaignRepo.EXPECT().SetBuilding(gomock.Any(), store.campaign.ID, gomock.Any()).Return(nil).AnyTimes()
+	campaignRepo.EXPECT().GetWithStats(gomock.Any(), store.campaign.ID).
+		Return(&models.CampaignWithStats{ID: store.campaign.ID, Status: models.CampaignStatusSending, Stats: models.CampaignStats{Pending: 1}}, nil).
+		AnyTimes()
+	customerRepo.EXPECT().GetByIDs(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, ids []int64) ([]*models.Customer, error) {
+			customers := make([]*models.Customer, 0, len(ids))
+			for _, id := range ids {
+				customers = append(customers, store.customers[id])
+			}
+			return customers, nil
+		}).
+		AnyTimes()
+	messageRepo.EXPECT().CreateBatch(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, messages []*models.OutboundMessage) error {
+			store.mu.Lock()
+			defer store.mu.Unlock()
+			for _, message := range messages {
+				store.nextID++
+				message.ID = store.nextID
+				stored := *message
+				store.messages[message.ID] = &stored
+			}
+			return nil
+		}).
+		AnyTimes()
+	messageRepo.EXPECT().MarkQueued(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	return service.NewCampaignService(
+		campaignRepo,
+		customerRepo,
+		messageRepo,
+		nil,
+		service.NewTemplateService(nil, nil),
+		queueClient,
+		service.CampaignServiceConfig{SendBatchSize: 2},
+		logger,
+	)
+}
+
+// newWorkerProcessor builds the worker's message processor on top of the shared store
+func newWorkerProcessor(ctrl *gomock.Controller, store *sharedStore, sender worker.MessageSender, scheduler worker.JobScheduler, logger *slog.Logger) *worker.MessageProcessor {
+	campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+	customerRepo := mocks.NewMockCustomerRepository(ctrl)
+	messageRepo := mocks.NewMockOutboundMessageRepository(ctrl)
+
+	campaignRepo.EXPECT().GetByID(gomock.Any(), store.campaign.ID).
+		Return(&models.Campaign{ID: store.campaign.ID, Channel: store.campaign.Channel, Status: models.CampaignStatusSending}, nil).
+		AnyTimes()
+	campaignRepo.EXPECT().GetWithStats(gomock.Any(), store.campaign.ID).
+		Return(&models.CampaignWithStats{ID: store.campaign.ID, Status: models.CampaignStatusSending, Stats: models.CampaignStats{Pending: 1}}, nil).
+		AnyTimes()
+	customerRepo.EXPECT().GetByID(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, id int64) (*models.Customer, error) {
+			return store.customers[id], nil
+		}).
+		AnyTimes()
+	messageRepo.EXPECT().GetByID(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, id int64) (*models.OutboundMessage, error) {
+			store.mu.Lock()
+			defer store.mu.Unlock()
+			message, ok := store.messages[id]
+			if !ok {
+				return nil, models.ErrNotFound
+			}
+			copied := *message
+			return &copied, nil
+		}).
+		AnyTimes()
+	messageRepo.EXPECT().ClaimMessage(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, id int64) (bool, error) {
+			store.mu.Lock()
+			defer store.mu.Unlock()
+			if store.messages[id].Status != models.MessageStatusPending {
+				return false, nil
+			}
+			store.messages[id].Status = models.MessageStatusSending
+			return true, nil
+		}).
+		AnyTimes()
+	messageRepo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, id int64, status string, lastError *string) error {
+			store.mu.Lock()
+			defer store.mu.Unlock()
+			store.messages[id].Status = status
+			return nil
+		}).
+		AnyTimes()
+	messageRepo.EXPECT().MarkSent(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, id int64, providerMessageID string) error {
+			store.mu.Lock()
+			defer store.mu.Unlock()
+			store.messages[id].Status = models.MessageStatusSent
+			return nil
+		}).
+		AnyTimes()
+
+	return worker.NewMessageProcessor(messageRepo, campaignRepo, customerRepo, sender, scheduler, 3, logger)
+}
+
+// waitFor polls cond until it holds or the deadline passes
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) bool {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return cond()
+}
+
+func TestContract_APIPublishWorkerConsume(t *testing.T) {
+	mr := miniredis.RunT(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := loadQueueConfig(t, mr)
+	store := newSharedStore()
+	ctrl := gomock.NewController(t)
+
+	// Producer and consumer use separate connections, as separate processes do
+	apiQueue := newQueueClient(t, cfg, logger)
+	workerQueue := newQueueClient(t, cfg, logger)
+
+	api := newAPIService(ctrl, store, apiQueue, logger)
+	result, err := api.SendCampaign(context.Background(), store.campaign.ID, &service.SendCampaignRequest{CustomerIDs: []int64{1, 2, 3}})
+	if err != nil {
+		t.Fatalf("SendCampaign() error = %v", err)
+	}
+	if result.MessagesQueued != 3 {
+		t.Fatalf("MessagesQueued = %d, want 3", result.MessagesQueued)
+	}
+
+	// The API must publish to the lanes the worker consumes
+	depth, err := workerQueue.Depth(context.Background())
+	if err != nil || depth.Ready != 3 {
+		t.Fatalf("worker sees %+v ready jobs on queue %q (err %v), want 3", depth, cfg.QueueName, err)
+	}
+
+	sender := &recordingSender{sent: map[string]string{}}
+	processor := newWorkerProcessor(ctrl, store, sender, workerQueue, logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- workerQueue.Consume(ctx, processor.Process, 2) }()
+
+	delivered := waitFor(t, 5*time.Second, func() bool { return sender.count() == 3 })
+	cancel()
+	<-done
+
+	if !delivered {
+		t.Fatalf("worker delivered %d messages, want 3", sender.count())
+	}
+	if got := sender.sent["+254700000002"]; got != "Hi Ben" {
+		t.Errorf("content for Ben = %q, want %q", got, "Hi Ben")
+	}
+	for id, message := range store.messages {
+		if message.Status != models.MessageStatusSent {
+			t.Errorf("message %d status = %s, want sent", id, message.Status)
+		}
+	}
+	if quarantined, _ := workerQueue.ListQuarantined(context.Background(), 10); len(quarantined) != 0 {
+		t.Errorf("%d jobs quarantined, want none: %+v", len(quarantined), quarantined)
+	}
+}
+
+func TestContract_InMemoryQueueInOneProcess(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	store := newSharedStore()
+	ctrl := gomock.NewController(t)
+
+	// API and worker share one in-memory queue, as when run in one process
+	queueClient := queue.NewMemoryClient(logger)
+	t.Cleanup(func() { _ = queueClient.Close() })
+
+	api := newAPIService(ctrl, store, queueClient, logger)
+	if _, err := api.SendCampaign(context.Background(), store.campaign.ID, &service.SendCampaignRequest{CustomerIDs: []int64{1, 2, 3}}); err != nil {
+		t.Fatalf("SendCampaign() error = %v", err)
+	}
+
+	sender := &recordingSender{sent: map[string]string{}}
+	processor := newWorkerProcessor(ctrl, store, sender, queueClient, logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- queueClient.Consume(ctx, processor.Process, 2) }()
+
+	delivered := waitFor(t, 5*time.Second, func() bool { return sender.count() == 3 })
+	cancel()
+	<-done
+
+	if !delivered {
+		t.Fatalf("worker delivered %d messages, want 3", sender.count())
+	}
+	if got := sender.sent["+254700000001"]; got != "Hi Ann" {
+		t.Errorf("content for Ann = %q, want %q", got, "Hi Ann")
+	}
+	if depth, _ := queueClient.Depth(context.Background()); *depth != (queue.Depth{}) {
+		t.Errorf("Depth() = %+v, want an empty queue", *depth)
+	}
+}
+
+func TestContract_LegacyPayloadIsConsumed(t *testing.T) {
+	mr := miniredis.RunT(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := loadQueueConfig(t, mr)
+	store := newSharedStore()
+	store.messages[7] = &models.OutboundMessage{ID: 7, CampaignID: 1, CustomerID: 1, Status: models.MessageStatusPending, RenderedContent: "Hi Ann"}
+	ctrl := gomock.NewController(t)
+
+	// Jobs published by builds that predate payload versioning are still in flight after a deploy
+	if _, err := mr.Lpush(cfg.QueueName, `{"outbound_message_id":7}`); err != nil {
+		t.Fatalf("Lpush() error = %v", err)
+	}
+
+	workerQueue := newQueueClient(t, cfg, logger)
+	sender := &recordingSender{sent: map[string]string{}}
+	processor := newWorkerProcessor(ctrl, store, sender, workerQueue, logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- workerQueue.Consume(ctx, processor.Process, 1) }()
+
+	delivered := waitFor(t, 5*time.Second, func() bool { return sender.count() == 1 })
+	cancel()
+	<-done
+
+	if !delivered {
+		t.Fatal("legacy job was not delivered")
+	}
+}
+
+func TestContract_DelayedJobIsConsumed(t *testing.T) {
+	mr := miniredis.RunT(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := loadQueueConfig(t, mr)
+	store := newSharedStore()
+	store.messages[9] = &models.OutboundMessage{ID: 9, CampaignID: 1, CustomerID: 3, Status: models.MessageStatusPending, RenderedContent: "Hi Cy"}
+	ctrl := gomock.NewController(t)
+
+	// Deferred jobs (warm-up caps, delivery windows) go through the delayed set
+	workerQueue := newQueueClient(t, cfg, logger)
+	if err := workerQueue.PublishDelayed(context.Background(), &models.MessageJob{OutboundMessageID: 9}, time.Now()); err != nil {
+		t.Fatalf("PublishDelayed() error = %v", err)
+	}
+
+	sender := &recordingSender{sent: map[string]string{}}
+	processor := newWorkerProcessor(ctrl, store, sender, workerQueue, logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- workerQueue.Consume(ctx, processor.Process, 1) }()
+
+	delivered := waitFor(t, 5*time.Second, func() bool { return sender.count() == 1 })
+	cancel()
+	<-done
+
+	if !delivered {
+		t.Fatal("delayed job was not delivered")
+	}
+}
@@ -0,0 +1,110 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/tracing"
+)
+
+// ErrUnsupportedJobVersion is returned for payloads written by a newer build
+// than this consumer understands
+var ErrUnsupportedJobVersion = errors.New("unsupported job payload version")
+
+// jobPayload is a decoded but not yet typed job, keyed by JSON field name
+type jobPayload map[string]json.RawMessage
+
+// jobUpgraders migrate a payload from version N (the key) to version N+1.
+// Payloads published before versioning existed carry no version field and are
+// treated as version 0.
+var jobUpgraders = map[int]func(payload jobPayload) error{
+	0: upgradeJobV0,
+}
+
+// upgradeJobV0 upgrades unversioned payloads; the field layout is unchanged
+func upgradeJobV0(payload jobPayload) error {
+	if _, ok := payload["outbound_message_id"]; !ok {
+		return fmt.Errorf("v0 payload missing outbound_message_id")
+	}
+	return nil
+}
+
+// readyAt returns a copy of job stamped as ready at the given time. A job
+// that is already stamped, such as a retry of a job that waited before, is
+// stamped again: its lag is counted from when it was last made ready.
+func readyAt(job *models.MessageJob, at time.Time) *models.MessageJob {
+	stamped := *job
+	at = at.UTC()
+	stamped.ReadyAt = &at
+	return &stamped
+}
+
+// traced returns job carrying the trace context of ctx. A job published
+// outside any trace keeps the trace context it already carries.
+func traced(ctx context.Context, job *models.MessageJob) *models.MessageJob {
+	if carrier := tracing.Inject(ctx); carrier != nil {
+		job.TraceContext = carrier
+	}
+	return job
+}
+
+// EncodeJob serializes a job, stamping it with the current payload version
+func EncodeJob(job *models.MessageJob) ([]byte, error) {
+	stamped := *job
+	stamped.Version = models.MessageJobVersion
+
+	data, err := json.Marshal(&stamped)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal job: %w", err)
+	}
+	return data, nil
+}
+
+// DecodeJob deserializes a job payload of any known version, running it through
+// the registered upgraders until it matches models.MessageJobVersion.
+// Unknown fields are ignored so that additive changes never break old consumers.
+func DecodeJob(data []byte) (*models.MessageJob, error) {
+	var payload jobPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal job: %w", err)
+	}
+
+	version := 0
+	if raw, ok := payload["version"]; ok {
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return nil, fmt.Errorf("invalid job version: %w", err)
+		}
+	}
+
+	if version > models.MessageJobVersion {
+		return nil, fmt.Errorf("%w: %d (max %d)", ErrUnsupportedJobVersion, version, models.MessageJobVersion)
+	}
+
+	for version < models.MessageJobVersion {
+		upgrade, ok := jobUpgraders[version]
+		if !ok {
+			return nil, fmt.Errorf("%w: no upgrader from version %d", ErrUnsupportedJobVersion, version)
+		}
+		if err := upgrade(payload); err != nil {
+			return nil, fmt.Errorf("failed to upgrade job from version %d: %w", version, err)
+		}
+		version++
+	}
+
+	upgraded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to re-encode upgraded job: %w", err)
+	}
+
+	var job models.MessageJob
+	if err := json.Unmarshal(upgraded, &job); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal job: %w", err)
+	}
+	job.Version = version
+
+	return &job, nil
+}
@@ -0,0 +1,89 @@
+package queue
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestDecodeJob(t *testing.T) {
+	tests := []struct {
+		name      string
+		payload   string
+		wantID    int64
+		wantErr   bool
+		wantErrIs error
+	}{
+		{
+			name:    "current version",
+			payload: `{"version":1,"outbound_message_id":42}`,
+			wantID:  42,
+		},
+		{
+			name:    "legacy unversioned payload",
+			payload: `{"outbound_message_id":7}`,
+			wantID:  7,
+		},
+		{
+			name:    "unknown fields are tolerated",
+			payload: `{"version":1,"outbound_message_id":9,"trace_id":"abc","priority":2}`,
+			wantID:  9,
+		},
+		{
+			name:      "newer version is rejected",
+			payload:   `{"version":99,"outbound_message_id":1}`,
+			wantErr:   true,
+			wantErrIs: ErrUnsupportedJobVersion,
+		},
+		{
+			name:    "legacy payload without message id",
+			payload: `{"foo":"bar"}`,
+			wantErr: true,
+		},
+		{
+			name:    "malformed json",
+			payload: `{"outbound_message_id":`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job, err := DecodeJob([]byte(tt.payload))
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeJob() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Errorf("DecodeJob() error = %v, want %v", err, tt.wantErrIs)
+			}
+			if err != nil {
+				return
+			}
+
+			if job.OutboundMessageID != tt.wantID {
+				t.Errorf("OutboundMessageID = %d, want %d", job.OutboundMessageID, tt.wantID)
+			}
+			if job.Version != models.MessageJobVersion {
+				t.Errorf("Version = %d, want %d", job.Version, models.MessageJobVersion)
+			}
+		})
+	}
+}
+
+func TestEncodeJob_RoundTrip(t *testing.T) {
+	data, err := EncodeJob(&models.MessageJob{OutboundMessageID: 5})
+	if err != nil {
+		t.Fatalf("EncodeJob() error = %v", err)
+	}
+
+	job, err := DecodeJob(data)
+	if err != nil {
+		t.Fatalf("DecodeJob() error = %v", err)
+	}
+
+	if job.OutboundMessageID != 5 || job.Version != models.MessageJobVersion {
+		t.Errorf("round trip = %+v, want message 5 at version %d", job, models.MessageJobVersion)
+	}
+}
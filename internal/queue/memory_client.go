@@ -0,0 +1,476 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// Every client must keep up with the interface
+var (
+	_ Client = (*redisClient)(nil)
+	_ Client = (*postgresClient)(nil)
+	_ Client = (*memoryClient)(nil)
+)
+
+// errMemoryClientClosed is returned by a memory client after Close
+var errMemoryClientClosed = errors.New("memory queue is closed")
+
+// memoryClient implements Client in process memory. Producers and consumers
+// share jobs only when they share the client, so it suits running the API and
+// the worker in one process for local development and integration tests. Jobs
+// are lost when the process exits.
+//
+// Jobs are stored encoded, as on Redis, so payload changes that would break
+// the worker break here too. Lanes, delayed jobs, maintenance, quarantine and
+// the shutdown drain behave as they do with Redis.
+type memoryClient struct {
+	drainTimeout time.Duration
+	logger       *slog.Logger
+
+	mu       sync.Mutex
+	lanes    map[int64][]memoryJob // ready jobs per campaign, oldest first; 0 is the shared lane
+	rotation []int64               // IDs of the lanes with jobs, in the order they are served
+	delayed  []memoryJob
+	inFlight map[*models.MessageJob]memoryDelivery
+	// quarantined holds the newest entry last
+	quarantined []QuarantinedJob
+	maintenance Maintenance
+	consumers   int64
+	closed      bool
+
+	// wake is signalled when a job is published, so idle consumers take it
+	// without waiting out their poll interval
+	wake chan struct{}
+}
+
+// memoryJob is an encoded job with what the queue needs to know about it
+type memoryJob struct {
+	payload    string
+	campaignID int64
+	// readyAt is when the job became ready, or for a delayed job when it will
+	readyAt time.Time
+}
+
+// memoryDelivery is a job in flight and the consumer holding it
+type memoryDelivery struct {
+	job      memoryJob
+	consumer int64
+}
+
+// NewMemoryClient creates a queue client that keeps its jobs in memory
+func NewMemoryClient(logger *slog.Logger) Client {
+	return &memoryClient{
+		drainTimeout: defaultDrainTimeout,
+		logger:       logger,
+		lanes:        make(map[int64][]memoryJob),
+		inFlight:     make(map[*models.MessageJob]memoryDelivery),
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+// encode stamps and serializes a job the way the Redis client publishes it
+func (c *memoryClient) encode(ctx context.Context, job *models.MessageJob, at time.Time) (memoryJob, error) {
+	data, err := EncodeJob(traced(ctx, readyAt(job, at)))
+	if err != nil {
+		return memoryJob{}, err
+	}
+	return memoryJob{payload: string(data), campaignID: max(job.CampaignID, 0), readyAt: at}, nil
+}
+
+// Publish adds a job to its campaign's lane
+func (c *memoryClient) Publish(ctx context.Context, job *models.MessageJob) error {
+	entry, err := c.encode(ctx, job, time.Now())
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return errMemoryClientClosed
+	}
+	c.enqueue(entry)
+	return nil
+}
+
+// PublishDelayed holds a job until at, when a consumer moves it to its lane
+func (c *memoryClient) PublishDelayed(ctx context.Context, job *models.MessageJob, at time.Time) error {
+	entry, err := c.encode(ctx, job, at)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return errMemoryClientClosed
+	}
+	c.delayed = append(c.delayed, entry)
+	return nil
+}
+
+// enqueue appends a job to its lane, putting the lane in rotation, and wakes
+// a consumer. c.mu must be held.
+func (c *memoryClient) enqueue(entry memoryJob) {
+	lane := c.lanes[entry.campaignID]
+	if len(lane) == 0 {
+		c.rotation = append(c.rotation, entry.campaignID)
+	}
+	c.lanes[entry.campaignID] = append(lane, entry)
+
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// take promotes due delayed jobs and returns the next job in rotation,
+// reporting false when none is ready
+func (c *memoryClient) take() (memoryJob, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.delayed = slices.DeleteFunc(c.delayed, func(entry memoryJob) bool {
+		if entry.readyAt.After(now) {
+			return false
+		}
+		c.enqueue(entry)
+		return true
+	})
+
+	if len(c.rotation) == 0 {
+		return memoryJob{}, false
+	}
+
+	// Serve the lane at the head of the rotation and move it to the back
+	id := c.rotation[0]
+	lane := c.lanes[id]
+	entry := lane[0]
+	if len(lane) == 1 {
+		delete(c.lanes, id)
+		c.rotation = c.rotation[1:]
+	} else {
+		c.lanes[id] = lane[1:]
+		c.rotation = append(c.rotation[1:], id)
+	}
+	return entry, true
+}
+
+// Consume hands jobs to the handler as the Redis client does: up to
+// concurrency at once (max 5), lanes in turn, none while in maintenance. Once
+// ctx is done it waits up to the drain timeout for the jobs in flight and
+// returns any still running to the queue.
+func (c *memoryClient) Consume(ctx context.Context, handler MessageHandler, concurrency int) error {
+	concurrency = min(max(concurrency, 1), 5)
+
+	c.mu.Lock()
+	c.consumers++
+	consumer := c.consumers
+	c.mu.Unlock()
+
+	jobsCtx, cancelJobs := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelJobs()
+
+	semaphore := make(chan struct{}, concurrency)
+	var running sync.WaitGroup
+
+	drain := func() {
+		done := make(chan struct{})
+		go func() {
+			running.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(c.drainTimeout):
+			c.logger.Warn("drain timeout reached, returning unfinished jobs to the queue",
+				slog.Duration("drain_timeout", c.drainTimeout),
+			)
+			cancelJobs()
+			c.release(consumer)
+		}
+	}
+
+	for {
+		if ctx.Err() != nil {
+			drain()
+			return ctx.Err()
+		}
+		select {
+		case <-ctx.Done():
+			continue
+		case semaphore <- struct{}{}:
+		}
+
+		c.mu.Lock()
+		paused := c.maintenance.Enabled
+		c.mu.Unlock()
+
+		entry, ok := memoryJob{}, false
+		if !paused {
+			entry, ok = c.take()
+		}
+		if !ok {
+			<-semaphore
+			wait := idlePollInterval
+			if paused {
+				wait = maintenancePollInterval
+			}
+			select {
+			case <-ctx.Done():
+			case <-c.wake:
+			case <-time.After(wait):
+			}
+			continue
+		}
+
+		job, err := DecodeJob([]byte(entry.payload))
+		if err != nil {
+			<-semaphore
+			c.logger.Error("failed to decode job, quarantining payload",
+				slog.String("error", err.Error()),
+				slog.String("data", entry.payload),
+			)
+			c.quarantine(entry.payload, err)
+			continue
+		}
+
+		c.mu.Lock()
+		c.inFlight[job] = memoryDelivery{job: entry, consumer: consumer}
+		c.mu.Unlock()
+
+		running.Add(1)
+		go func(job *models.MessageJob) {
+			defer running.Done()
+			defer func() { <-semaphore }()
+
+			err := c.handle(jobsCtx, handler, job, entry.payload)
+			if err != nil {
+				c.logger.Error("handler failed to process job",
+					slog.Int64("message_id", job.OutboundMessageID),
+					slog.String("error", err.Error()),
+				)
+				if errors.Is(err, ErrRequeue) {
+					if err := c.Nack(jobsCtx, job); err != nil {
+						c.logger.Error("failed to requeue job",
+							slog.Int64("message_id", job.OutboundMessageID),
+							slog.String("error", err.Error()),
+						)
+					}
+					return
+				}
+			}
+
+			if err := c.Ack(jobsCtx, job); err != nil {
+				c.logger.Error("failed to ack job",
+					slog.Int64("message_id", job.OutboundMessageID),
+					slog.String("error", err.Error()),
+				)
+			}
+		}(job)
+	}
+}
+
+// handle runs the handler for a job, quarantining the job if it panics
+func (c *memoryClient) handle(ctx context.Context, handler MessageHandler, job *models.MessageJob, payload string) (err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+
+		stack := debug.Stack()
+		c.logger.Error("handler panicked",
+			slog.Int64("message_id", job.OutboundMessageID),
+			slog.Any("panic", r),
+			slog.String("stack", string(stack)),
+		)
+
+		err = fmt.Errorf("handler panic: %v", r)
+		c.quarantine(payload, fmt.Errorf("%w\n%s", err, stack))
+	}()
+
+	return handler(ctx, job)
+}
+
+// release returns the jobs a consumer still holds to their lanes
+func (c *memoryClient) release(consumer int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for job, delivery := range c.inFlight {
+		if delivery.consumer == consumer {
+			delete(c.inFlight, job)
+			c.enqueue(delivery.job)
+		}
+	}
+}
+
+// Ack forgets a job handed out by Consume
+func (c *memoryClient) Ack(ctx context.Context, job *models.MessageJob) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.inFlight[job]; !ok {
+		return ErrNotInFlight
+	}
+	delete(c.inFlight, job)
+	return nil
+}
+
+// Nack returns a job handed out by Consume to the back of its lane
+func (c *memoryClient) Nack(ctx context.Context, job *models.MessageJob) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delivery, ok := c.inFlight[job]
+	if !ok {
+		return ErrNotInFlight
+	}
+	delete(c.inFlight, job)
+	c.enqueue(delivery.job)
+	return nil
+}
+
+// quarantine keeps a payload that could not be handled
+func (c *memoryClient) quarantine(payload string, cause error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.quarantined = append(c.quarantined, QuarantinedJob{
+		Payload:       payload,
+		Error:         cause.Error(),
+		QuarantinedAt: time.Now().UTC(),
+	})
+}
+
+// Close stops the client accepting jobs
+func (c *memoryClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	return nil
+}
+
+// Health reports whether the client is still open
+func (c *memoryClient) Health(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return errMemoryClientClosed
+	}
+	return nil
+}
+
+// Depth counts the jobs held by the client
+func (c *memoryClient) Depth(ctx context.Context) (*Depth, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	depth := &Depth{
+		Delayed:     int64(len(c.delayed)),
+		InFlight:    int64(len(c.inFlight)),
+		Quarantined: int64(len(c.quarantined)),
+	}
+	for _, lane := range c.lanes {
+		depth.Ready += int64(len(lane))
+	}
+	return depth, nil
+}
+
+// Lag returns how long the oldest job at the head of a lane has waited
+func (c *memoryClient) Lag(ctx context.Context) (time.Duration, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var lag time.Duration
+	for _, lane := range c.lanes {
+		lag = max(lag, time.Since(lane[0].readyAt))
+	}
+	return lag, nil
+}
+
+// Maintenance returns the current maintenance mode state
+func (c *memoryClient) Maintenance(ctx context.Context) (*Maintenance, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	state := c.maintenance
+	return &state, nil
+}
+
+// SetMaintenance turns maintenance mode on, keeping the start of one already on
+func (c *memoryClient) SetMaintenance(ctx context.Context, reason string) (*Maintenance, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.maintenance.Enabled {
+		startedAt := time.Now().UTC()
+		c.maintenance.StartedAt = &startedAt
+	}
+	c.maintenance.Enabled = true
+	c.maintenance.Reason = reason
+
+	state := c.maintenance
+	return &state, nil
+}
+
+// ClearMaintenance turns maintenance mode off
+func (c *memoryClient) ClearMaintenance(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maintenance = Maintenance{}
+	return nil
+}
+
+// ListQuarantined returns up to limit quarantined payloads, newest first
+func (c *memoryClient) ListQuarantined(ctx context.Context, limit int) ([]QuarantinedJob, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	limit = min(max(limit, 1), len(c.quarantined))
+	jobs := make([]QuarantinedJob, 0, limit)
+	for i := len(c.quarantined) - 1; len(jobs) < limit; i-- {
+		jobs = append(jobs, c.quarantined[i])
+	}
+	return jobs, nil
+}
+
+// PurgeQuarantine removes every quarantined payload
+func (c *memoryClient) PurgeQuarantine(ctx context.Context) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	purged := int64(len(c.quarantined))
+	c.quarantined = nil
+	return purged, nil
+}
+
+// RemoveCampaignJobs removes the campaign's lane and its delayed jobs
+func (c *memoryClient) RemoveCampaignJobs(ctx context.Context, campaignID int64) (int64, error) {
+	if campaignID <= 0 {
+		return 0, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := int64(len(c.lanes[campaignID]))
+	delete(c.lanes, campaignID)
+	c.rotation = slices.DeleteFunc(c.rotation, func(id int64) bool { return id == campaignID })
+
+	before := len(c.delayed)
+	c.delayed = slices.DeleteFunc(c.delayed, func(entry memoryJob) bool { return entry.campaignID == campaignID })
+	removed += int64(before - len(c.delayed))
+
+	return removed, nil
+}
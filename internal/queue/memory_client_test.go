@@ -0,0 +1,183 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func newTestMemoryClient() *memoryClient {
+	return NewMemoryClient(slog.New(slog.NewTextHandler(io.Discard, nil))).(*memoryClient)
+}
+
+func TestMemoryClient_ConsumeServesCampaignsInTurn(t *testing.T) {
+	client := newTestMemoryClient()
+	ctx := context.Background()
+
+	for id := int64(1); id <= 4; id++ {
+		if err := client.Publish(ctx, &models.MessageJob{OutboundMessageID: id, CampaignID: 10}); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	for id := int64(5); id <= 6; id++ {
+		if err := client.Publish(ctx, &models.MessageJob{OutboundMessageID: id, CampaignID: 20}); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	// Delayed jobs are held until due; a job of a removed campaign never runs
+	if err := client.PublishDelayed(ctx, &models.MessageJob{OutboundMessageID: 7, CampaignID: 30}, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("PublishDelayed() error = %v", err)
+	}
+	if err := client.PublishDelayed(ctx, &models.MessageJob{OutboundMessageID: 8}, time.Now().Add(100*time.Millisecond)); err != nil {
+		t.Fatalf("PublishDelayed() error = %v", err)
+	}
+	if removed, err := client.RemoveCampaignJobs(ctx, 30); err != nil || removed != 1 {
+		t.Fatalf("RemoveCampaignJobs() = %d, %v, want 1", removed, err)
+	}
+
+	handled := make(chan *models.MessageJob, 8)
+	consumeCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Consume(consumeCtx, func(ctx context.Context, job *models.MessageJob) error {
+			handled <- job
+			return nil
+		}, 1)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	var order []int64
+	for len(order) < 7 {
+		select {
+		case job := <-handled:
+			if job.Version != models.MessageJobVersion || job.ReadyAt == nil {
+				t.Errorf("job %d = %+v, want it stamped as on Redis", job.OutboundMessageID, job)
+			}
+			order = append(order, job.OutboundMessageID)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("consumed %v, want 7 jobs", order)
+		}
+	}
+
+	want := "[1 5 2 6 3 4 8]"
+	if fmt.Sprint(order) != want {
+		t.Errorf("consumed in order %v, want %s", order, want)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if depth, _ := client.Depth(ctx); *depth != (Depth{}) {
+		t.Errorf("Depth() = %+v, want an empty queue", *depth)
+	}
+}
+
+func TestMemoryClient_ConsumeRequeuesAndQuarantines(t *testing.T) {
+	client := newTestMemoryClient()
+	ctx := context.Background()
+
+	for id := int64(1); id <= 2; id++ {
+		if err := client.Publish(ctx, &models.MessageJob{OutboundMessageID: id}); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+
+	// Job 1 asks for a requeue once; job 2 panics and is quarantined
+	var attempts atomic.Int32
+	finished := make(chan int64, 3)
+	consumeCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Consume(consumeCtx, func(ctx context.Context, job *models.MessageJob) error {
+			defer func() { finished <- job.OutboundMessageID }()
+			if job.OutboundMessageID == 2 {
+				panic("bad job")
+			}
+			if attempts.Add(1) == 1 {
+				return fmt.Errorf("provider busy: %w", ErrRequeue)
+			}
+			return nil
+		}, 2)
+	}()
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-finished:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%d jobs handled, want 3", i)
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	<-done
+
+	if attempts.Load() != 2 {
+		t.Errorf("job 1 handled %d times, want 2", attempts.Load())
+	}
+	quarantined, _ := client.ListQuarantined(ctx, 10)
+	if len(quarantined) != 1 {
+		t.Fatalf("ListQuarantined() = %d jobs, want 1", len(quarantined))
+	}
+	if depth, _ := client.Depth(ctx); depth.Ready != 0 || depth.InFlight != 0 || depth.Quarantined != 1 {
+		t.Errorf("Depth() = %+v, want only the quarantined job", *depth)
+	}
+}
+
+func TestMemoryClient_ConsumeDrainTimeout(t *testing.T) {
+	client := newTestMemoryClient()
+	client.drainTimeout = 100 * time.Millisecond
+	ctx := context.Background()
+
+	if _, err := client.SetMaintenance(ctx, "migration"); err != nil {
+		t.Fatalf("SetMaintenance() error = %v", err)
+	}
+	if err := client.Publish(ctx, &models.MessageJob{OutboundMessageID: 1}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	// The handler runs until its context is canceled
+	started := make(chan struct{})
+	consumeCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Consume(consumeCtx, func(ctx context.Context, job *models.MessageJob) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		}, 1)
+	}()
+
+	// Nothing is taken during maintenance
+	select {
+	case <-started:
+		t.Fatal("job consumed during maintenance")
+	case <-time.After(200 * time.Millisecond):
+	}
+	if err := client.ClearMaintenance(ctx); err != nil {
+		t.Fatalf("ClearMaintenance() error = %v", err)
+	}
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job not consumed after maintenance")
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Consume() did not return after the drain timeout")
+	}
+
+	// The unfinished job is back on the queue
+	if depth, _ := client.Depth(ctx); depth.Ready != 1 || depth.InFlight != 0 {
+		t.Errorf("Depth() = %+v, want the unfinished job ready", *depth)
+	}
+}
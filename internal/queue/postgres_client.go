@@ -0,0 +1,800 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/config"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/tracing"
+)
+
+// promoteBatchSize is how many due delayed jobs a consumer promotes at a time
+const promoteBatchSize = 500
+
+// Jobs wait in the queue_jobs table. Each ready job has a turn, and consumers
+// take the job with the lowest turn, so a job's turn is its place in the
+// rotation across campaign lanes: one past the last job waiting in its
+// campaign's lane, but never ahead of the first job waiting in any lane. A
+// campaign queued behind a large one is therefore interleaved with it rather
+// than served after it. Delayed jobs have no turn until they are due.
+//
+// nextTurnSQL computes the turn of a job joining the lane of the campaign ID
+// given by the expression %[1]s. Queries using it must pass the queue name as $1.
+const nextTurnSQL = `GREATEST(
+		(SELECT MAX(w.turn) + 1 FROM queue_jobs w
+			WHERE w.queue = $1 AND w.campaign_id = %[1]s AND w.consumer IS NULL AND w.turn IS NOT NULL),
+		(SELECT MIN(w.turn) FROM queue_jobs w
+			WHERE w.queue = $1 AND w.consumer IS NULL AND w.turn IS NOT NULL),
+		0)`
+
+var (
+	// enqueueQuery adds a ready job to the back of its campaign's lane
+	enqueueQuery = `
+		INSERT INTO queue_jobs (queue, campaign_id, payload, ready_at, turn)
+		VALUES ($1, $2, $3, $4, ` + fmt.Sprintf(nextTurnSQL, "$2") + `)`
+
+	// promoteQuery gives up to $2 due delayed jobs their turn. Rows locked by
+	// another consumer promoting at the same time are skipped, so no job is
+	// promoted twice.
+	promoteQuery = `
+		UPDATE queue_jobs j
+		SET turn = ` + fmt.Sprintf(nextTurnSQL, "j.campaign_id") + `
+		WHERE j.id IN (
+			SELECT id FROM queue_jobs
+			WHERE queue = $1 AND turn IS NULL AND ready_at <= NOW()
+			ORDER BY ready_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)`
+
+	// nackQuery returns job $2 held by consumer $3 to the back of its lane. A
+	// job no longer held by the consumer has already been returned by the
+	// reaper and is left alone.
+	nackQuery = `
+		UPDATE queue_jobs j
+		SET consumer = NULL, taken_at = NULL, turn = ` + fmt.Sprintf(nextTurnSQL, "j.campaign_id") + `
+		WHERE j.queue = $1 AND j.id = $2 AND j.consumer = $3`
+)
+
+// takeQuery hands the ready job with the lowest turn to consumer $2. Jobs
+// being taken by other consumers are locked and skipped, so consumers never
+// wait on each other.
+const takeQuery = `
+	UPDATE queue_jobs SET consumer = $2, taken_at = NOW()
+	WHERE id = (
+		SELECT id FROM queue_jobs
+		WHERE queue = $1 AND consumer IS NULL AND turn IS NOT NULL
+		ORDER BY turn, id
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING id, payload`
+
+// postgresClient implements Client on the Postgres database. Consumers
+// register in queue_consumers and renew a heartbeat there while running; the
+// jobs of a consumer that misses it are returned to the queue by the others.
+type postgresClient struct {
+	db                *sql.DB
+	queueName         string
+	visibilityTimeout time.Duration
+	drainTimeout      time.Duration
+	logger            *slog.Logger
+
+	// inFlight maps every job handed out by Consume to its row
+	inFlight sync.Map // *models.MessageJob -> postgresDelivery
+}
+
+// postgresDelivery is the row of a job in flight and the consumer holding it
+type postgresDelivery struct {
+	id       int64
+	consumer string
+}
+
+// PostgresConfig holds the settings of the Postgres queue
+type PostgresConfig struct {
+	QueueName string
+	// VisibilityTimeout is how long a consumer may go without renewing its
+	// heartbeat before its in-flight jobs are returned to the queue
+	VisibilityTimeout time.Duration
+	// DrainTimeout is how long a stopping consumer waits for its in-flight
+	// jobs before returning them to the queue
+	DrainTimeout time.Duration
+}
+
+// PostgresConfigFor returns the Postgres queue settings for cfg
+func PostgresConfigFor(cfg config.QueueConfig) PostgresConfig {
+	return PostgresConfig{
+		QueueName:         cfg.QueueName,
+		VisibilityTimeout: cfg.VisibilityTimeout,
+		DrainTimeout:      cfg.DrainTimeout,
+	}
+}
+
+// NewPostgresClient creates a queue client keeping its jobs in the database.
+// The database stays open when the client is closed.
+func NewPostgresClient(db *sql.DB, cfg PostgresConfig, logger *slog.Logger) (Client, error) {
+	if db == nil {
+		return nil, errors.New("the Postgres queue needs a database connection")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Fail at startup rather than on the first publish when the queue
+	// tables have not been migrated
+	if _, err := db.ExecContext(ctx, `SELECT 1 FROM queue_jobs LIMIT 0`); err != nil {
+		return nil, fmt.Errorf("failed to read queue_jobs (is migration 051 applied?): %w", err)
+	}
+
+	logger.Info("using Postgres queue", slog.String("queue", cfg.QueueName))
+
+	visibilityTimeout := cfg.VisibilityTimeout
+	if visibilityTimeout <= 0 {
+		visibilityTimeout = defaultVisibilityTimeout
+	}
+	drainTimeout := cfg.DrainTimeout
+	if drainTimeout <= 0 {
+		drainTimeout = defaultDrainTimeout
+	}
+
+	return &postgresClient{
+		db:                db,
+		queueName:         cfg.QueueName,
+		visibilityTimeout: visibilityTimeout,
+		drainTimeout:      drainTimeout,
+		logger:            logger,
+	}, nil
+}
+
+// Publish adds a job to the back of its campaign's lane
+func (c *postgresClient) Publish(ctx context.Context, job *models.MessageJob) error {
+	now := time.Now()
+	data, err := EncodeJob(traced(ctx, readyAt(job, now)))
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.db.ExecContext(ctx, enqueueQuery, c.queueName, max(job.CampaignID, 0), string(data), now); err != nil {
+		return fmt.Errorf("failed to push job to queue: %w", err)
+	}
+
+	c.logger.Debug("job published to queue",
+		slog.Int64("message_id", job.OutboundMessageID),
+	)
+
+	return nil
+}
+
+// PublishDelayed adds a job without a turn; consumers give it one once at has
+// passed. The job is ready from that time on, so waiting for it is not counted
+// as lag.
+func (c *postgresClient) PublishDelayed(ctx context.Context, job *models.MessageJob, at time.Time) error {
+	data, err := EncodeJob(traced(ctx, readyAt(job, at)))
+	if err != nil {
+		return err
+	}
+
+	_, err = c.db.ExecContext(ctx, `
+		INSERT INTO queue_jobs (queue, campaign_id, payload, ready_at)
+		VALUES ($1, $2, $3, $4)`,
+		c.queueName, max(job.CampaignID, 0), string(data), at,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to publish delayed job: %w", err)
+	}
+
+	c.logger.Debug("job scheduled",
+		slog.Int64("message_id", job.OutboundMessageID),
+		slog.Time("at", at),
+	)
+
+	return nil
+}
+
+// promoteDelayed periodically gives due delayed jobs their turn until ctx is done
+func (c *postgresClient) promoteDelayed(ctx context.Context) {
+	ticker := time.NewTicker(delayedPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			result, err := c.db.ExecContext(ctx, promoteQuery, c.queueName, promoteBatchSize)
+			if err != nil {
+				if ctx.Err() == nil {
+					c.logger.Error("failed to promote delayed jobs", slog.String("error", err.Error()))
+				}
+				continue
+			}
+			if promoted, _ := result.RowsAffected(); promoted > 0 {
+				c.logger.Debug("delayed jobs promoted", slog.Int64("count", promoted))
+			}
+		}
+	}
+}
+
+// Consume receives messages from the queue and processes them with the handler,
+// as the Redis client does: up to concurrency at once (max 5), lanes in turn,
+// none while in maintenance. Once ctx is done no more jobs are taken; Consume
+// waits up to the drain timeout for the jobs in flight, then cancels any still
+// running, returns them to the queue and returns.
+//
+// A taken job stays in queue_jobs, marked with this consumer, until its
+// handler has returned, so a crash mid-job leaves it for the reaper instead of
+// losing it.
+func (c *postgresClient) Consume(ctx context.Context, handler MessageHandler, concurrency int) error {
+	concurrency = min(max(concurrency, 1), 5)
+
+	consumer := newConsumerID()
+	if err := c.registerConsumer(ctx, consumer); err != nil {
+		return err
+	}
+
+	c.logger.Info("starting queue consumer",
+		slog.String("queue", c.queueName),
+		slog.String("consumer", consumer),
+		slog.Int("concurrency", concurrency),
+	)
+
+	// The heartbeat outlives ctx until in-flight jobs are done, so they are
+	// not reaped while still finishing during shutdown
+	heartbeatCtx, stopHeartbeat := context.WithCancel(context.WithoutCancel(ctx))
+	go c.heartbeat(heartbeatCtx, consumer)
+
+	// Give delayed jobs their turn as they become due
+	go c.promoteDelayed(ctx)
+
+	// Return the in-flight jobs of consumers that stopped renewing their heartbeat
+	go c.reapStale(ctx)
+
+	// Jobs run under their own context, which outlives ctx until the drain
+	// timeout, so a shutdown lets them finish instead of failing mid-send
+	jobsCtx, cancelJobs := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelJobs()
+
+	semaphore := make(chan struct{}, concurrency)
+	var running sync.WaitGroup
+
+	acquire := func() bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case semaphore <- struct{}{}:
+			if ctx.Err() != nil {
+				<-semaphore
+				return false
+			}
+			return true
+		}
+	}
+	release := func() { <-semaphore }
+
+	// drain waits up to the drain timeout for in-flight jobs to complete, then
+	// deregisters the consumer, returning the jobs still running to the queue
+	drain := func() {
+		done := make(chan struct{})
+		go func() {
+			running.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+			c.logger.Info("all in-flight jobs completed")
+		case <-time.After(c.drainTimeout):
+			c.logger.Warn("drain timeout reached, returning unfinished jobs to the queue",
+				slog.Duration("drain_timeout", c.drainTimeout),
+				slog.Int("in_flight", len(semaphore)),
+			)
+			cancelJobs()
+		}
+		stopHeartbeat()
+		c.unregisterConsumer(ctx, consumer)
+	}
+
+	paused := false
+	var maintenanceCheckedAt time.Time
+
+	for {
+		if !acquire() {
+			c.logger.Info("consumer stopped by context, waiting for in-flight jobs to complete",
+				slog.Duration("drain_timeout", c.drainTimeout),
+			)
+			drain()
+			return ctx.Err()
+		}
+
+		if time.Since(maintenanceCheckedAt) >= maintenancePollInterval {
+			paused = c.maintenancePaused(ctx, paused)
+			maintenanceCheckedAt = time.Now()
+		}
+		if paused {
+			release()
+			select {
+			case <-ctx.Done():
+			case <-time.After(maintenancePollInterval):
+			}
+			continue
+		}
+
+		id, payload, err := c.take(ctx, consumer)
+		if err != nil {
+			release()
+			if errors.Is(err, sql.ErrNoRows) {
+				// No jobs ready; look again shortly
+				select {
+				case <-ctx.Done():
+				case <-time.After(idlePollInterval):
+				}
+				continue
+			}
+			if ctx.Err() != nil {
+				continue
+			}
+			c.logger.Error("failed to take job from queue", slog.String("error", err.Error()))
+			// Sleep briefly to avoid tight loop on persistent errors
+			time.Sleep(1 * time.Second)
+			continue
+		}
+
+		// Deserialize job, upgrading payloads written by older builds
+		job, err := DecodeJob([]byte(payload))
+		if err != nil {
+			release()
+			c.logger.Error("failed to decode job, quarantining payload",
+				slog.String("error", err.Error()),
+				slog.String("data", payload),
+			)
+			c.quarantine(ctx, payload, err)
+			c.settle(ctx, id)
+			continue
+		}
+		c.inFlight.Store(job, postgresDelivery{id: id, consumer: consumer})
+
+		c.logger.Debug("job received from queue",
+			slog.Int64("message_id", job.OutboundMessageID),
+			slog.Int("version", job.Version),
+		)
+
+		running.Add(1)
+		go func(job *models.MessageJob) {
+			defer running.Done()
+			defer release()
+
+			// Settle with a fresh context so a shutdown in progress doesn't strand the job
+			sctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+			defer cancel()
+
+			jobCtx, span := c.startProcessSpan(jobsCtx, job)
+			err := c.handle(jobCtx, handler, job, payload)
+			tracing.End(span, err)
+			if err != nil {
+				c.logger.Error("handler failed to process job",
+					slog.Int64("message_id", job.OutboundMessageID),
+					slog.String("error", err.Error()),
+				)
+				if errors.Is(err, ErrRequeue) {
+					if err := c.Nack(sctx, job); err != nil {
+						c.logger.Error("failed to requeue job",
+							slog.Int64("message_id", job.OutboundMessageID),
+							slog.String("error", err.Error()),
+						)
+					}
+					return
+				}
+			}
+
+			if err := c.Ack(sctx, job); err != nil {
+				c.logger.Error("failed to ack job",
+					slog.Int64("message_id", job.OutboundMessageID),
+					slog.String("error", err.Error()),
+				)
+			}
+		}(job)
+	}
+}
+
+// take runs takeQuery, returning sql.ErrNoRows when no job is ready
+func (c *postgresClient) take(ctx context.Context, consumer string) (int64, string, error) {
+	var id int64
+	var payload string
+	err := c.db.QueryRowContext(ctx, takeQuery, c.queueName, consumer).Scan(&id, &payload)
+	return id, payload, err
+}
+
+// Ack deletes a job handed out by Consume. A job the reaper already returned
+// to the queue is left there, to be handled again.
+func (c *postgresClient) Ack(ctx context.Context, job *models.MessageJob) error {
+	value, ok := c.inFlight.LoadAndDelete(job)
+	if !ok {
+		return ErrNotInFlight
+	}
+	d := value.(postgresDelivery)
+
+	if _, err := c.db.ExecContext(ctx, `DELETE FROM queue_jobs WHERE id = $1 AND consumer = $2`, d.id, d.consumer); err != nil {
+		return fmt.Errorf("failed to ack job: %w", err)
+	}
+	return nil
+}
+
+// Nack returns a job handed out by Consume to the back of its lane
+func (c *postgresClient) Nack(ctx context.Context, job *models.MessageJob) error {
+	value, ok := c.inFlight.LoadAndDelete(job)
+	if !ok {
+		return ErrNotInFlight
+	}
+	d := value.(postgresDelivery)
+
+	if _, err := c.db.ExecContext(ctx, nackQuery, c.queueName, d.id, d.consumer); err != nil {
+		return fmt.Errorf("failed to nack job: %w", err)
+	}
+	return nil
+}
+
+// settle deletes a job that never reached a handler (e.g. it was quarantined)
+func (c *postgresClient) settle(ctx context.Context, id int64) {
+	sctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	if _, err := c.db.ExecContext(sctx, `DELETE FROM queue_jobs WHERE id = $1`, id); err != nil {
+		c.logger.Error("failed to remove job from queue", slog.String("error", err.Error()))
+	}
+}
+
+// registerConsumer adds the consumer with a fresh heartbeat
+func (c *postgresClient) registerConsumer(ctx context.Context, consumer string) error {
+	if err := c.beat(ctx, consumer); err != nil {
+		return fmt.Errorf("failed to register consumer: %w", err)
+	}
+	return nil
+}
+
+// beat renews the consumer's heartbeat, registering it again if a reaper
+// took it for dead
+func (c *postgresClient) beat(ctx context.Context, consumer string) error {
+	_, err := c.db.ExecContext(ctx, `
+		INSERT INTO queue_consumers (consumer, queue, heartbeat_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (consumer) DO UPDATE SET heartbeat_at = NOW()`,
+		consumer, c.queueName,
+	)
+	return err
+}
+
+// unregisterConsumer returns the jobs the consumer still holds (those still
+// running after the drain timeout, or whose ack failed) to the queue and
+// removes the consumer
+func (c *postgresClient) unregisterConsumer(ctx context.Context, consumer string) {
+	uctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	result, err := c.db.ExecContext(uctx, `
+		WITH gone AS (
+			DELETE FROM queue_consumers WHERE consumer = $2
+		)
+		UPDATE queue_jobs SET consumer = NULL, taken_at = NULL
+		WHERE queue = $1 AND consumer = $2`,
+		c.queueName, consumer,
+	)
+	if err != nil {
+		c.logger.Error("failed to unregister consumer",
+			slog.String("consumer", consumer),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	if returned, _ := result.RowsAffected(); returned > 0 {
+		c.logger.Warn("returned unacknowledged jobs to the queue",
+			slog.String("consumer", consumer),
+			slog.Int64("count", returned),
+		)
+	}
+}
+
+// heartbeat renews the consumer's heartbeat until ctx is done, three times
+// per visibility timeout so one slow round trip does not get its jobs reaped
+func (c *postgresClient) heartbeat(ctx context.Context, consumer string) {
+	ticker := time.NewTicker(c.visibilityTimeout / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.beat(ctx, consumer); err != nil && ctx.Err() == nil {
+				c.logger.Error("failed to renew consumer heartbeat", slog.String("error", err.Error()))
+			}
+		}
+	}
+}
+
+// reapStale periodically removes the consumers whose heartbeat is older than
+// the visibility timeout, e.g. because their process crashed, and returns
+// their jobs to the queue, keeping their turn, until ctx is done
+func (c *postgresClient) reapStale(ctx context.Context) {
+	ticker := time.NewTicker(c.visibilityTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			result, err := c.db.ExecContext(ctx, `
+				WITH stale AS (
+					DELETE FROM queue_consumers
+					WHERE queue = $1 AND heartbeat_at < NOW() - make_interval(secs => $2)
+					RETURNING consumer
+				)
+				UPDATE queue_jobs SET consumer = NULL, taken_at = NULL
+				WHERE consumer IN (SELECT consumer FROM stale)`,
+				c.queueName, c.visibilityTimeout.Seconds(),
+			)
+			if err != nil {
+				if ctx.Err() == nil {
+					c.logger.Error("failed to reap stale consumers", slog.String("error", err.Error()))
+				}
+				continue
+			}
+			if returned, _ := result.RowsAffected(); returned > 0 {
+				c.logger.Warn("returned stale in-flight jobs to the queue",
+					slog.Int64("count", returned),
+				)
+			}
+		}
+	}
+}
+
+// startProcessSpan starts the span of processing a job, linked to the span
+// that published it, as the Redis client does
+func (c *postgresClient) startProcessSpan(ctx context.Context, job *models.MessageJob) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "process "+c.queueName,
+		trace.WithNewRoot(),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithLinks(tracing.Link(job.TraceContext)),
+		trace.WithAttributes(
+			semconv.MessagingSystemKey.String("postgresql"),
+			semconv.MessagingOperationTypeProcess,
+			semconv.MessagingDestinationName(c.queueName),
+			attribute.Int64("outbound_message_id", job.OutboundMessageID),
+		),
+	)
+}
+
+// handle runs the handler for a job, quarantining the job if it panics
+func (c *postgresClient) handle(ctx context.Context, handler MessageHandler, job *models.MessageJob, payload string) (err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+
+		stack := debug.Stack()
+		c.logger.Error("handler panicked",
+			slog.Int64("message_id", job.OutboundMessageID),
+			slog.Any("panic", r),
+			slog.String("stack", string(stack)),
+		)
+
+		err = fmt.Errorf("handler panic: %v", r)
+		c.quarantine(ctx, payload, fmt.Errorf("%w\n%s", err, stack))
+	}()
+
+	return handler(ctx, job)
+}
+
+// quarantine keeps a payload that could not be handled instead of dropping it
+func (c *postgresClient) quarantine(ctx context.Context, payload string, cause error) {
+	// Use a fresh context so a shutdown in progress doesn't lose the payload
+	qctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	_, err := c.db.ExecContext(qctx,
+		`INSERT INTO queue_quarantine (queue, payload, error) VALUES ($1, $2, $3)`,
+		c.queueName, payload, cause.Error(),
+	)
+	if err != nil {
+		c.logger.Error("failed to quarantine payload",
+			slog.String("error", err.Error()),
+			slog.String("data", payload),
+		)
+	}
+}
+
+// ListQuarantined returns up to limit quarantined payloads, newest first
+func (c *postgresClient) ListQuarantined(ctx context.Context, limit int) ([]QuarantinedJob, error) {
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT payload, error, quarantined_at
+		FROM queue_quarantine
+		WHERE queue = $1
+		ORDER BY id DESC
+		LIMIT $2`,
+		c.queueName, max(limit, 1),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read quarantine: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := []QuarantinedJob{}
+	for rows.Next() {
+		var job QuarantinedJob
+		if err := rows.Scan(&job.Payload, &job.Error, &job.QuarantinedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan quarantined job: %w", err)
+		}
+		job.QuarantinedAt = job.QuarantinedAt.UTC()
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read quarantine: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// PurgeQuarantine deletes the queue's quarantined payloads
+func (c *postgresClient) PurgeQuarantine(ctx context.Context) (int64, error) {
+	result, err := c.db.ExecContext(ctx, `DELETE FROM queue_quarantine WHERE queue = $1`, c.queueName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge quarantine: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// RemoveCampaignJobs deletes the campaign's ready and delayed jobs. Jobs
+// published without a campaign ID are never removed.
+func (c *postgresClient) RemoveCampaignJobs(ctx context.Context, campaignID int64) (int64, error) {
+	if campaignID <= 0 {
+		return 0, nil
+	}
+
+	result, err := c.db.ExecContext(ctx, `
+		DELETE FROM queue_jobs
+		WHERE queue = $1 AND campaign_id = $2 AND consumer IS NULL`,
+		c.queueName, campaignID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove campaign jobs: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// maintenancePaused reads the maintenance flag and logs transitions. If the
+// flag cannot be read the previous state is kept.
+func (c *postgresClient) maintenancePaused(ctx context.Context, paused bool) bool {
+	maintenance, err := c.Maintenance(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			c.logger.Error("failed to read maintenance flag", slog.String("error", err.Error()))
+		}
+		return paused
+	}
+
+	if maintenance.Enabled && !paused {
+		c.logger.Warn("maintenance mode on, consumer paused",
+			slog.String("queue", c.queueName),
+			slog.String("reason", maintenance.Reason),
+		)
+	}
+	if !maintenance.Enabled && paused {
+		c.logger.Info("maintenance mode off, consumer resumed",
+			slog.String("queue", c.queueName),
+		)
+	}
+
+	return maintenance.Enabled
+}
+
+// Maintenance returns the maintenance flag; a missing row means maintenance is off
+func (c *postgresClient) Maintenance(ctx context.Context) (*Maintenance, error) {
+	maintenance := &Maintenance{}
+	var startedAt time.Time
+	err := c.db.QueryRowContext(ctx,
+		`SELECT reason, started_at FROM queue_maintenance WHERE queue = $1`,
+		c.queueName,
+	).Scan(&maintenance.Reason, &startedAt)
+	if err == sql.ErrNoRows {
+		return maintenance, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read maintenance flag: %w", err)
+	}
+
+	startedAt = startedAt.UTC()
+	maintenance.Enabled = true
+	maintenance.StartedAt = &startedAt
+	return maintenance, nil
+}
+
+// SetMaintenance stores the maintenance flag. Enabling it again keeps the
+// original start time so operators can see how long maintenance has lasted.
+func (c *postgresClient) SetMaintenance(ctx context.Context, reason string) (*Maintenance, error) {
+	maintenance := &Maintenance{Enabled: true}
+	var startedAt time.Time
+	err := c.db.QueryRowContext(ctx, `
+		INSERT INTO queue_maintenance (queue, reason, started_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (queue) DO UPDATE SET reason = EXCLUDED.reason
+		RETURNING reason, started_at`,
+		c.queueName, reason,
+	).Scan(&maintenance.Reason, &startedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set maintenance flag: %w", err)
+	}
+
+	startedAt = startedAt.UTC()
+	maintenance.StartedAt = &startedAt
+	return maintenance, nil
+}
+
+// ClearMaintenance deletes the maintenance flag
+func (c *postgresClient) ClearMaintenance(ctx context.Context) error {
+	if _, err := c.db.ExecContext(ctx, `DELETE FROM queue_maintenance WHERE queue = $1`, c.queueName); err != nil {
+		return fmt.Errorf("failed to clear maintenance flag: %w", err)
+	}
+	return nil
+}
+
+// Close does nothing; the database belongs to the caller
+func (c *postgresClient) Close() error {
+	return nil
+}
+
+// Health checks if the database is reachable
+func (c *postgresClient) Health(ctx context.Context) error {
+	if err := c.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("Postgres queue health check failed: %w", err)
+	}
+	return nil
+}
+
+// Depth counts the queue's ready, delayed, in-flight and quarantined jobs
+func (c *postgresClient) Depth(ctx context.Context) (*Depth, error) {
+	depth := &Depth{}
+	err := c.db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*) FILTER (WHERE consumer IS NULL AND turn IS NOT NULL),
+			COUNT(*) FILTER (WHERE turn IS NULL),
+			COUNT(*) FILTER (WHERE consumer IS NOT NULL),
+			(SELECT COUNT(*) FROM queue_quarantine WHERE queue = $1)
+		FROM queue_jobs
+		WHERE queue = $1`,
+		c.queueName,
+	).Scan(&depth.Ready, &depth.Delayed, &depth.InFlight, &depth.Quarantined)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get queue depth: %w", err)
+	}
+	return depth, nil
+}
+
+// Lag returns how long the longest waiting ready job has waited since it
+// became ready
+func (c *postgresClient) Lag(ctx context.Context) (time.Duration, error) {
+	var oldest sql.NullTime
+	err := c.db.QueryRowContext(ctx, `
+		SELECT MIN(ready_at) FROM queue_jobs
+		WHERE queue = $1 AND consumer IS NULL AND turn IS NOT NULL`,
+		c.queueName,
+	).Scan(&oldest)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read oldest job: %w", err)
+	}
+	if !oldest.Valid {
+		return 0, nil
+	}
+	return max(time.Since(oldest.Time), 0), nil
+}
@@ -0,0 +1,362 @@
+//go:build integration
+
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// These tests run the Postgres client against a real database, the way the
+// Redis client tests run against miniredis. They need a migrated database:
+//
+//	TEST_DATABASE_URL=postgres://... go test -tags integration ./internal/queue/...
+//
+// Each test uses its own queue name and deletes its rows afterwards, so the
+// database may be shared with a local development stack.
+
+// newPostgresTestClient connects a client to TEST_DATABASE_URL on a queue of
+// its own, skipping the test when no database is configured
+func newPostgresTestClient(t *testing.T) (Client, *sql.DB, string) {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	queueName := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		for _, table := range []string{"queue_jobs", "queue_consumers", "queue_quarantine", "queue_maintenance"} {
+			_, _ = db.Exec(`DELETE FROM `+table+` WHERE queue = $1`, queueName)
+		}
+	})
+
+	client, err := NewPostgresClient(db, PostgresConfig{QueueName: queueName}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewPostgresClient() error = %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	return client, db, queueName
+}
+
+func TestPostgresClient_Depth(t *testing.T) {
+	client, db, queueName := newPostgresTestClient(t)
+
+	ctx := context.Background()
+	for id := int64(1); id <= 3; id++ {
+		if err := client.Publish(ctx, &models.MessageJob{OutboundMessageID: id}); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	if err := client.PublishDelayed(ctx, &models.MessageJob{OutboundMessageID: 4}, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("PublishDelayed() error = %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO queue_quarantine (queue, payload, error) VALUES ($1, 'garbage', 'bad')`, queueName); err != nil {
+		t.Fatalf("failed to quarantine payload: %v", err)
+	}
+
+	depth, err := client.Depth(ctx)
+	if err != nil {
+		t.Fatalf("Depth() error = %v", err)
+	}
+	if *depth != (Depth{Ready: 3, Delayed: 1, Quarantined: 1}) {
+		t.Errorf("Depth() = %+v, want ready 3, delayed 1, quarantined 1", *depth)
+	}
+}
+
+func TestPostgresClient_RemoveCampaignJobs(t *testing.T) {
+	client, _, _ := newPostgresTestClient(t)
+
+	ctx := context.Background()
+	for id := int64(1); id <= 6; id++ {
+		if err := client.Publish(ctx, &models.MessageJob{OutboundMessageID: id, CampaignID: 1 + id%2}); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	if err := client.PublishDelayed(ctx, &models.MessageJob{OutboundMessageID: 7, CampaignID: 2}, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("PublishDelayed() error = %v", err)
+	}
+	// Jobs without a campaign are left alone
+	if err := client.Publish(ctx, &models.MessageJob{OutboundMessageID: 8}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	removed, err := client.RemoveCampaignJobs(ctx, 2)
+	if err != nil {
+		t.Fatalf("RemoveCampaignJobs() error = %v", err)
+	}
+	if removed != 4 {
+		t.Errorf("RemoveCampaignJobs() = %d, want 4", removed)
+	}
+
+	depth, err := client.Depth(ctx)
+	if err != nil {
+		t.Fatalf("Depth() error = %v", err)
+	}
+	if *depth != (Depth{Ready: 4}) {
+		t.Errorf("Depth() = %+v, want the other campaign's 3 jobs and the unassigned one", *depth)
+	}
+}
+
+func TestPostgresClient_ConsumeServesCampaignsInTurn(t *testing.T) {
+	client, _, _ := newPostgresTestClient(t)
+
+	// A large campaign is queued first, then a small one behind it
+	ctx := context.Background()
+	const large, small = int64(123456789012), int64(7)
+	for id := int64(1); id <= 6; id++ {
+		if err := client.Publish(ctx, &models.MessageJob{OutboundMessageID: id, CampaignID: large}); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	for id := int64(7); id <= 8; id++ {
+		if err := client.Publish(ctx, &models.MessageJob{OutboundMessageID: id, CampaignID: small}); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+
+	handled := make(chan *models.MessageJob, 8)
+	consumeCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Consume(consumeCtx, func(ctx context.Context, job *models.MessageJob) error {
+			handled <- job
+			return nil
+		}, 1)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	var order []int64
+	for len(order) < 8 {
+		select {
+		case job := <-handled:
+			order = append(order, job.OutboundMessageID)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("consumed %v, want 8 jobs", order)
+		}
+	}
+
+	// The lanes alternate until the small campaign's are done; each lane keeps its own order
+	want := []int64{1, 7, 2, 8, 3, 4, 5, 6}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("consumed in order %v, want %v", order, want)
+		}
+	}
+}
+
+func TestPostgresClient_ConsumeAcksAndNacks(t *testing.T) {
+	client, db, queueName := newPostgresTestClient(t)
+
+	ctx := context.Background()
+	if err := client.Publish(ctx, &models.MessageJob{OutboundMessageID: 1}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	// The first attempt asks for a requeue, the second succeeds
+	attempts := make(chan int64, 2)
+	var calls atomic.Int32
+	consumeCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Consume(consumeCtx, func(ctx context.Context, job *models.MessageJob) error {
+			attempts <- job.OutboundMessageID
+			if calls.Add(1) == 1 {
+				return fmt.Errorf("provider busy: %w", ErrRequeue)
+			}
+			return nil
+		}, 1)
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-attempts:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("job consumed %d times, want 2", i)
+		}
+	}
+
+	// Give the second ack time to land before checking
+	time.Sleep(100 * time.Millisecond)
+	depth, err := client.Depth(ctx)
+	if err != nil {
+		t.Fatalf("Depth() error = %v", err)
+	}
+	if depth.Ready != 0 || depth.InFlight != 0 {
+		t.Errorf("Depth() = %+v, want no ready or in-flight jobs after the ack", *depth)
+	}
+
+	cancel()
+	<-done
+
+	var consumers int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM queue_consumers WHERE queue = $1`, queueName).Scan(&consumers); err != nil {
+		t.Fatalf("failed to count consumers: %v", err)
+	}
+	if consumers != 0 {
+		t.Errorf("%d consumers after shutdown, want none", consumers)
+	}
+}
+
+func TestPostgresClient_ConsumePromotesDelayedJobs(t *testing.T) {
+	client, _, _ := newPostgresTestClient(t)
+
+	ctx := context.Background()
+	if err := client.PublishDelayed(ctx, &models.MessageJob{OutboundMessageID: 1}, time.Now()); err != nil {
+		t.Fatalf("PublishDelayed() error = %v", err)
+	}
+	if err := client.PublishDelayed(ctx, &models.MessageJob{OutboundMessageID: 2}, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("PublishDelayed() error = %v", err)
+	}
+
+	handled := make(chan int64, 2)
+	consumeCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Consume(consumeCtx, func(ctx context.Context, job *models.MessageJob) error {
+			handled <- job.OutboundMessageID
+			return nil
+		}, 1)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	select {
+	case id := <-handled:
+		if id != 1 {
+			t.Fatalf("consumed job %d, want the due job 1", id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("due delayed job was not consumed")
+	}
+
+	// Give the ack time to land before checking
+	time.Sleep(100 * time.Millisecond)
+	depth, err := client.Depth(ctx)
+	if err != nil {
+		t.Fatalf("Depth() error = %v", err)
+	}
+	if *depth != (Depth{Delayed: 1}) {
+		t.Errorf("Depth() = %+v, want only the job due in an hour", *depth)
+	}
+}
+
+func TestPostgresClient_ConsumeQuarantines(t *testing.T) {
+	client, db, queueName := newPostgresTestClient(t)
+
+	// An undecodable payload and a job whose handler panics
+	ctx := context.Background()
+	if _, err := db.Exec(`INSERT INTO queue_jobs (queue, payload, ready_at, turn) VALUES ($1, 'garbage', NOW(), 0)`, queueName); err != nil {
+		t.Fatalf("failed to insert payload: %v", err)
+	}
+	if err := client.Publish(ctx, &models.MessageJob{OutboundMessageID: 1}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	consumeCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Consume(consumeCtx, func(ctx context.Context, job *models.MessageJob) error {
+			panic("handler bug")
+		}, 1)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	var depth *Depth
+	for time.Now().Before(deadline) {
+		var err error
+		if depth, err = client.Depth(ctx); err != nil {
+			t.Fatalf("Depth() error = %v", err)
+		}
+		if depth.Quarantined == 2 && depth.Ready == 0 && depth.InFlight == 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if *depth != (Depth{Quarantined: 2}) {
+		t.Fatalf("Depth() = %+v, want both jobs quarantined", *depth)
+	}
+
+	quarantined, err := client.ListQuarantined(ctx, 10)
+	if err != nil {
+		t.Fatalf("ListQuarantined() error = %v", err)
+	}
+	if len(quarantined) != 2 || quarantined[1].Payload != "garbage" {
+		t.Fatalf("ListQuarantined() = %+v, want the panicking job, then the garbage payload", quarantined)
+	}
+
+	purged, err := client.PurgeQuarantine(ctx)
+	if err != nil {
+		t.Fatalf("PurgeQuarantine() error = %v", err)
+	}
+	if purged != 2 {
+		t.Errorf("PurgeQuarantine() = %d, want 2", purged)
+	}
+}
+
+func TestPostgresClient_ConsumePausesDuringMaintenance(t *testing.T) {
+	client, _, _ := newPostgresTestClient(t)
+
+	ctx := context.Background()
+	if _, err := client.SetMaintenance(ctx, "database upgrade"); err != nil {
+		t.Fatalf("SetMaintenance() error = %v", err)
+	}
+	if err := client.Publish(ctx, &models.MessageJob{OutboundMessageID: 1}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	handled := make(chan int64, 1)
+	consumeCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Consume(consumeCtx, func(ctx context.Context, job *models.MessageJob) error {
+			handled <- job.OutboundMessageID
+			return nil
+		}, 1)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	select {
+	case <-handled:
+		t.Fatal("job consumed during maintenance")
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	if err := client.ClearMaintenance(ctx); err != nil {
+		t.Fatalf("ClearMaintenance() error = %v", err)
+	}
+	select {
+	case <-handled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job not consumed after maintenance ended")
+	}
+}
@@ -3,26 +3,220 @@ package queue
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"math/rand/v2"
+	"os"
+	"runtime/debug"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/Raymond9734/campaign-messaging-backend/internal/config"
 	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/tracing"
 )
 
+// quarantineSuffix is appended to the queue name to form the quarantine list key
+const quarantineSuffix = ":quarantine"
+
+// delayedSuffix is appended to the queue name to form the delayed job sorted set key
+const delayedSuffix = ":delayed"
+
+// maintenanceSuffix is appended to the queue name to form the maintenance flag key
+const maintenanceSuffix = ":maintenance"
+
+// maintenancePollInterval is how often consumers re-read the maintenance flag
+const maintenancePollInterval = 1 * time.Second
+
+// delayedPollInterval is how often consumers move due delayed jobs onto the queue
+const delayedPollInterval = 1 * time.Second
+
+// removeScanPage is how many jobs RemoveCampaignJobs reads at a time
+const removeScanPage = 1000
+
+// idlePollInterval is how long a consumer waits before looking for jobs again
+// after finding none ready
+const idlePollInterval = 100 * time.Millisecond
+
+// Ready jobs wait in one lane per campaign, a list named after the queue and
+// the campaign ID, so consumers can take them from each campaign in turn. The
+// lanes list holds the IDs of the lanes with jobs in the order they are
+// served; "0" stands for the shared lane, the queue list itself, which holds
+// jobs without a campaign ID. enqueueLua builds the same key names.
+const (
+	laneSuffix  = ":campaign:"
+	lanesSuffix = ":lanes"
+)
+
+// enqueueLua defines enqueue, which pushes a job payload onto its campaign's
+// lane and puts the lane in rotation. Payloads without a campaign ID go to the
+// shared lane. Scripts using it must pass the queue list as KEYS[1].
+const enqueueLua = `
+local function enqueue(payload)
+	local lane, id = KEYS[1], '0'
+	local ok, job = pcall(cjson.decode, payload)
+	if ok and type(job) == 'table' and type(job.campaign_id) == 'number' and job.campaign_id > 0 then
+		id = string.format('%d', job.campaign_id)
+		lane = KEYS[1] .. ':campaign:' .. id
+	end
+	redis.call('LPUSH', lane, payload)
+	local lanes = KEYS[1] .. ':lanes'
+	if not redis.call('LPOS', lanes, id) then
+		redis.call('LPUSH', lanes, id)
+	end
+end
+`
+
+// enqueueScript pushes ARGV[1] onto its lane.
+//
+// KEYS[1] queue list
+var enqueueScript = redis.NewScript(enqueueLua + `
+enqueue(ARGV[1])
+return 1
+`)
+
+// takeScript moves the next job into a consumer's processing list. It serves
+// the lanes in rotation, one job per lane per turn, so a campaign queued
+// behind a large one still makes progress. A lane found empty leaves the
+// rotation. Jobs on the shared lane that are not in rotation, such as those
+// pushed by builds without lanes, are taken once every lane is empty.
+//
+// KEYS[1] queue list, KEYS[2] lanes list, KEYS[3] processing list
+var takeScript = redis.NewScript(`
+for _ = 1, redis.call('LLEN', KEYS[2]) do
+	local id = redis.call('RPOPLPUSH', KEYS[2], KEYS[2])
+	local lane = KEYS[1]
+	if id ~= '0' then
+		lane = KEYS[1] .. ':campaign:' .. id
+	end
+	local payload = redis.call('RPOPLPUSH', lane, KEYS[3])
+	if redis.call('LLEN', lane) == 0 then
+		redis.call('LREM', KEYS[2], 0, id)
+	end
+	if payload then
+		return payload
+	end
+end
+return redis.call('RPOPLPUSH', KEYS[1], KEYS[3])
+`)
+
+// Consumers take jobs into their own processing list and renew a heartbeat
+// key while running. The consumers set lists every consumer that may still
+// hold in-flight jobs, so a reaper can find the lists of crashed ones.
+const (
+	processingSuffix = ":processing:"
+	heartbeatSuffix  = ":heartbeat:"
+	consumersSuffix  = ":consumers"
+)
+
+// defaultVisibilityTimeout is how long a consumer may miss its heartbeat
+// before its in-flight jobs are returned to the queue
+const defaultVisibilityTimeout = 60 * time.Second
+
+// defaultDrainTimeout is how long a stopping consumer waits for its in-flight
+// jobs to finish
+const defaultDrainTimeout = 30 * time.Second
+
+// promoteDelayedScript atomically moves up to ARGV[2] jobs whose score (ready
+// time in unix ms) is at or before ARGV[1] from the delayed set to their lanes.
+// Running it from several consumers at once never publishes a job twice.
+//
+// KEYS[1] queue list, KEYS[2] delayed set
+var promoteDelayedScript = redis.NewScript(enqueueLua + `
+local due = redis.call('ZRANGEBYSCORE', KEYS[2], '-inf', ARGV[1], 'LIMIT', 0, tonumber(ARGV[2]))
+for _, payload in ipairs(due) do
+	enqueue(payload)
+	redis.call('ZREM', KEYS[2], payload)
+end
+return #due
+`)
+
+// nackScript moves a job from a processing list back onto its lane. A job
+// that is no longer in the processing list has already been returned by the
+// reaper and is not pushed again.
+//
+// KEYS[1] queue list, KEYS[2] processing list
+var nackScript = redis.NewScript(enqueueLua + `
+if redis.call('LREM', KEYS[2], 1, ARGV[1]) > 0 then
+	enqueue(ARGV[1])
+	return 1
+end
+return 0
+`)
+
+// releaseConsumerScript returns every job in a consumer's processing list to
+// its lane and forgets the consumer. Unless ARGV[2] is "1" it does nothing
+// while the consumer's heartbeat is alive, returning -1.
+//
+// KEYS[1] queue list, KEYS[2] heartbeat key, KEYS[3] processing list,
+// KEYS[4] consumers set; ARGV[1] consumer ID
+var releaseConsumerScript = redis.NewScript(enqueueLua + `
+if ARGV[2] ~= '1' and redis.call('EXISTS', KEYS[2]) == 1 then
+	return -1
+end
+local moved = 0
+local payload = redis.call('RPOP', KEYS[3])
+while payload do
+	enqueue(payload)
+	moved = moved + 1
+	payload = redis.call('RPOP', KEYS[3])
+end
+redis.call('DEL', KEYS[2])
+redis.call('SREM', KEYS[4], ARGV[1])
+return moved
+`)
+
+// tracer starts the spans of queue operations
+var tracer = otel.Tracer("github.com/Raymond9734/campaign-messaging-backend/internal/queue")
+
 // redisClient implements Client using Redis
 type redisClient struct {
-	client    *redis.Client
-	queueName string
-	logger    *slog.Logger
+	client            *redis.Client
+	queueName         string
+	visibilityTimeout time.Duration
+	drainTimeout      time.Duration
+	logger            *slog.Logger
+
+	// inFlight maps every job handed out by Consume to where it came from
+	inFlight sync.Map // *models.MessageJob -> delivery
+}
+
+// delivery is the raw payload of a job in flight and the processing list holding it
+type delivery struct {
+	processing string
+	payload    string
 }
 
 // RedisConfig holds Redis configuration
 type RedisConfig struct {
 	URL       string
 	QueueName string
+	// VisibilityTimeout is how long a consumer may go without renewing its
+	// heartbeat before its in-flight jobs are returned to the queue
+	VisibilityTimeout time.Duration
+	// DrainTimeout is how long a stopping consumer waits for its in-flight
+	// jobs before returning them to the queue
+	DrainTimeout time.Duration
+}
+
+// RedisConfigFor returns the queue settings for cfg. The API (producer) and the
+// worker (consumer) both build their client through it so they always agree on
+// the Redis instance and queue name.
+func RedisConfigFor(cfg config.QueueConfig) RedisConfig {
+	return RedisConfig{
+		URL:               cfg.RedisURL,
+		QueueName:         cfg.QueueName,
+		VisibilityTimeout: cfg.VisibilityTimeout,
+		DrainTimeout:      cfg.DrainTimeout,
+	}
 }
 
 // NewRedisClient creates a new Redis queue client
@@ -49,23 +243,35 @@ func NewRedisClient(cfg RedisConfig, logger *slog.Logger) (Client, error) {
 		slog.String("queue", cfg.QueueName),
 	)
 
+	visibilityTimeout := cfg.VisibilityTimeout
+	if visibilityTimeout <= 0 {
+		visibilityTimeout = defaultVisibilityTimeout
+	}
+	drainTimeout := cfg.DrainTimeout
+	if drainTimeout <= 0 {
+		drainTimeout = defaultDrainTimeout
+	}
+
 	return &redisClient{
-		client:    client,
-		queueName: cfg.QueueName,
-		logger:    logger,
+		client:            client,
+		queueName:         cfg.QueueName,
+		visibilityTimeout: visibilityTimeout,
+		drainTimeout:      drainTimeout,
+		logger:            logger,
 	}, nil
 }
 
 // Publish sends a message job to the queue
 func (c *redisClient) Publish(ctx context.Context, job *models.MessageJob) error {
-	// Serialize job to JSON
-	data, err := json.Marshal(job)
+	// Serialize job to JSON, stamped with the current payload version, the
+	// time it became ready and the trace it was published in
+	data, err := EncodeJob(traced(ctx, readyAt(job, time.Now())))
 	if err != nil {
-		return fmt.Errorf("failed to marshal job: %w", err)
+		return err
 	}
 
-	// Push to Redis list (LPUSH for FIFO with BRPOP)
-	if err := c.client.LPush(ctx, c.queueName, data).Err(); err != nil {
+	// Push onto the campaign's lane (LPUSH for FIFO with RPOPLPUSH)
+	if err := enqueueScript.Run(ctx, c.client, []string{c.queueName}, data).Err(); err != nil {
 		return fmt.Errorf("failed to push job to queue: %w", err)
 	}
 
@@ -76,8 +282,68 @@ func (c *redisClient) Publish(ctx context.Context, job *models.MessageJob) error
 	return nil
 }
 
+// PublishDelayed adds a job to the delayed set, scored by when it becomes due.
+// The job is ready from that time on, so waiting for it is not counted as lag.
+func (c *redisClient) PublishDelayed(ctx context.Context, job *models.MessageJob, at time.Time) error {
+	data, err := EncodeJob(traced(ctx, readyAt(job, at)))
+	if err != nil {
+		return err
+	}
+
+	err = c.client.ZAdd(ctx, c.queueName+delayedSuffix, redis.Z{
+		Score:  float64(at.UnixMilli()),
+		Member: data,
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("failed to publish delayed job: %w", err)
+	}
+
+	c.logger.Debug("job scheduled",
+		slog.Int64("message_id", job.OutboundMessageID),
+		slog.Time("at", at),
+	)
+
+	return nil
+}
+
+// promoteDelayed periodically moves due delayed jobs onto the queue until ctx is done
+func (c *redisClient) promoteDelayed(ctx context.Context) {
+	ticker := time.NewTicker(delayedPollInterval)
+	defer ticker.Stop()
+
+	keys := []string{c.queueName, c.queueName + delayedSuffix}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			promoted, err := promoteDelayedScript.Run(ctx, c.client, keys, time.Now().UnixMilli(), 500).Int()
+			if err != nil {
+				if ctx.Err() == nil {
+					c.logger.Error("failed to promote delayed jobs", slog.String("error", err.Error()))
+				}
+				continue
+			}
+			if promoted > 0 {
+				c.logger.Debug("delayed jobs promoted", slog.Int("count", promoted))
+			}
+		}
+	}
+}
+
 // Consume receives messages from the queue and processes them with the handler
-// concurrency controls how many messages can be processed simultaneously (max 5)
+// concurrency controls how many messages can be processed simultaneously (max 5).
+//
+// Once ctx is done no more jobs are taken. Consume waits up to the drain
+// timeout for the jobs in flight, which keep running with a context of their
+// own, then cancels any still running, returns them to the queue and returns.
+//
+// Each job is moved atomically from its lane into this consumer's processing
+// list and only removed once its handler has returned, so a crash mid-job
+// leaves it there for the reaper instead of losing it. Lanes are served in
+// turn, so concurrent campaigns are sent interleaved rather than one after
+// the other.
 func (c *redisClient) Consume(ctx context.Context, handler MessageHandler, concurrency int) error {
 	// Validate concurrency
 	if concurrency < 1 {
@@ -87,86 +353,618 @@ func (c *redisClient) Consume(ctx context.Context, handler MessageHandler, concu
 		concurrency = 5
 	}
 
+	consumer := newConsumerID()
+	processing := c.queueName + processingSuffix + consumer
+
+	if err := c.registerConsumer(ctx, consumer); err != nil {
+		return err
+	}
+
 	c.logger.Info("starting queue consumer",
 		slog.String("queue", c.queueName),
+		slog.String("consumer", consumer),
 		slog.Int("concurrency", concurrency),
 	)
 
-	// Semaphore to limit concurrent processing
+	// The heartbeat outlives ctx until in-flight jobs are done, so they are
+	// not reaped while still finishing during shutdown
+	heartbeatCtx, stopHeartbeat := context.WithCancel(context.WithoutCancel(ctx))
+	go c.heartbeat(heartbeatCtx, consumer)
+
+	// Move delayed jobs onto the queue as they become due
+	go c.promoteDelayed(ctx)
+
+	// Return the in-flight jobs of consumers that stopped renewing their heartbeat
+	go c.reapStale(ctx)
+
+	// Jobs run under their own context, which outlives ctx until the drain
+	// timeout, so a shutdown lets them finish instead of failing mid-send
+	jobsCtx, cancelJobs := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelJobs()
+
+	// Semaphore to limit concurrent processing, and the jobs still running
 	semaphore := make(chan struct{}, concurrency)
+	var running sync.WaitGroup
 
-	for {
+	// acquire waits for a free processing slot, reporting false once ctx is done
+	acquire := func() bool {
 		select {
 		case <-ctx.Done():
-			c.logger.Info("consumer stopped by context, waiting for in-flight jobs to complete")
-			// Wait for all in-flight jobs to complete
-			for i := 0; i < concurrency; i++ {
-				semaphore <- struct{}{}
+			return false
+		case semaphore <- struct{}{}:
+			if ctx.Err() != nil {
+				<-semaphore
+				return false
 			}
+			return true
+		}
+	}
+	release := func() { <-semaphore }
+
+	// drain waits up to the drain timeout for in-flight jobs to complete, then
+	// deregisters the consumer. Jobs still running by then are canceled and
+	// returned to the queue.
+	drain := func() {
+		done := make(chan struct{})
+		go func() {
+			running.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
 			c.logger.Info("all in-flight jobs completed")
+		case <-time.After(c.drainTimeout):
+			c.logger.Warn("drain timeout reached, returning unfinished jobs to the queue",
+				slog.Duration("drain_timeout", c.drainTimeout),
+				slog.Int("in_flight", len(semaphore)),
+			)
+			cancelJobs()
+		}
+		stopHeartbeat()
+		c.unregisterConsumer(ctx, consumer)
+	}
+
+	// Maintenance flag, re-read at most once per maintenancePollInterval
+	paused := false
+	var maintenanceCheckedAt time.Time
+
+	for {
+		// A slot is acquired before a job is taken, so no job waits in the
+		// processing list for a handler, and none is taken once ctx is done
+		if !acquire() {
+			c.logger.Info("consumer stopped by context, waiting for in-flight jobs to complete",
+				slog.Duration("drain_timeout", c.drainTimeout),
+			)
+			drain()
 			return ctx.Err()
+		}
 
-		default:
-			// Blocking pop from Redis list (blocks for 1 second if empty)
-			result, err := c.client.BRPop(ctx, 1*time.Second, c.queueName).Result()
-			if err != nil {
-				if err == redis.Nil {
-					// Timeout, no messages available - continue
-					continue
-				}
-				if err == context.Canceled || err == context.DeadlineExceeded {
-					c.logger.Info("consumer stopped by context")
-					// Wait for all in-flight jobs to complete
-					for i := 0; i < concurrency; i++ {
-						semaphore <- struct{}{}
-					}
-					return err
+		if time.Since(maintenanceCheckedAt) >= maintenancePollInterval {
+			paused = c.maintenancePaused(ctx, paused)
+			maintenanceCheckedAt = time.Now()
+		}
+		if paused {
+			// Leave jobs on the queue until maintenance ends
+			release()
+			select {
+			case <-ctx.Done():
+			case <-time.After(maintenancePollInterval):
+			}
+			continue
+		}
+
+		// Move the next job in rotation into the processing list
+		payload, err := c.take(ctx, processing)
+		if err != nil {
+			release()
+			if err == redis.Nil {
+				// No jobs ready; look again shortly
+				select {
+				case <-ctx.Done():
+				case <-time.After(idlePollInterval):
 				}
-				c.logger.Error("failed to pop from queue", slog.String("error", err.Error()))
-				// Sleep briefly to avoid tight loop on persistent errors
-				time.Sleep(1 * time.Second)
 				continue
 			}
+			if err == context.Canceled || err == context.DeadlineExceeded {
+				c.logger.Info("consumer stopped by context")
+				drain()
+				return err
+			}
+			c.logger.Error("failed to pop from queue", slog.String("error", err.Error()))
+			// Sleep briefly to avoid tight loop on persistent errors
+			time.Sleep(1 * time.Second)
+			continue
+		}
 
-			// BRPOP returns [queueName, value]
-			if len(result) < 2 {
-				c.logger.Error("unexpected BRPOP result format")
-				continue
+		// Deserialize job, upgrading payloads written by older builds
+		job, err := DecodeJob([]byte(payload))
+		if err != nil {
+			release()
+			c.logger.Error("failed to decode job, quarantining payload",
+				slog.String("error", err.Error()),
+				slog.String("data", payload),
+			)
+			c.quarantine(ctx, payload, err)
+			c.settle(ctx, processing, payload)
+			continue
+		}
+		c.inFlight.Store(job, delivery{processing: processing, payload: payload})
+
+		c.logger.Debug("job received from queue",
+			slog.Int64("message_id", job.OutboundMessageID),
+			slog.Int("version", job.Version),
+		)
+
+		// Process job concurrently in a goroutine
+		running.Add(1)
+		go func(job *models.MessageJob) {
+			defer running.Done()
+			defer release() // Release semaphore slot when done
+
+			// Settle with a fresh context so a shutdown in progress doesn't strand the job
+			sctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+			defer cancel()
+
+			// Process job with handler, in a span linked to the request that queued it
+			jobCtx, span := c.startProcessSpan(jobsCtx, job)
+			err := c.handle(jobCtx, handler, job)
+			tracing.End(span, err)
+			if err != nil {
+				c.logger.Error("handler failed to process job",
+					slog.Int64("message_id", job.OutboundMessageID),
+					slog.String("error", err.Error()),
+				)
+				// Retry logic is handled by the worker/handler, which may ask for a requeue
+				if errors.Is(err, ErrRequeue) {
+					if err := c.Nack(sctx, job); err != nil {
+						c.logger.Error("failed to requeue job",
+							slog.Int64("message_id", job.OutboundMessageID),
+							slog.String("error", err.Error()),
+						)
+					}
+					return
+				}
 			}
 
-			// Deserialize job
-			var job models.MessageJob
-			if err := json.Unmarshal([]byte(result[1]), &job); err != nil {
-				c.logger.Error("failed to unmarshal job",
+			if err := c.Ack(sctx, job); err != nil {
+				c.logger.Error("failed to ack job",
+					slog.Int64("message_id", job.OutboundMessageID),
 					slog.String("error", err.Error()),
-					slog.String("data", result[1]),
 				)
-				continue
 			}
+		}(job)
+	}
+}
 
-			c.logger.Debug("job received from queue",
-				slog.Int64("message_id", job.OutboundMessageID),
-			)
+// take runs takeScript, returning redis.Nil when no job is ready
+func (c *redisClient) take(ctx context.Context, processing string) (string, error) {
+	keys := []string{c.queueName, c.queueName + lanesSuffix, processing}
+	return takeScript.Run(ctx, c.client, keys).Text()
+}
+
+// Ack removes a job from the processing list it was taken into
+func (c *redisClient) Ack(ctx context.Context, job *models.MessageJob) error {
+	value, ok := c.inFlight.LoadAndDelete(job)
+	if !ok {
+		return ErrNotInFlight
+	}
+	d := value.(delivery)
+
+	if err := c.client.LRem(ctx, d.processing, 1, d.payload).Err(); err != nil {
+		return fmt.Errorf("failed to ack job: %w", err)
+	}
+	return nil
+}
+
+// Nack moves a job from its processing list back onto its lane in one step,
+// so it is neither lost nor queued twice
+func (c *redisClient) Nack(ctx context.Context, job *models.MessageJob) error {
+	value, ok := c.inFlight.LoadAndDelete(job)
+	if !ok {
+		return ErrNotInFlight
+	}
+	d := value.(delivery)
+
+	if err := nackScript.Run(ctx, c.client, []string{c.queueName, d.processing}, d.payload).Err(); err != nil {
+		return fmt.Errorf("failed to nack job: %w", err)
+	}
+	return nil
+}
+
+// settle removes a payload that never became a job (e.g. it was quarantined)
+// from the processing list
+func (c *redisClient) settle(ctx context.Context, processing, payload string) {
+	sctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	if err := c.client.LRem(sctx, processing, 1, payload).Err(); err != nil {
+		c.logger.Error("failed to remove payload from processing list", slog.String("error", err.Error()))
+	}
+}
+
+// newConsumerID returns an ID unique to this Consume call
+func newConsumerID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "worker"
+	}
+	return fmt.Sprintf("%s-%d-%08x", host, os.Getpid(), rand.Uint32())
+}
+
+// registerConsumer starts the consumer's heartbeat and adds it to the consumers set
+func (c *redisClient) registerConsumer(ctx context.Context, consumer string) error {
+	pipe := c.client.TxPipeline()
+	pipe.Set(ctx, c.queueName+heartbeatSuffix+consumer, time.Now().UTC().Format(time.RFC3339), c.visibilityTimeout)
+	pipe.SAdd(ctx, c.queueName+consumersSuffix, consumer)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to register consumer: %w", err)
+	}
+	return nil
+}
+
+// unregisterConsumer returns anything left in the consumer's processing list
+// (jobs whose ack failed) to the queue and removes the consumer
+func (c *redisClient) unregisterConsumer(ctx context.Context, consumer string) {
+	uctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	returned, err := c.releaseConsumer(uctx, consumer, true)
+	if err != nil {
+		c.logger.Error("failed to unregister consumer",
+			slog.String("consumer", consumer),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	if returned > 0 {
+		c.logger.Warn("returned unacknowledged jobs to the queue",
+			slog.String("consumer", consumer),
+			slog.Int64("count", returned),
+		)
+	}
+}
 
-			// Acquire semaphore slot (blocks if all slots are busy)
-			semaphore <- struct{}{}
+// releaseConsumer runs releaseConsumerScript for a consumer and returns how
+// many jobs it returned to the queue, or -1 if the consumer is still alive
+func (c *redisClient) releaseConsumer(ctx context.Context, consumer string, force bool) (int64, error) {
+	keys := []string{
+		c.queueName,
+		c.queueName + heartbeatSuffix + consumer,
+		c.queueName + processingSuffix + consumer,
+		c.queueName + consumersSuffix,
+	}
+	forceArg := "0"
+	if force {
+		forceArg = "1"
+	}
+	return releaseConsumerScript.Run(ctx, c.client, keys, consumer, forceArg).Int64()
+}
 
-			// Process job concurrently in a goroutine
-			go func(job models.MessageJob) {
-				defer func() { <-semaphore }() // Release semaphore slot when done
+// heartbeat renews the consumer's heartbeat key until ctx is done. It is
+// renewed three times per visibility timeout so one slow round trip does not
+// get the consumer's jobs reaped.
+func (c *redisClient) heartbeat(ctx context.Context, consumer string) {
+	ticker := time.NewTicker(c.visibilityTimeout / 3)
+	defer ticker.Stop()
 
-				// Process job with handler
-				if err := handler(ctx, &job); err != nil {
-					c.logger.Error("handler failed to process job",
-						slog.Int64("message_id", job.OutboundMessageID),
-						slog.String("error", err.Error()),
+	key := c.queueName + heartbeatSuffix + consumer
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.client.Set(ctx, key, time.Now().UTC().Format(time.RFC3339), c.visibilityTimeout).Err(); err != nil {
+				if ctx.Err() == nil {
+					c.logger.Error("failed to renew consumer heartbeat", slog.String("error", err.Error()))
+				}
+			}
+		}
+	}
+}
+
+// reapStale periodically returns the in-flight jobs of consumers whose
+// heartbeat has expired, e.g. because their process crashed, until ctx is done
+func (c *redisClient) reapStale(ctx context.Context) {
+	ticker := time.NewTicker(c.visibilityTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			consumers, err := c.client.SMembers(ctx, c.queueName+consumersSuffix).Result()
+			if err != nil {
+				if ctx.Err() == nil {
+					c.logger.Error("failed to list queue consumers", slog.String("error", err.Error()))
+				}
+				continue
+			}
+
+			for _, consumer := range consumers {
+				returned, err := c.releaseConsumer(ctx, consumer, false)
+				if err != nil {
+					if ctx.Err() == nil {
+						c.logger.Error("failed to reap stale consumer",
+							slog.String("consumer", consumer),
+							slog.String("error", err.Error()),
+						)
+					}
+					continue
+				}
+				if returned >= 0 {
+					c.logger.Warn("returned stale in-flight jobs to the queue",
+						slog.String("consumer", consumer),
+						slog.Int64("count", returned),
 					)
-					// Note: Job is already popped from queue
-					// Retry logic is handled by the worker/handler
 				}
-			}(job)
+			}
+		}
+	}
+}
+
+// startProcessSpan starts the span of processing a job. It is the root of its
+// own trace, linked to the span that published the job: a send queues one job
+// per recipient, and a trace per message keeps each of them readable.
+func (c *redisClient) startProcessSpan(ctx context.Context, job *models.MessageJob) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "process "+c.queueName,
+		trace.WithNewRoot(),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithLinks(tracing.Link(job.TraceContext)),
+		trace.WithAttributes(
+			semconv.MessagingSystemKey.String("redis"),
+			semconv.MessagingOperationTypeProcess,
+			semconv.MessagingDestinationName(c.queueName),
+			attribute.Int64("outbound_message_id", job.OutboundMessageID),
+		),
+	)
+}
+
+// handle runs the handler for a single job, converting a panic into an error so
+// that one bad job cannot take down the consumer loop. Handlers are expected to
+// recover and record their own panics; anything that still escapes is quarantined
+// with its stack trace.
+func (c *redisClient) handle(ctx context.Context, handler MessageHandler, job *models.MessageJob) (err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+
+		stack := debug.Stack()
+		c.logger.Error("handler panicked",
+			slog.Int64("message_id", job.OutboundMessageID),
+			slog.Any("panic", r),
+			slog.String("stack", string(stack)),
+		)
+
+		err = fmt.Errorf("handler panic: %v", r)
+
+		payload, encodeErr := EncodeJob(job)
+		if encodeErr != nil {
+			c.logger.Error("failed to encode panicked job", slog.String("error", encodeErr.Error()))
+			return
+		}
+		c.quarantine(ctx, string(payload), fmt.Errorf("%w\n%s", err, stack))
+	}()
+
+	return handler(ctx, job)
+}
+
+// quarantine parks an undecodable payload on the quarantine list instead of dropping it
+func (c *redisClient) quarantine(ctx context.Context, payload string, decodeErr error) {
+	entry, err := json.Marshal(QuarantinedJob{
+		Payload:       payload,
+		Error:         decodeErr.Error(),
+		QuarantinedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		c.logger.Error("failed to marshal quarantine entry", slog.String("error", err.Error()))
+		return
+	}
+
+	// Use a fresh context so a shutdown in progress doesn't lose the payload
+	qctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	if err := c.client.LPush(qctx, c.queueName+quarantineSuffix, entry).Err(); err != nil {
+		c.logger.Error("failed to quarantine payload",
+			slog.String("error", err.Error()),
+			slog.String("data", payload),
+		)
+	}
+}
+
+// ListQuarantined returns up to limit quarantined payloads, newest first
+func (c *redisClient) ListQuarantined(ctx context.Context, limit int) ([]QuarantinedJob, error) {
+	if limit < 1 {
+		limit = 1
+	}
+
+	entries, err := c.client.LRange(ctx, c.queueName+quarantineSuffix, 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read quarantine: %w", err)
+	}
+
+	jobs := make([]QuarantinedJob, 0, len(entries))
+	for _, entry := range entries {
+		var job QuarantinedJob
+		if err := json.Unmarshal([]byte(entry), &job); err != nil {
+			// Should never happen, but surface the raw entry rather than hide it
+			job = QuarantinedJob{Payload: entry, Error: "unreadable quarantine entry: " + err.Error()}
 		}
+		jobs = append(jobs, job)
 	}
+
+	return jobs, nil
+}
+
+// PurgeQuarantine deletes the quarantine list
+func (c *redisClient) PurgeQuarantine(ctx context.Context) (int64, error) {
+	key := c.queueName + quarantineSuffix
+
+	pipe := c.client.TxPipeline()
+	length := pipe.LLen(ctx, key)
+	pipe.Del(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to purge quarantine: %w", err)
+	}
+
+	return length.Val(), nil
+}
+
+// RemoveCampaignJobs deletes the campaign's lane, then scans the shared lane
+// and the delayed set page by page for jobs stamped with the campaign. It does
+// not block the queue, so a job published or taken during the scan may be
+// missed; the worker skips jobs of cancelled and paused campaigns, so this
+// only saves it the work. Jobs published without a campaign ID are never
+// removed.
+func (c *redisClient) RemoveCampaignJobs(ctx context.Context, campaignID int64) (int64, error) {
+	matches := func(payload string) bool {
+		job, err := DecodeJob([]byte(payload))
+		return err == nil && job.CampaignID == campaignID
+	}
+
+	id := strconv.FormatInt(campaignID, 10)
+	pipe := c.client.TxPipeline()
+	length := pipe.LLen(ctx, c.queueName+laneSuffix+id)
+	pipe.Del(ctx, c.queueName+laneSuffix+id)
+	pipe.LRem(ctx, c.queueName+lanesSuffix, 0, id)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to remove campaign lane: %w", err)
+	}
+	removed := length.Val()
+
+	// Jobs queued by builds without lanes wait on the shared lane. Consumers
+	// take jobs from the tail, so positions counted from the head only move
+	// when jobs are published, which at worst rereads a few
+	for start := int64(0); ; {
+		page, err := c.client.LRange(ctx, c.queueName, start, start+removeScanPage-1).Result()
+		if err != nil {
+			return removed, fmt.Errorf("failed to read queue: %w", err)
+		}
+		for _, payload := range page {
+			if !matches(payload) {
+				start++
+				continue
+			}
+			n, err := c.client.LRem(ctx, c.queueName, 1, payload).Result()
+			if err != nil {
+				return removed, fmt.Errorf("failed to remove job from queue: %w", err)
+			}
+			removed += n
+		}
+		if len(page) < removeScanPage {
+			break
+		}
+	}
+
+	delayed := c.queueName + delayedSuffix
+	for start := int64(0); ; {
+		page, err := c.client.ZRange(ctx, delayed, start, start+removeScanPage-1).Result()
+		if err != nil {
+			return removed, fmt.Errorf("failed to read delayed jobs: %w", err)
+		}
+		for _, payload := range page {
+			if !matches(payload) {
+				start++
+				continue
+			}
+			n, err := c.client.ZRem(ctx, delayed, payload).Result()
+			if err != nil {
+				return removed, fmt.Errorf("failed to remove delayed job: %w", err)
+			}
+			removed += n
+		}
+		if len(page) < removeScanPage {
+			break
+		}
+	}
+
+	return removed, nil
+}
+
+// maintenancePaused reads the maintenance flag and logs transitions. If the
+// flag cannot be read the previous state is kept.
+func (c *redisClient) maintenancePaused(ctx context.Context, paused bool) bool {
+	maintenance, err := c.Maintenance(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			c.logger.Error("failed to read maintenance flag", slog.String("error", err.Error()))
+		}
+		return paused
+	}
+
+	if maintenance.Enabled && !paused {
+		c.logger.Warn("maintenance mode on, consumer paused",
+			slog.String("queue", c.queueName),
+			slog.String("reason", maintenance.Reason),
+		)
+	}
+	if !maintenance.Enabled && paused {
+		c.logger.Info("maintenance mode off, consumer resumed",
+			slog.String("queue", c.queueName),
+		)
+	}
+
+	return maintenance.Enabled
+}
+
+// Maintenance returns the maintenance flag; a missing key means maintenance is off
+func (c *redisClient) Maintenance(ctx context.Context) (*Maintenance, error) {
+	data, err := c.client.Get(ctx, c.queueName+maintenanceSuffix).Bytes()
+	if err == redis.Nil {
+		return &Maintenance{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read maintenance flag: %w", err)
+	}
+
+	var maintenance Maintenance
+	if err := json.Unmarshal(data, &maintenance); err != nil {
+		return nil, fmt.Errorf("failed to decode maintenance flag: %w", err)
+	}
+	return &maintenance, nil
+}
+
+// SetMaintenance stores the maintenance flag. Enabling it again keeps the
+// original start time so operators can see how long maintenance has lasted.
+func (c *redisClient) SetMaintenance(ctx context.Context, reason string) (*Maintenance, error) {
+	current, err := c.Maintenance(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	startedAt := time.Now().UTC()
+	if current.Enabled && current.StartedAt != nil {
+		startedAt = *current.StartedAt
+	}
+
+	maintenance := &Maintenance{
+		Enabled:   true,
+		Reason:    reason,
+		StartedAt: &startedAt,
+	}
+
+	data, err := json.Marshal(maintenance)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal maintenance flag: %w", err)
+	}
+	if err := c.client.Set(ctx, c.queueName+maintenanceSuffix, data, 0).Err(); err != nil {
+		return nil, fmt.Errorf("failed to set maintenance flag: %w", err)
+	}
+
+	return maintenance, nil
+}
+
+// ClearMaintenance deletes the maintenance flag
+func (c *redisClient) ClearMaintenance(ctx context.Context) error {
+	if err := c.client.Del(ctx, c.queueName+maintenanceSuffix).Err(); err != nil {
+		return fmt.Errorf("failed to clear maintenance flag: %w", err)
+	}
+	return nil
 }
 
 // Close closes the Redis connection
@@ -183,11 +981,106 @@ func (c *redisClient) Health(ctx context.Context) error {
 	return nil
 }
 
-// QueueLength returns the number of jobs in the queue (for monitoring)
+// laneKeys returns the keys of the lanes in rotation and of the shared lane
+func (c *redisClient) laneKeys(ctx context.Context) ([]string, error) {
+	ids, err := c.client.LRange(ctx, c.queueName+lanesSuffix, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list queue lanes: %w", err)
+	}
+
+	keys := []string{c.queueName}
+	for _, id := range ids {
+		if id != "0" {
+			keys = append(keys, c.queueName+laneSuffix+id)
+		}
+	}
+	return keys, nil
+}
+
+// Depth returns the ready, delayed, in-flight and quarantined job counts
+func (c *redisClient) Depth(ctx context.Context) (*Depth, error) {
+	consumers, err := c.client.SMembers(ctx, c.queueName+consumersSuffix).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list queue consumers: %w", err)
+	}
+	lanes, err := c.laneKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pipe := c.client.Pipeline()
+	ready := make([]*redis.IntCmd, len(lanes))
+	for i, lane := range lanes {
+		ready[i] = pipe.LLen(ctx, lane)
+	}
+	delayed := pipe.ZCard(ctx, c.queueName+delayedSuffix)
+	quarantined := pipe.LLen(ctx, c.queueName+quarantineSuffix)
+	processing := make([]*redis.IntCmd, len(consumers))
+	for i, consumer := range consumers {
+		processing[i] = pipe.LLen(ctx, c.queueName+processingSuffix+consumer)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get queue depth: %w", err)
+	}
+
+	var readyJobs, inFlight int64
+	for _, length := range ready {
+		readyJobs += length.Val()
+	}
+	for _, length := range processing {
+		inFlight += length.Val()
+	}
+
+	return &Depth{
+		Ready:       readyJobs,
+		Delayed:     delayed.Val(),
+		InFlight:    inFlight,
+		Quarantined: quarantined.Val(),
+	}, nil
+}
+
+// Lag reads the oldest job of every lane, the next one a consumer takes from
+// it, and returns the longest wait. Jobs published by builds that did not
+// stamp ReadyAt count as no lag.
+func (c *redisClient) Lag(ctx context.Context) (time.Duration, error) {
+	lanes, err := c.laneKeys(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	pipe := c.client.Pipeline()
+	oldest := make([]*redis.StringCmd, len(lanes))
+	for i, lane := range lanes {
+		oldest[i] = pipe.LIndex(ctx, lane, -1)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return 0, fmt.Errorf("failed to read oldest jobs: %w", err)
+	}
+
+	var lag time.Duration
+	for _, cmd := range oldest {
+		payload, err := cmd.Result()
+		if err != nil {
+			// An empty lane has no oldest job
+			continue
+		}
+
+		job, err := DecodeJob([]byte(payload))
+		if err != nil || job.ReadyAt == nil {
+			// Undecodable payloads are quarantined by the consumer that takes them
+			continue
+		}
+		lag = max(lag, time.Since(*job.ReadyAt))
+	}
+
+	return lag, nil
+}
+
+// QueueLength returns the number of ready jobs in every lane (for monitoring)
 func (c *redisClient) QueueLength(ctx context.Context) (int64, error) {
-	length, err := c.client.LLen(ctx, c.queueName).Result()
+	depth, err := c.Depth(ctx)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get queue length: %w", err)
 	}
-	return length, nil
+	return depth.Ready, nil
 }
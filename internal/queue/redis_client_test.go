@@ -0,0 +1,517 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/config"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/tracing"
+)
+
+func TestRedisClient_Depth(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client, err := NewRedisClient(RedisConfig{URL: "redis://" + mr.Addr(), QueueName: "sends"}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewRedisClient() error = %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	for id := int64(1); id <= 3; id++ {
+		if err := client.Publish(ctx, &models.MessageJob{OutboundMessageID: id}); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	if err := client.PublishDelayed(ctx, &models.MessageJob{OutboundMessageID: 4}, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("PublishDelayed() error = %v", err)
+	}
+	mr.Lpush("sends:quarantine", `{"payload":"garbage"}`)
+
+	depth, err := client.Depth(ctx)
+	if err != nil {
+		t.Fatalf("Depth() error = %v", err)
+	}
+	if *depth != (Depth{Ready: 3, Delayed: 1, Quarantined: 1}) {
+		t.Errorf("Depth() = %+v, want ready 3, delayed 1, quarantined 1", *depth)
+	}
+}
+
+func TestRedisClient_RemoveCampaignJobs(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client, err := NewRedisClient(RedisConfig{URL: "redis://" + mr.Addr(), QueueName: "sends"}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewRedisClient() error = %v", err)
+	}
+	defer client.Close()
+
+	// More jobs than one scan page, alternating between two campaigns
+	ctx := context.Background()
+	jobs := int64(removeScanPage + 10)
+	for id := int64(1); id <= jobs; id++ {
+		if err := client.Publish(ctx, &models.MessageJob{OutboundMessageID: id, CampaignID: 1 + id%2}); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	for id := jobs + 1; id <= jobs+4; id++ {
+		if err := client.PublishDelayed(ctx, &models.MessageJob{OutboundMessageID: id, CampaignID: 1 + id%2}, time.Now().Add(time.Hour)); err != nil {
+			t.Fatalf("PublishDelayed() error = %v", err)
+		}
+	}
+	// Jobs without a campaign are left alone
+	if err := client.Publish(ctx, &models.MessageJob{OutboundMessageID: jobs + 5}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	removed, err := client.RemoveCampaignJobs(ctx, 2)
+	if err != nil {
+		t.Fatalf("RemoveCampaignJobs() error = %v", err)
+	}
+	if want := jobs/2 + 2; removed != want {
+		t.Errorf("RemoveCampaignJobs() = %d, want %d", removed, want)
+	}
+
+	depth, err := client.Depth(ctx)
+	if err != nil {
+		t.Fatalf("Depth() error = %v", err)
+	}
+	if *depth != (Depth{Ready: jobs/2 + 1, Delayed: 2}) {
+		t.Errorf("Depth() = %+v, want ready %d, delayed 2", *depth, jobs/2+1)
+	}
+}
+
+func TestRedisClient_ConsumeServesCampaignsInTurn(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client, err := NewRedisClient(RedisConfig{URL: "redis://" + mr.Addr(), QueueName: "sends"}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewRedisClient() error = %v", err)
+	}
+	defer client.Close()
+
+	// A large campaign is queued first, then a small one behind it
+	ctx := context.Background()
+	const large, small = int64(123456789012), int64(7)
+	for id := int64(1); id <= 6; id++ {
+		if err := client.Publish(ctx, &models.MessageJob{OutboundMessageID: id, CampaignID: large}); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	for id := int64(7); id <= 8; id++ {
+		if err := client.Publish(ctx, &models.MessageJob{OutboundMessageID: id, CampaignID: small}); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	if !mr.Exists(fmt.Sprintf("sends:campaign:%d", large)) {
+		t.Fatal("large campaign's jobs are not on its lane")
+	}
+
+	handled := make(chan *models.MessageJob, 8)
+	consumeCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Consume(consumeCtx, func(ctx context.Context, job *models.MessageJob) error {
+			handled <- job
+			return nil
+		}, 1)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	var order []int64
+	for len(order) < 8 {
+		select {
+		case job := <-handled:
+			order = append(order, job.OutboundMessageID)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("consumed %v, want 8 jobs", order)
+		}
+	}
+
+	// The lanes alternate until the small campaign's are done; each lane keeps its own order
+	want := []int64{1, 7, 2, 8, 3, 4, 5, 6}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("consumed in order %v, want %v", order, want)
+		}
+	}
+
+	if lanes, _ := mr.List("sends:lanes"); len(lanes) != 0 {
+		t.Errorf("lanes after draining = %v, want none", lanes)
+	}
+}
+
+func TestRedisClient_Lag(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client, err := NewRedisClient(RedisConfig{URL: "redis://" + mr.Addr(), QueueName: "sends"}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewRedisClient() error = %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	lag, err := client.Lag(ctx)
+	if err != nil {
+		t.Fatalf("Lag() error = %v", err)
+	}
+	if lag != 0 {
+		t.Errorf("Lag() of an empty queue = %v, want 0", lag)
+	}
+
+	// The oldest job was ready two minutes ago; newer ones don't count
+	readyAt := time.Now().Add(-2 * time.Minute)
+	data, err := EncodeJob(&models.MessageJob{OutboundMessageID: 1, ReadyAt: &readyAt})
+	if err != nil {
+		t.Fatalf("EncodeJob() error = %v", err)
+	}
+	mr.Lpush("sends", string(data))
+	if err := client.Publish(ctx, &models.MessageJob{OutboundMessageID: 2}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	lag, err = client.Lag(ctx)
+	if err != nil {
+		t.Fatalf("Lag() error = %v", err)
+	}
+	if lag < 2*time.Minute || lag > 3*time.Minute {
+		t.Errorf("Lag() = %v, want about 2m", lag)
+	}
+}
+
+func TestRedisClient_ConsumePausesDuringMaintenance(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client, err := NewRedisClient(RedisConfig{URL: "redis://" + mr.Addr(), QueueName: "sends"}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewRedisClient() error = %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	if _, err := client.SetMaintenance(ctx, "schema migration"); err != nil {
+		t.Fatalf("SetMaintenance() error = %v", err)
+	}
+	if err := client.Publish(ctx, &models.MessageJob{OutboundMessageID: 1}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	handled := make(chan int64, 1)
+	consumeCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Consume(consumeCtx, func(ctx context.Context, job *models.MessageJob) error {
+			handled <- job.OutboundMessageID
+			return nil
+		}, 1)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	select {
+	case id := <-handled:
+		t.Fatalf("job %d consumed during maintenance", id)
+	case <-time.After(1500 * time.Millisecond):
+	}
+	if depth, _ := client.Depth(ctx); depth.Ready != 1 {
+		t.Errorf("ready jobs during maintenance = %d, want 1", depth.Ready)
+	}
+
+	if err := client.ClearMaintenance(ctx); err != nil {
+		t.Fatalf("ClearMaintenance() error = %v", err)
+	}
+
+	select {
+	case id := <-handled:
+		if id != 1 {
+			t.Errorf("consumed job %d, want 1", id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("job not consumed after maintenance ended")
+	}
+}
+
+func TestRedisClient_ConsumeAcksAndNacks(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client, err := NewRedisClient(RedisConfig{URL: "redis://" + mr.Addr(), QueueName: "sends"}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewRedisClient() error = %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	if err := client.Publish(ctx, &models.MessageJob{OutboundMessageID: 1}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	// The first attempt asks for a requeue, the second succeeds
+	attempts := make(chan int64, 2)
+	var calls atomic.Int32
+	consumeCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Consume(consumeCtx, func(ctx context.Context, job *models.MessageJob) error {
+			attempts <- job.OutboundMessageID
+			if calls.Add(1) == 1 {
+				return fmt.Errorf("provider busy: %w", ErrRequeue)
+			}
+			return nil
+		}, 1)
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-attempts:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("job consumed %d times, want 2", i)
+		}
+	}
+
+	// Give the second ack time to land before checking
+	time.Sleep(100 * time.Millisecond)
+	depth, err := client.Depth(ctx)
+	if err != nil {
+		t.Fatalf("Depth() error = %v", err)
+	}
+	if depth.Ready != 0 || depth.InFlight != 0 {
+		t.Errorf("Depth() = %+v, want no ready or in-flight jobs after the ack", *depth)
+	}
+
+	cancel()
+	<-done
+
+	if members, _ := mr.Members("sends:consumers"); len(members) != 0 {
+		t.Errorf("consumers after shutdown = %v, want none", members)
+	}
+}
+
+func TestRedisClient_ConsumeDrainsInFlightJobs(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client, err := NewRedisClient(RedisConfig{URL: "redis://" + mr.Addr(), QueueName: "sends"}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewRedisClient() error = %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	if err := client.Publish(ctx, &models.MessageJob{OutboundMessageID: 1}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	handlerErr := make(chan error, 1)
+	consumeCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Consume(consumeCtx, func(ctx context.Context, job *models.MessageJob) error {
+			close(started)
+			<-finish
+			handlerErr <- ctx.Err()
+			return nil
+		}, 1)
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job not consumed")
+	}
+
+	// Once stopped the consumer takes no new jobs and waits for the one in flight
+	cancel()
+	if err := client.Publish(ctx, &models.MessageJob{OutboundMessageID: 2}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	select {
+	case err := <-done:
+		t.Fatalf("Consume() returned %v with a job in flight", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	close(finish)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Consume() did not return after the job finished")
+	}
+	if err := <-handlerErr; err != nil {
+		t.Errorf("handler context error = %v, want it left running during the drain", err)
+	}
+
+	depth, err := client.Depth(ctx)
+	if err != nil {
+		t.Fatalf("Depth() error = %v", err)
+	}
+	if depth.Ready != 1 || depth.InFlight != 0 {
+		t.Errorf("Depth() = %+v, want the unstarted job ready and none in flight", *depth)
+	}
+}
+
+func TestRedisClient_ConsumeDrainTimeout(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client, err := NewRedisClient(RedisConfig{URL: "redis://" + mr.Addr(), QueueName: "sends", DrainTimeout: 100 * time.Millisecond}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewRedisClient() error = %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	if err := client.Publish(ctx, &models.MessageJob{OutboundMessageID: 1}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	// The handler runs until its context is canceled
+	started := make(chan struct{})
+	consumeCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Consume(consumeCtx, func(ctx context.Context, job *models.MessageJob) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		}, 1)
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job not consumed")
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Consume() did not return after the drain timeout")
+	}
+
+	// The unfinished job is back on the queue for another worker
+	depth, err := client.Depth(ctx)
+	if err != nil {
+		t.Fatalf("Depth() error = %v", err)
+	}
+	if depth.Ready != 1 || depth.InFlight != 0 {
+		t.Errorf("Depth() = %+v, want the unfinished job ready", *depth)
+	}
+}
+
+func TestRedisClient_ReapsJobsOfStaleConsumers(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client, err := NewRedisClient(RedisConfig{URL: "redis://" + mr.Addr(), QueueName: "sends", VisibilityTimeout: 300 * time.Millisecond}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewRedisClient() error = %v", err)
+	}
+	defer client.Close()
+
+	// A crashed consumer left job 1 in flight; a live one is still working on job 2
+	mr.SAdd("sends:consumers", "crashed", "alive")
+	mr.Lpush("sends:processing:crashed", `{"outbound_message_id":1,"version":1}`)
+	mr.Lpush("sends:processing:alive", `{"outbound_message_id":2,"version":1}`)
+	mr.Set("sends:heartbeat:alive", "now")
+	mr.SetTTL("sends:heartbeat:alive", time.Hour)
+
+	handled := make(chan int64, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Consume(ctx, func(ctx context.Context, job *models.MessageJob) error {
+			handled <- job.OutboundMessageID
+			return nil
+		}, 1)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	select {
+	case id := <-handled:
+		if id != 1 {
+			t.Errorf("consumed job %d, want the crashed consumer's job 1", id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("stale in-flight job was not returned to the queue")
+	}
+
+	select {
+	case id := <-handled:
+		t.Errorf("job %d of a live consumer was reaped", id)
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	if mr.Exists("sends:processing:crashed") {
+		t.Error("crashed consumer's processing list still exists")
+	}
+}
+
+func TestRedisClient_ConsumeLinksToPublishingSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	if _, err := tracing.Setup(context.Background(), config.TracingConfig{}, "test"); err != nil {
+		t.Fatalf("tracing.Setup() error = %v", err)
+	}
+
+	mr := miniredis.RunT(t)
+	client, err := NewRedisClient(RedisConfig{URL: "redis://" + mr.Addr(), QueueName: "sends"}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewRedisClient() error = %v", err)
+	}
+	defer client.Close()
+
+	sendCtx, sendSpan := provider.Tracer("test").Start(context.Background(), "send campaign")
+	if err := client.Publish(sendCtx, &models.MessageJob{OutboundMessageID: 1}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	sendSpan.End()
+
+	handled := make(chan trace.SpanContext, 1)
+	consumeCtx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Consume(consumeCtx, func(ctx context.Context, job *models.MessageJob) error {
+			handled <- trace.SpanContextFromContext(ctx)
+			return nil
+		}, 1)
+	}()
+
+	var processCtx trace.SpanContext
+	select {
+	case processCtx = <-handled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job was not consumed")
+	}
+	cancel()
+	<-done
+
+	if processCtx.TraceID() == sendSpan.SpanContext().TraceID() {
+		t.Error("process span joined the send's trace, want a trace of its own")
+	}
+
+	var process sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.SpanContext().SpanID() == processCtx.SpanID() {
+			process = span
+		}
+	}
+	if process == nil {
+		t.Fatal("process span was not ended")
+	}
+	if links := process.Links(); len(links) != 1 || links[0].SpanContext.SpanID() != sendSpan.SpanContext().SpanID() {
+		t.Errorf("process span links = %+v, want the send span", links)
+	}
+}
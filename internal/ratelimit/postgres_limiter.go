@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+)
+
+// takeTokenQuery refills key $1's bucket at $2 tokens per second up to $3
+// tokens and takes one, returning the tokens left. The balance may go
+// negative: the taker then owes the wait until its token refills. The row
+// lock of the upsert serializes takers of a key, and refills use the
+// database clock so workers with skewed clocks agree on them.
+const takeTokenQuery = `
+	INSERT INTO rate_limit_buckets AS b (key, tokens, updated_at)
+	VALUES ($1, $3::DOUBLE PRECISION - 1, NOW())
+	ON CONFLICT (key) DO UPDATE SET
+		tokens = LEAST(
+			$3::DOUBLE PRECISION,
+			b.tokens + GREATEST(EXTRACT(EPOCH FROM NOW() - b.updated_at)::DOUBLE PRECISION, 0) * $2::DOUBLE PRECISION
+		) - 1,
+		updated_at = GREATEST(NOW(), b.updated_at)
+	RETURNING tokens`
+
+// postgresLimiter implements Limiter with a token bucket per key stored in
+// the rate_limit_buckets table
+type postgresLimiter struct {
+	*limiterState
+
+	db *sql.DB
+}
+
+// NewPostgresLimiter creates a token bucket limiter on the database. The
+// database stays open when the limiter is closed.
+func NewPostgresLimiter(db *sql.DB, rates map[string]float64, logger *slog.Logger) Limiter {
+	return &postgresLimiter{
+		limiterState: newLimiterState(rates, logger),
+		db:           db,
+	}
+}
+
+// Wait takes a token from the bucket for key in one round trip, then waits
+// for it to refill if the bucket was empty. A wait cut short by ctx still
+// uses up the token.
+func (l *postgresLimiter) Wait(ctx context.Context, key string) error {
+	rate, burst, ok := l.bucket(key)
+	if !ok {
+		return nil
+	}
+
+	var tokens float64
+	if err := l.db.QueryRowContext(ctx, takeTokenQuery, key, rate, burst).Scan(&tokens); err != nil {
+		return fmt.Errorf("failed to acquire rate limit token: %w", err)
+	}
+
+	var waited time.Duration
+	if tokens < 0 {
+		waited = time.Duration(math.Ceil(-tokens*1000/rate)) * time.Millisecond
+		select {
+		case <-time.After(waited):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	l.record(key, waited)
+	return nil
+}
+
+// Close does nothing; the database belongs to the caller
+func (l *postgresLimiter) Close() error {
+	return nil
+}
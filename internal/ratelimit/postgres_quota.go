@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// postgresQuota implements Quota with one row of the quota_counters table per
+// key and window
+type postgresQuota struct {
+	db *sql.DB
+}
+
+// NewPostgresQuota creates a quota counter on the database. The database
+// stays open when the quota is closed.
+func NewPostgresQuota(db *sql.DB) Quota {
+	return &postgresQuota{db: db}
+}
+
+// Take consumes one unit of the key's quota for the window. The counter is
+// only incremented while under limit, so concurrent takers never overshoot it.
+func (q *postgresQuota) Take(ctx context.Context, key string, windowStart time.Time, window time.Duration, limit int) (bool, error) {
+	if limit <= 0 {
+		return false, nil
+	}
+
+	// Keep counters for one extra window so late workers still see them
+	var used int
+	err := q.db.QueryRowContext(ctx, `
+		INSERT INTO quota_counters AS c (key, window_start, used, expires_at)
+		VALUES ($1, $2, 1, $3)
+		ON CONFLICT (key, window_start) DO UPDATE SET used = c.used + 1
+		WHERE c.used < $4
+		RETURNING used`,
+		key, windowStart, windowStart.Add(2*window), limit,
+	).Scan(&used)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to take quota: %w", err)
+	}
+
+	// The first unit of a window clears the key's expired counters
+	if used == 1 {
+		if _, err := q.db.ExecContext(ctx, `DELETE FROM quota_counters WHERE key = $1 AND expires_at < NOW()`, key); err != nil {
+			return true, fmt.Errorf("failed to clear expired quota: %w", err)
+		}
+	}
+
+	return true, nil
+}
+
+// Used reads the key's counter for the window; a window without one is unused
+func (q *postgresQuota) Used(ctx context.Context, key string, windowStart time.Time) (int, error) {
+	var used int
+	err := q.db.QueryRowContext(ctx,
+		`SELECT used FROM quota_counters WHERE key = $1 AND window_start = $2`,
+		key, windowStart,
+	).Scan(&used)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read quota: %w", err)
+	}
+
+	return used, nil
+}
+
+// Refund decrements the key's counter for the window, never below zero
+func (q *postgresQuota) Refund(ctx context.Context, key string, windowStart time.Time) error {
+	_, err := q.db.ExecContext(ctx,
+		`UPDATE quota_counters SET used = used - 1 WHERE key = $1 AND window_start = $2 AND used > 0`,
+		key, windowStart,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to refund quota: %w", err)
+	}
+
+	return nil
+}
+
+// Close does nothing; the database belongs to the caller
+func (q *postgresQuota) Close() error {
+	return nil
+}
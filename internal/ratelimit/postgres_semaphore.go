@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// postgresSemaphore implements Semaphore with the leases of each key stored as
+// rows of the semaphore_leases table
+type postgresSemaphore struct {
+	db *sql.DB
+}
+
+// NewPostgresSemaphore creates a semaphore on the database. The database
+// stays open when the semaphore is closed.
+func NewPostgresSemaphore(db *sql.DB) Semaphore {
+	return &postgresSemaphore{db: db}
+}
+
+// Acquire takes a slot of key under a new random lease. Acquirers of a key
+// are serialized by a transaction-scoped advisory lock, so two of them never
+// count the same free slot.
+func (s *postgresSemaphore) Acquire(ctx context.Context, key string, limit int, ttl time.Duration) (string, bool, error) {
+	lease, err := newLease()
+	if err != nil {
+		return "", false, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback() // Rollback is safe to call even after Commit
+	}()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('semaphore:' || $1))`, key); err != nil {
+		return "", false, fmt.Errorf("failed to lock semaphore: %w", err)
+	}
+
+	// Leases not released within their ttl are freed first
+	if _, err := tx.ExecContext(ctx, `DELETE FROM semaphore_leases WHERE key = $1 AND expires_at <= NOW()`, key); err != nil {
+		return "", false, fmt.Errorf("failed to free expired leases: %w", err)
+	}
+
+	var held int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM semaphore_leases WHERE key = $1`, key).Scan(&held); err != nil {
+		return "", false, fmt.Errorf("failed to count leases: %w", err)
+	}
+
+	taken := held < limit
+	if taken {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO semaphore_leases (key, lease, expires_at)
+			VALUES ($1, $2, NOW() + make_interval(secs => $3))`,
+			key, lease, ttl.Seconds(),
+		)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to acquire semaphore: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	if !taken {
+		return "", false, nil
+	}
+
+	return lease, true, nil
+}
+
+// Release deletes the lease
+func (s *postgresSemaphore) Release(ctx context.Context, key, lease string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM semaphore_leases WHERE key = $1 AND lease = $2`, key, lease); err != nil {
+		return fmt.Errorf("failed to release semaphore: %w", err)
+	}
+	return nil
+}
+
+// Close does nothing; the database belongs to the caller
+func (s *postgresSemaphore) Close() error {
+	return nil
+}
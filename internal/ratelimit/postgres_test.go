@@ -0,0 +1,196 @@
+//go:build integration
+
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+// These tests run the Postgres limiter, quota and semaphore against a real
+// database. They need one migrated through 051_postgres_queue:
+//
+//	TEST_DATABASE_URL=postgres://... go test -tags integration ./internal/ratelimit/...
+//
+// Each test uses keys of its own and deletes their rows afterwards.
+
+// openTestDB connects to TEST_DATABASE_URL and returns a key prefix unique to
+// the test, skipping the test when no database is configured
+func openTestDB(t *testing.T) (*sql.DB, string) {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	prefix := fmt.Sprintf("test_%d:", time.Now().UnixNano())
+	t.Cleanup(func() {
+		for _, table := range []string{"rate_limit_buckets", "quota_counters", "semaphore_leases"} {
+			_, _ = db.Exec(`DELETE FROM `+table+` WHERE key LIKE $1 || '%'`, prefix)
+		}
+	})
+
+	return db, prefix
+}
+
+func TestPostgresLimiter_Wait(t *testing.T) {
+	db, prefix := openTestDB(t)
+	key := prefix + "sms"
+	limiter := NewPostgresLimiter(db, map[string]float64{key: 10}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	defer limiter.Close()
+
+	// A full bucket of 10 tokens, then one more that waits about 100ms to refill
+	ctx := context.Background()
+	for i := 0; i < 11; i++ {
+		if err := limiter.Wait(ctx, key); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+
+	stats := limiter.Stats()[key]
+	if stats.Acquired != 11 || stats.Throttled == 0 {
+		t.Errorf("Stats() = %+v, want 11 acquired with at least one throttled", stats)
+	}
+
+	// Keys without a rate are never throttled or counted
+	if err := limiter.Wait(ctx, prefix+"whatsapp"); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if _, ok := limiter.Stats()[prefix+"whatsapp"]; ok {
+		t.Error("unthrottled key has stats")
+	}
+}
+
+func TestPostgresLimiter_WaitCancelled(t *testing.T) {
+	db, prefix := openTestDB(t)
+	key := prefix + "sms"
+	limiter := NewPostgresLimiter(db, map[string]float64{key: 0.1}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	defer limiter.Close()
+
+	// The first token is free; the second would take 10 seconds to refill
+	if err := limiter.Wait(context.Background(), key); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx, key); err != context.DeadlineExceeded {
+		t.Errorf("Wait() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestPostgresQuota_TakeUsedRefund(t *testing.T) {
+	db, prefix := openTestDB(t)
+	key := prefix + "daily"
+	quota := NewPostgresQuota(db)
+	defer quota.Close()
+
+	ctx := context.Background()
+	window := 24 * time.Hour
+	today := time.Now().UTC().Truncate(window)
+
+	for i := 0; i < 3; i++ {
+		taken, err := quota.Take(ctx, key, today, window, 2)
+		if err != nil {
+			t.Fatalf("Take() error = %v", err)
+		}
+		if want := i < 2; taken != want {
+			t.Errorf("Take() #%d = %v, want %v", i+1, taken, want)
+		}
+	}
+	if used, err := quota.Used(ctx, key, today); err != nil || used != 2 {
+		t.Errorf("Used() = %d, %v, want 2 with the third take refused", used, err)
+	}
+
+	// A refund frees a unit for the next take
+	if err := quota.Refund(ctx, key, today); err != nil {
+		t.Fatalf("Refund() error = %v", err)
+	}
+	if used, _ := quota.Used(ctx, key, today); used != 1 {
+		t.Errorf("Used() after refund = %d, want 1", used)
+	}
+	if taken, err := quota.Take(ctx, key, today, window, 2); err != nil || !taken {
+		t.Errorf("Take() after refund = %v, %v, want true", taken, err)
+	}
+
+	// Windows are counted apart, and a refund never goes below zero
+	tomorrow := today.Add(window)
+	if err := quota.Refund(ctx, key, tomorrow); err != nil {
+		t.Fatalf("Refund() error = %v", err)
+	}
+	if used, err := quota.Used(ctx, key, tomorrow); err != nil || used != 0 {
+		t.Errorf("Used() for an unused window = %d, %v, want 0", used, err)
+	}
+
+	if taken, err := quota.Take(ctx, key, today, window, 0); err != nil || taken {
+		t.Errorf("Take() with no limit = %v, %v, want false", taken, err)
+	}
+}
+
+func TestPostgresSemaphore_AcquireRelease(t *testing.T) {
+	db, prefix := openTestDB(t)
+	key := prefix + "account:1"
+	semaphore := NewPostgresSemaphore(db)
+	defer semaphore.Close()
+
+	ctx := context.Background()
+	var leases []string
+	for i := 0; i < 3; i++ {
+		lease, ok, err := semaphore.Acquire(ctx, key, 2, time.Minute)
+		if err != nil {
+			t.Fatalf("Acquire() error = %v", err)
+		}
+		if want := i < 2; ok != want {
+			t.Fatalf("Acquire() #%d = %v, want %v", i+1, ok, want)
+		}
+		if ok {
+			leases = append(leases, lease)
+		}
+	}
+	if leases[0] == leases[1] {
+		t.Errorf("both slots share lease %q", leases[0])
+	}
+
+	// Releasing a slot lets the next acquire in
+	if err := semaphore.Release(ctx, key, leases[0]); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if _, ok, err := semaphore.Acquire(ctx, key, 2, time.Minute); err != nil || !ok {
+		t.Errorf("Acquire() after release = %v, %v, want true", ok, err)
+	}
+}
+
+func TestPostgresSemaphore_ExpiredLeasesAreFreed(t *testing.T) {
+	db, prefix := openTestDB(t)
+	key := prefix + "account:1"
+	semaphore := NewPostgresSemaphore(db)
+	defer semaphore.Close()
+
+	// A worker that crashed holding the only slot
+	ctx := context.Background()
+	if _, ok, err := semaphore.Acquire(ctx, key, 1, 100*time.Millisecond); err != nil || !ok {
+		t.Fatalf("Acquire() = %v, %v, want true", ok, err)
+	}
+	if _, ok, _ := semaphore.Acquire(ctx, key, 1, time.Minute); ok {
+		t.Fatal("Acquire() took a held slot")
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	if _, ok, err := semaphore.Acquire(ctx, key, 1, time.Minute); err != nil || !ok {
+		t.Errorf("Acquire() after the lease expired = %v, %v, want true", ok, err)
+	}
+}
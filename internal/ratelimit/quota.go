@@ -0,0 +1,37 @@
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/config"
+)
+
+// Quota counts usage per key within fixed windows (e.g. a UTC day) and refuses
+// once the window's limit is reached. Counts are shared by every worker.
+type Quota interface {
+	// Take consumes one unit of key's quota in the window starting at windowStart.
+	// It returns false, without consuming anything, once limit has been reached.
+	Take(ctx context.Context, key string, windowStart time.Time, window time.Duration, limit int) (bool, error)
+
+	// Used returns how many units of key's quota were taken in the window
+	// starting at windowStart
+	Used(ctx context.Context, key string, windowStart time.Time) (int, error)
+
+	// Refund gives back one unit of key's quota taken in the window starting
+	// at windowStart. A window with nothing taken is left as it is.
+	Refund(ctx context.Context, key string, windowStart time.Time) error
+
+	// Close releases the quota's connection
+	Close() error
+}
+
+// NewQuota creates the quota counter of the configured queue backend.
+// database is only used by the Postgres backend.
+func NewQuota(cfg config.QueueConfig, database *sql.DB) (Quota, error) {
+	if cfg.Backend == config.QueueBackendPostgres {
+		return NewPostgresQuota(database), nil
+	}
+	return NewRedisQuota(cfg.RedisURL)
+}
@@ -0,0 +1,109 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// quotaKeyPrefix namespaces the quota counter keys in Redis
+const quotaKeyPrefix = "quota:"
+
+// takeQuotaScript increments a window counter unless it already reached the limit.
+// Returns 1 when a unit was taken, 0 when the limit is reached.
+//
+// KEYS[1] counter key, ARGV[1] limit, ARGV[2] counter TTL in milliseconds
+var takeQuotaScript = redis.NewScript(`
+local used = tonumber(redis.call('GET', KEYS[1]) or '0')
+if used >= tonumber(ARGV[1]) then
+	return 0
+end
+redis.call('INCR', KEYS[1])
+if used == 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 1
+`)
+
+// refundQuotaScript decrements a window counter that is above zero, keeping its TTL.
+//
+// KEYS[1] counter key
+var refundQuotaScript = redis.NewScript(`
+local used = tonumber(redis.call('GET', KEYS[1]) or '0')
+if used > 0 then
+	redis.call('DECR', KEYS[1])
+end
+return used
+`)
+
+// redisQuota implements Quota with one Redis counter per key and window
+type redisQuota struct {
+	client *redis.Client
+}
+
+// NewRedisQuota creates a new Redis-backed quota counter
+func NewRedisQuota(url string) (Quota, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Redis URL: %w", err)
+	}
+
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	return &redisQuota{client: client}, nil
+}
+
+// Take consumes one unit of the key's quota for the window
+func (q *redisQuota) Take(ctx context.Context, key string, windowStart time.Time, window time.Duration, limit int) (bool, error) {
+	counterKey := quotaCounterKey(key, windowStart)
+
+	// Keep counters for one extra window so late workers still see them
+	ttl := (2 * window).Milliseconds()
+
+	taken, err := takeQuotaScript.Run(ctx, q.client, []string{counterKey}, limit, ttl).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to take quota: %w", err)
+	}
+
+	return taken == 1, nil
+}
+
+// Used reads the key's counter for the window; a window without one is unused
+func (q *redisQuota) Used(ctx context.Context, key string, windowStart time.Time) (int, error) {
+	used, err := q.client.Get(ctx, quotaCounterKey(key, windowStart)).Int()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read quota: %w", err)
+	}
+
+	return used, nil
+}
+
+// Refund gives back one unit of the key's quota for the window
+func (q *redisQuota) Refund(ctx context.Context, key string, windowStart time.Time) error {
+	if err := refundQuotaScript.Run(ctx, q.client, []string{quotaCounterKey(key, windowStart)}).Err(); err != nil {
+		return fmt.Errorf("failed to refund quota: %w", err)
+	}
+	return nil
+}
+
+// quotaCounterKey returns the Redis key counting key's usage in a window
+func quotaCounterKey(key string, windowStart time.Time) string {
+	return fmt.Sprintf("%s%s:%d", quotaKeyPrefix, key, windowStart.Unix())
+}
+
+// Close closes the Redis connection
+func (q *redisQuota) Close() error {
+	return q.client.Close()
+}
@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// semaphoreKeyPrefix namespaces the semaphore keys in Redis
+const semaphoreKeyPrefix = "semaphore:"
+
+// acquireSemaphoreScript drops expired leases and adds a lease unless all
+// slots are held. Leases are scored by their expiry on the Redis server clock.
+// Returns 1 when a slot was taken, 0 when all are held.
+//
+// KEYS[1] semaphore key, ARGV[1] limit, ARGV[2] lease TTL in milliseconds, ARGV[3] lease
+var acquireSemaphoreScript = redis.NewScript(`
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local ttl = tonumber(ARGV[2])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now)
+if redis.call('ZCARD', KEYS[1]) >= tonumber(ARGV[1]) then
+	return 0
+end
+redis.call('ZADD', KEYS[1], now + ttl, ARGV[3])
+redis.call('PEXPIRE', KEYS[1], ttl)
+return 1
+`)
+
+// redisSemaphore implements Semaphore with one Redis sorted set of leases per key
+type redisSemaphore struct {
+	client *redis.Client
+}
+
+// NewRedisSemaphore creates a new Redis-backed semaphore
+func NewRedisSemaphore(url string) (Semaphore, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Redis URL: %w", err)
+	}
+
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	return &redisSemaphore{client: client}, nil
+}
+
+// Acquire takes a slot of key under a new random lease
+func (s *redisSemaphore) Acquire(ctx context.Context, key string, limit int, ttl time.Duration) (string, bool, error) {
+	lease, err := newLease()
+	if err != nil {
+		return "", false, err
+	}
+
+	taken, err := acquireSemaphoreScript.Run(ctx, s.client, []string{semaphoreKeyPrefix + key}, limit, ttl.Milliseconds(), lease).Int()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to acquire semaphore: %w", err)
+	}
+	if taken == 0 {
+		return "", false, nil
+	}
+
+	return lease, true, nil
+}
+
+// Release removes the lease from key
+func (s *redisSemaphore) Release(ctx context.Context, key, lease string) error {
+	if err := s.client.ZRem(ctx, semaphoreKeyPrefix+key, lease).Err(); err != nil {
+		return fmt.Errorf("failed to release semaphore: %w", err)
+	}
+	return nil
+}
+
+// Close closes the Redis connection
+func (s *redisSemaphore) Close() error {
+	return s.client.Close()
+}
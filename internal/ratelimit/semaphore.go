@@ -0,0 +1,46 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/config"
+)
+
+// Semaphore limits how many holders may hold a slot of a key at once. Slots
+// are shared by every worker.
+type Semaphore interface {
+	// Acquire takes one of key's limit slots and returns the lease to release
+	// it with. It returns false, without taking anything, while all slots are
+	// held. A slot that is not released within ttl (e.g. its holder died) is
+	// freed on its own.
+	Acquire(ctx context.Context, key string, limit int, ttl time.Duration) (string, bool, error)
+
+	// Release gives back the slot held by lease
+	Release(ctx context.Context, key, lease string) error
+
+	// Close releases the semaphore's connection
+	Close() error
+}
+
+// NewSemaphore creates the semaphore of the configured queue backend.
+// database is only used by the Postgres backend.
+func NewSemaphore(cfg config.QueueConfig, database *sql.DB) (Semaphore, error) {
+	if cfg.Backend == config.QueueBackendPostgres {
+		return NewPostgresSemaphore(database), nil
+	}
+	return NewRedisSemaphore(cfg.RedisURL)
+}
+
+// newLease returns a random lease ID
+func newLease() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to create lease: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
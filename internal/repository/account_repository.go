@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// AccountRepository defines the interface for account data access
+type AccountRepository interface {
+	// Create inserts the account together with its first admin, so an account
+	// is never left without anyone able to sign in to it
+	Create(ctx context.Context, account *models.Account, admin *models.User) error
+	GetByID(ctx context.Context, id int64) (*models.Account, error)
+	List(ctx context.Context) ([]*models.Account, error)
+	// SetDailySendCap sets or, with nil, removes the account's daily send cap
+	SetDailySendCap(ctx context.Context, id int64, dailySendCap *int) error
+	// SetCustomerAttributes replaces the customer attribute keys the
+	// account's templates may use
+	SetCustomerAttributes(ctx context.Context, id int64, keys []string) error
+	// SetBouncePolicy replaces how the account's bounced messages are handled
+	SetBouncePolicy(ctx context.Context, id int64, policy models.BouncePolicy) error
+}
+
+// accountRepository implements AccountRepository using PostgreSQL
+type accountRepository struct {
+	db *sql.DB
+}
+
+// NewAccountRepository creates a new account repository
+func NewAccountRepository(db *sql.DB) AccountRepository {
+	return &accountRepository{db: db}
+}
+
+// accountScope returns the account that queries run under ctx are restricted
+// to. Queries pass it for a "($n::BIGINT = 0 OR account_id = $n)" condition,
+// so 0, background work acting for no account, matches every account.
+func accountScope(ctx context.Context) int64 {
+	return models.AccountIDFromContext(ctx)
+}
+
+// ownerAccount returns the account that rows created under ctx belong to.
+// Background work creating rows of its own acts for the default account.
+func ownerAccount(ctx context.Context) int64 {
+	if accountID := models.AccountIDFromContext(ctx); accountID != 0 {
+		return accountID
+	}
+	return models.DefaultAccountID
+}
+
+// Create inserts an account and its admin in a single transaction
+func (r *accountRepository) Create(ctx context.Context, account *models.Account, admin *models.User) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback() // Rollback is safe to call even after Commit
+	}()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO accounts (name) VALUES ($1)
+		RETURNING id, soft_bounce_retry_hours, soft_bounce_max_retries, suppress_hard_bounces, created_at, updated_at`, account.Name).
+		Scan(&account.ID, &account.BouncePolicy.SoftRetryHours, &account.BouncePolicy.SoftMaxRetries, &account.BouncePolicy.SuppressHard, &account.CreatedAt, &account.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create account: %w", err)
+	}
+
+	query := `
+		INSERT INTO users (account_id, email, password_hash, role)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, account_id, created_at, updated_at`
+
+	err = tx.QueryRowContext(ctx, query, account.ID, admin.Email, admin.PasswordHash, admin.Role).
+		Scan(&admin.ID, &admin.AccountID, &admin.CreatedAt, &admin.UpdatedAt)
+	if isUniqueViolation(err) {
+		return models.ErrConflictWithMsg(fmt.Sprintf("user %s already exists", admin.Email))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create account admin: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an account by ID
+func (r *accountRepository) GetByID(ctx context.Context, id int64) (*models.Account, error) {
+	query := `SELECT id, name, daily_send_cap, customer_attributes, soft_bounce_retry_hours, soft_bounce_max_retries, suppress_hard_bounces, created_at, updated_at FROM accounts WHERE id = $1`
+
+	account := &models.Account{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&account.ID, &account.Name, &account.DailySendCap, pq.Array(&account.CustomerAttributes), &account.BouncePolicy.SoftRetryHours, &account.BouncePolicy.SoftMaxRetries, &account.BouncePolicy.SuppressHard, &account.CreatedAt, &account.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFoundWithMsg(fmt.Sprintf("account with ID %d not found", id))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	return account, nil
+}
+
+// List retrieves every account ordered by ID
+func (r *accountRepository) List(ctx context.Context) ([]*models.Account, error) {
+	query := `SELECT id, name, daily_send_cap, customer_attributes, soft_bounce_retry_hours, soft_bounce_max_retries, suppress_hard_bounces, created_at, updated_at FROM accounts ORDER BY id ASC`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	defer rows.Close()
+
+	accounts := []*models.Account{}
+	for rows.Next() {
+		account := &models.Account{}
+		if err := rows.Scan(&account.ID, &account.Name, &account.DailySendCap, pq.Array(&account.CustomerAttributes), &account.BouncePolicy.SoftRetryHours, &account.BouncePolicy.SoftMaxRetries, &account.BouncePolicy.SuppressHard, &account.CreatedAt, &account.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan account: %w", err)
+		}
+		accounts = append(accounts, account)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating accounts: %w", err)
+	}
+
+	return accounts, nil
+}
+
+// SetDailySendCap updates the daily send cap of an account
+func (r *accountRepository) SetDailySendCap(ctx context.Context, id int64, dailySendCap *int) error {
+	query := `UPDATE accounts SET daily_send_cap = $1 WHERE id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, dailySendCap, id)
+	if err != nil {
+		return fmt.Errorf("failed to set daily send cap: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrNotFoundWithMsg(fmt.Sprintf("account with ID %d not found", id))
+	}
+
+	return nil
+}
+
+// SetCustomerAttributes updates the customer attribute keys of an account
+func (r *accountRepository) SetCustomerAttributes(ctx context.Context, id int64, keys []string) error {
+	query := `UPDATE accounts SET customer_attributes = $1 WHERE id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, pq.Array(keys), id)
+	if err != nil {
+		return fmt.Errorf("failed to set customer attributes: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrNotFoundWithMsg(fmt.Sprintf("account with ID %d not found", id))
+	}
+
+	return nil
+}
+
+// SetBouncePolicy updates the bounce policy of an account
+func (r *accountRepository) SetBouncePolicy(ctx context.Context, id int64, policy models.BouncePolicy) error {
+	query := `
+		UPDATE accounts
+		SET soft_bounce_retry_hours = $1, soft_bounce_max_retries = $2, suppress_hard_bounces = $3
+		WHERE id = $4`
+
+	result, err := r.db.ExecContext(ctx, query, policy.SoftRetryHours, policy.SoftMaxRetries, policy.SuppressHard, id)
+	if err != nil {
+		return fmt.Errorf("failed to set bounce policy: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrNotFoundWithMsg(fmt.Sprintf("account with ID %d not found", id))
+	}
+
+	return nil
+}
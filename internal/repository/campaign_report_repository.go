@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// CampaignReportRepository defines the aggregate queries behind campaign
+// reports. Every query reads the messages of one campaign in the account of
+// ctx through the (campaign_id, status) index.
+type CampaignReportRepository interface {
+	// Funnel counts the campaign's message events per time bucket, oldest
+	// first. Buckets without events are left out.
+	Funnel(ctx context.Context, campaignID int64, bucket string) ([]*models.FunnelBucket, error)
+	// FailureReasons counts failed and undelivered messages by error, most
+	// common first
+	FailureReasons(ctx context.Context, campaignID int64, limit int) ([]*models.FailureReasonCount, error)
+	// Retries counts the campaign's messages by retry count
+	Retries(ctx context.Context, campaignID int64) ([]*models.RetryCount, error)
+	// SendLatency measures the time from a message's creation to its first
+	// sent event
+	SendLatency(ctx context.Context, campaignID int64) (*models.SendLatency, error)
+}
+
+// campaignReportRepository implements CampaignReportRepository using PostgreSQL
+type campaignReportRepository struct {
+	db *sql.DB
+}
+
+// NewCampaignReportRepository creates a new campaign report repository
+func NewCampaignReportRepository(db *sql.DB) CampaignReportRepository {
+	return &campaignReportRepository{db: db}
+}
+
+// Funnel truncates event times to the bucket; bucket must be one that
+// date_trunc accepts
+func (r *campaignReportRepository) Funnel(ctx context.Context, campaignID int64, bucket string) ([]*models.FunnelBucket, error) {
+	query := `
+		SELECT date_trunc($2, e.created_at) AS start,
+			COUNT(*) FILTER (WHERE e.type = 'queued'),
+			COUNT(*) FILTER (WHERE e.type = 'sent'),
+			COUNT(*) FILTER (WHERE e.type = 'delivered'),
+			COUNT(*) FILTER (WHERE e.type = 'undelivered'),
+			COUNT(*) FILTER (WHERE e.type = 'failed')
+		FROM outbound_messages om
+		JOIN message_events e ON e.message_id = om.id
+		WHERE om.campaign_id = $1 AND ($3::BIGINT = 0 OR om.account_id = $3)
+			AND e.type IN ('queued', 'sent', 'delivered', 'undelivered', 'failed')
+		GROUP BY start
+		ORDER BY start ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, campaignID, bucket, accountScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count campaign funnel: %w", err)
+	}
+	defer rows.Close()
+
+	buckets := []*models.FunnelBucket{}
+	for rows.Next() {
+		b := &models.FunnelBucket{}
+		if err := rows.Scan(&b.Start, &b.Queued, &b.Sent, &b.Delivered, &b.Undelivered, &b.Failed); err != nil {
+			return nil, fmt.Errorf("failed to scan funnel bucket: %w", err)
+		}
+		buckets = append(buckets, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating funnel buckets: %w", err)
+	}
+
+	return buckets, nil
+}
+
+// FailureReasons groups messages by their last error. Messages that failed
+// without one are counted under "unknown".
+func (r *campaignReportRepository) FailureReasons(ctx context.Context, campaignID int64, limit int) ([]*models.FailureReasonCount, error) {
+	query := `
+		SELECT COALESCE(NULLIF(last_error, ''), 'unknown') AS reason, COUNT(*) AS messages
+		FROM outbound_messages
+		WHERE campaign_id = $1 AND status IN ('failed', 'undelivered')
+			AND ($3::BIGINT = 0 OR account_id = $3)
+		GROUP BY reason
+		ORDER BY messages DESC, reason ASC
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, campaignID, limit, accountScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count failure reasons: %w", err)
+	}
+	defer rows.Close()
+
+	reasons := []*models.FailureReasonCount{}
+	for rows.Next() {
+		reason := &models.FailureReasonCount{}
+		if err := rows.Scan(&reason.Reason, &reason.Messages); err != nil {
+			return nil, fmt.Errorf("failed to scan failure reason: %w", err)
+		}
+		reasons = append(reasons, reason)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating failure reasons: %w", err)
+	}
+
+	return reasons, nil
+}
+
+// Retries groups messages by retry count, fewest retries first
+func (r *campaignReportRepository) Retries(ctx context.Context, campaignID int64) ([]*models.RetryCount, error) {
+	query := `
+		SELECT retry_count, COUNT(*)
+		FROM outbound_messages
+		WHERE campaign_id = $1 AND ($2::BIGINT = 0 OR account_id = $2)
+		GROUP BY retry_count
+		ORDER BY retry_count ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, campaignID, accountScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count retries: %w", err)
+	}
+	defer rows.Close()
+
+	retries := []*models.RetryCount{}
+	for rows.Next() {
+		count := &models.RetryCount{}
+		if err := rows.Scan(&count.Retries, &count.Messages); err != nil {
+			return nil, fmt.Errorf("failed to scan retry count: %w", err)
+		}
+		retries = append(retries, count)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating retry counts: %w", err)
+	}
+
+	return retries, nil
+}
+
+// SendLatency takes each message's first sent event, so a soft bounce sent
+// again later does not count as a slow send
+func (r *campaignReportRepository) SendLatency(ctx context.Context, campaignID int64) (*models.SendLatency, error) {
+	query := `
+		SELECT COUNT(*), AVG(seconds), MAX(seconds)
+		FROM (
+			SELECT EXTRACT(EPOCH FROM MIN(e.created_at) - om.created_at)::FLOAT8 AS seconds
+			FROM outbound_messages om
+			JOIN message_events e ON e.message_id = om.id AND e.type = 'sent'
+			WHERE om.campaign_id = $1 AND ($2::BIGINT = 0 OR om.account_id = $2)
+			GROUP BY om.id, om.created_at
+		) latencies`
+
+	latency := &models.SendLatency{}
+	err := r.db.QueryRowContext(ctx, query, campaignID, accountScope(ctx)).
+		Scan(&latency.Messages, &latency.AverageSeconds, &latency.MaxSeconds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure send latency: %w", err)
+	}
+
+	return latency, nil
+}
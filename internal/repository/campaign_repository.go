@@ -3,7 +3,11 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"time"
+
+	"github.com/lib/pq"
 
 	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
 )
@@ -12,11 +16,73 @@ import (
 type CampaignRepository interface {
 	Create(ctx context.Context, campaign *models.Campaign) error
 	GetByID(ctx context.Context, id int64) (*models.Campaign, error)
+	GetByExternalKey(ctx context.Context, key string) (*models.Campaign, error)
+	GetByExternalID(ctx context.Context, externalID string) (*models.Campaign, error)
+	GetBySlug(ctx context.Context, slug string) (*models.Campaign, error)
 	GetWithStats(ctx context.Context, id int64) (*models.CampaignWithStats, error)
+	// CountMessages counts a campaign's messages by status, and returns the
+	// snapshot the count was taken in
+	CountMessages(ctx context.Context, id int64) (*models.CampaignStats, models.TxSnapshot, error)
+	GetCostAccrued(ctx context.Context, id int64) (float64, error)
 	List(ctx context.Context, filter models.CampaignFilter) ([]*models.Campaign, int64, error)
+	// ListUpdatedSince returns up to limit campaigns updated after the cursor,
+	// oldest change first. Changes younger than settle are left for a later
+	// poll so rows written by transactions still in flight are not skipped.
+	ListUpdatedSince(ctx context.Context, after models.ChangeCursor, settle time.Duration, limit int) ([]*models.CampaignChange, error)
 	Update(ctx context.Context, campaign *models.Campaign) error
+	// UpsertByExternalKey creates the campaign, or updates the campaign with the
+	// same external key when it is still a draft or scheduled. It reports
+	// whether the campaign was created.
+	UpsertByExternalKey(ctx context.Context, campaign *models.Campaign) (bool, error)
 	UpdateStatus(ctx context.Context, id int64, status string) error
+	// TransitionStatus moves the campaign from status from to status to and
+	// reports whether it did; it does not when the campaign's status is no
+	// longer from, e.g. because a concurrent send claimed it first
+	TransitionStatus(ctx context.Context, id int64, from, to string) (bool, error)
+	// SetBuilding records whether a send is still creating the campaign's messages
+	SetBuilding(ctx context.Context, id int64, building bool) error
+	// CompleteSend moves a sending or paused campaign whose send has finished
+	// building to its final status and reports whether it did
+	CompleteSend(ctx context.Context, id int64, status string) (bool, error)
+	// ClaimDueCampaigns claims up to limit scheduled campaigns with a bound
+	// audience whose scheduled_at, brought forward by their prebuild_minutes,
+	// is at or before now, ready campaigns whose scheduled_at is, and paused
+	// campaigns whose resume_at is, and returns their IDs. Campaigns claimed by
+	// another replica after staleBefore are skipped.
+	ClaimDueCampaigns(ctx context.Context, now, staleBefore time.Time, limit int) ([]int64, error)
+	PauseSendingByChannel(ctx context.Context, channel, reason string) ([]int64, error)
+	// PauseSending pauses the campaign if it is sending, or holds a campaign
+	// that awaits its next delivery window, and reports whether it did
+	PauseSending(ctx context.Context, id int64, reason string) (bool, error)
+	// PauseUntil pauses the campaign if it is sending, to be resumed by the
+	// scheduler at resumeAt. It reports whether the campaign now awaits
+	// resumeAt, which is also the case when another worker paused it first.
+	PauseUntil(ctx context.Context, id int64, reason string, resumeAt time.Time) (bool, error)
+	Resume(ctx context.Context, id int64) error
+	// Cancel cancels the campaign if it is scheduled, ready, awaiting approval,
+	// sending or paused and reports whether it did
+	Cancel(ctx context.Context, id int64) (bool, error)
+	// SetMaxCost changes the campaign's cost cap; nil removes it
+	SetMaxCost(ctx context.Context, id int64, maxCost *float64) error
+	// SetMaxInFlight changes how many of the campaign's messages may be sent
+	// at once; nil removes the limit
+	SetMaxInFlight(ctx context.Context, id int64, maxInFlight *int) error
+	// SetValidateNumbers changes whether the campaign's numbers are looked up
+	// before they are sent to
+	SetValidateNumbers(ctx context.Context, id int64, validate bool) error
+	// SetUTM replaces the UTM parameters appended to the links of the
+	// campaign's messages; nil removes them
+	SetUTM(ctx context.Context, id int64, utm *models.CampaignUTM) error
+	// SetShortDomain changes the short domain chosen for the campaign's
+	// links; nil leaves the choice to the account's default
+	SetShortDomain(ctx context.Context, id int64, shortDomainID *int64) error
+	// ReserveCost adds amount to the campaign's accrued cost unless that would
+	// exceed its cost cap, and reports whether it did
+	ReserveCost(ctx context.Context, id int64, amount float64) (bool, error)
+	// ReleaseCost gives back a reservation for a message that was not sent
+	ReleaseCost(ctx context.Context, id int64, amount float64) error
 	Delete(ctx context.Context, id int64) error
+	DeleteWithMessages(ctx context.Context, id int64) error
 }
 
 // campaignRepository implements CampaignRepository using PostgreSQL
@@ -29,23 +95,47 @@ func NewCampaignRepository(db *sql.DB) CampaignRepository {
 	return &campaignRepository{db: db}
 }
 
-// Create inserts a new campaign
+// Create inserts a new campaign into the account of ctx, with a unique slug
+// derived from its name
 func (r *campaignRepository) Create(ctx context.Context, campaign *models.Campaign) error {
 	query := `
-		INSERT INTO campaigns (name, channel, status, base_template, scheduled_at)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, created_at`
+		INSERT INTO campaigns (name, slug, channel, status, base_template, sender_id, delivery_windows, timezone, scheduled_at, labels, external_id, audience, max_cost, max_in_flight, prebuild_minutes, environment, locale, account_id, template_id, template_version, validate_numbers, utm)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, COALESCE(NULLIF($8, ''), 'UTC'), $9, COALESCE($10::TEXT[], '{}'), $11, $12, $13, $14, $15, COALESCE(NULLIF($16, ''), 'live'), COALESCE(NULLIF($17, ''), 'en'), $18, $19, $20, $21, $22)
+		RETURNING id, account_id, timezone, environment, locale, created_at`
 
-	err := r.db.QueryRowContext(
-		ctx,
-		query,
-		campaign.Name,
-		campaign.Channel,
-		campaign.Status,
-		campaign.BaseTemplate,
-		campaign.ScheduledAt,
-	).Scan(&campaign.ID, &campaign.CreatedAt)
+	err := r.withFreeSlug(ctx, campaign.Name, func(slug string) error {
+		campaign.Slug = slug
+		return r.db.QueryRowContext(
+			ctx,
+			query,
+			campaign.Name,
+			slug,
+			campaign.Channel,
+			campaign.Status,
+			campaign.BaseTemplate,
+			campaign.SenderID,
+			campaign.DeliveryWindows,
+			campaign.Timezone,
+			utcTime(campaign.ScheduledAt),
+			pq.Array(campaign.Labels),
+			campaign.ExternalID,
+			campaign.Audience,
+			campaign.MaxCost,
+			campaign.MaxInFlight,
+			campaign.PrebuildMinutes,
+			campaign.Environment,
+			campaign.Locale,
+			ownerAccount(ctx),
+			campaign.TemplateID,
+			campaign.TemplateVersion,
+			campaign.ValidateNumbers,
+			campaign.UTM,
+		).Scan(&campaign.ID, &campaign.AccountID, &campaign.Timezone, &campaign.Environment, &campaign.Locale, &campaign.CreatedAt)
+	})
 
+	if isConstraintViolation(err, "idx_campaigns_external_id") {
+		return models.ErrConflictWithMsg(fmt.Sprintf("campaign with external ID %s already exists", *campaign.ExternalID))
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create campaign: %w", err)
 	}
@@ -53,21 +143,102 @@ func (r *campaignRepository) Create(ctx context.Context, campaign *models.Campai
 	return nil
 }
 
+// maxSlugAttempts bounds how often an insert is retried when concurrent
+// creates of campaigns with the same name race for one slug
+const maxSlugAttempts = 5
+
+// withFreeSlug runs insert with the first slug not yet taken by another
+// campaign of the account: the base slug of name, or that base with the lowest free suffix
+// from 2 up. Insert is run again with a new slug if another campaign took the
+// slug in the meantime.
+func (r *campaignRepository) withFreeSlug(ctx context.Context, name string, insert func(slug string) error) error {
+	base := models.Slugify(name)
+
+	for attempt := 1; ; attempt++ {
+		slug, err := r.freeSlug(ctx, base)
+		if err != nil {
+			return err
+		}
+
+		err = insert(slug)
+		if isConstraintViolation(err, "idx_campaigns_slug") && attempt < maxSlugAttempts {
+			continue
+		}
+		return err
+	}
+}
+
+// freeSlug returns base, or base-N with the lowest N from 2 that is not taken
+func (r *campaignRepository) freeSlug(ctx context.Context, base string) (string, error) {
+	// Slugs only hold [a-z0-9-], so base needs no LIKE escaping
+	query := `SELECT slug FROM campaigns WHERE account_id = $2 AND (slug = $1 OR slug LIKE $1 || '-%')`
+
+	rows, err := r.db.QueryContext(ctx, query, base, ownerAccount(ctx))
+	if err != nil {
+		return "", fmt.Errorf("failed to look up campaign slugs: %w", err)
+	}
+	defer rows.Close()
+
+	taken := make(map[string]bool)
+	for rows.Next() {
+		var slug string
+		if err := rows.Scan(&slug); err != nil {
+			return "", fmt.Errorf("failed to scan campaign slug: %w", err)
+		}
+		taken[slug] = true
+	}
+
+	if err = rows.Err(); err != nil {
+		return "", fmt.Errorf("error iterating campaign slugs: %w", err)
+	}
+
+	if !taken[base] {
+		return base, nil
+	}
+	for n := 2; ; n++ {
+		if slug := fmt.Sprintf("%s-%d", base, n); !taken[slug] {
+			return slug, nil
+		}
+	}
+}
+
 // GetByID retrieves a campaign by ID
 func (r *campaignRepository) GetByID(ctx context.Context, id int64) (*models.Campaign, error) {
 	query := `
-		SELECT id, name, channel, status, base_template, scheduled_at, created_at
+		SELECT id, account_id, name, slug, channel, status, environment, locale, validate_numbers, base_template, template_id, template_version, sender_id, delivery_windows, timezone, scheduled_at, labels, audience, utm, short_domain_id, max_cost, max_in_flight, prebuild_minutes, external_key, external_id, paused_reason, paused_at, resume_at, created_at
 		FROM campaigns
-		WHERE id = $1`
+		WHERE id = $1 AND ($2::BIGINT = 0 OR account_id = $2)`
 
 	campaign := &models.Campaign{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := r.db.QueryRowContext(ctx, query, id, accountScope(ctx)).Scan(
 		&campaign.ID,
+		&campaign.AccountID,
 		&campaign.Name,
+		&campaign.Slug,
 		&campaign.Channel,
 		&campaign.Status,
+		&campaign.Environment,
+		&campaign.Locale,
+		&campaign.ValidateNumbers,
 		&campaign.BaseTemplate,
+		&campaign.TemplateID,
+		&campaign.TemplateVersion,
+		&campaign.SenderID,
+		&campaign.DeliveryWindows,
+		&campaign.Timezone,
 		&campaign.ScheduledAt,
+		pq.Array(&campaign.Labels),
+		&campaign.Audience,
+		&campaign.UTM,
+		&campaign.ShortDomainID,
+		&campaign.MaxCost,
+		&campaign.MaxInFlight,
+		&campaign.PrebuildMinutes,
+		&campaign.ExternalKey,
+		&campaign.ExternalID,
+		&campaign.PausedReason,
+		&campaign.PausedAt,
+		&campaign.ResumeAt,
 		&campaign.CreatedAt,
 	)
 
@@ -81,48 +252,230 @@ func (r *campaignRepository) GetByID(ctx context.Context, id int64) (*models.Cam
 	return campaign, nil
 }
 
+// GetByExternalKey retrieves a campaign by its caller-supplied external key
+func (r *campaignRepository) GetByExternalKey(ctx context.Context, key string) (*models.Campaign, error) {
+	query := `
+		SELECT id, account_id, name, slug, channel, status, environment, locale, validate_numbers, base_template, template_id, template_version, sender_id, delivery_windows, timezone, scheduled_at, labels, audience, utm, short_domain_id, max_cost, max_in_flight, prebuild_minutes, external_key, external_id, paused_reason, paused_at, resume_at, created_at
+		FROM campaigns
+		WHERE external_key = $1 AND ($2::BIGINT = 0 OR account_id = $2)`
+
+	campaign := &models.Campaign{}
+	err := r.db.QueryRowContext(ctx, query, key, accountScope(ctx)).Scan(
+		&campaign.ID,
+		&campaign.AccountID,
+		&campaign.Name,
+		&campaign.Slug,
+		&campaign.Channel,
+		&campaign.Status,
+		&campaign.Environment,
+		&campaign.Locale,
+		&campaign.ValidateNumbers,
+		&campaign.BaseTemplate,
+		&campaign.TemplateID,
+		&campaign.TemplateVersion,
+		&campaign.SenderID,
+		&campaign.DeliveryWindows,
+		&campaign.Timezone,
+		&campaign.ScheduledAt,
+		pq.Array(&campaign.Labels),
+		&campaign.Audience,
+		&campaign.UTM,
+		&campaign.ShortDomainID,
+		&campaign.MaxCost,
+		&campaign.MaxInFlight,
+		&campaign.PrebuildMinutes,
+		&campaign.ExternalKey,
+		&campaign.ExternalID,
+		&campaign.PausedReason,
+		&campaign.PausedAt,
+		&campaign.ResumeAt,
+		&campaign.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFoundWithMsg(fmt.Sprintf("campaign with external key %q not found", key))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign: %w", err)
+	}
+
+	return campaign, nil
+}
+
+// GetByExternalID retrieves a campaign by the ID an external system knows it by
+func (r *campaignRepository) GetByExternalID(ctx context.Context, externalID string) (*models.Campaign, error) {
+	query := `
+		SELECT id, account_id, name, slug, channel, status, environment, locale, validate_numbers, base_template, template_id, template_version, sender_id, delivery_windows, timezone, scheduled_at, labels, audience, utm, short_domain_id, max_cost, max_in_flight, prebuild_minutes, external_key, external_id, paused_reason, paused_at, resume_at, created_at
+		FROM campaigns
+		WHERE external_id = $1 AND ($2::BIGINT = 0 OR account_id = $2)`
+
+	campaign := &models.Campaign{}
+	err := r.db.QueryRowContext(ctx, query, externalID, accountScope(ctx)).Scan(
+		&campaign.ID,
+		&campaign.AccountID,
+		&campaign.Name,
+		&campaign.Slug,
+		&campaign.Channel,
+		&campaign.Status,
+		&campaign.Environment,
+		&campaign.Locale,
+		&campaign.ValidateNumbers,
+		&campaign.BaseTemplate,
+		&campaign.TemplateID,
+		&campaign.TemplateVersion,
+		&campaign.SenderID,
+		&campaign.DeliveryWindows,
+		&campaign.Timezone,
+		&campaign.ScheduledAt,
+		pq.Array(&campaign.Labels),
+		&campaign.Audience,
+		&campaign.UTM,
+		&campaign.ShortDomainID,
+		&campaign.MaxCost,
+		&campaign.MaxInFlight,
+		&campaign.PrebuildMinutes,
+		&campaign.ExternalKey,
+		&campaign.ExternalID,
+		&campaign.PausedReason,
+		&campaign.PausedAt,
+		&campaign.ResumeAt,
+		&campaign.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFoundWithMsg(fmt.Sprintf("campaign with external ID %s not found", externalID))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign by external ID: %w", err)
+	}
+
+	return campaign, nil
+}
+
+// GetBySlug retrieves a campaign by its slug
+func (r *campaignRepository) GetBySlug(ctx context.Context, slug string) (*models.Campaign, error) {
+	query := `
+		SELECT id, account_id, name, slug, channel, status, environment, locale, validate_numbers, base_template, template_id, template_version, sender_id, delivery_windows, timezone, scheduled_at, labels, audience, utm, short_domain_id, max_cost, max_in_flight, prebuild_minutes, external_key, external_id, paused_reason, paused_at, resume_at, created_at
+		FROM campaigns
+		WHERE slug = $1 AND ($2::BIGINT = 0 OR account_id = $2)`
+
+	campaign := &models.Campaign{}
+	err := r.db.QueryRowContext(ctx, query, slug, accountScope(ctx)).Scan(
+		&campaign.ID,
+		&campaign.AccountID,
+		&campaign.Name,
+		&campaign.Slug,
+		&campaign.Channel,
+		&campaign.Status,
+		&campaign.Environment,
+		&campaign.Locale,
+		&campaign.ValidateNumbers,
+		&campaign.BaseTemplate,
+		&campaign.TemplateID,
+		&campaign.TemplateVersion,
+		&campaign.SenderID,
+		&campaign.DeliveryWindows,
+		&campaign.Timezone,
+		&campaign.ScheduledAt,
+		pq.Array(&campaign.Labels),
+		&campaign.Audience,
+		&campaign.UTM,
+		&campaign.ShortDomainID,
+		&campaign.MaxCost,
+		&campaign.MaxInFlight,
+		&campaign.PrebuildMinutes,
+		&campaign.ExternalKey,
+		&campaign.ExternalID,
+		&campaign.PausedReason,
+		&campaign.PausedAt,
+		&campaign.ResumeAt,
+		&campaign.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFoundWithMsg(fmt.Sprintf("campaign with slug %q not found", slug))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign by slug: %w", err)
+	}
+
+	return campaign, nil
+}
+
 // GetWithStats retrieves a campaign with message statistics
 func (r *campaignRepository) GetWithStats(ctx context.Context, id int64) (*models.CampaignWithStats, error) {
-	// Get campaign
 	campaign, err := r.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get stats
-	statsQuery := `
+	stats, _, err := r.CountMessages(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	costAccrued, err := r.GetCostAccrued(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewCampaignWithStats(campaign, *stats, costAccrued), nil
+}
+
+// CountMessages counts a campaign's messages by status, and returns the
+// snapshot the count was taken in
+func (r *campaignRepository) CountMessages(ctx context.Context, id int64) (*models.CampaignStats, models.TxSnapshot, error) {
+	query := `
 		SELECT
+			pg_current_snapshot()::TEXT,
 			COUNT(*) as total,
 			COUNT(*) FILTER (WHERE status = 'pending') as pending,
-			0 as sending,
-			COUNT(*) FILTER (WHERE status = 'sent') as sent,
-			COUNT(*) FILTER (WHERE status = 'failed') as failed
+			COUNT(*) FILTER (WHERE status = 'sending') as sending,
+			COUNT(*) FILTER (WHERE status IN ('sent', 'delivered', 'undelivered')) as sent,
+			COUNT(*) FILTER (WHERE status = 'failed') as failed,
+			COUNT(*) FILTER (WHERE status = 'delivered') as delivered,
+			COUNT(*) FILTER (WHERE status = 'undelivered') as undelivered,
+			COUNT(*) FILTER (WHERE status = 'skipped') as skipped
 		FROM outbound_messages
 		WHERE campaign_id = $1`
 
+	var snapshotText string
 	var stats models.CampaignStats
-	err = r.db.QueryRowContext(ctx, statsQuery, id).Scan(
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&snapshotText,
 		&stats.Total,
 		&stats.Pending,
 		&stats.Sending,
 		&stats.Sent,
 		&stats.Failed,
+		&stats.Delivered,
+		&stats.Undelivered,
+		&stats.Skipped,
 	)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to get campaign stats: %w", err)
+		return nil, models.TxSnapshot{}, fmt.Errorf("failed to get campaign stats: %w", err)
+	}
+
+	snapshot, err := models.ParseTxSnapshot(snapshotText)
+	if err != nil {
+		return nil, models.TxSnapshot{}, fmt.Errorf("failed to get campaign stats: %w", err)
 	}
 
-	return &models.CampaignWithStats{
-		ID:           campaign.ID,
-		Name:         campaign.Name,
-		Channel:      campaign.Channel,
-		Status:       campaign.Status,
-		BaseTemplate: campaign.BaseTemplate,
-		ScheduledAt:  campaign.ScheduledAt,
-		CreatedAt:    campaign.CreatedAt,
-		Stats:        stats,
-	}, nil
+	return &stats, snapshot, nil
+}
+
+// GetCostAccrued retrieves the cost of a campaign's messages sent or being
+// sent so far
+func (r *campaignRepository) GetCostAccrued(ctx context.Context, id int64) (float64, error) {
+	query := `SELECT COALESCE((SELECT accrued FROM campaign_costs WHERE campaign_id = $1), 0)`
+
+	var costAccrued float64
+	if err := r.db.QueryRowContext(ctx, query, id).Scan(&costAccrued); err != nil {
+		return 0, fmt.Errorf("failed to get campaign cost: %w", err)
+	}
+
+	return costAccrued, nil
 }
 
 // List retrieves campaigns with pagination and filtering
@@ -132,12 +485,12 @@ func (r *campaignRepository) List(ctx context.Context, filter models.CampaignFil
 
 	// Build query with filters
 	query := `
-		SELECT id, name, channel, status, base_template, scheduled_at, created_at
+		SELECT id, account_id, name, slug, channel, status, environment, locale, validate_numbers, base_template, template_id, template_version, sender_id, delivery_windows, timezone, scheduled_at, labels, audience, utm, short_domain_id, max_cost, max_in_flight, prebuild_minutes, external_key, external_id, paused_reason, paused_at, resume_at, created_at
 		FROM campaigns
-		WHERE 1=1`
-	countQuery := `SELECT COUNT(*) FROM campaigns WHERE 1=1`
-	args := []interface{}{}
-	argPos := 1
+		WHERE ($1::BIGINT = 0 OR account_id = $1)`
+	countQuery := `SELECT COUNT(*) FROM campaigns WHERE ($1::BIGINT = 0 OR account_id = $1)`
+	args := []interface{}{accountScope(ctx)}
+	argPos := 2
 
 	if filter.Channel != "" {
 		query += fmt.Sprintf(" AND channel = $%d", argPos)
@@ -153,6 +506,18 @@ func (r *campaignRepository) List(ctx context.Context, filter models.CampaignFil
 		argPos++
 	}
 
+	// Test campaigns are only listed when asked for
+	if filter.Environment != models.CampaignEnvironmentAll {
+		environment := filter.Environment
+		if environment == "" {
+			environment = models.CampaignEnvironmentLive
+		}
+		query += fmt.Sprintf(" AND environment = $%d", argPos)
+		countQuery += fmt.Sprintf(" AND environment = $%d", argPos)
+		args = append(args, environment)
+		argPos++
+	}
+
 	// Get total count
 	var totalCount int64
 	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&totalCount)
@@ -177,11 +542,33 @@ func (r *campaignRepository) List(ctx context.Context, filter models.CampaignFil
 		campaign := &models.Campaign{}
 		err := rows.Scan(
 			&campaign.ID,
+			&campaign.AccountID,
 			&campaign.Name,
+			&campaign.Slug,
 			&campaign.Channel,
 			&campaign.Status,
+			&campaign.Environment,
+			&campaign.Locale,
+			&campaign.ValidateNumbers,
 			&campaign.BaseTemplate,
+			&campaign.TemplateID,
+			&campaign.TemplateVersion,
+			&campaign.SenderID,
+			&campaign.DeliveryWindows,
+			&campaign.Timezone,
 			&campaign.ScheduledAt,
+			pq.Array(&campaign.Labels),
+			&campaign.Audience,
+			&campaign.UTM,
+			&campaign.ShortDomainID,
+			&campaign.MaxCost,
+			&campaign.MaxInFlight,
+			&campaign.PrebuildMinutes,
+			&campaign.ExternalKey,
+			&campaign.ExternalID,
+			&campaign.PausedReason,
+			&campaign.PausedAt,
+			&campaign.ResumeAt,
 			&campaign.CreatedAt,
 		)
 		if err != nil {
@@ -197,12 +584,77 @@ func (r *campaignRepository) List(ctx context.Context, filter models.CampaignFil
 	return campaigns, totalCount, nil
 }
 
+// ListUpdatedSince retrieves campaigns changed after the cursor in (updated_at, id) order
+func (r *campaignRepository) ListUpdatedSince(ctx context.Context, after models.ChangeCursor, settle time.Duration, limit int) ([]*models.CampaignChange, error) {
+	query := `
+		SELECT id, account_id, name, slug, channel, status, environment, locale, validate_numbers, base_template, template_id, template_version, sender_id, delivery_windows, timezone, scheduled_at, labels, audience, utm, short_domain_id, max_cost, max_in_flight, prebuild_minutes, external_key, external_id, paused_reason, paused_at, resume_at, created_at, updated_at
+		FROM campaigns
+		WHERE (updated_at, id) > ($1, $2)
+			AND updated_at < LOCALTIMESTAMP - make_interval(secs => $3)
+			AND ($5::BIGINT = 0 OR account_id = $5)
+		ORDER BY updated_at, id
+		LIMIT $4`
+
+	rows, err := r.db.QueryContext(ctx, query, after.UpdatedAt, after.ID, settle.Seconds(), limit, accountScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list updated campaigns: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []*models.CampaignChange{}
+	for rows.Next() {
+		change := &models.CampaignChange{Campaign: &models.Campaign{}}
+		err := rows.Scan(
+			&change.ID,
+			&change.AccountID,
+			&change.Name,
+			&change.Slug,
+			&change.Channel,
+			&change.Status,
+			&change.Environment,
+			&change.Locale,
+			&change.ValidateNumbers,
+			&change.BaseTemplate,
+			&change.TemplateID,
+			&change.TemplateVersion,
+			&change.SenderID,
+			&change.DeliveryWindows,
+			&change.Timezone,
+			&change.ScheduledAt,
+			pq.Array(&change.Labels),
+			&change.Audience,
+			&change.UTM,
+			&change.ShortDomainID,
+			&change.MaxCost,
+			&change.MaxInFlight,
+			&change.PrebuildMinutes,
+			&change.ExternalKey,
+			&change.ExternalID,
+			&change.PausedReason,
+			&change.PausedAt,
+			&change.ResumeAt,
+			&change.CreatedAt,
+			&change.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan updated campaign: %w", err)
+		}
+		changes = append(changes, change)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating updated campaigns: %w", err)
+	}
+
+	return changes, nil
+}
+
 // Update updates an existing campaign
 func (r *campaignRepository) Update(ctx context.Context, campaign *models.Campaign) error {
 	query := `
 		UPDATE campaigns
-		SET name = $1, channel = $2, status = $3, base_template = $4, scheduled_at = $5
-		WHERE id = $6
+		SET name = $1, channel = $2, status = $3, base_template = $4, sender_id = $5, delivery_windows = $6, scheduled_at = $7, labels = COALESCE($8::TEXT[], '{}'), external_id = $9, audience = $10, max_cost = $11, locale = COALESCE(NULLIF($12, ''), locale)
+		WHERE id = $13 AND ($14::BIGINT = 0 OR account_id = $14)
 		`
 
 	result, err := r.db.ExecContext(
@@ -212,9 +664,20 @@ func (r *campaignRepository) Update(ctx context.Context, campaign *models.Campai
 		campaign.Channel,
 		campaign.Status,
 		campaign.BaseTemplate,
-		campaign.ScheduledAt,
+		campaign.SenderID,
+		campaign.DeliveryWindows,
+		utcTime(campaign.ScheduledAt),
+		pq.Array(campaign.Labels),
+		campaign.ExternalID,
+		campaign.Audience,
+		campaign.MaxCost,
+		campaign.Locale,
 		campaign.ID,
+		accountScope(ctx),
 	)
+	if isUniqueViolation(err) {
+		return models.ErrConflictWithMsg(fmt.Sprintf("campaign with external ID %s already exists", *campaign.ExternalID))
+	}
 	if err != nil {
 		return fmt.Errorf("failed to update campaign: %w", err)
 	}
@@ -231,14 +694,107 @@ func (r *campaignRepository) Update(ctx context.Context, campaign *models.Campai
 	return nil
 }
 
+// UpsertByExternalKey inserts the campaign or, on an external key conflict,
+// updates the existing row in the same statement so concurrent calls with the
+// same key cannot create two campaigns. The update is skipped for campaigns
+// that have started sending, in which case a conflict is returned.
+func (r *campaignRepository) UpsertByExternalKey(ctx context.Context, campaign *models.Campaign) (bool, error) {
+	query := `
+		INSERT INTO campaigns (name, slug, channel, status, base_template, sender_id, delivery_windows, timezone, scheduled_at, labels, external_key, locale, account_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, COALESCE(NULLIF($8, ''), 'UTC'), $9, COALESCE($10::TEXT[], '{}'), $11, COALESCE(NULLIF($12, ''), 'en'), $13)
+		ON CONFLICT (account_id, external_key) WHERE external_key IS NOT NULL DO UPDATE
+		SET name = EXCLUDED.name, channel = EXCLUDED.channel, status = EXCLUDED.status,
+			base_template = EXCLUDED.base_template, sender_id = EXCLUDED.sender_id,
+			delivery_windows = EXCLUDED.delivery_windows, timezone = EXCLUDED.timezone,
+			scheduled_at = EXCLUDED.scheduled_at, labels = EXCLUDED.labels, locale = EXCLUDED.locale
+		WHERE campaigns.status IN ('draft', 'scheduled')
+		RETURNING id, account_id, slug, timezone, environment, locale, created_at, xmax = 0`
+
+	// An update keeps the existing slug; the free slug is only used on insert
+	var created bool
+	err := r.withFreeSlug(ctx, campaign.Name, func(freeSlug string) error {
+		return r.db.QueryRowContext(
+			ctx,
+			query,
+			campaign.Name,
+			freeSlug,
+			campaign.Channel,
+			campaign.Status,
+			campaign.BaseTemplate,
+			campaign.SenderID,
+			campaign.DeliveryWindows,
+			campaign.Timezone,
+			utcTime(campaign.ScheduledAt),
+			pq.Array(campaign.Labels),
+			campaign.ExternalKey,
+			campaign.Locale,
+			ownerAccount(ctx),
+		).Scan(&campaign.ID, &campaign.AccountID, &campaign.Slug, &campaign.Timezone, &campaign.Environment, &campaign.Locale, &campaign.CreatedAt, &created)
+	})
+
+	if err == sql.ErrNoRows {
+		return false, models.ErrConflictWithMsg("campaign has already started sending and can no longer be changed")
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to upsert campaign: %w", err)
+	}
+
+	return created, nil
+}
+
+// ClaimDueCampaigns marks due scheduled, ready and paused campaigns as claimed in one statement.
+// SKIP LOCKED lets replicas polling at the same moment claim different
+// campaigns instead of waiting on each other.
+func (r *campaignRepository) ClaimDueCampaigns(ctx context.Context, now, staleBefore time.Time, limit int) ([]int64, error) {
+	query := `
+		UPDATE campaigns
+		SET dispatch_claimed_at = $1
+		WHERE id IN (
+			SELECT id
+			FROM campaigns
+			WHERE (
+					(status = 'scheduled' AND audience IS NOT NULL
+						AND scheduled_at - make_interval(mins => COALESCE(prebuild_minutes, 0)) <= $1)
+					OR (status = 'ready' AND scheduled_at <= $1)
+					OR (status = 'paused' AND resume_at <= $1)
+				)
+				AND (dispatch_claimed_at IS NULL OR dispatch_claimed_at < $2)
+			ORDER BY COALESCE(resume_at, scheduled_at), id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id`
+
+	rows, err := r.db.QueryContext(ctx, query, now, staleBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due campaigns: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan claimed campaign: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating claimed campaigns: %w", err)
+	}
+
+	return ids, nil
+}
+
 // UpdateStatus updates only the status of a campaign
 func (r *campaignRepository) UpdateStatus(ctx context.Context, id int64, status string) error {
 	query := `
 		UPDATE campaigns
 		SET status = $1
-		WHERE id = $2`
+		WHERE id = $2 AND ($3::BIGINT = 0 OR account_id = $3)`
 
-	result, err := r.db.ExecContext(ctx, query, status, id)
+	result, err := r.db.ExecContext(ctx, query, status, id, accountScope(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to update campaign status: %w", err)
 	}
@@ -255,11 +811,372 @@ func (r *campaignRepository) UpdateStatus(ctx context.Context, id int64, status
 	return nil
 }
 
+// TransitionStatus changes a campaign's status only if it is still the
+// expected one, in a single statement
+func (r *campaignRepository) TransitionStatus(ctx context.Context, id int64, from, to string) (bool, error) {
+	query := `
+		UPDATE campaigns
+		SET status = $3
+		WHERE id = $1 AND status = $2 AND ($4::BIGINT = 0 OR account_id = $4)`
+
+	result, err := r.db.ExecContext(ctx, query, id, from, to, accountScope(ctx))
+	if err != nil {
+		return false, fmt.Errorf("failed to update campaign status from %s to %s: %w", from, to, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// SetBuilding sets the campaign's building flag
+func (r *campaignRepository) SetBuilding(ctx context.Context, id int64, building bool) error {
+	query := `
+		UPDATE campaigns
+		SET building = $2
+		WHERE id = $1 AND ($3::BIGINT = 0 OR account_id = $3)`
+
+	if _, err := r.db.ExecContext(ctx, query, id, building, accountScope(ctx)); err != nil {
+		return fmt.Errorf("failed to set campaign building: %w", err)
+	}
+
+	return nil
+}
+
+// CompleteSend sets the final status of a campaign whose last message has an
+// outcome, unless a send is still building it or it was cancelled meanwhile
+func (r *campaignRepository) CompleteSend(ctx context.Context, id int64, status string) (bool, error) {
+	query := `
+		UPDATE campaigns
+		SET status = $2
+		WHERE id = $1 AND status IN ('sending', 'paused') AND NOT building
+			AND ($3::BIGINT = 0 OR account_id = $3)`
+
+	result, err := r.db.ExecContext(ctx, query, id, status, accountScope(ctx))
+	if err != nil {
+		return false, fmt.Errorf("failed to complete campaign: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// PauseSendingByChannel pauses every sending campaign on a channel and returns their IDs.
+// Campaigns that are already paused, and test campaigns, which go to the
+// provider's sandbox, are left untouched.
+func (r *campaignRepository) PauseSendingByChannel(ctx context.Context, channel, reason string) ([]int64, error) {
+	query := `
+		UPDATE campaigns
+		SET status = 'paused', paused_reason = $2, paused_at = CURRENT_TIMESTAMP
+		WHERE channel = $1 AND status = 'sending' AND environment = 'live'
+		RETURNING id`
+
+	rows, err := r.db.QueryContext(ctx, query, channel, reason)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pause campaigns: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan paused campaign ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating paused campaigns: %w", err)
+	}
+
+	return ids, nil
+}
+
+// PauseSending pauses a sending campaign with a reason
+func (r *campaignRepository) PauseSending(ctx context.Context, id int64, reason string) (bool, error) {
+	query := `
+		UPDATE campaigns
+		SET status = 'paused', paused_reason = $2, paused_at = COALESCE(paused_at, CURRENT_TIMESTAMP), resume_at = NULL
+		WHERE id = $1 AND (status = 'sending' OR (status = 'paused' AND resume_at IS NOT NULL))
+			AND ($3::BIGINT = 0 OR account_id = $3)`
+
+	result, err := r.db.ExecContext(ctx, query, id, reason, accountScope(ctx))
+	if err != nil {
+		return false, fmt.Errorf("failed to pause campaign: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// PauseUntil pauses a sending campaign with a resume time. A campaign another
+// worker paused until a window opens is matched too, keeping its pause time.
+// The dispatch claim is cleared so the campaign can be claimed at resumeAt.
+func (r *campaignRepository) PauseUntil(ctx context.Context, id int64, reason string, resumeAt time.Time) (bool, error) {
+	query := `
+		UPDATE campaigns
+		SET status = 'paused', paused_reason = $2, paused_at = COALESCE(paused_at, CURRENT_TIMESTAMP), resume_at = $3,
+			dispatch_claimed_at = NULL
+		WHERE id = $1 AND (status = 'sending' OR (status = 'paused' AND resume_at IS NOT NULL))
+			AND ($4::BIGINT = 0 OR account_id = $4)`
+
+	result, err := r.db.ExecContext(ctx, query, id, reason, resumeAt.UTC(), accountScope(ctx))
+	if err != nil {
+		return false, fmt.Errorf("failed to pause campaign until %s: %w", resumeAt.UTC().Format(time.RFC3339), err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// Resume moves a paused campaign back to sending and clears the pause reason
+func (r *campaignRepository) Resume(ctx context.Context, id int64) error {
+	query := `
+		UPDATE campaigns
+		SET status = 'sending', paused_reason = NULL, paused_at = NULL, resume_at = NULL
+		WHERE id = $1 AND status = 'paused' AND ($2::BIGINT = 0 OR account_id = $2)`
+
+	result, err := r.db.ExecContext(ctx, query, id, accountScope(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to resume campaign: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrConflictWithMsg(fmt.Sprintf("campaign with ID %d is not paused", id))
+	}
+
+	return nil
+}
+
+// Cancel moves a scheduled, ready, awaiting_approval, sending or paused
+// campaign to cancelled
+func (r *campaignRepository) Cancel(ctx context.Context, id int64) (bool, error) {
+	query := `
+		UPDATE campaigns
+		SET status = 'cancelled'
+		WHERE id = $1 AND status IN ('scheduled', 'ready', 'awaiting_approval', 'sending', 'paused') AND ($2::BIGINT = 0 OR account_id = $2)`
+
+	result, err := r.db.ExecContext(ctx, query, id, accountScope(ctx))
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel campaign: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// SetMaxCost updates the cost cap of a campaign
+func (r *campaignRepository) SetMaxCost(ctx context.Context, id int64, maxCost *float64) error {
+	query := `UPDATE campaigns SET max_cost = $2 WHERE id = $1 AND ($3::BIGINT = 0 OR account_id = $3)`
+
+	result, err := r.db.ExecContext(ctx, query, id, maxCost, accountScope(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to set campaign cost cap: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrNotFoundWithMsg(fmt.Sprintf("campaign with ID %d not found", id))
+	}
+
+	return nil
+}
+
+// SetMaxInFlight updates the concurrency limit of a campaign
+func (r *campaignRepository) SetMaxInFlight(ctx context.Context, id int64, maxInFlight *int) error {
+	query := `UPDATE campaigns SET max_in_flight = $2 WHERE id = $1 AND ($3::BIGINT = 0 OR account_id = $3)`
+
+	result, err := r.db.ExecContext(ctx, query, id, maxInFlight, accountScope(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to set campaign concurrency limit: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrNotFoundWithMsg(fmt.Sprintf("campaign with ID %d not found", id))
+	}
+
+	return nil
+}
+
+// SetValidateNumbers updates the validate_numbers flag of a campaign
+func (r *campaignRepository) SetValidateNumbers(ctx context.Context, id int64, validate bool) error {
+	query := `UPDATE campaigns SET validate_numbers = $2 WHERE id = $1 AND ($3::BIGINT = 0 OR account_id = $3)`
+
+	result, err := r.db.ExecContext(ctx, query, id, validate, accountScope(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to set campaign number validation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrNotFoundWithMsg(fmt.Sprintf("campaign with ID %d not found", id))
+	}
+
+	return nil
+}
+
+// SetUTM replaces the UTM parameters of a campaign; nil removes them
+func (r *campaignRepository) SetUTM(ctx context.Context, id int64, utm *models.CampaignUTM) error {
+	query := `UPDATE campaigns SET utm = $2 WHERE id = $1 AND ($3::BIGINT = 0 OR account_id = $3)`
+
+	result, err := r.db.ExecContext(ctx, query, id, utm, accountScope(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to set campaign utm parameters: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrNotFoundWithMsg(fmt.Sprintf("campaign with ID %d not found", id))
+	}
+
+	return nil
+}
+
+// SetShortDomain changes the short domain of a campaign; nil clears it
+func (r *campaignRepository) SetShortDomain(ctx context.Context, id int64, shortDomainID *int64) error {
+	query := `UPDATE campaigns SET short_domain_id = $2 WHERE id = $1 AND ($3::BIGINT = 0 OR account_id = $3)`
+
+	result, err := r.db.ExecContext(ctx, query, id, shortDomainID, accountScope(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to set campaign short domain: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrNotFoundWithMsg(fmt.Sprintf("campaign with ID %d not found", id))
+	}
+
+	return nil
+}
+
+// ReserveCost adds to the accrued cost in one statement, so concurrent workers
+// cannot together overshoot the cap. The row is locked only for the increment.
+func (r *campaignRepository) ReserveCost(ctx context.Context, id int64, amount float64) (bool, error) {
+	query := `
+		INSERT INTO campaign_costs AS c (campaign_id, accrued)
+		SELECT id, $2
+		FROM campaigns
+		WHERE id = $1 AND (max_cost IS NULL OR $2 <= max_cost)
+		ON CONFLICT (campaign_id) DO UPDATE
+		SET accrued = c.accrued + EXCLUDED.accrued, updated_at = CURRENT_TIMESTAMP
+		WHERE NOT EXISTS (
+			SELECT 1 FROM campaigns
+			WHERE id = c.campaign_id AND max_cost < c.accrued + EXCLUDED.accrued
+		)
+		RETURNING c.accrued`
+
+	var accrued float64
+	err := r.db.QueryRowContext(ctx, query, id, amount).Scan(&accrued)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve campaign cost: %w", err)
+	}
+
+	return true, nil
+}
+
+// ReleaseCost subtracts a reservation from the accrued cost
+func (r *campaignRepository) ReleaseCost(ctx context.Context, id int64, amount float64) error {
+	query := `
+		UPDATE campaign_costs
+		SET accrued = GREATEST(accrued - $2, 0), updated_at = CURRENT_TIMESTAMP
+		WHERE campaign_id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, id, amount); err != nil {
+		return fmt.Errorf("failed to release campaign cost: %w", err)
+	}
+
+	return nil
+}
+
 // Delete removes a campaign
 func (r *campaignRepository) Delete(ctx context.Context, id int64) error {
-	query := `DELETE FROM campaigns WHERE id = $1`
+	query := `DELETE FROM campaigns WHERE id = $1 AND ($2::BIGINT = 0 OR account_id = $2)`
+
+	result, err := r.db.ExecContext(ctx, query, id, accountScope(ctx))
+	if isForeignKeyViolation(err) {
+		return models.ErrConflictWithMsg(fmt.Sprintf("campaign with ID %d has message history", id))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete campaign: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	if rowsAffected == 0 {
+		return models.ErrNotFoundWithMsg(fmt.Sprintf("campaign with ID %d not found", id))
+	}
+
+	return nil
+}
+
+// DeleteWithMessages removes a campaign together with its outbound messages in a single transaction
+func (r *campaignRepository) DeleteWithMessages(ctx context.Context, id int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback() // Rollback is safe to call even after Commit
+	}()
+
+	scope := accountScope(ctx)
+	if _, err := tx.ExecContext(ctx, `DELETE FROM outbound_messages WHERE campaign_id = $1 AND ($2::BIGINT = 0 OR account_id = $2)`, id, scope); err != nil {
+		return fmt.Errorf("failed to delete campaign messages: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM campaigns WHERE id = $1 AND ($2::BIGINT = 0 OR account_id = $2)`, id, scope)
 	if err != nil {
 		return fmt.Errorf("failed to delete campaign: %w", err)
 	}
@@ -273,5 +1190,37 @@ func (r *campaignRepository) Delete(ctx context.Context, id int64) error {
 		return models.ErrNotFoundWithMsg(fmt.Sprintf("campaign with ID %d not found", id))
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return nil
 }
+
+// isForeignKeyViolation reports whether err is a PostgreSQL foreign key violation (SQLSTATE 23503)
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
+
+// isUniqueViolation reports whether err is a PostgreSQL unique violation (SQLSTATE 23505)
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// isConstraintViolation reports whether err is a unique violation of the named index
+func isConstraintViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == constraint
+}
+
+// utcTime converts t to UTC. Timestamp columns carry no zone, so a time with
+// any other offset would be stored as the wrong instant.
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
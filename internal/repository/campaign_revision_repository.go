@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// CampaignRevisionRepository defines the interface for campaign draft revision data access
+type CampaignRevisionRepository interface {
+	// Save stores revision as the campaign's next revision. When campaign is not
+	// nil its editable fields and status are updated in the same transaction.
+	// Only draft and scheduled campaigns can be saved.
+	Save(ctx context.Context, revision *models.CampaignRevision, campaign *models.Campaign) error
+	Latest(ctx context.Context, campaignID int64) (*models.CampaignRevision, error)
+	Get(ctx context.Context, campaignID int64, revision int) (*models.CampaignRevision, error)
+	List(ctx context.Context, campaignID int64, limit int) ([]*models.CampaignRevision, error)
+}
+
+// campaignRevisionRepository implements CampaignRevisionRepository using PostgreSQL
+type campaignRevisionRepository struct {
+	db *sql.DB
+}
+
+// NewCampaignRevisionRepository creates a new campaign revision repository
+func NewCampaignRevisionRepository(db *sql.DB) CampaignRevisionRepository {
+	return &campaignRevisionRepository{db: db}
+}
+
+// Save stores a revision, applying it to the campaign when requested.
+// The campaign row is locked for the transaction, which serializes revision
+// numbering and guards against the campaign being sent concurrently.
+func (r *campaignRevisionRepository) Save(ctx context.Context, revision *models.CampaignRevision, campaign *models.Campaign) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback() // Rollback is safe to call even after Commit
+	}()
+
+	var status string
+	lockQuery := `SELECT status FROM campaigns WHERE id = $1 AND ($2::BIGINT = 0 OR account_id = $2) FOR UPDATE`
+	err = tx.QueryRowContext(ctx, lockQuery, revision.CampaignID, accountScope(ctx)).Scan(&status)
+	if err == sql.ErrNoRows {
+		return models.ErrNotFoundWithMsg(fmt.Sprintf("campaign with ID %d not found", revision.CampaignID))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock campaign: %w", err)
+	}
+	if status != models.CampaignStatusDraft && status != models.CampaignStatusScheduled {
+		return models.ErrConflictWithMsg(fmt.Sprintf("campaign is no longer editable (status: %s)", status))
+	}
+
+	if campaign != nil {
+		query := `
+			UPDATE campaigns
+			SET name = $1, status = $2, base_template = $3, sender_id = $4, delivery_windows = $5, scheduled_at = $6
+			WHERE id = $7`
+
+		_, err := tx.ExecContext(
+			ctx,
+			query,
+			campaign.Name,
+			campaign.Status,
+			campaign.BaseTemplate,
+			campaign.SenderID,
+			campaign.DeliveryWindows,
+			campaign.ScheduledAt,
+			campaign.ID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to apply draft to campaign: %w", err)
+		}
+	}
+
+	query := `
+		INSERT INTO campaign_revisions (campaign_id, revision, source, reverted_from, draft, applied, validation_error, account_id)
+		SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4, $5, $6, (SELECT account_id FROM campaigns WHERE id = $1)
+		FROM campaign_revisions
+		WHERE campaign_id = $1
+		RETURNING id, revision, created_at`
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		revision.CampaignID,
+		revision.Source,
+		revision.RevertedFrom,
+		revision.Draft,
+		revision.Applied,
+		revision.ValidationError,
+	).Scan(&revision.ID, &revision.Revision, &revision.CreatedAt)
+	if isUniqueViolation(err) {
+		return models.ErrConflictWithMsg("campaign draft was saved concurrently, retry")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create campaign revision: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// revisionColumns is the column list scanned by scanRevision
+const revisionColumns = `id, campaign_id, revision, source, reverted_from, draft, applied, validation_error, created_at`
+
+// scanRevision scans a row selected with revisionColumns
+func scanRevision(row interface{ Scan(dest ...any) error }) (*models.CampaignRevision, error) {
+	revision := &models.CampaignRevision{}
+	err := row.Scan(
+		&revision.ID,
+		&revision.CampaignID,
+		&revision.Revision,
+		&revision.Source,
+		&revision.RevertedFrom,
+		&revision.Draft,
+		&revision.Applied,
+		&revision.ValidationError,
+		&revision.CreatedAt,
+	)
+	return revision, err
+}
+
+// Latest retrieves the most recent revision of a campaign
+func (r *campaignRevisionRepository) Latest(ctx context.Context, campaignID int64) (*models.CampaignRevision, error) {
+	query := `SELECT ` + revisionColumns + `
+		FROM campaign_revisions
+		WHERE campaign_id = $1 AND ($2::BIGINT = 0 OR account_id = $2)
+		ORDER BY revision DESC
+		LIMIT 1`
+
+	revision, err := scanRevision(r.db.QueryRowContext(ctx, query, campaignID, accountScope(ctx)))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFoundWithMsg(fmt.Sprintf("campaign %d has no revisions", campaignID))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest campaign revision: %w", err)
+	}
+
+	return revision, nil
+}
+
+// Get retrieves a revision of a campaign by its number
+func (r *campaignRevisionRepository) Get(ctx context.Context, campaignID int64, number int) (*models.CampaignRevision, error) {
+	query := `SELECT ` + revisionColumns + `
+		FROM campaign_revisions
+		WHERE campaign_id = $1 AND revision = $2 AND ($3::BIGINT = 0 OR account_id = $3)`
+
+	revision, err := scanRevision(r.db.QueryRowContext(ctx, query, campaignID, number, accountScope(ctx)))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFoundWithMsg(fmt.Sprintf("revision %d of campaign %d not found", number, campaignID))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign revision: %w", err)
+	}
+
+	return revision, nil
+}
+
+// List retrieves the most recent revisions of a campaign, newest first
+func (r *campaignRevisionRepository) List(ctx context.Context, campaignID int64, limit int) ([]*models.CampaignRevision, error) {
+	query := `SELECT ` + revisionColumns + `
+		FROM campaign_revisions
+		WHERE campaign_id = $1 AND ($3::BIGINT = 0 OR account_id = $3)
+		ORDER BY revision DESC
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, campaignID, limit, accountScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list campaign revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := make([]*models.CampaignRevision, 0)
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan campaign revision: %w", err)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating campaign revisions: %w", err)
+	}
+
+	return revisions, nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// ChangeLogRepository defines the interface for change log data access. Records
+// are written by database triggers, not through this repository.
+type ChangeLogRepository interface {
+	// ListAfter returns up to limit records with an ID greater than afterID, in
+	// ID order, optionally for one entity. Records younger than settle are left
+	// for a later poll so changes of transactions still in flight are not skipped.
+	ListAfter(ctx context.Context, afterID int64, entity string, settle time.Duration, limit int) ([]*models.ChangeRecord, error)
+	// DeleteBefore removes up to limit records changed before the given time
+	DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+// changeLogRepository implements ChangeLogRepository using PostgreSQL
+type changeLogRepository struct {
+	db *sql.DB
+}
+
+// NewChangeLogRepository creates a new change log repository
+func NewChangeLogRepository(db *sql.DB) ChangeLogRepository {
+	return &changeLogRepository{db: db}
+}
+
+// ListAfter retrieves change records after the given ID
+func (r *changeLogRepository) ListAfter(ctx context.Context, afterID int64, entity string, settle time.Duration, limit int) ([]*models.ChangeRecord, error) {
+	query := `
+		SELECT id, entity, entity_id, operation, data, changed_at
+		FROM change_log
+		WHERE id > $1
+			AND ($2::TEXT = '' OR entity = $2)
+			AND changed_at < LOCALTIMESTAMP - make_interval(secs => $3)
+			AND ($5::BIGINT = 0 OR account_id = $5)
+		ORDER BY id
+		LIMIT $4`
+
+	rows, err := r.db.QueryContext(ctx, query, afterID, entity, settle.Seconds(), limit, accountScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changes: %w", err)
+	}
+	defer rows.Close()
+
+	records := []*models.ChangeRecord{}
+	for rows.Next() {
+		record := &models.ChangeRecord{}
+		var data []byte
+		if err := rows.Scan(&record.ID, &record.Entity, &record.EntityID, &record.Operation, &data, &record.ChangedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan change: %w", err)
+		}
+		if len(data) > 0 {
+			record.Data = data
+		}
+		records = append(records, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating changes: %w", err)
+	}
+
+	return records, nil
+}
+
+// DeleteBefore prunes the oldest change records in one batch
+func (r *changeLogRepository) DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	query := `
+		DELETE FROM change_log
+		WHERE id IN (
+			SELECT id
+			FROM change_log
+			WHERE changed_at < $1
+			ORDER BY id
+			LIMIT $2
+		)`
+
+	result, err := r.db.ExecContext(ctx, query, before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune change log: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// CustomerEventRepository defines the interface for customer event and timeline data access
+type CustomerEventRepository interface {
+	Record(ctx context.Context, event *models.CustomerEvent) error
+	// Timeline returns up to limit timeline entries that occurred before the
+	// given time, newest first
+	Timeline(ctx context.Context, customerID int64, before time.Time, limit int) ([]*models.TimelineEntry, error)
+	// LastSentCampaignID returns the campaign of the last message sent to the
+	// customer, or nil when none was
+	LastSentCampaignID(ctx context.Context, customerID int64) (*int64, error)
+	// OptOutReport counts opt-outs, per attributed campaign, and opt-ins in
+	// [from, to), along with each campaign's messages created in that period
+	// and sent. The period and rates are left for the caller.
+	OptOutReport(ctx context.Context, from, to time.Time) (*models.OptOutReport, error)
+}
+
+// customerEventRepository implements CustomerEventRepository using PostgreSQL
+type customerEventRepository struct {
+	db *sql.DB
+}
+
+// NewCustomerEventRepository creates a new customer event repository
+func NewCustomerEventRepository(db *sql.DB) CustomerEventRepository {
+	return &customerEventRepository{db: db}
+}
+
+// Record inserts a customer event into the customer's account
+func (r *customerEventRepository) Record(ctx context.Context, event *models.CustomerEvent) error {
+	query := `
+		INSERT INTO customer_events (customer_id, type, campaign_id, details, account_id)
+		VALUES ($1, $2, $3, $4, (SELECT account_id FROM customers WHERE id = $1))
+		RETURNING id, created_at`
+
+	var details interface{}
+	if len(event.Details) > 0 {
+		details = string(event.Details)
+	}
+
+	err := r.db.QueryRowContext(ctx, query, event.CustomerID, event.Type, event.CampaignID, details).Scan(&event.ID, &event.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record customer event: %w", err)
+	}
+
+	return nil
+}
+
+// Timeline merges customer events with the customer's messages. Each message
+// contributes a queued entry and, once it is final, a sent or failed entry at
+// its last update. Both sources are read through their (customer_id, created_at)
+// indexes.
+func (r *customerEventRepository) Timeline(ctx context.Context, customerID int64, before time.Time, limit int) ([]*models.TimelineEntry, error) {
+	query := `
+		SELECT occurred_at, type, campaign_id, message_id, details
+		FROM (
+			SELECT created_at AS occurred_at, type, campaign_id, NULL::BIGINT AS message_id, details
+			FROM customer_events
+			WHERE customer_id = $1 AND ($4::BIGINT = 0 OR account_id = $4)
+			UNION ALL
+			SELECT created_at, 'message_queued', campaign_id, id, NULL::JSONB
+			FROM outbound_messages
+			WHERE customer_id = $1 AND ($4::BIGINT = 0 OR account_id = $4)
+			UNION ALL
+			SELECT updated_at, 'message_' || status, campaign_id, id,
+				jsonb_strip_nulls(jsonb_build_object('error', last_error, 'retry_count', retry_count))
+			FROM outbound_messages
+			WHERE customer_id = $1 AND status IN ('sent', 'delivered', 'undelivered', 'failed')
+				AND ($4::BIGINT = 0 OR account_id = $4)
+		) timeline
+		WHERE occurred_at < $2
+		ORDER BY occurred_at DESC, message_id DESC NULLS LAST
+		LIMIT $3`
+
+	rows, err := r.db.QueryContext(ctx, query, customerID, before, limit, accountScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read customer timeline: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]*models.TimelineEntry, 0)
+	for rows.Next() {
+		entry := &models.TimelineEntry{}
+		var details []byte
+		if err := rows.Scan(&entry.OccurredAt, &entry.Type, &entry.CampaignID, &entry.MessageID, &details); err != nil {
+			return nil, fmt.Errorf("failed to scan timeline entry: %w", err)
+		}
+		if len(details) > 0 {
+			entry.Details = details
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating customer timeline: %w", err)
+	}
+
+	return entries, nil
+}
+
+// LastSentCampaignID reads the customer's newest sent message through the
+// (customer_id, created_at) index
+func (r *customerEventRepository) LastSentCampaignID(ctx context.Context, customerID int64) (*int64, error) {
+	query := `
+		SELECT campaign_id
+		FROM outbound_messages
+		WHERE customer_id = $1 AND status IN ('sent', 'delivered', 'undelivered')
+			AND ($2::BIGINT = 0 OR account_id = $2)
+		ORDER BY created_at DESC
+		LIMIT 1`
+
+	var campaignID int64
+	err := r.db.QueryRowContext(ctx, query, customerID, accountScope(ctx)).Scan(&campaignID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find last campaign sent: %w", err)
+	}
+
+	return &campaignID, nil
+}
+
+// OptOutReport reads the period's opt-in and opt-out events through the
+// (type, created_at) index and its sent messages through (status, created_at)
+func (r *customerEventRepository) OptOutReport(ctx context.Context, from, to time.Time) (*models.OptOutReport, error) {
+	report := &models.OptOutReport{Campaigns: make([]*models.CampaignOptOuts, 0)}
+
+	totalsQuery := `
+		SELECT
+			COUNT(*) FILTER (WHERE type = 'opted_out'),
+			COUNT(*) FILTER (WHERE type = 'opted_out' AND campaign_id IS NULL),
+			COUNT(DISTINCT customer_id) FILTER (WHERE type = 'opted_in')
+		FROM customer_events
+		WHERE type IN ('opted_out', 'opted_in') AND created_at >= $1 AND created_at < $2
+			AND ($3::BIGINT = 0 OR account_id = $3)`
+
+	scope := accountScope(ctx)
+	err := r.db.QueryRowContext(ctx, totalsQuery, from, to, scope).Scan(&report.OptOuts, &report.UnattributedOptOuts, &report.Resubscribed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count opt-outs: %w", err)
+	}
+
+	campaignsQuery := `
+		WITH sent AS (
+			SELECT campaign_id, COUNT(*) AS messages_sent
+			FROM outbound_messages
+			WHERE status IN ('sent', 'delivered', 'undelivered') AND created_at >= $1 AND created_at < $2
+				AND ($3::BIGINT = 0 OR account_id = $3)
+			GROUP BY campaign_id
+		), opt_outs AS (
+			SELECT campaign_id, COUNT(*) AS opt_outs
+			FROM customer_events
+			WHERE type = 'opted_out' AND campaign_id IS NOT NULL AND created_at >= $1 AND created_at < $2
+				AND ($3::BIGINT = 0 OR account_id = $3)
+			GROUP BY campaign_id
+		)
+		SELECT c.id, c.name, COALESCE(sent.messages_sent, 0), COALESCE(opt_outs.opt_outs, 0)
+		FROM sent
+		FULL JOIN opt_outs ON opt_outs.campaign_id = sent.campaign_id
+		JOIN campaigns c ON c.id = COALESCE(sent.campaign_id, opt_outs.campaign_id)
+		ORDER BY c.id`
+
+	rows, err := r.db.QueryContext(ctx, campaignsQuery, from, to, scope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count opt-outs per campaign: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		campaign := &models.CampaignOptOuts{}
+		if err := rows.Scan(&campaign.CampaignID, &campaign.Name, &campaign.MessagesSent, &campaign.OptOuts); err != nil {
+			return nil, fmt.Errorf("failed to scan campaign opt-outs: %w", err)
+		}
+		report.Campaigns = append(report.Campaigns, campaign)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating campaign opt-outs: %w", err)
+	}
+
+	return report, nil
+}
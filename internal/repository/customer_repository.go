@@ -4,6 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
 
 	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
 )
@@ -12,10 +17,37 @@ import (
 type CustomerRepository interface {
 	Create(ctx context.Context, customer *models.Customer) error
 	GetByID(ctx context.Context, id int64) (*models.Customer, error)
+	// GetByPhone retrieves the customer with a phone. Outside an account,
+	// where several accounts may have one, it returns the customer messaged
+	// most recently, whose account a reply to that phone is answering.
 	GetByPhone(ctx context.Context, phone string) (*models.Customer, error)
+	GetByExternalID(ctx context.Context, externalID string) (*models.Customer, error)
+	GetByIDs(ctx context.Context, ids []int64) ([]*models.Customer, error)
+	GetByPhones(ctx context.Context, phones []string) ([]*models.Customer, error)
+	ListAfterID(ctx context.Context, afterID int64, limit int) ([]*models.Customer, error)
+	Count(ctx context.Context) (int64, error)
+	CountByIDs(ctx context.Context, ids []int64) (int64, error)
+	// ListMatchingAfterID is ListAfterID restricted to customers matching filter
+	ListMatchingAfterID(ctx context.Context, filter models.SegmentFilter, afterID int64, limit int) ([]*models.Customer, error)
+	CountMatching(ctx context.Context, filter models.SegmentFilter) (int64, error)
+	// MatchingIDs returns those of ids whose customers match filter
+	MatchingIDs(ctx context.Context, filter models.SegmentFilter, ids []int64) ([]int64, error)
+	// ListMatching retrieves a page of the customers matching filter, in ID
+	// order
+	ListMatching(ctx context.Context, filter models.SegmentFilter, page, pageSize int) ([]*models.Customer, error)
+	// SampleMatching returns up to limit customers matching filter, picked at
+	// random from those campaigns would message
+	SampleMatching(ctx context.Context, filter models.SegmentFilter, limit int) ([]*models.Customer, error)
 	List(ctx context.Context, filter models.CustomerFilter) ([]*models.Customer, int64, error)
 	Update(ctx context.Context, customer *models.Customer) error
 	Delete(ctx context.Context, id int64) error
+	Anonymize(ctx context.Context, id int64) error
+	// SetOptedOut opts the customer out of, or back into, campaign messages.
+	// It returns false when the customer already was.
+	SetOptedOut(ctx context.Context, id int64, optedOut bool) (bool, error)
+	// SetSnoozedUntil keeps the customer out of campaigns until a time or,
+	// with nil, lets them back in
+	SetSnoozedUntil(ctx context.Context, id int64, until *time.Time) error
 }
 
 // customerRepository implements CustomerRepository using PostgreSQL
@@ -28,23 +60,29 @@ func NewCustomerRepository(db *sql.DB) CustomerRepository {
 	return &customerRepository{db: db}
 }
 
-// Create inserts a new customer
+// Create inserts a new customer into the account of ctx
 func (r *customerRepository) Create(ctx context.Context, customer *models.Customer) error {
 	query := `
-		INSERT INTO customers (phone, first_name, last_name, location, preferred_product)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO customers (account_id, phone, first_name, last_name, location, preferred_product, external_id, attributes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING id`
 
 	err := r.db.QueryRowContext(
 		ctx,
 		query,
+		ownerAccount(ctx),
 		customer.Phone,
 		customer.FirstName,
 		customer.LastName,
 		customer.Location,
 		customer.PreferredProduct,
+		customer.ExternalID,
+		customer.Attributes,
 	).Scan(&customer.ID)
 
+	if isUniqueViolation(err) {
+		return models.ErrConflictWithMsg(fmt.Sprintf("customer with external ID %s already exists", *customer.ExternalID))
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create customer: %w", err)
 	}
@@ -55,18 +93,23 @@ func (r *customerRepository) Create(ctx context.Context, customer *models.Custom
 // GetByID retrieves a customer by ID
 func (r *customerRepository) GetByID(ctx context.Context, id int64) (*models.Customer, error) {
 	query := `
-		SELECT id, phone, first_name, last_name, location, preferred_product
+		SELECT id, phone, first_name, last_name, location, preferred_product, external_id, opted_out_at, attributes, snoozed_until, bounced_at
 		FROM customers
-		WHERE id = $1`
+		WHERE id = $1 AND ($2::BIGINT = 0 OR account_id = $2)`
 
 	customer := &models.Customer{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := r.db.QueryRowContext(ctx, query, id, accountScope(ctx)).Scan(
 		&customer.ID,
 		&customer.Phone,
 		&customer.FirstName,
 		&customer.LastName,
 		&customer.Location,
 		&customer.PreferredProduct,
+		&customer.ExternalID,
+		&customer.OptedOutAt,
+		&customer.Attributes,
+		&customer.SnoozedUntil,
+		&customer.BouncedAt,
 	)
 
 	if err == sql.ErrNoRows {
@@ -82,18 +125,28 @@ func (r *customerRepository) GetByID(ctx context.Context, id int64) (*models.Cus
 // GetByPhone retrieves a customer by phone number
 func (r *customerRepository) GetByPhone(ctx context.Context, phone string) (*models.Customer, error) {
 	query := `
-		SELECT id, phone, first_name, last_name, location, preferred_product
-		FROM customers
-		WHERE phone = $1`
+		SELECT id, phone, first_name, last_name, location, preferred_product, external_id, opted_out_at, attributes, snoozed_until, bounced_at
+		FROM customers c
+		WHERE phone = $1 AND ($2::BIGINT = 0 OR account_id = $2)
+		ORDER BY (
+			SELECT MAX(created_at) FROM outbound_messages
+			WHERE customer_id = c.id AND status IN ('sent', 'delivered', 'undelivered')
+		) DESC NULLS LAST, id ASC
+		LIMIT 1`
 
 	customer := &models.Customer{}
-	err := r.db.QueryRowContext(ctx, query, phone).Scan(
+	err := r.db.QueryRowContext(ctx, query, phone, accountScope(ctx)).Scan(
 		&customer.ID,
 		&customer.Phone,
 		&customer.FirstName,
 		&customer.LastName,
 		&customer.Location,
 		&customer.PreferredProduct,
+		&customer.ExternalID,
+		&customer.OptedOutAt,
+		&customer.Attributes,
+		&customer.SnoozedUntil,
+		&customer.BouncedAt,
 	)
 
 	if err == sql.ErrNoRows {
@@ -106,6 +159,339 @@ func (r *customerRepository) GetByPhone(ctx context.Context, phone string) (*mod
 	return customer, nil
 }
 
+// GetByExternalID retrieves a customer by the ID an external system knows it by
+func (r *customerRepository) GetByExternalID(ctx context.Context, externalID string) (*models.Customer, error) {
+	query := `
+		SELECT id, phone, first_name, last_name, location, preferred_product, external_id, opted_out_at, attributes, snoozed_until, bounced_at
+		FROM customers
+		WHERE external_id = $1 AND ($2::BIGINT = 0 OR account_id = $2)`
+
+	customer := &models.Customer{}
+	err := r.db.QueryRowContext(ctx, query, externalID, accountScope(ctx)).Scan(
+		&customer.ID,
+		&customer.Phone,
+		&customer.FirstName,
+		&customer.LastName,
+		&customer.Location,
+		&customer.PreferredProduct,
+		&customer.ExternalID,
+		&customer.OptedOutAt,
+		&customer.Attributes,
+		&customer.SnoozedUntil,
+		&customer.BouncedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFoundWithMsg(fmt.Sprintf("customer with external ID %s not found", externalID))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer by external ID: %w", err)
+	}
+
+	return customer, nil
+}
+
+// GetByIDs retrieves the customers with the given IDs ordered by ID.
+// IDs that do not exist are silently omitted from the result.
+func (r *customerRepository) GetByIDs(ctx context.Context, ids []int64) ([]*models.Customer, error) {
+	if len(ids) == 0 {
+		return []*models.Customer{}, nil
+	}
+
+	query := `
+		SELECT id, phone, first_name, last_name, location, preferred_product, external_id, opted_out_at, attributes, snoozed_until, bounced_at
+		FROM customers
+		WHERE id = ANY($1) AND ($2::BIGINT = 0 OR account_id = $2)
+		ORDER BY id ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids), accountScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customers by IDs: %w", err)
+	}
+	defer rows.Close()
+
+	return scanCustomers(rows)
+}
+
+// GetByPhones retrieves customers whose phone is in phones, ordered by ID.
+// Phones without a customer are simply absent from the result.
+func (r *customerRepository) GetByPhones(ctx context.Context, phones []string) ([]*models.Customer, error) {
+	if len(phones) == 0 {
+		return []*models.Customer{}, nil
+	}
+
+	query := `
+		SELECT id, phone, first_name, last_name, location, preferred_product, external_id, opted_out_at, attributes, snoozed_until, bounced_at
+		FROM customers
+		WHERE phone = ANY($1) AND ($2::BIGINT = 0 OR account_id = $2)
+		ORDER BY id ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(phones), accountScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customers by phones: %w", err)
+	}
+	defer rows.Close()
+
+	return scanCustomers(rows)
+}
+
+// ListAfterID retrieves up to limit customers with an ID greater than afterID.
+// Keyset iteration keeps pages stable even while customers are being added.
+func (r *customerRepository) ListAfterID(ctx context.Context, afterID int64, limit int) ([]*models.Customer, error) {
+	query := `
+		SELECT id, phone, first_name, last_name, location, preferred_product, external_id, opted_out_at, attributes, snoozed_until, bounced_at
+		FROM customers
+		WHERE id > $1 AND ($3::BIGINT = 0 OR account_id = $3)
+		ORDER BY id ASC
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, afterID, limit, accountScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list customers after ID: %w", err)
+	}
+	defer rows.Close()
+
+	return scanCustomers(rows)
+}
+
+// Count returns the number of customers
+func (r *customerRepository) Count(ctx context.Context) (int64, error) {
+	query := `SELECT COUNT(*) FROM customers WHERE $1::BIGINT = 0 OR account_id = $1`
+
+	var count int64
+	if err := r.db.QueryRowContext(ctx, query, accountScope(ctx)).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count customers: %w", err)
+	}
+
+	return count, nil
+}
+
+// CountByIDs returns how many of the given IDs belong to existing customers
+func (r *customerRepository) CountByIDs(ctx context.Context, ids []int64) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	query := `SELECT COUNT(*) FROM customers WHERE id = ANY($1) AND ($2::BIGINT = 0 OR account_id = $2)`
+
+	var count int64
+	if err := r.db.QueryRowContext(ctx, query, pq.Array(ids), accountScope(ctx)).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count customers by IDs: %w", err)
+	}
+
+	return count, nil
+}
+
+// ListMatchingAfterID retrieves up to limit customers matching filter with an
+// ID greater than afterID
+func (r *customerRepository) ListMatchingAfterID(ctx context.Context, filter models.SegmentFilter, afterID int64, limit int) ([]*models.Customer, error) {
+	where, args, err := segmentFilterClause(filter, 4)
+	if err != nil {
+		return nil, err
+	}
+	query := `
+		SELECT id, phone, first_name, last_name, location, preferred_product, external_id, opted_out_at, attributes, snoozed_until, bounced_at
+		FROM customers
+		WHERE id > $1 AND ($3::BIGINT = 0 OR account_id = $3)` + where + `
+		ORDER BY id ASC
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, append([]interface{}{afterID, limit, accountScope(ctx)}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list matching customers: %w", err)
+	}
+	defer rows.Close()
+
+	return scanCustomers(rows)
+}
+
+// CountMatching returns the number of customers matching filter
+func (r *customerRepository) CountMatching(ctx context.Context, filter models.SegmentFilter) (int64, error) {
+	where, args, err := segmentFilterClause(filter, 2)
+	if err != nil {
+		return 0, err
+	}
+	query := `SELECT COUNT(*) FROM customers WHERE ($1::BIGINT = 0 OR account_id = $1)` + where
+
+	var count int64
+	if err := r.db.QueryRowContext(ctx, query, append([]interface{}{accountScope(ctx)}, args...)...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count matching customers: %w", err)
+	}
+
+	return count, nil
+}
+
+// MatchingIDs checks a batch of customers against a segment filter
+func (r *customerRepository) MatchingIDs(ctx context.Context, filter models.SegmentFilter, ids []int64) ([]int64, error) {
+	if len(ids) == 0 {
+		return []int64{}, nil
+	}
+
+	where, args, err := segmentFilterClause(filter, 3)
+	if err != nil {
+		return nil, err
+	}
+	query := `SELECT id FROM customers WHERE id = ANY($1) AND ($2::BIGINT = 0 OR account_id = $2)` + where
+
+	rows, err := r.db.QueryContext(ctx, query, append([]interface{}{pq.Array(ids), accountScope(ctx)}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to match customers: %w", err)
+	}
+	defer rows.Close()
+
+	matching := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan customer ID: %w", err)
+		}
+		matching = append(matching, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating matching customers: %w", err)
+	}
+
+	return matching, nil
+}
+
+// ListMatching retrieves a page of the customers matching filter
+func (r *customerRepository) ListMatching(ctx context.Context, filter models.SegmentFilter, page, pageSize int) ([]*models.Customer, error) {
+	models.ValidateAndSetDefaults(&page, &pageSize)
+
+	where, args, err := segmentFilterClause(filter, 4)
+	if err != nil {
+		return nil, err
+	}
+	query := `
+		SELECT id, phone, first_name, last_name, location, preferred_product, external_id, opted_out_at, attributes, snoozed_until, bounced_at
+		FROM customers
+		WHERE ($3::BIGINT = 0 OR account_id = $3)` + where + `
+		ORDER BY id ASC
+		LIMIT $1 OFFSET $2`
+
+	rows, err := r.db.QueryContext(ctx, query, append([]interface{}{pageSize, models.CalculateOffset(page, pageSize), accountScope(ctx)}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list matching customers: %w", err)
+	}
+	defer rows.Close()
+
+	return scanCustomers(rows)
+}
+
+// SampleMatching retrieves a random sample of the customers matching filter
+// who are neither opted out nor snoozed
+func (r *customerRepository) SampleMatching(ctx context.Context, filter models.SegmentFilter, limit int) ([]*models.Customer, error) {
+	where, args, err := segmentFilterClause(filter, 4)
+	if err != nil {
+		return nil, err
+	}
+	query := `
+		SELECT id, phone, first_name, last_name, location, preferred_product, external_id, opted_out_at, attributes, snoozed_until, bounced_at
+		FROM customers
+		WHERE ($2::BIGINT = 0 OR account_id = $2)
+			AND opted_out_at IS NULL AND bounced_at IS NULL AND (snoozed_until IS NULL OR snoozed_until <= $3)` + where + `
+		ORDER BY random()
+		LIMIT $1`
+
+	rows, err := r.db.QueryContext(ctx, query, append([]interface{}{limit, accountScope(ctx), time.Now().UTC()}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample matching customers: %w", err)
+	}
+	defer rows.Close()
+
+	return scanCustomers(rows)
+}
+
+// segmentFilterClause builds the AND conditions for a segment filter, with
+// placeholders numbered from argPos. Every value, attribute keys included, is
+// passed as an argument.
+func segmentFilterClause(filter models.SegmentFilter, argPos int) (string, []interface{}, error) {
+	clause := ""
+	args := []interface{}{}
+
+	if filter.Location != "" {
+		clause += fmt.Sprintf(" AND location = $%d", argPos)
+		args = append(args, filter.Location)
+		argPos++
+	}
+	if filter.PreferredProduct != "" {
+		clause += fmt.Sprintf(" AND preferred_product = $%d", argPos)
+		args = append(args, filter.PreferredProduct)
+		argPos++
+	}
+	// Prefixes are validated as digits, so they hold no LIKE wildcards
+	if filter.PhonePrefix != "" {
+		clause += fmt.Sprintf(" AND phone LIKE $%d", argPos)
+		args = append(args, filter.PhonePrefix+"%")
+		argPos++
+	}
+
+	for _, condition := range filter.Conditions {
+		// Only fields from the models' fixed lists are used as column names;
+		// anything else is an attribute key
+		expr := condition.Field
+		if condition.IsAttribute() {
+			expr = fmt.Sprintf("attributes->>$%d", argPos)
+			args = append(args, condition.Field)
+			argPos++
+		}
+
+		var value interface{}
+		var err error
+		switch condition.Operator {
+		case models.SegmentOpEquals:
+			clause += fmt.Sprintf(" AND %s = $%d", expr, argPos)
+			value, err = condition.Text()
+		case models.SegmentOpContains:
+			clause += fmt.Sprintf(" AND %s ILIKE $%d", expr, argPos)
+			var text string
+			text, err = condition.Text()
+			value = "%" + likeEscaper.Replace(text) + "%"
+		case models.SegmentOpIn:
+			clause += fmt.Sprintf(" AND %s = ANY($%d)", expr, argPos)
+			var values []string
+			values, err = condition.List()
+			value = pq.Array(values)
+		case models.SegmentOpExists:
+			if slices.Contains(models.SegmentDateFields, condition.Field) {
+				clause += fmt.Sprintf(" AND %s IS NOT NULL", expr)
+			} else {
+				clause += fmt.Sprintf(" AND COALESCE(%s, '') <> ''", expr)
+			}
+			continue
+		case models.SegmentOpGreaterThan:
+			clause += fmt.Sprintf(" AND segment_numeric(%s) > $%d", expr, argPos)
+			value, err = condition.Number()
+		case models.SegmentOpLessThan:
+			clause += fmt.Sprintf(" AND segment_numeric(%s) < $%d", expr, argPos)
+			value, err = condition.Number()
+		case models.SegmentOpBefore, models.SegmentOpAfter:
+			if condition.IsAttribute() {
+				expr = "segment_timestamp(" + expr + ")"
+			}
+			comparison := "<"
+			if condition.Operator == models.SegmentOpAfter {
+				comparison = ">"
+			}
+			clause += fmt.Sprintf(" AND %s %s $%d", expr, comparison, argPos)
+			value, err = condition.Time()
+		default:
+			return "", nil, models.ErrInvalidInput(fmt.Sprintf("invalid condition operator %q", condition.Operator))
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, value)
+		argPos++
+	}
+
+	return clause, args, nil
+}
+
+// likeEscaper escapes LIKE wildcards so a value matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 // List retrieves customers with pagination and filtering
 func (r *customerRepository) List(ctx context.Context, filter models.CustomerFilter) ([]*models.Customer, int64, error) {
 	// Validate and set defaults
@@ -113,12 +499,12 @@ func (r *customerRepository) List(ctx context.Context, filter models.CustomerFil
 
 	// Build query with filters
 	query := `
-		SELECT id, phone, first_name, last_name, location, preferred_product
+		SELECT id, phone, first_name, last_name, location, preferred_product, external_id, opted_out_at, attributes, snoozed_until, bounced_at
 		FROM customers
-		WHERE 1=1`
-	countQuery := `SELECT COUNT(*) FROM customers WHERE 1=1`
-	args := []interface{}{}
-	argPos := 1
+		WHERE ($1::BIGINT = 0 OR account_id = $1)`
+	countQuery := `SELECT COUNT(*) FROM customers WHERE ($1::BIGINT = 0 OR account_id = $1)`
+	args := []interface{}{accountScope(ctx)}
+	argPos := 2
 
 	if filter.Phone != "" {
 		query += fmt.Sprintf(" AND phone LIKE $%d", argPos)
@@ -163,6 +549,11 @@ func (r *customerRepository) List(ctx context.Context, filter models.CustomerFil
 			&customer.LastName,
 			&customer.Location,
 			&customer.PreferredProduct,
+			&customer.ExternalID,
+			&customer.OptedOutAt,
+			&customer.Attributes,
+			&customer.SnoozedUntil,
+			&customer.BouncedAt,
 		)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan customer: %w", err)
@@ -177,12 +568,14 @@ func (r *customerRepository) List(ctx context.Context, filter models.CustomerFil
 	return customers, totalCount, nil
 }
 
-// Update updates an existing customer
+// Update updates an existing customer. A new phone number clears the old
+// number's hard bounce.
 func (r *customerRepository) Update(ctx context.Context, customer *models.Customer) error {
 	query := `
 		UPDATE customers
-		SET phone = $1, first_name = $2, last_name = $3, location = $4, preferred_product = $5
-		WHERE id = $6
+		SET phone = $1, first_name = $2, last_name = $3, location = $4, preferred_product = $5, external_id = $6, attributes = $7,
+			bounced_at = CASE WHEN phone = $1 THEN bounced_at END
+		WHERE id = $8 AND ($9::BIGINT = 0 OR account_id = $9)
 		`
 
 	result, err := r.db.ExecContext(
@@ -193,8 +586,14 @@ func (r *customerRepository) Update(ctx context.Context, customer *models.Custom
 		customer.LastName,
 		customer.Location,
 		customer.PreferredProduct,
+		customer.ExternalID,
+		customer.Attributes,
 		customer.ID,
+		accountScope(ctx),
 	)
+	if isUniqueViolation(err) {
+		return models.ErrConflictWithMsg(fmt.Sprintf("customer with external ID %s already exists", *customer.ExternalID))
+	}
 	if err != nil {
 		return fmt.Errorf("failed to update customer: %w", err)
 	}
@@ -213,9 +612,12 @@ func (r *customerRepository) Update(ctx context.Context, customer *models.Custom
 
 // Delete removes a customer
 func (r *customerRepository) Delete(ctx context.Context, id int64) error {
-	query := `DELETE FROM customers WHERE id = $1`
+	query := `DELETE FROM customers WHERE id = $1 AND ($2::BIGINT = 0 OR account_id = $2)`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := r.db.ExecContext(ctx, query, id, accountScope(ctx))
+	if isForeignKeyViolation(err) {
+		return models.ErrConflictWithMsg(fmt.Sprintf("customer with ID %d has message history", id))
+	}
 	if err != nil {
 		return fmt.Errorf("failed to delete customer: %w", err)
 	}
@@ -231,3 +633,109 @@ func (r *customerRepository) Delete(ctx context.Context, id int64) error {
 
 	return nil
 }
+
+// Anonymize scrubs personal data from a customer while keeping the row so that
+// message history referencing it stays intact
+func (r *customerRepository) Anonymize(ctx context.Context, id int64) error {
+	// Earlier change log records hold the customer's details too
+	query := `
+		WITH scrubbed AS (
+			UPDATE change_log SET data = NULL
+			WHERE entity = 'customer' AND entity_id = $1::BIGINT::text AND ($2::BIGINT = 0 OR account_id = $2)
+		)
+		UPDATE customers
+		SET phone = 'anon-' || id::text, first_name = '', last_name = '', location = '', preferred_product = '', external_id = NULL, attributes = '{}'
+		WHERE id = $1 AND ($2::BIGINT = 0 OR account_id = $2)`
+
+	result, err := r.db.ExecContext(ctx, query, id, accountScope(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to anonymize customer: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrNotFoundWithMsg(fmt.Sprintf("customer with ID %d not found", id))
+	}
+
+	return nil
+}
+
+// SetOptedOut sets or clears opted_out_at. Only a change of state updates the
+// row, so a repeated STOP keeps the time of the first one.
+func (r *customerRepository) SetOptedOut(ctx context.Context, id int64, optedOut bool) (bool, error) {
+	query := `
+		UPDATE customers
+		SET opted_out_at = CASE WHEN $1 THEN CURRENT_TIMESTAMP END
+		WHERE id = $2 AND (opted_out_at IS NULL) = $1 AND ($3::BIGINT = 0 OR account_id = $3)`
+
+	result, err := r.db.ExecContext(ctx, query, optedOut, id, accountScope(ctx))
+	if err != nil {
+		return false, fmt.Errorf("failed to set customer opt-out: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// SetSnoozedUntil sets or clears snoozed_until
+func (r *customerRepository) SetSnoozedUntil(ctx context.Context, id int64, until *time.Time) error {
+	query := `
+		UPDATE customers
+		SET snoozed_until = $1
+		WHERE id = $2 AND ($3::BIGINT = 0 OR account_id = $3)`
+
+	result, err := r.db.ExecContext(ctx, query, until, id, accountScope(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to set customer snooze: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrNotFoundWithMsg(fmt.Sprintf("customer with ID %d not found", id))
+	}
+
+	return nil
+}
+
+// scanCustomers reads all customer rows from a result set
+func scanCustomers(rows *sql.Rows) ([]*models.Customer, error) {
+	customers := []*models.Customer{}
+	for rows.Next() {
+		customer := &models.Customer{}
+		err := rows.Scan(
+			&customer.ID,
+			&customer.Phone,
+			&customer.FirstName,
+			&customer.LastName,
+			&customer.Location,
+			&customer.PreferredProduct,
+			&customer.ExternalID,
+			&customer.OptedOutAt,
+			&customer.Attributes,
+			&customer.SnoozedUntil,
+			&customer.BouncedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan customer: %w", err)
+		}
+		customers = append(customers, customer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating customers: %w", err)
+	}
+
+	return customers, nil
+}
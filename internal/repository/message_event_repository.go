@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// MessageEventRepository defines the interface for message event data access.
+// Queued, delivery and soft bounce events are recorded by the outbound message
+// repository along with the change they record.
+type MessageEventRepository interface {
+	// Record adds an event to a message. It returns a not found error when
+	// there is no such message.
+	Record(ctx context.Context, event *models.MessageEvent) error
+	// ListByMessage returns a message's events, oldest first
+	ListByMessage(ctx context.Context, messageID int64) ([]*models.MessageEvent, error)
+}
+
+// messageEventRepository implements MessageEventRepository using PostgreSQL
+type messageEventRepository struct {
+	db *sql.DB
+}
+
+// NewMessageEventRepository creates a new message event repository
+func NewMessageEventRepository(db *sql.DB) MessageEventRepository {
+	return &messageEventRepository{db: db}
+}
+
+// Record inserts an event into the account of its message
+func (r *messageEventRepository) Record(ctx context.Context, event *models.MessageEvent) error {
+	query := `
+		INSERT INTO message_events (account_id, message_id, type, detail)
+		SELECT account_id, id, $2, $3
+		FROM outbound_messages
+		WHERE id = $1
+		RETURNING id, created_at`
+
+	err := r.db.QueryRowContext(ctx, query, event.MessageID, event.Type, event.Detail).
+		Scan(&event.ID, &event.CreatedAt)
+	if err == sql.ErrNoRows {
+		return models.ErrNotFoundWithMsg(fmt.Sprintf("message with ID %d not found", event.MessageID))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record message event: %w", err)
+	}
+
+	return nil
+}
+
+// ListByMessage retrieves the events of a message in the order they happened
+func (r *messageEventRepository) ListByMessage(ctx context.Context, messageID int64) ([]*models.MessageEvent, error) {
+	query := `
+		SELECT id, message_id, type, detail, created_at
+		FROM message_events
+		WHERE message_id = $1 AND ($2::BIGINT = 0 OR account_id = $2)
+		ORDER BY id ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, messageID, accountScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list message events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*models.MessageEvent{}
+	for rows.Next() {
+		event := &models.MessageEvent{}
+		if err := rows.Scan(&event.ID, &event.MessageID, &event.Type, &event.Detail, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan message event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating message events: %w", err)
+	}
+
+	return events, nil
+}
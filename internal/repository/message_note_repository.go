@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// MessageNoteRepository defines the interface for message note data access
+type MessageNoteRepository interface {
+	// Create adds a note to a message of the account of ctx. It returns a
+	// not found error when there is no such message.
+	Create(ctx context.Context, note *models.MessageNote) error
+	// ListByMessage returns a message's notes, oldest first
+	ListByMessage(ctx context.Context, messageID int64) ([]*models.MessageNote, error)
+}
+
+// messageNoteRepository implements MessageNoteRepository using PostgreSQL
+type messageNoteRepository struct {
+	db *sql.DB
+}
+
+// NewMessageNoteRepository creates a new message note repository
+func NewMessageNoteRepository(db *sql.DB) MessageNoteRepository {
+	return &messageNoteRepository{db: db}
+}
+
+// Create inserts a note into the account of its message
+func (r *messageNoteRepository) Create(ctx context.Context, note *models.MessageNote) error {
+	query := `
+		INSERT INTO message_notes (account_id, message_id, author, body)
+		SELECT account_id, id, $2, $3
+		FROM outbound_messages
+		WHERE id = $1 AND ($4::BIGINT = 0 OR account_id = $4)
+		RETURNING id, created_at`
+
+	err := r.db.QueryRowContext(ctx, query, note.MessageID, note.Author, note.Body, accountScope(ctx)).
+		Scan(&note.ID, &note.CreatedAt)
+	if err == sql.ErrNoRows {
+		return models.ErrNotFoundWithMsg(fmt.Sprintf("message with ID %d not found", note.MessageID))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create message note: %w", err)
+	}
+
+	return nil
+}
+
+// ListByMessage retrieves the notes of a message in the order they were written
+func (r *messageNoteRepository) ListByMessage(ctx context.Context, messageID int64) ([]*models.MessageNote, error) {
+	query := `
+		SELECT id, message_id, author, body, created_at
+		FROM message_notes
+		WHERE message_id = $1 AND ($2::BIGINT = 0 OR account_id = $2)
+		ORDER BY id ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, messageID, accountScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list message notes: %w", err)
+	}
+	defer rows.Close()
+
+	notes := []*models.MessageNote{}
+	for rows.Next() {
+		note := &models.MessageNote{}
+		if err := rows.Scan(&note.ID, &note.MessageID, &note.Author, &note.Body, &note.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan message note: %w", err)
+		}
+		notes = append(notes, note)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating message notes: %w", err)
+	}
+
+	return notes, nil
+}
@@ -0,0 +1,313 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// MessageTemplateRepository defines the interface for message template data access
+type MessageTemplateRepository interface {
+	Create(ctx context.Context, template *models.MessageTemplate) error
+	GetByID(ctx context.Context, id int64) (*models.MessageTemplate, error)
+	List(ctx context.Context) ([]*models.MessageTemplate, error)
+	// Update saves a template's name and content. Changed content becomes a
+	// new version, which is carried to the unsent campaigns still holding the
+	// previous version's content; Update returns how many there were.
+	Update(ctx context.Context, template *models.MessageTemplate) (int64, error)
+	Delete(ctx context.Context, id int64) error
+	ListVersions(ctx context.Context, id int64) ([]*models.MessageTemplateVersion, error)
+	GetVersion(ctx context.Context, id int64, version int) (*models.MessageTemplateVersion, error)
+}
+
+// messageTemplateRepository implements MessageTemplateRepository using PostgreSQL
+type messageTemplateRepository struct {
+	db *sql.DB
+}
+
+// NewMessageTemplateRepository creates a new message template repository
+func NewMessageTemplateRepository(db *sql.DB) MessageTemplateRepository {
+	return &messageTemplateRepository{db: db}
+}
+
+// Create inserts a new template and its first version into the account of
+// ctx. Template names are unique per account, so every query here acts on the
+// account of ctx alone.
+func (r *messageTemplateRepository) Create(ctx context.Context, template *models.MessageTemplate) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback() // Rollback is safe to call even after Commit
+	}()
+
+	query := `
+		INSERT INTO message_templates (account_id, name, content)
+		VALUES ($1, $2, $3)
+		RETURNING id, account_id, version, created_at, updated_at`
+
+	err = tx.QueryRowContext(ctx, query, ownerAccount(ctx), template.Name, template.Content).
+		Scan(&template.ID, &template.AccountID, &template.Version, &template.CreatedAt, &template.UpdatedAt)
+	if isUniqueViolation(err) {
+		return models.ErrConflictWithMsg(fmt.Sprintf("template %q already exists", template.Name))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create message template: %w", err)
+	}
+
+	if err := insertTemplateVersion(ctx, tx, template); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// insertTemplateVersion records the template's current content as its version
+func insertTemplateVersion(ctx context.Context, tx *sql.Tx, template *models.MessageTemplate) error {
+	query := `
+		INSERT INTO message_template_versions (template_id, version, content)
+		VALUES ($1, $2, $3)`
+
+	if _, err := tx.ExecContext(ctx, query, template.ID, template.Version, template.Content); err != nil {
+		return fmt.Errorf("failed to create message template version: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a template at its latest version
+func (r *messageTemplateRepository) GetByID(ctx context.Context, id int64) (*models.MessageTemplate, error) {
+	query := `
+		SELECT id, account_id, name, content, version, created_at, updated_at
+		FROM message_templates
+		WHERE id = $1 AND account_id = $2`
+
+	template := &models.MessageTemplate{}
+	err := r.db.QueryRowContext(ctx, query, id, ownerAccount(ctx)).Scan(
+		&template.ID,
+		&template.AccountID,
+		&template.Name,
+		&template.Content,
+		&template.Version,
+		&template.CreatedAt,
+		&template.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFoundWithMsg(fmt.Sprintf("template with ID %d not found", id))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message template: %w", err)
+	}
+
+	return template, nil
+}
+
+// List retrieves every template ordered by name
+func (r *messageTemplateRepository) List(ctx context.Context) ([]*models.MessageTemplate, error) {
+	query := `
+		SELECT id, account_id, name, content, version, created_at, updated_at
+		FROM message_templates
+		WHERE account_id = $1
+		ORDER BY name ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, ownerAccount(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list message templates: %w", err)
+	}
+	defer rows.Close()
+
+	templates := []*models.MessageTemplate{}
+	for rows.Next() {
+		template := &models.MessageTemplate{}
+		err := rows.Scan(
+			&template.ID,
+			&template.AccountID,
+			&template.Name,
+			&template.Content,
+			&template.Version,
+			&template.CreatedAt,
+			&template.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan message template: %w", err)
+		}
+		templates = append(templates, template)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating message templates: %w", err)
+	}
+
+	return templates, nil
+}
+
+// Update saves the template, versioning changed content. The template row is
+// locked for the transaction, which serializes version numbering.
+func (r *messageTemplateRepository) Update(ctx context.Context, template *models.MessageTemplate) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback() // Rollback is safe to call even after Commit
+	}()
+
+	var previous string
+	lockQuery := `SELECT content, version FROM message_templates WHERE id = $1 AND account_id = $2 FOR UPDATE`
+	err = tx.QueryRowContext(ctx, lockQuery, template.ID, ownerAccount(ctx)).Scan(&previous, &template.Version)
+	if err == sql.ErrNoRows {
+		return 0, models.ErrNotFoundWithMsg(fmt.Sprintf("template with ID %d not found", template.ID))
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to lock message template: %w", err)
+	}
+
+	var campaigns int64
+	if template.Content != previous {
+		template.Version++
+		if err := insertTemplateVersion(ctx, tx, template); err != nil {
+			return 0, err
+		}
+
+		// Campaigns whose template was edited after creation keep their edits
+		campaignQuery := `
+			UPDATE campaigns
+			SET base_template = $2, template_version = $3
+			WHERE template_id = $1 AND base_template = $4 AND status IN ('draft', 'scheduled')`
+
+		result, err := tx.ExecContext(ctx, campaignQuery, template.ID, template.Content, template.Version, previous)
+		if err != nil {
+			return 0, fmt.Errorf("failed to update campaigns using message template: %w", err)
+		}
+		if campaigns, err = result.RowsAffected(); err != nil {
+			return 0, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+	}
+
+	query := `
+		UPDATE message_templates
+		SET name = $2, content = $3, version = $4
+		WHERE id = $1
+		RETURNING account_id, created_at, updated_at`
+
+	err = tx.QueryRowContext(ctx, query, template.ID, template.Name, template.Content, template.Version).
+		Scan(&template.AccountID, &template.CreatedAt, &template.UpdatedAt)
+	if isUniqueViolation(err) {
+		return 0, models.ErrConflictWithMsg(fmt.Sprintf("template %q already exists", template.Name))
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to update message template: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return campaigns, nil
+}
+
+// Delete removes a template and its versions. Campaigns created from it keep
+// their base template but no longer follow the template's edits.
+func (r *messageTemplateRepository) Delete(ctx context.Context, id int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback() // Rollback is safe to call even after Commit
+	}()
+
+	accountID := ownerAccount(ctx)
+	unlinkQuery := `
+		UPDATE campaigns
+		SET template_id = NULL, template_version = NULL
+		WHERE template_id = $1 AND account_id = $2`
+
+	if _, err := tx.ExecContext(ctx, unlinkQuery, id, accountID); err != nil {
+		return fmt.Errorf("failed to unlink campaigns from message template: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM message_templates WHERE id = $1 AND account_id = $2`, id, accountID)
+	if err != nil {
+		return fmt.Errorf("failed to delete message template: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrNotFoundWithMsg(fmt.Sprintf("template with ID %d not found", id))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ListVersions retrieves every version of a template, newest first
+func (r *messageTemplateRepository) ListVersions(ctx context.Context, id int64) ([]*models.MessageTemplateVersion, error) {
+	query := `
+		SELECT v.template_id, v.version, v.content, v.created_at
+		FROM message_template_versions v
+		JOIN message_templates t ON t.id = v.template_id
+		WHERE v.template_id = $1 AND t.account_id = $2
+		ORDER BY v.version DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, id, ownerAccount(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list message template versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := []*models.MessageTemplateVersion{}
+	for rows.Next() {
+		version := &models.MessageTemplateVersion{}
+		if err := rows.Scan(&version.TemplateID, &version.Version, &version.Content, &version.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan message template version: %w", err)
+		}
+		versions = append(versions, version)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating message template versions: %w", err)
+	}
+
+	// Every template has a first version, so none means no template
+	if len(versions) == 0 {
+		return nil, models.ErrNotFoundWithMsg(fmt.Sprintf("template with ID %d not found", id))
+	}
+
+	return versions, nil
+}
+
+// GetVersion retrieves one version of a template
+func (r *messageTemplateRepository) GetVersion(ctx context.Context, id int64, version int) (*models.MessageTemplateVersion, error) {
+	query := `
+		SELECT v.template_id, v.version, v.content, v.created_at
+		FROM message_template_versions v
+		JOIN message_templates t ON t.id = v.template_id
+		WHERE v.template_id = $1 AND v.version = $2 AND t.account_id = $3`
+
+	found := &models.MessageTemplateVersion{}
+	err := r.db.QueryRowContext(ctx, query, id, version, ownerAccount(ctx)).
+		Scan(&found.TemplateID, &found.Version, &found.Content, &found.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFoundWithMsg(fmt.Sprintf("version %d of template %d not found", version, id))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message template version: %w", err)
+	}
+
+	return found, nil
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// NumberLookupRepository defines the interface for cached number lookups.
+// Lookups are shared by every account, as they describe the number itself.
+type NumberLookupRepository interface {
+	// Get returns the lookup of phone made after since
+	Get(ctx context.Context, phone string, since time.Time) (*models.NumberLookup, error)
+	// Save stores a lookup, replacing the earlier one of the same phone
+	Save(ctx context.Context, lookup *models.NumberLookup) error
+}
+
+// numberLookupRepository implements NumberLookupRepository using PostgreSQL
+type numberLookupRepository struct {
+	db *sql.DB
+}
+
+// NewNumberLookupRepository creates a new number lookup repository
+func NewNumberLookupRepository(db *sql.DB) NumberLookupRepository {
+	return &numberLookupRepository{db: db}
+}
+
+// Get retrieves a cached lookup that is newer than since
+func (r *numberLookupRepository) Get(ctx context.Context, phone string, since time.Time) (*models.NumberLookup, error) {
+	query := `
+		SELECT phone, reachable, status, checked_at
+		FROM number_lookups
+		WHERE phone = $1 AND checked_at > $2`
+
+	lookup := &models.NumberLookup{}
+	err := r.db.QueryRowContext(ctx, query, phone, since.UTC()).Scan(
+		&lookup.Phone,
+		&lookup.Reachable,
+		&lookup.Status,
+		&lookup.CheckedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFoundWithMsg(fmt.Sprintf("no lookup of %s since %s", phone, since.UTC().Format(time.RFC3339)))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get number lookup: %w", err)
+	}
+
+	return lookup, nil
+}
+
+// Save inserts or replaces the lookup of a phone
+func (r *numberLookupRepository) Save(ctx context.Context, lookup *models.NumberLookup) error {
+	query := `
+		INSERT INTO number_lookups (phone, reachable, status, checked_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (phone) DO UPDATE
+		SET reachable = EXCLUDED.reachable, status = EXCLUDED.status, checked_at = EXCLUDED.checked_at`
+
+	_, err := r.db.ExecContext(ctx, query, lookup.Phone, lookup.Reachable, lookup.Status, lookup.CheckedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to save number lookup: %w", err)
+	}
+
+	return nil
+}
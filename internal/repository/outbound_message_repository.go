@@ -4,6 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
+
+	"github.com/lib/pq"
 
 	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
 )
@@ -14,10 +17,86 @@ type OutboundMessageRepository interface {
 	CreateBatch(ctx context.Context, messages []*models.OutboundMessage) error
 	GetByID(ctx context.Context, id int64) (*models.OutboundMessage, error)
 	List(ctx context.Context, filter models.OutboundMessageFilter) ([]*models.OutboundMessage, int64, error)
+	ListRecipients(ctx context.Context, filter models.OutboundMessageFilter) ([]*models.MessageRecipient, int64, error)
 	Update(ctx context.Context, message *models.OutboundMessage) error
 	UpdateStatus(ctx context.Context, id int64, status string, lastError *string) error
+	// MarkSent records that the provider accepted the message under providerMessageID
+	MarkSent(ctx context.Context, id int64, providerMessageID string) error
+	// ApplyDeliveryReport sets the final status of the sent message the report
+	// refers to. It returns false when no sent message has the report's provider
+	// message ID.
+	ApplyDeliveryReport(ctx context.Context, report models.DeliveryReport) (bool, error)
+	// RetrySoftBounces moves up to limit soft bounced messages whose
+	// re-attempt is due back to pending and reopens their finished campaigns.
+	// Due messages that can no longer be sent have their re-attempt dropped.
+	// It returns how many were re-attempted and how many were due in all.
+	RetrySoftBounces(ctx context.Context, limit int) (retried, due int64, err error)
+	// CountBounces counts the bounced messages of a campaign
+	CountBounces(ctx context.Context, campaignID int64) (*models.BounceStats, error)
 	GetPendingMessages(ctx context.Context, limit int) ([]*models.OutboundMessage, error)
+	// ClaimMessage moves a pending message to 'sending' for the worker about to
+	// send it. It returns false when the message is no longer pending, e.g.
+	// another worker claimed it first.
+	ClaimMessage(ctx context.Context, id int64) (bool, error)
+	// ClaimPending moves up to limit of the oldest pending messages to
+	// 'sending' and returns them, for a poller or sweeper working alongside
+	// the queue consumers. A message is never claimed by two callers.
+	ClaimPending(ctx context.Context, limit int) ([]*models.OutboundMessage, error)
+	// ReclaimStale moves up to limit messages claimed before claimedBefore and
+	// still 'sending' back to pending and unqueued, for the outbox relay to
+	// publish again. It returns how many were reclaimed.
+	ReclaimStale(ctx context.Context, claimedBefore time.Time, limit int) (int64, error)
+	// MarkQueued records that jobs for the given messages were published
+	MarkQueued(ctx context.Context, ids []int64) error
+	// MarkUnqueued records that the job of a pending message was dropped
+	// while its campaign was paused, so resuming the campaign publishes it again
+	MarkUnqueued(ctx context.Context, id int64) error
+	// UnqueueByCampaign marks the pending messages of a campaign unqueued once
+	// their jobs were removed from the queue. It returns how many it marked.
+	UnqueueByCampaign(ctx context.Context, campaignID int64) (int64, error)
+	// ListUnqueuedByCampaign returns up to limit pending messages of a
+	// campaign that are not queued, with IDs above afterID, in ID order
+	ListUnqueuedByCampaign(ctx context.Context, campaignID, afterID int64, limit int) ([]*models.OutboundMessage, error)
+	// RelayUnqueued hands up to limit pending messages that were never marked
+	// queued, and have not changed for settle, to publish, and marks queued the
+	// ones it publishes. It returns how many were published. Messages built
+	// ahead of their campaign's schedule are held until the campaign is
+	// released, those of a send awaiting approval until it is approved, and
+	// those of paused campaigns until the campaign is resumed.
+	RelayUnqueued(ctx context.Context, settle time.Duration, limit int, publish func(ctx context.Context, id, campaignID int64) error) (int, error)
 	IncrementRetryCount(ctx context.Context, id int64) error
+	CountByCampaign(ctx context.Context, campaignID int64) (int64, error)
+	CountByCustomer(ctx context.Context, customerID int64) (int64, error)
+	// MessagedSince returns those of customerIDs with a message created at or
+	// after since that did not fail or get skipped
+	MessagedSince(ctx context.Context, customerIDs []int64, since time.Time) ([]int64, error)
+	ListByCampaignAfterID(ctx context.Context, campaignID, afterID int64, limit int) ([]*models.OutboundMessage, error)
+	// ListFailedRecipients returns up to limit failed or undelivered messages
+	// of a campaign with IDs above afterID, with their customers, in ID order
+	ListFailedRecipients(ctx context.Context, campaignID, afterID int64, limit int) ([]*models.FailedRecipient, error)
+	// ListUpdatedSince returns up to limit messages updated after the cursor,
+	// oldest change first. Changes younger than settle are left for a later
+	// poll so rows written by transactions still in flight are not skipped.
+	ListUpdatedSince(ctx context.Context, after models.ChangeCursor, settle time.Duration, limit int) ([]*models.OutboundMessage, error)
+	// ListReceipts returns up to limit receipts of sent, failed, delivered or
+	// undelivered messages updated after the cursor, in the same order and
+	// with the same settle delay as ListUpdatedSince
+	ListReceipts(ctx context.Context, after models.ChangeCursor, settle time.Duration, limit int) ([]*models.MessageReceipt, error)
+	UpdateStatusBatch(ctx context.Context, ids []int64, status string) (int64, error)
+	ResetFailedByCampaign(ctx context.Context, campaignID int64, maxRetry int) ([]int64, error)
+	// CountFailedInWindow counts the failed messages ResetFailedInWindow would
+	// reset, and the campaigns they belong to
+	CountFailedInWindow(ctx context.Context, window models.FailedMessageWindow) (messages, campaigns int64, err error)
+	// ResetFailedInWindow moves up to limit failed messages in the window with
+	// IDs above afterID back to pending with their retries cleared, reopens
+	// their finished campaigns, and returns the reset messages in ID order
+	ResetFailedInWindow(ctx context.Context, window models.FailedMessageWindow, afterID int64, limit int) ([]*models.OutboundMessage, error)
+	// SkipPendingByCampaign marks every pending message of a campaign skipped
+	// and returns how many it marked
+	SkipPendingByCampaign(ctx context.Context, campaignID int64) (int64, error)
+	// RedactContentBefore clears the rendered content of up to limit finished
+	// (sent, failed, delivered or undelivered) messages last updated before the given time
+	RedactContentBefore(ctx context.Context, before time.Time, limit int) (int64, error)
 }
 
 // outboundMessageRepository implements OutboundMessageRepository using PostgreSQL
@@ -30,11 +109,12 @@ func NewOutboundMessageRepository(db *sql.DB) OutboundMessageRepository {
 	return &outboundMessageRepository{db: db}
 }
 
-// Create inserts a new outbound message
+// Create inserts a new outbound message into its campaign's account. The
+// customer must belong to the same account, which the database enforces.
 func (r *outboundMessageRepository) Create(ctx context.Context, message *models.OutboundMessage) error {
 	query := `
-		INSERT INTO outbound_messages (campaign_id, customer_id, status, rendered_content, last_error, retry_count)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO outbound_messages (campaign_id, customer_id, status, rendered_content, last_error, retry_count, account_id)
+		VALUES ($1, $2, $3, $4, $5, $6, (SELECT account_id FROM campaigns WHERE id = $1))
 		RETURNING id, created_at, updated_at`
 
 	err := r.db.QueryRowContext(
@@ -55,7 +135,8 @@ func (r *outboundMessageRepository) Create(ctx context.Context, message *models.
 	return nil
 }
 
-// CreateBatch inserts multiple outbound messages in a single transaction
+// CreateBatch inserts multiple outbound messages in a single transaction, each
+// into its campaign's account
 func (r *outboundMessageRepository) CreateBatch(ctx context.Context, messages []*models.OutboundMessage) error {
 	if len(messages) == 0 {
 		return nil
@@ -70,8 +151,8 @@ func (r *outboundMessageRepository) CreateBatch(ctx context.Context, messages []
 	}()
 
 	stmt, err := tx.PrepareContext(ctx, `
-		INSERT INTO outbound_messages (campaign_id, customer_id, status, rendered_content, retry_count)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO outbound_messages (campaign_id, customer_id, status, rendered_content, retry_count, account_id)
+		VALUES ($1, $2, $3, $4, $5, (SELECT account_id FROM campaigns WHERE id = $1))
 		RETURNING id, created_at, updated_at`)
 	if err != nil {
 		return fmt.Errorf("failed to prepare statement: %w", err)
@@ -103,12 +184,12 @@ func (r *outboundMessageRepository) CreateBatch(ctx context.Context, messages []
 // GetByID retrieves an outbound message by ID
 func (r *outboundMessageRepository) GetByID(ctx context.Context, id int64) (*models.OutboundMessage, error) {
 	query := `
-		SELECT id, campaign_id, customer_id, status, rendered_content, last_error, retry_count, created_at, updated_at
+		SELECT id, campaign_id, customer_id, status, COALESCE(rendered_content, ''), last_error, retry_count, content_redacted_at, provider_message_id, created_at, updated_at
 		FROM outbound_messages
-		WHERE id = $1`
+		WHERE id = $1 AND ($2::BIGINT = 0 OR account_id = $2)`
 
 	message := &models.OutboundMessage{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := r.db.QueryRowContext(ctx, query, id, accountScope(ctx)).Scan(
 		&message.ID,
 		&message.CampaignID,
 		&message.CustomerID,
@@ -116,6 +197,8 @@ func (r *outboundMessageRepository) GetByID(ctx context.Context, id int64) (*mod
 		&message.RenderedContent,
 		&message.LastError,
 		&message.RetryCount,
+		&message.ContentRedactedAt,
+		&message.ProviderMessageID,
 		&message.CreatedAt,
 		&message.UpdatedAt,
 	)
@@ -137,12 +220,12 @@ func (r *outboundMessageRepository) List(ctx context.Context, filter models.Outb
 
 	// Build query with filters
 	query := `
-		SELECT id, campaign_id, customer_id, status, rendered_content, last_error, retry_count, created_at, updated_at
+		SELECT id, campaign_id, customer_id, status, COALESCE(rendered_content, ''), last_error, retry_count, content_redacted_at, provider_message_id, created_at, updated_at
 		FROM outbound_messages
-		WHERE 1=1`
-	countQuery := `SELECT COUNT(*) FROM outbound_messages WHERE 1=1`
-	args := []interface{}{}
-	argPos := 1
+		WHERE ($1::BIGINT = 0 OR account_id = $1)`
+	countQuery := `SELECT COUNT(*) FROM outbound_messages WHERE ($1::BIGINT = 0 OR account_id = $1)`
+	args := []interface{}{accountScope(ctx)}
+	argPos := 2
 
 	if filter.CampaignID > 0 {
 		query += fmt.Sprintf(" AND campaign_id = $%d", argPos)
@@ -195,6 +278,8 @@ func (r *outboundMessageRepository) List(ctx context.Context, filter models.Outb
 			&message.RenderedContent,
 			&message.LastError,
 			&message.RetryCount,
+			&message.ContentRedactedAt,
+			&message.ProviderMessageID,
 			&message.CreatedAt,
 			&message.UpdatedAt,
 		)
@@ -211,11 +296,98 @@ func (r *outboundMessageRepository) List(ctx context.Context, filter models.Outb
 	return messages, totalCount, nil
 }
 
-// Update updates an existing outbound message
+// ListRecipients retrieves messages joined with their customers, newest first.
+// A phone filter resolves through the customers phone index and then the
+// messages (customer_id, created_at) index, so looking up one recipient of a
+// large campaign never scans the campaign.
+func (r *outboundMessageRepository) ListRecipients(ctx context.Context, filter models.OutboundMessageFilter) ([]*models.MessageRecipient, int64, error) {
+	models.ValidateAndSetDefaults(&filter.Page, &filter.PageSize)
+
+	from := `
+		FROM outbound_messages m
+		JOIN customers c ON c.id = m.customer_id
+		WHERE ($1::BIGINT = 0 OR m.account_id = $1)`
+	args := []interface{}{accountScope(ctx)}
+	argPos := 2
+
+	if filter.CampaignID > 0 {
+		from += fmt.Sprintf(" AND m.campaign_id = $%d", argPos)
+		args = append(args, filter.CampaignID)
+		argPos++
+	}
+
+	if filter.CustomerID > 0 {
+		from += fmt.Sprintf(" AND m.customer_id = $%d", argPos)
+		args = append(args, filter.CustomerID)
+		argPos++
+	}
+
+	if filter.Phone != "" {
+		from += fmt.Sprintf(" AND c.phone = $%d", argPos)
+		args = append(args, filter.Phone)
+		argPos++
+	}
+
+	if filter.Status != "" {
+		from += fmt.Sprintf(" AND m.status = $%d", argPos)
+		args = append(args, filter.Status)
+		argPos++
+	}
+
+	var totalCount int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*)`+from, args...).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to count message recipients: %w", err)
+	}
+
+	query := `
+		SELECT m.id, m.campaign_id, m.customer_id, m.status, COALESCE(m.rendered_content, ''), m.last_error, m.retry_count,
+			m.content_redacted_at, m.provider_message_id, m.created_at, m.updated_at, c.phone, c.first_name, c.last_name` + from +
+		fmt.Sprintf(" ORDER BY m.id DESC LIMIT $%d OFFSET $%d", argPos, argPos+1)
+	args = append(args, filter.PageSize, models.CalculateOffset(filter.Page, filter.PageSize))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list message recipients: %w", err)
+	}
+	defer rows.Close()
+
+	recipients := []*models.MessageRecipient{}
+	for rows.Next() {
+		recipient := &models.MessageRecipient{OutboundMessage: &models.OutboundMessage{}}
+		err := rows.Scan(
+			&recipient.ID,
+			&recipient.CampaignID,
+			&recipient.CustomerID,
+			&recipient.Status,
+			&recipient.RenderedContent,
+			&recipient.LastError,
+			&recipient.RetryCount,
+			&recipient.ContentRedactedAt,
+			&recipient.ProviderMessageID,
+			&recipient.CreatedAt,
+			&recipient.UpdatedAt,
+			&recipient.Phone,
+			&recipient.FirstName,
+			&recipient.LastName,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan message recipient: %w", err)
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating message recipients: %w", err)
+	}
+
+	return recipients, totalCount, nil
+}
+
+// Update updates an existing outbound message. Redacted content stays cleared.
 func (r *outboundMessageRepository) Update(ctx context.Context, message *models.OutboundMessage) error {
 	query := `
 		UPDATE outbound_messages
-		SET status = $1, rendered_content = $2, last_error = $3, retry_count = $4
+		SET status = $1, rendered_content = CASE WHEN content_redacted_at IS NULL THEN $2 END, last_error = $3, retry_count = $4
 		WHERE id = $5
 		RETURNING updated_at`
 
@@ -263,10 +435,139 @@ func (r *outboundMessageRepository) UpdateStatus(ctx context.Context, id int64,
 	return nil
 }
 
+// MarkSent sets the message to sent and stores the provider's message ID
+func (r *outboundMessageRepository) MarkSent(ctx context.Context, id int64, providerMessageID string) error {
+	query := `
+		UPDATE outbound_messages
+		SET status = 'sent', last_error = NULL, provider_message_id = NULLIF($1, '')
+		WHERE id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, providerMessageID, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark outbound message sent: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrNotFoundWithMsg(fmt.Sprintf("outbound message with ID %d not found", id))
+	}
+
+	return nil
+}
+
+// ApplyDeliveryReport moves a sent message to delivered or undelivered. A later
+// report for the same message replaces the earlier one, as providers may
+// report a failed delivery attempt before a successful one. Every applied
+// report is recorded as a message event.
+//
+// A bounce is handled under the policy of the message's account: a soft
+// bounce with re-attempts left is scheduled to be sent again, and a hard
+// bounce suppresses the customer's number when the policy says so.
+func (r *outboundMessageRepository) ApplyDeliveryReport(ctx context.Context, report models.DeliveryReport) (bool, error) {
+	query := `
+		WITH reported AS (
+			UPDATE outbound_messages om
+			SET status = $1, last_error = $2, bounce_type = NULLIF($4::TEXT, ''),
+				bounce_retry_at = CASE
+					WHEN $4::TEXT = 'soft' AND om.bounce_retries < a.soft_bounce_max_retries
+					THEN LOCALTIMESTAMP + make_interval(hours => a.soft_bounce_retry_hours)
+				END
+			FROM accounts a
+			WHERE a.id = om.account_id
+				AND om.provider_message_id = $3 AND om.status IN ('sent', 'delivered', 'undelivered')
+			RETURNING om.id, om.account_id, om.customer_id, a.suppress_hard_bounces
+		), suppressed AS (
+			UPDATE customers c
+			SET bounced_at = COALESCE(c.bounced_at, LOCALTIMESTAMP)
+			FROM reported
+			WHERE c.id = reported.customer_id AND $4::TEXT = 'hard' AND reported.suppress_hard_bounces
+		), recorded AS (
+			INSERT INTO message_events (account_id, message_id, type, detail)
+			SELECT account_id, id, $1, $2 FROM reported
+		)
+		SELECT COUNT(*) FROM reported`
+
+	var applied int64
+	err := r.db.QueryRowContext(ctx, query, report.Status, report.Reason, report.ProviderMessageID, report.Bounce).Scan(&applied)
+	if err != nil {
+		return false, fmt.Errorf("failed to apply delivery report: %w", err)
+	}
+
+	return applied > 0, nil
+}
+
+// RetrySoftBounces resends the soft bounces that are due, oldest first. The
+// outbox relay publishes them once they are pending and unqueued again.
+// Messages of cancelled campaigns, and those whose content was redacted, stay
+// undelivered. Each resent message gets a retried event. SKIP LOCKED lets
+// several workers sweep at once.
+func (r *outboundMessageRepository) RetrySoftBounces(ctx context.Context, limit int) (retried, due int64, err error) {
+	query := `
+		WITH due AS (
+			SELECT om.id, c.status <> 'cancelled' AND om.content_redacted_at IS NULL AS resend
+			FROM outbound_messages om
+			JOIN campaigns c ON c.id = om.campaign_id
+			WHERE om.status = 'undelivered' AND om.bounce_retry_at <= LOCALTIMESTAMP
+			ORDER BY om.bounce_retry_at
+			LIMIT $1
+			FOR UPDATE OF om SKIP LOCKED
+		), dropped AS (
+			UPDATE outbound_messages
+			SET bounce_retry_at = NULL
+			WHERE id IN (SELECT id FROM due WHERE NOT resend)
+		), retried AS (
+			UPDATE outbound_messages
+			SET status = 'pending', last_error = NULL, bounce_type = NULL, bounce_retry_at = NULL,
+				bounce_retries = bounce_retries + 1, queued_at = NULL
+			WHERE id IN (SELECT id FROM due WHERE resend)
+			RETURNING id, account_id, campaign_id
+		), recorded AS (
+			INSERT INTO message_events (account_id, message_id, type, detail)
+			SELECT account_id, id, 'retried', 'soft bounce' FROM retried
+		), reopened AS (
+			UPDATE campaigns
+			SET status = 'sending'
+			WHERE id IN (SELECT campaign_id FROM retried) AND status IN ('sent', 'failed')
+		)
+		SELECT (SELECT COUNT(*) FROM retried), (SELECT COUNT(*) FROM due)`
+
+	if err := r.db.QueryRowContext(ctx, query, limit).Scan(&retried, &due); err != nil {
+		return 0, 0, fmt.Errorf("failed to retry soft bounces: %w", err)
+	}
+
+	return retried, due, nil
+}
+
+// CountBounces counts a campaign's messages by how they bounced
+func (r *outboundMessageRepository) CountBounces(ctx context.Context, campaignID int64) (*models.BounceStats, error) {
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE status = 'undelivered' AND bounce_type = 'soft'),
+			COUNT(*) FILTER (WHERE status = 'undelivered' AND bounce_type = 'hard'),
+			COUNT(*) FILTER (WHERE bounce_retry_at IS NOT NULL),
+			COUNT(*) FILTER (WHERE bounce_retries > 0),
+			COUNT(*) FILTER (WHERE bounce_retries > 0 AND status = 'delivered')
+		FROM outbound_messages
+		WHERE campaign_id = $1 AND ($2::BIGINT = 0 OR account_id = $2)`
+
+	stats := &models.BounceStats{CampaignID: campaignID}
+	err := r.db.QueryRowContext(ctx, query, campaignID, accountScope(ctx)).
+		Scan(&stats.Soft, &stats.Hard, &stats.RetryScheduled, &stats.Retried, &stats.Recovered)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count bounces: %w", err)
+	}
+
+	return stats, nil
+}
+
 // GetPendingMessages retrieves pending messages for worker processing
 func (r *outboundMessageRepository) GetPendingMessages(ctx context.Context, limit int) ([]*models.OutboundMessage, error) {
 	query := `
-		SELECT id, campaign_id, customer_id, status, rendered_content, last_error, retry_count, created_at, updated_at
+		SELECT id, campaign_id, customer_id, status, COALESCE(rendered_content, ''), last_error, retry_count, content_redacted_at, provider_message_id, created_at, updated_at
 		FROM outbound_messages
 		WHERE status = 'pending'
 		ORDER BY created_at ASC
@@ -289,6 +590,8 @@ func (r *outboundMessageRepository) GetPendingMessages(ctx context.Context, limi
 			&message.RenderedContent,
 			&message.LastError,
 			&message.RetryCount,
+			&message.ContentRedactedAt,
+			&message.ProviderMessageID,
 			&message.CreatedAt,
 			&message.UpdatedAt,
 		)
@@ -305,6 +608,295 @@ func (r *outboundMessageRepository) GetPendingMessages(ctx context.Context, limi
 	return messages, nil
 }
 
+// ClaimMessage claims a message by its status, so of two jobs of the same
+// message only one ever gets to send it. claimed_at starts the claim's lease.
+func (r *outboundMessageRepository) ClaimMessage(ctx context.Context, id int64) (bool, error) {
+	query := `
+		UPDATE outbound_messages
+		SET status = 'sending', claimed_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'pending'`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim message: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// ClaimPending atomically moves up to limit of the oldest pending messages to
+// 'sending' and returns them. FOR UPDATE SKIP LOCKED lets several pollers or
+// sweepers run side by side without ever claiming the same row twice, and
+// claimed_at starts each claim's lease as for ClaimMessage.
+func (r *outboundMessageRepository) ClaimPending(ctx context.Context, limit int) ([]*models.OutboundMessage, error) {
+	query := `
+		UPDATE outbound_messages
+		SET status = 'sending', claimed_at = CURRENT_TIMESTAMP
+		WHERE id IN (
+			SELECT id
+			FROM outbound_messages
+			WHERE status = 'pending' AND ($2::BIGINT = 0 OR account_id = $2)
+			ORDER BY created_at ASC
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, campaign_id, customer_id, status, COALESCE(rendered_content, ''), last_error, retry_count, content_redacted_at, provider_message_id, created_at, updated_at`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, accountScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim pending messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages := []*models.OutboundMessage{}
+	for rows.Next() {
+		message := &models.OutboundMessage{}
+		err := rows.Scan(
+			&message.ID,
+			&message.CampaignID,
+			&message.CustomerID,
+			&message.Status,
+			&message.RenderedContent,
+			&message.LastError,
+			&message.RetryCount,
+			&message.ContentRedactedAt,
+			&message.ProviderMessageID,
+			&message.CreatedAt,
+			&message.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan claimed message: %w", err)
+		}
+		messages = append(messages, message)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating claimed messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// ReclaimStale returns the messages of workers that died between claiming and
+// recording the outcome. A worker that died after the provider accepted the
+// message cannot be told apart, so such a message is sent again: sends are
+// at least once. SKIP LOCKED lets several reapers run side by side.
+func (r *outboundMessageRepository) ReclaimStale(ctx context.Context, claimedBefore time.Time, limit int) (int64, error) {
+	query := `
+		UPDATE outbound_messages
+		SET status = 'pending', claimed_at = NULL, queued_at = NULL
+		WHERE id IN (
+			SELECT id
+			FROM outbound_messages
+			WHERE status = 'sending' AND claimed_at < $1
+			ORDER BY claimed_at ASC
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)`
+
+	result, err := r.db.ExecContext(ctx, query, claimedBefore, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reclaim stale messages: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// MarkQueued sets queued_at on messages whose jobs were published and records
+// their queued events
+func (r *outboundMessageRepository) MarkQueued(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query := `
+		WITH queued AS (
+			UPDATE outbound_messages
+			SET queued_at = CURRENT_TIMESTAMP
+			WHERE id = ANY($1) AND queued_at IS NULL
+			RETURNING id, account_id
+		)
+		INSERT INTO message_events (account_id, message_id, type)
+		SELECT account_id, id, 'queued' FROM queued`
+
+	if _, err := r.db.ExecContext(ctx, query, pq.Array(ids)); err != nil {
+		return fmt.Errorf("failed to mark messages queued: %w", err)
+	}
+
+	return nil
+}
+
+// MarkUnqueued clears queued_at of a message that is still pending
+func (r *outboundMessageRepository) MarkUnqueued(ctx context.Context, id int64) error {
+	query := `
+		UPDATE outbound_messages
+		SET queued_at = NULL
+		WHERE id = $1 AND status = 'pending' AND ($2::BIGINT = 0 OR account_id = $2)`
+
+	if _, err := r.db.ExecContext(ctx, query, id, accountScope(ctx)); err != nil {
+		return fmt.Errorf("failed to mark message unqueued: %w", err)
+	}
+
+	return nil
+}
+
+// UnqueueByCampaign clears queued_at of a campaign's pending messages
+func (r *outboundMessageRepository) UnqueueByCampaign(ctx context.Context, campaignID int64) (int64, error) {
+	query := `
+		UPDATE outbound_messages
+		SET queued_at = NULL
+		WHERE campaign_id = $1 AND status = 'pending' AND queued_at IS NOT NULL
+			AND ($2::BIGINT = 0 OR account_id = $2)`
+
+	result, err := r.db.ExecContext(ctx, query, campaignID, accountScope(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("failed to unqueue campaign messages: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// ListUnqueuedByCampaign retrieves a page of a campaign's unqueued pending
+// messages
+func (r *outboundMessageRepository) ListUnqueuedByCampaign(ctx context.Context, campaignID, afterID int64, limit int) ([]*models.OutboundMessage, error) {
+	query := `
+		SELECT id, campaign_id, customer_id, status, COALESCE(rendered_content, ''), last_error, retry_count, content_redacted_at, provider_message_id, created_at, updated_at
+		FROM outbound_messages
+		WHERE campaign_id = $1 AND id > $2 AND status = 'pending' AND queued_at IS NULL
+			AND ($4::BIGINT = 0 OR account_id = $4)
+		ORDER BY id ASC
+		LIMIT $3`
+
+	rows, err := r.db.QueryContext(ctx, query, campaignID, afterID, limit, accountScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unqueued campaign messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages := []*models.OutboundMessage{}
+	for rows.Next() {
+		message := &models.OutboundMessage{}
+		err := rows.Scan(
+			&message.ID,
+			&message.CampaignID,
+			&message.CustomerID,
+			&message.Status,
+			&message.RenderedContent,
+			&message.LastError,
+			&message.RetryCount,
+			&message.ContentRedactedAt,
+			&message.ProviderMessageID,
+			&message.CreatedAt,
+			&message.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan unqueued message: %w", err)
+		}
+		messages = append(messages, message)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating unqueued messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// RelayUnqueued locks the oldest unqueued pending messages, publishes each and
+// marks the published ones queued in the same transaction. A crash before the
+// commit leaves them unqueued, so they are published again by a later relay:
+// jobs are delivered at least once. SKIP LOCKED lets several relays run side
+// by side. Publishing stops at the first error, keeping what was published.
+func (r *outboundMessageRepository) RelayUnqueued(ctx context.Context, settle time.Duration, limit int, publish func(ctx context.Context, id, campaignID int64) error) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback() // Rollback is safe to call even after Commit
+	}()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, campaign_id
+		FROM outbound_messages om
+		WHERE queued_at IS NULL AND status = 'pending'
+			AND updated_at < LOCALTIMESTAMP - make_interval(secs => $1)
+			AND NOT EXISTS (
+				SELECT 1 FROM campaigns c
+				WHERE c.id = om.campaign_id
+					AND (c.status IN ('ready', 'awaiting_approval', 'paused') OR (c.status = 'scheduled' AND c.prebuild_minutes IS NOT NULL))
+			)
+		ORDER BY updated_at ASC
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED`, settle.Seconds(), limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to lock unqueued messages: %w", err)
+	}
+
+	ids, campaignIDs := []int64{}, []int64{}
+	for rows.Next() {
+		var id, campaignID int64
+		if err := rows.Scan(&id, &campaignID); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan unqueued message: %w", err)
+		}
+		ids = append(ids, id)
+		campaignIDs = append(campaignIDs, campaignID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating unqueued messages: %w", err)
+	}
+
+	published := make([]int64, 0, len(ids))
+	var publishErr error
+	for i, id := range ids {
+		if publishErr = publish(ctx, id, campaignIDs[i]); publishErr != nil {
+			break
+		}
+		published = append(published, id)
+	}
+
+	if len(published) > 0 {
+		_, err := tx.ExecContext(ctx, `
+			WITH queued AS (
+				UPDATE outbound_messages
+				SET queued_at = CURRENT_TIMESTAMP
+				WHERE id = ANY($1)
+				RETURNING id, account_id
+			)
+			INSERT INTO message_events (account_id, message_id, type)
+			SELECT account_id, id, 'queued' FROM queued`, pq.Array(published))
+		if err != nil {
+			return 0, fmt.Errorf("failed to mark relayed messages queued: %w", err)
+		}
+		if err := tx.Commit(); err != nil {
+			return 0, fmt.Errorf("failed to commit transaction: %w", err)
+		}
+	}
+
+	if publishErr != nil {
+		return len(published), fmt.Errorf("failed to publish message %d: %w", ids[len(published)], publishErr)
+	}
+
+	return len(published), nil
+}
+
 // IncrementRetryCount increments the retry count for a message
 func (r *outboundMessageRepository) IncrementRetryCount(ctx context.Context, id int64) error {
 	query := `
@@ -328,3 +920,439 @@ func (r *outboundMessageRepository) IncrementRetryCount(ctx context.Context, id
 
 	return nil
 }
+
+// CountByCampaign returns the number of messages recorded for a campaign
+func (r *outboundMessageRepository) CountByCampaign(ctx context.Context, campaignID int64) (int64, error) {
+	query := `SELECT COUNT(*) FROM outbound_messages WHERE campaign_id = $1 AND ($2::BIGINT = 0 OR account_id = $2)`
+
+	var count int64
+	if err := r.db.QueryRowContext(ctx, query, campaignID, accountScope(ctx)).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count messages for campaign: %w", err)
+	}
+
+	return count, nil
+}
+
+// CountByCustomer returns the number of messages recorded for a customer
+func (r *outboundMessageRepository) CountByCustomer(ctx context.Context, customerID int64) (int64, error) {
+	query := `SELECT COUNT(*) FROM outbound_messages WHERE customer_id = $1 AND ($2::BIGINT = 0 OR account_id = $2)`
+
+	var count int64
+	if err := r.db.QueryRowContext(ctx, query, customerID, accountScope(ctx)).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count messages for customer: %w", err)
+	}
+
+	return count, nil
+}
+
+// MessagedSince reads the customers' messages through the (customer_id,
+// created_at) index
+func (r *outboundMessageRepository) MessagedSince(ctx context.Context, customerIDs []int64, since time.Time) ([]int64, error) {
+	if len(customerIDs) == 0 {
+		return []int64{}, nil
+	}
+
+	query := `
+		SELECT DISTINCT customer_id
+		FROM outbound_messages
+		WHERE customer_id = ANY($1) AND created_at >= $2 AND status NOT IN ('failed', 'skipped')
+			AND ($3::BIGINT = 0 OR account_id = $3)`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(customerIDs), since, accountScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find recently messaged customers: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan customer ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating recently messaged customers: %w", err)
+	}
+
+	return ids, nil
+}
+
+// ListByCampaignAfterID retrieves up to limit messages of a campaign with an ID
+// greater than afterID, ordered by ID (keyset pagination for exports)
+func (r *outboundMessageRepository) ListByCampaignAfterID(ctx context.Context, campaignID, afterID int64, limit int) ([]*models.OutboundMessage, error) {
+	query := `
+		SELECT id, campaign_id, customer_id, status, COALESCE(rendered_content, ''), last_error, retry_count, content_redacted_at, provider_message_id, created_at, updated_at
+		FROM outbound_messages
+		WHERE campaign_id = $1 AND id > $2 AND ($4::BIGINT = 0 OR account_id = $4)
+		ORDER BY id ASC
+		LIMIT $3`
+
+	rows, err := r.db.QueryContext(ctx, query, campaignID, afterID, limit, accountScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list campaign messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages := []*models.OutboundMessage{}
+	for rows.Next() {
+		message := &models.OutboundMessage{}
+		err := rows.Scan(
+			&message.ID,
+			&message.CampaignID,
+			&message.CustomerID,
+			&message.Status,
+			&message.RenderedContent,
+			&message.LastError,
+			&message.RetryCount,
+			&message.ContentRedactedAt,
+			&message.ProviderMessageID,
+			&message.CreatedAt,
+			&message.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan campaign message: %w", err)
+		}
+		messages = append(messages, message)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating campaign messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// ListFailedRecipients retrieves a page of a campaign's failed and undelivered
+// messages joined with their customers
+func (r *outboundMessageRepository) ListFailedRecipients(ctx context.Context, campaignID, afterID int64, limit int) ([]*models.FailedRecipient, error) {
+	query := `
+		SELECT om.id, c.id, c.external_id, c.phone, c.first_name, c.last_name, om.status, om.last_error, om.retry_count, om.created_at, om.updated_at
+		FROM outbound_messages om
+		JOIN customers c ON c.id = om.customer_id
+		WHERE om.campaign_id = $1 AND om.id > $2 AND om.status IN ('failed', 'undelivered')
+			AND ($4::BIGINT = 0 OR om.account_id = $4)
+		ORDER BY om.id ASC
+		LIMIT $3`
+
+	rows, err := r.db.QueryContext(ctx, query, campaignID, afterID, limit, accountScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list failed recipients: %w", err)
+	}
+	defer rows.Close()
+
+	recipients := []*models.FailedRecipient{}
+	for rows.Next() {
+		recipient := &models.FailedRecipient{}
+		err := rows.Scan(
+			&recipient.MessageID,
+			&recipient.CustomerID,
+			&recipient.ExternalID,
+			&recipient.Phone,
+			&recipient.FirstName,
+			&recipient.LastName,
+			&recipient.Status,
+			&recipient.LastError,
+			&recipient.RetryCount,
+			&recipient.CreatedAt,
+			&recipient.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan failed recipient: %w", err)
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating failed recipients: %w", err)
+	}
+
+	return recipients, nil
+}
+
+// failedInWindow selects the failed messages of a FailedMessageWindow. Only
+// messages of campaigns that are sending or finished qualify: paused and
+// cancelled campaigns are left to be resumed, and messages whose content was
+// redacted have nothing left to send.
+const failedInWindow = `
+	FROM outbound_messages om
+	JOIN campaigns c ON c.id = om.campaign_id
+	WHERE om.status = 'failed' AND om.updated_at >= $1 AND om.updated_at < $2
+		AND strpos(lower(COALESCE(om.last_error, '')), lower($3)) > 0
+		AND ($4 = '' OR c.channel = $4)
+		AND c.status IN ('sending', 'sent', 'failed')
+		AND om.content_redacted_at IS NULL
+		AND ($5::BIGINT = 0 OR om.account_id = $5)`
+
+// CountFailedInWindow counts the failed messages in a window and their campaigns
+func (r *outboundMessageRepository) CountFailedInWindow(ctx context.Context, window models.FailedMessageWindow) (int64, int64, error) {
+	query := `SELECT COUNT(*), COUNT(DISTINCT om.campaign_id)` + failedInWindow
+
+	var messages, campaigns int64
+	err := r.db.QueryRowContext(ctx, query,
+		window.From, window.To, window.ErrorContains, window.Channel, accountScope(ctx),
+	).Scan(&messages, &campaigns)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count failed messages: %w", err)
+	}
+
+	return messages, campaigns, nil
+}
+
+// ResetFailedInWindow moves a batch of failed messages in a window back to
+// pending in ID order. Their retry count and last error are cleared, since
+// the failures being remediated did not use up real attempts, and they are
+// unqueued so the outbox relay publishes them if the caller does not. Their
+// campaigns move from sent or failed back to sending in the same statement,
+// and the worker completes them again once the messages settle. Paging by ID
+// keeps a message that fails again while the retry runs from being reset twice.
+func (r *outboundMessageRepository) ResetFailedInWindow(ctx context.Context, window models.FailedMessageWindow, afterID int64, limit int) ([]*models.OutboundMessage, error) {
+	query := `
+		WITH reset AS (
+			UPDATE outbound_messages
+			SET status = 'pending', last_error = NULL, retry_count = 0, queued_at = NULL
+			WHERE id IN (
+				SELECT om.id` + failedInWindow + `
+					AND om.id > $7
+				ORDER BY om.id
+				LIMIT $6
+				FOR UPDATE OF om SKIP LOCKED
+			)
+			RETURNING id, campaign_id
+		), reopened AS (
+			UPDATE campaigns
+			SET status = 'sending'
+			WHERE id IN (SELECT campaign_id FROM reset) AND status IN ('sent', 'failed')
+		)
+		SELECT id, campaign_id FROM reset ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, query,
+		window.From, window.To, window.ErrorContains, window.Channel, accountScope(ctx), limit, afterID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reset failed messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages := []*models.OutboundMessage{}
+	for rows.Next() {
+		message := &models.OutboundMessage{Status: models.MessageStatusPending}
+		if err := rows.Scan(&message.ID, &message.CampaignID); err != nil {
+			return nil, fmt.Errorf("failed to scan reset message: %w", err)
+		}
+		messages = append(messages, message)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reset messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// ListUpdatedSince retrieves messages changed after the cursor in (updated_at, id) order
+func (r *outboundMessageRepository) ListUpdatedSince(ctx context.Context, after models.ChangeCursor, settle time.Duration, limit int) ([]*models.OutboundMessage, error) {
+	query := `
+		SELECT id, campaign_id, customer_id, status, COALESCE(rendered_content, ''), last_error, retry_count, content_redacted_at, provider_message_id, created_at, updated_at
+		FROM outbound_messages
+		WHERE (updated_at, id) > ($1, $2)
+			AND updated_at < LOCALTIMESTAMP - make_interval(secs => $3)
+			AND ($5::BIGINT = 0 OR account_id = $5)
+		ORDER BY updated_at, id
+		LIMIT $4`
+
+	rows, err := r.db.QueryContext(ctx, query, after.UpdatedAt, after.ID, settle.Seconds(), limit, accountScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list updated messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages := []*models.OutboundMessage{}
+	for rows.Next() {
+		message := &models.OutboundMessage{}
+		err := rows.Scan(
+			&message.ID,
+			&message.CampaignID,
+			&message.CustomerID,
+			&message.Status,
+			&message.RenderedContent,
+			&message.LastError,
+			&message.RetryCount,
+			&message.ContentRedactedAt,
+			&message.ProviderMessageID,
+			&message.CreatedAt,
+			&message.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan updated message: %w", err)
+		}
+		messages = append(messages, message)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating updated messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// ListReceipts retrieves receipts of messages that have left the queue,
+// changed after the cursor in (updated_at, id) order
+func (r *outboundMessageRepository) ListReceipts(ctx context.Context, after models.ChangeCursor, settle time.Duration, limit int) ([]*models.MessageReceipt, error) {
+	query := `
+		SELECT om.id, om.campaign_id, c.phone, om.status, om.provider_message_id, om.created_at, om.updated_at
+		FROM outbound_messages om
+		JOIN customers c ON c.id = om.customer_id
+		WHERE (om.updated_at, om.id) > ($1, $2)
+			AND om.updated_at < LOCALTIMESTAMP - make_interval(secs => $3)
+			AND om.status IN ($6, $7, $8, $9)
+			AND ($5::BIGINT = 0 OR om.account_id = $5)
+		ORDER BY om.updated_at, om.id
+		LIMIT $4`
+
+	rows, err := r.db.QueryContext(ctx, query, after.UpdatedAt, after.ID, settle.Seconds(), limit, accountScope(ctx),
+		models.MessageStatusSent, models.MessageStatusFailed, models.MessageStatusDelivered, models.MessageStatusUndelivered)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list message receipts: %w", err)
+	}
+	defer rows.Close()
+
+	receipts := []*models.MessageReceipt{}
+	for rows.Next() {
+		receipt := &models.MessageReceipt{}
+		var phone string
+		err := rows.Scan(
+			&receipt.MessageID,
+			&receipt.CampaignID,
+			&phone,
+			&receipt.Status,
+			&receipt.ProviderMessageID,
+			&receipt.CreatedAt,
+			&receipt.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan message receipt: %w", err)
+		}
+		receipt.PhoneHash = models.HashPhone(phone)
+		receipts = append(receipts, receipt)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating message receipts: %w", err)
+	}
+
+	return receipts, nil
+}
+
+// UpdateStatusBatch sets the status of many messages in a single statement and
+// returns the number of rows updated
+func (r *outboundMessageRepository) UpdateStatusBatch(ctx context.Context, ids []int64, status string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	query := `
+		UPDATE outbound_messages
+		SET status = $1
+		WHERE id = ANY($2) AND ($3::BIGINT = 0 OR account_id = $3)`
+
+	result, err := r.db.ExecContext(ctx, query, status, pq.Array(ids), accountScope(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("failed to update outbound message statuses: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// ResetFailedByCampaign moves every failed message of a campaign that still has
+// retries left (retry_count < maxRetry) back to pending and clears its last
+// error. Messages whose content was redacted have nothing left to send and stay
+// failed. The reset messages are unqueued again, so the outbox relay publishes
+// them if the caller does not; their IDs are returned so they can be requeued.
+func (r *outboundMessageRepository) ResetFailedByCampaign(ctx context.Context, campaignID int64, maxRetry int) ([]int64, error) {
+	query := `
+		UPDATE outbound_messages
+		SET status = 'pending', last_error = NULL, queued_at = NULL
+		WHERE campaign_id = $1 AND status = 'failed' AND retry_count < $2 AND content_redacted_at IS NULL
+			AND ($3::BIGINT = 0 OR account_id = $3)
+		RETURNING id`
+
+	rows, err := r.db.QueryContext(ctx, query, campaignID, maxRetry, accountScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to reset failed messages: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan reset message ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reset messages: %w", err)
+	}
+
+	return ids, nil
+}
+
+// SkipPendingByCampaign moves the pending messages of a cancelled campaign to
+// skipped. Messages a worker has already claimed are left to finish.
+func (r *outboundMessageRepository) SkipPendingByCampaign(ctx context.Context, campaignID int64) (int64, error) {
+	query := `
+		UPDATE outbound_messages
+		SET status = 'skipped'
+		WHERE campaign_id = $1 AND status = 'pending' AND ($2::BIGINT = 0 OR account_id = $2)`
+
+	result, err := r.db.ExecContext(ctx, query, campaignID, accountScope(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("failed to skip pending messages: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// RedactContentBefore clears rendered_content of the oldest final messages and
+// records when it happened. Only sent and failed messages are redacted, since
+// pending ones still need their content. SKIP LOCKED keeps the batch from
+// waiting on messages a worker is updating.
+func (r *outboundMessageRepository) RedactContentBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	query := `
+		UPDATE outbound_messages
+		SET rendered_content = NULL, content_redacted_at = CURRENT_TIMESTAMP
+		WHERE id IN (
+			SELECT id
+			FROM outbound_messages
+			WHERE content_redacted_at IS NULL AND status IN ('sent', 'failed', 'delivered', 'undelivered') AND updated_at < $1
+			ORDER BY updated_at ASC
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)`
+
+	result, err := r.db.ExecContext(ctx, query, before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to redact message content: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
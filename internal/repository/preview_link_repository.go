@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// PreviewLinkRepository defines the interface for campaign preview link data access
+type PreviewLinkRepository interface {
+	Create(ctx context.Context, link *models.PreviewLink) error
+	GetByToken(ctx context.Context, token string) (*models.PreviewLink, error)
+	// DeleteByCampaignID revokes every preview link of a campaign and returns how many there were
+	DeleteByCampaignID(ctx context.Context, campaignID int64) (int64, error)
+}
+
+// previewLinkRepository implements PreviewLinkRepository using PostgreSQL
+type previewLinkRepository struct {
+	db *sql.DB
+}
+
+// NewPreviewLinkRepository creates a new preview link repository
+func NewPreviewLinkRepository(db *sql.DB) PreviewLinkRepository {
+	return &previewLinkRepository{db: db}
+}
+
+// Create inserts a new preview link into its campaign's account
+func (r *previewLinkRepository) Create(ctx context.Context, link *models.PreviewLink) error {
+	query := `
+		INSERT INTO campaign_preview_links (token, campaign_id, expires_at, account_id)
+		VALUES ($1, $2, $3, (SELECT account_id FROM campaigns WHERE id = $2))
+		RETURNING created_at`
+
+	err := r.db.QueryRowContext(ctx, query, link.Token, link.CampaignID, link.ExpiresAt).Scan(&link.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create preview link: %w", err)
+	}
+
+	return nil
+}
+
+// GetByToken retrieves a preview link by its token, expired or not. Links are
+// opened without signing in, so the token alone identifies the account.
+func (r *previewLinkRepository) GetByToken(ctx context.Context, token string) (*models.PreviewLink, error) {
+	query := `
+		SELECT token, campaign_id, account_id, expires_at, created_at
+		FROM campaign_preview_links
+		WHERE token = $1`
+
+	link := &models.PreviewLink{}
+	err := r.db.QueryRowContext(ctx, query, token).Scan(
+		&link.Token,
+		&link.CampaignID,
+		&link.AccountID,
+		&link.ExpiresAt,
+		&link.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFoundWithMsg("preview link not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get preview link: %w", err)
+	}
+
+	return link, nil
+}
+
+// DeleteByCampaignID removes all preview links of a campaign
+func (r *previewLinkRepository) DeleteByCampaignID(ctx context.Context, campaignID int64) (int64, error) {
+	query := `DELETE FROM campaign_preview_links WHERE campaign_id = $1 AND ($2::BIGINT = 0 OR account_id = $2)`
+
+	result, err := r.db.ExecContext(ctx, query, campaignID, accountScope(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete preview links: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// ReportsRepository defines the account-wide aggregate queries behind
+// analytics. Each counts the messages of the account of ctx created in
+// [from, to), grouped into periods that date_trunc takes as interval.
+type ReportsRepository interface {
+	// MessageCounts counts messages per period, channel and status. Groups
+	// without messages are left out.
+	MessageCounts(ctx context.Context, interval string, from, to time.Time) ([]*models.AnalyticsMessageCount, error)
+	// CampaignsStarted counts, per period, the campaigns whose first message
+	// was created in it
+	CampaignsStarted(ctx context.Context, interval string, from, to time.Time) ([]*models.AnalyticsCampaignCount, error)
+}
+
+// reportsRepository implements ReportsRepository using PostgreSQL
+type reportsRepository struct {
+	db *sql.DB
+}
+
+// NewReportsRepository creates a new reports repository
+func NewReportsRepository(db *sql.DB) ReportsRepository {
+	return &reportsRepository{db: db}
+}
+
+// MessageCounts reads the period's messages through the (account_id,
+// created_at) index, taking the channel from their campaign
+func (r *reportsRepository) MessageCounts(ctx context.Context, interval string, from, to time.Time) ([]*models.AnalyticsMessageCount, error) {
+	query := `
+		SELECT date_trunc($1, om.created_at) AS start, c.channel, om.status, COUNT(*)
+		FROM outbound_messages om
+		JOIN campaigns c ON c.id = om.campaign_id
+		WHERE om.created_at >= $2 AND om.created_at < $3
+			AND ($4::BIGINT = 0 OR om.account_id = $4)
+		GROUP BY start, c.channel, om.status
+		ORDER BY start ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, interval, from, to, accountScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count messages: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []*models.AnalyticsMessageCount{}
+	for rows.Next() {
+		count := &models.AnalyticsMessageCount{}
+		if err := rows.Scan(&count.Start, &count.Channel, &count.Status, &count.Messages); err != nil {
+			return nil, fmt.Errorf("failed to scan message count: %w", err)
+		}
+		counts = append(counts, count)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating message counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// CampaignsStarted takes the first message of each campaign that created one
+// before to, and counts those created from from on
+func (r *reportsRepository) CampaignsStarted(ctx context.Context, interval string, from, to time.Time) ([]*models.AnalyticsCampaignCount, error) {
+	query := `
+		SELECT date_trunc($1, started_at) AS start, COUNT(*)
+		FROM (
+			SELECT MIN(created_at) AS started_at
+			FROM outbound_messages
+			WHERE created_at < $3 AND ($4::BIGINT = 0 OR account_id = $4)
+			GROUP BY campaign_id
+			HAVING MIN(created_at) >= $2
+		) started
+		GROUP BY start
+		ORDER BY start ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, interval, from, to, accountScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count started campaigns: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []*models.AnalyticsCampaignCount{}
+	for rows.Next() {
+		count := &models.AnalyticsCampaignCount{}
+		if err := rows.Scan(&count.Start, &count.Campaigns); err != nil {
+			return nil, fmt.Errorf("failed to scan campaign count: %w", err)
+		}
+		counts = append(counts, count)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating campaign counts: %w", err)
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// SegmentRepository defines the interface for customer segment data access
+type SegmentRepository interface {
+	Create(ctx context.Context, segment *models.Segment) error
+	GetByID(ctx context.Context, id int64) (*models.Segment, error)
+	List(ctx context.Context, page, pageSize int) ([]*models.Segment, int64, error)
+	Update(ctx context.Context, segment *models.Segment) error
+	Delete(ctx context.Context, id int64) error
+	// GetCount returns the segment's cached customer count, or a not found
+	// error when it has not been counted since it was created or changed
+	GetCount(ctx context.Context, segmentID int64) (*models.SegmentCount, error)
+	// SaveCount caches a customer count of a segment in the account of ctx
+	SaveCount(ctx context.Context, count *models.SegmentCount) error
+	// ListStaleCounts retrieves up to limit segments never counted or last
+	// counted before countedBefore, those counted longest ago first
+	ListStaleCounts(ctx context.Context, countedBefore time.Time, limit int) ([]*models.Segment, error)
+}
+
+// segmentRepository implements SegmentRepository using PostgreSQL
+type segmentRepository struct {
+	db *sql.DB
+}
+
+// NewSegmentRepository creates a new segment repository
+func NewSegmentRepository(db *sql.DB) SegmentRepository {
+	return &segmentRepository{db: db}
+}
+
+// Create inserts a new segment into the account of ctx
+func (r *segmentRepository) Create(ctx context.Context, segment *models.Segment) error {
+	query := `
+		INSERT INTO segments (name, description, filter, account_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query, segment.Name, segment.Description, segment.Filter, ownerAccount(ctx)).
+		Scan(&segment.ID, &segment.CreatedAt, &segment.UpdatedAt)
+	if isUniqueViolation(err) {
+		return models.ErrConflictWithMsg(fmt.Sprintf("segment named %q already exists", segment.Name))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create segment: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a segment by ID
+func (r *segmentRepository) GetByID(ctx context.Context, id int64) (*models.Segment, error) {
+	query := `
+		SELECT id, name, description, filter, created_at, updated_at
+		FROM segments
+		WHERE id = $1 AND ($2::BIGINT = 0 OR account_id = $2)`
+
+	segment := &models.Segment{}
+	err := r.db.QueryRowContext(ctx, query, id, accountScope(ctx)).Scan(
+		&segment.ID,
+		&segment.Name,
+		&segment.Description,
+		&segment.Filter,
+		&segment.CreatedAt,
+		&segment.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFoundWithMsg(fmt.Sprintf("segment with ID %d not found", id))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get segment: %w", err)
+	}
+
+	return segment, nil
+}
+
+// List retrieves a page of segments ordered by name
+func (r *segmentRepository) List(ctx context.Context, page, pageSize int) ([]*models.Segment, int64, error) {
+	models.ValidateAndSetDefaults(&page, &pageSize)
+
+	scope := accountScope(ctx)
+
+	var totalCount int64
+	countQuery := `SELECT COUNT(*) FROM segments WHERE ($1::BIGINT = 0 OR account_id = $1)`
+	if err := r.db.QueryRowContext(ctx, countQuery, scope).Scan(&totalCount); err != nil {
+		return nil, 0, fmt.Errorf("failed to count segments: %w", err)
+	}
+
+	query := `
+		SELECT id, name, description, filter, created_at, updated_at
+		FROM segments
+		WHERE ($3::BIGINT = 0 OR account_id = $3)
+		ORDER BY name ASC, id ASC
+		LIMIT $1 OFFSET $2`
+
+	rows, err := r.db.QueryContext(ctx, query, pageSize, models.CalculateOffset(page, pageSize), scope)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list segments: %w", err)
+	}
+	defer rows.Close()
+
+	segments := []*models.Segment{}
+	for rows.Next() {
+		segment := &models.Segment{}
+		err := rows.Scan(
+			&segment.ID,
+			&segment.Name,
+			&segment.Description,
+			&segment.Filter,
+			&segment.CreatedAt,
+			&segment.UpdatedAt,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan segment: %w", err)
+		}
+		segments = append(segments, segment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating segments: %w", err)
+	}
+
+	return segments, totalCount, nil
+}
+
+// Update replaces a segment's name, description and filter, and drops its
+// cached count, which the new filter may not match
+func (r *segmentRepository) Update(ctx context.Context, segment *models.Segment) error {
+	query := `
+		WITH uncounted AS (
+			DELETE FROM segment_counts
+			WHERE segment_id = $4 AND ($5::BIGINT = 0 OR account_id = $5)
+		)
+		UPDATE segments
+		SET name = $1, description = $2, filter = $3
+		WHERE id = $4 AND ($5::BIGINT = 0 OR account_id = $5)
+		RETURNING created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query, segment.Name, segment.Description, segment.Filter, segment.ID, accountScope(ctx)).
+		Scan(&segment.CreatedAt, &segment.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return models.ErrNotFoundWithMsg(fmt.Sprintf("segment with ID %d not found", segment.ID))
+	}
+	if isUniqueViolation(err) {
+		return models.ErrConflictWithMsg(fmt.Sprintf("segment named %q already exists", segment.Name))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update segment: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a segment. Segments bound as the audience of a campaign that
+// has yet to be sent are kept, so that the campaign can still be sent.
+func (r *segmentRepository) Delete(ctx context.Context, id int64) error {
+	boundQuery := `
+		SELECT COUNT(*) FROM campaigns
+		WHERE audience->>'segment_id' = $1::BIGINT::text AND status IN ('draft', 'scheduled')
+			AND ($2::BIGINT = 0 OR account_id = $2)`
+
+	scope := accountScope(ctx)
+	var bound int64
+	if err := r.db.QueryRowContext(ctx, boundQuery, id, scope).Scan(&bound); err != nil {
+		return fmt.Errorf("failed to check campaigns bound to segment: %w", err)
+	}
+	if bound > 0 {
+		return models.ErrConflictWithMsg(fmt.Sprintf("segment with ID %d is the audience of %d unsent campaigns", id, bound))
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM segments WHERE id = $1 AND ($2::BIGINT = 0 OR account_id = $2)`, id, scope)
+	if err != nil {
+		return fmt.Errorf("failed to delete segment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrNotFoundWithMsg(fmt.Sprintf("segment with ID %d not found", id))
+	}
+
+	return nil
+}
+
+// GetCount retrieves a segment's cached customer count
+func (r *segmentRepository) GetCount(ctx context.Context, segmentID int64) (*models.SegmentCount, error) {
+	query := `
+		SELECT segment_id, customer_count, counted_at
+		FROM segment_counts
+		WHERE segment_id = $1 AND ($2::BIGINT = 0 OR account_id = $2)`
+
+	count := &models.SegmentCount{}
+	err := r.db.QueryRowContext(ctx, query, segmentID, accountScope(ctx)).Scan(&count.SegmentID, &count.CustomerCount, &count.CountedAt)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFoundWithMsg(fmt.Sprintf("segment with ID %d has not been counted", segmentID))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get segment count: %w", err)
+	}
+
+	return count, nil
+}
+
+// SaveCount replaces the segment's cached count. Counts of segments outside
+// the account of ctx are ignored.
+func (r *segmentRepository) SaveCount(ctx context.Context, count *models.SegmentCount) error {
+	query := `
+		INSERT INTO segment_counts (segment_id, account_id, customer_count, counted_at)
+		SELECT id, account_id, $2, $3
+		FROM segments
+		WHERE id = $1 AND ($4::BIGINT = 0 OR account_id = $4)
+		ON CONFLICT (segment_id) DO UPDATE
+		SET customer_count = EXCLUDED.customer_count, counted_at = EXCLUDED.counted_at`
+
+	if _, err := r.db.ExecContext(ctx, query, count.SegmentID, count.CustomerCount, count.CountedAt, accountScope(ctx)); err != nil {
+		return fmt.Errorf("failed to save segment count: %w", err)
+	}
+
+	return nil
+}
+
+// ListStaleCounts reads segments without a count first
+func (r *segmentRepository) ListStaleCounts(ctx context.Context, countedBefore time.Time, limit int) ([]*models.Segment, error) {
+	query := `
+		SELECT s.id, s.account_id, s.name, s.description, s.filter, s.created_at, s.updated_at
+		FROM segments s
+		LEFT JOIN segment_counts sc ON sc.segment_id = s.id
+		WHERE (sc.counted_at IS NULL OR sc.counted_at < $1)
+			AND ($3::BIGINT = 0 OR s.account_id = $3)
+		ORDER BY sc.counted_at ASC NULLS FIRST, s.id ASC
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, countedBefore, limit, accountScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stale segment counts: %w", err)
+	}
+	defer rows.Close()
+
+	segments := []*models.Segment{}
+	for rows.Next() {
+		segment := &models.Segment{}
+		err := rows.Scan(
+			&segment.ID,
+			&segment.AccountID,
+			&segment.Name,
+			&segment.Description,
+			&segment.Filter,
+			&segment.CreatedAt,
+			&segment.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan segment: %w", err)
+		}
+		segments = append(segments, segment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating segments: %w", err)
+	}
+
+	return segments, nil
+}
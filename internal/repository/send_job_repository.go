@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// SendJobRepository defines the interface for send job data access
+type SendJobRepository interface {
+	// Create records a send awaiting a second approval in the account of ctx
+	Create(ctx context.Context, job *models.SendJob) error
+	GetByID(ctx context.Context, id int64) (*models.SendJob, error)
+	// Approve records approverID's approval of a send job still awaiting
+	// one and moves its campaign from awaiting_approval to campaignStatus, in
+	// one transaction. It reports whether it did; nothing is changed when the
+	// job was approved already or its campaign is no longer awaiting approval.
+	// The user who requested the send cannot approve it.
+	Approve(ctx context.Context, id, approverID int64, campaignStatus string) (bool, error)
+}
+
+// sendJobRepository implements SendJobRepository using PostgreSQL
+type sendJobRepository struct {
+	db *sql.DB
+}
+
+// NewSendJobRepository creates a new send job repository
+func NewSendJobRepository(db *sql.DB) SendJobRepository {
+	return &sendJobRepository{db: db}
+}
+
+// sendJobColumns is the column list scanned by scanSendJob
+const sendJobColumns = `id, account_id, campaign_id, status, recipient_count, requested_by, approved_by, approved_at, created_at`
+
+// scanSendJob scans a row selected with sendJobColumns
+func scanSendJob(row interface{ Scan(dest ...any) error }) (*models.SendJob, error) {
+	job := &models.SendJob{}
+	err := row.Scan(
+		&job.ID,
+		&job.AccountID,
+		&job.CampaignID,
+		&job.Status,
+		&job.RecipientCount,
+		&job.RequestedBy,
+		&job.ApprovedBy,
+		&job.ApprovedAt,
+		&job.CreatedAt,
+	)
+	return job, err
+}
+
+// Create inserts a send job
+func (r *sendJobRepository) Create(ctx context.Context, job *models.SendJob) error {
+	query := `
+		INSERT INTO send_jobs (account_id, campaign_id, recipient_count, requested_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + sendJobColumns
+
+	created, err := scanSendJob(r.db.QueryRowContext(ctx, query, ownerAccount(ctx), job.CampaignID, job.RecipientCount, job.RequestedBy))
+	if err != nil {
+		return fmt.Errorf("failed to create send job: %w", err)
+	}
+
+	*job = *created
+	return nil
+}
+
+// GetByID retrieves a send job by ID
+func (r *sendJobRepository) GetByID(ctx context.Context, id int64) (*models.SendJob, error) {
+	query := `SELECT ` + sendJobColumns + `
+		FROM send_jobs
+		WHERE id = $1 AND ($2::BIGINT = 0 OR account_id = $2)`
+
+	job, err := scanSendJob(r.db.QueryRowContext(ctx, query, id, accountScope(ctx)))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFoundWithMsg(fmt.Sprintf("send job with ID %d not found", id))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get send job: %w", err)
+	}
+
+	return job, nil
+}
+
+// Approve moves a send job from awaiting_second_approval to approved, unless
+// approverID requested it, together with its campaign from awaiting_approval
+// to campaignStatus
+func (r *sendJobRepository) Approve(ctx context.Context, id, approverID int64, campaignStatus string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback() // Rollback is safe to call even after Commit
+	}()
+
+	scope := accountScope(ctx)
+	var campaignID int64
+	err = tx.QueryRowContext(ctx, `
+		UPDATE send_jobs
+		SET status = 'approved', approved_by = $2, approved_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'awaiting_second_approval'
+			AND (requested_by IS NULL OR requested_by <> $2)
+			AND ($3::BIGINT = 0 OR account_id = $3)
+		RETURNING campaign_id`, id, approverID, scope).Scan(&campaignID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to approve send job: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE campaigns
+		SET status = $2
+		WHERE id = $1 AND status = 'awaiting_approval' AND ($3::BIGINT = 0 OR account_id = $3)`,
+		campaignID, campaignStatus, scope)
+	if err != nil {
+		return false, fmt.Errorf("failed to update campaign status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
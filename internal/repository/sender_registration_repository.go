@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// SenderRegistrationRepository defines the interface for sender registration data access
+type SenderRegistrationRepository interface {
+	Upsert(ctx context.Context, registration *models.SenderRegistration) error
+	Get(ctx context.Context, senderID, country string) (*models.SenderRegistration, error)
+	ListBySenderID(ctx context.Context, senderID string) ([]*models.SenderRegistration, error)
+	Delete(ctx context.Context, senderID, country string) error
+}
+
+// senderRegistrationRepository implements SenderRegistrationRepository using PostgreSQL
+type senderRegistrationRepository struct {
+	db *sql.DB
+}
+
+// NewSenderRegistrationRepository creates a new sender registration repository
+func NewSenderRegistrationRepository(db *sql.DB) SenderRegistrationRepository {
+	return &senderRegistrationRepository{db: db}
+}
+
+// Upsert creates or replaces a sender's registration for a country in the
+// account of ctx
+func (r *senderRegistrationRepository) Upsert(ctx context.Context, registration *models.SenderRegistration) error {
+	query := `
+		INSERT INTO sender_registrations (sender_id, country, type, registration_id, account_id)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (account_id, sender_id, country) DO UPDATE
+		SET type = EXCLUDED.type,
+			registration_id = EXCLUDED.registration_id
+		RETURNING created_at, updated_at`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		registration.SenderID,
+		registration.Country,
+		registration.Type,
+		registration.RegistrationID,
+		ownerAccount(ctx),
+	).Scan(&registration.CreatedAt, &registration.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to upsert sender registration: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a sender's registration for a country
+func (r *senderRegistrationRepository) Get(ctx context.Context, senderID, country string) (*models.SenderRegistration, error) {
+	query := `
+		SELECT sender_id, country, type, registration_id, created_at, updated_at
+		FROM sender_registrations
+		WHERE sender_id = $1 AND country = $2 AND account_id = $3`
+
+	registration := &models.SenderRegistration{}
+	err := r.db.QueryRowContext(ctx, query, senderID, country, ownerAccount(ctx)).Scan(
+		&registration.SenderID,
+		&registration.Country,
+		&registration.Type,
+		&registration.RegistrationID,
+		&registration.CreatedAt,
+		&registration.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFoundWithMsg(fmt.Sprintf("sender %s is not registered for %s", senderID, country))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sender registration: %w", err)
+	}
+
+	return registration, nil
+}
+
+// ListBySenderID retrieves a sender's registrations ordered by country
+func (r *senderRegistrationRepository) ListBySenderID(ctx context.Context, senderID string) ([]*models.SenderRegistration, error) {
+	query := `
+		SELECT sender_id, country, type, registration_id, created_at, updated_at
+		FROM sender_registrations
+		WHERE sender_id = $1 AND account_id = $2
+		ORDER BY country ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, senderID, ownerAccount(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sender registrations: %w", err)
+	}
+	defer rows.Close()
+
+	registrations := []*models.SenderRegistration{}
+	for rows.Next() {
+		registration := &models.SenderRegistration{}
+		err := rows.Scan(
+			&registration.SenderID,
+			&registration.Country,
+			&registration.Type,
+			&registration.RegistrationID,
+			&registration.CreatedAt,
+			&registration.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sender registration: %w", err)
+		}
+		registrations = append(registrations, registration)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sender registrations: %w", err)
+	}
+
+	return registrations, nil
+}
+
+// Delete removes a sender's registration for a country
+func (r *senderRegistrationRepository) Delete(ctx context.Context, senderID, country string) error {
+	query := `DELETE FROM sender_registrations WHERE sender_id = $1 AND country = $2 AND account_id = $3`
+
+	result, err := r.db.ExecContext(ctx, query, senderID, country, ownerAccount(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to delete sender registration: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrNotFoundWithMsg(fmt.Sprintf("sender %s is not registered for %s", senderID, country))
+	}
+
+	return nil
+}
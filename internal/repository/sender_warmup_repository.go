@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// SenderWarmupRepository defines the interface for sender warm-up policy data access
+type SenderWarmupRepository interface {
+	Upsert(ctx context.Context, warmup *models.SenderWarmup) error
+	GetBySenderID(ctx context.Context, senderID string) (*models.SenderWarmup, error)
+	Delete(ctx context.Context, senderID string) error
+}
+
+// senderWarmupRepository implements SenderWarmupRepository using PostgreSQL
+type senderWarmupRepository struct {
+	db *sql.DB
+}
+
+// NewSenderWarmupRepository creates a new sender warm-up repository
+func NewSenderWarmupRepository(db *sql.DB) SenderWarmupRepository {
+	return &senderWarmupRepository{db: db}
+}
+
+// Upsert creates or replaces the warm-up policy for a sender in the account
+// of ctx
+func (r *senderWarmupRepository) Upsert(ctx context.Context, warmup *models.SenderWarmup) error {
+	query := `
+		INSERT INTO sender_warmups (sender_id, started_on, initial_daily_cap, max_daily_cap, account_id)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (account_id, sender_id) DO UPDATE
+		SET started_on = EXCLUDED.started_on,
+			initial_daily_cap = EXCLUDED.initial_daily_cap,
+			max_daily_cap = EXCLUDED.max_daily_cap
+		RETURNING created_at`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		warmup.SenderID,
+		warmup.StartedOn,
+		warmup.InitialDailyCap,
+		warmup.MaxDailyCap,
+		ownerAccount(ctx),
+	).Scan(&warmup.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to upsert sender warm-up: %w", err)
+	}
+
+	return nil
+}
+
+// GetBySenderID retrieves the warm-up policy for a sender
+func (r *senderWarmupRepository) GetBySenderID(ctx context.Context, senderID string) (*models.SenderWarmup, error) {
+	query := `
+		SELECT sender_id, started_on, initial_daily_cap, max_daily_cap, created_at
+		FROM sender_warmups
+		WHERE sender_id = $1 AND account_id = $2`
+
+	warmup := &models.SenderWarmup{}
+	err := r.db.QueryRowContext(ctx, query, senderID, ownerAccount(ctx)).Scan(
+		&warmup.SenderID,
+		&warmup.StartedOn,
+		&warmup.InitialDailyCap,
+		&warmup.MaxDailyCap,
+		&warmup.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFoundWithMsg(fmt.Sprintf("no warm-up policy for sender %s", senderID))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sender warm-up: %w", err)
+	}
+
+	return warmup, nil
+}
+
+// Delete removes the warm-up policy for a sender, ending its ramp
+func (r *senderWarmupRepository) Delete(ctx context.Context, senderID string) error {
+	query := `DELETE FROM sender_warmups WHERE sender_id = $1 AND account_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, senderID, ownerAccount(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to delete sender warm-up: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrNotFoundWithMsg(fmt.Sprintf("no warm-up policy for sender %s", senderID))
+	}
+
+	return nil
+}
@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// ShortDomainRepository defines the interface for short domain data access
+type ShortDomainRepository interface {
+	// Create adds a short domain to the account of ctx. A default domain
+	// replaces the account's previous default.
+	Create(ctx context.Context, domain *models.ShortDomain) error
+	GetByID(ctx context.Context, id int64) (*models.ShortDomain, error)
+	// List retrieves the account's short domains, the default first
+	List(ctx context.Context) ([]*models.ShortDomain, error)
+	// Update changes whether a short domain is the default and whether it is
+	// enabled. A default domain replaces the account's previous default.
+	Update(ctx context.Context, domain *models.ShortDomain) error
+	Delete(ctx context.Context, id int64) error
+	// RecordCheck saves the result of a health check of a short domain
+	RecordCheck(ctx context.Context, check *models.ShortDomainCheck) error
+	// ListDueForCheck retrieves up to limit enabled short domains never
+	// checked or last checked before checkedBefore, those checked longest ago
+	// first
+	ListDueForCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]*models.ShortDomain, error)
+}
+
+// shortDomainRepository implements ShortDomainRepository using PostgreSQL
+type shortDomainRepository struct {
+	db *sql.DB
+}
+
+// NewShortDomainRepository creates a new short domain repository
+func NewShortDomainRepository(db *sql.DB) ShortDomainRepository {
+	return &shortDomainRepository{db: db}
+}
+
+// shortDomainColumns is the column list scanned by scanShortDomain
+const shortDomainColumns = `id, account_id, domain, is_default, enabled, status, last_error, checked_at, created_at, updated_at`
+
+// scanShortDomain scans a row selected with shortDomainColumns
+func scanShortDomain(row interface{ Scan(dest ...any) error }) (*models.ShortDomain, error) {
+	domain := &models.ShortDomain{}
+	err := row.Scan(
+		&domain.ID,
+		&domain.AccountID,
+		&domain.Domain,
+		&domain.IsDefault,
+		&domain.Enabled,
+		&domain.Status,
+		&domain.LastError,
+		&domain.CheckedAt,
+		&domain.CreatedAt,
+		&domain.UpdatedAt,
+	)
+	return domain, err
+}
+
+// Create inserts a short domain, clearing the account's default first when
+// the new domain is to be the default
+func (r *shortDomainRepository) Create(ctx context.Context, domain *models.ShortDomain) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback() // Rollback is safe to call even after Commit
+	}()
+
+	accountID := ownerAccount(ctx)
+	if domain.IsDefault {
+		clearQuery := `UPDATE short_domains SET is_default = FALSE WHERE account_id = $1 AND is_default`
+		if _, err := tx.ExecContext(ctx, clearQuery, accountID); err != nil {
+			return fmt.Errorf("failed to clear default short domain: %w", err)
+		}
+	}
+
+	query := `
+		INSERT INTO short_domains (account_id, domain, is_default, enabled)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + shortDomainColumns
+
+	created, err := scanShortDomain(tx.QueryRowContext(ctx, query, accountID, domain.Domain, domain.IsDefault, domain.Enabled))
+	if isUniqueViolation(err) {
+		return models.ErrConflictWithMsg(fmt.Sprintf("short domain %q is already registered", domain.Domain))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create short domain: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	*domain = *created
+	return nil
+}
+
+// GetByID retrieves a short domain by ID
+func (r *shortDomainRepository) GetByID(ctx context.Context, id int64) (*models.ShortDomain, error) {
+	query := `SELECT ` + shortDomainColumns + `
+		FROM short_domains
+		WHERE id = $1 AND ($2::BIGINT = 0 OR account_id = $2)`
+
+	domain, err := scanShortDomain(r.db.QueryRowContext(ctx, query, id, accountScope(ctx)))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFoundWithMsg(fmt.Sprintf("short domain with ID %d not found", id))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get short domain: %w", err)
+	}
+
+	return domain, nil
+}
+
+// List retrieves short domains ordered by name after the default
+func (r *shortDomainRepository) List(ctx context.Context) ([]*models.ShortDomain, error) {
+	query := `SELECT ` + shortDomainColumns + `
+		FROM short_domains
+		WHERE ($1::BIGINT = 0 OR account_id = $1)
+		ORDER BY is_default DESC, domain ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, accountScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list short domains: %w", err)
+	}
+	defer rows.Close()
+
+	return scanShortDomains(rows)
+}
+
+// Update saves a short domain's default and enabled flags, clearing the
+// default of its account first when it is to be the default
+func (r *shortDomainRepository) Update(ctx context.Context, domain *models.ShortDomain) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback() // Rollback is safe to call even after Commit
+	}()
+
+	scope := accountScope(ctx)
+	if domain.IsDefault {
+		clearQuery := `
+			UPDATE short_domains SET is_default = FALSE
+			WHERE is_default AND id <> $1
+				AND account_id = (SELECT account_id FROM short_domains WHERE id = $1 AND ($2::BIGINT = 0 OR account_id = $2))`
+		if _, err := tx.ExecContext(ctx, clearQuery, domain.ID, scope); err != nil {
+			return fmt.Errorf("failed to clear default short domain: %w", err)
+		}
+	}
+
+	query := `
+		UPDATE short_domains
+		SET is_default = $2, enabled = $3
+		WHERE id = $1 AND ($4::BIGINT = 0 OR account_id = $4)
+		RETURNING ` + shortDomainColumns
+
+	updated, err := scanShortDomain(tx.QueryRowContext(ctx, query, domain.ID, domain.IsDefault, domain.Enabled, scope))
+	if err == sql.ErrNoRows {
+		return models.ErrNotFoundWithMsg(fmt.Sprintf("short domain with ID %d not found", domain.ID))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update short domain: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	*domain = *updated
+	return nil
+}
+
+// Delete removes a short domain. Campaigns that chose it go back to the
+// account's default.
+func (r *shortDomainRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM short_domains WHERE id = $1 AND ($2::BIGINT = 0 OR account_id = $2)`, id, accountScope(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to delete short domain: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrNotFoundWithMsg(fmt.Sprintf("short domain with ID %d not found", id))
+	}
+
+	return nil
+}
+
+// RecordCheck saves a health check result. Results for short domains outside
+// the account of ctx, or deleted since the check started, are ignored.
+func (r *shortDomainRepository) RecordCheck(ctx context.Context, check *models.ShortDomainCheck) error {
+	query := `
+		UPDATE short_domains
+		SET status = $2, last_error = $3, checked_at = $4
+		WHERE id = $1 AND ($5::BIGINT = 0 OR account_id = $5)`
+
+	if _, err := r.db.ExecContext(ctx, query, check.DomainID, check.Status, check.Error, check.CheckedAt, accountScope(ctx)); err != nil {
+		return fmt.Errorf("failed to record short domain check: %w", err)
+	}
+
+	return nil
+}
+
+// ListDueForCheck reads short domains never checked first
+func (r *shortDomainRepository) ListDueForCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]*models.ShortDomain, error) {
+	query := `SELECT ` + shortDomainColumns + `
+		FROM short_domains
+		WHERE enabled AND (checked_at IS NULL OR checked_at < $1)
+			AND ($3::BIGINT = 0 OR account_id = $3)
+		ORDER BY checked_at ASC NULLS FIRST, id ASC
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, checkedBefore, limit, accountScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list short domains due for a check: %w", err)
+	}
+	defer rows.Close()
+
+	return scanShortDomains(rows)
+}
+
+// scanShortDomains scans every row of a query selecting shortDomainColumns
+func scanShortDomains(rows *sql.Rows) ([]*models.ShortDomain, error) {
+	domains := []*models.ShortDomain{}
+	for rows.Next() {
+		domain, err := scanShortDomain(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan short domain: %w", err)
+		}
+		domains = append(domains, domain)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating short domains: %w", err)
+	}
+
+	return domains, nil
+}
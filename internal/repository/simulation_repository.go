@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// SimulationRepository defines the interface for campaign simulation data access
+type SimulationRepository interface {
+	Create(ctx context.Context, run *models.SimulationRun) error
+	Complete(ctx context.Context, run *models.SimulationRun) error
+	Fail(ctx context.Context, id int64, reason string) error
+	GetByID(ctx context.Context, campaignID, id int64) (*models.SimulationRun, error)
+	InsertMessages(ctx context.Context, messages []*models.SimulatedMessage) error
+}
+
+// simulationRepository implements SimulationRepository using PostgreSQL
+type simulationRepository struct {
+	db *sql.DB
+}
+
+// NewSimulationRepository creates a new simulation repository
+func NewSimulationRepository(db *sql.DB) SimulationRepository {
+	return &simulationRepository{db: db}
+}
+
+// Create inserts a new running simulation into its campaign's account
+func (r *simulationRepository) Create(ctx context.Context, run *models.SimulationRun) error {
+	query := `
+		INSERT INTO simulation_runs (campaign_id, status, worker_concurrency, account_id)
+		VALUES ($1, $2, $3, (SELECT account_id FROM campaigns WHERE id = $1))
+		RETURNING id, created_at`
+
+	err := r.db.QueryRowContext(ctx, query, run.CampaignID, run.Status, run.WorkerConcurrency).
+		Scan(&run.ID, &run.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create simulation: %w", err)
+	}
+
+	return nil
+}
+
+// Complete stores the results of a finished simulation
+func (r *simulationRepository) Complete(ctx context.Context, run *models.SimulationRun) error {
+	query := `
+		UPDATE simulation_runs
+		SET status = 'completed', audience_size = $1, simulated_sent = $2, simulated_failed = $3,
+			render_ms = $4, send_ms = $5, avg_send_latency_ms = $6, projected_duration_ms = $7,
+			completed_at = CURRENT_TIMESTAMP
+		WHERE id = $8
+		RETURNING completed_at`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		run.AudienceSize,
+		run.SimulatedSent,
+		run.SimulatedFailed,
+		run.RenderMs,
+		run.SendMs,
+		run.AvgSendLatencyMs,
+		run.ProjectedDurationMs,
+		run.ID,
+	).Scan(&run.CompletedAt)
+	if err != nil {
+		return fmt.Errorf("failed to complete simulation: %w", err)
+	}
+
+	run.Status = models.SimulationStatusCompleted
+	return nil
+}
+
+// Fail marks a simulation as failed with the reason
+func (r *simulationRepository) Fail(ctx context.Context, id int64, reason string) error {
+	query := `
+		UPDATE simulation_runs
+		SET status = 'failed', error = $1, completed_at = CURRENT_TIMESTAMP
+		WHERE id = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, reason, id); err != nil {
+		return fmt.Errorf("failed to mark simulation failed: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a simulation of a campaign
+func (r *simulationRepository) GetByID(ctx context.Context, campaignID, id int64) (*models.SimulationRun, error) {
+	query := `
+		SELECT id, campaign_id, status, audience_size, simulated_sent, simulated_failed,
+			render_ms, send_ms, avg_send_latency_ms, worker_concurrency, projected_duration_ms,
+			error, created_at, completed_at
+		FROM simulation_runs
+		WHERE id = $1 AND campaign_id = $2 AND ($3::BIGINT = 0 OR account_id = $3)`
+
+	run := &models.SimulationRun{}
+	err := r.db.QueryRowContext(ctx, query, id, campaignID, accountScope(ctx)).Scan(
+		&run.ID,
+		&run.CampaignID,
+		&run.Status,
+		&run.AudienceSize,
+		&run.SimulatedSent,
+		&run.SimulatedFailed,
+		&run.RenderMs,
+		&run.SendMs,
+		&run.AvgSendLatencyMs,
+		&run.WorkerConcurrency,
+		&run.ProjectedDurationMs,
+		&run.Error,
+		&run.CreatedAt,
+		&run.CompletedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFoundWithMsg(fmt.Sprintf("simulation with ID %d not found", id))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get simulation: %w", err)
+	}
+
+	return run, nil
+}
+
+// InsertMessages inserts simulated messages in a single transaction
+func (r *simulationRepository) InsertMessages(ctx context.Context, messages []*models.SimulatedMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback() // Rollback is safe to call even after Commit
+	}()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO simulated_messages (simulation_id, customer_id, status, rendered_content, last_error, latency_ms)
+		VALUES ($1, $2, $3, $4, $5, $6)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, message := range messages {
+		_, err := stmt.ExecContext(
+			ctx,
+			message.SimulationID,
+			message.CustomerID,
+			message.Status,
+			message.RenderedContent,
+			message.LastError,
+			message.LatencyMs,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert simulated message: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// TemplateCatalogRepository defines the interface for reading the template
+// catalog. The catalog is shared by every account and is not written through
+// the API.
+type TemplateCatalogRepository interface {
+	// List retrieves the catalog, of one category when category is not empty
+	List(ctx context.Context, category string) ([]*models.CatalogTemplate, error)
+	GetBySlug(ctx context.Context, slug string) (*models.CatalogTemplate, error)
+}
+
+// templateCatalogRepository implements TemplateCatalogRepository using PostgreSQL
+type templateCatalogRepository struct {
+	db *sql.DB
+}
+
+// NewTemplateCatalogRepository creates a new template catalog repository
+func NewTemplateCatalogRepository(db *sql.DB) TemplateCatalogRepository {
+	return &templateCatalogRepository{db: db}
+}
+
+// List retrieves catalog templates ordered by category and name
+func (r *templateCatalogRepository) List(ctx context.Context, category string) ([]*models.CatalogTemplate, error) {
+	query := `
+		SELECT slug, name, category, description, channel, content, created_at
+		FROM template_catalog
+		WHERE ($1 = '' OR category = $1)
+		ORDER BY category ASC, name ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, category)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list catalog templates: %w", err)
+	}
+	defer rows.Close()
+
+	templates := []*models.CatalogTemplate{}
+	for rows.Next() {
+		template := &models.CatalogTemplate{}
+		err := rows.Scan(
+			&template.Slug,
+			&template.Name,
+			&template.Category,
+			&template.Description,
+			&template.Channel,
+			&template.Content,
+			&template.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan catalog template: %w", err)
+		}
+		templates = append(templates, template)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating catalog templates: %w", err)
+	}
+
+	return templates, nil
+}
+
+// GetBySlug retrieves one catalog template
+func (r *templateCatalogRepository) GetBySlug(ctx context.Context, slug string) (*models.CatalogTemplate, error) {
+	query := `
+		SELECT slug, name, category, description, channel, content, created_at
+		FROM template_catalog
+		WHERE slug = $1`
+
+	template := &models.CatalogTemplate{}
+	err := r.db.QueryRowContext(ctx, query, slug).Scan(
+		&template.Slug,
+		&template.Name,
+		&template.Category,
+		&template.Description,
+		&template.Channel,
+		&template.Content,
+		&template.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFoundWithMsg(fmt.Sprintf("catalog template %q not found", slug))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get catalog template: %w", err)
+	}
+
+	return template, nil
+}
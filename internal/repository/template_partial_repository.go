@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// TemplatePartialRepository defines the interface for template partial data access
+type TemplatePartialRepository interface {
+	Create(ctx context.Context, partial *models.TemplatePartial) error
+	GetByName(ctx context.Context, name string) (*models.TemplatePartial, error)
+	// GetByNames returns the partials that exist among names, keyed by name
+	GetByNames(ctx context.Context, names []string) (map[string]*models.TemplatePartial, error)
+	List(ctx context.Context) ([]*models.TemplatePartial, error)
+	Update(ctx context.Context, partial *models.TemplatePartial) error
+	Delete(ctx context.Context, name string) error
+}
+
+// templatePartialRepository implements TemplatePartialRepository using PostgreSQL
+type templatePartialRepository struct {
+	db *sql.DB
+}
+
+// NewTemplatePartialRepository creates a new template partial repository
+func NewTemplatePartialRepository(db *sql.DB) TemplatePartialRepository {
+	return &templatePartialRepository{db: db}
+}
+
+// Create inserts a new partial into the account of ctx. Partial names are
+// unique per account, so every query here acts on the account of ctx alone.
+func (r *templatePartialRepository) Create(ctx context.Context, partial *models.TemplatePartial) error {
+	query := `
+		INSERT INTO template_partials (name, content, account_id)
+		VALUES ($1, $2, $3)
+		RETURNING created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query, partial.Name, partial.Content, ownerAccount(ctx)).
+		Scan(&partial.CreatedAt, &partial.UpdatedAt)
+	if isUniqueViolation(err) {
+		return models.ErrConflictWithMsg(fmt.Sprintf("partial %q already exists", partial.Name))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create template partial: %w", err)
+	}
+
+	return nil
+}
+
+// GetByName retrieves a partial by name
+func (r *templatePartialRepository) GetByName(ctx context.Context, name string) (*models.TemplatePartial, error) {
+	query := `
+		SELECT name, content, created_at, updated_at
+		FROM template_partials
+		WHERE name = $1 AND account_id = $2`
+
+	partial := &models.TemplatePartial{}
+	err := r.db.QueryRowContext(ctx, query, name, ownerAccount(ctx)).Scan(
+		&partial.Name,
+		&partial.Content,
+		&partial.CreatedAt,
+		&partial.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFoundWithMsg(fmt.Sprintf("partial %q not found", name))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template partial: %w", err)
+	}
+
+	return partial, nil
+}
+
+// GetByNames retrieves several partials in one query
+func (r *templatePartialRepository) GetByNames(ctx context.Context, names []string) (map[string]*models.TemplatePartial, error) {
+	partials := make(map[string]*models.TemplatePartial, len(names))
+	if len(names) == 0 {
+		return partials, nil
+	}
+
+	query := `
+		SELECT name, content, created_at, updated_at
+		FROM template_partials
+		WHERE name = ANY($1) AND account_id = $2`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(names), ownerAccount(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template partials: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		partial := &models.TemplatePartial{}
+		if err := rows.Scan(&partial.Name, &partial.Content, &partial.CreatedAt, &partial.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan template partial: %w", err)
+		}
+		partials[partial.Name] = partial
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating template partials: %w", err)
+	}
+
+	return partials, nil
+}
+
+// List retrieves every partial ordered by name
+func (r *templatePartialRepository) List(ctx context.Context) ([]*models.TemplatePartial, error) {
+	query := `
+		SELECT name, content, created_at, updated_at
+		FROM template_partials
+		WHERE account_id = $1
+		ORDER BY name ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, ownerAccount(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list template partials: %w", err)
+	}
+	defer rows.Close()
+
+	partials := []*models.TemplatePartial{}
+	for rows.Next() {
+		partial := &models.TemplatePartial{}
+		if err := rows.Scan(&partial.Name, &partial.Content, &partial.CreatedAt, &partial.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan template partial: %w", err)
+		}
+		partials = append(partials, partial)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating template partials: %w", err)
+	}
+
+	return partials, nil
+}
+
+// Update replaces a partial's content
+func (r *templatePartialRepository) Update(ctx context.Context, partial *models.TemplatePartial) error {
+	query := `
+		UPDATE template_partials
+		SET content = $2
+		WHERE name = $1 AND account_id = $3
+		RETURNING created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query, partial.Name, partial.Content, ownerAccount(ctx)).
+		Scan(&partial.CreatedAt, &partial.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return models.ErrNotFoundWithMsg(fmt.Sprintf("partial %q not found", partial.Name))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update template partial: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a partial unless a campaign that has not been sent yet includes it
+func (r *templatePartialRepository) Delete(ctx context.Context, name string) error {
+	// strpos rather than LIKE, as '_' in a name is a LIKE wildcard
+	usedQuery := `
+		SELECT COUNT(*) FROM campaigns
+		WHERE strpos(base_template, '{>' || $1 || '}') > 0 AND status IN ('draft', 'scheduled')
+			AND account_id = $2`
+
+	accountID := ownerAccount(ctx)
+	var used int64
+	if err := r.db.QueryRowContext(ctx, usedQuery, name, accountID).Scan(&used); err != nil {
+		return fmt.Errorf("failed to check campaigns using partial: %w", err)
+	}
+	if used > 0 {
+		return models.ErrConflictWithMsg(fmt.Sprintf("partial %q is included by %d unsent campaigns", name, used))
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM template_partials WHERE name = $1 AND account_id = $2`, name, accountID)
+	if err != nil {
+		return fmt.Errorf("failed to delete template partial: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrNotFoundWithMsg(fmt.Sprintf("partial %q not found", name))
+	}
+
+	return nil
+}
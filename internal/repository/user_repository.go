@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// UserRepository defines the interface for user data access
+type UserRepository interface {
+	Create(ctx context.Context, user *models.User) error
+	GetByID(ctx context.Context, id int64) (*models.User, error)
+	// GetByEmail looks the email up across all accounts: emails are unique
+	// across them, and sign-in does not know the account yet
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	List(ctx context.Context) ([]*models.User, error)
+	UpdateRole(ctx context.Context, id int64, role string) error
+	Delete(ctx context.Context, id int64) error
+}
+
+// userRepository implements UserRepository using PostgreSQL
+type userRepository struct {
+	db *sql.DB
+}
+
+// NewUserRepository creates a new user repository
+func NewUserRepository(db *sql.DB) UserRepository {
+	return &userRepository{db: db}
+}
+
+// Create inserts a new user into the account of ctx
+func (r *userRepository) Create(ctx context.Context, user *models.User) error {
+	query := `
+		INSERT INTO users (account_id, email, password_hash, role)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, account_id, created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query, ownerAccount(ctx), user.Email, user.PasswordHash, user.Role).
+		Scan(&user.ID, &user.AccountID, &user.CreatedAt, &user.UpdatedAt)
+	if isUniqueViolation(err) {
+		return models.ErrConflictWithMsg(fmt.Sprintf("user %s already exists", user.Email))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a user by ID
+func (r *userRepository) GetByID(ctx context.Context, id int64) (*models.User, error) {
+	user, err := r.get(ctx, `WHERE id = $1 AND ($2::BIGINT = 0 OR account_id = $2)`, id, accountScope(ctx))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFoundWithMsg(fmt.Sprintf("user with ID %d not found", id))
+	}
+	return user, err
+}
+
+// GetByEmail retrieves a user by email
+func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	user, err := r.get(ctx, `WHERE email = $1`, email)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFoundWithMsg(fmt.Sprintf("user %s not found", email))
+	}
+	return user, err
+}
+
+// get retrieves the user matching where, returning sql.ErrNoRows unwrapped
+func (r *userRepository) get(ctx context.Context, where string, args ...interface{}) (*models.User, error) {
+	query := `
+		SELECT id, account_id, email, password_hash, role, created_at, updated_at
+		FROM users ` + where
+
+	user := &models.User{}
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(
+		&user.ID,
+		&user.AccountID,
+		&user.Email,
+		&user.PasswordHash,
+		&user.Role,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+// List retrieves the users of the account ordered by email
+func (r *userRepository) List(ctx context.Context) ([]*models.User, error) {
+	query := `
+		SELECT id, account_id, email, password_hash, role, created_at, updated_at
+		FROM users
+		WHERE $1::BIGINT = 0 OR account_id = $1
+		ORDER BY email ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, accountScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []*models.User{}
+	for rows.Next() {
+		user := &models.User{}
+		err := rows.Scan(
+			&user.ID,
+			&user.AccountID,
+			&user.Email,
+			&user.PasswordHash,
+			&user.Role,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating users: %w", err)
+	}
+
+	return users, nil
+}
+
+// UpdateRole changes a user's role
+func (r *userRepository) UpdateRole(ctx context.Context, id int64, role string) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE users SET role = $2 WHERE id = $1 AND ($3::BIGINT = 0 OR account_id = $3)`, id, role, accountScope(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to update user role: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrNotFoundWithMsg(fmt.Sprintf("user with ID %d not found", id))
+	}
+
+	return nil
+}
+
+// Delete removes a user
+func (r *userRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM users WHERE id = $1 AND ($2::BIGINT = 0 OR account_id = $2)`, id, accountScope(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrNotFoundWithMsg(fmt.Sprintf("user with ID %d not found", id))
+	}
+
+	return nil
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/ratelimit"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// maxDailySendCap bounds an account's daily send cap
+const maxDailySendCap = 10_000_000
+
+// AccountService manages the tenants of a deployment
+type AccountService interface {
+	// Create adds an account along with its first admin
+	Create(ctx context.Context, req *CreateAccountRequest) (*AccountResult, error)
+	List(ctx context.Context) ([]*models.Account, error)
+	// SetDailySendCap changes how many messages the account may send per day
+	SetDailySendCap(ctx context.Context, id int64, req *SetDailySendCapRequest) (*models.Account, error)
+	// Usage reports the account's sends today against its daily send cap
+	Usage(ctx context.Context, id int64) (*AccountUsage, error)
+	// CustomerAttributes returns the customer attribute keys the account's
+	// templates may use
+	CustomerAttributes(ctx context.Context, id int64) ([]string, error)
+	// SetCustomerAttributes replaces the customer attribute keys the
+	// account's templates may use
+	SetCustomerAttributes(ctx context.Context, id int64, req *SetCustomerAttributesRequest) ([]string, error)
+	// BouncePolicy returns how the account's bounced messages are handled
+	BouncePolicy(ctx context.Context, id int64) (*models.BouncePolicy, error)
+	// SetBouncePolicy changes how the account's bounced messages are handled
+	SetBouncePolicy(ctx context.Context, id int64, req *SetBouncePolicyRequest) (*models.BouncePolicy, error)
+}
+
+type accountService struct {
+	accountRepo repository.AccountRepository
+	quota       ratelimit.Quota
+	now         func() time.Time
+	logger      *slog.Logger
+}
+
+// NewAccountService creates a new account service. quota holds the daily send
+// counters that workers take from.
+func NewAccountService(accountRepo repository.AccountRepository, quota ratelimit.Quota, logger *slog.Logger) AccountService {
+	return &accountService{
+		accountRepo: accountRepo,
+		quota:       quota,
+		now:         time.Now,
+		logger:      logger,
+	}
+}
+
+// Create validates the account and its admin and inserts both
+func (s *accountService) Create(ctx context.Context, req *CreateAccountRequest) (*AccountResult, error) {
+	account := &models.Account{Name: strings.TrimSpace(req.Name)}
+	if err := account.Validate(); err != nil {
+		return nil, err
+	}
+
+	admin, err := newUser(normalizeEmail(req.AdminEmail), req.AdminPassword, models.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.accountRepo.Create(ctx, account, admin); err != nil {
+		s.logger.Error("failed to create account", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	s.logger.Info("account created",
+		slog.Int64("account_id", account.ID),
+		slog.Int64("admin_user_id", admin.ID),
+	)
+
+	return &AccountResult{Account: account, Admin: admin}, nil
+}
+
+// List retrieves every account
+func (s *accountService) List(ctx context.Context) ([]*models.Account, error) {
+	return s.accountRepo.List(ctx)
+}
+
+// SetDailySendCap sets or removes the daily send cap of an account. A lower
+// cap applies to the rest of today; campaigns paused at the old cap are still
+// resumed the next day.
+func (s *accountService) SetDailySendCap(ctx context.Context, id int64, req *SetDailySendCapRequest) (*models.Account, error) {
+	if req.DailySendCap != nil && (*req.DailySendCap <= 0 || *req.DailySendCap > maxDailySendCap) {
+		return nil, models.ErrInvalidInput(fmt.Sprintf("daily_send_cap must be between 1 and %d", maxDailySendCap))
+	}
+
+	if err := s.accountRepo.SetDailySendCap(ctx, id, req.DailySendCap); err != nil {
+		return nil, err
+	}
+
+	account, err := s.accountRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("account daily send cap updated",
+		slog.Int64("account_id", id),
+		slog.Any("daily_send_cap", req.DailySendCap),
+	)
+
+	return account, nil
+}
+
+// Usage reads today's send count of an account from the workers' counters
+func (s *accountService) Usage(ctx context.Context, id int64) (*AccountUsage, error) {
+	account, err := s.accountRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	day := models.StartOfDay(s.now())
+
+	sent, err := s.quota.Used(ctx, models.DailySendQuotaKey(account.ID), day)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &AccountUsage{
+		AccountID:    account.ID,
+		Day:          day.Format(time.DateOnly),
+		SentToday:    sent,
+		DailySendCap: account.DailySendCap,
+		ResetsAt:     day.Add(24 * time.Hour),
+	}
+	if account.DailySendCap != nil {
+		remaining := max(*account.DailySendCap-sent, 0)
+		usage.Remaining = &remaining
+	}
+
+	return usage, nil
+}
+
+// CustomerAttributes returns the customer attribute keys an account declares
+func (s *accountService) CustomerAttributes(ctx context.Context, id int64) ([]string, error) {
+	account, err := s.accountRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return account.CustomerAttributes, nil
+}
+
+// SetCustomerAttributes validates and stores the customer attribute keys of an
+// account, sorted and without duplicates. Removing a key does not touch
+// customers' attributes or existing campaigns, whose templates still render
+// it; only templates validated afterwards reject it.
+func (s *accountService) SetCustomerAttributes(ctx context.Context, id int64, req *SetCustomerAttributesRequest) ([]string, error) {
+	keys := slices.Clone(req.Attributes)
+	if keys == nil {
+		keys = []string{}
+	}
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+
+	if len(keys) > models.MaxCustomerAttributes {
+		return nil, models.ErrInvalidInput(fmt.Sprintf("an account can declare at most %d customer attributes", models.MaxCustomerAttributes))
+	}
+	for _, key := range keys {
+		if err := models.ValidateAttributeKey(key); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.accountRepo.SetCustomerAttributes(ctx, id, keys); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("account customer attributes updated",
+		slog.Int64("account_id", id),
+		slog.Any("attributes", keys),
+	)
+
+	return keys, nil
+}
+
+// BouncePolicy returns the bounce policy of an account
+func (s *accountService) BouncePolicy(ctx context.Context, id int64) (*models.BouncePolicy, error) {
+	account, err := s.accountRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &account.BouncePolicy, nil
+}
+
+// SetBouncePolicy applies the request's fields over the account's current
+// bounce policy and stores the result. Soft bounces already scheduled keep
+// their re-attempt time; the new policy applies to bounces reported after it.
+func (s *accountService) SetBouncePolicy(ctx context.Context, id int64, req *SetBouncePolicyRequest) (*models.BouncePolicy, error) {
+	account, err := s.accountRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := account.BouncePolicy
+	if req.SoftRetryHours != nil {
+		policy.SoftRetryHours = *req.SoftRetryHours
+	}
+	if req.SoftMaxRetries != nil {
+		policy.SoftMaxRetries = *req.SoftMaxRetries
+	}
+	if req.SuppressHard != nil {
+		policy.SuppressHard = *req.SuppressHard
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.accountRepo.SetBouncePolicy(ctx, id, policy); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("account bounce policy updated",
+		slog.Int64("account_id", id),
+		slog.Int("soft_retry_hours", policy.SoftRetryHours),
+		slog.Int("soft_max_retries", policy.SoftMaxRetries),
+		slog.Bool("suppress_hard", policy.SuppressHard),
+	)
+
+	return &policy, nil
+}
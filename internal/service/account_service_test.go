@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestAccountService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	accountRepo := mocks.NewMockAccountRepository(ctrl)
+	svc := NewAccountService(accountRepo, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	accountRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, account *models.Account, admin *models.User) error {
+			account.ID = 2
+			admin.ID = 11
+			admin.AccountID = account.ID
+			return nil
+		})
+
+	result, err := svc.Create(context.Background(), &CreateAccountRequest{
+		Name:          " Acme Retail ",
+		AdminEmail:    "Owner@Acme.example",
+		AdminPassword: "correct horse battery",
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if result.Name != "Acme Retail" || result.Admin.Email != "owner@acme.example" || result.Admin.Role != models.RoleAdmin {
+		t.Errorf("Create() = %+v with admin %+v, want trimmed name and a normalized admin", result.Account, result.Admin)
+	}
+	if result.Admin.AccountID != 2 {
+		t.Errorf("admin account = %d, want 2", result.Admin.AccountID)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(result.Admin.PasswordHash), []byte("correct horse battery")) != nil {
+		t.Error("admin password hash does not match the password")
+	}
+
+	invalid := []*CreateAccountRequest{
+		{Name: "  ", AdminEmail: "owner@acme.example", AdminPassword: "correct horse battery"},
+		{Name: "Acme", AdminEmail: "not-an-email", AdminPassword: "correct horse battery"},
+		{Name: "Acme", AdminEmail: "owner@acme.example", AdminPassword: "short"},
+	}
+	for _, req := range invalid {
+		var appErr *models.AppError
+		if _, err := svc.Create(context.Background(), req); !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+			t.Errorf("Create(%+v) error = %v, want INVALID_INPUT", req, err)
+		}
+	}
+}
+
+// fixedQuota reports a fixed usage per key
+type fixedQuota map[string]int
+
+func (q fixedQuota) Take(ctx context.Context, key string, windowStart time.Time, window time.Duration, limit int) (bool, error) {
+	return q[key] < limit, nil
+}
+func (q fixedQuota) Used(ctx context.Context, key string, windowStart time.Time) (int, error) {
+	return q[key], nil
+}
+func (q fixedQuota) Refund(ctx context.Context, key string, windowStart time.Time) error {
+	return nil
+}
+func (q fixedQuota) Close() error { return nil }
+
+func TestAccountService_Usage(t *testing.T) {
+	now := time.Date(2026, 10, 16, 15, 4, 0, 0, time.UTC)
+	dailyCap := 1000
+
+	tests := []struct {
+		name          string
+		account       *models.Account
+		sent          int
+		wantRemaining *int
+	}{
+		{name: "under cap", account: &models.Account{ID: 2, DailySendCap: &dailyCap}, sent: 640, wantRemaining: intPtr(360)},
+		{name: "cap lowered below usage", account: &models.Account{ID: 2, DailySendCap: &dailyCap}, sent: 1200, wantRemaining: intPtr(0)},
+		{name: "no cap", account: &models.Account{ID: 2}, sent: 5000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			accountRepo := mocks.NewMockAccountRepository(ctrl)
+			accountRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(tt.account, nil)
+
+			svc := &accountService{
+				accountRepo: accountRepo,
+				quota:       fixedQuota{models.DailySendQuotaKey(2): tt.sent},
+				now:         func() time.Time { return now },
+				logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+			}
+
+			usage, err := svc.Usage(context.Background(), 2)
+			if err != nil {
+				t.Fatalf("Usage() error = %v", err)
+			}
+			if usage.Day != "2026-10-16" || usage.SentToday != tt.sent || !usage.ResetsAt.Equal(time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)) {
+				t.Errorf("Usage() = %+v, want %d sent on 2026-10-16", usage, tt.sent)
+			}
+			if (usage.Remaining == nil) != (tt.wantRemaining == nil) || usage.Remaining != nil && *usage.Remaining != *tt.wantRemaining {
+				t.Errorf("Remaining = %v, want %v", usage.Remaining, tt.wantRemaining)
+			}
+		})
+	}
+}
+
+func TestAccountService_SetDailySendCap(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	accountRepo := mocks.NewMockAccountRepository(ctrl)
+	svc := NewAccountService(accountRepo, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	dailyCap := 5000
+	accountRepo.EXPECT().SetDailySendCap(gomock.Any(), int64(2), &dailyCap).Return(nil)
+	accountRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&models.Account{ID: 2, DailySendCap: &dailyCap}, nil)
+
+	account, err := svc.SetDailySendCap(context.Background(), 2, &SetDailySendCapRequest{DailySendCap: &dailyCap})
+	if err != nil {
+		t.Fatalf("SetDailySendCap() error = %v", err)
+	}
+	if account.DailySendCap == nil || *account.DailySendCap != dailyCap {
+		t.Errorf("DailySendCap = %v, want %d", account.DailySendCap, dailyCap)
+	}
+
+	// Nothing is stored for an invalid cap
+	for _, invalid := range []int{0, -1, maxDailySendCap + 1} {
+		var appErr *models.AppError
+		if _, err := svc.SetDailySendCap(context.Background(), 2, &SetDailySendCapRequest{DailySendCap: &invalid}); !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+			t.Errorf("SetDailySendCap(%d) error = %v, want INVALID_INPUT", invalid, err)
+		}
+	}
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func TestAccountService_SetCustomerAttributes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	accountRepo := mocks.NewMockAccountRepository(ctrl)
+	svc := NewAccountService(accountRepo, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	want := []string{"loyalty_tier", "shoe_size"}
+	accountRepo.EXPECT().SetCustomerAttributes(gomock.Any(), int64(2), want).Return(nil)
+
+	got, err := svc.SetCustomerAttributes(context.Background(), 2, &SetCustomerAttributesRequest{
+		Attributes: []string{"shoe_size", "loyalty_tier", "shoe_size"},
+	})
+	if err != nil {
+		t.Fatalf("SetCustomerAttributes() error = %v", err)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("SetCustomerAttributes() = %v, want %v", got, want)
+	}
+
+	// Nothing is stored for an invalid key
+	for _, invalid := range []string{"", "Tier", "2fa", "first_name", "tier-level"} {
+		var appErr *models.AppError
+		if _, err := svc.SetCustomerAttributes(context.Background(), 2, &SetCustomerAttributesRequest{Attributes: []string{invalid}}); !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+			t.Errorf("SetCustomerAttributes(%q) error = %v, want INVALID_INPUT", invalid, err)
+		}
+	}
+}
+
+func TestAccountService_SetBouncePolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	accountRepo := mocks.NewMockAccountRepository(ctrl)
+	svc := NewAccountService(accountRepo, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	current := models.BouncePolicy{SoftRetryHours: 4, SoftMaxRetries: 2, SuppressHard: true}
+	accountRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&models.Account{ID: 2, BouncePolicy: current}, nil).AnyTimes()
+
+	// Omitted fields keep their current value
+	want := models.BouncePolicy{SoftRetryHours: 12, SoftMaxRetries: 2, SuppressHard: false}
+	accountRepo.EXPECT().SetBouncePolicy(gomock.Any(), int64(2), want).Return(nil)
+
+	hours, suppress := 12, false
+	got, err := svc.SetBouncePolicy(context.Background(), 2, &SetBouncePolicyRequest{SoftRetryHours: &hours, SuppressHard: &suppress})
+	if err != nil {
+		t.Fatalf("SetBouncePolicy() error = %v", err)
+	}
+	if *got != want {
+		t.Errorf("SetBouncePolicy() = %+v, want %+v", *got, want)
+	}
+
+	// Nothing is stored for a policy out of bounds
+	for _, req := range []*SetBouncePolicyRequest{
+		{SoftRetryHours: intPtr(0)},
+		{SoftRetryHours: intPtr(models.MaxSoftBounceRetryHours + 1)},
+		{SoftMaxRetries: intPtr(-1)},
+		{SoftMaxRetries: intPtr(models.MaxSoftBounceRetries + 1)},
+	} {
+		var appErr *models.AppError
+		if _, err := svc.SetBouncePolicy(context.Background(), 2, req); !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+			t.Errorf("SetBouncePolicy(%+v) error = %v, want INVALID_INPUT", req, err)
+		}
+	}
+}
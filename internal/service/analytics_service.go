@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// defaultAnalyticsWeeks is how many weeks a weekly overview covers without
+// dates
+const defaultAnalyticsWeeks = 12
+
+// AnalyticsService builds account-wide reports for dashboards
+type AnalyticsService interface {
+	Overview(ctx context.Context, req *AnalyticsOverviewRequest) (*models.AnalyticsOverview, error)
+}
+
+type analyticsService struct {
+	reportsRepo repository.ReportsRepository
+	now         func() time.Time
+	logger      *slog.Logger
+}
+
+// NewAnalyticsService creates a new analytics service
+func NewAnalyticsService(reportsRepo repository.ReportsRepository, logger *slog.Logger) AnalyticsService {
+	return &analyticsService{
+		reportsRepo: reportsRepo,
+		now:         time.Now,
+		logger:      logger,
+	}
+}
+
+// Overview totals the account's campaigns and messages per day or week
+// between two dates, inclusive, and over the whole period. Without dates it
+// covers the last 30 days, or the last 12 weeks.
+func (s *analyticsService) Overview(ctx context.Context, req *AnalyticsOverviewRequest) (*models.AnalyticsOverview, error) {
+	if req.Interval == "" {
+		req.Interval = models.AnalyticsIntervalDay
+	}
+	if err := models.ValidateAnalyticsInterval(req.Interval); err != nil {
+		return nil, err
+	}
+
+	from, to, err := req.period(models.StartOfDay(s.now()))
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := s.reportsRepo.MessageCounts(ctx, req.Interval, from, to)
+	if err != nil {
+		s.logger.Error("failed to count messages for analytics",
+			slog.String("error", err.Error()),
+		)
+		return nil, err
+	}
+	campaigns, err := s.reportsRepo.CampaignsStarted(ctx, req.Interval, from, to)
+	if err != nil {
+		s.logger.Error("failed to count campaigns for analytics",
+			slog.String("error", err.Error()),
+		)
+		return nil, err
+	}
+
+	overview := &models.AnalyticsOverview{
+		From:     from,
+		To:       to.AddDate(0, 0, -1),
+		Interval: req.Interval,
+		Totals:   models.NewAnalyticsTotals(),
+		Periods:  make([]*models.AnalyticsPeriod, 0),
+	}
+
+	// Every period is listed, so dashboards can chart them without gaps
+	periods := make(map[time.Time]*models.AnalyticsPeriod)
+	for start := from; start.Before(to); start = nextAnalyticsPeriod(start, req.Interval) {
+		period := &models.AnalyticsPeriod{Start: start, AnalyticsTotals: models.NewAnalyticsTotals()}
+		overview.Periods = append(overview.Periods, period)
+		periods[start] = period
+	}
+
+	for _, count := range messages {
+		overview.Totals.AddMessages(count.Channel, count.Status, count.Messages)
+		if period, ok := periods[count.Start.UTC()]; ok {
+			period.AddMessages(count.Channel, count.Status, count.Messages)
+		}
+	}
+	for _, count := range campaigns {
+		overview.Totals.CampaignsSent += count.Campaigns
+		if period, ok := periods[count.Start.UTC()]; ok {
+			period.CampaignsSent += count.Campaigns
+		}
+	}
+
+	setSuccessRate(&overview.Totals)
+	for _, period := range overview.Periods {
+		setSuccessRate(&period.AnalyticsTotals)
+	}
+
+	return overview, nil
+}
+
+// nextAnalyticsPeriod returns the start of the day or week after start
+func nextAnalyticsPeriod(start time.Time, interval string) time.Time {
+	if interval == models.AnalyticsIntervalWeek {
+		return start.AddDate(0, 0, 7)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// setSuccessRate works out the totals' success rate once a message finished
+func setSuccessRate(totals *models.AnalyticsTotals) {
+	if finished := totals.Finished(); finished > 0 {
+		rate := roundRate(float64(totals.Succeeded()) / float64(finished))
+		totals.SuccessRate = &rate
+	}
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestAnalyticsService_Overview(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	reportsRepo := mocks.NewMockReportsRepository(ctrl)
+	svc := &analyticsService{
+		reportsRepo: reportsRepo,
+		now:         func() time.Time { return time.Date(2025, 6, 30, 15, 0, 0, 0, time.UTC) },
+		logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 0, 0, 0, 0, time.UTC) }
+	from, to := day(28), time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	reportsRepo.EXPECT().MessageCounts(gomock.Any(), models.AnalyticsIntervalDay, from, to).
+		Return([]*models.AnalyticsMessageCount{
+			{Start: day(28), Channel: models.ChannelSMS, Status: models.MessageStatusDelivered, Messages: 90},
+			{Start: day(28), Channel: models.ChannelSMS, Status: models.MessageStatusFailed, Messages: 10},
+			{Start: day(30), Channel: models.ChannelWhatsApp, Status: models.MessageStatusSent, Messages: 30},
+			{Start: day(30), Channel: models.ChannelWhatsApp, Status: models.MessageStatusPending, Messages: 20},
+		}, nil)
+	reportsRepo.EXPECT().CampaignsStarted(gomock.Any(), models.AnalyticsIntervalDay, from, to).
+		Return([]*models.AnalyticsCampaignCount{{Start: day(28), Campaigns: 2}, {Start: day(30), Campaigns: 1}}, nil)
+
+	overview, err := svc.Overview(context.Background(), &AnalyticsOverviewRequest{From: "2025-06-28"})
+	if err != nil {
+		t.Fatalf("Overview() error = %v", err)
+	}
+
+	if overview.Totals.CampaignsSent != 3 || overview.Totals.Messages != 150 {
+		t.Errorf("Totals = %d campaigns, %d messages, want 3 and 150", overview.Totals.CampaignsSent, overview.Totals.Messages)
+	}
+	if overview.Totals.MessagesByChannel[models.ChannelSMS] != 100 || overview.Totals.MessagesByStatus[models.MessageStatusPending] != 20 {
+		t.Errorf("Totals by channel %v and status %v", overview.Totals.MessagesByChannel, overview.Totals.MessagesByStatus)
+	}
+	// Pending messages have not finished and do not count against the rate
+	if overview.Totals.SuccessRate == nil || *overview.Totals.SuccessRate != 0.9231 {
+		t.Errorf("Totals.SuccessRate = %v, want 0.9231", overview.Totals.SuccessRate)
+	}
+
+	// The quiet day in between is listed without a rate
+	if len(overview.Periods) != 3 {
+		t.Fatalf("Overview() has %d periods, want 3", len(overview.Periods))
+	}
+	if quiet := overview.Periods[1]; !quiet.Start.Equal(day(29)) || quiet.Messages != 0 || quiet.SuccessRate != nil {
+		t.Errorf("Periods[1] = %+v, want an empty 2025-06-29", quiet)
+	}
+	if last := overview.Periods[2]; last.CampaignsSent != 1 || last.SuccessRate == nil || *last.SuccessRate != 1 {
+		t.Errorf("Periods[2] = %+v, want 1 campaign at rate 1", last)
+	}
+}
+
+func TestAnalyticsOverviewRequest_Period(t *testing.T) {
+	today := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+
+	// Weekly overviews default to 12 weeks starting on a Monday
+	from, to, err := (&AnalyticsOverviewRequest{Interval: models.AnalyticsIntervalWeek}).period(today)
+	if err != nil {
+		t.Fatalf("period() error = %v", err)
+	}
+	if from.Weekday() != time.Monday || !from.Equal(time.Date(2025, 4, 7, 0, 0, 0, 0, time.UTC)) || !to.Equal(today.AddDate(0, 0, 1)) {
+		t.Errorf("period(week) = %s to %s, want 2025-04-07 to 2025-07-01", from, to)
+	}
+
+	var appErr *models.AppError
+	if _, _, err := (&AnalyticsOverviewRequest{From: "2025-07-01", To: "2025-06-01"}).period(today); !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+		t.Errorf("period(from after to) error = %v, want INVALID_INPUT", err)
+	}
+}
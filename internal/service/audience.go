@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// defaultSendBatchSize is the number of recipients fetched, rendered, inserted
+// and published per batch when building a campaign audience
+const defaultSendBatchSize = 1000
+
+// audienceSource yields campaign recipients one page at a time so that audience
+// building never holds more than a single page of customers in memory
+type audienceSource interface {
+	// NextPage returns the next page of customers, or an empty slice once the
+	// audience is exhausted
+	NextPage(ctx context.Context) ([]*models.Customer, error)
+	// Count returns the number of customers the source will yield
+	Count(ctx context.Context) (int64, error)
+	// Sample returns up to limit customers picked at random from those the
+	// source yields who are neither opted out, snoozed nor bounced
+	Sample(ctx context.Context, limit int) ([]*models.Customer, error)
+}
+
+// customerIDSource pages through an explicit list of customer IDs.
+// IDs are de-duplicated and walked in ascending order so each page is a stable
+// keyset range; IDs that no longer exist are dropped by the repository.
+type customerIDSource struct {
+	customerRepo repository.CustomerRepository
+	ids          []int64
+	pageSize     int
+	offset       int
+}
+
+func newCustomerIDSource(customerRepo repository.CustomerRepository, ids []int64, pageSize int) *customerIDSource {
+	seen := make(map[int64]bool, len(ids))
+	unique := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	sort.Slice(unique, func(i, j int) bool { return unique[i] < unique[j] })
+
+	return &customerIDSource{
+		customerRepo: customerRepo,
+		ids:          unique,
+		pageSize:     pageSize,
+	}
+}
+
+// NextPage fetches the customers for the next chunk of IDs
+func (s *customerIDSource) NextPage(ctx context.Context) ([]*models.Customer, error) {
+	for s.offset < len(s.ids) {
+		end := s.offset + s.pageSize
+		if end > len(s.ids) {
+			end = len(s.ids)
+		}
+		chunk := s.ids[s.offset:end]
+		s.offset = end
+
+		customers, err := s.customerRepo.GetByIDs(ctx, chunk)
+		if err != nil {
+			return nil, err
+		}
+		// Skip chunks whose customers have all been removed
+		if len(customers) > 0 {
+			return customers, nil
+		}
+	}
+
+	return []*models.Customer{}, nil
+}
+
+// Count returns the number of requested IDs that still belong to a customer
+func (s *customerIDSource) Count(ctx context.Context) (int64, error) {
+	return s.customerRepo.CountByIDs(ctx, s.ids)
+}
+
+// Sample fetches a random selection of the requested IDs
+func (s *customerIDSource) Sample(ctx context.Context, limit int) ([]*models.Customer, error) {
+	ids := slices.Clone(s.ids)
+	rand.Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+
+	now := time.Now()
+	sample := make([]*models.Customer, 0, limit)
+	for start := 0; start < len(ids) && len(sample) < limit; start += s.pageSize {
+		end := min(start+s.pageSize, len(ids))
+		customers, err := s.customerRepo.GetByIDs(ctx, ids[start:end])
+		if err != nil {
+			return nil, err
+		}
+		for _, customer := range customers {
+			if len(sample) < limit && !customer.IsOptedOut() && !customer.IsSnoozed(now) && !customer.IsBounced() {
+				sample = append(sample, customer)
+			}
+		}
+	}
+
+	return sample, nil
+}
+
+// allCustomersSource walks the whole customer table with keyset pagination
+type allCustomersSource struct {
+	customerRepo repository.CustomerRepository
+	pageSize     int
+	lastID       int64
+}
+
+func newAllCustomersSource(customerRepo repository.CustomerRepository, pageSize int) *allCustomersSource {
+	return &allCustomersSource{
+		customerRepo: customerRepo,
+		pageSize:     pageSize,
+	}
+}
+
+// NextPage fetches the next page of customers after the last seen ID
+func (s *allCustomersSource) NextPage(ctx context.Context) ([]*models.Customer, error) {
+	customers, err := s.customerRepo.ListAfterID(ctx, s.lastID, s.pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(customers) > 0 {
+		s.lastID = customers[len(customers)-1].ID
+	}
+
+	return customers, nil
+}
+
+// Count returns the number of customers
+func (s *allCustomersSource) Count(ctx context.Context) (int64, error) {
+	return s.customerRepo.Count(ctx)
+}
+
+// Sample fetches a random selection of all customers
+func (s *allCustomersSource) Sample(ctx context.Context, limit int) ([]*models.Customer, error) {
+	return s.customerRepo.SampleMatching(ctx, models.SegmentFilter{}, limit)
+}
+
+// filteredCustomersSource walks the customers matching a segment filter with
+// keyset pagination
+type filteredCustomersSource struct {
+	customerRepo repository.CustomerRepository
+	filter       models.SegmentFilter
+	pageSize     int
+	lastID       int64
+}
+
+func newFilteredCustomersSource(customerRepo repository.CustomerRepository, filter models.SegmentFilter, pageSize int) *filteredCustomersSource {
+	return &filteredCustomersSource{
+		customerRepo: customerRepo,
+		filter:       filter,
+		pageSize:     pageSize,
+	}
+}
+
+// NextPage fetches the next page of matching customers after the last seen ID
+func (s *filteredCustomersSource) NextPage(ctx context.Context) ([]*models.Customer, error) {
+	customers, err := s.customerRepo.ListMatchingAfterID(ctx, s.filter, s.lastID, s.pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(customers) > 0 {
+		s.lastID = customers[len(customers)-1].ID
+	}
+
+	return customers, nil
+}
+
+// Count returns the number of customers matching the filter
+func (s *filteredCustomersSource) Count(ctx context.Context) (int64, error) {
+	return s.customerRepo.CountMatching(ctx, s.filter)
+}
+
+// Sample fetches a random selection of the matching customers
+func (s *filteredCustomersSource) Sample(ctx context.Context, limit int) ([]*models.Customer, error) {
+	return s.customerRepo.SampleMatching(ctx, s.filter, limit)
+}
+
+// excludingSource drops excluded customers from the pages of another source
+// and counts them. Segments and recent messages are checked a page at a time
+// in the database.
+type excludingSource struct {
+	audienceSource
+	customerRepo  repository.CustomerRepository
+	messageRepo   repository.OutboundMessageRepository
+	customerIDs   map[int64]bool
+	segments      []models.SegmentFilter
+	messagedSince time.Time
+	counts        ExclusionCounts
+}
+
+// NextPage returns the next page with excluded customers removed. Pages whose
+// customers are all excluded are skipped, so that an empty page still means
+// the audience is exhausted.
+func (s *excludingSource) NextPage(ctx context.Context) ([]*models.Customer, error) {
+	for {
+		page, err := s.audienceSource.NextPage(ctx)
+		if err != nil || len(page) == 0 {
+			return page, err
+		}
+
+		kept, err := s.exclude(ctx, page, &s.counts)
+		if err != nil {
+			return nil, err
+		}
+		if len(kept) > 0 {
+			return kept, nil
+		}
+	}
+}
+
+// Sample filters a sample of the underlying source, so it may hold fewer than
+// limit customers
+func (s *excludingSource) Sample(ctx context.Context, limit int) ([]*models.Customer, error) {
+	sample, err := s.audienceSource.Sample(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	return s.exclude(ctx, sample, &ExclusionCounts{})
+}
+
+// exclude returns the customers that are not excluded and adds the excluded
+// ones to counts
+func (s *excludingSource) exclude(ctx context.Context, customers []*models.Customer, counts *ExclusionCounts) ([]*models.Customer, error) {
+	remaining := make([]*models.Customer, 0, len(customers))
+	for _, customer := range customers {
+		if s.customerIDs[customer.ID] {
+			counts.CustomerIDs++
+			continue
+		}
+		remaining = append(remaining, customer)
+	}
+
+	for _, filter := range s.segments {
+		matching, err := s.customerRepo.MatchingIDs(ctx, filter, customerIDs(remaining))
+		if err != nil {
+			return nil, fmt.Errorf("failed to match excluded segment: %w", err)
+		}
+		before := len(remaining)
+		remaining = withoutIDs(remaining, matching)
+		counts.Segments += int64(before - len(remaining))
+	}
+
+	if !s.messagedSince.IsZero() && len(remaining) > 0 {
+		messaged, err := s.messageRepo.MessagedSince(ctx, customerIDs(remaining), s.messagedSince)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find recently messaged customers: %w", err)
+		}
+		before := len(remaining)
+		remaining = withoutIDs(remaining, messaged)
+		counts.RecentlyMessaged += int64(before - len(remaining))
+	}
+
+	counts.Total = counts.CustomerIDs + counts.Segments + counts.RecentlyMessaged
+	return remaining, nil
+}
+
+// customerIDs returns the IDs of customers
+func customerIDs(customers []*models.Customer) []int64 {
+	ids := make([]int64, len(customers))
+	for i, customer := range customers {
+		ids[i] = customer.ID
+	}
+	return ids
+}
+
+// withoutIDs returns customers without those whose ID is in ids
+func withoutIDs(customers []*models.Customer, ids []int64) []*models.Customer {
+	if len(ids) == 0 {
+		return customers
+	}
+	excluded := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		excluded[id] = true
+	}
+	return slices.DeleteFunc(customers, func(customer *models.Customer) bool {
+		return excluded[customer.ID]
+	})
+}
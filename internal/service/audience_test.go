@@ -0,0 +1,707 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func newTestCustomers(n int) map[int64]*models.Customer {
+	customers := make(map[int64]*models.Customer, n)
+	for i := 1; i <= n; i++ {
+		customers[int64(i)] = &models.Customer{ID: int64(i), FirstName: "Customer"}
+	}
+	return customers
+}
+
+// drainSource collects the page sizes produced by a source
+func drainSource(t *testing.T, source audienceSource) []int {
+	t.Helper()
+
+	var pages []int
+	for {
+		customers, err := source.NextPage(context.Background())
+		if err != nil {
+			t.Fatalf("NextPage() error = %v", err)
+		}
+		if len(customers) == 0 {
+			return pages
+		}
+		pages = append(pages, len(customers))
+	}
+}
+
+func TestCustomerIDSource_Pages(t *testing.T) {
+	customers := &customerStore{byID: newTestCustomers(10)}
+
+	// Duplicates are dropped and unknown IDs (99) are skipped
+	ids := []int64{5, 1, 2, 2, 3, 4, 6, 7, 99}
+	pages := drainSource(t, newCustomerIDSource(customers.repo(t), ids, 3))
+
+	want := []int{3, 3, 1}
+	if len(pages) != len(want) {
+		t.Fatalf("pages = %v, want %v", pages, want)
+	}
+	for i := range want {
+		if pages[i] != want[i] {
+			t.Errorf("pages = %v, want %v", pages, want)
+			break
+		}
+	}
+}
+
+func TestAllCustomersSource_Pages(t *testing.T) {
+	customers := &customerStore{byID: newTestCustomers(7)}
+
+	pages := drainSource(t, newAllCustomersSource(customers.repo(t), 3))
+
+	want := []int{3, 3, 1}
+	if len(pages) != len(want) {
+		t.Fatalf("pages = %v, want %v", pages, want)
+	}
+}
+
+func TestFilteredCustomersSource_Pages(t *testing.T) {
+	customers := newTestCustomers(10)
+	for id, customer := range customers {
+		customer.Location = "Mombasa"
+		if id%2 == 0 {
+			customer.Location = "Nairobi"
+		}
+	}
+
+	source := newFilteredCustomersSource((&customerStore{byID: customers}).repo(t), models.SegmentFilter{Location: "Nairobi"}, 2)
+	pages := drainSource(t, source)
+
+	want := []int{2, 2, 1}
+	if fmt.Sprint(pages) != fmt.Sprint(want) {
+		t.Fatalf("pages = %v, want %v", pages, want)
+	}
+	if count, _ := source.Count(context.Background()); count != 5 {
+		t.Errorf("Count() = %d, want 5", count)
+	}
+}
+
+func TestCampaignService_SendCampaign_Segment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	segmentRepo := mocks.NewMockSegmentRepository(ctrl)
+	segmentRepo.EXPECT().GetByID(gomock.Any(), int64(3)).
+		Return(&models.Segment{ID: 3, Filter: models.SegmentFilter{PhonePrefix: "+2547"}}, nil)
+	segmentRepo.EXPECT().GetByID(gomock.Any(), int64(9)).
+		Return(nil, models.ErrNotFoundWithMsg("segment with ID 9 not found"))
+
+	customers := newTestCustomers(6)
+	for id, customer := range customers {
+		customer.Phone = fmt.Sprintf("+25470000000%d", id)
+		if id > 4 {
+			customer.Phone = fmt.Sprintf("+25510000000%d", id)
+		}
+	}
+	campaigns := &campaignStore{
+		all: []*models.Campaign{
+			{ID: 1, Status: models.CampaignStatusDraft, BaseTemplate: "Hi {first_name}"},
+			{ID: 2, Status: models.CampaignStatusDraft, BaseTemplate: "Hi {first_name}"},
+		},
+	}
+	messages := &messageStore{}
+
+	svc := NewCampaignService(
+		campaigns.repo(t),
+		(&customerStore{byID: customers}).repo(t),
+		messages.repo(t),
+		segmentRepo,
+		NewTemplateService(nil, nil),
+		&mockQueueClient{},
+		CampaignServiceConfig{SendBatchSize: 3},
+		slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	)
+
+	result, err := svc.SendCampaign(context.Background(), 1, &SendCampaignRequest{SegmentID: int64Ptr(3)})
+	if err != nil {
+		t.Fatalf("SendCampaign() error = %v", err)
+	}
+	if result.MessagesQueued != 4 {
+		t.Errorf("MessagesQueued = %d, want the 4 customers matching the segment", result.MessagesQueued)
+	}
+
+	// A missing segment fails the send before any message is built
+	_, err = svc.SendCampaign(context.Background(), 2, &SendCampaignRequest{SegmentID: int64Ptr(9)})
+	if !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("SendCampaign() error = %v, want not found", err)
+	}
+	if campaigns.all[1].Status != models.CampaignStatusDraft {
+		t.Errorf("campaign status = %s, want draft", campaigns.all[1].Status)
+	}
+}
+
+func TestSendCampaignRequest_Validate_Segment(t *testing.T) {
+	invalid := []*SendCampaignRequest{
+		{SegmentID: int64Ptr(1), Target: SendTargetAll},
+		{SegmentID: int64Ptr(1), CustomerIDs: []int64{1}},
+		{SegmentID: int64Ptr(0)},
+	}
+	for _, req := range invalid {
+		var appErr *models.AppError
+		if err := req.Validate(); !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+			t.Errorf("Validate(%+v) error = %v, want INVALID_INPUT", req, err)
+		}
+	}
+	if err := (&SendCampaignRequest{SegmentID: int64Ptr(1)}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestCampaignService_SendCampaign_Filter(t *testing.T) {
+	customers := newTestCustomers(7)
+	for id, customer := range customers {
+		customer.Location = "Nairobi"
+		if id%3 == 0 {
+			customer.Location = "Mombasa"
+		}
+	}
+	campaigns := &campaignStore{
+		all: []*models.Campaign{
+			{ID: 1, Status: models.CampaignStatusDraft, BaseTemplate: "Hi {first_name}"},
+			{
+				ID:           2,
+				Status:       models.CampaignStatusDraft,
+				BaseTemplate: "Hi {first_name}",
+				Audience:     &models.CampaignAudience{Filter: &models.SegmentFilter{Location: "Mombasa"}},
+			},
+		},
+	}
+
+	svc := NewCampaignService(
+		campaigns.repo(t),
+		(&customerStore{byID: customers}).repo(t),
+		(&messageStore{}).repo(t),
+		nil,
+		NewTemplateService(nil, nil),
+		&mockQueueClient{},
+		CampaignServiceConfig{SendBatchSize: 2},
+		slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	)
+
+	result, err := svc.SendCampaign(context.Background(), 1, &SendCampaignRequest{Filter: &models.SegmentFilter{Location: " Nairobi "}})
+	if err != nil {
+		t.Fatalf("SendCampaign() error = %v", err)
+	}
+	if result.MessagesQueued != 5 {
+		t.Errorf("MessagesQueued = %d, want the 5 customers in Nairobi", result.MessagesQueued)
+	}
+
+	// A bound filter is used by sends that name no recipients
+	result, err = svc.SendCampaign(context.Background(), 2, &SendCampaignRequest{})
+	if err != nil {
+		t.Fatalf("SendCampaign() error = %v", err)
+	}
+	if result.MessagesQueued != 2 {
+		t.Errorf("MessagesQueued = %d, want the 2 customers in Mombasa", result.MessagesQueued)
+	}
+}
+
+func TestSendCampaignRequest_Validate_Filter(t *testing.T) {
+	invalid := []*SendCampaignRequest{
+		{Filter: &models.SegmentFilter{}},
+		{Filter: &models.SegmentFilter{Location: "  "}},
+		{Filter: &models.SegmentFilter{PhonePrefix: "07x"}},
+		{Filter: &models.SegmentFilter{Location: "Nairobi"}, Target: SendTargetAll},
+		{Filter: &models.SegmentFilter{Location: "Nairobi"}, SegmentID: int64Ptr(1)},
+	}
+	for _, req := range invalid {
+		var appErr *models.AppError
+		if err := req.Validate(); !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+			t.Errorf("Validate(%+v) error = %v, want INVALID_INPUT", req, err)
+		}
+	}
+	if err := (&SendCampaignRequest{Filter: &models.SegmentFilter{Location: "Nairobi"}}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestCampaignService_SendCampaign_Exclude(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	segmentRepo := mocks.NewMockSegmentRepository(ctrl)
+	segmentRepo.EXPECT().GetByID(gomock.Any(), int64(3)).
+		Return(&models.Segment{ID: 3, Filter: models.SegmentFilter{Location: "Mombasa"}}, nil)
+
+	customers := newTestCustomers(10)
+	for id, customer := range customers {
+		customer.Location = "Nairobi"
+		if id >= 8 {
+			customer.Location = "Mombasa"
+		}
+	}
+	campaigns := &campaignStore{
+		all: []*models.Campaign{
+			{ID: 1, Status: models.CampaignStatusDraft, BaseTemplate: "Hi {first_name}"},
+		},
+	}
+	// Customer 4 was messaged yesterday, 5 a month ago and 6 yesterday
+	// without the message going out
+	messages := &messageStore{
+		all: []*models.OutboundMessage{
+			{CustomerID: 4, Status: models.MessageStatusDelivered, CreatedAt: time.Now().Add(-24 * time.Hour)},
+			{CustomerID: 5, Status: models.MessageStatusDelivered, CreatedAt: time.Now().AddDate(0, -1, 0)},
+			{CustomerID: 6, Status: models.MessageStatusFailed, CreatedAt: time.Now().Add(-24 * time.Hour)},
+		},
+	}
+
+	svc := NewCampaignService(
+		campaigns.repo(t),
+		(&customerStore{byID: customers}).repo(t),
+		messages.repo(t),
+		segmentRepo,
+		NewTemplateService(nil, nil),
+		&mockQueueClient{},
+		CampaignServiceConfig{SendBatchSize: 3},
+		slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	)
+
+	result, err := svc.SendCampaign(context.Background(), 1, &SendCampaignRequest{
+		Target: SendTargetAll,
+		Exclude: &AudienceExclusion{
+			CustomerIDs:        []int64{1, 2, 9},
+			SegmentIDs:         []int64{3},
+			MessagedWithinDays: 7,
+		},
+	})
+	if err != nil {
+		t.Fatalf("SendCampaign() error = %v", err)
+	}
+
+	// 9 is both named and in the segment, so it counts once
+	want := ExclusionCounts{CustomerIDs: 3, Segments: 2, RecentlyMessaged: 1, Total: 6}
+	if result.Excluded == nil || *result.Excluded != want {
+		t.Errorf("Excluded = %+v, want %+v", result.Excluded, want)
+	}
+	if result.MessagesQueued != 4 {
+		t.Errorf("MessagesQueued = %d, want 4", result.MessagesQueued)
+	}
+	for _, msg := range messages.all[3:] {
+		if msg.CustomerID != 3 && msg.CustomerID != 5 && msg.CustomerID != 6 && msg.CustomerID != 7 {
+			t.Errorf("message built for excluded customer %d", msg.CustomerID)
+		}
+	}
+}
+
+func TestSendCampaignRequest_Validate_Exclude(t *testing.T) {
+	invalid := []*SendCampaignRequest{
+		{Target: SendTargetAll, Exclude: &AudienceExclusion{SegmentIDs: []int64{0}}},
+		{Target: SendTargetAll, Exclude: &AudienceExclusion{SegmentIDs: make([]int64, maxExclusionSegments+1)}},
+		{Target: SendTargetAll, Exclude: &AudienceExclusion{MessagedWithinDays: -1}},
+		{Target: SendTargetAll, Exclude: &AudienceExclusion{MessagedWithinDays: maxExclusionMessagedDays + 1}},
+	}
+	for _, req := range invalid {
+		var appErr *models.AppError
+		if err := req.Validate(); !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+			t.Errorf("Validate(%+v) error = %v, want INVALID_INPUT", req.Exclude, err)
+		}
+	}
+}
+
+func TestCampaignService_SendCampaign_TargetAll(t *testing.T) {
+	campaigns := &campaignStore{
+		all: []*models.Campaign{
+			{ID: 1, Status: models.CampaignStatusDraft, BaseTemplate: "Hi {first_name}"},
+		},
+	}
+	messages := &messageStore{}
+	queueClient := &mockQueueClient{}
+
+	svc := NewCampaignService(
+		campaigns.repo(t),
+		(&customerStore{byID: newTestCustomers(25)}).repo(t),
+		messages.repo(t),
+		nil,
+		NewTemplateService(nil, nil),
+		queueClient,
+		CampaignServiceConfig{SendBatchSize: 10, RenderConcurrency: 3},
+		slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	)
+
+	result, err := svc.SendCampaign(context.Background(), 1, &SendCampaignRequest{Target: SendTargetAll})
+	if err != nil {
+		t.Fatalf("SendCampaign() error = %v", err)
+	}
+
+	if result.MessagesQueued != 25 {
+		t.Errorf("MessagesQueued = %d, want 25", result.MessagesQueued)
+	}
+	if len(messages.all) != 25 {
+		t.Errorf("messages created = %d, want 25", len(messages.all))
+	}
+	if campaigns.all[0].Status != models.CampaignStatusSending {
+		t.Errorf("campaign status = %s, want %s", campaigns.all[0].Status, models.CampaignStatusSending)
+	}
+}
+
+func TestCampaignService_SendCampaign_BoundAudience(t *testing.T) {
+	campaigns := &campaignStore{
+		all: []*models.Campaign{
+			{ID: 1, Status: models.CampaignStatusDraft, BaseTemplate: "Hi {first_name}", Audience: &models.CampaignAudience{CustomerIDs: []int64{2, 4, 6}}},
+			{ID: 2, Status: models.CampaignStatusDraft, BaseTemplate: "Hi {first_name}"},
+		},
+	}
+	messages := &messageStore{}
+
+	svc := NewCampaignService(
+		campaigns.repo(t),
+		(&customerStore{byID: newTestCustomers(10)}).repo(t),
+		messages.repo(t),
+		nil,
+		NewTemplateService(nil, nil),
+		&mockQueueClient{},
+		CampaignServiceConfig{SendBatchSize: 10, RenderConcurrency: 1},
+		slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	)
+
+	// A request naming no recipients goes to the bound audience
+	result, err := svc.SendCampaign(context.Background(), 1, &SendCampaignRequest{})
+	if err != nil {
+		t.Fatalf("SendCampaign() error = %v", err)
+	}
+	if result.MessagesQueued != 3 || len(messages.all) != 3 {
+		t.Errorf("messages queued = %d, created = %d, want 3", result.MessagesQueued, len(messages.all))
+	}
+
+	var appErr *models.AppError
+	_, err = svc.SendCampaign(context.Background(), 2, &SendCampaignRequest{})
+	if !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+		t.Errorf("SendCampaign() without a bound audience error = %v, want INVALID_INPUT", err)
+	}
+}
+
+func TestCampaignService_SendCampaign_ConfirmLargeAudience(t *testing.T) {
+	campaigns := &campaignStore{
+		all: []*models.Campaign{
+			{ID: 1, Status: models.CampaignStatusDraft, BaseTemplate: "Hi {first_name}"},
+			{ID: 2, Status: models.CampaignStatusDraft, BaseTemplate: "Hi {first_name}"},
+		},
+	}
+	messages := &messageStore{}
+
+	svc := NewCampaignService(
+		campaigns.repo(t),
+		(&customerStore{byID: newTestCustomers(10)}).repo(t),
+		messages.repo(t),
+		nil,
+		NewTemplateService(nil, nil),
+		&mockQueueClient{},
+		CampaignServiceConfig{SendBatchSize: 10, RenderConcurrency: 1, ConfirmThreshold: 5},
+		slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	)
+
+	// At or below the threshold no confirmation is needed
+	if _, err := svc.SendCampaign(context.Background(), 2, &SendCampaignRequest{CustomerIDs: []int64{1, 2, 3, 4, 5, 99}}); err != nil {
+		t.Fatalf("SendCampaign() below threshold error = %v", err)
+	}
+
+	for _, confirm := range []*int64{nil, int64Ptr(9)} {
+		var appErr *models.AppError
+		_, err := svc.SendCampaign(context.Background(), 1, &SendCampaignRequest{Target: SendTargetAll, ConfirmRecipientCount: confirm})
+		if !errors.As(err, &appErr) || appErr.Code != "CONFIRMATION_REQUIRED" {
+			t.Fatalf("SendCampaign() with confirmation %v error = %v, want CONFIRMATION_REQUIRED", confirm, err)
+		}
+	}
+	if len(messages.all) != 5 {
+		t.Fatalf("messages created = %d, want 5 (none from the unconfirmed sends)", len(messages.all))
+	}
+
+	result, err := svc.SendCampaign(context.Background(), 1, &SendCampaignRequest{Target: SendTargetAll, ConfirmRecipientCount: int64Ptr(10)})
+	if err != nil {
+		t.Fatalf("SendCampaign() confirmed error = %v", err)
+	}
+	if result.MessagesQueued != 10 {
+		t.Errorf("messages queued = %d, want 10", result.MessagesQueued)
+	}
+}
+
+func TestSendCampaignRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     SendCampaignRequest
+		wantErr bool
+	}{
+		{name: "customer ids", req: SendCampaignRequest{CustomerIDs: []int64{1}}},
+		{name: "target all", req: SendCampaignRequest{Target: SendTargetAll}},
+		{name: "empty", req: SendCampaignRequest{}, wantErr: true},
+		{name: "unknown target", req: SendCampaignRequest{Target: "some"}, wantErr: true},
+		{name: "both", req: SendCampaignRequest{CustomerIDs: []int64{1}, Target: SendTargetAll}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCampaignService_BuildMessages_PreservesOrder(t *testing.T) {
+	customers := make([]*models.Customer, 200)
+	for i := range customers {
+		customers[i] = &models.Customer{ID: int64(i + 1), FirstName: fmt.Sprintf("C%d", i+1)}
+	}
+
+	svc := &campaignService{
+		templateSvc: NewTemplateService(nil, nil),
+		config:      CampaignServiceConfig{RenderConcurrency: 8},
+		logger:      slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	}
+
+	campaign := &models.Campaign{ID: 1, BaseTemplate: "Hi {first_name}"}
+	messages := svc.buildMessages(context.Background(), campaign, svc.templateSvc.Compile(campaign.BaseTemplate), customers)
+
+	if len(messages) != len(customers) {
+		t.Fatalf("buildMessages() returned %d messages, want %d", len(messages), len(customers))
+	}
+	for i, message := range messages {
+		if message.CustomerID != customers[i].ID {
+			t.Fatalf("messages[%d].CustomerID = %d, want %d", i, message.CustomerID, customers[i].ID)
+		}
+		if want := "Hi " + customers[i].FirstName; message.RenderedContent != want {
+			t.Errorf("messages[%d].RenderedContent = %q, want %q", i, message.RenderedContent, want)
+		}
+	}
+}
+
+func TestCampaignService_SendCampaign_StopsWhenContextDone(t *testing.T) {
+	campaigns := &campaignStore{
+		all: []*models.Campaign{
+			{ID: 1, Status: models.CampaignStatusDraft, BaseTemplate: "Hi {first_name}"},
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// The request context is cancelled once the first batch is created
+	messages := &messageStore{}
+	messageRepo := mocks.NewMockOutboundMessageRepository(gomock.NewController(t))
+	messageRepo.EXPECT().CreateBatch(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, batch []*models.OutboundMessage) error {
+		defer cancel()
+		return messages.createBatch(ctx, batch)
+	})
+	messages.expect(messageRepo)
+
+	svc := NewCampaignService(
+		campaigns.repo(t),
+		(&customerStore{byID: newTestCustomers(25)}).repo(t),
+		messageRepo,
+		nil,
+		NewTemplateService(nil, nil),
+		&mockQueueClient{},
+		CampaignServiceConfig{SendBatchSize: 10, RenderConcurrency: 3},
+		slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	)
+
+	_, err := svc.SendCampaign(ctx, 1, &SendCampaignRequest{Target: SendTargetAll})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("SendCampaign() error = %v, want context.Canceled", err)
+	}
+
+	// Only the batch built before the client went away exists
+	if got := len(messages.all); got != 10 {
+		t.Errorf("messages created = %d, want 10", got)
+	}
+	// The partial send is still recorded so a retry cannot duplicate it
+	if campaigns.all[0].Status != models.CampaignStatusSending {
+		t.Errorf("campaign status = %s, want %s", campaigns.all[0].Status, models.CampaignStatusSending)
+	}
+}
+
+func TestCampaignService_SendCampaign_RestoresStatusWhenNothingCreated(t *testing.T) {
+	campaigns := &campaignStore{
+		all: []*models.Campaign{
+			{ID: 1, Status: models.CampaignStatusDraft, BaseTemplate: "Hi {first_name}"},
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	svc := NewCampaignService(
+		campaigns.repo(t),
+		(&customerStore{byID: newTestCustomers(5)}).repo(t),
+		(&messageStore{}).repo(t),
+		nil,
+		NewTemplateService(nil, nil),
+		&mockQueueClient{},
+		CampaignServiceConfig{SendBatchSize: 10},
+		slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	)
+
+	if _, err := svc.SendCampaign(ctx, 1, &SendCampaignRequest{Target: SendTargetAll}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("SendCampaign() error = %v, want context.Canceled", err)
+	}
+
+	// No message was created, so the send may be retried
+	if campaigns.all[0].Status != models.CampaignStatusDraft {
+		t.Errorf("campaign status = %s, want %s", campaigns.all[0].Status, models.CampaignStatusDraft)
+	}
+}
+
+func TestCampaignService_SendCampaign_ClaimedByAnotherSend(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+
+	// Both sends read the campaign as a draft; the other one claimed it first,
+	// so this one creates no message
+	campaignRepo.EXPECT().GetByID(gomock.Any(), int64(1)).
+		Return(&models.Campaign{ID: 1, Status: models.CampaignStatusDraft, BaseTemplate: "Hi {first_name}"}, nil)
+	campaignRepo.EXPECT().TransitionStatus(gomock.Any(), int64(1), models.CampaignStatusDraft, models.CampaignStatusSending).Return(false, nil)
+
+	svc := NewCampaignService(
+		campaignRepo,
+		(&customerStore{byID: newTestCustomers(5)}).repo(t),
+		mocks.NewMockOutboundMessageRepository(ctrl),
+		nil,
+		NewTemplateService(nil, nil),
+		&mockQueueClient{},
+		CampaignServiceConfig{SendBatchSize: 10},
+		slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	)
+
+	_, err := svc.SendCampaign(context.Background(), 1, &SendCampaignRequest{Target: SendTargetAll})
+	if !errors.Is(err, models.ErrConflict) {
+		t.Errorf("SendCampaign() error = %v, want conflict", err)
+	}
+}
+
+func TestCampaignService_SendCampaign_StopsWhenCancelled(t *testing.T) {
+	campaigns := &campaignStore{
+		all: []*models.Campaign{
+			{ID: 1, Status: models.CampaignStatusDraft, BaseTemplate: "Hi {first_name}"},
+		},
+	}
+	// The campaign is cancelled once the first batch is created
+	messages := &messageStore{}
+	messageRepo := mocks.NewMockOutboundMessageRepository(gomock.NewController(t))
+	messageRepo.EXPECT().CreateBatch(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, batch []*models.OutboundMessage) error {
+		campaigns.all[0].Status = models.CampaignStatusCancelled
+		return messages.createBatch(ctx, batch)
+	})
+	messages.expect(messageRepo)
+
+	svc := NewCampaignService(
+		campaigns.repo(t),
+		(&customerStore{byID: newTestCustomers(25)}).repo(t),
+		messageRepo,
+		nil,
+		NewTemplateService(nil, nil),
+		&mockQueueClient{},
+		CampaignServiceConfig{SendBatchSize: 10},
+		slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	)
+
+	_, err := svc.SendCampaign(context.Background(), 1, &SendCampaignRequest{Target: SendTargetAll})
+	if !errors.Is(err, models.ErrConflict) {
+		t.Fatalf("SendCampaign() error = %v, want conflict", err)
+	}
+
+	// No batch is built after the cancellation, and the campaign stays cancelled
+	if got := len(messages.all); got != 10 {
+		t.Errorf("messages created = %d, want 10", got)
+	}
+	if campaigns.all[0].Status != models.CampaignStatusCancelled {
+		t.Errorf("campaign status = %s, want %s", campaigns.all[0].Status, models.CampaignStatusCancelled)
+	}
+}
+
+func TestCampaignService_SendCampaign_BuildingUntilLastBatch(t *testing.T) {
+	campaigns := &campaignStore{
+		all: []*models.Campaign{
+			{ID: 1, Status: models.CampaignStatusDraft, BaseTemplate: "Hi {first_name}"},
+		},
+	}
+	// Workers must not complete the campaign while later batches are created
+	messages := &messageStore{}
+	var building []bool
+	messageRepo := mocks.NewMockOutboundMessageRepository(gomock.NewController(t))
+	messageRepo.EXPECT().CreateBatch(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, batch []*models.OutboundMessage) error {
+		building = append(building, campaigns.building[1])
+		return messages.createBatch(ctx, batch)
+	}).AnyTimes()
+	messages.expect(messageRepo)
+
+	svc := NewCampaignService(
+		campaigns.repo(t),
+		(&customerStore{byID: newTestCustomers(25)}).repo(t),
+		messageRepo,
+		nil,
+		NewTemplateService(nil, nil),
+		&mockQueueClient{},
+		CampaignServiceConfig{SendBatchSize: 10},
+		slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	)
+
+	if _, err := svc.SendCampaign(context.Background(), 1, &SendCampaignRequest{Target: SendTargetAll}); err != nil {
+		t.Fatalf("SendCampaign() error = %v", err)
+	}
+
+	if len(building) != 3 || !building[0] || !building[1] || !building[2] {
+		t.Errorf("building while creating batches = %v, want true for all 3", building)
+	}
+	if campaigns.building[1] {
+		t.Error("campaign still building after the send returned")
+	}
+}
+
+// flakyQueueClient fails to publish the jobs of the given messages
+type flakyQueueClient struct {
+	*mockQueueClient
+	fail map[int64]bool
+}
+
+func (m *flakyQueueClient) Publish(ctx context.Context, job *models.MessageJob) error {
+	if m.fail[job.OutboundMessageID] {
+		return errors.New("queue unavailable")
+	}
+	return m.mockQueueClient.Publish(ctx, job)
+}
+
+func TestCampaignService_SendCampaign_MarksQueuedMessages(t *testing.T) {
+	campaigns := &campaignStore{
+		all: []*models.Campaign{
+			{ID: 1, Status: models.CampaignStatusDraft, BaseTemplate: "Hi {first_name}"},
+		},
+	}
+	messages := &messageStore{}
+
+	svc := NewCampaignService(
+		campaigns.repo(t),
+		(&customerStore{byID: newTestCustomers(5)}).repo(t),
+		messages.repo(t),
+		nil,
+		NewTemplateService(nil, nil),
+		&flakyQueueClient{mockQueueClient: &mockQueueClient{}, fail: map[int64]bool{2: true, 4: true}},
+		CampaignServiceConfig{SendBatchSize: 3},
+		slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	)
+
+	result, err := svc.SendCampaign(context.Background(), 1, &SendCampaignRequest{Target: SendTargetAll})
+	if err != nil {
+		t.Fatalf("SendCampaign() error = %v", err)
+	}
+	if result.MessagesQueued != 3 {
+		t.Errorf("MessagesQueued = %d, want 3", result.MessagesQueued)
+	}
+
+	// Messages whose jobs were not published stay unqueued for the outbox relay
+	if fmt.Sprint(messages.queued) != "[1 3 5]" {
+		t.Errorf("queued = %v, want [1 3 5]", messages.queued)
+	}
+}
+
+func int64Ptr(n int64) *int64 {
+	return &n
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// MinJWTSecretLength is the minimum length of the secret JWTs are signed with
+const MinJWTSecretLength = 32
+
+// AuthService signs operators in and verifies the tokens it issues
+type AuthService interface {
+	Login(ctx context.Context, req *LoginRequest) (*LoginResult, error)
+	// Authenticate verifies a token and returns the user it was issued to
+	Authenticate(token string) (*AuthClaims, error)
+	// EnsureAdmin creates an admin with email and password unless a user
+	// with that email exists, so a new deployment has someone to sign in as
+	EnsureAdmin(ctx context.Context, email, password string) error
+}
+
+// AuthClaims are the claims of an issued token. The role is fixed when the
+// token is issued; a changed role applies from the user's next login. The
+// account is the tenant every request made with the token acts for.
+type AuthClaims struct {
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	AccountID int64  `json:"account_id"`
+	jwt.RegisteredClaims
+}
+
+// UserID returns the ID of the user the token was issued to
+func (c *AuthClaims) UserID() int64 {
+	id, _ := strconv.ParseInt(c.Subject, 10, 64)
+	return id
+}
+
+type authService struct {
+	userRepo repository.UserRepository
+	secret   []byte
+	tokenTTL time.Duration
+	logger   *slog.Logger
+	now      func() time.Time
+}
+
+// dummyPasswordHash is compared against when a login names an unknown email,
+// so that the response time does not reveal which emails exist
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("campaign-manager-dummy"), bcrypt.DefaultCost)
+
+// NewAuthService creates a new auth service issuing tokens signed with secret
+// that are valid for tokenTTL
+func NewAuthService(userRepo repository.UserRepository, secret string, tokenTTL time.Duration, logger *slog.Logger) AuthService {
+	return &authService{
+		userRepo: userRepo,
+		secret:   []byte(secret),
+		tokenTTL: tokenTTL,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// Login checks an email and password and issues a token
+func (s *authService) Login(ctx context.Context, req *LoginRequest) (*LoginResult, error) {
+	invalid := models.ErrUnauthorized("invalid email or password")
+
+	user, err := s.userRepo.GetByEmail(ctx, normalizeEmail(req.Email))
+	if errors.Is(err, models.ErrNotFound) {
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(req.Password))
+		return nil, invalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		s.logger.Warn("failed login", slog.Int64("user_id", user.ID))
+		return nil, invalid
+	}
+
+	now := s.now()
+	expiresAt := now.Add(s.tokenTTL)
+	claims := &AuthClaims{
+		Email:     user.Email,
+		Role:      user.Role,
+		AccountID: user.AccountID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.FormatInt(user.ID, 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	s.logger.Info("user logged in",
+		slog.Int64("user_id", user.ID),
+		slog.Int64("account_id", user.AccountID),
+		slog.String("role", user.Role),
+	)
+
+	return &LoginResult{
+		Token:     token,
+		ExpiresAt: expiresAt.UTC().Truncate(time.Second),
+		User:      user,
+	}, nil
+}
+
+// Authenticate verifies a token's signature and expiry
+func (s *authService) Authenticate(token string) (*AuthClaims, error) {
+	claims := &AuthClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return s.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithTimeFunc(s.now), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, models.ErrUnauthorized("invalid or expired token")
+	}
+	// Tokens issued before accounts existed carry no account and must be renewed
+	if !models.IsValidRole(claims.Role) || claims.UserID() == 0 || claims.AccountID == 0 {
+		return nil, models.ErrUnauthorized("invalid or expired token")
+	}
+
+	return claims, nil
+}
+
+// EnsureAdmin creates the bootstrap admin in the default account
+func (s *authService) EnsureAdmin(ctx context.Context, email, password string) error {
+	email = normalizeEmail(email)
+	_, err := s.userRepo.GetByEmail(ctx, email)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, models.ErrNotFound) {
+		return err
+	}
+
+	user, err := newUser(email, password, models.RoleAdmin)
+	if err != nil {
+		return err
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return err
+	}
+
+	s.logger.Info("admin user created", slog.Int64("user_id", user.ID))
+
+	return nil
+}
+
+// newUser validates a new user's details and hashes their password
+func newUser(email, password, role string) (*models.User, error) {
+	user := &models.User{Email: email, Role: role}
+	if err := user.Validate(); err != nil {
+		return nil, err
+	}
+	if len(password) < models.MinPasswordLength {
+		return nil, models.ErrInvalidInput(fmt.Sprintf("password must be at least %d characters", models.MinPasswordLength))
+	}
+	// bcrypt only reads the first 72 bytes; longer passwords would be truncated silently
+	if len(password) > 72 {
+		return nil, models.ErrInvalidInput("password must be at most 72 bytes")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+	user.PasswordHash = string(hash)
+
+	return user, nil
+}
+
+// normalizeEmail trims and lowercases an email so lookups ignore case
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+const testJWTSecret = "test-secret-at-least-thirty-two-chars"
+
+func newTestAuthService(t *testing.T) (*authService, *mocks.MockUserRepository) {
+	ctrl := gomock.NewController(t)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	svc := NewAuthService(userRepo, testJWTSecret, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil))).(*authService)
+	return svc, userRepo
+}
+
+func testUser(t *testing.T, password, role string) *models.User {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &models.User{ID: 7, AccountID: 3, Email: "ops@example.com", PasswordHash: string(hash), Role: role}
+}
+
+func assertUnauthorized(t *testing.T, err error) {
+	t.Helper()
+	var appErr *models.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "UNAUTHORIZED" {
+		t.Errorf("error = %v, want UNAUTHORIZED", err)
+	}
+}
+
+func TestAuthService_Login(t *testing.T) {
+	svc, userRepo := newTestAuthService(t)
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	user := testUser(t, "correct horse battery", models.RoleSender)
+	userRepo.EXPECT().GetByEmail(gomock.Any(), "ops@example.com").Return(user, nil).Times(2)
+
+	result, err := svc.Login(context.Background(), &LoginRequest{Email: " Ops@Example.com ", Password: "correct horse battery"})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if !result.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("ExpiresAt = %v, want %v", result.ExpiresAt, now.Add(time.Hour))
+	}
+
+	claims, err := svc.Authenticate(result.Token)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if claims.UserID() != 7 || claims.AccountID != 3 || claims.Role != models.RoleSender || claims.Email != "ops@example.com" {
+		t.Errorf("claims = %+v, want user 7 of account 3 with role sender", claims)
+	}
+
+	_, err = svc.Login(context.Background(), &LoginRequest{Email: "ops@example.com", Password: "wrong password"})
+	assertUnauthorized(t, err)
+
+	userRepo.EXPECT().GetByEmail(gomock.Any(), "nobody@example.com").Return(nil, models.ErrNotFoundWithMsg("user not found"))
+	_, err = svc.Login(context.Background(), &LoginRequest{Email: "nobody@example.com", Password: "correct horse battery"})
+	assertUnauthorized(t, err)
+}
+
+func TestAuthService_Authenticate_Rejects(t *testing.T) {
+	svc, userRepo := newTestAuthService(t)
+	issuedAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return issuedAt }
+
+	userRepo.EXPECT().GetByEmail(gomock.Any(), gomock.Any()).Return(testUser(t, "correct horse battery", models.RoleViewer), nil)
+	result, err := svc.Login(context.Background(), &LoginRequest{Email: "ops@example.com", Password: "correct horse battery"})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	other := NewAuthService(userRepo, "another-secret-at-least-thirty-two-chars", time.Hour, svc.logger).(*authService)
+	other.now = svc.now
+	if _, err := other.Authenticate(result.Token); err == nil {
+		t.Error("Authenticate() accepted a token signed with another secret")
+	}
+
+	if _, err := svc.Authenticate(result.Token + "x"); err == nil {
+		t.Error("Authenticate() accepted a tampered token")
+	}
+
+	svc.now = func() time.Time { return issuedAt.Add(2 * time.Hour) }
+	_, err = svc.Authenticate(result.Token)
+	assertUnauthorized(t, err)
+}
+
+func TestAuthService_EnsureAdmin(t *testing.T) {
+	svc, userRepo := newTestAuthService(t)
+
+	userRepo.EXPECT().GetByEmail(gomock.Any(), "admin@example.com").Return(nil, models.ErrNotFoundWithMsg("user not found"))
+	userRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, user *models.User) error {
+			if user.Role != models.RoleAdmin {
+				t.Errorf("Create() role = %q, want admin", user.Role)
+			}
+			if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte("bootstrap-password")) != nil {
+				t.Error("Create() password hash does not match the password")
+			}
+			return nil
+		})
+	if err := svc.EnsureAdmin(context.Background(), "Admin@example.com", "bootstrap-password"); err != nil {
+		t.Fatalf("EnsureAdmin() error = %v", err)
+	}
+
+	// An existing user is left alone, whatever the configured password
+	userRepo.EXPECT().GetByEmail(gomock.Any(), "admin@example.com").Return(&models.User{ID: 1, Role: models.RoleViewer}, nil)
+	if err := svc.EnsureAdmin(context.Background(), "admin@example.com", "short"); err != nil {
+		t.Fatalf("EnsureAdmin() error = %v", err)
+	}
+}
+
+func TestUserService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	svc := NewUserService(userRepo, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	userRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	user, err := svc.Create(context.Background(), &CreateUserRequest{Email: "Editor@Example.com", Password: "long enough password", Role: models.RoleEditor})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if user.Email != "editor@example.com" || user.PasswordHash == "" {
+		t.Errorf("Create() = %+v, want lowercased email and a password hash", user)
+	}
+
+	invalid := []*CreateUserRequest{
+		{Email: "editor@example.com", Password: "short", Role: models.RoleEditor},
+		{Email: "editor@example.com", Password: "long enough password", Role: "owner"},
+		{Email: "editor", Password: "long enough password", Role: models.RoleEditor},
+	}
+	for _, req := range invalid {
+		var appErr *models.AppError
+		if _, err := svc.Create(context.Background(), req); !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+			t.Errorf("Create(%+v) error = %v, want INVALID_INPUT", req, err)
+		}
+	}
+}
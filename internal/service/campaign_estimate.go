@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// Estimate renders the campaign's template for every customer of its audience,
+// or of a segment, and counts the messages and SMS segments a send would
+// take. Long messages and characters outside the GSM 7-bit alphabet multiply
+// the segments, so the cost is worked out per message. Customers who are
+// opted out, snoozed or bounced are counted as skipped. The recommender is not
+// called, so {recommended_product} renders as each customer's preferred
+// product; test campaigns are estimated at no cost, as they are sent.
+func (s *campaignService) Estimate(ctx context.Context, campaignID int64, req *EstimateCampaignRequest) (*CampaignEstimate, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	campaign, err := s.campaignRepo.GetByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	audience := &SendCampaignRequest{SegmentID: req.SegmentID}
+	if req.SegmentID == nil {
+		if campaign.Audience == nil {
+			return nil, models.ErrInvalidInput("segment_id is required (the campaign has no bound audience)")
+		}
+		audience = audienceRequest(campaign.Audience)
+	}
+	source, err := s.newAudienceSource(ctx, audience)
+	if err != nil {
+		return nil, err
+	}
+
+	expanded, err := s.templateSvc.ExpandPartials(ctx, campaign.BaseTemplate)
+	if err != nil {
+		return nil, err
+	}
+	compiled := s.templateSvc.Compile(expanded).ForCampaign(campaign)
+
+	estimate := &CampaignEstimate{
+		CampaignID:         campaign.ID,
+		Channel:            campaign.Channel,
+		MessagesBySegments: make(map[int]int64),
+	}
+	if !campaign.IsTest() {
+		estimate.Rate = s.config.MessageCosts[campaign.Channel]
+	}
+
+	now := time.Now()
+	for {
+		page, err := source.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(page) == 0 {
+			break
+		}
+
+		for _, customer := range page {
+			if customer.IsOptedOut() || customer.IsSnoozed(now) || customer.IsBounced() {
+				estimate.Skipped++
+				continue
+			}
+
+			rendered, err := compiled.Render(customer)
+			if err != nil {
+				return nil, fmt.Errorf("failed to render message for customer %d: %w", customer.ID, err)
+			}
+
+			estimate.Messages++
+			estimate.EstimatedCost += models.MessageCost(campaign.Channel, rendered, estimate.Rate)
+			if campaign.Channel == models.ChannelSMS {
+				segments := s.templateSvc.MeasureSMS(rendered).Segments
+				estimate.TotalSegments += int64(segments)
+				estimate.MessagesBySegments[segments]++
+			}
+		}
+	}
+
+	return estimate, nil
+}
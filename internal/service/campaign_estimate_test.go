@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"maps"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestCampaignService_Estimate(t *testing.T) {
+	optedOutAt := time.Now()
+	bouncedAt := time.Now()
+	customers := &customerStore{
+		byID: map[int64]*models.Customer{
+			1: {ID: 1, Phone: "+254700000001", FirstName: "Alice"},
+			2: {ID: 2, Phone: "+254700000002", FirstName: "Bob", OptedOutAt: &optedOutAt},
+			3: {ID: 3, Phone: "+254700000003", FirstName: "Zoë"},
+			4: {ID: 4, Phone: "+254700000004", FirstName: strings.Repeat("a", 150)},
+			5: {ID: 5, Phone: "+254700000005", FirstName: "Dan", BouncedAt: &bouncedAt},
+		},
+	}
+	all := &models.CampaignAudience{Target: SendTargetAll}
+	campaigns := &campaignStore{
+		all: []*models.Campaign{
+			{ID: 1, Channel: models.ChannelSMS, BaseTemplate: "Hi {first_name}, sale today", Audience: all},
+			{ID: 2, Channel: models.ChannelWhatsApp, BaseTemplate: "Hi {first_name}", Audience: all},
+			{ID: 3, Channel: models.ChannelSMS, BaseTemplate: "Hi {first_name}"},
+		},
+	}
+	svc := &campaignService{
+		campaignRepo: campaigns.repo(t),
+		customerRepo: customers.repo(t),
+		templateSvc:  NewTemplateService(nil, nil),
+		config: CampaignServiceConfig{
+			SendBatchSize: 2,
+			MessageCosts:  map[string]float64{models.ChannelSMS: 0.5, models.ChannelWhatsApp: 2},
+		},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	// Alice fits one GSM-7 segment, Zoë's name needs UCS-2 and the long name
+	// spills into a second segment
+	estimate, err := svc.Estimate(context.Background(), 1, &EstimateCampaignRequest{})
+	if err != nil {
+		t.Fatalf("Estimate() error = %v", err)
+	}
+	if estimate.Messages != 3 || estimate.Skipped != 2 {
+		t.Errorf("Estimate() = %d messages, %d skipped, want 3 and 2", estimate.Messages, estimate.Skipped)
+	}
+	if estimate.TotalSegments != 4 || !maps.Equal(estimate.MessagesBySegments, map[int]int64{1: 2, 2: 1}) {
+		t.Errorf("Estimate() segments = %d %v, want 4 map[1:2 2:1]", estimate.TotalSegments, estimate.MessagesBySegments)
+	}
+	if math.Abs(estimate.EstimatedCost-2) > 1e-9 {
+		t.Errorf("EstimatedCost = %v, want 2", estimate.EstimatedCost)
+	}
+
+	// Other channels are priced per message
+	estimate, err = svc.Estimate(context.Background(), 2, &EstimateCampaignRequest{})
+	if err != nil {
+		t.Fatalf("Estimate(whatsapp) error = %v", err)
+	}
+	if estimate.TotalSegments != 0 || math.Abs(estimate.EstimatedCost-6) > 1e-9 {
+		t.Errorf("Estimate(whatsapp) = %d segments costing %v, want 0 costing 6", estimate.TotalSegments, estimate.EstimatedCost)
+	}
+
+	// Without a bound audience a segment must be named
+	var appErr *models.AppError
+	if _, err := svc.Estimate(context.Background(), 3, &EstimateCampaignRequest{}); !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+		t.Errorf("Estimate(no audience) error = %v, want INVALID_INPUT", err)
+	}
+}
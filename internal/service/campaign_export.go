@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// Export returns the campaign's portable definition
+func (s *campaignService) Export(ctx context.Context, campaignID int64) (*CampaignDefinition, error) {
+	campaign, err := s.campaignRepo.GetByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.definitionOf(campaign), nil
+}
+
+// Import creates a new campaign from a definition. The campaign starts as a
+// draft, or scheduled when the definition has a schedule, exactly as if it had
+// been created through the API.
+func (s *campaignService) Import(ctx context.Context, definition *CampaignDefinition) (*models.Campaign, error) {
+	if err := s.checkDefinition(definition); err != nil {
+		return nil, err
+	}
+
+	campaign, err := s.Create(ctx, definition.createRequest())
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("campaign imported", slog.Int64("campaign_id", campaign.ID))
+
+	return campaign, nil
+}
+
+// checkDefinition validates a definition and that its variables, when given,
+// match the template
+func (s *campaignService) checkDefinition(definition *CampaignDefinition) error {
+	if err := definition.Validate(); err != nil {
+		return err
+	}
+
+	if definition.Variables != nil {
+		want := s.templateVariables(definition.Template)
+		got := slices.Clone(definition.Variables)
+		slices.Sort(want)
+		slices.Sort(got)
+		if !slices.Equal(slices.Compact(got), want) {
+			return models.ErrInvalidInput(fmt.Sprintf(
+				"variables [%s] do not match the template's placeholders [%s]",
+				strings.Join(definition.Variables, ", "), strings.Join(want, ", "),
+			))
+		}
+	}
+
+	return nil
+}
+
+// definitionOf builds the portable definition of a campaign
+func (s *campaignService) definitionOf(campaign *models.Campaign) *CampaignDefinition {
+	labels := campaign.Labels
+	if labels == nil {
+		labels = []string{}
+	}
+
+	return &CampaignDefinition{
+		Version:  CampaignDefinitionVersion,
+		Name:     campaign.Name,
+		Channel:  campaign.Channel,
+		Template: campaign.BaseTemplate,
+		SenderID: campaign.SenderID,
+		Schedule: CampaignSchedule{
+			ScheduledAt:     campaign.ScheduledAt,
+			DeliveryWindows: campaign.DeliveryWindows,
+			Timezone:        campaign.Timezone,
+		},
+		Variables: s.templateVariables(campaign.BaseTemplate),
+		Labels:    labels,
+		Locale:    campaign.Locale,
+		UTM:       campaign.UTM,
+	}
+}
+
+// templateVariables lists the distinct placeholders of a template in first-use order
+func (s *campaignService) templateVariables(template string) []string {
+	return uniquePlaceholders(s.templateSvc.ExtractPlaceholders(template))
+}
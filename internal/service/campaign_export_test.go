@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func newTestExportService(campaignRepo *mocks.MockCampaignRepository) CampaignService {
+	return NewCampaignService(
+		campaignRepo,
+		nil,
+		nil,
+		nil,
+		NewTemplateService(nil, nil),
+		nil,
+		CampaignServiceConfig{},
+		slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	)
+}
+
+func TestCampaignService_ExportImport_RoundTrip(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+	svc := newTestExportService(campaignRepo)
+
+	sender := "ACME"
+	scheduledAt := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+	source := &models.Campaign{
+		ID:              7,
+		Name:            "Black Friday",
+		Channel:         models.ChannelSMS,
+		Status:          models.CampaignStatusSent,
+		BaseTemplate:    "Hi {first_name}, {preferred_product} is 30% off. Bye {first_name}",
+		SenderID:        &sender,
+		DeliveryWindows: models.DeliveryWindows{{Days: []string{"mon"}, StartHour: 9, EndHour: 17}},
+		Timezone:        "Africa/Nairobi",
+		ScheduledAt:     &scheduledAt,
+		Labels:          []string{"retail", "q4"},
+	}
+	campaignRepo.EXPECT().GetByID(gomock.Any(), int64(7)).Return(source, nil)
+
+	definition, err := svc.Export(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if want := []string{"first_name", "preferred_product"}; len(definition.Variables) != 2 || definition.Variables[0] != want[0] || definition.Variables[1] != want[1] {
+		t.Errorf("Variables = %v, want %v", definition.Variables, want)
+	}
+
+	campaignRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, campaign *models.Campaign) error {
+			campaign.ID = 99
+			return nil
+		})
+
+	imported, err := svc.Import(context.Background(), definition)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	if imported.ID != 99 || imported.Name != source.Name || imported.BaseTemplate != source.BaseTemplate {
+		t.Errorf("imported campaign = %+v, want a copy of campaign 7", imported)
+	}
+	if imported.Status != models.CampaignStatusScheduled {
+		t.Errorf("Status = %s, want scheduled", imported.Status)
+	}
+	if len(imported.Labels) != 2 || len(imported.DeliveryWindows) != 1 || imported.Timezone != source.Timezone || *imported.SenderID != sender {
+		t.Errorf("imported campaign = %+v, want labels, windows, timezone and sender carried over", imported)
+	}
+}
+
+func TestCampaignService_Import_Invalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition CampaignDefinition
+	}{
+		{
+			name:       "missing version",
+			definition: CampaignDefinition{Name: "A", Channel: models.ChannelSMS, Template: "Hi"},
+		},
+		{
+			name:       "future version",
+			definition: CampaignDefinition{Version: 2, Name: "A", Channel: models.ChannelSMS, Template: "Hi"},
+		},
+		{
+			name:       "variables do not match template",
+			definition: CampaignDefinition{Version: 1, Name: "A", Channel: models.ChannelSMS, Template: "Hi {first_name}", Variables: []string{"last_name"}},
+		},
+		{
+			name: "schedule in the past",
+			definition: CampaignDefinition{Version: 1, Name: "A", Channel: models.ChannelSMS, Template: "Hi",
+				Schedule: CampaignSchedule{ScheduledAt: func() *time.Time { t := time.Now().Add(-time.Hour); return &t }()}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			svc := newTestExportService(mocks.NewMockCampaignRepository(ctrl))
+
+			if _, err := svc.Import(context.Background(), &tt.definition); err == nil {
+				t.Error("Import() error = nil, want invalid input")
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// PreviewSample renders the campaign's template for a sample of its audience,
+// or of a segment, flagging the placeholders each customer has no value for.
+// Customers who are opted out or snoozed are never sampled.
+func (s *campaignService) PreviewSample(ctx context.Context, campaignID int64, req *PreviewSampleRequest) (*PreviewSampleResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	campaign, err := s.campaignRepo.GetByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	audience := &SendCampaignRequest{SegmentID: req.SegmentID}
+	if req.SegmentID == nil {
+		if campaign.Audience == nil {
+			return nil, models.ErrInvalidInput("segment_id is required (the campaign has no bound audience)")
+		}
+		audience = audienceRequest(campaign.Audience)
+	}
+	source, err := s.newAudienceSource(ctx, audience)
+	if err != nil {
+		return nil, err
+	}
+
+	templateToUse := campaign.BaseTemplate
+	override := req.OverrideTemplate != nil && *req.OverrideTemplate != ""
+	if override {
+		templateToUse = *req.OverrideTemplate
+	}
+	expanded, err := s.templateSvc.ExpandPartials(ctx, templateToUse)
+	if err != nil {
+		return nil, err
+	}
+	if override {
+		if err := s.templateSvc.ValidateTemplate(ctx, expanded); err != nil {
+			return nil, err
+		}
+	}
+
+	size := req.Size
+	if size == 0 {
+		size = defaultPreviewSampleSize
+	}
+	var customers []*models.Customer
+	if req.Pick == PreviewSamplePickFirst {
+		customers, err = firstEligible(ctx, source, size)
+	} else {
+		customers, err = source.Sample(ctx, size)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	audienceSize, err := source.Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	compiled := s.templateSvc.Compile(expanded).ForCampaign(campaign)
+	placeholders := uniquePlaceholders(s.templateSvc.ExtractPlaceholders(expanded))
+	products := recommendations(ctx, s.config.Recommender, compiled, customers, s.logger)
+
+	result := &PreviewSampleResult{
+		CampaignID:   campaign.ID,
+		UsedTemplate: templateToUse,
+		Placeholders: placeholders,
+		AudienceSize: audienceSize,
+		Samples:      make([]*SamplePreview, 0, len(customers)),
+	}
+	for _, customer := range customers {
+		recommended := recommendedValues(products, customer)
+		rendered, err := compiled.RenderWith(customer, recommended)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render message for customer %d: %w", customer.ID, err)
+		}
+
+		missing := make([]string, 0)
+		for _, placeholder := range placeholders {
+			if recommended[placeholder] == "" && customerFieldValue(customer, placeholder) == "" {
+				missing = append(missing, placeholder)
+			}
+		}
+
+		encoding, _, _ := models.SMSEncoding(rendered)
+		result.Samples = append(result.Samples, &SamplePreview{
+			Customer:      &CustomerPreview{ID: customer.ID, FirstName: customer.FirstName},
+			Rendered:      rendered,
+			Length:        utf8.RuneCountInString(rendered),
+			Encoding:      encoding,
+			Segments:      models.SMSSegments(rendered),
+			MissingFields: missing,
+		})
+	}
+
+	return result, nil
+}
+
+// firstEligible returns the first limit customers of source, in ID order,
+// who are neither opted out, snoozed nor bounced
+func firstEligible(ctx context.Context, source audienceSource, limit int) ([]*models.Customer, error) {
+	now := time.Now()
+	eligible := make([]*models.Customer, 0, limit)
+	for len(eligible) < limit {
+		page, err := source.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(page) == 0 {
+			break
+		}
+		for _, customer := range page {
+			if len(eligible) < limit && !customer.IsOptedOut() && !customer.IsSnoozed(now) && !customer.IsBounced() {
+				eligible = append(eligible, customer)
+			}
+		}
+	}
+	return eligible, nil
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestCampaignService_PreviewSample(t *testing.T) {
+	optedOutAt := time.Now()
+	snoozedUntil := time.Now().Add(time.Hour)
+	customers := &customerStore{
+		byID: map[int64]*models.Customer{
+			1: {ID: 1, Phone: "+254700000001", FirstName: "Alice", Location: "Nairobi"},
+			2: {ID: 2, Phone: "+254700000002", FirstName: "Bob", Location: "Mombasa", OptedOutAt: &optedOutAt},
+			3: {ID: 3, Phone: "+254700000003", FirstName: "Carol", SnoozedUntil: &snoozedUntil},
+			4: {ID: 4, Phone: "+254700000004", FirstName: "Dan", Location: "Nairobi"},
+			5: {ID: 5, Phone: "+254700000005", Location: "Kisumu"},
+		},
+	}
+	campaigns := &campaignStore{
+		all: []*models.Campaign{
+			{ID: 1, BaseTemplate: "Hi {first_name} in {location}", Audience: &models.CampaignAudience{Target: SendTargetAll}},
+			{ID: 2, BaseTemplate: "Hi {first_name}"},
+		},
+	}
+	segmentRepo := mocks.NewMockSegmentRepository(gomock.NewController(t))
+	svc := &campaignService{
+		campaignRepo: campaigns.repo(t),
+		customerRepo: customers.repo(t),
+		segmentRepo:  segmentRepo,
+		templateSvc:  NewTemplateService(nil, nil),
+		config:       CampaignServiceConfig{SendBatchSize: 2},
+		logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	// The first customers skip those who are opted out or snoozed
+	result, err := svc.PreviewSample(context.Background(), 1, &PreviewSampleRequest{Size: 3, Pick: PreviewSamplePickFirst})
+	if err != nil {
+		t.Fatalf("PreviewSample() error = %v", err)
+	}
+	if result.AudienceSize != 5 || len(result.Samples) != 3 {
+		t.Fatalf("PreviewSample() = %d samples of %d, want 3 of 5", len(result.Samples), result.AudienceSize)
+	}
+	var ids []int64
+	for _, sample := range result.Samples {
+		ids = append(ids, sample.Customer.ID)
+	}
+	if !slices.Equal(ids, []int64{1, 4, 5}) {
+		t.Errorf("sampled customers = %v, want [1 4 5]", ids)
+	}
+	if got := result.Samples[0].Rendered; got != "Hi Alice in Nairobi" {
+		t.Errorf("Rendered = %q, want %q", got, "Hi Alice in Nairobi")
+	}
+	if got := result.Samples[2].MissingFields; !slices.Equal(got, []string{"first_name"}) {
+		t.Errorf("MissingFields = %v, want [first_name]", got)
+	}
+
+	// A segment is sampled in place of the bound audience
+	segmentID := int64(9)
+	segmentRepo.EXPECT().GetByID(gomock.Any(), segmentID).
+		Return(&models.Segment{ID: segmentID, Filter: models.SegmentFilter{Location: "Nairobi"}}, nil)
+	result, err = svc.PreviewSample(context.Background(), 2, &PreviewSampleRequest{SegmentID: &segmentID})
+	if err != nil {
+		t.Fatalf("PreviewSample(segment) error = %v", err)
+	}
+	if result.AudienceSize != 2 || len(result.Samples) != 2 {
+		t.Errorf("PreviewSample(segment) = %d samples of %d, want 2 of 2", len(result.Samples), result.AudienceSize)
+	}
+
+	// Without a bound audience a segment must be named
+	var appErr *models.AppError
+	if _, err := svc.PreviewSample(context.Background(), 2, &PreviewSampleRequest{}); !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+		t.Errorf("PreviewSample(no audience) error = %v, want INVALID_INPUT", err)
+	}
+	if _, err := svc.PreviewSample(context.Background(), 1, &PreviewSampleRequest{Size: maxPreviewSampleSize + 1}); !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+		t.Errorf("PreviewSample(too large) error = %v, want INVALID_INPUT", err)
+	}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// externalKeyPattern restricts external keys to characters that are safe in a URL path segment
+var externalKeyPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:-]{0,99}$`)
+
+// Provision makes the campaign under externalKey match the definition,
+// creating it if needed. Applying the definition the campaign already has is a
+// no-op, even after the campaign was sent, so repeated applies of an unchanged
+// configuration always succeed. Changing a campaign that has started sending is
+// a conflict.
+func (s *campaignService) Provision(ctx context.Context, externalKey string, definition *CampaignDefinition) (*ProvisionResult, error) {
+	if !externalKeyPattern.MatchString(externalKey) {
+		return nil, models.ErrInvalidInput("external_key must be 1-100 letters, digits, '.', '_', ':' or '-', starting with a letter or digit")
+	}
+
+	existing, err := s.campaignRepo.GetByExternalKey(ctx, externalKey)
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		return nil, err
+	}
+	if existing != nil && s.matchesDefinition(existing, definition) {
+		return &ProvisionResult{Outcome: ProvisionUnchanged, Campaign: existing}, nil
+	}
+
+	if err := s.checkDefinition(definition); err != nil {
+		return nil, err
+	}
+
+	campaign, err := s.newCampaign(ctx, definition.createRequest())
+	if err != nil {
+		return nil, err
+	}
+	campaign.ExternalKey = &externalKey
+	if existing != nil {
+		// Definitions carry no audience or cost cap, so provisioning keeps them
+		campaign.Audience = existing.Audience
+		campaign.MaxCost = existing.MaxCost
+	}
+
+	created, err := s.campaignRepo.UpsertByExternalKey(ctx, campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	outcome := ProvisionUpdated
+	if created {
+		outcome = ProvisionCreated
+		s.publishCreated(ctx, campaign)
+	}
+
+	s.logger.Info("campaign provisioned",
+		slog.Int64("campaign_id", campaign.ID),
+		slog.String("external_key", externalKey),
+		slog.String("outcome", outcome),
+	)
+
+	return &ProvisionResult{Outcome: outcome, Campaign: campaign}, nil
+}
+
+// matchesDefinition reports whether applying the definition would leave the
+// campaign unchanged
+func (s *campaignService) matchesDefinition(campaign *models.Campaign, definition *CampaignDefinition) bool {
+	current := s.definitionOf(campaign)
+
+	labels, err := models.NormalizeLabels(definition.Labels)
+	if err != nil {
+		return false
+	}
+
+	return current.Name == definition.Name &&
+		current.Channel == definition.Channel &&
+		current.Template == definition.Template &&
+		equalStringPtr(current.SenderID, definition.SenderID) &&
+		equalTimePtr(current.Schedule.ScheduledAt, definition.Schedule.ScheduledAt) &&
+		fmt.Sprint(current.Schedule.DeliveryWindows) == fmt.Sprint(definition.Schedule.DeliveryWindows) &&
+		cmp.Or(current.Schedule.Timezone, models.DefaultTimezone) == cmp.Or(definition.Schedule.Timezone, models.DefaultTimezone) &&
+		slices.Equal(current.Labels, labels)
+}
+
+// equalStringPtr reports whether two optional strings are both unset or equal
+func equalStringPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// equalTimePtr reports whether two optional times are both unset or the same instant
+func equalTimePtr(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
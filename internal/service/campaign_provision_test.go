@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestCampaignService_Provision(t *testing.T) {
+	definition := CampaignDefinition{
+		Version:  CampaignDefinitionVersion,
+		Name:     "Weekly digest",
+		Channel:  models.ChannelSMS,
+		Template: "Hi {first_name}, here is your digest",
+		Labels:   []string{"digest", " weekly "},
+	}
+	key := "weekly-digest"
+	applied := &models.Campaign{
+		ID:           3,
+		Name:         definition.Name,
+		Channel:      definition.Channel,
+		Status:       models.CampaignStatusSent,
+		BaseTemplate: definition.Template,
+		Labels:       []string{"digest", "weekly"},
+		ExternalKey:  &key,
+	}
+
+	t.Run("creates when the key is new", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+		svc := newTestExportService(campaignRepo)
+
+		campaignRepo.EXPECT().GetByExternalKey(gomock.Any(), key).Return(nil, models.ErrNotFoundWithMsg("not found"))
+		campaignRepo.EXPECT().UpsertByExternalKey(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, campaign *models.Campaign) (bool, error) {
+				if campaign.ExternalKey == nil || *campaign.ExternalKey != key {
+					t.Errorf("ExternalKey = %v, want %q", campaign.ExternalKey, key)
+				}
+				if campaign.Status != models.CampaignStatusDraft {
+					t.Errorf("Status = %s, want draft", campaign.Status)
+				}
+				campaign.ID = 3
+				return true, nil
+			})
+
+		result, err := svc.Provision(context.Background(), key, &definition)
+		if err != nil {
+			t.Fatalf("Provision() error = %v", err)
+		}
+		if result.Outcome != ProvisionCreated || result.Campaign.ID != 3 {
+			t.Errorf("result = %+v, want campaign 3 created", result)
+		}
+	})
+
+	t.Run("unchanged definition is a no-op even after sending", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+		svc := newTestExportService(campaignRepo)
+
+		campaignRepo.EXPECT().GetByExternalKey(gomock.Any(), key).Return(applied, nil)
+		// No upsert expected
+
+		result, err := svc.Provision(context.Background(), key, &definition)
+		if err != nil {
+			t.Fatalf("Provision() error = %v", err)
+		}
+		if result.Outcome != ProvisionUnchanged {
+			t.Errorf("Outcome = %s, want unchanged", result.Outcome)
+		}
+	})
+
+	t.Run("changing a sent campaign conflicts", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+		svc := newTestExportService(campaignRepo)
+
+		changed := definition
+		changed.Template = "Hello {first_name}, here is your digest"
+		campaignRepo.EXPECT().GetByExternalKey(gomock.Any(), key).Return(applied, nil)
+		campaignRepo.EXPECT().UpsertByExternalKey(gomock.Any(), gomock.Any()).
+			Return(false, models.ErrConflictWithMsg("campaign has already started sending"))
+
+		if _, err := svc.Provision(context.Background(), key, &changed); !errors.Is(err, models.ErrConflict) {
+			t.Errorf("Provision() error = %v, want conflict", err)
+		}
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		svc := newTestExportService(mocks.NewMockCampaignRepository(ctrl))
+
+		if _, err := svc.Provision(context.Background(), "-bad key", &definition); err == nil {
+			t.Error("Provision() error = nil, want invalid input")
+		}
+	})
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/tracing"
+)
+
+// retryFailedBatchSize is how many failed messages are reset and requeued at a time
+const retryFailedBatchSize = 1000
+
+// defaultMaxRetryCount is the number of send attempts a message gets when
+// CampaignServiceConfig sets none, matching MAX_RETRY_COUNT's default
+const defaultMaxRetryCount = 3
+
+// RetryFailed moves the failed messages matching the request back to pending
+// and requeues them, batch by batch, across every campaign and account. It is
+// the operator's remediation for an incident such as a provider outage. A dry
+// run only counts the messages, and the retry itself must confirm that count.
+func (s *campaignService) RetryFailed(ctx context.Context, req *RetryFailedRequest) (result *RetryFailedResult, err error) {
+	ctx, span := tracer.Start(ctx, "CampaignService.RetryFailed",
+		trace.WithAttributes(attribute.Bool("dry_run", req.DryRun)),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	req.ErrorContains = strings.TrimSpace(req.ErrorContains)
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	window := models.FailedMessageWindow{
+		From:          req.From.UTC(),
+		To:            req.To.UTC(),
+		ErrorContains: req.ErrorContains,
+		Channel:       req.Channel,
+	}
+
+	// An incident fails the sends of every account alike
+	ctx = models.WithAccountID(ctx, 0)
+
+	matched, campaigns, err := s.messageRepo.CountFailedInWindow(ctx, window)
+	if err != nil {
+		return nil, err
+	}
+	result = &RetryFailedResult{
+		DryRun:           req.DryRun,
+		MessagesMatched:  matched,
+		CampaignsMatched: campaigns,
+	}
+	if req.DryRun || matched == 0 {
+		return result, nil
+	}
+
+	if req.ConfirmMessageCount == nil {
+		return nil, models.ErrConfirmationRequired(fmt.Sprintf(
+			"%d failed messages in %d campaigns match; repeat the request with \"confirm_message_count\": %d",
+			matched, campaigns, matched,
+		))
+	}
+	if *req.ConfirmMessageCount != matched {
+		return nil, models.ErrConfirmationRequired(fmt.Sprintf(
+			"confirm_message_count is %d but %d failed messages match; run a dry run and repeat the request with \"confirm_message_count\": %d",
+			*req.ConfirmMessageCount, matched, matched,
+		))
+	}
+
+	// Each batch is reset before it is published; messages reset but not
+	// published because the request ends are left to the outbox relay. The
+	// batches follow message IDs, so a message that fails again while the
+	// retry runs is not reset a second time.
+	var lastID int64
+	for {
+		if err := ctx.Err(); err != nil {
+			s.logger.Warn("retry of failed messages stopped before all were requeued",
+				slog.Int("messages_reset", result.MessagesReset),
+				slog.Int("messages_requeued", result.MessagesRequeued),
+			)
+			return nil, fmt.Errorf("retry stopped after resetting %d messages: %w", result.MessagesReset, err)
+		}
+
+		batch, err := s.messageRepo.ResetFailedInWindow(ctx, window, lastID, retryFailedBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(batch) == 0 {
+			break
+		}
+		lastID = batch[len(batch)-1].ID
+
+		result.MessagesReset += len(batch)
+		result.MessagesRequeued += s.publishMessages(ctx, batch)
+	}
+
+	s.logger.Info("failed messages retried",
+		slog.Time("from", window.From),
+		slog.Time("to", window.To),
+		slog.String("error_contains", window.ErrorContains),
+		slog.String("channel", window.Channel),
+		slog.Int64("campaigns_matched", campaigns),
+		slog.Int("messages_reset", result.MessagesReset),
+		slog.Int("messages_requeued", result.MessagesRequeued),
+	)
+
+	return result, nil
+}
+
+// RetryCampaignFailed moves the failed messages of one campaign that still
+// have attempts left back to pending and requeues them. Unlike RetryFailed it
+// keeps their retry counts, so each message only gets the attempts it has
+// left. The campaign must be sending or finished; a finished one is sending
+// again until the messages settle.
+func (s *campaignService) RetryCampaignFailed(ctx context.Context, campaignID int64) (result *RetryCampaignFailedResult, err error) {
+	ctx, span := tracer.Start(ctx, "CampaignService.RetryCampaignFailed",
+		trace.WithAttributes(attribute.Int64("campaign_id", campaignID)),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	campaign, err := s.campaignRepo.GetByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	ctx = models.WithAccountID(ctx, campaign.AccountID)
+
+	switch campaign.Status {
+	case models.CampaignStatusSending, models.CampaignStatusSent, models.CampaignStatusFailed:
+	default:
+		return nil, models.ErrConflictWithMsg(
+			fmt.Sprintf("only sending or finished campaigns can retry failed messages (current status: %s)", campaign.Status),
+		)
+	}
+
+	ids, err := s.messageRepo.ResetFailedByCampaign(ctx, campaignID, s.config.MaxRetryCount)
+	if err != nil {
+		return nil, err
+	}
+	result = &RetryCampaignFailedResult{
+		CampaignID:    campaignID,
+		MessagesReset: len(ids),
+		Status:        campaign.Status,
+	}
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	if campaign.Status != models.CampaignStatusSending {
+		if _, err := s.campaignRepo.TransitionStatus(ctx, campaignID, campaign.Status, models.CampaignStatusSending); err != nil {
+			return nil, err
+		}
+	}
+
+	// A cancellation since the status was read must not send the messages
+	// just reset; they are skipped at once rather than by the worker one by one
+	current, err := s.campaignRepo.GetByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	result.Status = current.Status
+	if current.Status == models.CampaignStatusCancelled {
+		skipped, err := s.messageRepo.UpdateStatusBatch(ctx, ids, models.MessageStatusSkipped)
+		if err != nil {
+			return nil, err
+		}
+		s.logger.Warn("campaign cancelled while its failed messages were retried",
+			slog.Int64("campaign_id", campaignID),
+			slog.Int64("messages_skipped", skipped),
+		)
+		return nil, models.ErrConflictWithMsg(fmt.Sprintf("campaign was cancelled; its %d reset messages were skipped", skipped))
+	}
+
+	// Messages reset but not published because the request ends are left to
+	// the outbox relay
+	for start := 0; start < len(ids); start += retryFailedBatchSize {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("retry stopped after requeueing %d of %d messages: %w", result.MessagesRequeued, len(ids), err)
+		}
+
+		end := min(start+retryFailedBatchSize, len(ids))
+		batch := make([]*models.OutboundMessage, 0, end-start)
+		for _, id := range ids[start:end] {
+			batch = append(batch, &models.OutboundMessage{ID: id, CampaignID: campaignID, Status: models.MessageStatusPending})
+		}
+		result.MessagesRequeued += s.publishMessages(ctx, batch)
+	}
+
+	s.logger.Info("campaign failed messages retried",
+		slog.Int64("campaign_id", campaignID),
+		slog.Int("messages_reset", result.MessagesReset),
+		slog.Int("messages_requeued", result.MessagesRequeued),
+	)
+
+	return result, nil
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func outageRetryRequest() *RetryFailedRequest {
+	from := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	return &RetryFailedRequest{
+		From:          from,
+		To:            from.Add(30 * time.Minute),
+		ErrorContains: " provider unavailable ",
+	}
+}
+
+func TestCampaignService_RetryFailed_DryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	messageRepo := mocks.NewMockOutboundMessageRepository(ctrl)
+
+	req := outageRetryRequest()
+	req.DryRun = true
+
+	// Nothing is reset on a dry run
+	messageRepo.EXPECT().CountFailedInWindow(gomock.Any(), models.FailedMessageWindow{
+		From:          req.From,
+		To:            req.To,
+		ErrorContains: "provider unavailable",
+	}).Return(int64(2400), int64(3), nil)
+
+	svc := &campaignService{
+		messageRepo: messageRepo,
+		logger:      slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	}
+
+	result, err := svc.RetryFailed(context.Background(), req)
+	if err != nil {
+		t.Fatalf("RetryFailed() error = %v", err)
+	}
+	want := RetryFailedResult{DryRun: true, MessagesMatched: 2400, CampaignsMatched: 3}
+	if *result != want {
+		t.Errorf("RetryFailed() = %+v, want %+v", *result, want)
+	}
+}
+
+func TestCampaignService_RetryFailed_RequiresConfirmation(t *testing.T) {
+	for name, confirm := range map[string]*int64{"missing": nil, "stale": int64Ptr(2000)} {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			messageRepo := mocks.NewMockOutboundMessageRepository(ctrl)
+
+			messageRepo.EXPECT().CountFailedInWindow(gomock.Any(), gomock.Any()).Return(int64(2400), int64(3), nil)
+
+			svc := &campaignService{
+				messageRepo: messageRepo,
+				logger:      slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+			}
+
+			req := outageRetryRequest()
+			req.ConfirmMessageCount = confirm
+			_, err := svc.RetryFailed(context.Background(), req)
+
+			var appErr *models.AppError
+			if !errors.As(err, &appErr) || appErr.Code != "CONFIRMATION_REQUIRED" {
+				t.Errorf("RetryFailed() error = %v, want CONFIRMATION_REQUIRED", err)
+			}
+		})
+	}
+}
+
+func TestCampaignService_RetryFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	messageRepo := mocks.NewMockOutboundMessageRepository(ctrl)
+	queueClient := mocks.NewMockClient(ctrl)
+
+	batch := func(firstID int64, size int) []*models.OutboundMessage {
+		messages := make([]*models.OutboundMessage, size)
+		for i := range messages {
+			messages[i] = &models.OutboundMessage{ID: firstID + int64(i), CampaignID: 1 + int64(i%3)}
+		}
+		return messages
+	}
+
+	messageRepo.EXPECT().CountFailedInWindow(gomock.Any(), gomock.Any()).Return(int64(1200), int64(3), nil)
+	gomock.InOrder(
+		messageRepo.EXPECT().ResetFailedInWindow(gomock.Any(), gomock.Any(), int64(0), retryFailedBatchSize).Return(batch(1, 1000), nil),
+		messageRepo.EXPECT().ResetFailedInWindow(gomock.Any(), gomock.Any(), int64(1000), retryFailedBatchSize).Return(batch(1001, 200), nil),
+		messageRepo.EXPECT().ResetFailedInWindow(gomock.Any(), gomock.Any(), int64(1200), retryFailedBatchSize).Return([]*models.OutboundMessage{}, nil),
+	)
+	queueClient.EXPECT().Publish(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, job *models.MessageJob) error {
+		if job.CampaignID == 0 {
+			t.Errorf("job for message %d has no campaign ID", job.OutboundMessageID)
+		}
+		return nil
+	}).Times(1200)
+	messageRepo.EXPECT().MarkQueued(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	svc := &campaignService{
+		messageRepo: messageRepo,
+		queueClient: queueClient,
+		logger:      slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	}
+
+	req := outageRetryRequest()
+	req.ConfirmMessageCount = int64Ptr(1200)
+	result, err := svc.RetryFailed(context.Background(), req)
+	if err != nil {
+		t.Fatalf("RetryFailed() error = %v", err)
+	}
+	want := RetryFailedResult{MessagesMatched: 1200, CampaignsMatched: 3, MessagesReset: 1200, MessagesRequeued: 1200}
+	if *result != want {
+		t.Errorf("RetryFailed() = %+v, want %+v", *result, want)
+	}
+}
+
+func TestRetryFailedRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(req *RetryFailedRequest)
+	}{
+		{"missing window", func(req *RetryFailedRequest) { req.From = time.Time{} }},
+		{"inverted window", func(req *RetryFailedRequest) { req.To = req.From.Add(-time.Minute) }},
+		{"window too long", func(req *RetryFailedRequest) { req.To = req.From.Add(8 * 24 * time.Hour) }},
+		{"window ends in the future", func(req *RetryFailedRequest) {
+			req.From, req.To = time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+		}},
+		{"no error class", func(req *RetryFailedRequest) { req.ErrorContains = "" }},
+		{"unknown channel", func(req *RetryFailedRequest) { req.Channel = "fax" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := outageRetryRequest()
+			tt.mutate(req)
+
+			var appErr *models.AppError
+			if err := req.Validate(); !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+				t.Errorf("Validate() error = %v, want INVALID_INPUT", err)
+			}
+		})
+	}
+}
+
+func TestCampaignService_RetryCampaignFailed(t *testing.T) {
+	errMsg := "provider unavailable"
+	campaigns := &campaignStore{
+		all: []*models.Campaign{{ID: 1, Status: models.CampaignStatusSent}},
+	}
+	messages := &messageStore{
+		all: []*models.OutboundMessage{
+			{ID: 1, CampaignID: 1, Status: models.MessageStatusFailed, RetryCount: 1, LastError: &errMsg},
+			{ID: 2, CampaignID: 1, Status: models.MessageStatusFailed, RetryCount: 3, LastError: &errMsg},
+			{ID: 3, CampaignID: 1, Status: models.MessageStatusSent},
+			{ID: 4, CampaignID: 2, Status: models.MessageStatusFailed},
+		},
+	}
+	queueClient := &mockQueueClient{}
+
+	svc := NewCampaignService(
+		campaigns.repo(t),
+		nil,
+		messages.repo(t),
+		nil,
+		NewTemplateService(nil, nil),
+		queueClient,
+		CampaignServiceConfig{MaxRetryCount: 3},
+		slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	)
+
+	result, err := svc.RetryCampaignFailed(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("RetryCampaignFailed() error = %v", err)
+	}
+
+	// Only the message with attempts left is retried, and the campaign is
+	// sending again until it settles
+	want := RetryCampaignFailedResult{CampaignID: 1, MessagesReset: 1, MessagesRequeued: 1, Status: models.CampaignStatusSending}
+	if *result != want {
+		t.Errorf("RetryCampaignFailed() = %+v, want %+v", *result, want)
+	}
+	if len(queueClient.published) != 1 || queueClient.published[0].OutboundMessageID != 1 {
+		t.Errorf("jobs published = %+v, want message 1", queueClient.published)
+	}
+	if messages.all[1].Status != models.MessageStatusFailed || messages.all[3].Status != models.MessageStatusFailed {
+		t.Error("messages without attempts left or of other campaigns were reset")
+	}
+
+	// A draft has nothing to retry
+	campaigns.all[0].Status = models.CampaignStatusDraft
+	if _, err := svc.RetryCampaignFailed(context.Background(), 1); !errors.Is(err, models.ErrConflict) {
+		t.Errorf("RetryCampaignFailed() of a draft error = %v, want conflict", err)
+	}
+}
+
+func TestCampaignService_RetryCampaignFailed_CancelledMeanwhile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+	messageRepo := mocks.NewMockOutboundMessageRepository(ctrl)
+	queueClient := &mockQueueClient{}
+
+	// The campaign is cancelled between the status check and the reset
+	gomock.InOrder(
+		campaignRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Campaign{ID: 1, Status: models.CampaignStatusSending}, nil),
+		messageRepo.EXPECT().ResetFailedByCampaign(gomock.Any(), int64(1), 3).Return([]int64{7, 8}, nil),
+		campaignRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Campaign{ID: 1, Status: models.CampaignStatusCancelled}, nil),
+		messageRepo.EXPECT().UpdateStatusBatch(gomock.Any(), []int64{7, 8}, models.MessageStatusSkipped).Return(int64(2), nil),
+	)
+
+	svc := NewCampaignService(
+		campaignRepo,
+		nil,
+		messageRepo,
+		nil,
+		NewTemplateService(nil, nil),
+		queueClient,
+		CampaignServiceConfig{},
+		slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	)
+
+	if _, err := svc.RetryCampaignFailed(context.Background(), 1); !errors.Is(err, models.ErrConflict) {
+		t.Errorf("RetryCampaignFailed() error = %v, want conflict", err)
+	}
+	if len(queueClient.published) != 0 {
+		t.Errorf("jobs published = %d, want none", len(queueClient.published))
+	}
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// Report builds the campaign's report from its message events and messages.
+// The funnel and send latency come from message events, so messages sent
+// before events were recorded only show in the stats, failure reasons and
+// retries.
+func (s *campaignService) Report(ctx context.Context, campaignID int64, bucket string) (*models.CampaignReport, error) {
+	if s.config.Reports == nil {
+		return nil, models.ErrInvalidInput("campaign reports are not supported here")
+	}
+	if bucket == "" {
+		bucket = models.ReportBucketMinute
+	}
+	if err := models.ValidateReportBucket(bucket); err != nil {
+		return nil, err
+	}
+
+	// Also checks that the campaign exists in the account of ctx
+	campaign, err := s.GetByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	funnel, err := s.config.Reports.Funnel(ctx, campaignID, bucket)
+	if err != nil {
+		return nil, err
+	}
+	reasons, err := s.config.Reports.FailureReasons(ctx, campaignID, models.MaxReportFailureReasons)
+	if err != nil {
+		return nil, err
+	}
+	retries, err := s.config.Reports.Retries(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	latency, err := s.config.Reports.SendLatency(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CampaignReport{
+		CampaignID:     campaign.ID,
+		Stats:          campaign.Stats,
+		Bucket:         bucket,
+		Funnel:         funnel,
+		FailureReasons: reasons,
+		Retries:        retries,
+		Latency:        *latency,
+	}, nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestCampaignService_Report(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	reportRepo := mocks.NewMockCampaignReportRepository(ctrl)
+	svc := &campaignService{
+		campaignRepo: (&campaignStore{all: []*models.Campaign{{ID: 1, Channel: models.ChannelSMS}}}).repo(t),
+		config:       CampaignServiceConfig{Reports: reportRepo},
+		logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	start := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	average := 4.5
+	reportRepo.EXPECT().Funnel(gomock.Any(), int64(1), models.ReportBucketHour).
+		Return([]*models.FunnelBucket{{Start: start, Queued: 10, Sent: 8, Failed: 2}}, nil)
+	reportRepo.EXPECT().FailureReasons(gomock.Any(), int64(1), models.MaxReportFailureReasons).
+		Return([]*models.FailureReasonCount{{Reason: "customer opted out", Messages: 2}}, nil)
+	reportRepo.EXPECT().Retries(gomock.Any(), int64(1)).
+		Return([]*models.RetryCount{{Retries: 0, Messages: 9}, {Retries: 1, Messages: 1}}, nil)
+	reportRepo.EXPECT().SendLatency(gomock.Any(), int64(1)).
+		Return(&models.SendLatency{Messages: 8, AverageSeconds: &average}, nil)
+
+	report, err := svc.Report(context.Background(), 1, models.ReportBucketHour)
+	if err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+	if report.Bucket != models.ReportBucketHour || len(report.Funnel) != 1 || report.Funnel[0].Sent != 8 {
+		t.Errorf("Report() funnel = %s %v, want one hour bucket with 8 sent", report.Bucket, report.Funnel)
+	}
+	if len(report.FailureReasons) != 1 || len(report.Retries) != 2 || report.Latency.Messages != 8 {
+		t.Errorf("Report() = %+v, want the repository's aggregates", report)
+	}
+
+	// Buckets other than minute and hour are refused before any query
+	var appErr *models.AppError
+	if _, err := svc.Report(context.Background(), 1, "week"); !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+		t.Errorf("Report(week) error = %v, want INVALID_INPUT", err)
+	}
+	if _, err := svc.Report(context.Background(), 2, ""); !errors.As(err, &appErr) || appErr.Code != "NOT_FOUND" {
+		t.Errorf("Report(unknown campaign) error = %v, want NOT_FOUND", err)
+	}
+}
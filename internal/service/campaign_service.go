@@ -2,29 +2,116 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
+	"sync"
+	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/events"
 	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
 	"github.com/Raymond9734/campaign-messaging-backend/internal/queue"
 	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/tracing"
 )
 
+// tracer starts the spans of sends, whose queued jobs link back to them
+var tracer = otel.Tracer("github.com/Raymond9734/campaign-messaging-backend/internal/service")
+
 // CampaignService handles campaign business logic
 type CampaignService interface {
 	Create(ctx context.Context, req *CreateCampaignRequest) (*models.Campaign, error)
 	GetByID(ctx context.Context, id int64) (*models.CampaignWithStats, error)
+	GetByExternalID(ctx context.Context, externalID string) (*models.CampaignWithStats, error)
+	GetBySlug(ctx context.Context, slug string) (*models.CampaignWithStats, error)
 	List(ctx context.Context, filter models.CampaignFilter) (*CampaignListResult, error)
+	ListUpdatedSince(ctx context.Context, cursor string, limit int) (*CampaignChangesResult, error)
 	SendCampaign(ctx context.Context, campaignID int64, req *SendCampaignRequest) (*SendCampaignResult, error)
+	Prebuild(ctx context.Context, campaignID int64) (*PrebuildResult, error)
+	// ApproveSendJob releases the messages of a send held for a second
+	// approval. approverID must differ from the user who requested the send.
+	ApproveSendJob(ctx context.Context, jobID int64, approverID *int64) (*SendCampaignResult, error)
 	PreviewPersonalized(ctx context.Context, campaignID int64, req *PreviewRequest) (*PreviewResult, error)
+	// PreviewSample renders the campaign for a sample of its audience
+	PreviewSample(ctx context.Context, campaignID int64, req *PreviewSampleRequest) (*PreviewSampleResult, error)
+	// Estimate counts the messages and SMS segments sending the campaign
+	// would take, and what they would cost
+	Estimate(ctx context.Context, campaignID int64, req *EstimateCampaignRequest) (*CampaignEstimate, error)
+	// Report breaks the campaign's sends down over time, by failure reason
+	// and by retries, with its send latency
+	Report(ctx context.Context, campaignID int64, bucket string) (*models.CampaignReport, error)
+	Delete(ctx context.Context, id int64, force bool) error
+	StreamMessages(ctx context.Context, campaignID, afterID int64, fn func(page []*models.OutboundMessage) error) error
+	StreamFailures(ctx context.Context, campaignID int64, fn func(page []*models.FailedRecipient) error) error
+	Pause(ctx context.Context, campaignID int64, req *PauseCampaignRequest) (*PauseCampaignResult, error)
+	Resume(ctx context.Context, campaignID int64) (*ResumeCampaignResult, error)
+	Cancel(ctx context.Context, campaignID int64) (*CancelCampaignResult, error)
+	RetryFailed(ctx context.Context, req *RetryFailedRequest) (*RetryFailedResult, error)
+	// RetryCampaignFailed requeues the failed messages of a campaign that
+	// have attempts left
+	RetryCampaignFailed(ctx context.Context, campaignID int64) (*RetryCampaignFailedResult, error)
+	SetMaxCost(ctx context.Context, campaignID int64, req *SetMaxCostRequest) (*models.CampaignWithStats, error)
+	SetMaxInFlight(ctx context.Context, campaignID int64, req *SetMaxInFlightRequest) (*models.CampaignWithStats, error)
+	// SetValidateNumbers turns number lookups before sending on or off
+	SetValidateNumbers(ctx context.Context, campaignID int64, req *SetValidateNumbersRequest) (*models.CampaignWithStats, error)
+	// SetUTM changes the UTM parameters appended to the campaign's links
+	SetUTM(ctx context.Context, campaignID int64, req *SetUTMRequest) (*models.CampaignWithStats, error)
+	Export(ctx context.Context, campaignID int64) (*CampaignDefinition, error)
+	Import(ctx context.Context, definition *CampaignDefinition) (*models.Campaign, error)
+	Provision(ctx context.Context, externalKey string, definition *CampaignDefinition) (*ProvisionResult, error)
+}
+
+// CampaignServiceConfig holds tunables for building and dispatching campaigns
+type CampaignServiceConfig struct {
+	// SendBatchSize is the number of messages inserted and published per chunk
+	SendBatchSize int
+	// RenderConcurrency bounds the goroutines rendering templates for a chunk
+	RenderConcurrency int
+	// ConfirmThreshold is the audience size above which a send must carry a
+	// matching confirm_recipient_count; 0 never asks
+	ConfirmThreshold int64
+	// ApprovalThreshold is the audience size above which a send is built but
+	// held until a second user approves its send job; 0 never holds one
+	ApprovalThreshold int64
+	// MaxRetryCount is the number of send attempts a message gets; failed
+	// messages with fewer can be retried per campaign
+	MaxRetryCount int
+	// SendJobs stores the sends held for approval; nil never holds a send
+	SendJobs repository.SendJobRepository
+	// Recommender fills {recommended_product}; nil uses each customer's
+	// preferred product
+	Recommender Recommender
+	// LiveStats serves campaign stats from memory; nil counts the messages
+	// on every read
+	LiveStats *LiveStats
+	// Events receives a CampaignCreated for every new campaign; nil publishes
+	// nothing
+	Events *events.Bus
+	// Templates resolves the template_id of create requests; nil refuses them
+	Templates repository.MessageTemplateRepository
+	// Catalog resolves the catalog_template of create requests; nil refuses
+	// them
+	Catalog repository.TemplateCatalogRepository
+	// Reports runs the aggregate queries of campaign reports; nil refuses them
+	Reports repository.CampaignReportRepository
+	// MessageCosts maps a channel to its price per SMS segment, or per message
+	// on other channels, for estimates; channels without a price are free
+	MessageCosts map[string]float64
 }
 
 type campaignService struct {
 	campaignRepo repository.CampaignRepository
 	customerRepo repository.CustomerRepository
 	messageRepo  repository.OutboundMessageRepository
+	segmentRepo  repository.SegmentRepository
 	templateSvc  TemplateService
 	queueClient  queue.Client
+	config       CampaignServiceConfig
 	logger       *slog.Logger
 }
 
@@ -33,29 +120,129 @@ func NewCampaignService(
 	campaignRepo repository.CampaignRepository,
 	customerRepo repository.CustomerRepository,
 	messageRepo repository.OutboundMessageRepository,
+	segmentRepo repository.SegmentRepository,
 	templateSvc TemplateService,
 	queueClient queue.Client,
+	config CampaignServiceConfig,
 	logger *slog.Logger,
 ) CampaignService {
+	if config.SendBatchSize < 1 {
+		config.SendBatchSize = defaultSendBatchSize
+	}
+	if config.RenderConcurrency < 1 {
+		config.RenderConcurrency = 1
+	}
+	if config.MaxRetryCount < 1 {
+		config.MaxRetryCount = defaultMaxRetryCount
+	}
+
 	return &campaignService{
 		campaignRepo: campaignRepo,
 		customerRepo: customerRepo,
 		messageRepo:  messageRepo,
+		segmentRepo:  segmentRepo,
 		templateSvc:  templateSvc,
 		queueClient:  queueClient,
+		config:       config,
 		logger:       logger,
 	}
 }
 
 // Create creates a new campaign
 func (s *campaignService) Create(ctx context.Context, req *CreateCampaignRequest) (*models.Campaign, error) {
+	campaign, err := s.newCampaign(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.campaignRepo.Create(ctx, campaign); err != nil {
+		s.logger.Error("failed to create campaign",
+			slog.String("error", err.Error()),
+			slog.String("name", req.Name),
+		)
+		return nil, fmt.Errorf("failed to create campaign: %w", err)
+	}
+
+	s.logger.Info("campaign created",
+		slog.Int64("campaign_id", campaign.ID),
+		slog.String("name", campaign.Name),
+		slog.String("status", campaign.Status),
+	)
+	s.publishCreated(ctx, campaign)
+
+	return campaign, nil
+}
+
+// publishCreated publishes a CampaignCreated for a campaign just stored
+func (s *campaignService) publishCreated(ctx context.Context, campaign *models.Campaign) {
+	s.config.Events.Publish(ctx, events.CampaignCreated{
+		CampaignID: campaign.ID,
+		AccountID:  campaign.AccountID,
+		Name:       campaign.Name,
+		Channel:    campaign.Channel,
+		Status:     campaign.Status,
+		At:         campaign.CreatedAt.UTC(),
+	})
+}
+
+// GetByExternalID retrieves a campaign with statistics by its external ID
+func (s *campaignService) GetByExternalID(ctx context.Context, externalID string) (*models.CampaignWithStats, error) {
+	campaign, err := s.campaignRepo.GetByExternalID(ctx, externalID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, campaign.ID)
+}
+
+// GetBySlug retrieves a campaign with statistics by its slug
+func (s *campaignService) GetBySlug(ctx context.Context, slug string) (*models.CampaignWithStats, error) {
+	campaign, err := s.campaignRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, campaign.ID)
+}
+
+// newCampaign validates a create request and builds the campaign it describes
+func (s *campaignService) newCampaign(ctx context.Context, req *CreateCampaignRequest) (*models.Campaign, error) {
+	if req.CatalogTemplate != "" {
+		if err := s.useCatalogTemplate(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+
 	// Validate request
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
 
-	// Validate template syntax
-	if err := s.templateSvc.ValidateTemplate(req.BaseTemplate); err != nil {
+	var templateVersion *int
+	if req.TemplateID != nil {
+		version, err := s.useTemplate(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		templateVersion = &version
+	}
+
+	if req.SubstituteUnicode && req.Channel == models.ChannelSMS {
+		req.BaseTemplate = models.SubstituteGSM7(req.BaseTemplate)
+	}
+
+	// Validate template syntax; the campaign keeps its partial references so
+	// that later edits to a partial reach it
+	expanded, err := s.templateSvc.ExpandPartials(ctx, req.BaseTemplate)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.templateSvc.ValidateTemplate(ctx, expanded); err != nil {
+		return nil, err
+	}
+
+	labels, err := models.NormalizeLabels(req.Labels)
+	if err != nil {
 		return nil, err
 	}
 
@@ -65,44 +252,132 @@ func (s *campaignService) Create(ctx context.Context, req *CreateCampaignRequest
 		status = models.CampaignStatusScheduled
 	}
 
-	// Create campaign
-	campaign := &models.Campaign{
-		Name:         req.Name,
-		Channel:      req.Channel,
-		Status:       status,
-		BaseTemplate: req.BaseTemplate,
-		ScheduledAt:  req.ScheduledAt,
+	environment := req.Environment
+	if environment == "" {
+		environment = models.CampaignEnvironmentLive
 	}
 
-	if err := s.campaignRepo.Create(ctx, campaign); err != nil {
-		s.logger.Error("failed to create campaign",
-			slog.String("error", err.Error()),
-			slog.String("name", req.Name),
-		)
-		return nil, fmt.Errorf("failed to create campaign: %w", err)
+	locale := req.Locale
+	if locale == "" {
+		locale = defaultLocale
 	}
 
-	s.logger.Info("campaign created",
-		slog.Int64("campaign_id", campaign.ID),
-		slog.String("name", campaign.Name),
-		slog.String("status", campaign.Status),
-	)
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = models.DefaultTimezone
+	}
 
-	return campaign, nil
+	return &models.Campaign{
+		Name:            req.Name,
+		Channel:         req.Channel,
+		Status:          status,
+		Environment:     environment,
+		Locale:          locale,
+		BaseTemplate:    req.BaseTemplate,
+		TemplateID:      req.TemplateID,
+		TemplateVersion: templateVersion,
+		ValidateNumbers: req.ValidateNumbers,
+		UTM:             req.UTM,
+		SenderID:        req.SenderID,
+		DeliveryWindows: req.DeliveryWindows,
+		Timezone:        timezone,
+		ScheduledAt:     req.ScheduledAt,
+		Labels:          labels,
+		ExternalID:      req.ExternalID,
+		Audience:        req.Audience,
+		MaxCost:         req.MaxCost,
+		MaxInFlight:     req.MaxInFlight,
+		PrebuildMinutes: req.PrebuildMinutes,
+	}, nil
+}
+
+// useTemplate sets the request's base template to the content of the message
+// template it names and returns the version used
+func (s *campaignService) useTemplate(ctx context.Context, req *CreateCampaignRequest) (int, error) {
+	if s.config.Templates == nil {
+		return 0, models.ErrInvalidInput("template_id is not supported here")
+	}
+
+	if req.TemplateVersion == nil {
+		template, err := s.config.Templates.GetByID(ctx, *req.TemplateID)
+		if err != nil {
+			return 0, templateLookupError(err)
+		}
+		req.BaseTemplate = template.Content
+		return template.Version, nil
+	}
+
+	version, err := s.config.Templates.GetVersion(ctx, *req.TemplateID, *req.TemplateVersion)
+	if err != nil {
+		return 0, templateLookupError(err)
+	}
+	req.BaseTemplate = version.Content
+	return version.Version, nil
+}
+
+// useCatalogTemplate fills the request from the catalog template it names:
+// its content always, and its name and channel when the request has none
+func (s *campaignService) useCatalogTemplate(ctx context.Context, req *CreateCampaignRequest) error {
+	if s.config.Catalog == nil {
+		return models.ErrInvalidInput("catalog_template is not supported here")
+	}
+	if req.BaseTemplate != "" || req.TemplateID != nil {
+		return models.ErrInvalidInput("catalog_template cannot be combined with base_template or template_id")
+	}
+
+	template, err := s.config.Catalog.GetBySlug(ctx, req.CatalogTemplate)
+	if err != nil {
+		return templateLookupError(err)
+	}
+	req.BaseTemplate = template.Content
+	if req.Name == "" {
+		req.Name = template.Name
+	}
+	if req.Channel == "" {
+		req.Channel = template.Channel
+	}
+	return nil
+}
+
+// templateLookupError reports a missing template as a problem with the
+// request rather than a missing campaign
+func templateLookupError(err error) error {
+	if errors.Is(err, models.ErrNotFound) {
+		return models.ErrInvalidInput(err.Error())
+	}
+	return err
 }
 
 // GetByID retrieves a campaign with statistics
 func (s *campaignService) GetByID(ctx context.Context, id int64) (*models.CampaignWithStats, error) {
-	campaign, err := s.campaignRepo.GetWithStats(ctx, id)
+	if s.config.LiveStats == nil {
+		return s.campaignRepo.GetWithStats(ctx, id)
+	}
+
+	campaign, err := s.campaignRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	return campaign, nil
+	stats, err := s.config.LiveStats.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	costAccrued, err := s.campaignRepo.GetCostAccrued(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewCampaignWithStats(campaign, stats, costAccrued), nil
 }
 
 // List retrieves campaigns with pagination
 func (s *campaignService) List(ctx context.Context, filter models.CampaignFilter) (*CampaignListResult, error) {
+	if filter.Environment != "" && filter.Environment != models.CampaignEnvironmentAll && !models.IsValidEnvironment(filter.Environment) {
+		return nil, models.ErrInvalidInput("invalid environment (must be 'live', 'test' or 'all')")
+	}
+
 	campaigns, totalCount, err := s.campaignRepo.List(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list campaigns: %w", err)
@@ -117,11 +392,13 @@ func (s *campaignService) List(ctx context.Context, filter models.CampaignFilter
 	listItems := make([]*CampaignListItem, len(campaigns))
 	for i, c := range campaigns {
 		listItems[i] = &CampaignListItem{
-			ID:        c.ID,
-			Name:      c.Name,
-			Channel:   c.Channel,
-			Status:    c.Status,
-			CreatedAt: c.CreatedAt,
+			ID:          c.ID,
+			Name:        c.Name,
+			Slug:        c.Slug,
+			Channel:     c.Channel,
+			Status:      c.Status,
+			Environment: c.Environment,
+			CreatedAt:   c.CreatedAt,
 		}
 	}
 
@@ -132,9 +409,18 @@ func (s *campaignService) List(ctx context.Context, filter models.CampaignFilter
 }
 
 // SendCampaign sends a campaign to specified customers
-func (s *campaignService) SendCampaign(ctx context.Context, campaignID int64, req *SendCampaignRequest) (*SendCampaignResult, error) {
-	// Validate request
-	if err := req.Validate(); err != nil {
+func (s *campaignService) SendCampaign(ctx context.Context, campaignID int64, req *SendCampaignRequest) (result *SendCampaignResult, err error) {
+	ctx, span := tracer.Start(ctx, "CampaignService.SendCampaign",
+		trace.WithAttributes(attribute.Int64("campaign_id", campaignID)),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	// Validate recipients named in the request before touching the database
+	if req.namesRecipients() {
+		if err := req.Validate(); err != nil {
+			return nil, err
+		}
+	} else if err := req.Exclude.Validate(); err != nil {
 		return nil, err
 	}
 
@@ -143,6 +429,86 @@ func (s *campaignService) SendCampaign(ctx context.Context, campaignID int64, re
 	if err != nil {
 		return nil, err
 	}
+	// The scheduler sends for no account in particular; build the audience
+	// from the campaign's own account only
+	ctx = models.WithAccountID(ctx, campaign.AccountID)
+
+	// A campaign built ahead of its schedule only has its messages published
+	if campaign.Status == models.CampaignStatusReady {
+		return s.releasePrebuilt(ctx, campaign, req)
+	}
+	if campaign.Status == models.CampaignStatusAwaitingApproval {
+		return nil, models.ErrConflictWithMsg("campaign's send is awaiting a second approval; another user must approve its send job")
+	}
+
+	queued, excluded, job, err := s.buildAudience(ctx, campaign, req, false)
+	if err != nil {
+		return nil, err
+	}
+
+	result = &SendCampaignResult{
+		CampaignID:     campaign.ID,
+		MessagesQueued: queued,
+		Status:         models.CampaignStatusSending,
+		Excluded:       excluded,
+	}
+	if job != nil {
+		result.Status = models.CampaignStatusAwaitingApproval
+		result.SendJob = job
+	}
+	return result, nil
+}
+
+// Prebuild resolves the bound audience of a scheduled campaign and renders its
+// messages ahead of scheduled_at without queueing them. The campaign is then
+// ready, and sending it at scheduled_at only publishes the built messages.
+func (s *campaignService) Prebuild(ctx context.Context, campaignID int64) (result *PrebuildResult, err error) {
+	ctx, span := tracer.Start(ctx, "CampaignService.Prebuild",
+		trace.WithAttributes(attribute.Int64("campaign_id", campaignID)),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	campaign, err := s.campaignRepo.GetByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	ctx = models.WithAccountID(ctx, campaign.AccountID)
+
+	if campaign.Status != models.CampaignStatusScheduled {
+		return nil, models.ErrConflictWithMsg(
+			fmt.Sprintf("only scheduled campaigns can be built ahead (current status: %s)", campaign.Status),
+		)
+	}
+
+	built, _, job, err := s.buildAudience(ctx, campaign, &SendCampaignRequest{}, true)
+	if err != nil {
+		return nil, err
+	}
+
+	result = &PrebuildResult{
+		CampaignID:    campaign.ID,
+		MessagesBuilt: built,
+		Status:        models.CampaignStatusReady,
+	}
+	if job != nil {
+		result.Status = models.CampaignStatusAwaitingApproval
+	}
+	return result, nil
+}
+
+// buildAudience renders and stores a message for every recipient of the send
+// and returns how many it queued, with the exclusion counts when the request
+// had exclusions. With hold set the messages are stored but not queued, the
+// campaign is left ready instead of sending and the number of messages built
+// is returned. A send above ApprovalThreshold is held in the same way, with
+// the campaign awaiting approval, and returns its send job.
+func (s *campaignService) buildAudience(ctx context.Context, campaign *models.Campaign, req *SendCampaignRequest, hold bool) (int, *ExclusionCounts, *models.SendJob, error) {
+	campaignID := campaign.ID
+
+	req, err := resolveAudience(campaign, req)
+	if err != nil {
+		return 0, nil, nil, err
+	}
 
 	// Check if campaign can be sent (idempotency check)
 	// Prevents duplicate sends if API is called multiple times
@@ -151,84 +517,178 @@ func (s *campaignService) SendCampaign(ctx context.Context, campaignID int64, re
 			slog.Int64("campaign_id", campaignID),
 			slog.String("current_status", campaign.Status),
 		)
-		return nil, models.ErrConflictWithMsg(
+		return 0, nil, nil, models.ErrConflictWithMsg(
 			fmt.Sprintf("campaign already processed (status: '%s'). To prevent duplicate sends, campaigns in 'sending', 'sent', or 'failed' status cannot be sent again", campaign.Status),
 		)
 	}
 
-	// Create outbound messages for each customer
-	messages := make([]*models.OutboundMessage, 0, len(req.CustomerIDs))
-	for _, customerID := range req.CustomerIDs {
-		// Get customer
-		customer, err := s.customerRepo.GetByID(ctx, customerID)
+	// Build the audience in batches of SendBatchSize: every batch is rendered,
+	// inserted and published before the next one is fetched, which bounds memory
+	// and transaction size for very large audiences
+	source, err := s.newAudienceSource(ctx, req)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	size, err := s.countAudience(ctx, source)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	if err := s.confirmAudienceSize(size, req.ConfirmRecipientCount); err != nil {
+		return 0, nil, nil, err
+	}
+	approval := s.needsApproval(size)
+
+	// Parse the template once for the whole audience, with partials as they are now
+	template, err := s.templateSvc.ExpandPartials(ctx, campaign.BaseTemplate)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	compiled := s.templateSvc.Compile(template).ForCampaign(campaign)
+
+	// Claim the campaign before creating any message: of concurrent sends,
+	// the scheduler's included, only the one that moves it on from the status
+	// read above builds the audience. A held build is left ready, to be
+	// released at scheduled_at, and one that needs approval waits for it.
+	previousStatus := campaign.Status
+	claimedStatus := models.CampaignStatusSending
+	switch {
+	case approval:
+		claimedStatus = models.CampaignStatusAwaitingApproval
+	case hold:
+		claimedStatus = models.CampaignStatusReady
+	}
+	claimed, err := s.campaignRepo.TransitionStatus(ctx, campaignID, previousStatus, claimedStatus)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	if !claimed {
+		s.logger.Warn("idempotency check failed: campaign claimed by another send",
+			slog.Int64("campaign_id", campaignID),
+			slog.String("previous_status", previousStatus),
+		)
+		return 0, nil, nil, models.ErrConflictWithMsg(
+			fmt.Sprintf("campaign is no longer '%s'; another send has already started it", previousStatus),
+		)
+	}
+
+	var job *models.SendJob
+	if approval {
+		job = &models.SendJob{CampaignID: campaignID, RecipientCount: size, RequestedBy: req.RequestedBy}
+		if err := s.config.SendJobs.Create(ctx, job); err != nil {
+			s.releaseClaim(ctx, campaignID, 0, claimedStatus, previousStatus)
+			return 0, nil, nil, err
+		}
+		hold = true
+	}
+
+	// Workers may finish the first batches before the last one is created;
+	// the building flag keeps them from completing the campaign meanwhile
+	if claimedStatus == models.CampaignStatusSending {
+		if err := s.campaignRepo.SetBuilding(ctx, campaignID, true); err != nil {
+			s.releaseClaim(ctx, campaignID, 0, claimedStatus, previousStatus)
+			return 0, nil, nil, err
+		}
+		defer s.finishBuild(ctx, campaignID)
+	}
+
+	createdCount := 0
+	queuedCount := 0
+	for batch := 1; ; batch++ {
+		// Stop building once the request is abandoned or out of time
+		if err := ctx.Err(); err != nil {
+			s.releaseClaim(ctx, campaignID, createdCount, claimedStatus, previousStatus)
+			return 0, nil, nil, fmt.Errorf("send stopped before audience batch %d: %w", batch, err)
+		}
+
+		// Or once the campaign is cancelled; its messages already created are
+		// skipped by the cancellation or by the worker
+		current, err := s.campaignRepo.GetByID(ctx, campaignID)
 		if err != nil {
-			s.logger.Warn("customer not found, skipping",
-				slog.Int64("customer_id", customerID),
-				slog.String("error", err.Error()),
+			s.releaseClaim(ctx, campaignID, createdCount, claimedStatus, previousStatus)
+			return 0, nil, nil, fmt.Errorf("failed to check campaign before audience batch %d: %w", batch, err)
+		}
+		if current.Status == models.CampaignStatusCancelled {
+			s.logger.Warn("campaign cancelled while its audience was built",
+				slog.Int64("campaign_id", campaignID),
+				slog.Int("messages_created", createdCount),
+			)
+			return 0, nil, nil, models.ErrConflictWithMsg(
+				fmt.Sprintf("campaign was cancelled after %d messages were created", createdCount),
 			)
-			continue
 		}
 
-		// Render message content
-		renderedContent, err := s.templateSvc.Render(campaign.BaseTemplate, customer)
+		customers, err := source.NextPage(ctx)
 		if err != nil {
-			s.logger.Error("failed to render template",
+			s.releaseClaim(ctx, campaignID, createdCount, claimedStatus, previousStatus)
+			return 0, nil, nil, fmt.Errorf("failed to fetch audience batch %d: %w", batch, err)
+		}
+		if len(customers) == 0 {
+			break
+		}
+
+		// Each batch gets a span, which the jobs it queues link to
+		batchCtx, batchSpan := tracer.Start(ctx, "send batch",
+			trace.WithAttributes(attribute.Int("batch", batch), attribute.Int("customers", len(customers))),
+		)
+
+		messages := s.buildMessages(batchCtx, campaign, compiled, customers)
+		if len(messages) == 0 {
+			batchSpan.End()
+			continue
+		}
+
+		// Batch create messages
+		if err := s.messageRepo.CreateBatch(batchCtx, messages); err != nil {
+			tracing.End(batchSpan, err)
+			s.logger.Error("failed to create messages",
 				slog.Int64("campaign_id", campaignID),
-				slog.Int64("customer_id", customerID),
+				slog.Int("batch", batch),
 				slog.String("error", err.Error()),
 			)
-			continue
+			s.releaseClaim(ctx, campaignID, createdCount, claimedStatus, previousStatus)
+			return 0, nil, nil, fmt.Errorf("failed to create messages: %w", err)
 		}
+		createdCount += len(messages)
 
-		// Create outbound message
-		message := &models.OutboundMessage{
-			CampaignID:      campaign.ID,
-			CustomerID:      customer.ID,
-			Status:          models.MessageStatusPending,
-			RenderedContent: renderedContent,
-			RetryCount:      0,
+		batchQueued := 0
+		if !hold {
+			batchQueued = s.publishMessages(batchCtx, messages)
 		}
+		queuedCount += batchQueued
+		batchSpan.SetAttributes(attribute.Int("messages_queued", batchQueued))
+		batchSpan.End()
 
-		messages = append(messages, message)
+		s.logger.Info("send batch processed",
+			slog.Int64("campaign_id", campaignID),
+			slog.Int("batch", batch),
+			slog.Int("batch_size", len(messages)),
+			slog.Int("messages_created", createdCount),
+			slog.Int("messages_queued", queuedCount),
+		)
 	}
 
-	if len(messages) == 0 {
-		return nil, models.ErrInvalidInput("no valid customers found to send messages")
+	if createdCount == 0 {
+		s.releaseClaim(ctx, campaignID, createdCount, claimedStatus, previousStatus)
+		return 0, nil, nil, models.ErrInvalidInput("no valid customers found to send messages")
 	}
 
-	// Batch create messages
-	if err := s.messageRepo.CreateBatch(ctx, messages); err != nil {
-		s.logger.Error("failed to create messages",
+	if job != nil {
+		s.logger.Info("campaign send awaiting a second approval",
 			slog.Int64("campaign_id", campaignID),
-			slog.String("error", err.Error()),
+			slog.Int64("send_job_id", job.ID),
+			slog.Int64("recipients", size),
+			slog.Int("messages_built", createdCount),
 		)
-		return nil, fmt.Errorf("failed to create messages: %w", err)
-	}
-
-	// Queue messages for sending
-	queuedCount := 0
-	for _, message := range messages {
-		job := &models.MessageJob{
-			OutboundMessageID: message.ID,
-		}
-
-		if err := s.queueClient.Publish(ctx, job); err != nil {
-			s.logger.Error("failed to queue message",
-				slog.Int64("message_id", message.ID),
-				slog.String("error", err.Error()),
-			)
-			continue
-		}
-		queuedCount++
+		return queuedCount, exclusionCounts(source), job, nil
 	}
 
-	// Update campaign status to sending
-	if err := s.campaignRepo.UpdateStatus(ctx, campaign.ID, models.CampaignStatusSending); err != nil {
-		s.logger.Error("failed to update campaign status",
+	if hold {
+		s.logger.Info("campaign built ahead of schedule",
 			slog.Int64("campaign_id", campaignID),
-			slog.String("error", err.Error()),
+			slog.Int("messages_built", createdCount),
 		)
-		// Don't fail the request if status update fails
+		return createdCount, exclusionCounts(source), nil, nil
 	}
 
 	s.logger.Info("campaign sent",
@@ -236,47 +696,494 @@ func (s *campaignService) SendCampaign(ctx context.Context, campaignID int64, re
 		slog.Int("messages_queued", queuedCount),
 	)
 
-	return &SendCampaignResult{
-		CampaignID:     campaign.ID,
-		MessagesQueued: queuedCount,
-		Status:         models.CampaignStatusSending,
-	}, nil
+	return queuedCount, exclusionCounts(source), nil, nil
 }
 
-// PreviewPersonalized generates a preview of a personalized message
-func (s *campaignService) PreviewPersonalized(ctx context.Context, campaignID int64, req *PreviewRequest) (*PreviewResult, error) {
-	// Validate request
-	if err := req.Validate(); err != nil {
-		return nil, err
+// exclusionCounts returns the counts of a source that excludes customers, and
+// nil for any other
+func exclusionCounts(source audienceSource) *ExclusionCounts {
+	if excluding, ok := source.(*excludingSource); ok {
+		return &excluding.counts
 	}
+	return nil
+}
 
-	// Get campaign
-	campaign, err := s.campaignRepo.GetByID(ctx, campaignID)
+// releasePrebuilt queues the messages of a campaign built ahead of its
+// schedule and moves it to sending. The audience was fixed when the messages
+// were built, so the request may not name recipients.
+func (s *campaignService) releasePrebuilt(ctx context.Context, campaign *models.Campaign, req *SendCampaignRequest) (*SendCampaignResult, error) {
+	if req.namesRecipients() || req.Exclude != nil {
+		return nil, models.ErrInvalidInput("the campaign's messages are already built for its bound audience; send it without recipients or exclusions")
+	}
+
+	// Claim the release first, so an API send racing the scheduler cannot
+	// publish the messages twice. Messages left unpublished by a release that
+	// stops are then published by the outbox relay.
+	claimed, err := s.campaignRepo.TransitionStatus(ctx, campaign.ID, models.CampaignStatusReady, models.CampaignStatusSending)
 	if err != nil {
 		return nil, err
 	}
+	if !claimed {
+		return nil, models.ErrConflictWithMsg("campaign is no longer 'ready'; another send has already released it")
+	}
 
-	// Get customer
-	customer, err := s.customerRepo.GetByID(ctx, req.CustomerID)
+	queued, err := s.publishBuilt(ctx, campaign.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Determine which template to use
-	templateToUse := campaign.BaseTemplate
-	if req.OverrideTemplate != nil && *req.OverrideTemplate != "" {
-		templateToUse = *req.OverrideTemplate
+	s.logger.Info("prebuilt campaign released",
+		slog.Int64("campaign_id", campaign.ID),
+		slog.Int("messages_queued", queued),
+	)
 
-		// Validate override template
-		if err := s.templateSvc.ValidateTemplate(templateToUse); err != nil {
-			return nil, err
+	return &SendCampaignResult{
+		CampaignID:     campaign.ID,
+		MessagesQueued: queued,
+		Status:         models.CampaignStatusSending,
+	}, nil
+}
+
+// publishBuilt queues the pending messages of a campaign whose messages were
+// built and held, and returns how many it queued. Messages it leaves
+// unpublished when it stops are published by the outbox relay.
+func (s *campaignService) publishBuilt(ctx context.Context, campaignID int64) (int, error) {
+	queued := 0
+	var lastID int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return 0, fmt.Errorf("release stopped after queueing %d messages: %w", queued, err)
+		}
+
+		page, err := s.messageRepo.ListByCampaignAfterID(ctx, campaignID, lastID, exportPageSize)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read campaign messages: %w", err)
+		}
+		if len(page) == 0 {
+			return queued, nil
+		}
+
+		pending := make([]*models.OutboundMessage, 0, len(page))
+		for _, message := range page {
+			if message.Status == models.MessageStatusPending {
+				pending = append(pending, message)
+			}
 		}
+		queued += s.publishMessages(ctx, pending)
+
+		lastID = page[len(page)-1].ID
 	}
+}
 
-	// Render message
-	renderedMessage, err := s.templateSvc.Render(templateToUse, customer)
-	if err != nil {
-		return nil, fmt.Errorf("failed to render message: %w", err)
+// ApproveSendJob approves a send held for a second approval and releases its
+// campaign: a campaign scheduled for later is left ready, to be released at
+// scheduled_at, and any other has its messages queued now. The approver must
+// be signed in and cannot be the user who requested the send.
+func (s *campaignService) ApproveSendJob(ctx context.Context, jobID int64, approverID *int64) (result *SendCampaignResult, err error) {
+	ctx, span := tracer.Start(ctx, "CampaignService.ApproveSendJob",
+		trace.WithAttributes(attribute.Int64("send_job_id", jobID)),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	if s.config.SendJobs == nil {
+		return nil, models.ErrNotFoundWithMsg(fmt.Sprintf("send job with ID %d not found", jobID))
+	}
+	if approverID == nil {
+		return nil, models.ErrForbidden("approving a send requires a signed-in user")
+	}
+
+	job, err := s.config.SendJobs.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != models.SendJobStatusAwaitingApproval {
+		return nil, models.ErrConflictWithMsg(fmt.Sprintf("send job %d is already %s", jobID, job.Status))
+	}
+	if job.RequestedByUser(*approverID) {
+		s.logger.Warn("send approval by its requester refused",
+			slog.Int64("send_job_id", jobID),
+			slog.Int64("user_id", *approverID),
+		)
+		return nil, models.ErrForbidden("a send must be approved by a different user than the one who requested it")
+	}
+
+	campaign, err := s.campaignRepo.GetByID(ctx, job.CampaignID)
+	if err != nil {
+		return nil, err
+	}
+	ctx = models.WithAccountID(ctx, campaign.AccountID)
+
+	if campaign.Status != models.CampaignStatusAwaitingApproval {
+		return nil, models.ErrConflictWithMsg(fmt.Sprintf("campaign %d is %s, not awaiting approval", campaign.ID, campaign.Status))
+	}
+
+	// The approval is recorded together with moving the campaign on, so of
+	// two approvers only one releases the messages and a cancelled campaign
+	// is not released
+	status := models.CampaignStatusSending
+	if campaign.ScheduledAt != nil && campaign.ScheduledAt.After(time.Now()) {
+		status = models.CampaignStatusReady
+	}
+	approved, err := s.config.SendJobs.Approve(ctx, jobID, *approverID, status)
+	if err != nil {
+		return nil, err
+	}
+	if !approved {
+		return nil, models.ErrConflictWithMsg(
+			fmt.Sprintf("send job %d is no longer awaiting approval; it was approved or its campaign cancelled meanwhile", jobID),
+		)
+	}
+	job.Status = models.SendJobStatusApproved
+	job.ApprovedBy = approverID
+
+	queued := 0
+	if status == models.CampaignStatusSending {
+		if queued, err = s.publishBuilt(ctx, campaign.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	s.logger.Info("campaign send approved",
+		slog.Int64("send_job_id", jobID),
+		slog.Int64("campaign_id", campaign.ID),
+		slog.Int64("approved_by", *approverID),
+		slog.String("status", status),
+		slog.Int("messages_queued", queued),
+	)
+
+	return &SendCampaignResult{
+		CampaignID:     campaign.ID,
+		MessagesQueued: queued,
+		Status:         status,
+		SendJob:        job,
+	}, nil
+}
+
+// countAudience returns the size of the send's audience before exclusions,
+// or 0 without counting when neither confirmation nor approval is on
+func (s *campaignService) countAudience(ctx context.Context, source audienceSource) (int64, error) {
+	if s.config.ConfirmThreshold <= 0 && !s.approvalEnabled() {
+		return 0, nil
+	}
+
+	size, err := source.Count(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count audience: %w", err)
+	}
+	return size, nil
+}
+
+// confirmAudienceSize guards against accidental sends to a huge audience, such
+// as every customer: above ConfirmThreshold recipients the caller must confirm
+// the exact audience size, which the error tells them
+func (s *campaignService) confirmAudienceSize(size int64, confirmed *int64) error {
+	if s.config.ConfirmThreshold <= 0 || size <= s.config.ConfirmThreshold {
+		return nil
+	}
+
+	if confirmed == nil {
+		return models.ErrConfirmationRequired(fmt.Sprintf(
+			"campaign would send to %d recipients, above the confirmation threshold of %d; repeat the request with \"confirm_recipient_count\": %d",
+			size, s.config.ConfirmThreshold, size,
+		))
+	}
+	if *confirmed != size {
+		return models.ErrConfirmationRequired(fmt.Sprintf(
+			"confirm_recipient_count is %d but the audience has %d recipients; check the audience and repeat the request with \"confirm_recipient_count\": %d",
+			*confirmed, size, size,
+		))
+	}
+
+	return nil
+}
+
+// approvalEnabled reports whether sends above ApprovalThreshold are held for a
+// second approval
+func (s *campaignService) approvalEnabled() bool {
+	return s.config.ApprovalThreshold > 0 && s.config.SendJobs != nil
+}
+
+// needsApproval reports whether a send to an audience of size must be
+// approved by a second user before its messages are queued
+func (s *campaignService) needsApproval(size int64) bool {
+	return s.approvalEnabled() && size > s.config.ApprovalThreshold
+}
+
+// newAudienceSource picks the recipient source for a send request, dropping
+// the customers it excludes. A segment is looked up here so that a missing
+// one fails the send before it starts.
+// Segments and filters are streamed from the database a batch at a time, so
+// audiences of any size are sent without loading them into memory.
+func (s *campaignService) newAudienceSource(ctx context.Context, req *SendCampaignRequest) (audienceSource, error) {
+	var source audienceSource
+	switch {
+	case req.SegmentID != nil:
+		segment, err := s.segmentRepo.GetByID(ctx, *req.SegmentID)
+		if err != nil {
+			return nil, err
+		}
+		source = newFilteredCustomersSource(s.customerRepo, segment.Filter, s.config.SendBatchSize)
+	case req.Filter != nil:
+		source = newFilteredCustomersSource(s.customerRepo, trimmedFilter(*req.Filter), s.config.SendBatchSize)
+	case req.Target == SendTargetAll:
+		source = newAllCustomersSource(s.customerRepo, s.config.SendBatchSize)
+	default:
+		source = newCustomerIDSource(s.customerRepo, req.CustomerIDs, s.config.SendBatchSize)
+	}
+
+	if req.Exclude == nil {
+		return source, nil
+	}
+	return s.newExcludingSource(ctx, source, req.Exclude)
+}
+
+// newExcludingSource wraps source to drop the customers exclude names. Its
+// segments are looked up here, like the audience's own.
+func (s *campaignService) newExcludingSource(ctx context.Context, source audienceSource, exclude *AudienceExclusion) (*excludingSource, error) {
+	excluding := &excludingSource{
+		audienceSource: source,
+		customerRepo:   s.customerRepo,
+		messageRepo:    s.messageRepo,
+		customerIDs:    make(map[int64]bool, len(exclude.CustomerIDs)),
+	}
+	for _, id := range exclude.CustomerIDs {
+		excluding.customerIDs[id] = true
+	}
+	for _, id := range exclude.SegmentIDs {
+		segment, err := s.segmentRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		excluding.segments = append(excluding.segments, segment.Filter)
+	}
+	if exclude.MessagedWithinDays > 0 {
+		if s.messageRepo == nil {
+			return nil, models.ErrInvalidInput("exclude messaged_within_days is not supported here")
+		}
+		excluding.messagedSince = time.Now().UTC().AddDate(0, 0, -exclude.MessagedWithinDays)
+	}
+
+	return excluding, nil
+}
+
+// buildMessages renders the campaign template for each customer in a batch.
+// Rendering is spread over a bounded pool of RenderConcurrency goroutines; the
+// returned messages keep the order of customers so inserts stay deterministic.
+// Customers whose message fails to render are logged and skipped.
+func (s *campaignService) buildMessages(ctx context.Context, campaign *models.Campaign, compiled *CompiledTemplate, customers []*models.Customer) []*models.OutboundMessage {
+	rendered := make([]*models.OutboundMessage, len(customers))
+	products := recommendations(ctx, s.config.Recommender, compiled, customers, s.logger)
+
+	workers := s.config.RenderConcurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(customers) {
+		workers = len(customers)
+	}
+
+	now := time.Now()
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				customer := customers[i]
+
+				// Opted-out, snoozed and bounced customers get no campaign messages
+				if customer.IsOptedOut() || customer.IsSnoozed(now) || customer.IsBounced() {
+					continue
+				}
+
+				// Render message content
+				renderedContent, err := compiled.RenderWith(customer, recommendedValues(products, customer))
+				if err != nil {
+					s.logger.Error("failed to render template",
+						slog.Int64("campaign_id", campaign.ID),
+						slog.Int64("customer_id", customer.ID),
+						slog.String("error", err.Error()),
+					)
+					continue
+				}
+
+				rendered[i] = &models.OutboundMessage{
+					CampaignID:      campaign.ID,
+					CustomerID:      customer.ID,
+					Status:          models.MessageStatusPending,
+					RenderedContent: renderedContent,
+					RetryCount:      0,
+				}
+			}
+		}()
+	}
+
+	for i := range customers {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	// Drop skipped customers while preserving order
+	messages := make([]*models.OutboundMessage, 0, len(rendered))
+	for _, message := range rendered {
+		if message != nil {
+			messages = append(messages, message)
+		}
+	}
+
+	return messages
+}
+
+// publishMessages queues a job per message, marks the queued messages in the
+// outbox and returns how many were queued. Messages left unmarked, because
+// publishing failed or the process stopped first, are published by the
+// worker's outbox relay.
+func (s *campaignService) publishMessages(ctx context.Context, messages []*models.OutboundMessage) int {
+	queued := make([]int64, 0, len(messages))
+	for _, message := range messages {
+		job := &models.MessageJob{
+			OutboundMessageID: message.ID,
+			CampaignID:        message.CampaignID,
+		}
+
+		if err := s.queueClient.Publish(ctx, job); err != nil {
+			s.logger.Error("failed to queue message",
+				slog.Int64("message_id", message.ID),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		queued = append(queued, message.ID)
+	}
+
+	// The jobs are out either way; unmarked messages are only published again
+	if err := s.messageRepo.MarkQueued(context.WithoutCancel(ctx), queued); err != nil {
+		s.logger.Warn("failed to mark messages queued; the outbox relay will queue them again",
+			slog.Int("messages", len(queued)),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	return len(queued)
+}
+
+// finishBuild clears the building flag once a send has stopped creating
+// messages, and completes the campaign when workers have already recorded an
+// outcome for every message
+func (s *campaignService) finishBuild(ctx context.Context, campaignID int64) {
+	// The request context may already be done; the flag must be cleared regardless
+	ctx = context.WithoutCancel(ctx)
+	if err := s.campaignRepo.SetBuilding(ctx, campaignID, false); err != nil {
+		s.logger.Error("failed to clear campaign building flag",
+			slog.Int64("campaign_id", campaignID),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	campaign, err := s.campaignRepo.GetWithStats(ctx, campaignID)
+	if err != nil {
+		s.logger.Error("failed to get campaign stats",
+			slog.Int64("campaign_id", campaignID),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	status, complete := campaign.Stats.FinalStatus()
+	if !complete {
+		return
+	}
+
+	completed, err := s.campaignRepo.CompleteSend(ctx, campaignID, status)
+	if err != nil {
+		s.logger.Error("failed to complete campaign",
+			slog.Int64("campaign_id", campaignID),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	if !completed {
+		return
+	}
+
+	s.config.Events.Publish(ctx, events.CampaignCompleted{
+		CampaignID: campaignID,
+		Status:     status,
+		Stats:      campaign.Stats,
+		At:         time.Now().UTC(),
+	})
+	s.logger.Info("campaign completed as its send finished building",
+		slog.Int64("campaign_id", campaignID),
+		slog.String("status", status),
+	)
+}
+
+// releaseClaim hands a campaign back to its status from before the send when
+// audience building stops before any message was created, so that the send
+// can be retried. Once messages exist the campaign keeps the claimed status,
+// "sending" or "ready", so that a retried send cannot duplicate them. A
+// campaign whose status changed since the claim, e.g. by a cancellation, is
+// left as it is.
+func (s *campaignService) releaseClaim(ctx context.Context, campaignID int64, createdCount int, claimedStatus, previousStatus string) {
+	if createdCount > 0 {
+		return
+	}
+
+	// The request context may already be done; the status must be restored regardless
+	if _, err := s.campaignRepo.TransitionStatus(context.WithoutCancel(ctx), campaignID, claimedStatus, previousStatus); err != nil {
+		s.logger.Error("failed to restore campaign status after failed send",
+			slog.Int64("campaign_id", campaignID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// PreviewPersonalized generates a preview of a personalized message
+func (s *campaignService) PreviewPersonalized(ctx context.Context, campaignID int64, req *PreviewRequest) (*PreviewResult, error) {
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Get campaign
+	campaign, err := s.campaignRepo.GetByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get customer
+	customer, err := s.customerRepo.GetByID(ctx, req.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Determine which template to use
+	templateToUse := campaign.BaseTemplate
+	override := req.OverrideTemplate != nil && *req.OverrideTemplate != ""
+	if override {
+		templateToUse = *req.OverrideTemplate
+	}
+
+	expanded, err := s.templateSvc.ExpandPartials(ctx, templateToUse)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate override template
+	if override {
+		if err := s.templateSvc.ValidateTemplate(ctx, expanded); err != nil {
+			return nil, err
+		}
+	}
+
+	// Render message
+	compiled := s.templateSvc.Compile(expanded).ForCampaign(campaign)
+	products := recommendations(ctx, s.config.Recommender, compiled, []*models.Customer{customer}, s.logger)
+	renderedMessage, err := compiled.RenderWith(customer, recommendedValues(products, customer))
+	if err != nil {
+		return nil, fmt.Errorf("failed to render message: %w", err)
 	}
 
 	return &PreviewResult{
@@ -288,3 +1195,387 @@ func (s *campaignService) PreviewPersonalized(ctx context.Context, campaignID in
 		},
 	}, nil
 }
+
+// Delete removes a campaign. Campaigns with message history are only removed when
+// force is set, in which case their outbound messages are deleted as well.
+func (s *campaignService) Delete(ctx context.Context, id int64, force bool) error {
+	campaign, err := s.campaignRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// Never pull a campaign out from under the worker
+	if campaign.Status == models.CampaignStatusSending {
+		return models.ErrConflictWithMsg(
+			fmt.Sprintf("campaign %d is currently sending and cannot be deleted", id),
+		)
+	}
+
+	messageCount, err := s.messageRepo.CountByCampaign(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to check campaign message history: %w", err)
+	}
+
+	if messageCount > 0 && !force {
+		return models.ErrConflictWithMsg(
+			fmt.Sprintf("campaign %d has %d messages in its history; pass force=true to delete it together with its messages", id, messageCount),
+		)
+	}
+
+	if messageCount > 0 {
+		err = s.campaignRepo.DeleteWithMessages(ctx, id)
+	} else {
+		err = s.campaignRepo.Delete(ctx, id)
+	}
+	if err != nil {
+		s.logger.Error("failed to delete campaign",
+			slog.Int64("campaign_id", id),
+			slog.String("error", err.Error()),
+		)
+		return fmt.Errorf("failed to delete campaign: %w", err)
+	}
+
+	s.logger.Info("campaign deleted",
+		slog.Int64("campaign_id", id),
+		slog.Int64("messages_deleted", messageCount),
+	)
+
+	return nil
+}
+
+// exportPageSize is the number of messages read per page when streaming exports
+const exportPageSize = 1000
+
+// StreamMessages walks the messages of a campaign with IDs above afterID in ID
+// order and hands them to fn one page at a time. The campaign is looked up first
+// so that a missing campaign is reported before fn is ever called.
+func (s *campaignService) StreamMessages(ctx context.Context, campaignID, afterID int64, fn func(page []*models.OutboundMessage) error) error {
+	if _, err := s.campaignRepo.GetByID(ctx, campaignID); err != nil {
+		return err
+	}
+
+	lastID := afterID
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		page, err := s.messageRepo.ListByCampaignAfterID(ctx, campaignID, lastID, exportPageSize)
+		if err != nil {
+			return fmt.Errorf("failed to read campaign messages: %w", err)
+		}
+		if len(page) == 0 {
+			return nil
+		}
+
+		if err := fn(page); err != nil {
+			return err
+		}
+
+		lastID = page[len(page)-1].ID
+	}
+}
+
+// StreamFailures walks the failed and undelivered messages of a campaign in
+// ID order, calling fn with one page of recipients at a time
+func (s *campaignService) StreamFailures(ctx context.Context, campaignID int64, fn func(page []*models.FailedRecipient) error) error {
+	if _, err := s.campaignRepo.GetByID(ctx, campaignID); err != nil {
+		return err
+	}
+
+	var lastID int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		page, err := s.messageRepo.ListFailedRecipients(ctx, campaignID, lastID, exportPageSize)
+		if err != nil {
+			return fmt.Errorf("failed to read failed recipients: %w", err)
+		}
+		if len(page) == 0 {
+			return nil
+		}
+
+		if err := fn(page); err != nil {
+			return err
+		}
+
+		lastID = page[len(page)-1].MessageID
+	}
+}
+
+// SetMaxCost changes a campaign's cost cap. Raising the cap does not restart a
+// campaign paused at the old one; resume it afterwards.
+func (s *campaignService) SetMaxCost(ctx context.Context, campaignID int64, req *SetMaxCostRequest) (*models.CampaignWithStats, error) {
+	if err := validateMaxCost(req.MaxCost); err != nil {
+		return nil, err
+	}
+
+	if err := s.campaignRepo.SetMaxCost(ctx, campaignID, req.MaxCost); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("campaign cost cap changed",
+		slog.Int64("campaign_id", campaignID),
+		slog.Any("max_cost", req.MaxCost),
+	)
+
+	return s.GetByID(ctx, campaignID)
+}
+
+// SetMaxInFlight changes how many of a campaign's messages workers send at
+// once. Workers read the limit before every send, so it applies straight away.
+func (s *campaignService) SetMaxInFlight(ctx context.Context, campaignID int64, req *SetMaxInFlightRequest) (*models.CampaignWithStats, error) {
+	if err := validateMaxInFlight(req.MaxInFlight); err != nil {
+		return nil, err
+	}
+
+	if err := s.campaignRepo.SetMaxInFlight(ctx, campaignID, req.MaxInFlight); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("campaign concurrency limit changed",
+		slog.Int64("campaign_id", campaignID),
+		slog.Any("max_in_flight", req.MaxInFlight),
+	)
+
+	return s.GetByID(ctx, campaignID)
+}
+
+// SetValidateNumbers turns number lookups on or off for a campaign. Workers
+// read the flag before every send, so it applies to messages not yet sent.
+func (s *campaignService) SetValidateNumbers(ctx context.Context, campaignID int64, req *SetValidateNumbersRequest) (*models.CampaignWithStats, error) {
+	if req.ValidateNumbers == nil {
+		return nil, models.ErrInvalidInput("validate_numbers is required")
+	}
+
+	if err := s.campaignRepo.SetValidateNumbers(ctx, campaignID, *req.ValidateNumbers); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("campaign number validation changed",
+		slog.Int64("campaign_id", campaignID),
+		slog.Bool("validate_numbers", *req.ValidateNumbers),
+	)
+
+	return s.GetByID(ctx, campaignID)
+}
+
+// SetUTM replaces the UTM parameters of a campaign. Links are tagged as
+// messages are rendered, so messages already built keep the parameters they
+// were built with.
+func (s *campaignService) SetUTM(ctx context.Context, campaignID int64, req *SetUTMRequest) (*models.CampaignWithStats, error) {
+	if req.UTM != nil {
+		if err := req.UTM.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.campaignRepo.SetUTM(ctx, campaignID, req.UTM); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("campaign utm parameters changed",
+		slog.Int64("campaign_id", campaignID),
+		slog.Bool("tagged", req.UTM != nil),
+	)
+
+	return s.GetByID(ctx, campaignID)
+}
+
+// maxPauseReasonLength bounds the reason given when pausing a campaign
+const maxPauseReasonLength = 500
+
+// defaultPauseReason is recorded when a campaign is paused without a reason
+const defaultPauseReason = "paused manually"
+
+// Pause stops a sending campaign. Its waiting jobs are removed from the queue
+// and workers drop any job of a paused campaign they still pick up, so its
+// pending messages stay pending until Resume requeues them.
+func (s *campaignService) Pause(ctx context.Context, campaignID int64, req *PauseCampaignRequest) (result *PauseCampaignResult, err error) {
+	ctx, span := tracer.Start(ctx, "CampaignService.Pause",
+		trace.WithAttributes(attribute.Int64("campaign_id", campaignID)),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	reason := strings.TrimSpace(req.Reason)
+	if len(reason) > maxPauseReasonLength {
+		return nil, models.ErrInvalidInput(fmt.Sprintf("reason must be at most %d characters", maxPauseReasonLength))
+	}
+	if reason == "" {
+		reason = defaultPauseReason
+	}
+
+	campaign, err := s.campaignRepo.GetByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	ctx = models.WithAccountID(ctx, campaign.AccountID)
+
+	// The status may change between the read and the update; the update only
+	// pauses a campaign that is still sending. A campaign waiting for its next
+	// delivery window is held so the scheduler no longer resumes it.
+	paused := false
+	if campaign.Status == models.CampaignStatusSending || campaign.AwaitsWindow() {
+		if paused, err = s.campaignRepo.PauseSending(ctx, campaignID, reason); err != nil {
+			return nil, err
+		}
+	}
+	if !paused {
+		return nil, models.ErrConflictWithMsg(
+			fmt.Sprintf("campaign is not sending (current status: %s)", campaign.Status),
+		)
+	}
+
+	// Removing the jobs only saves workers from picking them up to drop them.
+	// Their messages are first marked unqueued, as a dropped job's message is,
+	// for Resume to publish them again. Should that fail, the jobs are left for
+	// workers to drop.
+	var removed int64
+	if _, err := s.messageRepo.UnqueueByCampaign(ctx, campaignID); err != nil {
+		s.logger.Warn("failed to unqueue messages of paused campaign",
+			slog.Int64("campaign_id", campaignID),
+			slog.String("error", err.Error()),
+		)
+	} else if removed, err = s.queueClient.RemoveCampaignJobs(ctx, campaignID); err != nil {
+		s.logger.Warn("failed to remove jobs of paused campaign",
+			slog.Int64("campaign_id", campaignID),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	s.logger.Info("campaign paused",
+		slog.Int64("campaign_id", campaignID),
+		slog.String("reason", reason),
+		slog.Int64("jobs_removed", removed),
+	)
+
+	return &PauseCampaignResult{
+		CampaignID:  campaignID,
+		JobsRemoved: removed,
+		Status:      models.CampaignStatusPaused,
+		Reason:      reason,
+	}, nil
+}
+
+// Resume moves a paused campaign back to sending and requeues the pending
+// messages whose jobs were removed or dropped while it was paused. Messages
+// whose jobs are still queued or delayed, such as a retry after a failed
+// attempt, are left to those jobs.
+func (s *campaignService) Resume(ctx context.Context, campaignID int64) (result *ResumeCampaignResult, err error) {
+	ctx, span := tracer.Start(ctx, "CampaignService.Resume",
+		trace.WithAttributes(attribute.Int64("campaign_id", campaignID)),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	campaign, err := s.campaignRepo.GetByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	ctx = models.WithAccountID(ctx, campaign.AccountID)
+
+	if campaign.Status != models.CampaignStatusPaused {
+		return nil, models.ErrConflictWithMsg(
+			fmt.Sprintf("campaign is not paused (current status: %s)", campaign.Status),
+		)
+	}
+
+	if err := s.campaignRepo.Resume(ctx, campaignID); err != nil {
+		return nil, err
+	}
+
+	requeued := 0
+	var lastID int64
+	for {
+		if err := ctx.Err(); err != nil {
+			s.logger.Warn("resume stopped before all pending messages were requeued",
+				slog.Int64("campaign_id", campaignID),
+				slog.Int("messages_requeued", requeued),
+			)
+			return nil, fmt.Errorf("resume stopped after requeueing %d messages: %w", requeued, err)
+		}
+
+		page, err := s.messageRepo.ListUnqueuedByCampaign(ctx, campaignID, lastID, exportPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read campaign messages: %w", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+
+		requeued += s.publishMessages(ctx, page)
+
+		lastID = page[len(page)-1].ID
+	}
+
+	s.logger.Info("campaign resumed",
+		slog.Int64("campaign_id", campaignID),
+		slog.Int("messages_requeued", requeued),
+	)
+
+	return &ResumeCampaignResult{
+		CampaignID:       campaignID,
+		MessagesRequeued: requeued,
+		Status:           models.CampaignStatusSending,
+	}, nil
+}
+
+// Cancel stops a scheduled, sending or paused campaign for good. Its pending
+// messages are marked skipped and its waiting jobs are removed from the queue;
+// messages a worker is already sending are allowed to finish.
+func (s *campaignService) Cancel(ctx context.Context, campaignID int64) (result *CancelCampaignResult, err error) {
+	ctx, span := tracer.Start(ctx, "CampaignService.Cancel",
+		trace.WithAttributes(attribute.Int64("campaign_id", campaignID)),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	campaign, err := s.campaignRepo.GetByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	ctx = models.WithAccountID(ctx, campaign.AccountID)
+
+	// The status may change between the read and the update; the update only
+	// cancels a campaign that can still be cancelled
+	cancelled := false
+	if campaign.CanBeCancelled() {
+		if cancelled, err = s.campaignRepo.Cancel(ctx, campaignID); err != nil {
+			return nil, err
+		}
+	}
+	if !cancelled {
+		return nil, models.ErrConflictWithMsg(
+			fmt.Sprintf("campaign cannot be cancelled (current status: %s)", campaign.Status),
+		)
+	}
+
+	// Workers skip the jobs of cancelled campaigns, so from here on nothing
+	// more is sent even if the steps below fail
+	skipped, err := s.messageRepo.SkipPendingByCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("campaign cancelled, but failed to skip its pending messages: %w", err)
+	}
+
+	removed, err := s.queueClient.RemoveCampaignJobs(ctx, campaignID)
+	if err != nil {
+		s.logger.Warn("failed to remove jobs of cancelled campaign",
+			slog.Int64("campaign_id", campaignID),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	s.logger.Info("campaign cancelled",
+		slog.Int64("campaign_id", campaignID),
+		slog.String("previous_status", campaign.Status),
+		slog.Int64("messages_skipped", skipped),
+		slog.Int64("jobs_removed", removed),
+	)
+
+	return &CancelCampaignResult{
+		CampaignID:      campaignID,
+		MessagesSkipped: skipped,
+		JobsRemoved:     removed,
+		Status:          models.CampaignStatusCancelled,
+	}, nil
+}
@@ -2,24 +2,58 @@ package service
 
 import (
 	"context"
+	"errors"
+	"log/slog"
+	"os"
 	"testing"
 
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
 	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
 )
 
-// MockCampaignRepository for testing
-type mockCampaignRepository struct {
-	campaigns []*models.Campaign
+// campaignStore holds the campaigns behind a generated repository mock and
+// those whose send is still creating their messages
+type campaignStore struct {
+	all      []*models.Campaign
+	building map[int64]bool
+}
+
+// repo returns a mock repository reading and updating the store
+func (s *campaignStore) repo(t *testing.T) *mocks.MockCampaignRepository {
+	repo := mocks.NewMockCampaignRepository(gomock.NewController(t))
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(s.create).AnyTimes()
+	repo.EXPECT().GetByID(gomock.Any(), gomock.Any()).DoAndReturn(s.getByID).AnyTimes()
+	repo.EXPECT().GetWithStats(gomock.Any(), gomock.Any()).DoAndReturn(s.getWithStats).AnyTimes()
+	repo.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(s.list).AnyTimes()
+	repo.EXPECT().GetBySlug(gomock.Any(), gomock.Any()).DoAndReturn(s.getBySlug).AnyTimes()
+	repo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(s.update).AnyTimes()
+	repo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(s.updateStatus).AnyTimes()
+	repo.EXPECT().TransitionStatus(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(s.transitionStatus).AnyTimes()
+	repo.EXPECT().SetBuilding(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(s.setBuilding).AnyTimes()
+	repo.EXPECT().CompleteSend(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(s.completeSend).AnyTimes()
+	repo.EXPECT().SetMaxCost(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(s.setMaxCost).AnyTimes()
+	repo.EXPECT().SetMaxInFlight(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(s.setMaxInFlight).AnyTimes()
+	repo.EXPECT().SetValidateNumbers(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(s.setValidateNumbers).AnyTimes()
+	repo.EXPECT().SetUTM(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(s.setUTM).AnyTimes()
+	repo.EXPECT().SetShortDomain(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(s.setShortDomain).AnyTimes()
+	repo.EXPECT().PauseSendingByChannel(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(s.pauseSendingByChannel).AnyTimes()
+	repo.EXPECT().Resume(gomock.Any(), gomock.Any()).DoAndReturn(s.resume).AnyTimes()
+	repo.EXPECT().Cancel(gomock.Any(), gomock.Any()).DoAndReturn(s.cancel).AnyTimes()
+	repo.EXPECT().Delete(gomock.Any(), gomock.Any()).DoAndReturn(s.delete).AnyTimes()
+	repo.EXPECT().DeleteWithMessages(gomock.Any(), gomock.Any()).DoAndReturn(s.deleteWithMessages).AnyTimes()
+	return repo
 }
 
-func (m *mockCampaignRepository) Create(ctx context.Context, campaign *models.Campaign) error {
-	campaign.ID = int64(len(m.campaigns) + 1)
-	m.campaigns = append(m.campaigns, campaign)
+func (s *campaignStore) create(ctx context.Context, campaign *models.Campaign) error {
+	campaign.ID = int64(len(s.all) + 1)
+	s.all = append(s.all, campaign)
 	return nil
 }
 
-func (m *mockCampaignRepository) GetByID(ctx context.Context, id int64) (*models.Campaign, error) {
-	for _, c := range m.campaigns {
+func (s *campaignStore) getByID(ctx context.Context, id int64) (*models.Campaign, error) {
+	for _, c := range s.all {
 		if c.ID == id {
 			return c, nil
 		}
@@ -27,18 +61,21 @@ func (m *mockCampaignRepository) GetByID(ctx context.Context, id int64) (*models
 	return nil, models.ErrNotFoundWithMsg("campaign not found")
 }
 
-func (m *mockCampaignRepository) GetWithStats(ctx context.Context, id int64) (*models.CampaignWithStats, error) {
-	campaign, err := m.GetByID(ctx, id)
+func (s *campaignStore) getWithStats(ctx context.Context, id int64) (*models.CampaignWithStats, error) {
+	campaign, err := s.getByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	return &models.CampaignWithStats{
 		ID:           campaign.ID,
 		Name:         campaign.Name,
+		Slug:         campaign.Slug,
 		Channel:      campaign.Channel,
 		Status:       campaign.Status,
 		BaseTemplate: campaign.BaseTemplate,
 		ScheduledAt:  campaign.ScheduledAt,
+		MaxCost:      campaign.MaxCost,
+		MaxInFlight:  campaign.MaxInFlight,
 		CreatedAt:    campaign.CreatedAt,
 		Stats: models.CampaignStats{
 			Total:   0,
@@ -50,10 +87,10 @@ func (m *mockCampaignRepository) GetWithStats(ctx context.Context, id int64) (*m
 	}, nil
 }
 
-func (m *mockCampaignRepository) List(ctx context.Context, filter models.CampaignFilter) ([]*models.Campaign, int64, error) {
+func (s *campaignStore) list(ctx context.Context, filter models.CampaignFilter) ([]*models.Campaign, int64, error) {
 	// Apply filters
 	filtered := []*models.Campaign{}
-	for _, c := range m.campaigns {
+	for _, c := range s.all {
 		if filter.Channel != "" && c.Channel != filter.Channel {
 			continue
 		}
@@ -82,18 +119,27 @@ func (m *mockCampaignRepository) List(ctx context.Context, filter models.Campaig
 	return filtered[start:end], totalCount, nil
 }
 
-func (m *mockCampaignRepository) Update(ctx context.Context, campaign *models.Campaign) error {
-	for i, c := range m.campaigns {
+func (s *campaignStore) getBySlug(ctx context.Context, slug string) (*models.Campaign, error) {
+	for _, c := range s.all {
+		if c.Slug == slug {
+			return c, nil
+		}
+	}
+	return nil, models.ErrNotFoundWithMsg("campaign not found")
+}
+
+func (s *campaignStore) update(ctx context.Context, campaign *models.Campaign) error {
+	for i, c := range s.all {
 		if c.ID == campaign.ID {
-			m.campaigns[i] = campaign
+			s.all[i] = campaign
 			return nil
 		}
 	}
 	return models.ErrNotFoundWithMsg("campaign not found")
 }
 
-func (m *mockCampaignRepository) UpdateStatus(ctx context.Context, id int64, status string) error {
-	for _, c := range m.campaigns {
+func (s *campaignStore) updateStatus(ctx context.Context, id int64, status string) error {
+	for _, c := range s.all {
 		if c.ID == id {
 			c.Status = status
 			return nil
@@ -102,16 +148,135 @@ func (m *mockCampaignRepository) UpdateStatus(ctx context.Context, id int64, sta
 	return models.ErrNotFoundWithMsg("campaign not found")
 }
 
-func (m *mockCampaignRepository) Delete(ctx context.Context, id int64) error {
-	for i, c := range m.campaigns {
+func (s *campaignStore) transitionStatus(ctx context.Context, id int64, from, to string) (bool, error) {
+	for _, c := range s.all {
+		if c.ID == id && c.Status == from {
+			c.Status = to
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *campaignStore) setBuilding(ctx context.Context, id int64, building bool) error {
+	if s.building == nil {
+		s.building = make(map[int64]bool)
+	}
+	s.building[id] = building
+	return nil
+}
+
+func (s *campaignStore) completeSend(ctx context.Context, id int64, status string) (bool, error) {
+	for _, c := range s.all {
+		if c.ID == id && !s.building[id] &&
+			(c.Status == models.CampaignStatusSending || c.Status == models.CampaignStatusPaused) {
+			c.Status = status
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *campaignStore) setMaxCost(ctx context.Context, id int64, maxCost *float64) error {
+	for _, c := range s.all {
+		if c.ID == id {
+			c.MaxCost = maxCost
+			return nil
+		}
+	}
+	return models.ErrNotFoundWithMsg("campaign not found")
+}
+
+func (s *campaignStore) setMaxInFlight(ctx context.Context, id int64, maxInFlight *int) error {
+	for _, c := range s.all {
 		if c.ID == id {
-			m.campaigns = append(m.campaigns[:i], m.campaigns[i+1:]...)
+			c.MaxInFlight = maxInFlight
 			return nil
 		}
 	}
 	return models.ErrNotFoundWithMsg("campaign not found")
 }
 
+func (s *campaignStore) setValidateNumbers(ctx context.Context, id int64, validate bool) error {
+	for _, c := range s.all {
+		if c.ID == id {
+			c.ValidateNumbers = validate
+			return nil
+		}
+	}
+	return models.ErrNotFoundWithMsg("campaign not found")
+}
+
+func (s *campaignStore) setUTM(ctx context.Context, id int64, utm *models.CampaignUTM) error {
+	for _, c := range s.all {
+		if c.ID == id {
+			c.UTM = utm
+			return nil
+		}
+	}
+	return models.ErrNotFoundWithMsg("campaign not found")
+}
+
+func (s *campaignStore) setShortDomain(ctx context.Context, id int64, shortDomainID *int64) error {
+	for _, c := range s.all {
+		if c.ID == id {
+			c.ShortDomainID = shortDomainID
+			return nil
+		}
+	}
+	return models.ErrNotFoundWithMsg("campaign not found")
+}
+
+func (s *campaignStore) pauseSendingByChannel(ctx context.Context, channel, reason string) ([]int64, error) {
+	ids := []int64{}
+	for _, c := range s.all {
+		if c.Channel == channel && c.Status == models.CampaignStatusSending {
+			c.Status = models.CampaignStatusPaused
+			c.PausedReason = &reason
+			ids = append(ids, c.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (s *campaignStore) resume(ctx context.Context, id int64) error {
+	for _, c := range s.all {
+		if c.ID == id {
+			if c.Status != models.CampaignStatusPaused {
+				return models.ErrConflictWithMsg("campaign is not paused")
+			}
+			c.Status = models.CampaignStatusSending
+			c.PausedReason = nil
+			return nil
+		}
+	}
+	return models.ErrNotFoundWithMsg("campaign not found")
+}
+
+func (s *campaignStore) cancel(ctx context.Context, id int64) (bool, error) {
+	for _, c := range s.all {
+		if c.ID == id && c.CanBeCancelled() {
+			c.Status = models.CampaignStatusCancelled
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *campaignStore) delete(ctx context.Context, id int64) error {
+	for i, c := range s.all {
+		if c.ID == id {
+			s.all = append(s.all[:i], s.all[i+1:]...)
+			return nil
+		}
+	}
+	return models.ErrNotFoundWithMsg("campaign not found")
+}
+
+func (s *campaignStore) deleteWithMessages(ctx context.Context, id int64) error {
+	return s.delete(ctx, id)
+}
+
 func TestCampaignService_List_Pagination(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -217,12 +382,12 @@ func TestCampaignService_List_Pagination(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Setup mock repository with campaigns
-			mockRepo := &mockCampaignRepository{
-				campaigns: make([]*models.Campaign, tt.totalCampaigns),
+			campaigns := &campaignStore{
+				all: make([]*models.Campaign, tt.totalCampaigns),
 			}
 
 			for i := 0; i < tt.totalCampaigns; i++ {
-				mockRepo.campaigns[i] = &models.Campaign{
+				campaigns.all[i] = &models.Campaign{
 					ID:           int64(i + 1),
 					Name:         "Campaign " + string(rune(i+1)),
 					Channel:      "sms",
@@ -233,7 +398,7 @@ func TestCampaignService_List_Pagination(t *testing.T) {
 
 			// Create service with mock
 			svc := &campaignService{
-				campaignRepo: mockRepo,
+				campaignRepo: campaigns.repo(t),
 			}
 
 			// Test pagination
@@ -352,12 +517,12 @@ func TestCampaignService_List_Filtering(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockRepo := &mockCampaignRepository{
-				campaigns: tt.campaigns,
+			campaigns := &campaignStore{
+				all: tt.campaigns,
 			}
 
 			svc := &campaignService{
-				campaignRepo: mockRepo,
+				campaignRepo: campaigns.repo(t),
 			}
 
 			result, err := svc.List(context.Background(), tt.filter)
@@ -372,10 +537,28 @@ func TestCampaignService_List_Filtering(t *testing.T) {
 	}
 }
 
+func TestCampaignService_List_InvalidEnvironment(t *testing.T) {
+	svc := &campaignService{
+		campaignRepo: (&campaignStore{}).repo(t),
+	}
+
+	_, err := svc.List(context.Background(), models.CampaignFilter{Environment: "staging"})
+	var appErr *models.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+		t.Fatalf("List() error = %v, want INVALID_INPUT", err)
+	}
+
+	for _, environment := range []string{"", models.CampaignEnvironmentTest, models.CampaignEnvironmentAll} {
+		if _, err := svc.List(context.Background(), models.CampaignFilter{Environment: environment}); err != nil {
+			t.Errorf("List(environment=%q) error = %v", environment, err)
+		}
+	}
+}
+
 func TestCampaignService_List_Stability(t *testing.T) {
 	// Test that pagination is stable (ORDER BY id DESC)
-	mockRepo := &mockCampaignRepository{
-		campaigns: []*models.Campaign{
+	campaigns := &campaignStore{
+		all: []*models.Campaign{
 			{ID: 1, Channel: "sms", Status: "draft", Name: "Campaign 1"},
 			{ID: 2, Channel: "sms", Status: "draft", Name: "Campaign 2"},
 			{ID: 3, Channel: "sms", Status: "draft", Name: "Campaign 3"},
@@ -385,7 +568,7 @@ func TestCampaignService_List_Stability(t *testing.T) {
 	}
 
 	svc := &campaignService{
-		campaignRepo: mockRepo,
+		campaignRepo: campaigns.repo(t),
 	}
 
 	// Fetch page 1
@@ -418,3 +601,105 @@ func TestCampaignService_List_Stability(t *testing.T) {
 		}
 	}
 }
+
+func TestCampaignService_GetBySlug(t *testing.T) {
+	campaigns := &campaignStore{
+		all: []*models.Campaign{
+			{ID: 1, Name: "Flash sale", Slug: "flash-sale", Channel: models.ChannelSMS, Status: models.CampaignStatusDraft},
+			{ID: 2, Name: "Flash sale", Slug: "flash-sale-2", Channel: models.ChannelSMS, Status: models.CampaignStatusDraft},
+		},
+	}
+	svc := &campaignService{
+		campaignRepo: campaigns.repo(t),
+		logger:       slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	}
+
+	campaign, err := svc.GetBySlug(context.Background(), "flash-sale-2")
+	if err != nil {
+		t.Fatalf("GetBySlug() error = %v", err)
+	}
+	if campaign.ID != 2 || campaign.Slug != "flash-sale-2" {
+		t.Errorf("GetBySlug() = campaign %d (%s), want campaign 2 (flash-sale-2)", campaign.ID, campaign.Slug)
+	}
+
+	var appErr *models.AppError
+	_, err = svc.GetBySlug(context.Background(), "summer-sale")
+	if !errors.As(err, &appErr) || appErr.Code != "NOT_FOUND" {
+		t.Errorf("GetBySlug(unknown) error = %v, want NOT_FOUND", err)
+	}
+}
+
+func TestCampaignService_SetMaxCost(t *testing.T) {
+	campaigns := &campaignStore{
+		all: []*models.Campaign{
+			{ID: 1, Name: "Flash sale", Channel: models.ChannelSMS, Status: models.CampaignStatusPaused},
+		},
+	}
+	svc := &campaignService{
+		campaignRepo: campaigns.repo(t),
+		logger:       slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	}
+
+	maxCost := 250.0
+	campaign, err := svc.SetMaxCost(context.Background(), 1, &SetMaxCostRequest{MaxCost: &maxCost})
+	if err != nil {
+		t.Fatalf("SetMaxCost() error = %v", err)
+	}
+	if campaign.MaxCost == nil || *campaign.MaxCost != maxCost {
+		t.Errorf("MaxCost = %v, want %v", campaign.MaxCost, maxCost)
+	}
+
+	// A null cap removes it
+	campaign, err = svc.SetMaxCost(context.Background(), 1, &SetMaxCostRequest{})
+	if err != nil {
+		t.Fatalf("SetMaxCost(nil) error = %v", err)
+	}
+	if campaign.MaxCost != nil {
+		t.Errorf("MaxCost = %v, want no cap", *campaign.MaxCost)
+	}
+
+	zero := 0.0
+	var appErr *models.AppError
+	_, err = svc.SetMaxCost(context.Background(), 1, &SetMaxCostRequest{MaxCost: &zero})
+	if !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+		t.Errorf("SetMaxCost(0) error = %v, want INVALID_INPUT", err)
+	}
+}
+
+func TestCampaignService_SetMaxInFlight(t *testing.T) {
+	campaigns := &campaignStore{
+		all: []*models.Campaign{
+			{ID: 1, Name: "Callback survey", Channel: models.ChannelSMS, Status: models.CampaignStatusSending},
+		},
+	}
+	svc := &campaignService{
+		campaignRepo: campaigns.repo(t),
+		logger:       slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	}
+
+	maxInFlight := 20
+	campaign, err := svc.SetMaxInFlight(context.Background(), 1, &SetMaxInFlightRequest{MaxInFlight: &maxInFlight})
+	if err != nil {
+		t.Fatalf("SetMaxInFlight() error = %v", err)
+	}
+	if campaign.MaxInFlight == nil || *campaign.MaxInFlight != maxInFlight {
+		t.Errorf("MaxInFlight = %v, want %d", campaign.MaxInFlight, maxInFlight)
+	}
+
+	// A null limit removes it
+	campaign, err = svc.SetMaxInFlight(context.Background(), 1, &SetMaxInFlightRequest{})
+	if err != nil {
+		t.Fatalf("SetMaxInFlight(nil) error = %v", err)
+	}
+	if campaign.MaxInFlight != nil {
+		t.Errorf("MaxInFlight = %d, want no limit", *campaign.MaxInFlight)
+	}
+
+	for _, invalid := range []int{0, maxInFlightLimit + 1} {
+		var appErr *models.AppError
+		_, err = svc.SetMaxInFlight(context.Background(), 1, &SetMaxInFlightRequest{MaxInFlight: &invalid})
+		if !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+			t.Errorf("SetMaxInFlight(%d) error = %v, want INVALID_INPUT", invalid, err)
+		}
+	}
+}
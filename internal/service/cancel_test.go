@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestCampaignService_Cancel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+	messageRepo := mocks.NewMockOutboundMessageRepository(ctrl)
+	queueClient := mocks.NewMockClient(ctrl)
+
+	campaignRepo.EXPECT().GetByID(gomock.Any(), int64(1)).
+		Return(&models.Campaign{ID: 1, AccountID: 1, Status: models.CampaignStatusSending}, nil)
+	gomock.InOrder(
+		campaignRepo.EXPECT().Cancel(gomock.Any(), int64(1)).Return(true, nil),
+		messageRepo.EXPECT().SkipPendingByCampaign(gomock.Any(), int64(1)).Return(int64(40), nil),
+		queueClient.EXPECT().RemoveCampaignJobs(gomock.Any(), int64(1)).Return(int64(38), nil),
+	)
+
+	svc := &campaignService{
+		campaignRepo: campaignRepo,
+		messageRepo:  messageRepo,
+		queueClient:  queueClient,
+		logger:       slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	}
+
+	result, err := svc.Cancel(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+	want := CancelCampaignResult{CampaignID: 1, MessagesSkipped: 40, JobsRemoved: 38, Status: models.CampaignStatusCancelled}
+	if *result != want {
+		t.Errorf("Cancel() = %+v, want %+v", *result, want)
+	}
+}
+
+func TestCampaignService_Cancel_Finished(t *testing.T) {
+	for _, status := range []string{models.CampaignStatusDraft, models.CampaignStatusSent, models.CampaignStatusCancelled} {
+		t.Run(status, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+
+			// Nothing is touched when the campaign cannot be cancelled
+			campaignRepo.EXPECT().GetByID(gomock.Any(), int64(2)).
+				Return(&models.Campaign{ID: 2, Status: status}, nil)
+
+			svc := &campaignService{
+				campaignRepo: campaignRepo,
+				logger:       slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+			}
+
+			_, err := svc.Cancel(context.Background(), 2)
+			var appErr *models.AppError
+			if !errors.As(err, &appErr) || appErr.Code != "CONFLICT" {
+				t.Errorf("Cancel() of a %s campaign error = %v, want CONFLICT", status, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+const (
+	// changeFeedSettle is how old a change must be before the feeds return it.
+	// updated_at is set when a transaction starts, so a row committed late can
+	// carry a timestamp behind a cursor a poller already holds; waiting lets
+	// such transactions commit before the feed moves past them.
+	changeFeedSettle = 5 * time.Second
+
+	defaultChangeFeedLimit = 100
+	maxChangeFeedLimit     = 500
+)
+
+// changeFeedPosition parses a change feed cursor and clamps the page size
+func changeFeedPosition(cursor string, limit int) (models.ChangeCursor, int, error) {
+	after, err := models.ParseChangeCursor(cursor)
+	if err != nil {
+		return models.ChangeCursor{}, 0, models.ErrInvalidInput(err.Error())
+	}
+
+	return after, changeFeedLimit(limit), nil
+}
+
+// changeFeedLimit applies the default and maximum page size of the change feeds
+func changeFeedLimit(limit int) int {
+	if limit < 1 {
+		return defaultChangeFeedLimit
+	}
+	if limit > maxChangeFeedLimit {
+		return maxChangeFeedLimit
+	}
+	return limit
+}
+
+// ListUpdatedSince returns campaigns changed after the cursor, oldest change first
+func (s *campaignService) ListUpdatedSince(ctx context.Context, cursor string, limit int) (*CampaignChangesResult, error) {
+	after, limit, err := changeFeedPosition(cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read one extra row to learn whether another page is waiting
+	changes, err := s.campaignRepo.ListUpdatedSince(ctx, after, changeFeedSettle, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list updated campaigns: %w", err)
+	}
+
+	result := &CampaignChangesResult{NextCursor: cursor}
+	if len(changes) > limit {
+		changes = changes[:limit]
+		result.HasMore = true
+	}
+	if len(changes) > 0 {
+		last := changes[len(changes)-1]
+		result.NextCursor = models.ChangeCursor{UpdatedAt: last.UpdatedAt, ID: last.ID}.String()
+	}
+	result.Data = changes
+
+	return result, nil
+}
+
+// ListUpdatedSince returns messages changed after the cursor, oldest change first
+func (s *messageService) ListUpdatedSince(ctx context.Context, cursor string, limit int) (*MessageChangesResult, error) {
+	after, limit, err := changeFeedPosition(cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read one extra row to learn whether another page is waiting
+	messages, err := s.messageRepo.ListUpdatedSince(ctx, after, changeFeedSettle, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list updated messages: %w", err)
+	}
+
+	result := &MessageChangesResult{NextCursor: cursor}
+	if len(messages) > limit {
+		messages = messages[:limit]
+		result.HasMore = true
+	}
+	if len(messages) > 0 {
+		last := messages[len(messages)-1]
+		result.NextCursor = models.ChangeCursor{UpdatedAt: last.UpdatedAt, ID: last.ID}.String()
+	}
+	result.Data = messages
+
+	return result, nil
+}
+
+// ListReceipts returns receipts of messages changed after the cursor, oldest
+// change first. Without a cursor the feed starts at since, or at the beginning
+// when since is nil; once a cursor is passed, since is ignored.
+func (s *messageService) ListReceipts(ctx context.Context, since *time.Time, cursor string, limit int) (*ReceiptListResult, error) {
+	after, limit, err := changeFeedPosition(cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	if cursor == "" && since != nil {
+		// ID 0 sorts before every row, so receipts updated exactly at since are included
+		after = models.ChangeCursor{UpdatedAt: since.UTC()}
+	}
+
+	// Read one extra row to learn whether another page is waiting
+	receipts, err := s.messageRepo.ListReceipts(ctx, after, changeFeedSettle, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list message receipts: %w", err)
+	}
+
+	result := &ReceiptListResult{NextCursor: cursor}
+	if len(receipts) > limit {
+		receipts = receipts[:limit]
+		result.HasMore = true
+	}
+	if len(receipts) > 0 {
+		last := receipts[len(receipts)-1]
+		result.NextCursor = models.ChangeCursor{UpdatedAt: last.UpdatedAt, ID: last.MessageID}.String()
+	}
+	result.Data = receipts
+
+	return result, nil
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestMessageService_ListUpdatedSince(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	messageRepo := mocks.NewMockOutboundMessageRepository(ctrl)
+	campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+	svc := NewMessageService(messageRepo, campaignRepo, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+	updatedAt := time.Date(2026, 3, 1, 9, 30, 0, 123456000, time.UTC)
+	page := []*models.OutboundMessage{
+		{ID: 7, Status: models.MessageStatusSent, UpdatedAt: updatedAt},
+		{ID: 9, Status: models.MessageStatusFailed, UpdatedAt: updatedAt},
+		{ID: 4, Status: models.MessageStatusSent, UpdatedAt: updatedAt.Add(time.Second)},
+	}
+
+	// A limit of 2 reads 3 rows to detect the next page
+	messageRepo.EXPECT().
+		ListUpdatedSince(gomock.Any(), models.ChangeCursor{}, changeFeedSettle, 3).
+		Return(page, nil)
+
+	result, err := svc.ListUpdatedSince(context.Background(), "", 2)
+	if err != nil {
+		t.Fatalf("ListUpdatedSince() error = %v", err)
+	}
+	if len(result.Data) != 2 || !result.HasMore {
+		t.Fatalf("result = %d messages, has_more %v, want 2 and true", len(result.Data), result.HasMore)
+	}
+
+	// The next cursor resumes after the last returned message, in the same instant
+	cursor, err := models.ParseChangeCursor(result.NextCursor)
+	if err != nil {
+		t.Fatalf("ParseChangeCursor(%q) error = %v", result.NextCursor, err)
+	}
+	if cursor.ID != 9 || !cursor.UpdatedAt.Equal(updatedAt) {
+		t.Errorf("cursor = %+v, want message 9 at %v", cursor, updatedAt)
+	}
+
+	// With nothing new the poller keeps its cursor
+	messageRepo.EXPECT().
+		ListUpdatedSince(gomock.Any(), cursor, changeFeedSettle, defaultChangeFeedLimit+1).
+		Return([]*models.OutboundMessage{}, nil)
+
+	result, err = svc.ListUpdatedSince(context.Background(), result.NextCursor, 0)
+	if err != nil {
+		t.Fatalf("ListUpdatedSince() error = %v", err)
+	}
+	if result.NextCursor != cursor.String() || result.HasMore || len(result.Data) != 0 {
+		t.Errorf("result = %+v, want an empty page keeping the cursor", result)
+	}
+
+	if _, err := svc.ListUpdatedSince(context.Background(), "not-a-cursor", 10); err == nil {
+		t.Error("ListUpdatedSince() with invalid cursor error = nil, want error")
+	}
+}
+
+func TestMessageService_ListReceipts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	messageRepo := mocks.NewMockOutboundMessageRepository(ctrl)
+	campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+	svc := NewMessageService(messageRepo, campaignRepo, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+	// since starts the feed at that instant, whatever zone it was given in
+	since := time.Date(2026, 3, 1, 12, 0, 0, 0, time.FixedZone("EAT", 3*60*60))
+	updatedAt := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	messageRepo.EXPECT().
+		ListReceipts(gomock.Any(), models.ChangeCursor{UpdatedAt: since.UTC()}, changeFeedSettle, defaultChangeFeedLimit+1).
+		Return([]*models.MessageReceipt{
+			{MessageID: 12, Status: models.MessageStatusDelivered, PhoneHash: models.HashPhone("+254700000001"), UpdatedAt: updatedAt},
+		}, nil)
+
+	result, err := svc.ListReceipts(context.Background(), &since, "", 0)
+	if err != nil {
+		t.Fatalf("ListReceipts() error = %v", err)
+	}
+	if len(result.Data) != 1 || result.HasMore {
+		t.Fatalf("result = %d receipts, has_more %v, want 1 and false", len(result.Data), result.HasMore)
+	}
+	want := models.ChangeCursor{UpdatedAt: updatedAt, ID: 12}.String()
+	if result.NextCursor != want {
+		t.Errorf("NextCursor = %q, want %q", result.NextCursor, want)
+	}
+
+	// Once a cursor is passed, since no longer moves the start
+	cursor, _ := models.ParseChangeCursor(result.NextCursor)
+	messageRepo.EXPECT().
+		ListReceipts(gomock.Any(), cursor, changeFeedSettle, defaultChangeFeedLimit+1).
+		Return([]*models.MessageReceipt{}, nil)
+
+	if _, err := svc.ListReceipts(context.Background(), &since, result.NextCursor, 0); err != nil {
+		t.Fatalf("ListReceipts() error = %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// ChangeService reads the change log that lets data warehouses sync incrementally
+type ChangeService interface {
+	List(ctx context.Context, afterID int64, entity string, limit int) (*ChangeListResult, error)
+}
+
+type changeService struct {
+	changeRepo repository.ChangeLogRepository
+	logger     *slog.Logger
+}
+
+// NewChangeService creates a new change service
+func NewChangeService(changeRepo repository.ChangeLogRepository, logger *slog.Logger) ChangeService {
+	return &changeService{
+		changeRepo: changeRepo,
+		logger:     logger,
+	}
+}
+
+// List returns change records after afterID in the order they were written,
+// optionally for a single entity
+func (s *changeService) List(ctx context.Context, afterID int64, entity string, limit int) (*ChangeListResult, error) {
+	if entity != "" && !models.IsValidChangeEntity(entity) {
+		return nil, models.ErrInvalidInput(fmt.Sprintf("invalid entity: %s", entity))
+	}
+
+	limit = changeFeedLimit(limit)
+
+	// Read one extra record to learn whether another page is waiting
+	records, err := s.changeRepo.ListAfter(ctx, afterID, entity, changeFeedSettle, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changes: %w", err)
+	}
+
+	result := &ChangeListResult{NextAfterID: afterID}
+	if len(records) > limit {
+		records = records[:limit]
+		result.HasMore = true
+	}
+	if len(records) > 0 {
+		result.NextAfterID = records[len(records)-1].ID
+	}
+	result.Data = records
+
+	return result, nil
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestChangeService_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	changeRepo := mocks.NewMockChangeLogRepository(ctrl)
+	svc := NewChangeService(changeRepo, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+	changeRepo.EXPECT().
+		ListAfter(gomock.Any(), int64(40), models.ChangeEntityCampaign, changeFeedSettle, 3).
+		Return([]*models.ChangeRecord{
+			{ID: 41, Entity: models.ChangeEntityCampaign, EntityID: "5", Operation: models.ChangeOperationUpdate},
+			{ID: 44, Entity: models.ChangeEntityCampaign, EntityID: "5", Operation: models.ChangeOperationDelete},
+			{ID: 47, Entity: models.ChangeEntityCampaign, EntityID: "6", Operation: models.ChangeOperationInsert},
+		}, nil)
+
+	result, err := svc.List(context.Background(), 40, models.ChangeEntityCampaign, 2)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(result.Data) != 2 || !result.HasMore || result.NextAfterID != 44 {
+		t.Errorf("result = %d records, has_more %v, next %d; want 2, true, 44", len(result.Data), result.HasMore, result.NextAfterID)
+	}
+
+	// With nothing new the consumer keeps its position
+	changeRepo.EXPECT().
+		ListAfter(gomock.Any(), int64(44), "", changeFeedSettle, defaultChangeFeedLimit+1).
+		Return([]*models.ChangeRecord{}, nil)
+
+	result, err = svc.List(context.Background(), 44, "", 0)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if result.NextAfterID != 44 || result.HasMore {
+		t.Errorf("result = %+v, want an empty page keeping after_id 44", result)
+	}
+
+	if _, err := svc.List(context.Background(), 0, "simulation", 10); err == nil {
+		t.Error("List() with unknown entity error = nil, want error")
+	}
+}
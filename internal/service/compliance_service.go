@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// Opt-out report periods
+const (
+	defaultReportDays = 30
+	maxReportDays     = 366
+)
+
+// Reply keywords, matched against the whole message ignoring case. These are
+// the keywords carriers and regulators expect a sender to honour.
+var (
+	optOutKeywords = map[string]bool{"STOP": true, "STOPALL": true, "UNSUBSCRIBE": true, "CANCEL": true, "END": true, "QUIT": true}
+	optInKeywords  = map[string]bool{"START": true, "UNSTOP": true, "SUBSCRIBE": true}
+)
+
+// ComplianceService handles customer opt-outs and the reports regulated
+// senders need for audits
+type ComplianceService interface {
+	// ReceiveInbound parses a provider's inbound message callback and applies
+	// STOP and START replies
+	ReceiveInbound(ctx context.Context, provider string, body []byte) (*InboundResult, error)
+	OptOutReport(ctx context.Context, req *OptOutReportRequest) (*models.OptOutReport, error)
+}
+
+// inboundMessage is a customer's reply
+type inboundMessage struct {
+	phone string
+	text  string
+}
+
+// inboundParser reads the replies out of an inbound message callback body
+type inboundParser func(body []byte) ([]inboundMessage, error)
+
+// inboundParsers are keyed by the provider names SENDER_PROVIDER and
+// WHATSAPP_PROVIDER take
+var inboundParsers = map[string]inboundParser{
+	"africastalking": parseAfricasTalkingInbound,
+	"twilio":         parseTwilioInbound,
+	"meta":           parseMetaInbound,
+}
+
+type complianceService struct {
+	customerRepo repository.CustomerRepository
+	eventRepo    repository.CustomerEventRepository
+	now          func() time.Time
+	logger       *slog.Logger
+}
+
+// NewComplianceService creates a new compliance service
+func NewComplianceService(
+	customerRepo repository.CustomerRepository,
+	eventRepo repository.CustomerEventRepository,
+	logger *slog.Logger,
+) ComplianceService {
+	return &complianceService{
+		customerRepo: customerRepo,
+		eventRepo:    eventRepo,
+		now:          time.Now,
+		logger:       logger,
+	}
+}
+
+// ReceiveInbound opts customers out on STOP and back in on START. Other
+// replies and replies from unknown numbers are ignored.
+func (s *complianceService) ReceiveInbound(ctx context.Context, provider string, body []byte) (*InboundResult, error) {
+	parse, ok := inboundParsers[provider]
+	if !ok {
+		return nil, models.ErrInvalidInput(fmt.Sprintf("unknown provider %q (valid providers are: africastalking, twilio, meta)", provider))
+	}
+
+	messages, err := parse(body)
+	if err != nil {
+		return nil, models.ErrInvalidInput(fmt.Sprintf("invalid %s inbound message: %v", provider, err))
+	}
+
+	result := &InboundResult{}
+	for _, message := range messages {
+		keyword := strings.ToUpper(strings.TrimSpace(message.text))
+		optOut, optIn := optOutKeywords[keyword], optInKeywords[keyword]
+		if !optOut && !optIn {
+			result.Ignored++
+			continue
+		}
+
+		customer, err := s.customerRepo.GetByPhone(ctx, message.phone)
+		if errors.Is(err, models.ErrNotFound) {
+			result.Ignored++
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to find customer: %w", err)
+		}
+
+		changed, err := s.setOptedOut(ctx, customer, keyword, optOut)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case !changed:
+			result.Ignored++
+		case optOut:
+			result.OptedOut++
+		default:
+			result.OptedIn++
+		}
+	}
+
+	return result, nil
+}
+
+// setOptedOut changes the customer's opt-out state and records the change on
+// their timeline. Opt-outs are attributed to the last campaign the customer
+// was sent. It returns false when the customer already was in that state.
+func (s *complianceService) setOptedOut(ctx context.Context, customer *models.Customer, keyword string, optOut bool) (bool, error) {
+	changed, err := s.customerRepo.SetOptedOut(ctx, customer.ID, optOut)
+	if err != nil {
+		s.logger.Error("failed to set customer opt-out",
+			slog.Int64("customer_id", customer.ID),
+			slog.String("error", err.Error()),
+		)
+		return false, err
+	}
+	if !changed {
+		return false, nil
+	}
+
+	event := &models.CustomerEvent{CustomerID: customer.ID, Type: models.CustomerEventOptedIn}
+	if optOut {
+		event.Type = models.CustomerEventOptedOut
+		campaignID, err := s.eventRepo.LastSentCampaignID(ctx, customer.ID)
+		if err != nil {
+			s.logger.Error("failed to attribute opt-out",
+				slog.Int64("customer_id", customer.ID),
+				slog.String("error", err.Error()),
+			)
+		}
+		event.CampaignID = campaignID
+	}
+	event.Details, _ = json.Marshal(map[string]string{"keyword": keyword})
+
+	// The opt-out already applies; a lost event only leaves it out of reports
+	if err := s.eventRepo.Record(ctx, event); err != nil {
+		s.logger.Error("failed to record customer event",
+			slog.Int64("customer_id", customer.ID),
+			slog.String("type", event.Type),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	s.logger.Info("customer opt-out changed",
+		slog.Int64("customer_id", customer.ID),
+		slog.Bool("opted_out", optOut),
+		slog.String("keyword", keyword),
+	)
+
+	return true, nil
+}
+
+// OptOutReport summarizes opt-outs per campaign between two dates, inclusive.
+// Without dates it covers the last 30 days.
+func (s *complianceService) OptOutReport(ctx context.Context, req *OptOutReportRequest) (*models.OptOutReport, error) {
+	from, to, err := req.period(models.StartOfDay(s.now()))
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.eventRepo.OptOutReport(ctx, from, to)
+	if err != nil {
+		s.logger.Error("failed to build opt-out report",
+			slog.String("error", err.Error()),
+		)
+		return nil, err
+	}
+	report.From, report.To = from, to.AddDate(0, 0, -1)
+
+	sending := 0
+	total := 0.0
+	for _, campaign := range report.Campaigns {
+		if campaign.MessagesSent == 0 {
+			continue
+		}
+		campaign.OptOutRate = roundRate(float64(campaign.OptOuts) / float64(campaign.MessagesSent))
+		total += campaign.OptOutRate
+		sending++
+	}
+	if sending > 0 {
+		report.AverageOptOutRate = roundRate(total / float64(sending))
+	}
+
+	return report, nil
+}
+
+// roundRate keeps four decimals of a rate, enough for hundredths of a percent
+func roundRate(rate float64) float64 {
+	return math.Round(rate*10000) / 10000
+}
+
+// parseAfricasTalkingInbound reads an Africa's Talking incoming message
+// callback, a form with the sender in from and the message in text
+func parseAfricasTalkingInbound(body []byte) ([]inboundMessage, error) {
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+	if form.Get("from") == "" {
+		return nil, fmt.Errorf("missing from")
+	}
+
+	return []inboundMessage{{phone: form.Get("from"), text: form.Get("text")}}, nil
+}
+
+// parseTwilioInbound reads a Twilio incoming message webhook, a form with the
+// sender in From and the message in Body
+func parseTwilioInbound(body []byte) ([]inboundMessage, error) {
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+	if form.Get("From") == "" {
+		return nil, fmt.Errorf("missing From")
+	}
+
+	return []inboundMessage{{phone: form.Get("From"), text: form.Get("Body")}}, nil
+}
+
+// metaInboundWebhook is the part of a WhatsApp Cloud API webhook carrying
+// incoming messages
+type metaInboundWebhook struct {
+	Entry []struct {
+		Changes []struct {
+			Value struct {
+				Messages []struct {
+					From string `json:"from"`
+					Type string `json:"type"`
+					Text struct {
+						Body string `json:"body"`
+					} `json:"text"`
+				} `json:"messages"`
+			} `json:"value"`
+		} `json:"changes"`
+	} `json:"entry"`
+}
+
+// parseMetaInbound reads the text messages in a WhatsApp Cloud API webhook.
+// Meta sends numbers without the leading '+' customers are stored with.
+func parseMetaInbound(body []byte) ([]inboundMessage, error) {
+	var webhook metaInboundWebhook
+	if err := json.Unmarshal(body, &webhook); err != nil {
+		return nil, err
+	}
+
+	var messages []inboundMessage
+	for _, entry := range webhook.Entry {
+		for _, change := range entry.Changes {
+			for _, message := range change.Value.Messages {
+				if message.Type != "text" || message.From == "" {
+					continue
+				}
+				messages = append(messages, inboundMessage{phone: "+" + strings.TrimPrefix(message.From, "+"), text: message.Text.Body})
+			}
+		}
+	}
+
+	return messages, nil
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestInboundParsers(t *testing.T) {
+	tests := []struct {
+		name      string
+		provider  string
+		body      string
+		wantPhone string
+		wantText  string
+	}{
+		{name: "africastalking", provider: "africastalking", body: "from=%2B254700000001&to=40404&text=STOP&id=abc", wantPhone: "+254700000001", wantText: "STOP"},
+		{name: "twilio", provider: "twilio", body: "From=%2B15005550009&To=%2B15005550006&Body=Stop", wantPhone: "+15005550009", wantText: "Stop"},
+		{
+			name:      "meta",
+			provider:  "meta",
+			body:      `{"entry":[{"changes":[{"value":{"messages":[{"from":"254700000001","type":"text","text":{"body":"stop"}}]}}]}]}`,
+			wantPhone: "+254700000001",
+			wantText:  "stop",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages, err := inboundParsers[tt.provider]([]byte(tt.body))
+			if err != nil {
+				t.Fatalf("parse error = %v", err)
+			}
+			if len(messages) != 1 || messages[0].phone != tt.wantPhone || messages[0].text != tt.wantText {
+				t.Errorf("parse = %+v, want %s: %q", messages, tt.wantPhone, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestComplianceService_ReceiveInbound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	customerRepo := mocks.NewMockCustomerRepository(ctrl)
+	eventRepo := mocks.NewMockCustomerEventRepository(ctrl)
+	svc := NewComplianceService(customerRepo, eventRepo, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	customer := &models.Customer{ID: 7, Phone: "+254700000001"}
+	campaignID := int64(3)
+
+	customerRepo.EXPECT().GetByPhone(gomock.Any(), "+254700000001").Return(customer, nil).Times(2)
+	customerRepo.EXPECT().GetByPhone(gomock.Any(), "+254700000009").Return(nil, models.ErrNotFoundWithMsg("customer not found"))
+	customerRepo.EXPECT().SetOptedOut(gomock.Any(), int64(7), true).Return(true, nil)
+	customerRepo.EXPECT().SetOptedOut(gomock.Any(), int64(7), false).Return(true, nil)
+	eventRepo.EXPECT().LastSentCampaignID(gomock.Any(), int64(7)).Return(&campaignID, nil)
+
+	var recorded []*models.CustomerEvent
+	eventRepo.EXPECT().Record(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, event *models.CustomerEvent) error {
+			recorded = append(recorded, event)
+			return nil
+		}).
+		Times(2)
+
+	replies := []struct {
+		body string
+		want InboundResult
+	}{
+		{body: "From=%2B254700000001&Body=+stop+", want: InboundResult{OptedOut: 1}},
+		{body: "From=%2B254700000001&Body=Start", want: InboundResult{OptedIn: 1}},
+		{body: "From=%2B254700000009&Body=STOP", want: InboundResult{Ignored: 1}},
+		{body: "From=%2B254700000001&Body=Please+stop+sending", want: InboundResult{Ignored: 1}},
+	}
+	for _, reply := range replies {
+		result, err := svc.ReceiveInbound(context.Background(), "twilio", []byte(reply.body))
+		if err != nil {
+			t.Fatalf("ReceiveInbound(%q) error = %v", reply.body, err)
+		}
+		if *result != reply.want {
+			t.Errorf("ReceiveInbound(%q) = %+v, want %+v", reply.body, *result, reply.want)
+		}
+	}
+
+	if len(recorded) != 2 || recorded[0].Type != models.CustomerEventOptedOut || recorded[1].Type != models.CustomerEventOptedIn {
+		t.Fatalf("recorded events = %v, want opted_out then opted_in", recorded)
+	}
+	if recorded[0].CampaignID == nil || *recorded[0].CampaignID != campaignID {
+		t.Errorf("opt-out campaign = %v, want %d", recorded[0].CampaignID, campaignID)
+	}
+	if string(recorded[0].Details) != `{"keyword":"STOP"}` {
+		t.Errorf("opt-out details = %s, want the keyword", recorded[0].Details)
+	}
+}
+
+func TestComplianceService_OptOutReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	eventRepo := mocks.NewMockCustomerEventRepository(ctrl)
+	svc := &complianceService{
+		customerRepo: mocks.NewMockCustomerRepository(ctrl),
+		eventRepo:    eventRepo,
+		now:          func() time.Time { return time.Date(2025, 6, 30, 15, 0, 0, 0, time.UTC) },
+		logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	eventRepo.EXPECT().OptOutReport(gomock.Any(), time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)).
+		Return(&models.OptOutReport{
+			OptOuts:      5,
+			Resubscribed: 1,
+			Campaigns: []*models.CampaignOptOuts{
+				{CampaignID: 1, MessagesSent: 1000, OptOuts: 4},
+				{CampaignID: 2, MessagesSent: 500, OptOuts: 0},
+				{CampaignID: 3, MessagesSent: 0, OptOuts: 1},
+			},
+		}, nil)
+
+	report, err := svc.OptOutReport(context.Background(), &OptOutReportRequest{})
+	if err != nil {
+		t.Fatalf("OptOutReport() error = %v", err)
+	}
+	if !report.From.Equal(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)) || !report.To.Equal(time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("OptOutReport() period = %s to %s, want the last 30 days", report.From, report.To)
+	}
+	if report.Campaigns[0].OptOutRate != 0.004 || report.Campaigns[2].OptOutRate != 0 {
+		t.Errorf("campaign rates = %v, %v, want 0.004 and 0", report.Campaigns[0].OptOutRate, report.Campaigns[2].OptOutRate)
+	}
+	// Campaign 3 sent nothing in the period and is left out of the average
+	if report.AverageOptOutRate != 0.002 {
+		t.Errorf("AverageOptOutRate = %v, want 0.002", report.AverageOptOutRate)
+	}
+
+	invalid := []*OptOutReportRequest{
+		{From: "June"},
+		{From: "2025-06-10", To: "2025-06-01"},
+		{From: "2024-01-01", To: "2025-06-01"},
+	}
+	for _, req := range invalid {
+		var appErr *models.AppError
+		if _, err := svc.OptOutReport(context.Background(), req); !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+			t.Errorf("OptOutReport(%+v) error = %v, want INVALID_INPUT", req, err)
+		}
+	}
+}
@@ -2,8 +2,12 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
+	"maps"
+	"strings"
+	"time"
 
 	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
 	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
@@ -14,24 +18,48 @@ type CustomerService interface {
 	Create(ctx context.Context, customer *models.Customer) (*models.Customer, error)
 	GetByID(ctx context.Context, id int64) (*models.Customer, error)
 	GetByPhone(ctx context.Context, phone string) (*models.Customer, error)
+	GetByExternalID(ctx context.Context, externalID string) (*models.Customer, error)
+	LookupByPhones(ctx context.Context, req *CustomerLookupRequest) (*CustomerLookupResult, error)
+	Timeline(ctx context.Context, customerID int64, before *time.Time, limit int) (*CustomerTimeline, error)
 	List(ctx context.Context, filter models.CustomerFilter) ([]*models.Customer, models.PaginationResult, error)
 	Update(ctx context.Context, customer *models.Customer) (*models.Customer, error)
-	Delete(ctx context.Context, id int64) error
+	Delete(ctx context.Context, id int64, anonymize bool) error
+	// Snooze keeps a customer out of campaigns until a time or, without
+	// one, lets a snoozed customer back in
+	Snooze(ctx context.Context, id int64, req *SnoozeCustomerRequest) (*models.Customer, error)
 }
 
+// Timeline page size limits
+const (
+	defaultTimelineLimit = 100
+	maxTimelineLimit     = 500
+)
+
+// maxSnooze is how far ahead a customer can be snoozed; longer breaks are
+// opt-outs
+const maxSnooze = 366 * 24 * time.Hour
+
 type customerService struct {
 	customerRepo repository.CustomerRepository
+	messageRepo  repository.OutboundMessageRepository
+	eventRepo    repository.CustomerEventRepository
 	logger       *slog.Logger
+	now          func() time.Time
 }
 
 // NewCustomerService creates a new customer service
 func NewCustomerService(
 	customerRepo repository.CustomerRepository,
+	messageRepo repository.OutboundMessageRepository,
+	eventRepo repository.CustomerEventRepository,
 	logger *slog.Logger,
 ) CustomerService {
 	return &customerService{
 		customerRepo: customerRepo,
+		messageRepo:  messageRepo,
+		eventRepo:    eventRepo,
 		logger:       logger,
+		now:          time.Now,
 	}
 }
 
@@ -64,6 +92,8 @@ func (s *customerService) Create(ctx context.Context, customer *models.Customer)
 		slog.String("phone", customer.Phone),
 	)
 
+	s.recordEvent(ctx, customer.ID, models.CustomerEventCreated, nil)
+
 	return customer, nil
 }
 
@@ -87,6 +117,54 @@ func (s *customerService) GetByPhone(ctx context.Context, phone string) (*models
 	return customer, nil
 }
 
+// GetByExternalID retrieves a customer by the ID an external system knows it by
+func (s *customerService) GetByExternalID(ctx context.Context, externalID string) (*models.Customer, error) {
+	return s.customerRepo.GetByExternalID(ctx, externalID)
+}
+
+// LookupByPhones resolves a list of phone numbers to customers in one query.
+// Phones are trimmed and de-duplicated; misses keep the order they were given in.
+func (s *customerService) LookupByPhones(ctx context.Context, req *CustomerLookupRequest) (*CustomerLookupResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(req.Phones))
+	phones := make([]string, 0, len(req.Phones))
+	for _, phone := range req.Phones {
+		phone = strings.TrimSpace(phone)
+		if phone == "" {
+			return nil, models.ErrInvalidInput("phones cannot contain empty values")
+		}
+		if !seen[phone] {
+			seen[phone] = true
+			phones = append(phones, phone)
+		}
+	}
+
+	customers, err := s.customerRepo.GetByPhones(ctx, phones)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up customers: %w", err)
+	}
+
+	found := make(map[string]bool, len(customers))
+	for _, customer := range customers {
+		found[customer.Phone] = true
+	}
+
+	misses := make([]string, 0, len(phones)-len(customers))
+	for _, phone := range phones {
+		if !found[phone] {
+			misses = append(misses, phone)
+		}
+	}
+
+	return &CustomerLookupResult{
+		Matched: customers,
+		Misses:  misses,
+	}, nil
+}
+
 // List retrieves customers with pagination
 func (s *customerService) List(ctx context.Context, filter models.CustomerFilter) ([]*models.Customer, models.PaginationResult, error) {
 	customers, totalCount, err := s.customerRepo.List(ctx, filter)
@@ -109,6 +187,21 @@ func (s *customerService) Update(ctx context.Context, customer *models.Customer)
 		return nil, err
 	}
 
+	previous, err := s.customerRepo.GetByID(ctx, customer.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Phones identify customers, so a new phone must not belong to someone else
+	if customer.Phone != previous.Phone {
+		existing, err := s.customerRepo.GetByPhone(ctx, customer.Phone)
+		if err == nil && existing != nil && existing.ID != customer.ID {
+			return nil, models.ErrConflictWithMsg(
+				fmt.Sprintf("customer with phone %s already exists", customer.Phone),
+			)
+		}
+	}
+
 	// Update customer
 	if err := s.customerRepo.Update(ctx, customer); err != nil {
 		s.logger.Error("failed to update customer",
@@ -122,11 +215,51 @@ func (s *customerService) Update(ctx context.Context, customer *models.Customer)
 		slog.Int64("customer_id", customer.ID),
 	)
 
+	if changed := changedCustomerFields(previous, customer); len(changed) > 0 {
+		s.recordEvent(ctx, customer.ID, models.CustomerEventProfileUpdated, map[string]interface{}{"fields": changed})
+	}
+
 	return customer, nil
 }
 
-// Delete removes a customer
-func (s *customerService) Delete(ctx context.Context, id int64) error {
+// Delete removes a customer. Customers with message history are refused unless
+// anonymize is set, in which case their personal data is scrubbed and the row is
+// kept so that the message history stays consistent.
+func (s *customerService) Delete(ctx context.Context, id int64, anonymize bool) error {
+	if _, err := s.customerRepo.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	messageCount, err := s.messageRepo.CountByCustomer(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to check customer message history: %w", err)
+	}
+
+	if messageCount > 0 {
+		if !anonymize {
+			return models.ErrConflictWithMsg(
+				fmt.Sprintf("customer %d has %d messages in their history; pass anonymize=true to scrub their personal data instead", id, messageCount),
+			)
+		}
+
+		if err := s.customerRepo.Anonymize(ctx, id); err != nil {
+			s.logger.Error("failed to anonymize customer",
+				slog.Int64("customer_id", id),
+				slog.String("error", err.Error()),
+			)
+			return fmt.Errorf("failed to anonymize customer: %w", err)
+		}
+
+		s.logger.Info("customer anonymized",
+			slog.Int64("customer_id", id),
+			slog.Int64("message_count", messageCount),
+		)
+
+		s.recordEvent(ctx, id, models.CustomerEventAnonymized, nil)
+
+		return nil
+	}
+
 	if err := s.customerRepo.Delete(ctx, id); err != nil {
 		s.logger.Error("failed to delete customer",
 			slog.Int64("customer_id", id),
@@ -141,3 +274,135 @@ func (s *customerService) Delete(ctx context.Context, id int64) error {
 
 	return nil
 }
+
+// Timeline returns a customer's activity in chronological order: profile and
+// consent events together with every message queued, sent or failed. It holds
+// the latest limit entries before the given time (now when nil); pass
+// NextBefore back as before to page further into the past.
+func (s *customerService) Timeline(ctx context.Context, customerID int64, before *time.Time, limit int) (*CustomerTimeline, error) {
+	if _, err := s.customerRepo.GetByID(ctx, customerID); err != nil {
+		return nil, err
+	}
+
+	if limit < 1 {
+		limit = defaultTimelineLimit
+	}
+	if limit > maxTimelineLimit {
+		limit = maxTimelineLimit
+	}
+
+	cursor := time.Now()
+	if before != nil {
+		cursor = *before
+	}
+
+	entries, err := s.eventRepo.Timeline(ctx, customerID, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	timeline := &CustomerTimeline{CustomerID: customerID, Entries: entries}
+	if len(entries) == limit {
+		oldest := entries[len(entries)-1].OccurredAt
+		timeline.NextBefore = &oldest
+	}
+
+	// The repository returns newest first; the timeline reads oldest first
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+
+	return timeline, nil
+}
+
+// Snooze sets the time until which campaigns skip the customer. Snoozing a
+// snoozed customer again replaces the time.
+func (s *customerService) Snooze(ctx context.Context, id int64, req *SnoozeCustomerRequest) (*models.Customer, error) {
+	if req.Until == nil {
+		return s.unsnooze(ctx, id)
+	}
+
+	now := s.now()
+	if !req.Until.After(now) || req.Until.Sub(now) > maxSnooze {
+		return nil, models.ErrInvalidInput("until must be a time in the next 366 days")
+	}
+	until := req.Until.UTC()
+
+	if err := s.customerRepo.SetSnoozedUntil(ctx, id, &until); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("customer snoozed",
+		slog.Int64("customer_id", id),
+		slog.Time("until", until),
+	)
+	s.recordEvent(ctx, id, models.CustomerEventSnoozed, map[string]interface{}{"until": until})
+
+	return s.customerRepo.GetByID(ctx, id)
+}
+
+// unsnooze clears the customer's snooze. A customer who is not snoozed is
+// returned unchanged.
+func (s *customerService) unsnooze(ctx context.Context, id int64) (*models.Customer, error) {
+	customer, err := s.customerRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if customer.SnoozedUntil == nil {
+		return customer, nil
+	}
+
+	if err := s.customerRepo.SetSnoozedUntil(ctx, id, nil); err != nil {
+		return nil, err
+	}
+	customer.SnoozedUntil = nil
+
+	s.logger.Info("customer unsnoozed", slog.Int64("customer_id", id))
+	s.recordEvent(ctx, id, models.CustomerEventUnsnoozed, nil)
+
+	return customer, nil
+}
+
+// recordEvent adds an entry to the customer's timeline. Failures are logged and
+// never fail the operation being recorded.
+func (s *customerService) recordEvent(ctx context.Context, customerID int64, eventType string, details map[string]interface{}) {
+	event := &models.CustomerEvent{CustomerID: customerID, Type: eventType}
+	if details != nil {
+		encoded, err := json.Marshal(details)
+		if err == nil {
+			event.Details = encoded
+		}
+	}
+
+	if err := s.eventRepo.Record(ctx, event); err != nil {
+		s.logger.Error("failed to record customer event",
+			slog.Int64("customer_id", customerID),
+			slog.String("type", eventType),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// changedCustomerFields lists the profile fields that differ between two versions of a customer
+func changedCustomerFields(before, after *models.Customer) []string {
+	changed := make([]string, 0)
+	if before.Phone != after.Phone {
+		changed = append(changed, "phone")
+	}
+	if before.FirstName != after.FirstName {
+		changed = append(changed, "first_name")
+	}
+	if before.LastName != after.LastName {
+		changed = append(changed, "last_name")
+	}
+	if before.Location != after.Location {
+		changed = append(changed, "location")
+	}
+	if before.PreferredProduct != after.PreferredProduct {
+		changed = append(changed, "preferred_product")
+	}
+	if !maps.Equal(before.Attributes, after.Attributes) {
+		changed = append(changed, "attributes")
+	}
+	return changed
+}
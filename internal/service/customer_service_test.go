@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestCustomerService_LookupByPhones(t *testing.T) {
+	customers := &customerStore{byID: map[int64]*models.Customer{
+		1: {ID: 1, Phone: "+254700000001"},
+		2: {ID: 2, Phone: "+254700000002"},
+		3: {ID: 3, Phone: "+254700000003"},
+	}}
+	svc := NewCustomerService(customers.repo(t), (&messageStore{}).repo(t), nil, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+	result, err := svc.LookupByPhones(context.Background(), &CustomerLookupRequest{
+		Phones: []string{"+254700000003", " +254700000001 ", "+254799999999", "+254700000003", "+254788888888"},
+	})
+	if err != nil {
+		t.Fatalf("LookupByPhones() error = %v", err)
+	}
+
+	if len(result.Matched) != 2 || result.Matched[0].ID != 1 || result.Matched[1].ID != 3 {
+		t.Errorf("Matched = %+v, want customers 1 and 3", result.Matched)
+	}
+	wantMisses := []string{"+254799999999", "+254788888888"}
+	if len(result.Misses) != len(wantMisses) {
+		t.Fatalf("Misses = %v, want %v", result.Misses, wantMisses)
+	}
+	for i := range wantMisses {
+		if result.Misses[i] != wantMisses[i] {
+			t.Errorf("Misses = %v, want %v", result.Misses, wantMisses)
+			break
+		}
+	}
+}
+
+func TestCustomerService_LookupByPhones_Invalid(t *testing.T) {
+	svc := NewCustomerService((&customerStore{}).repo(t), (&messageStore{}).repo(t), nil, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+	tooMany := make([]string, MaxLookupPhones+1)
+	for i := range tooMany {
+		tooMany[i] = "+2547"
+	}
+
+	tests := []struct {
+		name   string
+		phones []string
+	}{
+		{name: "no phones", phones: nil},
+		{name: "blank phone", phones: []string{"+254700000001", "  "}},
+		{name: "too many phones", phones: tooMany},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.LookupByPhones(context.Background(), &CustomerLookupRequest{Phones: tt.phones})
+			var appErr *models.AppError
+			if !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+				t.Errorf("LookupByPhones() error = %v, want INVALID_INPUT", err)
+			}
+		})
+	}
+}
+
+func TestCustomerService_Update_RecordsChangedFields(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	customerRepo := mocks.NewMockCustomerRepository(ctrl)
+	eventRepo := mocks.NewMockCustomerEventRepository(ctrl)
+
+	customerRepo.EXPECT().GetByID(gomock.Any(), int64(1)).
+		Return(&models.Customer{ID: 1, Phone: "+254700000001", FirstName: "Ann", Location: "Nairobi"}, nil)
+	customerRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+	eventRepo.EXPECT().Record(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, event *models.CustomerEvent) error {
+			if event.Type != models.CustomerEventProfileUpdated {
+				t.Errorf("event type = %s, want profile_updated", event.Type)
+			}
+			if got := string(event.Details); got != `{"fields":["first_name","location"]}` {
+				t.Errorf("event details = %s, want the changed fields", got)
+			}
+			return nil
+		})
+
+	svc := NewCustomerService(customerRepo, mocks.NewMockOutboundMessageRepository(ctrl), eventRepo, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+	_, err := svc.Update(context.Background(), &models.Customer{ID: 1, Phone: "+254700000001", FirstName: "Anne", Location: "Mombasa"})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+}
+
+func TestCustomerService_Update_PhoneTakenByAnotherCustomer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	customerRepo := mocks.NewMockCustomerRepository(ctrl)
+
+	customerRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Customer{ID: 1, Phone: "+254700000001"}, nil)
+	customerRepo.EXPECT().GetByPhone(gomock.Any(), "+254700000002").Return(&models.Customer{ID: 2, Phone: "+254700000002"}, nil)
+	// No Update expected
+
+	svc := NewCustomerService(customerRepo, mocks.NewMockOutboundMessageRepository(ctrl), nil, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+	_, err := svc.Update(context.Background(), &models.Customer{ID: 1, Phone: "+254700000002"})
+	if !errors.Is(err, models.ErrConflict) {
+		t.Errorf("Update() error = %v, want conflict", err)
+	}
+}
+
+func TestCustomerService_ExternalID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	customerRepo := mocks.NewMockCustomerRepository(ctrl)
+	svc := NewCustomerService(customerRepo, mocks.NewMockOutboundMessageRepository(ctrl), nil, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+	crmID := "crm-0042"
+	customerRepo.EXPECT().GetByExternalID(gomock.Any(), crmID).Return(&models.Customer{ID: 42, ExternalID: &crmID}, nil)
+
+	customer, err := svc.GetByExternalID(context.Background(), crmID)
+	if err != nil {
+		t.Fatalf("GetByExternalID() error = %v", err)
+	}
+	if customer.ID != 42 {
+		t.Errorf("customer ID = %d, want 42", customer.ID)
+	}
+
+	empty := ""
+	if _, err := svc.Create(context.Background(), &models.Customer{Phone: "+254700000001", ExternalID: &empty}); err == nil {
+		t.Error("Create() with empty external_id error = nil, want invalid input")
+	}
+}
+
+func TestCustomerService_Timeline(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	customerRepo := mocks.NewMockCustomerRepository(ctrl)
+	eventRepo := mocks.NewMockCustomerEventRepository(ctrl)
+
+	now := time.Now()
+	customerRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Customer{ID: 1}, nil)
+	// The repository hands back the newest entries first
+	eventRepo.EXPECT().Timeline(gomock.Any(), int64(1), gomock.Any(), 3).Return([]*models.TimelineEntry{
+		{OccurredAt: now.Add(-1 * time.Minute), Type: models.TimelineMessageSent},
+		{OccurredAt: now.Add(-2 * time.Minute), Type: models.TimelineMessageQueued},
+		{OccurredAt: now.Add(-3 * time.Minute), Type: models.CustomerEventCreated},
+	}, nil)
+
+	svc := NewCustomerService(customerRepo, mocks.NewMockOutboundMessageRepository(ctrl), eventRepo, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+	timeline, err := svc.Timeline(context.Background(), 1, nil, 3)
+	if err != nil {
+		t.Fatalf("Timeline() error = %v", err)
+	}
+
+	want := []string{models.CustomerEventCreated, models.TimelineMessageQueued, models.TimelineMessageSent}
+	for i, entry := range timeline.Entries {
+		if entry.Type != want[i] {
+			t.Fatalf("entry %d = %s, want chronological order %v", i, entry.Type, want)
+		}
+	}
+	// A full page points at the oldest entry for the next page
+	if timeline.NextBefore == nil || !timeline.NextBefore.Equal(now.Add(-3*time.Minute)) {
+		t.Errorf("NextBefore = %v, want the oldest entry's time", timeline.NextBefore)
+	}
+}
+
+func TestCustomerService_Snooze(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		until   time.Time
+		wantErr bool
+	}{
+		{name: "next week", until: now.Add(7 * 24 * time.Hour)},
+		{name: "in the past", until: now.Add(-time.Hour), wantErr: true},
+		{name: "more than a year ahead", until: now.Add(400 * 24 * time.Hour), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			customerRepo := mocks.NewMockCustomerRepository(ctrl)
+			eventRepo := mocks.NewMockCustomerEventRepository(ctrl)
+
+			if !tt.wantErr {
+				customerRepo.EXPECT().SetSnoozedUntil(gomock.Any(), int64(1), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, id int64, until *time.Time) error {
+						if until == nil || !until.Equal(tt.until) {
+							t.Errorf("snoozed until %v, want %v", until, tt.until)
+						}
+						return nil
+					})
+				eventRepo.EXPECT().Record(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, event *models.CustomerEvent) error {
+						if event.Type != models.CustomerEventSnoozed {
+							t.Errorf("event type = %s, want snoozed", event.Type)
+						}
+						return nil
+					})
+				customerRepo.EXPECT().GetByID(gomock.Any(), int64(1)).
+					Return(&models.Customer{ID: 1, SnoozedUntil: &tt.until}, nil)
+			}
+
+			svc := NewCustomerService(customerRepo, mocks.NewMockOutboundMessageRepository(ctrl), eventRepo, slog.New(slog.NewJSONHandler(os.Stdout, nil))).(*customerService)
+			svc.now = func() time.Time { return now }
+
+			until := tt.until
+			_, err := svc.Snooze(context.Background(), 1, &SnoozeCustomerRequest{Until: &until})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Snooze() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCustomerService_Snooze_NullUntilEndsSnooze(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	customerRepo := mocks.NewMockCustomerRepository(ctrl)
+	eventRepo := mocks.NewMockCustomerEventRepository(ctrl)
+
+	until := time.Now().Add(time.Hour)
+	customerRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Customer{ID: 1, SnoozedUntil: &until}, nil)
+	customerRepo.EXPECT().SetSnoozedUntil(gomock.Any(), int64(1), nil).Return(nil)
+	eventRepo.EXPECT().Record(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, event *models.CustomerEvent) error {
+			if event.Type != models.CustomerEventUnsnoozed {
+				t.Errorf("event type = %s, want unsnoozed", event.Type)
+			}
+			return nil
+		})
+
+	svc := NewCustomerService(customerRepo, mocks.NewMockOutboundMessageRepository(ctrl), eventRepo, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+	customer, err := svc.Snooze(context.Background(), 1, &SnoozeCustomerRequest{})
+	if err != nil {
+		t.Fatalf("Snooze() error = %v", err)
+	}
+	if customer.SnoozedUntil != nil {
+		t.Errorf("SnoozedUntil = %v, want the snooze ended", customer.SnoozedUntil)
+	}
+}
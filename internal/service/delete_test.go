@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestCampaignService_Delete(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       string
+		messageCount int64
+		force        bool
+		wantErrCode  string
+		wantDelete   string // repository method expected to delete the campaign
+	}{
+		{
+			name:       "draft without history",
+			status:     models.CampaignStatusDraft,
+			wantDelete: "Delete",
+		},
+		{
+			name:         "history without force is refused",
+			status:       models.CampaignStatusSent,
+			messageCount: 3,
+			wantErrCode:  "CONFLICT",
+		},
+		{
+			name:         "history with force deletes",
+			status:       models.CampaignStatusSent,
+			messageCount: 3,
+			force:        true,
+			wantDelete:   "DeleteWithMessages",
+		},
+		{
+			name:        "sending campaign is refused even with force",
+			status:      models.CampaignStatusSending,
+			force:       true,
+			wantErrCode: "CONFLICT",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+			messageRepo := mocks.NewMockOutboundMessageRepository(ctrl)
+
+			campaignRepo.EXPECT().GetByID(gomock.Any(), int64(1)).
+				Return(&models.Campaign{ID: 1, Status: tt.status}, nil)
+			if tt.status != models.CampaignStatusSending {
+				messageRepo.EXPECT().CountByCampaign(gomock.Any(), int64(1)).Return(tt.messageCount, nil)
+			}
+			switch tt.wantDelete {
+			case "Delete":
+				campaignRepo.EXPECT().Delete(gomock.Any(), int64(1)).Return(nil)
+			case "DeleteWithMessages":
+				campaignRepo.EXPECT().DeleteWithMessages(gomock.Any(), int64(1)).Return(nil)
+			}
+
+			svc := &campaignService{
+				campaignRepo: campaignRepo,
+				messageRepo:  messageRepo,
+				logger:       slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+			}
+
+			err := svc.Delete(context.Background(), 1, tt.force)
+
+			if tt.wantErrCode != "" {
+				var appErr *models.AppError
+				if !errors.As(err, &appErr) || appErr.Code != tt.wantErrCode {
+					t.Fatalf("Delete() error = %v, want AppError with %s code", err, tt.wantErrCode)
+				}
+			} else if err != nil {
+				t.Fatalf("Delete() error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestCustomerService_Delete(t *testing.T) {
+	tests := []struct {
+		name          string
+		messageCount  int64
+		anonymize     bool
+		wantErrCode   string
+		wantAnonymize bool
+	}{
+		{
+			name: "customer without history is deleted",
+		},
+		{
+			name:         "history without anonymize is refused",
+			messageCount: 2,
+			wantErrCode:  "CONFLICT",
+		},
+		{
+			name:          "history with anonymize scrubs personal data",
+			messageCount:  2,
+			anonymize:     true,
+			wantAnonymize: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			customerRepo := mocks.NewMockCustomerRepository(ctrl)
+			messageRepo := mocks.NewMockOutboundMessageRepository(ctrl)
+			eventRepo := mocks.NewMockCustomerEventRepository(ctrl)
+
+			customerRepo.EXPECT().GetByID(gomock.Any(), int64(1)).
+				Return(&models.Customer{ID: 1, FirstName: "Alice", LastName: "Mwangi", Phone: "+254712345001"}, nil)
+			messageRepo.EXPECT().CountByCustomer(gomock.Any(), int64(1)).Return(tt.messageCount, nil)
+			switch {
+			case tt.wantAnonymize:
+				customerRepo.EXPECT().Anonymize(gomock.Any(), int64(1)).Return(nil)
+				eventRepo.EXPECT().Record(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, event *models.CustomerEvent) error {
+						if event.Type != models.CustomerEventAnonymized {
+							t.Errorf("recorded event %s, want anonymized", event.Type)
+						}
+						return nil
+					})
+			case tt.wantErrCode == "":
+				customerRepo.EXPECT().Delete(gomock.Any(), int64(1)).Return(nil)
+			}
+
+			svc := NewCustomerService(customerRepo, messageRepo, eventRepo, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+			err := svc.Delete(context.Background(), 1, tt.anonymize)
+
+			if tt.wantErrCode != "" {
+				var appErr *models.AppError
+				if !errors.As(err, &appErr) || appErr.Code != tt.wantErrCode {
+					t.Fatalf("Delete() error = %v, want AppError with %s code", err, tt.wantErrCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Delete() error = %v, want nil", err)
+			}
+		})
+	}
+}
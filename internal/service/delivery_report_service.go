@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// DeliveryReportService applies provider delivery reports to sent messages
+type DeliveryReportService interface {
+	// Receive parses a provider's delivery report callback and updates the
+	// messages it reports on
+	Receive(ctx context.Context, provider string, body []byte) (*DeliveryReportResult, error)
+}
+
+// deliveryReportParser reads the final delivery reports out of a callback
+// body. Reports on messages still in flight are dropped.
+type deliveryReportParser func(body []byte) ([]models.DeliveryReport, error)
+
+// deliveryReportParsers are keyed by the provider names SENDER_PROVIDER and
+// WHATSAPP_PROVIDER take
+var deliveryReportParsers = map[string]deliveryReportParser{
+	"africastalking": parseAfricasTalkingReport,
+	"twilio":         parseTwilioReport,
+	"meta":           parseMetaReports,
+}
+
+type deliveryReportService struct {
+	messageRepo repository.OutboundMessageRepository
+	logger      *slog.Logger
+}
+
+// NewDeliveryReportService creates a new delivery report service
+func NewDeliveryReportService(messageRepo repository.OutboundMessageRepository, logger *slog.Logger) DeliveryReportService {
+	return &deliveryReportService{
+		messageRepo: messageRepo,
+		logger:      logger,
+	}
+}
+
+// Receive applies each report in the callback. Reports for provider message
+// IDs we never stored, such as messages sent before delivery reports were
+// tracked, are logged and counted as unmatched rather than failing the
+// callback, so the provider doesn't keep retrying them.
+func (s *deliveryReportService) Receive(ctx context.Context, provider string, body []byte) (*DeliveryReportResult, error) {
+	parse, ok := deliveryReportParsers[provider]
+	if !ok {
+		return nil, models.ErrInvalidInput(fmt.Sprintf("unknown provider %q (valid providers are: africastalking, twilio, meta)", provider))
+	}
+
+	reports, err := parse(body)
+	if err != nil {
+		return nil, models.ErrInvalidInput(fmt.Sprintf("invalid %s delivery report: %v", provider, err))
+	}
+
+	result := &DeliveryReportResult{}
+	for _, report := range reports {
+		applied, err := s.messageRepo.ApplyDeliveryReport(ctx, report)
+		if err != nil {
+			s.logger.Error("failed to apply delivery report",
+				slog.String("provider", provider),
+				slog.String("provider_message_id", report.ProviderMessageID),
+				slog.String("error", err.Error()),
+			)
+			return nil, fmt.Errorf("failed to apply delivery report: %w", err)
+		}
+
+		if !applied {
+			s.logger.Warn("delivery report for unknown message",
+				slog.String("provider", provider),
+				slog.String("provider_message_id", report.ProviderMessageID),
+				slog.String("status", report.Status),
+			)
+			result.Unmatched++
+			continue
+		}
+		result.Applied++
+	}
+
+	if result.Applied > 0 {
+		s.logger.Debug("delivery reports applied",
+			slog.String("provider", provider),
+			slog.Int("applied", result.Applied),
+			slog.Int("unmatched", result.Unmatched),
+		)
+	}
+
+	return result, nil
+}
+
+// parseAfricasTalkingReport reads an Africa's Talking delivery report, a form
+// with the message id, its status and, for failures, a failureReason
+func parseAfricasTalkingReport(body []byte) ([]models.DeliveryReport, error) {
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+
+	id := form.Get("id")
+	if id == "" {
+		return nil, fmt.Errorf("missing id")
+	}
+
+	var report models.DeliveryReport
+	switch status := form.Get("status"); status {
+	case "Success":
+		report = models.DeliveryReport{ProviderMessageID: id, Status: models.MessageStatusDelivered}
+	case "Failed", "Rejected", "AbsentSubscriber", "Expired":
+		reason := form.Get("failureReason")
+		report = undeliveredReport(id, status, reason, africasTalkingBounce(reason))
+	default:
+		// Sent, Submitted and Buffered are not final
+		return nil, nil
+	}
+
+	return []models.DeliveryReport{report}, nil
+}
+
+// parseTwilioReport reads a Twilio status callback, a form with the
+// MessageSid, its MessageStatus and, for failures, an ErrorCode
+func parseTwilioReport(body []byte) ([]models.DeliveryReport, error) {
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+
+	sid := form.Get("MessageSid")
+	if sid == "" {
+		return nil, fmt.Errorf("missing MessageSid")
+	}
+
+	var report models.DeliveryReport
+	switch status := form.Get("MessageStatus"); status {
+	case "delivered", "read":
+		report = models.DeliveryReport{ProviderMessageID: sid, Status: models.MessageStatusDelivered}
+	case "undelivered", "failed":
+		reason := ""
+		code := form.Get("ErrorCode")
+		if code != "" {
+			reason = "error code " + code
+		}
+		report = undeliveredReport(sid, status, reason, twilioBounce(code))
+	default:
+		// queued, sending and sent are not final
+		return nil, nil
+	}
+
+	return []models.DeliveryReport{report}, nil
+}
+
+// metaWebhook is the part of a WhatsApp Cloud API webhook we read. One
+// callback can carry statuses for several messages.
+type metaWebhook struct {
+	Entry []struct {
+		Changes []struct {
+			Value struct {
+				Statuses []struct {
+					ID     string `json:"id"`
+					Status string `json:"status"`
+					Errors []struct {
+						Code  int    `json:"code"`
+						Title string `json:"title"`
+					} `json:"errors"`
+				} `json:"statuses"`
+			} `json:"value"`
+		} `json:"changes"`
+	} `json:"entry"`
+}
+
+// parseMetaReports reads the message statuses in a WhatsApp Cloud API
+// webhook. Webhooks for incoming messages carry no statuses.
+func parseMetaReports(body []byte) ([]models.DeliveryReport, error) {
+	var webhook metaWebhook
+	if err := json.Unmarshal(body, &webhook); err != nil {
+		return nil, err
+	}
+
+	var reports []models.DeliveryReport
+	for _, entry := range webhook.Entry {
+		for _, change := range entry.Changes {
+			for _, status := range change.Value.Statuses {
+				if status.ID == "" {
+					continue
+				}
+				switch status.Status {
+				case "delivered", "read":
+					reports = append(reports, models.DeliveryReport{ProviderMessageID: status.ID, Status: models.MessageStatusDelivered})
+				case "failed":
+					reason, bounce := "", models.BounceSoft
+					if len(status.Errors) > 0 {
+						reason = fmt.Sprintf("%s (code %d)", status.Errors[0].Title, status.Errors[0].Code)
+						bounce = metaBounce(status.Errors[0].Code)
+					}
+					reports = append(reports, undeliveredReport(status.ID, status.Status, reason, bounce))
+				}
+			}
+		}
+	}
+
+	return reports, nil
+}
+
+// undeliveredReport builds an undelivered report, falling back to the
+// provider's status when it gives no reason
+func undeliveredReport(providerMessageID, status, reason, bounce string) models.DeliveryReport {
+	if reason == "" {
+		reason = status
+	}
+	return models.DeliveryReport{
+		ProviderMessageID: providerMessageID,
+		Status:            models.MessageStatusUndelivered,
+		Reason:            &reason,
+		Bounce:            bounce,
+	}
+}
+
+// Failures that mean the number cannot receive messages at all. Any other
+// failure is a soft bounce: a number is only suppressed when the provider is
+// clear that retrying is pointless.
+var (
+	// africasTalkingHardBounces are failureReason values, lowercased as
+	// Africa's Talking is not consistent about their case
+	africasTalkingHardBounces = map[string]bool{
+		"userinblacklist":      true,
+		"userdoesnotexist":     true,
+		"notnetworksubscriber": true,
+		"useraccountsuspended": true,
+	}
+	// twilioHardBounces are ErrorCode values
+	twilioHardBounces = map[string]bool{
+		"21211": true, // invalid 'To' number
+		"21610": true, // recipient replied STOP
+		"21614": true, // not a mobile number
+		"30004": true, // message blocked
+		"30005": true, // unknown destination handset
+		"30006": true, // landline or unreachable carrier
+	}
+	// metaHardBounces are error codes
+	metaHardBounces = map[int]bool{
+		131026: true, // the number is not on WhatsApp or cannot receive messages
+	}
+)
+
+// africasTalkingBounce classifies an Africa's Talking failureReason
+func africasTalkingBounce(reason string) string {
+	if africasTalkingHardBounces[strings.ToLower(reason)] {
+		return models.BounceHard
+	}
+	return models.BounceSoft
+}
+
+// twilioBounce classifies a Twilio ErrorCode
+func twilioBounce(code string) string {
+	if twilioHardBounces[code] {
+		return models.BounceHard
+	}
+	return models.BounceSoft
+}
+
+// metaBounce classifies a WhatsApp Cloud API error code
+func metaBounce(code int) string {
+	if metaHardBounces[code] {
+		return models.BounceHard
+	}
+	return models.BounceSoft
+}
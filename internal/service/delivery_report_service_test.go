@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestDeliveryReportParsers(t *testing.T) {
+	tests := []struct {
+		name       string
+		provider   string
+		body       string
+		wantStatus []string
+		wantReason string
+		wantBounce string
+	}{
+		{
+			name:       "africastalking success",
+			provider:   "africastalking",
+			body:       "id=ATXid_1&status=Success&phoneNumber=%2B254700000001&networkCode=63902",
+			wantStatus: []string{models.MessageStatusDelivered},
+		},
+		{
+			name:       "africastalking failure",
+			provider:   "africastalking",
+			body:       "id=ATXid_1&status=Failed&failureReason=UserInBlacklist",
+			wantStatus: []string{models.MessageStatusUndelivered},
+			wantReason: "UserInBlacklist",
+			wantBounce: models.BounceHard,
+		},
+		{
+			name:       "africastalking absent subscriber",
+			provider:   "africastalking",
+			body:       "id=ATXid_1&status=AbsentSubscriber",
+			wantStatus: []string{models.MessageStatusUndelivered},
+			wantReason: "AbsentSubscriber",
+			wantBounce: models.BounceSoft,
+		},
+		{
+			name:     "africastalking buffered",
+			provider: "africastalking",
+			body:     "id=ATXid_1&status=Buffered",
+		},
+		{
+			name:       "twilio delivered",
+			provider:   "twilio",
+			body:       "MessageSid=SM123&MessageStatus=delivered&AccountSid=AC123",
+			wantStatus: []string{models.MessageStatusDelivered},
+		},
+		{
+			name:       "twilio undelivered",
+			provider:   "twilio",
+			body:       "MessageSid=SM123&MessageStatus=undelivered&ErrorCode=30003",
+			wantStatus: []string{models.MessageStatusUndelivered},
+			wantReason: "error code 30003",
+			wantBounce: models.BounceSoft,
+		},
+		{
+			name:       "twilio unknown destination",
+			provider:   "twilio",
+			body:       "MessageSid=SM123&MessageStatus=undelivered&ErrorCode=30005",
+			wantStatus: []string{models.MessageStatusUndelivered},
+			wantReason: "error code 30005",
+			wantBounce: models.BounceHard,
+		},
+		{
+			name:     "twilio sent",
+			provider: "twilio",
+			body:     "MessageSid=SM123&MessageStatus=sent",
+		},
+		{
+			name:     "meta statuses",
+			provider: "meta",
+			body: `{"object":"whatsapp_business_account","entry":[{"changes":[{"field":"messages","value":{"statuses":[
+				{"id":"wamid.1","status":"sent"},
+				{"id":"wamid.2","status":"read"},
+				{"id":"wamid.3","status":"failed","errors":[{"code":131026,"title":"Message undeliverable"}]}]}}]}]}`,
+			wantStatus: []string{models.MessageStatusDelivered, models.MessageStatusUndelivered},
+			wantReason: "Message undeliverable (code 131026)",
+			wantBounce: models.BounceHard,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reports, err := deliveryReportParsers[tt.provider]([]byte(tt.body))
+			if err != nil {
+				t.Fatalf("parse error = %v", err)
+			}
+			if len(reports) != len(tt.wantStatus) {
+				t.Fatalf("got %d reports, want %d", len(reports), len(tt.wantStatus))
+			}
+			for i, report := range reports {
+				if report.Status != tt.wantStatus[i] {
+					t.Errorf("report %d status = %s, want %s", i, report.Status, tt.wantStatus[i])
+				}
+			}
+			if tt.wantReason != "" {
+				last := reports[len(reports)-1]
+				if last.Reason == nil || *last.Reason != tt.wantReason {
+					t.Errorf("reason = %v, want %q", last.Reason, tt.wantReason)
+				}
+				if last.Bounce != tt.wantBounce {
+					t.Errorf("bounce = %q, want %q", last.Bounce, tt.wantBounce)
+				}
+			}
+		})
+	}
+}
+
+func TestDeliveryReportService_Receive(t *testing.T) {
+	sentID, failedID := "SM1", "SM2"
+	messages := &messageStore{
+		all: []*models.OutboundMessage{
+			{ID: 1, Status: models.MessageStatusSent, ProviderMessageID: &sentID},
+			{ID: 2, Status: models.MessageStatusFailed, ProviderMessageID: &failedID},
+		},
+	}
+	svc := NewDeliveryReportService(messages.repo(t), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	result, err := svc.Receive(context.Background(), "twilio", []byte("MessageSid=SM1&MessageStatus=delivered"))
+	if err != nil {
+		t.Fatalf("Receive() error = %v", err)
+	}
+	if result.Applied != 1 || messages.all[0].Status != models.MessageStatusDelivered {
+		t.Errorf("Receive() = %+v, message status %s, want the sent message delivered", result, messages.all[0].Status)
+	}
+
+	// Reports for unknown or unsent messages are acknowledged without changes
+	for _, body := range []string{"MessageSid=SM9&MessageStatus=delivered", "MessageSid=SM2&MessageStatus=delivered"} {
+		result, err = svc.Receive(context.Background(), "twilio", []byte(body))
+		if err != nil {
+			t.Fatalf("Receive(%q) error = %v", body, err)
+		}
+		if result.Unmatched != 1 {
+			t.Errorf("Receive(%q) = %+v, want one unmatched report", body, result)
+		}
+	}
+	if messages.all[1].Status != models.MessageStatusFailed {
+		t.Errorf("failed message status = %s, want failed", messages.all[1].Status)
+	}
+
+	invalid := []struct{ provider, body string }{
+		{"carrier-pigeon", "id=1&status=Success"},
+		{"twilio", "MessageStatus=delivered"},
+		{"meta", "not json"},
+	}
+	for _, tt := range invalid {
+		var appErr *models.AppError
+		if _, err := svc.Receive(context.Background(), tt.provider, []byte(tt.body)); !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+			t.Errorf("Receive(%s, %q) error = %v, want INVALID_INPUT", tt.provider, tt.body, err)
+		}
+	}
+}
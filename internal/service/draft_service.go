@@ -0,0 +1,204 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// Revision listing limits
+const (
+	defaultRevisionLimit = 50
+	maxRevisionLimit     = 200
+)
+
+// DraftService autosaves campaign drafts and keeps their revision history
+type DraftService interface {
+	SaveDraft(ctx context.Context, campaignID int64, draft *models.CampaignDraft) (*models.CampaignRevision, error)
+	ListRevisions(ctx context.Context, campaignID int64, limit int) ([]*models.CampaignRevision, error)
+	Revert(ctx context.Context, campaignID int64, revision int) (*models.CampaignRevision, error)
+}
+
+type draftService struct {
+	campaignRepo repository.CampaignRepository
+	revisionRepo repository.CampaignRevisionRepository
+	templateSvc  TemplateService
+	logger       *slog.Logger
+}
+
+// NewDraftService creates a new draft service
+func NewDraftService(
+	campaignRepo repository.CampaignRepository,
+	revisionRepo repository.CampaignRevisionRepository,
+	templateSvc TemplateService,
+	logger *slog.Logger,
+) DraftService {
+	return &draftService{
+		campaignRepo: campaignRepo,
+		revisionRepo: revisionRepo,
+		templateSvc:  templateSvc,
+		logger:       logger,
+	}
+}
+
+// SaveDraft records the editor's current draft as a new revision. Drafts are
+// always kept, even while incomplete; a draft that passes validation is also
+// copied onto the campaign. Saving a draft identical to the latest revision
+// returns that revision without creating a new one.
+func (s *draftService) SaveDraft(ctx context.Context, campaignID int64, draft *models.CampaignDraft) (*models.CampaignRevision, error) {
+	campaign, err := s.editableCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := s.revisionRepo.Latest(ctx, campaignID)
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		return nil, err
+	}
+	if latest != nil && sameDraft(&latest.Draft, draft) {
+		return latest, nil
+	}
+
+	return s.save(ctx, campaign, &models.CampaignRevision{
+		CampaignID: campaignID,
+		Source:     models.RevisionSourceAutosave,
+		Draft:      *draft,
+	})
+}
+
+// ListRevisions returns the most recent revisions of a campaign, newest first
+func (s *draftService) ListRevisions(ctx context.Context, campaignID int64, limit int) ([]*models.CampaignRevision, error) {
+	if _, err := s.campaignRepo.GetByID(ctx, campaignID); err != nil {
+		return nil, err
+	}
+
+	if limit < 1 {
+		limit = defaultRevisionLimit
+	}
+	if limit > maxRevisionLimit {
+		limit = maxRevisionLimit
+	}
+
+	return s.revisionRepo.List(ctx, campaignID, limit)
+}
+
+// Revert restores an earlier revision by saving a copy of it as the newest
+// revision, so the history itself is never rewritten
+func (s *draftService) Revert(ctx context.Context, campaignID int64, number int) (*models.CampaignRevision, error) {
+	campaign, err := s.editableCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := s.revisionRepo.Get(ctx, campaignID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.save(ctx, campaign, &models.CampaignRevision{
+		CampaignID:   campaignID,
+		Source:       models.RevisionSourceRevert,
+		RevertedFrom: &target.Revision,
+		Draft:        target.Draft,
+	})
+}
+
+// editableCampaign loads a campaign and checks that it has not been sent yet
+func (s *draftService) editableCampaign(ctx context.Context, campaignID int64) (*models.Campaign, error) {
+	campaign, err := s.campaignRepo.GetByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !campaign.CanBeSent() {
+		return nil, models.ErrConflictWithMsg("only draft and scheduled campaigns can be edited")
+	}
+
+	return campaign, nil
+}
+
+// save validates the revision's draft and stores it, applying it to the
+// campaign when it is valid
+func (s *draftService) save(ctx context.Context, campaign *models.Campaign, revision *models.CampaignRevision) (*models.CampaignRevision, error) {
+	updated := applyDraft(campaign, &revision.Draft)
+
+	var apply *models.Campaign
+	if err := s.validateDraft(ctx, updated, &revision.Draft); err != nil {
+		// Only invalid drafts are recorded; failing to check one is not the draft's fault
+		var appErr *models.AppError
+		if !errors.As(err, &appErr) {
+			return nil, err
+		}
+		message := appErr.Message
+		revision.ValidationError = &message
+	} else {
+		revision.Applied = true
+		apply = updated
+	}
+
+	if err := s.revisionRepo.Save(ctx, revision, apply); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("campaign draft saved",
+		slog.Int64("campaign_id", campaign.ID),
+		slog.Int("revision", revision.Revision),
+		slog.String("source", revision.Source),
+		slog.Bool("applied", revision.Applied),
+	)
+
+	return revision, nil
+}
+
+// validateDraft applies the same rules as campaign creation and sending
+func (s *draftService) validateDraft(ctx context.Context, campaign *models.Campaign, draft *models.CampaignDraft) error {
+	if err := campaign.Validate(); err != nil {
+		return err
+	}
+	expanded, err := s.templateSvc.ExpandPartials(ctx, campaign.BaseTemplate)
+	if err != nil {
+		return err
+	}
+	if err := s.templateSvc.ValidateTemplate(ctx, expanded); err != nil {
+		return err
+	}
+	if draft.Audience != nil {
+		if err := audienceRequest(draft.Audience).Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyDraft returns a copy of campaign with the draft's fields. The status
+// follows the draft's schedule, as it does when a campaign is created.
+func applyDraft(campaign *models.Campaign, draft *models.CampaignDraft) *models.Campaign {
+	updated := *campaign
+	updated.Name = draft.Name
+	updated.BaseTemplate = draft.BaseTemplate
+	updated.SenderID = draft.SenderID
+	updated.DeliveryWindows = draft.DeliveryWindows
+	updated.ScheduledAt = draft.ScheduledAt
+	if draft.Audience != nil {
+		updated.Audience = draft.Audience
+	}
+
+	updated.Status = models.CampaignStatusDraft
+	if draft.ScheduledAt != nil {
+		updated.Status = models.CampaignStatusScheduled
+	}
+
+	return &updated
+}
+
+// sameDraft reports whether two drafts serialize identically
+func sameDraft(a, b *models.CampaignDraft) bool {
+	aj, _ := json.Marshal(a)
+	bj, _ := json.Marshal(b)
+	return bytes.Equal(aj, bj)
+}
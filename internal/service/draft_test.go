@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func newTestDraftService(ctrl *gomock.Controller, campaign *models.Campaign) (*draftService, *mocks.MockCampaignRevisionRepository) {
+	campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+	campaignRepo.EXPECT().GetByID(gomock.Any(), campaign.ID).Return(campaign, nil).AnyTimes()
+	revisionRepo := mocks.NewMockCampaignRevisionRepository(ctrl)
+
+	svc := NewDraftService(campaignRepo, revisionRepo, NewTemplateService(nil, nil), slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+	return svc.(*draftService), revisionRepo
+}
+
+func TestDraftService_SaveDraft(t *testing.T) {
+	campaign := &models.Campaign{ID: 1, Name: "Launch", Channel: models.ChannelSMS, Status: models.CampaignStatusDraft, BaseTemplate: "Hi {first_name}"}
+
+	tests := []struct {
+		name        string
+		draft       models.CampaignDraft
+		wantApplied bool
+		wantError   string
+	}{
+		{
+			name:        "valid draft is applied",
+			draft:       models.CampaignDraft{Name: "Launch v2", BaseTemplate: "Hello {first_name}", Audience: &models.CampaignAudience{Target: "all"}},
+			wantApplied: true,
+		},
+		{
+			name:      "unknown placeholder is kept but not applied",
+			draft:     models.CampaignDraft{Name: "Launch v2", BaseTemplate: "Hello {first_nam}"},
+			wantError: "invalid placeholders: first_nam. Valid placeholders are: first_name, last_name, location, preferred_product, recommended_product, phone",
+		},
+		{
+			name:      "empty audience is kept but not applied",
+			draft:     models.CampaignDraft{Name: "Launch v2", BaseTemplate: "Hello", Audience: &models.CampaignAudience{}},
+			wantError: "customer_ids is required and cannot be empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			svc, revisionRepo := newTestDraftService(ctrl, campaign)
+
+			revisionRepo.EXPECT().Latest(gomock.Any(), int64(1)).Return(nil, models.ErrNotFoundWithMsg("campaign 1 has no revisions"))
+			revisionRepo.EXPECT().Save(gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, revision *models.CampaignRevision, apply *models.Campaign) error {
+					if (apply != nil) != tt.wantApplied {
+						t.Errorf("campaign applied = %v, want %v", apply != nil, tt.wantApplied)
+					}
+					if apply != nil && apply.BaseTemplate != tt.draft.BaseTemplate {
+						t.Errorf("applied template = %q, want %q", apply.BaseTemplate, tt.draft.BaseTemplate)
+					}
+					revision.Revision = 1
+					return nil
+				})
+
+			revision, err := svc.SaveDraft(context.Background(), 1, &tt.draft)
+			if err != nil {
+				t.Fatalf("SaveDraft() error = %v", err)
+			}
+
+			if revision.Applied != tt.wantApplied {
+				t.Errorf("Applied = %v, want %v", revision.Applied, tt.wantApplied)
+			}
+			if tt.wantError != "" && (revision.ValidationError == nil || *revision.ValidationError != tt.wantError) {
+				t.Errorf("ValidationError = %v, want %q", revision.ValidationError, tt.wantError)
+			}
+			if revision.Source != models.RevisionSourceAutosave {
+				t.Errorf("Source = %s, want autosave", revision.Source)
+			}
+		})
+	}
+}
+
+func TestDraftService_SaveDraft_UnchangedDraftIsNotDuplicated(t *testing.T) {
+	campaign := &models.Campaign{ID: 1, Name: "Launch", Channel: models.ChannelSMS, Status: models.CampaignStatusDraft, BaseTemplate: "Hi"}
+	ctrl := gomock.NewController(t)
+	svc, revisionRepo := newTestDraftService(ctrl, campaign)
+
+	draft := models.CampaignDraft{Name: "Launch", BaseTemplate: "Hi {first_name}"}
+	latest := &models.CampaignRevision{CampaignID: 1, Revision: 4, Draft: draft, Applied: true}
+	revisionRepo.EXPECT().Latest(gomock.Any(), int64(1)).Return(latest, nil)
+	// No Save expected
+
+	revision, err := svc.SaveDraft(context.Background(), 1, &draft)
+	if err != nil {
+		t.Fatalf("SaveDraft() error = %v", err)
+	}
+	if revision.Revision != 4 {
+		t.Errorf("Revision = %d, want the latest revision 4", revision.Revision)
+	}
+}
+
+func TestDraftService_SaveDraft_SentCampaign(t *testing.T) {
+	campaign := &models.Campaign{ID: 1, Name: "Launch", Channel: models.ChannelSMS, Status: models.CampaignStatusSending, BaseTemplate: "Hi"}
+	ctrl := gomock.NewController(t)
+	svc, _ := newTestDraftService(ctrl, campaign)
+
+	_, err := svc.SaveDraft(context.Background(), 1, &models.CampaignDraft{Name: "Launch", BaseTemplate: "Hello"})
+	if !errors.Is(err, models.ErrConflict) {
+		t.Errorf("SaveDraft() error = %v, want conflict", err)
+	}
+}
+
+func TestDraftService_Revert(t *testing.T) {
+	campaign := &models.Campaign{ID: 1, Name: "Launch v3", Channel: models.ChannelSMS, Status: models.CampaignStatusDraft, BaseTemplate: "Yo"}
+	ctrl := gomock.NewController(t)
+	svc, revisionRepo := newTestDraftService(ctrl, campaign)
+
+	old := &models.CampaignRevision{CampaignID: 1, Revision: 2, Draft: models.CampaignDraft{Name: "Launch", BaseTemplate: "Hi {first_name}"}}
+	revisionRepo.EXPECT().Get(gomock.Any(), int64(1), 2).Return(old, nil)
+	revisionRepo.EXPECT().Save(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, revision *models.CampaignRevision, apply *models.Campaign) error {
+			if apply == nil || apply.Name != "Launch" || apply.BaseTemplate != "Hi {first_name}" {
+				t.Errorf("applied campaign = %+v, want revision 2's fields", apply)
+			}
+			revision.Revision = 5
+			return nil
+		})
+
+	revision, err := svc.Revert(context.Background(), 1, 2)
+	if err != nil {
+		t.Fatalf("Revert() error = %v", err)
+	}
+	if revision.Source != models.RevisionSourceRevert || revision.RevertedFrom == nil || *revision.RevertedFrom != 2 {
+		t.Errorf("revision = %+v, want a revert of revision 2", revision)
+	}
+	if revision.Revision != 5 {
+		t.Errorf("Revision = %d, want 5", revision.Revision)
+	}
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
@@ -8,10 +9,44 @@ import (
 
 // CreateCampaignRequest represents a request to create a campaign
 type CreateCampaignRequest struct {
-	Name         string     `json:"name"`
-	Channel      string     `json:"channel"`
-	BaseTemplate string     `json:"base_template"`
-	ScheduledAt  *time.Time `json:"scheduled_at,omitempty"`
+	Name            string                 `json:"name"`
+	Channel         string                 `json:"channel"`
+	BaseTemplate    string                 `json:"base_template"`
+	SenderID        *string                `json:"sender_id,omitempty"`
+	DeliveryWindows models.DeliveryWindows `json:"delivery_windows,omitempty"`
+	// Timezone is the IANA timezone the delivery windows are in (default UTC)
+	Timezone    string     `json:"timezone,omitempty"`
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
+	Labels      []string   `json:"labels,omitempty"`
+	ExternalID  *string    `json:"external_id,omitempty"`
+	// Audience is bound to the campaign and receives sends that name no recipients
+	Audience *models.CampaignAudience `json:"audience,omitempty"`
+	// MaxCost caps the campaign's spend; sending pauses once it is reached
+	MaxCost *float64 `json:"max_cost,omitempty"`
+	// MaxInFlight limits how many of the campaign's messages are sent at once
+	MaxInFlight *int `json:"max_in_flight,omitempty"`
+	// PrebuildMinutes builds a scheduled campaign's messages that many minutes
+	// before scheduled_at, so that sending at scheduled_at only queues them
+	PrebuildMinutes *int `json:"prebuild_minutes,omitempty"`
+	// Environment is live (the default) or test for QA campaigns
+	Environment string `json:"environment,omitempty"`
+	// Locale sets the number separators used by template formatters (default en)
+	Locale string `json:"locale,omitempty"`
+	// SubstituteUnicode replaces smart quotes, dashes and similar characters
+	// in an SMS template with GSM-7 equivalents, keeping segments at 160 characters
+	SubstituteUnicode bool `json:"substitute_unicode,omitempty"`
+	// TemplateID creates the campaign from a message template instead of an
+	// inline base_template, at TemplateVersion or the template's latest version
+	TemplateID      *int64 `json:"template_id,omitempty"`
+	TemplateVersion *int   `json:"template_version,omitempty"`
+	// CatalogTemplate creates the campaign from the starter template with this
+	// slug, whose name and channel fill in those the request leaves out
+	CatalogTemplate string `json:"catalog_template,omitempty"`
+	// ValidateNumbers has workers look each number up before sending to it
+	// and skip unreachable ones
+	ValidateNumbers bool `json:"validate_numbers,omitempty"`
+	// UTM is appended to every link in the campaign's messages
+	UTM *models.CampaignUTM `json:"utm,omitempty"`
 }
 
 // Validate performs validation on the create campaign request
@@ -25,23 +60,307 @@ func (r *CreateCampaignRequest) Validate() error {
 	if !models.IsValidChannel(r.Channel) {
 		return models.ErrInvalidInput("invalid channel (must be 'sms' or 'whatsapp')")
 	}
-	if r.BaseTemplate == "" {
-		return models.ErrInvalidInput("base_template is required")
+	if r.BaseTemplate == "" && r.TemplateID == nil {
+		return models.ErrInvalidInput("base_template, template_id or catalog_template is required")
+	}
+	if r.BaseTemplate != "" && r.TemplateID != nil {
+		return models.ErrInvalidInput("base_template and template_id cannot both be set")
+	}
+	if r.TemplateVersion != nil && r.TemplateID == nil {
+		return models.ErrInvalidInput("template_version requires template_id")
+	}
+	if r.SenderID != nil && (*r.SenderID == "" || len(*r.SenderID) > 32) {
+		return models.ErrInvalidInput("sender_id must be between 1 and 32 characters")
+	}
+	if err := r.DeliveryWindows.Validate(); err != nil {
+		return err
+	}
+	if r.Timezone != "" {
+		if err := models.ValidateTimezone(r.Timezone); err != nil {
+			return err
+		}
+	}
+	if err := models.ValidateExternalID(r.ExternalID); err != nil {
+		return err
+	}
+	if r.Audience != nil {
+		if err := audienceRequest(r.Audience).Validate(); err != nil {
+			return err
+		}
+	}
+	if r.Environment != "" && !models.IsValidEnvironment(r.Environment) {
+		return models.ErrInvalidInput("invalid environment (must be 'live' or 'test')")
+	}
+	if r.Locale != "" && !isValidLocale(r.Locale) {
+		return models.ErrInvalidInput(fmt.Sprintf("invalid locale: %s (must be one of %s)", r.Locale, validLocales))
+	}
+	if err := validateMaxInFlight(r.MaxInFlight); err != nil {
+		return err
+	}
+	if r.UTM != nil {
+		if err := r.UTM.Validate(); err != nil {
+			return err
+		}
+	}
+	if r.PrebuildMinutes != nil {
+		if r.ScheduledAt == nil || r.Audience == nil {
+			return models.ErrInvalidInput("prebuild_minutes requires scheduled_at and a bound audience")
+		}
+		if *r.PrebuildMinutes <= 0 || *r.PrebuildMinutes > maxPrebuildMinutes {
+			return models.ErrInvalidInput(fmt.Sprintf("prebuild_minutes must be between 1 and %d", maxPrebuildMinutes))
+		}
+	}
+	return validateMaxCost(r.MaxCost)
+}
+
+// validateMaxCost checks that a cost cap, when set, is positive
+func validateMaxCost(maxCost *float64) error {
+	if maxCost != nil && *maxCost <= 0 {
+		return models.ErrInvalidInput("max_cost must be greater than 0")
+	}
+	return nil
+}
+
+// SetMaxCostRequest represents a request to change a campaign's cost cap.
+// A null max_cost removes the cap.
+type SetMaxCostRequest struct {
+	MaxCost *float64 `json:"max_cost"`
+}
+
+// maxPrebuildMinutes bounds how early a scheduled campaign may be built; the
+// longer messages wait, the staler the customer data they were rendered from
+const maxPrebuildMinutes = 1440
+
+// maxInFlightLimit bounds a campaign's concurrency limit; a larger limit would
+// never be reached by the worker pool
+const maxInFlightLimit = 10000
+
+// validateMaxInFlight checks that a concurrency limit, when set, is positive and in range
+func validateMaxInFlight(maxInFlight *int) error {
+	if maxInFlight != nil && (*maxInFlight <= 0 || *maxInFlight > maxInFlightLimit) {
+		return models.ErrInvalidInput(fmt.Sprintf("max_in_flight must be between 1 and %d", maxInFlightLimit))
+	}
+	return nil
+}
+
+// SetMaxInFlightRequest represents a request to change how many of a
+// campaign's messages may be sent at once. A null max_in_flight removes the limit.
+type SetMaxInFlightRequest struct {
+	MaxInFlight *int `json:"max_in_flight"`
+}
+
+// SetValidateNumbersRequest represents a request to turn number lookups
+// before sending on or off for a campaign
+type SetValidateNumbersRequest struct {
+	ValidateNumbers *bool `json:"validate_numbers"`
+}
+
+// SetUTMRequest represents a request to change the UTM parameters appended to
+// a campaign's links. A null utm stops tagging them.
+type SetUTMRequest struct {
+	UTM *models.CampaignUTM `json:"utm"`
+}
+
+// CampaignDefinitionVersion is the format version written by campaign exports
+const CampaignDefinitionVersion = 1
+
+// CampaignDefinition is a portable description of a campaign. It holds no IDs,
+// status or delivery history, so it can be imported into another environment.
+type CampaignDefinition struct {
+	Version  int              `json:"version"`
+	Name     string           `json:"name"`
+	Channel  string           `json:"channel"`
+	Template string           `json:"template"`
+	SenderID *string          `json:"sender_id,omitempty"`
+	Schedule CampaignSchedule `json:"schedule"`
+	// Variables are the placeholders the template uses. On import they must
+	// match the template, which catches definitions edited by hand.
+	Variables []string            `json:"variables"`
+	Labels    []string            `json:"labels"`
+	Locale    string              `json:"locale,omitempty"`
+	UTM       *models.CampaignUTM `json:"utm,omitempty"`
+}
+
+// CampaignSchedule is when a campaign definition may be sent
+type CampaignSchedule struct {
+	ScheduledAt     *time.Time             `json:"scheduled_at,omitempty"`
+	DeliveryWindows models.DeliveryWindows `json:"delivery_windows,omitempty"`
+	Timezone        string                 `json:"timezone,omitempty"`
+}
+
+// Validate performs validation on the campaign definition
+func (d *CampaignDefinition) Validate() error {
+	if d.Version == 0 {
+		return models.ErrInvalidInput("version is required")
+	}
+	if d.Version != CampaignDefinitionVersion {
+		return models.ErrInvalidInput(fmt.Sprintf("unsupported definition version %d (supported: %d)", d.Version, CampaignDefinitionVersion))
+	}
+	if d.Template == "" {
+		return models.ErrInvalidInput("template is required")
+	}
+	if d.Schedule.ScheduledAt != nil && !d.Schedule.ScheduledAt.After(time.Now()) {
+		return models.ErrInvalidInput("schedule.scheduled_at must be in the future")
 	}
 	return nil
 }
 
-// SendCampaignRequest represents a request to send a campaign
+// createRequest converts the definition to a campaign create request
+func (d *CampaignDefinition) createRequest() *CreateCampaignRequest {
+	return &CreateCampaignRequest{
+		Name:            d.Name,
+		Channel:         d.Channel,
+		BaseTemplate:    d.Template,
+		SenderID:        d.SenderID,
+		DeliveryWindows: d.Schedule.DeliveryWindows,
+		Timezone:        d.Schedule.Timezone,
+		ScheduledAt:     d.Schedule.ScheduledAt,
+		Labels:          d.Labels,
+		Locale:          d.Locale,
+		UTM:             d.UTM,
+	}
+}
+
+// Provisioning outcomes
+const (
+	ProvisionCreated   = "created"
+	ProvisionUpdated   = "updated"
+	ProvisionUnchanged = "unchanged"
+)
+
+// ProvisionResult is the campaign held under an external key after provisioning
+type ProvisionResult struct {
+	Outcome  string           `json:"outcome"`
+	Campaign *models.Campaign `json:"campaign"`
+}
+
+// SendTargetAll sends a campaign to every customer
+const SendTargetAll = "all"
+
+// SendCampaignRequest represents a request to send a campaign. A request
+// without customer_ids or target goes to the campaign's bound audience.
 type SendCampaignRequest struct {
 	CustomerIDs []int64 `json:"customer_ids"`
+	Target      string  `json:"target,omitempty"`
+	// SegmentID sends to the customers matching a saved segment
+	SegmentID *int64 `json:"segment_id,omitempty"`
+	// Filter sends to the customers matching an ad hoc filter, without
+	// saving it as a segment
+	Filter *models.SegmentFilter `json:"filter,omitempty"`
+	// Exclude removes customers from the audience, whether it is named here
+	// or bound to the campaign
+	Exclude *AudienceExclusion `json:"exclude,omitempty"`
+	// ConfirmRecipientCount must equal the audience size when it is above the
+	// confirmation threshold
+	ConfirmRecipientCount *int64 `json:"confirm_recipient_count,omitempty"`
+	// RequestedBy is the signed-in user sending the campaign, who cannot
+	// approve a send held for approval; set by the handler
+	RequestedBy *int64 `json:"-"`
+}
+
+// Limits on send exclusions
+const (
+	maxExclusionSegments     = 10
+	maxExclusionMessagedDays = 365
+)
+
+// AudienceExclusion names customers a send skips: those with one of the IDs,
+// those matching one of the segments and those messaged in the last days
+type AudienceExclusion struct {
+	CustomerIDs []int64 `json:"customer_ids,omitempty"`
+	SegmentIDs  []int64 `json:"segment_ids,omitempty"`
+	// MessagedWithinDays excludes customers sent a message, by any campaign,
+	// in this many days
+	MessagedWithinDays int `json:"messaged_within_days,omitempty"`
+}
+
+// Validate checks the exclusion's segments and days. A nil exclusion is
+// valid.
+func (e *AudienceExclusion) Validate() error {
+	if e == nil {
+		return nil
+	}
+	if len(e.SegmentIDs) > maxExclusionSegments {
+		return models.ErrInvalidInput(fmt.Sprintf("exclude can name at most %d segment_ids", maxExclusionSegments))
+	}
+	for _, id := range e.SegmentIDs {
+		if id <= 0 {
+			return models.ErrInvalidInput("exclude segment_ids must be positive")
+		}
+	}
+	if e.MessagedWithinDays < 0 || e.MessagedWithinDays > maxExclusionMessagedDays {
+		return models.ErrInvalidInput(fmt.Sprintf("exclude messaged_within_days must be between 1 and %d", maxExclusionMessagedDays))
+	}
+	return nil
+}
+
+// ExclusionCounts counts the audience customers a send excluded. A customer
+// excluded for several reasons is counted once, under the first of
+// customer_ids, segments and recently_messaged.
+type ExclusionCounts struct {
+	CustomerIDs      int64 `json:"customer_ids"`
+	Segments         int64 `json:"segments"`
+	RecentlyMessaged int64 `json:"recently_messaged"`
+	Total            int64 `json:"total"`
+}
+
+// audienceRequest converts a stored audience to the send request it stands for
+func audienceRequest(audience *models.CampaignAudience) *SendCampaignRequest {
+	return &SendCampaignRequest{
+		CustomerIDs: audience.CustomerIDs,
+		Target:      audience.Target,
+		SegmentID:   audience.SegmentID,
+		Filter:      audience.Filter,
+	}
+}
+
+// namesRecipients reports whether the request selects its own recipients
+func (r *SendCampaignRequest) namesRecipients() bool {
+	return r.Target != "" || len(r.CustomerIDs) > 0 || r.SegmentID != nil || r.Filter != nil
+}
+
+// resolveAudience returns the request itself when it names recipients, and
+// otherwise the campaign's bound audience
+func resolveAudience(campaign *models.Campaign, req *SendCampaignRequest) (*SendCampaignRequest, error) {
+	if req.namesRecipients() {
+		return req, nil
+	}
+	if campaign.Audience == nil {
+		return nil, models.ErrInvalidInput("customer_ids is required and cannot be empty (the campaign has no bound audience)")
+	}
+	resolved := audienceRequest(campaign.Audience)
+	resolved.Exclude = req.Exclude
+	resolved.ConfirmRecipientCount = req.ConfirmRecipientCount
+	return resolved, nil
 }
 
 // Validate performs validation on the send campaign request
 func (r *SendCampaignRequest) Validate() error {
-	if len(r.CustomerIDs) == 0 {
+	if r.Target != "" && r.Target != SendTargetAll {
+		return models.ErrInvalidInput(fmt.Sprintf("invalid target: %s (must be 'all')", r.Target))
+	}
+	selectors := 0
+	for _, set := range []bool{len(r.CustomerIDs) > 0, r.Target != "", r.SegmentID != nil, r.Filter != nil} {
+		if set {
+			selectors++
+		}
+	}
+	if selectors > 1 {
+		return models.ErrInvalidInput("specify only one of customer_ids, target, segment_id or filter")
+	}
+	if selectors == 0 {
 		return models.ErrInvalidInput("customer_ids is required and cannot be empty")
 	}
-	return nil
+	if r.SegmentID != nil && *r.SegmentID <= 0 {
+		return models.ErrInvalidInput("segment_id must be positive")
+	}
+	if r.Filter != nil {
+		filter := trimmedFilter(*r.Filter)
+		if err := filter.Validate(); err != nil {
+			return err
+		}
+	}
+	return r.Exclude.Validate()
 }
 
 // SendCampaignResult represents the result of sending a campaign
@@ -49,6 +368,108 @@ type SendCampaignResult struct {
 	CampaignID     int64  `json:"campaign_id"`
 	MessagesQueued int    `json:"messages_queued"`
 	Status         string `json:"status"`
+	// Excluded is set when the request had exclusions
+	Excluded *ExclusionCounts `json:"excluded,omitempty"`
+	// SendJob is set when the send is held for a second approval, and on
+	// its approval
+	SendJob *models.SendJob `json:"send_job,omitempty"`
+}
+
+// PrebuildResult represents the result of building a scheduled campaign's
+// messages ahead of its schedule
+type PrebuildResult struct {
+	CampaignID    int64  `json:"campaign_id"`
+	MessagesBuilt int    `json:"messages_built"`
+	Status        string `json:"status"`
+}
+
+// PauseCampaignRequest represents a request to pause a sending campaign.
+// The reason is optional and shown on the campaign while it is paused.
+type PauseCampaignRequest struct {
+	Reason string `json:"reason"`
+}
+
+// PauseCampaignResult represents the result of pausing a campaign
+type PauseCampaignResult struct {
+	CampaignID  int64  `json:"campaign_id"`
+	JobsRemoved int64  `json:"jobs_removed"`
+	Status      string `json:"status"`
+	Reason      string `json:"reason"`
+}
+
+// ResumeCampaignResult represents the result of resuming a paused campaign
+type ResumeCampaignResult struct {
+	CampaignID       int64  `json:"campaign_id"`
+	MessagesRequeued int    `json:"messages_requeued"`
+	Status           string `json:"status"`
+}
+
+// CancelCampaignResult represents the result of cancelling a campaign
+type CancelCampaignResult struct {
+	CampaignID      int64  `json:"campaign_id"`
+	MessagesSkipped int64  `json:"messages_skipped"`
+	JobsRemoved     int64  `json:"jobs_removed"`
+	Status          string `json:"status"`
+}
+
+// RetryFailedRequest represents a request to retry the messages that failed
+// across campaigns during an incident such as a provider outage. A dry run
+// only counts them; the retry itself must confirm that count.
+type RetryFailedRequest struct {
+	From          time.Time `json:"from"`
+	To            time.Time `json:"to"`
+	ErrorContains string    `json:"error_contains"`
+	Channel       string    `json:"channel,omitempty"`
+	DryRun        bool      `json:"dry_run"`
+	// ConfirmMessageCount must equal the number of matching messages
+	ConfirmMessageCount *int64 `json:"confirm_message_count,omitempty"`
+}
+
+// maxRetryFailedWindow bounds the window of a retry of failed messages, so a
+// mistyped date cannot requeue months of failures
+const maxRetryFailedWindow = 7 * 24 * time.Hour
+
+// Validate performs validation on the retry failed request
+func (r *RetryFailedRequest) Validate() error {
+	if r.From.IsZero() || r.To.IsZero() {
+		return models.ErrInvalidInput("from and to are required")
+	}
+	if !r.To.After(r.From) {
+		return models.ErrInvalidInput("to must be after from")
+	}
+	// Retried messages that fail again would match a window reaching past now
+	if r.To.After(time.Now()) {
+		return models.ErrInvalidInput("to must not be in the future")
+	}
+	if r.To.Sub(r.From) > maxRetryFailedWindow {
+		return models.ErrInvalidInput(fmt.Sprintf("the window may span at most %s", maxRetryFailedWindow))
+	}
+	if r.ErrorContains == "" {
+		return models.ErrInvalidInput("error_contains is required")
+	}
+	if r.Channel != "" && !models.IsValidChannel(r.Channel) {
+		return models.ErrInvalidInput(fmt.Sprintf("invalid channel: %s", r.Channel))
+	}
+	return nil
+}
+
+// RetryFailedResult represents the result of retrying failed messages. For a
+// dry run only the matched counts are set.
+type RetryFailedResult struct {
+	DryRun           bool  `json:"dry_run"`
+	MessagesMatched  int64 `json:"messages_matched"`
+	CampaignsMatched int64 `json:"campaigns_matched"`
+	MessagesReset    int   `json:"messages_reset"`
+	MessagesRequeued int   `json:"messages_requeued"`
+}
+
+// RetryCampaignFailedResult represents the result of retrying the failed
+// messages of one campaign
+type RetryCampaignFailedResult struct {
+	CampaignID       int64  `json:"campaign_id"`
+	MessagesReset    int    `json:"messages_reset"`
+	MessagesRequeued int    `json:"messages_requeued"`
+	Status           string `json:"status"`
 }
 
 // PreviewRequest represents a request to preview a personalized message
@@ -78,13 +499,320 @@ type CustomerPreview struct {
 	FirstName string `json:"first_name"`
 }
 
+// Preview sample limits
+const (
+	defaultPreviewSampleSize = 5
+	maxPreviewSampleSize     = 50
+)
+
+// How a preview sample picks its customers
+const (
+	PreviewSamplePickRandom = "random"
+	PreviewSamplePickFirst  = "first"
+)
+
+// PreviewSampleRequest represents a request to preview a campaign for a
+// sample of its audience
+type PreviewSampleRequest struct {
+	// Size is how many customers to render for (default 5)
+	Size int `json:"size,omitempty"`
+	// Pick is random (the default) or first, the customers with the lowest IDs
+	Pick string `json:"pick,omitempty"`
+	// SegmentID samples a saved segment instead of the campaign's bound audience
+	SegmentID        *int64  `json:"segment_id,omitempty"`
+	OverrideTemplate *string `json:"override_template,omitempty"`
+}
+
+// Validate performs validation on the preview sample request
+func (r *PreviewSampleRequest) Validate() error {
+	if r.Size < 0 || r.Size > maxPreviewSampleSize {
+		return models.ErrInvalidInput(fmt.Sprintf("size must be between 1 and %d", maxPreviewSampleSize))
+	}
+	if r.Pick != "" && r.Pick != PreviewSamplePickRandom && r.Pick != PreviewSamplePickFirst {
+		return models.ErrInvalidInput(fmt.Sprintf("invalid pick: %s (must be '%s' or '%s')", r.Pick, PreviewSamplePickRandom, PreviewSamplePickFirst))
+	}
+	if r.SegmentID != nil && *r.SegmentID <= 0 {
+		return models.ErrInvalidInput("segment_id must be positive")
+	}
+	return nil
+}
+
+// PreviewSampleResult holds a campaign's template rendered for a sample of
+// its audience
+type PreviewSampleResult struct {
+	CampaignID   int64    `json:"campaign_id"`
+	UsedTemplate string   `json:"used_template"`
+	Placeholders []string `json:"placeholders"`
+	// AudienceSize counts the whole audience, including customers who are
+	// opted out or snoozed and so never sampled
+	AudienceSize int64            `json:"audience_size"`
+	Samples      []*SamplePreview `json:"samples"`
+}
+
+// SamplePreview is a campaign's template rendered for one sampled customer
+type SamplePreview struct {
+	Customer *CustomerPreview `json:"customer"`
+	Rendered string           `json:"rendered"`
+	// Length is the rendered length in characters, not bytes
+	Length   int    `json:"length"`
+	Encoding string `json:"encoding"`
+	Segments int    `json:"segments"`
+	// MissingFields lists the placeholders the customer has no value for
+	MissingFields []string `json:"missing_fields"`
+}
+
+// TemplatePreviewRequest represents a request to render a template against synthetic personas
+type TemplatePreviewRequest struct {
+	Template string `json:"template"`
+	// Personas limits the preview to the named personas; empty renders all of them
+	Personas []string `json:"personas,omitempty"`
+}
+
+// Validate performs validation on the template preview request
+func (r *TemplatePreviewRequest) Validate() error {
+	if r.Template == "" {
+		return models.ErrInvalidInput("template is required")
+	}
+	return nil
+}
+
+// TemplateEncodingRequest represents a request to check a template's SMS encoding
+type TemplateEncodingRequest struct {
+	Template string `json:"template"`
+	// Substitute replaces smart quotes, dashes and similar characters with
+	// GSM-7 equivalents before checking
+	Substitute bool `json:"substitute,omitempty"`
+}
+
+// Validate performs validation on the template encoding request
+func (r *TemplateEncodingRequest) Validate() error {
+	if r.Template == "" {
+		return models.ErrInvalidInput("template is required")
+	}
+	return nil
+}
+
+// TemplateEncodingResult reports the SMS encoding of a template's fixed text,
+// leaving out placeholders
+type TemplateEncodingResult struct {
+	// Template is the checked template, after any substitutions
+	Template    string `json:"template"`
+	Substituted bool   `json:"substituted"`
+	// Encoding is GSM-7 or UCS-2
+	Encoding string `json:"encoding"`
+	// SegmentCharacters fit in a single SMS and MultipartSegmentCharacters in
+	// each part of a longer message
+	SegmentCharacters          int `json:"segment_characters"`
+	MultipartSegmentCharacters int `json:"multipart_segment_characters"`
+	FixedLength                int `json:"fixed_length"`
+	FixedSegments              int `json:"fixed_segments"`
+	// UCS2Characters are the characters that force UCS-2 encoding
+	UCS2Characters []string `json:"ucs2_characters"`
+	Warnings       []string `json:"warnings"`
+}
+
+// SMSMeasure is how a message is sent as SMS
+type SMSMeasure struct {
+	// Encoding is GSM-7 or UCS-2
+	Encoding string `json:"encoding"`
+	// Length is in the encoding's units: septets for GSM-7, where extension
+	// characters such as € count twice, and UTF-16 units for UCS-2
+	Length   int `json:"length"`
+	Segments int `json:"segments"`
+}
+
+// EstimateCampaignRequest represents a request to estimate what sending a
+// campaign would cost
+type EstimateCampaignRequest struct {
+	// SegmentID estimates a saved segment instead of the campaign's bound
+	// audience
+	SegmentID *int64 `json:"segment_id,omitempty"`
+}
+
+// Validate performs validation on the estimate request
+func (r *EstimateCampaignRequest) Validate() error {
+	if r.SegmentID != nil && *r.SegmentID <= 0 {
+		return models.ErrInvalidInput("segment_id must be positive")
+	}
+	return nil
+}
+
+// CampaignEstimate is what sending a campaign to its audience would take
+type CampaignEstimate struct {
+	CampaignID int64  `json:"campaign_id"`
+	Channel    string `json:"channel"`
+	// Messages counts the customers who would be messaged and Skipped those
+	// who are opted out, snoozed or bounced
+	Messages int64 `json:"messages"`
+	Skipped  int64 `json:"skipped"`
+	// TotalSegments sums the SMS segments of every message; it is 0 on other
+	// channels
+	TotalSegments int64 `json:"total_segments"`
+	// MessagesBySegments counts the messages by how many SMS segments each takes
+	MessagesBySegments map[int]int64 `json:"messages_by_segments"`
+	// Rate is the channel's price per SMS segment, or per message on other
+	// channels
+	Rate          float64 `json:"rate"`
+	EstimatedCost float64 `json:"estimated_cost"`
+}
+
+// TemplatePreviewResult holds a template rendered for each requested persona
+type TemplatePreviewResult struct {
+	Template     string            `json:"template"`
+	Placeholders []string          `json:"placeholders"`
+	Previews     []*PersonaPreview `json:"previews"`
+}
+
+// PersonaPreview is a template rendered for one synthetic persona
+type PersonaPreview struct {
+	Persona     string           `json:"persona"`
+	Description string           `json:"description"`
+	Customer    *models.Customer `json:"customer"`
+	Rendered    string           `json:"rendered"`
+	// Length is the rendered length in characters, not bytes
+	Length int `json:"length"`
+	// Encoding is the SMS encoding of the rendered text, GSM-7 or UCS-2, and
+	// Segments the SMS segments it takes
+	Encoding string `json:"encoding"`
+	Segments int    `json:"segments"`
+	// EmptyPlaceholders lists placeholders that rendered as an empty string
+	EmptyPlaceholders []string `json:"empty_placeholders"`
+}
+
+// MaxLookupPhones is the maximum number of phone numbers per customer lookup
+const MaxLookupPhones = 1000
+
+// SnoozeCustomerRequest represents a request to keep a customer out of
+// campaigns until a time. A null until ends the snooze.
+type SnoozeCustomerRequest struct {
+	Until *time.Time `json:"until"`
+}
+
+// CustomerLookupRequest represents a request to resolve phone numbers to customers
+type CustomerLookupRequest struct {
+	Phones []string `json:"phones"`
+}
+
+// Validate performs validation on the customer lookup request
+func (r *CustomerLookupRequest) Validate() error {
+	if len(r.Phones) == 0 {
+		return models.ErrInvalidInput("phones is required and cannot be empty")
+	}
+	if len(r.Phones) > MaxLookupPhones {
+		return models.ErrInvalidInput(fmt.Sprintf("at most %d phones can be looked up at once", MaxLookupPhones))
+	}
+	return nil
+}
+
+// CustomerLookupResult holds the customers matched by a lookup and the phones without a customer
+type CustomerLookupResult struct {
+	Matched []*models.Customer `json:"matched"`
+	Misses  []string           `json:"misses"`
+}
+
+// CustomerTimeline is a page of a customer's activity in chronological order.
+// NextBefore is set when older entries may exist.
+type CustomerTimeline struct {
+	CustomerID int64                   `json:"customer_id"`
+	Entries    []*models.TimelineEntry `json:"entries"`
+	NextBefore *time.Time              `json:"next_before,omitempty"`
+}
+
+// CustomerListResult represents paginated customer list results
+type CustomerListResult struct {
+	Data       []*models.Customer      `json:"data"`
+	Pagination models.PaginationResult `json:"pagination"`
+}
+
+// SegmentRequest represents a request to create or replace a segment
+type SegmentRequest struct {
+	Name        string               `json:"name"`
+	Description string               `json:"description,omitempty"`
+	Filter      models.SegmentFilter `json:"filter"`
+}
+
+// SegmentDetails is a segment with the number of customers it matched when
+// it was last counted
+type SegmentDetails struct {
+	*models.Segment
+	CustomerCount int64     `json:"customer_count"`
+	CountedAt     time.Time `json:"counted_at"`
+}
+
+// SegmentMembersResult represents a page of the customers a segment matches
+type SegmentMembersResult struct {
+	Data       []*models.Customer      `json:"data"`
+	Pagination models.PaginationResult `json:"pagination"`
+}
+
+// SegmentListResult represents a paginated list of segments
+type SegmentListResult struct {
+	Data       []*models.Segment       `json:"data"`
+	Pagination models.PaginationResult `json:"pagination"`
+}
+
+// ShortDomainRequest represents a request to add a short domain. Enabled
+// defaults to true.
+type ShortDomainRequest struct {
+	Domain    string `json:"domain"`
+	IsDefault bool   `json:"is_default,omitempty"`
+	Enabled   *bool  `json:"enabled,omitempty"`
+}
+
+// UpdateShortDomainRequest represents a request to change a short domain;
+// fields left out are kept. The domain name itself cannot be changed.
+type UpdateShortDomainRequest struct {
+	IsDefault *bool `json:"is_default,omitempty"`
+	Enabled   *bool `json:"enabled,omitempty"`
+}
+
+// SetShortDomainRequest represents a request to choose the short domain of a
+// campaign's links. A null short_domain_id uses the account's default.
+type SetShortDomainRequest struct {
+	ShortDomainID *int64 `json:"short_domain_id"`
+}
+
+// Sources of the short domain a campaign's links use
+const (
+	// ShortDomainSourceCampaign is the domain chosen for the campaign
+	ShortDomainSourceCampaign = "campaign"
+	// ShortDomainSourceDefault is the account's default domain
+	ShortDomainSourceDefault = "default"
+	// ShortDomainSourceFallback is another usable domain of the account,
+	// used when neither the chosen nor the default domain is usable
+	ShortDomainSourceFallback = "fallback"
+)
+
+// CampaignShortDomain is the short domain a campaign's links would use now.
+// Domain is nil when the account has no usable domain.
+type CampaignShortDomain struct {
+	CampaignID    int64               `json:"campaign_id"`
+	ShortDomainID *int64              `json:"short_domain_id"`
+	Domain        *models.ShortDomain `json:"domain"`
+	Source        string              `json:"source,omitempty"`
+}
+
+// MessageListResult represents a paginated list of outbound messages
+type MessageListResult struct {
+	Data       []*models.OutboundMessage `json:"data"`
+	Pagination models.PaginationResult   `json:"pagination"`
+}
+
+// RecipientListResult represents a paginated list of campaign messages with their recipients
+type RecipientListResult struct {
+	Data       []*models.MessageRecipient `json:"data"`
+	Pagination models.PaginationResult    `json:"pagination"`
+}
+
 // CampaignListItem represents a campaign in list view (simplified)
 type CampaignListItem struct {
-	ID        int64     `json:"id"`
-	Name      string    `json:"name"`
-	Channel   string    `json:"channel"`
-	Status    string    `json:"status"`
-	CreatedAt time.Time `json:"created_at"`
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Channel     string    `json:"channel"`
+	Status      string    `json:"status"`
+	Environment string    `json:"environment"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
 // CampaignListResult represents paginated campaign list results
@@ -92,3 +820,300 @@ type CampaignListResult struct {
 	Data       []*CampaignListItem     `json:"data"`
 	Pagination models.PaginationResult `json:"pagination"`
 }
+
+// CampaignChangesResult is a page of the campaign change feed. Pass
+// NextCursor back to receive the changes that follow; it stays the same when
+// nothing has changed.
+type CampaignChangesResult struct {
+	Data       []*models.CampaignChange `json:"data"`
+	NextCursor string                   `json:"next_cursor"`
+	HasMore    bool                     `json:"has_more"`
+}
+
+// MessageChangesResult is a page of the message change feed
+type MessageChangesResult struct {
+	Data       []*models.OutboundMessage `json:"data"`
+	NextCursor string                    `json:"next_cursor"`
+	HasMore    bool                      `json:"has_more"`
+}
+
+// ReceiptListResult is a page of message receipts. Pass NextCursor back as
+// cursor to continue.
+type ReceiptListResult struct {
+	Data       []*models.MessageReceipt `json:"data"`
+	NextCursor string                   `json:"next_cursor"`
+	HasMore    bool                     `json:"has_more"`
+}
+
+// ChangeListResult is a page of the change log. Pass NextAfterID back as
+// after_id to continue; it stays the same when nothing has changed.
+type ChangeListResult struct {
+	Data        []*models.ChangeRecord `json:"data"`
+	NextAfterID int64                  `json:"next_after_id"`
+	HasMore     bool                   `json:"has_more"`
+}
+
+// DeliveryReportResult counts the reports in a delivery report callback that
+// updated a message and those that matched no sent message
+type DeliveryReportResult struct {
+	Applied   int `json:"applied"`
+	Unmatched int `json:"unmatched"`
+}
+
+// InboundResult counts the replies in an inbound message callback that opted
+// a customer out or back in, and those that changed nothing
+type InboundResult struct {
+	OptedOut int `json:"opted_out"`
+	OptedIn  int `json:"opted_in"`
+	Ignored  int `json:"ignored"`
+}
+
+// MetaWebhookResult is the outcome of a Meta webhook, which carries both
+// delivery reports and customer replies
+type MetaWebhookResult struct {
+	DeliveryReports *DeliveryReportResult `json:"delivery_reports"`
+	Inbound         *InboundResult        `json:"inbound"`
+}
+
+// OptOutReportRequest selects the days an opt-out report covers, as
+// YYYY-MM-DD dates in UTC. Both are inclusive and optional.
+type OptOutReportRequest struct {
+	From string
+	To   string
+}
+
+// period returns the report's [from, to) range. To defaults to today and
+// from to 30 days before it.
+func (r *OptOutReportRequest) period(today time.Time) (time.Time, time.Time, error) {
+	return reportPeriod(r.From, r.To, today, defaultReportDays)
+}
+
+// reportPeriod parses a report's inclusive YYYY-MM-DD dates into a [from, to)
+// range. To defaults to today and from to defaultDays before it.
+func reportPeriod(fromDate, toDate string, today time.Time, defaultDays int) (time.Time, time.Time, error) {
+	to := today
+	if toDate != "" {
+		parsed, err := time.Parse(time.DateOnly, toDate)
+		if err != nil {
+			return time.Time{}, time.Time{}, models.ErrInvalidInput("to must be a date in YYYY-MM-DD format")
+		}
+		to = parsed
+	}
+
+	from := to.AddDate(0, 0, -(defaultDays - 1))
+	if fromDate != "" {
+		parsed, err := time.Parse(time.DateOnly, fromDate)
+		if err != nil {
+			return time.Time{}, time.Time{}, models.ErrInvalidInput("from must be a date in YYYY-MM-DD format")
+		}
+		from = parsed
+	}
+
+	if from.After(to) {
+		return time.Time{}, time.Time{}, models.ErrInvalidInput("from must not be after to")
+	}
+	if to.Sub(from) >= maxReportDays*24*time.Hour {
+		return time.Time{}, time.Time{}, models.ErrInvalidInput(fmt.Sprintf("a report covers at most %d days", maxReportDays))
+	}
+
+	return from, to.AddDate(0, 0, 1), nil
+}
+
+// AnalyticsOverviewRequest selects the days an analytics overview covers, as
+// inclusive YYYY-MM-DD dates in UTC, and how they are grouped
+type AnalyticsOverviewRequest struct {
+	// Interval is models.AnalyticsIntervalDay (the default) or
+	// models.AnalyticsIntervalWeek
+	Interval string
+	From     string
+	To       string
+}
+
+// period returns the overview's [from, to) range. To defaults to today and
+// from to 30 days, or 12 weeks, before it. Weekly overviews start on the
+// Monday of from's week, so every week is whole.
+func (r *AnalyticsOverviewRequest) period(today time.Time) (time.Time, time.Time, error) {
+	defaultDays := defaultReportDays
+	if r.Interval == models.AnalyticsIntervalWeek {
+		defaultDays = defaultAnalyticsWeeks * 7
+	}
+
+	from, to, err := reportPeriod(r.From, r.To, today, defaultDays)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if r.Interval == models.AnalyticsIntervalWeek {
+		from = from.AddDate(0, 0, -((int(from.Weekday()) + 6) % 7))
+	}
+
+	return from, to, nil
+}
+
+// warmupDateLayout is the format of warm-up start dates in requests
+const warmupDateLayout = "2006-01-02"
+
+// SenderWarmupRequest represents a request to start or change a sender's warm-up ramp.
+// Omitted fields fall back to today, DefaultWarmupInitialDailyCap and DefaultWarmupMaxDailyCap.
+type SenderWarmupRequest struct {
+	StartedOn       string `json:"started_on,omitempty"`
+	InitialDailyCap int    `json:"initial_daily_cap,omitempty"`
+	MaxDailyCap     int    `json:"max_daily_cap,omitempty"`
+}
+
+// SenderWarmupStatus is a sender's warm-up policy together with today's effective cap
+type SenderWarmupStatus struct {
+	*models.SenderWarmup
+	TodayDailyCap int  `json:"today_daily_cap"`
+	Complete      bool `json:"complete"`
+}
+
+// SenderRegistrationRequest represents a request to record a sender's
+// registration for a country. Type defaults to what the country requires.
+type SenderRegistrationRequest struct {
+	Type           string `json:"type,omitempty"`
+	RegistrationID string `json:"registration_id"`
+}
+
+// SenderRegistrations lists a sender's registrations. Unregistered holds the
+// enforced countries whose rule covers the sender but which it has no
+// matching registration for; SMS from the sender to them are failed.
+type SenderRegistrations struct {
+	SenderID      string                       `json:"sender_id"`
+	Registrations []*models.SenderRegistration `json:"registrations"`
+	Unregistered  []string                     `json:"unregistered"`
+}
+
+// PreviewLinkRequest represents a request for a campaign preview link
+type PreviewLinkRequest struct {
+	// ExpiresInHours is how long the link works; 0 uses the default of 72
+	ExpiresInHours int `json:"expires_in_hours,omitempty"`
+}
+
+// ttl returns the link lifetime the request asks for
+func (r *PreviewLinkRequest) ttl() (time.Duration, error) {
+	if r.ExpiresInHours == 0 {
+		return models.DefaultPreviewLinkTTL, nil
+	}
+	ttl := time.Duration(r.ExpiresInHours) * time.Hour
+	if r.ExpiresInHours < 0 || ttl > models.MaxPreviewLinkTTL {
+		return 0, models.ErrInvalidInput(fmt.Sprintf("expires_in_hours must be between 1 and %d", int(models.MaxPreviewLinkTTL.Hours())))
+	}
+	return ttl, nil
+}
+
+// PreviewLinkResult is a newly issued preview link with its URL
+type PreviewLinkResult struct {
+	*models.PreviewLink
+	URL string `json:"url"`
+}
+
+// DevicePreview is a campaign's message rendered for each synthetic persona,
+// as shown on a preview link's page
+type DevicePreview struct {
+	CampaignID int64            `json:"campaign_id"`
+	Name       string           `json:"name"`
+	Channel    string           `json:"channel"`
+	SenderID   *string          `json:"sender_id"`
+	ExpiresAt  time.Time        `json:"expires_at"`
+	Messages   []*DeviceMessage `json:"messages"`
+}
+
+// DeviceMessage is one rendered message. Parts holds the SMS segments the
+// message is split into, or the whole message on other channels.
+type DeviceMessage struct {
+	Persona     string `json:"persona"`
+	Description string `json:"description"`
+	Length      int    `json:"length"`
+	// Encoding is GSM-7 or UCS-2 for SMS, and empty on other channels
+	Encoding string   `json:"encoding,omitempty"`
+	Parts    []string `json:"parts"`
+}
+
+// TemplatePartialRequest represents a request to create or update a template
+// partial. Name is only read on create; updates take it from the URL.
+type TemplatePartialRequest struct {
+	Name    string `json:"name,omitempty"`
+	Content string `json:"content"`
+}
+
+// MessageTemplateRequest represents a request to create or update a message
+// template. An update with changed content adds a version.
+type MessageTemplateRequest struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+// MessageNoteRequest represents a support note to add to a message
+type MessageNoteRequest struct {
+	Body string `json:"body"`
+}
+
+// LoginRequest represents an operator signing in
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// LoginResult holds the token issued at login; pass it as
+// "Authorization: Bearer <token>"
+type LoginResult struct {
+	Token     string       `json:"token"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	User      *models.User `json:"user"`
+}
+
+// CreateUserRequest represents a request to add an operator
+type CreateUserRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Role     string `json:"role"`
+}
+
+// UpdateUserRoleRequest represents a request to change an operator's role
+type UpdateUserRoleRequest struct {
+	Role string `json:"role"`
+}
+
+// CreateAccountRequest represents a request to add a tenant and its first admin
+type CreateAccountRequest struct {
+	Name          string `json:"name"`
+	AdminEmail    string `json:"admin_email"`
+	AdminPassword string `json:"admin_password"`
+}
+
+// AccountResult is a newly created account and its admin
+type AccountResult struct {
+	*models.Account
+	Admin *models.User `json:"admin"`
+}
+
+// SetDailySendCapRequest represents a request to change how many messages an
+// account may send per UTC day. A null daily_send_cap removes the cap.
+type SetDailySendCapRequest struct {
+	DailySendCap *int `json:"daily_send_cap"`
+}
+
+// SetCustomerAttributesRequest represents a request to replace the customer
+// attribute keys an account's templates may use as placeholders
+type SetCustomerAttributesRequest struct {
+	Attributes []string `json:"attributes"`
+}
+
+// SetBouncePolicyRequest represents a request to change how an account's
+// bounced messages are handled. Omitted fields keep their current value.
+type SetBouncePolicyRequest struct {
+	SoftRetryHours *int  `json:"soft_retry_hours"`
+	SoftMaxRetries *int  `json:"soft_max_retries"`
+	SuppressHard   *bool `json:"suppress_hard"`
+}
+
+// AccountUsage is how much of its daily send cap an account has used today.
+// Remaining is nil for an account without a cap.
+type AccountUsage struct {
+	AccountID    int64     `json:"account_id"`
+	Day          string    `json:"day"`
+	SentToday    int       `json:"sent_today"`
+	DailySendCap *int      `json:"daily_send_cap"`
+	Remaining    *int      `json:"remaining"`
+	ResetsAt     time.Time `json:"resets_at"`
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// liveStatsIdle is how long a campaign's counts are kept after they were last
+// read; campaigns nobody is watching are counted again on their next read
+const liveStatsIdle = 10 * time.Minute
+
+// LiveStats keeps the message counts of recently read campaigns in memory and
+// current by applying the status changes the database notifies on
+// models.MessageStatsChannel, so polling a campaign in flight does not count
+// its messages on every read. It is a db.Subscriber; until it is listening,
+// every read counts the messages.
+type LiveStats struct {
+	campaignRepo repository.CampaignRepository
+	now          func() time.Time
+	logger       *slog.Logger
+
+	mu        sync.Mutex
+	listening bool
+	campaigns map[int64]*liveCampaign
+}
+
+// liveCampaign is the counts of one campaign. Until the counts are loaded,
+// changes are kept in pending.
+type liveCampaign struct {
+	loaded   bool
+	stats    models.CampaignStats
+	snapshot models.TxSnapshot
+	pending  []*models.MessageStatsChange
+	readAt   time.Time
+}
+
+// NewLiveStats creates live campaign stats
+func NewLiveStats(campaignRepo repository.CampaignRepository, logger *slog.Logger) *LiveStats {
+	return &LiveStats{
+		campaignRepo: campaignRepo,
+		now:          time.Now,
+		logger:       logger,
+		campaigns:    make(map[int64]*liveCampaign),
+	}
+}
+
+// Get returns a campaign's message counts
+func (l *LiveStats) Get(ctx context.Context, campaignID int64) (models.CampaignStats, error) {
+	l.mu.Lock()
+	entry, ok := l.campaigns[campaignID]
+	switch {
+	case !l.listening || (ok && !entry.loaded):
+		// Not tracking changes, or another read is loading the counts
+		l.mu.Unlock()
+		return l.count(ctx, campaignID)
+	case ok:
+		entry.readAt = l.now()
+		stats := entry.stats
+		l.mu.Unlock()
+		return stats, nil
+	}
+
+	// Changes are collected from now on, so none is lost while counting.
+	// The snapshot of the count tells which of them it already includes.
+	entry = &liveCampaign{}
+	l.campaigns[campaignID] = entry
+	l.mu.Unlock()
+
+	stats, snapshot, err := l.campaignRepo.CountMessages(ctx, campaignID)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.campaigns[campaignID] != entry {
+		// Reset while counting; the count is still current
+		if err != nil {
+			return models.CampaignStats{}, err
+		}
+		return *stats, nil
+	}
+	if err != nil {
+		delete(l.campaigns, campaignID)
+		return models.CampaignStats{}, err
+	}
+
+	entry.loaded = true
+	entry.stats = *stats
+	entry.snapshot = snapshot
+	entry.readAt = l.now()
+	for _, change := range entry.pending {
+		entry.apply(change)
+	}
+	entry.pending = nil
+
+	return entry.stats, nil
+}
+
+// count counts a campaign's messages in the database
+func (l *LiveStats) count(ctx context.Context, campaignID int64) (models.CampaignStats, error) {
+	stats, _, err := l.campaignRepo.CountMessages(ctx, campaignID)
+	if err != nil {
+		return models.CampaignStats{}, err
+	}
+	return *stats, nil
+}
+
+// Notify applies a models.MessageStatsChange payload
+func (l *LiveStats) Notify(payload string) {
+	var change models.MessageStatsChange
+	if err := json.Unmarshal([]byte(payload), &change); err != nil {
+		l.logger.Warn("invalid message stats notification", slog.String("error", err.Error()))
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.campaigns[change.CampaignID]
+	if !ok {
+		return
+	}
+	if !entry.loaded {
+		entry.pending = append(entry.pending, &change)
+		return
+	}
+	entry.apply(&change)
+}
+
+// Listening drops all counts, since changes may have been missed while not
+// listening
+func (l *LiveStats) Listening(listening bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.listening = listening
+	l.campaigns = make(map[int64]*liveCampaign)
+}
+
+// Run drops the counts of campaigns that have not been read for a while,
+// until ctx is done
+func (l *LiveStats) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			l.evictIdle()
+		}
+	}
+}
+
+// evictIdle drops the counts not read within liveStatsIdle
+func (l *LiveStats) evictIdle() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	cutoff := l.now().Add(-liveStatsIdle)
+	for id, entry := range l.campaigns {
+		if entry.loaded && entry.readAt.Before(cutoff) {
+			delete(l.campaigns, id)
+		}
+	}
+}
+
+// apply adds a change the loaded counts do not include yet
+func (c *liveCampaign) apply(change *models.MessageStatsChange) {
+	if c.snapshot.Visible(change.XID) {
+		return
+	}
+	c.stats.Add(change.Counts)
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestLiveStats_AppliesChangesAfterCount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+	live := NewLiveStats(campaignRepo, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	live.Listening(true)
+
+	// Transaction 105 commits while the messages are counted: its change
+	// arrives before the count returns but is not part of it. Transaction 99
+	// committed before the count and is already included.
+	snapshot, _ := models.ParseTxSnapshot("100:110:100,105")
+	campaignRepo.EXPECT().CountMessages(gomock.Any(), int64(7)).DoAndReturn(
+		func(ctx context.Context, id int64) (*models.CampaignStats, models.TxSnapshot, error) {
+			live.Notify(`{"xid": "105", "campaign_id": 7, "counts": {"pending": -2, "sending": 2}}`)
+			live.Notify(`{"xid": "99", "campaign_id": 7, "counts": {"pending": -1, "sending": 1}}`)
+			return &models.CampaignStats{Total: 10, Pending: 9, Sending: 1}, snapshot, nil
+		})
+
+	stats, err := live.Get(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if stats != (models.CampaignStats{Total: 10, Pending: 7, Sending: 3}) {
+		t.Errorf("Get() = %+v, want 7 pending and 3 sending", stats)
+	}
+
+	// Later changes are applied without counting again
+	live.Notify(`{"xid": "112", "campaign_id": 7, "counts": {"sending": -3, "sent": 2, "failed": 1}}`)
+	live.Notify(`{"xid": "113", "campaign_id": 8, "counts": {"pending": 5}}`)
+
+	stats, err = live.Get(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if stats != (models.CampaignStats{Total: 10, Pending: 7, Sent: 2, Failed: 1}) {
+		t.Errorf("Get() = %+v, want 7 pending, 2 sent and 1 failed", stats)
+	}
+}
+
+func TestLiveStats_CountsWhileNotListening(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+	live := NewLiveStats(campaignRepo, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	campaignRepo.EXPECT().CountMessages(gomock.Any(), int64(7)).
+		Return(&models.CampaignStats{Total: 3, Pending: 3}, models.TxSnapshot{}, nil).Times(2)
+
+	for range 2 {
+		if _, err := live.Get(context.Background(), 7); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+	}
+
+	// A dropped connection discards the counts, which may miss changes
+	live.Listening(true)
+	campaignRepo.EXPECT().CountMessages(gomock.Any(), int64(7)).
+		Return(&models.CampaignStats{Total: 3, Pending: 3}, models.TxSnapshot{}, nil).Times(2)
+	if _, err := live.Get(context.Background(), 7); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	live.Listening(false)
+	live.Listening(true)
+	if _, err := live.Get(context.Background(), 7); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+}
+
+func TestLiveStats_EvictIdle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+	live := NewLiveStats(campaignRepo, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	live.Listening(true)
+
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	live.now = func() time.Time { return now }
+
+	campaignRepo.EXPECT().CountMessages(gomock.Any(), gomock.Any()).
+		Return(&models.CampaignStats{}, models.TxSnapshot{}, nil).Times(2)
+	for _, id := range []int64{1, 2} {
+		if _, err := live.Get(context.Background(), id); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+	}
+
+	now = now.Add(liveStatsIdle / 2)
+	if _, err := live.Get(context.Background(), 2); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	now = now.Add(liveStatsIdle/2 + time.Second)
+	live.evictIdle()
+
+	if _, ok := live.campaigns[1]; ok {
+		t.Error("Expected campaign 1 to be evicted")
+	}
+	if _, ok := live.campaigns[2]; !ok {
+		t.Error("Expected campaign 2 to be kept")
+	}
+}
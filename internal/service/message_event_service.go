@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// MessageEventService serves the send history of messages. Events are
+// recorded by the worker and the repositories, never through the API.
+type MessageEventService interface {
+	// List returns a message's events, oldest first
+	List(ctx context.Context, messageID int64) ([]*models.MessageEvent, error)
+}
+
+type messageEventService struct {
+	eventRepo   repository.MessageEventRepository
+	messageRepo repository.OutboundMessageRepository
+}
+
+// NewMessageEventService creates a new message event service
+func NewMessageEventService(
+	eventRepo repository.MessageEventRepository,
+	messageRepo repository.OutboundMessageRepository,
+) MessageEventService {
+	return &messageEventService{
+		eventRepo:   eventRepo,
+		messageRepo: messageRepo,
+	}
+}
+
+// List retrieves the events of a message, which must exist
+func (s *messageEventService) List(ctx context.Context, messageID int64) ([]*models.MessageEvent, error) {
+	if _, err := s.messageRepo.GetByID(ctx, messageID); err != nil {
+		return nil, err
+	}
+
+	return s.eventRepo.ListByMessage(ctx, messageID)
+}
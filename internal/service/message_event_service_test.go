@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestMessageEventService_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	eventRepo := mocks.NewMockMessageEventRepository(ctrl)
+	messageRepo := mocks.NewMockOutboundMessageRepository(ctrl)
+	svc := NewMessageEventService(eventRepo, messageRepo)
+
+	messageRepo.EXPECT().GetByID(gomock.Any(), int64(7)).Return(&models.OutboundMessage{ID: 7}, nil)
+	eventRepo.EXPECT().ListByMessage(gomock.Any(), int64(7)).Return([]*models.MessageEvent{
+		{MessageID: 7, Type: models.MessageEventQueued},
+		{MessageID: 7, Type: models.MessageEventSent},
+	}, nil)
+
+	events, err := svc.List(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(events) != 2 {
+		t.Errorf("List() = %d events, want 2", len(events))
+	}
+
+	// A message of another account is not found rather than listed as empty
+	messageRepo.EXPECT().GetByID(gomock.Any(), int64(42)).Return(nil, models.ErrNotFoundWithMsg("message with ID 42 not found"))
+	var appErr *models.AppError
+	if _, err := svc.List(context.Background(), 42); !errors.As(err, &appErr) || appErr.Code != "NOT_FOUND" {
+		t.Errorf("List(unknown) error = %v, want NOT_FOUND", err)
+	}
+}
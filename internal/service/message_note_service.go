@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// MessageNoteService records support notes on messages
+type MessageNoteService interface {
+	// Create adds a note to a message. author is the email of the user
+	// writing it, or nil when authentication is disabled.
+	Create(ctx context.Context, messageID int64, author *string, req *MessageNoteRequest) (*models.MessageNote, error)
+	// List returns a message's notes, oldest first
+	List(ctx context.Context, messageID int64) ([]*models.MessageNote, error)
+}
+
+type messageNoteService struct {
+	noteRepo    repository.MessageNoteRepository
+	messageRepo repository.OutboundMessageRepository
+	logger      *slog.Logger
+}
+
+// NewMessageNoteService creates a new message note service
+func NewMessageNoteService(
+	noteRepo repository.MessageNoteRepository,
+	messageRepo repository.OutboundMessageRepository,
+	logger *slog.Logger,
+) MessageNoteService {
+	return &messageNoteService{
+		noteRepo:    noteRepo,
+		messageRepo: messageRepo,
+		logger:      logger,
+	}
+}
+
+// Create validates and stores a note on a message of the account of ctx
+func (s *messageNoteService) Create(ctx context.Context, messageID int64, author *string, req *MessageNoteRequest) (*models.MessageNote, error) {
+	note := &models.MessageNote{
+		MessageID: messageID,
+		Author:    author,
+		Body:      strings.TrimSpace(req.Body),
+	}
+	if err := note.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.noteRepo.Create(ctx, note); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("message note added",
+		slog.Int64("message_id", messageID),
+		slog.Int64("note_id", note.ID),
+	)
+
+	return note, nil
+}
+
+// List retrieves the notes of a message, which must exist
+func (s *messageNoteService) List(ctx context.Context, messageID int64) ([]*models.MessageNote, error) {
+	if _, err := s.messageRepo.GetByID(ctx, messageID); err != nil {
+		return nil, err
+	}
+
+	return s.noteRepo.ListByMessage(ctx, messageID)
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestMessageNoteService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	noteRepo := mocks.NewMockMessageNoteRepository(ctrl)
+	svc := NewMessageNoteService(noteRepo, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	author := "agent@example.com"
+	noteRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, note *models.MessageNote) error {
+			note.ID = 3
+			return nil
+		})
+
+	note, err := svc.Create(context.Background(), 42, &author, &MessageNoteRequest{Body: "  carrier ticket #123 \n"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if note.ID != 3 || note.MessageID != 42 || note.Body != "carrier ticket #123" || note.Author == nil || *note.Author != author {
+		t.Errorf("Create() = %+v, want note 3 on message 42 by %s with a trimmed body", note, author)
+	}
+
+	// Nothing is stored for a blank or oversized body
+	for _, body := range []string{" ", strings.Repeat("x", models.MaxMessageNoteLength+1)} {
+		var appErr *models.AppError
+		if _, err := svc.Create(context.Background(), 42, nil, &MessageNoteRequest{Body: body}); !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+			t.Errorf("Create() with a %d character body error = %v, want INVALID_INPUT", len(body), err)
+		}
+	}
+}
+
+func TestMessageNoteService_List_UnknownMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	noteRepo := mocks.NewMockMessageNoteRepository(ctrl)
+	messageRepo := mocks.NewMockOutboundMessageRepository(ctrl)
+	svc := NewMessageNoteService(noteRepo, messageRepo, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	// A message of another account is not found rather than listed as empty
+	messageRepo.EXPECT().GetByID(gomock.Any(), int64(42)).Return(nil, models.ErrNotFoundWithMsg("message with ID 42 not found"))
+
+	var appErr *models.AppError
+	if _, err := svc.List(context.Background(), 42); !errors.As(err, &appErr) || appErr.Code != "NOT_FOUND" {
+		t.Errorf("List() error = %v, want NOT_FOUND", err)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
 	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
@@ -12,24 +14,37 @@ import (
 // MessageService handles outbound message business logic
 type MessageService interface {
 	GetByID(ctx context.Context, id int64) (*models.OutboundMessage, error)
+	List(ctx context.Context, filter models.OutboundMessageFilter) (*MessageListResult, error)
+	ListUpdatedSince(ctx context.Context, cursor string, limit int) (*MessageChangesResult, error)
+	ListReceipts(ctx context.Context, since *time.Time, cursor string, limit int) (*ReceiptListResult, error)
 	UpdateStatus(ctx context.Context, id int64, status string, lastError *string) error
 	IncrementRetryCount(ctx context.Context, id int64) error
 	GetPendingMessages(ctx context.Context, limit int) ([]*models.OutboundMessage, error)
+	ClaimPending(ctx context.Context, limit int) ([]*models.OutboundMessage, error)
+	UpdateStatusBatch(ctx context.Context, ids []int64, status string) (int64, error)
+	ResetFailedByCampaign(ctx context.Context, campaignID int64, maxRetry int) ([]int64, error)
+	ListCampaignRecipients(ctx context.Context, campaignID int64, filter models.OutboundMessageFilter) (*RecipientListResult, error)
+	// CampaignBounces counts a campaign's soft and hard bounces and how its
+	// soft bounce re-attempts went
+	CampaignBounces(ctx context.Context, campaignID int64) (*models.BounceStats, error)
 }
 
 type messageService struct {
-	messageRepo repository.OutboundMessageRepository
-	logger      *slog.Logger
+	messageRepo  repository.OutboundMessageRepository
+	campaignRepo repository.CampaignRepository
+	logger       *slog.Logger
 }
 
 // NewMessageService creates a new message service
 func NewMessageService(
 	messageRepo repository.OutboundMessageRepository,
+	campaignRepo repository.CampaignRepository,
 	logger *slog.Logger,
 ) MessageService {
 	return &messageService{
-		messageRepo: messageRepo,
-		logger:      logger,
+		messageRepo:  messageRepo,
+		campaignRepo: campaignRepo,
+		logger:       logger,
 	}
 }
 
@@ -43,6 +58,25 @@ func (s *messageService) GetByID(ctx context.Context, id int64) (*models.Outboun
 	return message, nil
 }
 
+// List retrieves messages filtered by campaign, customer and status, newest first
+func (s *messageService) List(ctx context.Context, filter models.OutboundMessageFilter) (*MessageListResult, error) {
+	if filter.Status != "" && !models.IsValidMessageStatus(filter.Status) {
+		return nil, models.ErrInvalidInput(fmt.Sprintf("invalid status: %s", filter.Status))
+	}
+
+	messages, totalCount, err := s.messageRepo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list messages: %w", err)
+	}
+
+	models.ValidateAndSetDefaults(&filter.Page, &filter.PageSize)
+
+	return &MessageListResult{
+		Data:       messages,
+		Pagination: models.NewPaginationResult(filter.Page, filter.PageSize, totalCount),
+	}, nil
+}
+
 // UpdateStatus updates the status of a message
 func (s *messageService) UpdateStatus(ctx context.Context, id int64, status string, lastError *string) error {
 	if !models.IsValidMessageStatus(status) {
@@ -88,3 +122,105 @@ func (s *messageService) GetPendingMessages(ctx context.Context, limit int) ([]*
 
 	return messages, nil
 }
+
+// ClaimPending atomically claims pending messages for processing
+func (s *messageService) ClaimPending(ctx context.Context, limit int) ([]*models.OutboundMessage, error) {
+	if limit < 1 {
+		return nil, models.ErrInvalidInput("limit must be at least 1")
+	}
+
+	messages, err := s.messageRepo.ClaimPending(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim pending messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// UpdateStatusBatch updates the status of many messages at once
+func (s *messageService) UpdateStatusBatch(ctx context.Context, ids []int64, status string) (int64, error) {
+	if !models.IsValidMessageStatus(status) {
+		return 0, models.ErrInvalidInput(fmt.Sprintf("invalid status: %s", status))
+	}
+
+	updated, err := s.messageRepo.UpdateStatusBatch(ctx, ids, status)
+	if err != nil {
+		s.logger.Error("failed to batch update message status",
+			slog.Int("message_count", len(ids)),
+			slog.String("status", status),
+			slog.String("error", err.Error()),
+		)
+		return 0, fmt.Errorf("failed to batch update message status: %w", err)
+	}
+
+	s.logger.Info("message statuses updated",
+		slog.Int64("updated", updated),
+		slog.String("status", status),
+	)
+
+	return updated, nil
+}
+
+// ResetFailedByCampaign moves retryable failed messages of a campaign back to pending
+func (s *messageService) ResetFailedByCampaign(ctx context.Context, campaignID int64, maxRetry int) ([]int64, error) {
+	if maxRetry < 1 {
+		return nil, models.ErrInvalidInput("max retry must be at least 1")
+	}
+
+	ids, err := s.messageRepo.ResetFailedByCampaign(ctx, campaignID, maxRetry)
+	if err != nil {
+		s.logger.Error("failed to reset failed messages",
+			slog.Int64("campaign_id", campaignID),
+			slog.String("error", err.Error()),
+		)
+		return nil, fmt.Errorf("failed to reset failed messages: %w", err)
+	}
+
+	s.logger.Info("failed messages reset to pending",
+		slog.Int64("campaign_id", campaignID),
+		slog.Int("reset_count", len(ids)),
+	)
+
+	return ids, nil
+}
+
+// ListCampaignRecipients searches the messages of a campaign by recipient phone
+// and status, answering whether a customer got the campaign and what happened
+func (s *messageService) ListCampaignRecipients(ctx context.Context, campaignID int64, filter models.OutboundMessageFilter) (*RecipientListResult, error) {
+	if filter.Status != "" && !models.IsValidMessageStatus(filter.Status) {
+		return nil, models.ErrInvalidInput(fmt.Sprintf("invalid status: %s", filter.Status))
+	}
+
+	if _, err := s.campaignRepo.GetByID(ctx, campaignID); err != nil {
+		return nil, err
+	}
+
+	filter.CampaignID = campaignID
+	filter.Phone = strings.TrimSpace(filter.Phone)
+
+	recipients, totalCount, err := s.messageRepo.ListRecipients(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list campaign recipients: %w", err)
+	}
+
+	models.ValidateAndSetDefaults(&filter.Page, &filter.PageSize)
+
+	return &RecipientListResult{
+		Data:       recipients,
+		Pagination: models.NewPaginationResult(filter.Page, filter.PageSize, totalCount),
+	}, nil
+}
+
+// CampaignBounces counts the bounced messages of a campaign
+func (s *messageService) CampaignBounces(ctx context.Context, campaignID int64) (*models.BounceStats, error) {
+	if _, err := s.campaignRepo.GetByID(ctx, campaignID); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.messageRepo.CountBounces(ctx, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count campaign bounces: %w", err)
+	}
+
+	return stats, nil
+}
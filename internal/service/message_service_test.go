@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestMessageService_ResetFailedByCampaign(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	messageRepo := mocks.NewMockOutboundMessageRepository(ctrl)
+	messageRepo.EXPECT().ResetFailedByCampaign(gomock.Any(), int64(1), 3).Return([]int64{7}, nil)
+
+	svc := NewMessageService(messageRepo, nil, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+	ids, err := svc.ResetFailedByCampaign(context.Background(), 1, 3)
+	if err != nil {
+		t.Fatalf("ResetFailedByCampaign() error = %v", err)
+	}
+	if len(ids) != 1 || ids[0] != 7 {
+		t.Errorf("ResetFailedByCampaign() ids = %v, want [7]", ids)
+	}
+
+	// Invalid arguments are rejected before reaching the repository
+	if _, err := svc.ResetFailedByCampaign(context.Background(), 1, 0); err == nil {
+		t.Error("ResetFailedByCampaign() with maxRetry 0 error = nil, want error")
+	}
+}
+
+func TestMessageService_UpdateStatusBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	messageRepo := mocks.NewMockOutboundMessageRepository(ctrl)
+	messageRepo.EXPECT().
+		UpdateStatusBatch(gomock.Any(), []int64{1, 2, 99}, models.MessageStatusPending).
+		Return(int64(2), nil)
+
+	svc := NewMessageService(messageRepo, nil, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+	updated, err := svc.UpdateStatusBatch(context.Background(), []int64{1, 2, 99}, models.MessageStatusPending)
+	if err != nil {
+		t.Fatalf("UpdateStatusBatch() error = %v", err)
+	}
+	if updated != 2 {
+		t.Errorf("UpdateStatusBatch() updated = %d, want 2", updated)
+	}
+
+	if _, err := svc.UpdateStatusBatch(context.Background(), []int64{1}, "bogus"); err == nil {
+		t.Error("UpdateStatusBatch() with invalid status error = nil, want error")
+	}
+}
+
+func TestMessageService_ClaimPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	messageRepo := mocks.NewMockOutboundMessageRepository(ctrl)
+	messageRepo.EXPECT().ClaimPending(gomock.Any(), 2).Return([]*models.OutboundMessage{
+		{ID: 1, Status: models.MessageStatusSending},
+		{ID: 2, Status: models.MessageStatusSending},
+	}, nil)
+
+	svc := NewMessageService(messageRepo, nil, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+	claimed, err := svc.ClaimPending(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("ClaimPending() error = %v", err)
+	}
+	if len(claimed) != 2 || claimed[0].Status != models.MessageStatusSending {
+		t.Errorf("ClaimPending() = %d messages, want 2 claimed", len(claimed))
+	}
+
+	// A limit below 1 is rejected before reaching the repository
+	if _, err := svc.ClaimPending(context.Background(), 0); err == nil {
+		t.Error("ClaimPending() with limit 0 error = nil, want error")
+	}
+}
+
+func TestMessageService_ListCampaignRecipients(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	messageRepo := mocks.NewMockOutboundMessageRepository(ctrl)
+	campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+
+	campaignRepo.EXPECT().GetByID(gomock.Any(), int64(4)).Return(&models.Campaign{ID: 4}, nil)
+	messageRepo.EXPECT().
+		ListRecipients(gomock.Any(), models.OutboundMessageFilter{CampaignID: 4, Phone: "+254712345001", Status: models.MessageStatusFailed}).
+		Return([]*models.MessageRecipient{{OutboundMessage: &models.OutboundMessage{ID: 9, CampaignID: 4, Status: models.MessageStatusFailed}, Phone: "+254712345001"}}, int64(1), nil)
+
+	svc := NewMessageService(messageRepo, campaignRepo, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+	result, err := svc.ListCampaignRecipients(context.Background(), 4, models.OutboundMessageFilter{Phone: " +254712345001", Status: models.MessageStatusFailed})
+	if err != nil {
+		t.Fatalf("ListCampaignRecipients() error = %v", err)
+	}
+	if len(result.Data) != 1 || result.Data[0].ID != 9 {
+		t.Errorf("Data = %+v, want message 9", result.Data)
+	}
+	if result.Pagination.TotalCount != 1 || result.Pagination.Page != 1 {
+		t.Errorf("Pagination = %+v, want one result on page 1", result.Pagination)
+	}
+
+	if _, err := svc.ListCampaignRecipients(context.Background(), 4, models.OutboundMessageFilter{Status: "bogus"}); err == nil {
+		t.Error("ListCampaignRecipients() with invalid status error = nil, want error")
+	}
+}
+
+func TestMessageService_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	messageRepo := mocks.NewMockOutboundMessageRepository(ctrl)
+	campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+
+	lastError := "provider timeout"
+	messageRepo.EXPECT().
+		List(gomock.Any(), models.OutboundMessageFilter{CustomerID: 3, Status: models.MessageStatusFailed, Page: 2, PageSize: 10}).
+		Return([]*models.OutboundMessage{{ID: 12, CustomerID: 3, Status: models.MessageStatusFailed, LastError: &lastError}}, int64(11), nil)
+
+	svc := NewMessageService(messageRepo, campaignRepo, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+	result, err := svc.List(context.Background(), models.OutboundMessageFilter{CustomerID: 3, Status: models.MessageStatusFailed, Page: 2, PageSize: 10})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(result.Data) != 1 || result.Data[0].LastError == nil || *result.Data[0].LastError != lastError {
+		t.Errorf("Data = %+v, want message 12 with its last error", result.Data)
+	}
+	if result.Pagination.TotalCount != 11 || result.Pagination.Page != 2 {
+		t.Errorf("Pagination = %+v, want 11 results on page 2", result.Pagination)
+	}
+
+	if _, err := svc.List(context.Background(), models.OutboundMessageFilter{Status: "bogus"}); err == nil {
+		t.Error("List() with invalid status error = nil, want error")
+	}
+}
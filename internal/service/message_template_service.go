@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// MessageTemplateService manages the named templates campaigns are created from
+type MessageTemplateService interface {
+	Create(ctx context.Context, req *MessageTemplateRequest) (*models.MessageTemplate, error)
+	GetByID(ctx context.Context, id int64) (*models.MessageTemplate, error)
+	List(ctx context.Context) ([]*models.MessageTemplate, error)
+	// Update renames a template or changes its content. New content is a new
+	// version, which unsent campaigns still on the previous version follow.
+	Update(ctx context.Context, id int64, req *MessageTemplateRequest) (*models.MessageTemplate, error)
+	Delete(ctx context.Context, id int64) error
+	ListVersions(ctx context.Context, id int64) ([]*models.MessageTemplateVersion, error)
+}
+
+type messageTemplateService struct {
+	templateRepo repository.MessageTemplateRepository
+	templateSvc  TemplateService
+	logger       *slog.Logger
+}
+
+// NewMessageTemplateService creates a new message template service
+func NewMessageTemplateService(
+	templateRepo repository.MessageTemplateRepository,
+	templateSvc TemplateService,
+	logger *slog.Logger,
+) MessageTemplateService {
+	return &messageTemplateService{
+		templateRepo: templateRepo,
+		templateSvc:  templateSvc,
+		logger:       logger,
+	}
+}
+
+// Create saves a new template at version 1
+func (s *messageTemplateService) Create(ctx context.Context, req *MessageTemplateRequest) (*models.MessageTemplate, error) {
+	template := &models.MessageTemplate{
+		Name:    strings.TrimSpace(req.Name),
+		Content: req.Content,
+	}
+	if err := s.validate(ctx, template); err != nil {
+		return nil, err
+	}
+
+	if err := s.templateRepo.Create(ctx, template); err != nil {
+		s.logger.Error("failed to create message template",
+			slog.String("name", template.Name),
+			slog.String("error", err.Error()),
+		)
+		return nil, err
+	}
+
+	s.logger.Info("message template created",
+		slog.Int64("template_id", template.ID),
+		slog.String("name", template.Name),
+	)
+
+	return template, nil
+}
+
+// GetByID retrieves a template at its latest version
+func (s *messageTemplateService) GetByID(ctx context.Context, id int64) (*models.MessageTemplate, error) {
+	return s.templateRepo.GetByID(ctx, id)
+}
+
+// List retrieves every template
+func (s *messageTemplateService) List(ctx context.Context) ([]*models.MessageTemplate, error) {
+	return s.templateRepo.List(ctx)
+}
+
+// Update saves a template's name and content
+func (s *messageTemplateService) Update(ctx context.Context, id int64, req *MessageTemplateRequest) (*models.MessageTemplate, error) {
+	template := &models.MessageTemplate{
+		ID:      id,
+		Name:    strings.TrimSpace(req.Name),
+		Content: req.Content,
+	}
+	if err := s.validate(ctx, template); err != nil {
+		return nil, err
+	}
+
+	campaigns, err := s.templateRepo.Update(ctx, template)
+	if err != nil {
+		s.logger.Error("failed to update message template",
+			slog.Int64("template_id", id),
+			slog.String("error", err.Error()),
+		)
+		return nil, err
+	}
+
+	s.logger.Info("message template updated",
+		slog.Int64("template_id", id),
+		slog.Int("version", template.Version),
+		slog.Int64("campaigns_updated", campaigns),
+	)
+
+	return template, nil
+}
+
+// Delete removes a template; campaigns created from it keep their content
+func (s *messageTemplateService) Delete(ctx context.Context, id int64) error {
+	if err := s.templateRepo.Delete(ctx, id); err != nil {
+		s.logger.Error("failed to delete message template",
+			slog.Int64("template_id", id),
+			slog.String("error", err.Error()),
+		)
+		return err
+	}
+
+	s.logger.Info("message template deleted", slog.Int64("template_id", id))
+
+	return nil
+}
+
+// ListVersions retrieves every version of a template, newest first
+func (s *messageTemplateService) ListVersions(ctx context.Context, id int64) ([]*models.MessageTemplateVersion, error) {
+	return s.templateRepo.ListVersions(ctx, id)
+}
+
+// validate checks the template and its content as a campaign's base template
+// would be checked, with partials expanded
+func (s *messageTemplateService) validate(ctx context.Context, template *models.MessageTemplate) error {
+	if err := template.Validate(); err != nil {
+		return err
+	}
+	expanded, err := s.templateSvc.ExpandPartials(ctx, template.Content)
+	if err != nil {
+		return err
+	}
+	return s.templateSvc.ValidateTemplate(ctx, expanded)
+}
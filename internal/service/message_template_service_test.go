@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestMessageTemplateService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	templateRepo := mocks.NewMockMessageTemplateRepository(ctrl)
+	templateSvc := NewTemplateService(mocks.NewMockTemplatePartialRepository(ctrl), nil)
+	svc := NewMessageTemplateService(templateRepo, templateSvc, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	templateRepo.EXPECT().Create(gomock.Any(), &models.MessageTemplate{Name: "Welcome", Content: "Hi {first_name}"}).Return(nil)
+	if _, err := svc.Create(context.Background(), &MessageTemplateRequest{Name: " Welcome ", Content: "Hi {first_name}"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	invalid := []*MessageTemplateRequest{
+		{Name: " ", Content: "Hi"},
+		{Name: "Welcome"},
+		{Name: "Welcome", Content: "Hi {nickname}"},
+	}
+	for _, req := range invalid {
+		var appErr *models.AppError
+		if _, err := svc.Create(context.Background(), req); !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+			t.Errorf("Create(%+v) error = %v, want INVALID_INPUT", req, err)
+		}
+	}
+}
+
+func TestCampaignService_Create_FromTemplate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	templateRepo := mocks.NewMockMessageTemplateRepository(ctrl)
+	svc := &campaignService{
+		templateSvc: NewTemplateService(mocks.NewMockTemplatePartialRepository(ctrl), nil),
+		config:      CampaignServiceConfig{Templates: templateRepo},
+		logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	templateID := int64(7)
+	templateRepo.EXPECT().GetByID(gomock.Any(), templateID).
+		Return(&models.MessageTemplate{ID: templateID, Name: "Welcome", Content: "Hi {first_name}, welcome!", Version: 3}, nil)
+
+	campaign, err := svc.newCampaign(context.Background(), &CreateCampaignRequest{Name: "Welcome", Channel: models.ChannelSMS, TemplateID: &templateID})
+	if err != nil {
+		t.Fatalf("newCampaign() error = %v", err)
+	}
+	if campaign.BaseTemplate != "Hi {first_name}, welcome!" || campaign.TemplateVersion == nil || *campaign.TemplateVersion != 3 {
+		t.Errorf("campaign template = %q at version %v, want the template's latest version", campaign.BaseTemplate, campaign.TemplateVersion)
+	}
+
+	// An older version can be pinned
+	version := 1
+	templateRepo.EXPECT().GetVersion(gomock.Any(), templateID, 1).
+		Return(&models.MessageTemplateVersion{TemplateID: templateID, Version: 1, Content: "Hi {first_name}"}, nil)
+	campaign, err = svc.newCampaign(context.Background(), &CreateCampaignRequest{Name: "Welcome", Channel: models.ChannelSMS, TemplateID: &templateID, TemplateVersion: &version})
+	if err != nil {
+		t.Fatalf("newCampaign(version 1) error = %v", err)
+	}
+	if campaign.BaseTemplate != "Hi {first_name}" || *campaign.TemplateVersion != 1 {
+		t.Errorf("campaign template = %q at version %d, want version 1", campaign.BaseTemplate, *campaign.TemplateVersion)
+	}
+
+	// A missing template is a bad request
+	templateRepo.EXPECT().GetByID(gomock.Any(), int64(8)).Return(nil, models.ErrNotFoundWithMsg("template with ID 8 not found"))
+	missing := int64(8)
+	var appErr *models.AppError
+	_, err = svc.newCampaign(context.Background(), &CreateCampaignRequest{Name: "Welcome", Channel: models.ChannelSMS, TemplateID: &missing})
+	if !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+		t.Errorf("newCampaign(missing template) error = %v, want INVALID_INPUT", err)
+	}
+
+	// A campaign takes an inline template or a template ID, not both
+	_, err = svc.newCampaign(context.Background(), &CreateCampaignRequest{Name: "Welcome", Channel: models.ChannelSMS, BaseTemplate: "Hi", TemplateID: &templateID})
+	if !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+		t.Errorf("newCampaign(both templates) error = %v, want INVALID_INPUT", err)
+	}
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/queue"
+)
+
+// messageStore holds the outbound messages behind a generated repository
+// mock and records the messages marked queued
+type messageStore struct {
+	all    []*models.OutboundMessage
+	queued []int64
+}
+
+// repo returns a mock repository reading and updating the store
+func (s *messageStore) repo(t *testing.T) *mocks.MockOutboundMessageRepository {
+	return s.expect(mocks.NewMockOutboundMessageRepository(gomock.NewController(t)))
+}
+
+// expect sets up repo to read and update the store. Expectations set up on
+// repo before take precedence.
+func (s *messageStore) expect(repo *mocks.MockOutboundMessageRepository) *mocks.MockOutboundMessageRepository {
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(s.create).AnyTimes()
+	repo.EXPECT().CreateBatch(gomock.Any(), gomock.Any()).DoAndReturn(s.createBatch).AnyTimes()
+	repo.EXPECT().GetByID(gomock.Any(), gomock.Any()).DoAndReturn(s.getByID).AnyTimes()
+	repo.EXPECT().CountByCampaign(gomock.Any(), gomock.Any()).DoAndReturn(s.countByCampaign).AnyTimes()
+	repo.EXPECT().CountByCustomer(gomock.Any(), gomock.Any()).DoAndReturn(s.countByCustomer).AnyTimes()
+	repo.EXPECT().MessagedSince(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(s.messagedSince).AnyTimes()
+	repo.EXPECT().ListFailedRecipients(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(s.listFailedRecipients).AnyTimes()
+	repo.EXPECT().ListByCampaignAfterID(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(s.listByCampaignAfterID).AnyTimes()
+	repo.EXPECT().UpdateStatusBatch(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(s.updateStatusBatch).AnyTimes()
+	repo.EXPECT().ResetFailedByCampaign(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(s.resetFailedByCampaign).AnyTimes()
+	repo.EXPECT().MarkQueued(gomock.Any(), gomock.Any()).DoAndReturn(s.markQueued).AnyTimes()
+	repo.EXPECT().SkipPendingByCampaign(gomock.Any(), gomock.Any()).DoAndReturn(s.skipPendingByCampaign).AnyTimes()
+	repo.EXPECT().ApplyDeliveryReport(gomock.Any(), gomock.Any()).DoAndReturn(s.applyDeliveryReport).AnyTimes()
+	return repo
+}
+
+func (s *messageStore) create(ctx context.Context, message *models.OutboundMessage) error {
+	message.ID = int64(len(s.all) + 1)
+	s.all = append(s.all, message)
+	return nil
+}
+
+func (s *messageStore) createBatch(ctx context.Context, messages []*models.OutboundMessage) error {
+	for _, message := range messages {
+		_ = s.create(ctx, message)
+	}
+	return nil
+}
+
+func (s *messageStore) getByID(ctx context.Context, id int64) (*models.OutboundMessage, error) {
+	for _, msg := range s.all {
+		if msg.ID == id {
+			return msg, nil
+		}
+	}
+	return nil, models.ErrNotFoundWithMsg("message not found")
+}
+
+func (s *messageStore) countByCampaign(ctx context.Context, campaignID int64) (int64, error) {
+	var count int64
+	for _, msg := range s.all {
+		if msg.CampaignID == campaignID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *messageStore) countByCustomer(ctx context.Context, customerID int64) (int64, error) {
+	var count int64
+	for _, msg := range s.all {
+		if msg.CustomerID == customerID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *messageStore) messagedSince(ctx context.Context, customerIDs []int64, since time.Time) ([]int64, error) {
+	messaged := []int64{}
+	for _, msg := range s.all {
+		if slices.Contains(customerIDs, msg.CustomerID) && !slices.Contains(messaged, msg.CustomerID) && !msg.CreatedAt.Before(since) &&
+			msg.Status != models.MessageStatusFailed && msg.Status != models.MessageStatusSkipped {
+			messaged = append(messaged, msg.CustomerID)
+		}
+	}
+	return messaged, nil
+}
+
+func (s *messageStore) listFailedRecipients(ctx context.Context, campaignID, afterID int64, limit int) ([]*models.FailedRecipient, error) {
+	page := []*models.FailedRecipient{}
+	for _, msg := range s.all {
+		failed := msg.Status == models.MessageStatusFailed || msg.Status == models.MessageStatusUndelivered
+		if msg.CampaignID == campaignID && failed && msg.ID > afterID && len(page) < limit {
+			page = append(page, &models.FailedRecipient{
+				MessageID:  msg.ID,
+				CustomerID: msg.CustomerID,
+				Status:     msg.Status,
+				LastError:  msg.LastError,
+				RetryCount: msg.RetryCount,
+			})
+		}
+	}
+	return page, nil
+}
+
+func (s *messageStore) listByCampaignAfterID(ctx context.Context, campaignID, afterID int64, limit int) ([]*models.OutboundMessage, error) {
+	page := []*models.OutboundMessage{}
+	for _, msg := range s.all {
+		if msg.CampaignID == campaignID && msg.ID > afterID && len(page) < limit {
+			page = append(page, msg)
+		}
+	}
+	return page, nil
+}
+
+func (s *messageStore) updateStatusBatch(ctx context.Context, ids []int64, status string) (int64, error) {
+	var updated int64
+	for _, id := range ids {
+		if msg, err := s.getByID(ctx, id); err == nil {
+			msg.Status = status
+			updated++
+		}
+	}
+	return updated, nil
+}
+
+func (s *messageStore) resetFailedByCampaign(ctx context.Context, campaignID int64, maxRetry int) ([]int64, error) {
+	ids := []int64{}
+	for _, msg := range s.all {
+		if msg.CampaignID == campaignID && msg.Status == models.MessageStatusFailed && msg.RetryCount < maxRetry {
+			msg.Status = models.MessageStatusPending
+			msg.LastError = nil
+			ids = append(ids, msg.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (s *messageStore) markQueued(ctx context.Context, ids []int64) error {
+	s.queued = append(s.queued, ids...)
+	return nil
+}
+func (s *messageStore) skipPendingByCampaign(ctx context.Context, campaignID int64) (int64, error) {
+	var skipped int64
+	for _, msg := range s.all {
+		if msg.CampaignID == campaignID && msg.Status == models.MessageStatusPending {
+			msg.Status = models.MessageStatusSkipped
+			skipped++
+		}
+	}
+	return skipped, nil
+}
+func (s *messageStore) applyDeliveryReport(ctx context.Context, report models.DeliveryReport) (bool, error) {
+	for _, msg := range s.all {
+		if msg.ProviderMessageID != nil && *msg.ProviderMessageID == report.ProviderMessageID && msg.WasSent() {
+			msg.Status = report.Status
+			msg.LastError = report.Reason
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// mockQueueClient records published jobs
+type mockQueueClient struct {
+	published []*models.MessageJob
+}
+
+func (m *mockQueueClient) Publish(ctx context.Context, job *models.MessageJob) error {
+	m.published = append(m.published, job)
+	return nil
+}
+func (m *mockQueueClient) PublishDelayed(ctx context.Context, job *models.MessageJob, at time.Time) error {
+	return nil
+}
+func (m *mockQueueClient) Consume(ctx context.Context, handler queue.MessageHandler, concurrency int) error {
+	return nil
+}
+func (m *mockQueueClient) Ack(ctx context.Context, job *models.MessageJob) error {
+	return nil
+}
+func (m *mockQueueClient) Nack(ctx context.Context, job *models.MessageJob) error {
+	return nil
+}
+func (m *mockQueueClient) Close() error {
+	return nil
+}
+func (m *mockQueueClient) Health(ctx context.Context) error {
+	return nil
+}
+func (m *mockQueueClient) Depth(ctx context.Context) (*queue.Depth, error) {
+	return &queue.Depth{Ready: int64(len(m.published))}, nil
+}
+func (m *mockQueueClient) Lag(ctx context.Context) (time.Duration, error) {
+	return 0, nil
+}
+func (m *mockQueueClient) Maintenance(ctx context.Context) (*queue.Maintenance, error) {
+	return &queue.Maintenance{}, nil
+}
+func (m *mockQueueClient) SetMaintenance(ctx context.Context, reason string) (*queue.Maintenance, error) {
+	return &queue.Maintenance{Enabled: true, Reason: reason}, nil
+}
+func (m *mockQueueClient) ClearMaintenance(ctx context.Context) error {
+	return nil
+}
+func (m *mockQueueClient) ListQuarantined(ctx context.Context, limit int) ([]queue.QuarantinedJob, error) {
+	return nil, nil
+}
+func (m *mockQueueClient) PurgeQuarantine(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+func (m *mockQueueClient) RemoveCampaignJobs(ctx context.Context, campaignID int64) (int64, error) {
+	return 0, nil
+}
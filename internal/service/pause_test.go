@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestCampaignService_Pause(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+	messageRepo := mocks.NewMockOutboundMessageRepository(ctrl)
+	queueClient := mocks.NewMockClient(ctrl)
+
+	campaignRepo.EXPECT().GetByID(gomock.Any(), int64(1)).
+		Return(&models.Campaign{ID: 1, AccountID: 1, Status: models.CampaignStatusSending}, nil)
+	// The messages are marked unqueued before their jobs are removed, so
+	// Resume publishes them again
+	gomock.InOrder(
+		campaignRepo.EXPECT().PauseSending(gomock.Any(), int64(1), "wrong discount code").Return(true, nil),
+		messageRepo.EXPECT().UnqueueByCampaign(gomock.Any(), int64(1)).Return(int64(12), nil),
+		queueClient.EXPECT().RemoveCampaignJobs(gomock.Any(), int64(1)).Return(int64(12), nil),
+	)
+
+	svc := &campaignService{
+		campaignRepo: campaignRepo,
+		messageRepo:  messageRepo,
+		queueClient:  queueClient,
+		logger:       slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	}
+
+	result, err := svc.Pause(context.Background(), 1, &PauseCampaignRequest{Reason: "  wrong discount code "})
+	if err != nil {
+		t.Fatalf("Pause() error = %v", err)
+	}
+	want := PauseCampaignResult{CampaignID: 1, JobsRemoved: 12, Status: models.CampaignStatusPaused, Reason: "wrong discount code"}
+	if *result != want {
+		t.Errorf("Pause() = %+v, want %+v", *result, want)
+	}
+}
+
+func TestCampaignService_Pause_DefaultReason(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+	messageRepo := mocks.NewMockOutboundMessageRepository(ctrl)
+	queueClient := mocks.NewMockClient(ctrl)
+
+	campaignRepo.EXPECT().GetByID(gomock.Any(), int64(1)).
+		Return(&models.Campaign{ID: 1, Status: models.CampaignStatusSending}, nil)
+	campaignRepo.EXPECT().PauseSending(gomock.Any(), int64(1), defaultPauseReason).Return(true, nil)
+	messageRepo.EXPECT().UnqueueByCampaign(gomock.Any(), int64(1)).Return(int64(3), nil)
+	// The pause stands even when the queue cannot be cleaned up
+	queueClient.EXPECT().RemoveCampaignJobs(gomock.Any(), int64(1)).Return(int64(0), errors.New("redis down"))
+
+	svc := &campaignService{
+		campaignRepo: campaignRepo,
+		messageRepo:  messageRepo,
+		queueClient:  queueClient,
+		logger:       slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	}
+
+	result, err := svc.Pause(context.Background(), 1, &PauseCampaignRequest{})
+	if err != nil {
+		t.Fatalf("Pause() error = %v", err)
+	}
+	if result.Reason != defaultPauseReason {
+		t.Errorf("Pause() reason = %q, want %q", result.Reason, defaultPauseReason)
+	}
+}
+
+func TestCampaignService_Pause_UnqueueFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+	messageRepo := mocks.NewMockOutboundMessageRepository(ctrl)
+	queueClient := mocks.NewMockClient(ctrl)
+
+	// The jobs are left for workers to drop, which marks their messages
+	// unqueued; removing them would leave nothing for Resume to publish
+	campaignRepo.EXPECT().GetByID(gomock.Any(), int64(1)).
+		Return(&models.Campaign{ID: 1, Status: models.CampaignStatusSending}, nil)
+	campaignRepo.EXPECT().PauseSending(gomock.Any(), int64(1), defaultPauseReason).Return(true, nil)
+	messageRepo.EXPECT().UnqueueByCampaign(gomock.Any(), int64(1)).Return(int64(0), errors.New("connection reset"))
+
+	svc := &campaignService{
+		campaignRepo: campaignRepo,
+		messageRepo:  messageRepo,
+		queueClient:  queueClient,
+		logger:       slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	}
+
+	result, err := svc.Pause(context.Background(), 1, &PauseCampaignRequest{})
+	if err != nil {
+		t.Fatalf("Pause() error = %v", err)
+	}
+	if result.JobsRemoved != 0 || result.Status != models.CampaignStatusPaused {
+		t.Errorf("Pause() = %+v, want paused with no jobs removed", *result)
+	}
+}
+
+func TestCampaignService_Pause_AwaitingWindow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+	messageRepo := mocks.NewMockOutboundMessageRepository(ctrl)
+	queueClient := mocks.NewMockClient(ctrl)
+
+	// Pausing a campaign that waits for its next delivery window holds it there
+	resumeAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	windowReason := "outside delivery windows"
+	campaignRepo.EXPECT().GetByID(gomock.Any(), int64(1)).
+		Return(&models.Campaign{ID: 1, Status: models.CampaignStatusPaused, PausedReason: &windowReason, ResumeAt: &resumeAt}, nil)
+	campaignRepo.EXPECT().PauseSending(gomock.Any(), int64(1), "hold for legal review").Return(true, nil)
+	messageRepo.EXPECT().UnqueueByCampaign(gomock.Any(), int64(1)).Return(int64(0), nil)
+	queueClient.EXPECT().RemoveCampaignJobs(gomock.Any(), int64(1)).Return(int64(0), nil)
+
+	svc := &campaignService{
+		campaignRepo: campaignRepo,
+		messageRepo:  messageRepo,
+		queueClient:  queueClient,
+		logger:       slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	}
+
+	result, err := svc.Pause(context.Background(), 1, &PauseCampaignRequest{Reason: "hold for legal review"})
+	if err != nil {
+		t.Fatalf("Pause() error = %v", err)
+	}
+	if result.Status != models.CampaignStatusPaused || result.Reason != "hold for legal review" {
+		t.Errorf("Pause() = %+v, want paused for legal review", *result)
+	}
+}
+
+func TestCampaignService_Pause_NotSending(t *testing.T) {
+	for _, status := range []string{models.CampaignStatusDraft, models.CampaignStatusPaused, models.CampaignStatusSent} {
+		t.Run(status, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+
+			// Nothing is touched when the campaign is not sending
+			campaignRepo.EXPECT().GetByID(gomock.Any(), int64(2)).
+				Return(&models.Campaign{ID: 2, Status: status}, nil)
+
+			svc := &campaignService{
+				campaignRepo: campaignRepo,
+				logger:       slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+			}
+
+			_, err := svc.Pause(context.Background(), 2, &PauseCampaignRequest{})
+			var appErr *models.AppError
+			if !errors.As(err, &appErr) || appErr.Code != "CONFLICT" {
+				t.Errorf("Pause() of a %s campaign error = %v, want CONFLICT", status, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestCampaignService_Prebuild(t *testing.T) {
+	scheduledAt := time.Now().Add(30 * time.Minute)
+	campaigns := &campaignStore{
+		all: []*models.Campaign{
+			{ID: 1, Status: models.CampaignStatusScheduled, BaseTemplate: "Hi {first_name}", ScheduledAt: &scheduledAt, Audience: &models.CampaignAudience{CustomerIDs: []int64{2, 4, 6}}},
+		},
+	}
+	messages := &messageStore{}
+	queueClient := &mockQueueClient{}
+
+	svc := NewCampaignService(
+		campaigns.repo(t),
+		(&customerStore{byID: newTestCustomers(10)}).repo(t),
+		messages.repo(t),
+		nil,
+		NewTemplateService(nil, nil),
+		queueClient,
+		CampaignServiceConfig{SendBatchSize: 10, RenderConcurrency: 1},
+		slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	)
+
+	// Building ahead renders the messages without queueing them
+	built, err := svc.Prebuild(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Prebuild() error = %v", err)
+	}
+	if built.MessagesBuilt != 3 || len(messages.all) != 3 {
+		t.Errorf("messages built = %d, created = %d, want 3", built.MessagesBuilt, len(messages.all))
+	}
+	if len(queueClient.published) != 0 {
+		t.Errorf("jobs published = %d, want none before scheduled_at", len(queueClient.published))
+	}
+	if got := campaigns.all[0].Status; got != models.CampaignStatusReady {
+		t.Errorf("campaign status = %s, want ready", got)
+	}
+
+	// The audience is fixed once built
+	var appErr *models.AppError
+	_, err = svc.SendCampaign(context.Background(), 1, &SendCampaignRequest{CustomerIDs: []int64{8}})
+	if !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+		t.Errorf("SendCampaign() with recipients error = %v, want INVALID_INPUT", err)
+	}
+
+	// Sending a ready campaign only queues the built messages
+	sent, err := svc.SendCampaign(context.Background(), 1, &SendCampaignRequest{})
+	if err != nil {
+		t.Fatalf("SendCampaign() error = %v", err)
+	}
+	if sent.MessagesQueued != 3 || len(queueClient.published) != 3 {
+		t.Errorf("messages queued = %d, published = %d, want 3", sent.MessagesQueued, len(queueClient.published))
+	}
+	if len(messages.all) != 3 {
+		t.Errorf("messages created = %d, want the 3 built ahead", len(messages.all))
+	}
+	if got := campaigns.all[0].Status; got != models.CampaignStatusSending {
+		t.Errorf("campaign status = %s, want sending", got)
+	}
+
+	// Only scheduled campaigns are built ahead
+	_, err = svc.Prebuild(context.Background(), 1)
+	if !errors.Is(err, models.ErrConflict) {
+		t.Errorf("Prebuild() of a sending campaign error = %v, want conflict", err)
+	}
+}
+
+func TestCreateCampaignRequest_Validate_Prebuild(t *testing.T) {
+	scheduledAt := time.Now().Add(time.Hour)
+	audience := &models.CampaignAudience{Target: "all"}
+	minutes := func(n int) *int { return &n }
+
+	tests := []struct {
+		name    string
+		req     CreateCampaignRequest
+		wantErr bool
+	}{
+		{name: "scheduled with audience", req: CreateCampaignRequest{ScheduledAt: &scheduledAt, Audience: audience, PrebuildMinutes: minutes(30)}},
+		{name: "not scheduled", req: CreateCampaignRequest{Audience: audience, PrebuildMinutes: minutes(30)}, wantErr: true},
+		{name: "no audience", req: CreateCampaignRequest{ScheduledAt: &scheduledAt, PrebuildMinutes: minutes(30)}, wantErr: true},
+		{name: "zero minutes", req: CreateCampaignRequest{ScheduledAt: &scheduledAt, Audience: audience, PrebuildMinutes: minutes(0)}, wantErr: true},
+		{name: "over a day", req: CreateCampaignRequest{ScheduledAt: &scheduledAt, Audience: audience, PrebuildMinutes: minutes(maxPrebuildMinutes + 1)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.Name = "Launch"
+			tt.req.Channel = models.ChannelSMS
+			tt.req.BaseTemplate = "Hi {first_name}"
+
+			err := tt.req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// PreviewLinkService issues tokenized links to a campaign's device preview,
+// so reviewers without API access can sign off its content
+type PreviewLinkService interface {
+	Create(ctx context.Context, campaignID int64, req *PreviewLinkRequest) (*PreviewLinkResult, error)
+	// Revoke invalidates every preview link of a campaign
+	Revoke(ctx context.Context, campaignID int64) error
+	// Render returns the campaign's message as the link's holder sees it
+	Render(ctx context.Context, token string) (*DevicePreview, error)
+}
+
+type previewLinkService struct {
+	linkRepo     repository.PreviewLinkRepository
+	campaignRepo repository.CampaignRepository
+	templateSvc  TemplateService
+	publicURL    string
+	logger       *slog.Logger
+	now          func() time.Time
+}
+
+// NewPreviewLinkService creates a new preview link service. publicURL is the
+// base URL links are built on.
+func NewPreviewLinkService(
+	linkRepo repository.PreviewLinkRepository,
+	campaignRepo repository.CampaignRepository,
+	templateSvc TemplateService,
+	publicURL string,
+	logger *slog.Logger,
+) PreviewLinkService {
+	return &previewLinkService{
+		linkRepo:     linkRepo,
+		campaignRepo: campaignRepo,
+		templateSvc:  templateSvc,
+		publicURL:    publicURL,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// Create issues a new preview link for a campaign
+func (s *previewLinkService) Create(ctx context.Context, campaignID int64, req *PreviewLinkRequest) (*PreviewLinkResult, error) {
+	ttl, err := req.ttl()
+	if err != nil {
+		return nil, err
+	}
+
+	// Only link to campaigns that exist
+	if _, err := s.campaignRepo.GetByID(ctx, campaignID); err != nil {
+		return nil, err
+	}
+
+	token, err := models.NewPreviewToken()
+	if err != nil {
+		return nil, err
+	}
+
+	link := &models.PreviewLink{
+		Token:      token,
+		CampaignID: campaignID,
+		ExpiresAt:  s.now().UTC().Add(ttl).Truncate(time.Second),
+	}
+	if err := s.linkRepo.Create(ctx, link); err != nil {
+		s.logger.Error("failed to create preview link",
+			slog.Int64("campaign_id", campaignID),
+			slog.String("error", err.Error()),
+		)
+		return nil, err
+	}
+
+	s.logger.Info("preview link created",
+		slog.Int64("campaign_id", campaignID),
+		slog.Time("expires_at", link.ExpiresAt),
+	)
+
+	return &PreviewLinkResult{
+		PreviewLink: link,
+		URL:         s.publicURL + "/preview/" + token,
+	}, nil
+}
+
+// Revoke deletes a campaign's preview links
+func (s *previewLinkService) Revoke(ctx context.Context, campaignID int64) error {
+	if _, err := s.campaignRepo.GetByID(ctx, campaignID); err != nil {
+		return err
+	}
+
+	revoked, err := s.linkRepo.DeleteByCampaignID(ctx, campaignID)
+	if err != nil {
+		s.logger.Error("failed to revoke preview links",
+			slog.Int64("campaign_id", campaignID),
+			slog.String("error", err.Error()),
+		)
+		return err
+	}
+
+	s.logger.Info("preview links revoked",
+		slog.Int64("campaign_id", campaignID),
+		slog.Int64("revoked", revoked),
+	)
+
+	return nil
+}
+
+// Render renders the linked campaign's current template for every synthetic
+// persona. Real customer data is never shown, since anyone with the link can
+// see the page.
+func (s *previewLinkService) Render(ctx context.Context, token string) (*DevicePreview, error) {
+	link, err := s.linkRepo.GetByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	// Expired and unknown links are indistinguishable to the holder
+	if link.Expired(s.now()) {
+		return nil, models.ErrNotFoundWithMsg("preview link not found")
+	}
+	// The page is public, so the link decides whose campaign and partials it reads
+	ctx = models.WithAccountID(ctx, link.AccountID)
+
+	campaign, err := s.campaignRepo.GetByID(ctx, link.CampaignID)
+	if errors.Is(err, models.ErrNotFound) {
+		return nil, models.ErrNotFoundWithMsg("preview link not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &DevicePreview{
+		CampaignID: campaign.ID,
+		Name:       campaign.Name,
+		Channel:    campaign.Channel,
+		SenderID:   campaign.SenderID,
+		ExpiresAt:  link.ExpiresAt,
+		Messages:   make([]*DeviceMessage, 0, len(syntheticPersonas)),
+	}
+
+	template, err := s.templateSvc.ExpandPartials(ctx, campaign.BaseTemplate)
+	if err != nil {
+		return nil, err
+	}
+	compiled := s.templateSvc.Compile(template).ForCampaign(campaign)
+	for _, p := range syntheticPersonas {
+		customer := p.customer
+		rendered, err := compiled.Render(&customer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render persona %s: %w", p.name, err)
+		}
+
+		message := &DeviceMessage{
+			Persona:     p.name,
+			Description: p.description,
+			Length:      utf8.RuneCountInString(rendered),
+			Parts:       []string{rendered},
+		}
+		// Only SMS is split into segments on the handset
+		if campaign.Channel == models.ChannelSMS {
+			message.Encoding, _, _ = models.SMSEncoding(rendered)
+			message.Parts = models.SplitSMSSegments(rendered)
+		}
+		preview.Messages = append(preview.Messages, message)
+	}
+
+	return preview, nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestPreviewLinkService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	linkRepo := mocks.NewMockPreviewLinkRepository(ctrl)
+	campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+	svc := NewPreviewLinkService(linkRepo, campaignRepo, NewTemplateService(nil, nil), "https://campaigns.example.com", slog.New(slog.NewTextHandler(io.Discard, nil))).(*previewLinkService)
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	campaignRepo.EXPECT().GetByID(gomock.Any(), int64(7)).Return(&models.Campaign{ID: 7}, nil)
+	linkRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	link, err := svc.Create(context.Background(), 7, &PreviewLinkRequest{ExpiresInHours: 24})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if link.URL != "https://campaigns.example.com/preview/"+link.Token || len(link.Token) != 48 {
+		t.Errorf("Create() URL = %q, want the public URL with a 48 character token", link.URL)
+	}
+	if want := now.Add(24 * time.Hour); !link.ExpiresAt.Equal(want) {
+		t.Errorf("Create() expires_at = %v, want %v", link.ExpiresAt, want)
+	}
+
+	for _, hours := range []int{-1, 24*30 + 1} {
+		var appErr *models.AppError
+		if _, err := svc.Create(context.Background(), 7, &PreviewLinkRequest{ExpiresInHours: hours}); !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+			t.Errorf("Create(expires_in_hours=%d) error = %v, want INVALID_INPUT", hours, err)
+		}
+	}
+}
+
+func TestPreviewLinkService_Render(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	linkRepo := mocks.NewMockPreviewLinkRepository(ctrl)
+	campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+	svc := NewPreviewLinkService(linkRepo, campaignRepo, NewTemplateService(nil, nil), "", slog.New(slog.NewTextHandler(io.Discard, nil))).(*previewLinkService)
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	linkRepo.EXPECT().GetByToken(gomock.Any(), "live").
+		Return(&models.PreviewLink{Token: "live", CampaignID: 7, ExpiresAt: now.Add(time.Hour)}, nil)
+	linkRepo.EXPECT().GetByToken(gomock.Any(), "expired").
+		Return(&models.PreviewLink{Token: "expired", CampaignID: 7, ExpiresAt: now}, nil)
+	campaignRepo.EXPECT().GetByID(gomock.Any(), int64(7)).Return(&models.Campaign{
+		ID:           7,
+		Name:         "Autumn",
+		Channel:      models.ChannelSMS,
+		BaseTemplate: "Hi {first_name}, " + strings.Repeat("a", 160),
+	}, nil)
+
+	preview, err := svc.Render(context.Background(), "live")
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if len(preview.Messages) != len(syntheticPersonas) {
+		t.Fatalf("Render() = %d messages, want one per persona", len(preview.Messages))
+	}
+	for _, message := range preview.Messages {
+		if message.Persona == "short_name" && (len(message.Parts) != 2 || message.Encoding != models.SMSEncodingGSM7) {
+			t.Errorf("short_name = %d %s parts, want 2 GSM-7 parts", len(message.Parts), message.Encoding)
+		}
+		if message.Persona == "unicode_name" && message.Encoding != models.SMSEncodingUCS2 {
+			t.Errorf("unicode_name encoding = %s, want UCS-2", message.Encoding)
+		}
+	}
+
+	if _, err := svc.Render(context.Background(), "expired"); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("Render() of an expired link error = %v, want not found", err)
+	}
+}
@@ -5,8 +5,14 @@ import (
 	"errors"
 	"log/slog"
 	"os"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
 	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
 )
 
@@ -139,22 +145,22 @@ func TestCampaignService_PreviewPersonalized(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Setup mocks
-			mockCampaignRepo := &mockCampaignRepository{
-				campaigns: []*models.Campaign{tt.campaign},
+			campaigns := &campaignStore{
+				all: []*models.Campaign{tt.campaign},
 			}
 
-			mockCustomerRepo := &mockCustomerRepository{
-				customers: map[int64]*models.Customer{
+			customers := &customerStore{
+				byID: map[int64]*models.Customer{
 					tt.customer.ID: tt.customer,
 				},
 			}
 
-			templateSvc := NewTemplateService()
+			templateSvc := NewTemplateService(nil, nil)
 			logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
 
 			svc := &campaignService{
-				campaignRepo: mockCampaignRepo,
-				customerRepo: mockCustomerRepo,
+				campaignRepo: campaigns.repo(t),
+				customerRepo: customers.repo(t),
 				templateSvc:  templateSvc,
 				logger:       logger,
 			}
@@ -203,18 +209,18 @@ func TestCampaignService_PreviewPersonalized_Errors(t *testing.T) {
 		name         string
 		campaignID   int64
 		customerID   int64
-		setupMocks   func() (*mockCampaignRepository, *mockCustomerRepository)
+		setupMocks   func() (*campaignStore, *customerStore)
 		wantErrType  string
 	}{
 		{
 			name:       "campaign not found",
 			campaignID: 999,
 			customerID: 1,
-			setupMocks: func() (*mockCampaignRepository, *mockCustomerRepository) {
-				return &mockCampaignRepository{
-						campaigns: []*models.Campaign{},
-					}, &mockCustomerRepository{
-						customers: map[int64]*models.Customer{
+			setupMocks: func() (*campaignStore, *customerStore) {
+				return &campaignStore{
+						all: []*models.Campaign{},
+					}, &customerStore{
+						byID: map[int64]*models.Customer{
 							1: {ID: 1, FirstName: "Alice"},
 						},
 					}
@@ -225,13 +231,13 @@ func TestCampaignService_PreviewPersonalized_Errors(t *testing.T) {
 			name:       "customer not found",
 			campaignID: 1,
 			customerID: 999,
-			setupMocks: func() (*mockCampaignRepository, *mockCustomerRepository) {
-				return &mockCampaignRepository{
-						campaigns: []*models.Campaign{
+			setupMocks: func() (*campaignStore, *customerStore) {
+				return &campaignStore{
+						all: []*models.Campaign{
 							{ID: 1, BaseTemplate: "test"},
 						},
-					}, &mockCustomerRepository{
-						customers: map[int64]*models.Customer{},
+					}, &customerStore{
+						byID: map[int64]*models.Customer{},
 					}
 			},
 			wantErrType: "not_found",
@@ -240,12 +246,12 @@ func TestCampaignService_PreviewPersonalized_Errors(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockCampaignRepo, mockCustomerRepo := tt.setupMocks()
+			campaigns, customers := tt.setupMocks()
 
 			svc := &campaignService{
-				campaignRepo: mockCampaignRepo,
-				customerRepo: mockCustomerRepo,
-				templateSvc:  NewTemplateService(),
+				campaignRepo: campaigns.repo(t),
+				customerRepo: customers.repo(t),
+				templateSvc:  NewTemplateService(nil, nil),
 				logger:       slog.New(slog.NewJSONHandler(os.Stdout, nil)),
 			}
 
@@ -276,31 +282,191 @@ func stringPtr(s string) *string {
 	return &s
 }
 
-// mockCustomerRepository for preview tests
-type mockCustomerRepository struct {
-	customers map[int64]*models.Customer
+// customerStore holds the customers behind a generated repository mock
+type customerStore struct {
+	byID map[int64]*models.Customer
+}
+
+// repo returns a mock repository reading and updating the store
+func (s *customerStore) repo(t *testing.T) *mocks.MockCustomerRepository {
+	repo := mocks.NewMockCustomerRepository(gomock.NewController(t))
+	repo.EXPECT().GetByID(gomock.Any(), gomock.Any()).DoAndReturn(s.getByID).AnyTimes()
+	repo.EXPECT().GetByIDs(gomock.Any(), gomock.Any()).DoAndReturn(s.getByIDs).AnyTimes()
+	repo.EXPECT().GetByPhones(gomock.Any(), gomock.Any()).DoAndReturn(s.getByPhones).AnyTimes()
+	repo.EXPECT().ListAfterID(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(s.listAfterID).AnyTimes()
+	repo.EXPECT().Count(gomock.Any()).DoAndReturn(s.count).AnyTimes()
+	repo.EXPECT().CountByIDs(gomock.Any(), gomock.Any()).DoAndReturn(s.countByIDs).AnyTimes()
+	repo.EXPECT().ListMatchingAfterID(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(s.listMatchingAfterID).AnyTimes()
+	repo.EXPECT().CountMatching(gomock.Any(), gomock.Any()).DoAndReturn(s.countMatching).AnyTimes()
+	repo.EXPECT().MatchingIDs(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(s.matchingIDs).AnyTimes()
+	repo.EXPECT().ListMatching(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(s.listMatching).AnyTimes()
+	repo.EXPECT().SampleMatching(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(s.sampleMatching).AnyTimes()
+	repo.EXPECT().Anonymize(gomock.Any(), gomock.Any()).DoAndReturn(s.anonymize).AnyTimes()
+	repo.EXPECT().SetOptedOut(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(s.setOptedOut).AnyTimes()
+	repo.EXPECT().SetSnoozedUntil(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(s.setSnoozedUntil).AnyTimes()
+	return repo
 }
 
-func (m *mockCustomerRepository) GetByID(ctx context.Context, id int64) (*models.Customer, error) {
-	customer, ok := m.customers[id]
+func (s *customerStore) getByID(ctx context.Context, id int64) (*models.Customer, error) {
+	customer, ok := s.byID[id]
 	if !ok {
 		return nil, models.ErrNotFoundWithMsg("customer not found")
 	}
 	return customer, nil
 }
 
-func (m *mockCustomerRepository) Create(ctx context.Context, customer *models.Customer) error {
-	return nil
+func (s *customerStore) getByIDs(ctx context.Context, ids []int64) ([]*models.Customer, error) {
+	customers := []*models.Customer{}
+	for _, id := range ids {
+		if customer, ok := s.byID[id]; ok {
+			customers = append(customers, customer)
+		}
+	}
+	return customers, nil
 }
-func (m *mockCustomerRepository) List(ctx context.Context, filter models.CustomerFilter) ([]*models.Customer, int64, error) {
-	return nil, 0, nil
+
+func (s *customerStore) getByPhones(ctx context.Context, phones []string) ([]*models.Customer, error) {
+	wanted := make(map[string]bool, len(phones))
+	for _, phone := range phones {
+		wanted[phone] = true
+	}
+	ids := make([]int64, 0, len(phones))
+	for id, customer := range s.byID {
+		if wanted[customer.Phone] {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	customers := make([]*models.Customer, 0, len(ids))
+	for _, id := range ids {
+		customers = append(customers, s.byID[id])
+	}
+	return customers, nil
 }
-func (m *mockCustomerRepository) Update(ctx context.Context, customer *models.Customer) error {
-	return nil
+
+func (s *customerStore) listAfterID(ctx context.Context, afterID int64, limit int) ([]*models.Customer, error) {
+	ids := make([]int64, 0, len(s.byID))
+	for id := range s.byID {
+		if id > afterID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return s.getByIDs(ctx, ids)
+}
+
+func (s *customerStore) count(ctx context.Context) (int64, error) {
+	return int64(len(s.byID)), nil
+}
+
+func (s *customerStore) countByIDs(ctx context.Context, ids []int64) (int64, error) {
+	var count int64
+	for _, id := range ids {
+		if _, ok := s.byID[id]; ok {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *customerStore) listMatchingAfterID(ctx context.Context, filter models.SegmentFilter, afterID int64, limit int) ([]*models.Customer, error) {
+	page, err := s.listAfterID(ctx, afterID, len(s.byID))
+	if err != nil {
+		return nil, err
+	}
+	matching := make([]*models.Customer, 0, limit)
+	for _, customer := range page {
+		if len(matching) < limit && matchesSegmentFilter(customer, filter) {
+			matching = append(matching, customer)
+		}
+	}
+	return matching, nil
+}
+
+func (s *customerStore) countMatching(ctx context.Context, filter models.SegmentFilter) (int64, error) {
+	var count int64
+	for _, customer := range s.byID {
+		if matchesSegmentFilter(customer, filter) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *customerStore) matchingIDs(ctx context.Context, filter models.SegmentFilter, ids []int64) ([]int64, error) {
+	matching := []int64{}
+	for _, id := range ids {
+		if customer, ok := s.byID[id]; ok && matchesSegmentFilter(customer, filter) {
+			matching = append(matching, id)
+		}
+	}
+	return matching, nil
+}
+
+func (s *customerStore) listMatching(ctx context.Context, filter models.SegmentFilter, page, pageSize int) ([]*models.Customer, error) {
+	matching, err := s.listMatchingAfterID(ctx, filter, 0, len(s.byID))
+	if err != nil {
+		return nil, err
+	}
+	start := min((page-1)*pageSize, len(matching))
+	return matching[start:min(start+pageSize, len(matching))], nil
+}
+
+// sampleMatching returns the first eligible matches rather than random ones,
+// keeping tests deterministic
+func (s *customerStore) sampleMatching(ctx context.Context, filter models.SegmentFilter, limit int) ([]*models.Customer, error) {
+	matching, err := s.listMatchingAfterID(ctx, filter, 0, len(s.byID))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	sample := make([]*models.Customer, 0, limit)
+	for _, customer := range matching {
+		if len(sample) < limit && !customer.IsOptedOut() && !customer.IsSnoozed(now) && !customer.IsBounced() {
+			sample = append(sample, customer)
+		}
+	}
+	return sample, nil
+}
+
+// matchesSegmentFilter applies a segment filter the way the repository's SQL does
+func matchesSegmentFilter(customer *models.Customer, filter models.SegmentFilter) bool {
+	return (filter.Location == "" || customer.Location == filter.Location) &&
+		(filter.PreferredProduct == "" || customer.PreferredProduct == filter.PreferredProduct) &&
+		strings.HasPrefix(customer.Phone, filter.PhonePrefix)
 }
-func (m *mockCustomerRepository) Delete(ctx context.Context, id int64) error {
+
+func (s *customerStore) anonymize(ctx context.Context, id int64) error {
+	customer, ok := s.byID[id]
+	if !ok {
+		return models.ErrNotFoundWithMsg("customer not found")
+	}
+	customer.FirstName = ""
+	customer.LastName = ""
 	return nil
 }
-func (m *mockCustomerRepository) GetByPhone(ctx context.Context, phone string) (*models.Customer, error) {
-	return nil, models.ErrNotFoundWithMsg("not implemented")
+
+func (s *customerStore) setOptedOut(ctx context.Context, id int64, optedOut bool) (bool, error) {
+	customer, ok := s.byID[id]
+	if !ok || customer.IsOptedOut() == optedOut {
+		return false, nil
+	}
+	customer.OptedOutAt = nil
+	if optedOut {
+		now := time.Now()
+		customer.OptedOutAt = &now
+	}
+	return true, nil
+}
+
+func (s *customerStore) setSnoozedUntil(ctx context.Context, id int64, until *time.Time) error {
+	customer, ok := s.byID[id]
+	if !ok {
+		return models.ErrNotFoundWithMsg("customer not found")
+	}
+	customer.SnoozedUntil = until
+	return nil
 }
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// recommendedProductField is the placeholder filled by the Recommender
+const recommendedProductField = "recommended_product"
+
+// Recommender picks the product to offer each customer, filling the
+// {recommended_product} placeholder. It is called once per audience batch.
+type Recommender interface {
+	// Recommend returns a product per customer ID. Customers left out fall
+	// back to their preferred product.
+	Recommend(ctx context.Context, customers []*models.Customer) (map[int64]string, error)
+}
+
+// preferredProductRecommender recommends each customer's preferred product
+type preferredProductRecommender struct{}
+
+// Recommend returns the customers' preferred products
+func (preferredProductRecommender) Recommend(ctx context.Context, customers []*models.Customer) (map[int64]string, error) {
+	products := make(map[int64]string, len(customers))
+	for _, customer := range customers {
+		products[customer.ID] = customer.PreferredProduct
+	}
+	return products, nil
+}
+
+// httpRecommender asks an external recommendation service for a batch of
+// customers at a time
+type httpRecommender struct {
+	url    string
+	client *http.Client
+}
+
+// recommendationRequest is the body posted to the recommendation service
+type recommendationRequest struct {
+	Customers []recommendationCustomer `json:"customers"`
+}
+
+// recommendationCustomer is the customer profile sent for a recommendation
+type recommendationCustomer struct {
+	ID               int64  `json:"id"`
+	FirstName        string `json:"first_name"`
+	Location         string `json:"location"`
+	PreferredProduct string `json:"preferred_product"`
+}
+
+// recommendationResponse maps customer IDs to recommended products
+type recommendationResponse struct {
+	Recommendations map[int64]string `json:"recommendations"`
+}
+
+// NewRecommender creates a recommender that calls url, or one that recommends
+// the preferred product when url is empty
+func NewRecommender(url string, timeout time.Duration) Recommender {
+	if url == "" {
+		return preferredProductRecommender{}
+	}
+
+	return &httpRecommender{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Recommend posts the customers' profiles and returns the recommendations
+func (r *httpRecommender) Recommend(ctx context.Context, customers []*models.Customer) (map[int64]string, error) {
+	payload := recommendationRequest{Customers: make([]recommendationCustomer, 0, len(customers))}
+	for _, customer := range customers {
+		payload.Customers = append(payload.Customers, recommendationCustomer{
+			ID:               customer.ID,
+			FirstName:        customer.FirstName,
+			Location:         customer.Location,
+			PreferredProduct: customer.PreferredProduct,
+		})
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal recommendation request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recommendation request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request recommendations: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("recommendation service returned status %d", resp.StatusCode)
+	}
+
+	var result recommendationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode recommendations: %w", err)
+	}
+
+	return result.Recommendations, nil
+}
+
+// recommendations resolves {recommended_product} for a batch when the
+// template uses it. A failing recommender must not stop a send, so its error
+// is logged and every customer falls back to their preferred product.
+func recommendations(ctx context.Context, recommender Recommender, compiled *CompiledTemplate, customers []*models.Customer, logger *slog.Logger) map[int64]string {
+	if recommender == nil || !compiled.Uses(recommendedProductField) {
+		return nil
+	}
+
+	products, err := recommender.Recommend(ctx, customers)
+	if err != nil {
+		logger.Warn("recommendations unavailable, using preferred products",
+			slog.Int("customers", len(customers)),
+			slog.String("error", err.Error()),
+		)
+		return nil
+	}
+	return products
+}
+
+// recommendedValues returns the template values for a customer's
+// recommendation, or nil to render their preferred product
+func recommendedValues(products map[int64]string, customer *models.Customer) map[string]string {
+	product := products[customer.ID]
+	if product == "" {
+		return nil
+	}
+	return map[string]string{recommendedProductField: product}
+}
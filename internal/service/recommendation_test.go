@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestCampaignService_BuildMessages_RecommendedProduct(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var req recommendationRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Customers) != 2 {
+			t.Errorf("recommendation request = %+v, %v, want both customers", req, err)
+		}
+		// Customer 2 gets no recommendation
+		_, _ = w.Write([]byte(`{"recommendations": {"1": "Trail Shoes"}}`))
+	}))
+	defer server.Close()
+
+	customers := []*models.Customer{
+		{ID: 1, FirstName: "Alice", PreferredProduct: "Shoes"},
+		{ID: 2, FirstName: "Bob", PreferredProduct: "Socks"},
+	}
+	campaign := &models.Campaign{ID: 1, BaseTemplate: "Hi {first_name}, try {recommended_product}"}
+
+	tests := []struct {
+		name        string
+		recommender Recommender
+		want        []string
+	}{
+		{name: "default", recommender: nil, want: []string{"Hi Alice, try Shoes", "Hi Bob, try Socks"}},
+		{name: "preferred product", recommender: NewRecommender("", time.Second), want: []string{"Hi Alice, try Shoes", "Hi Bob, try Socks"}},
+		{name: "http", recommender: NewRecommender(server.URL, time.Second), want: []string{"Hi Alice, try Trail Shoes", "Hi Bob, try Socks"}},
+		{name: "unavailable", recommender: NewRecommender("http://127.0.0.1:1", time.Second), want: []string{"Hi Alice, try Shoes", "Hi Bob, try Socks"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &campaignService{
+				templateSvc: NewTemplateService(nil, nil),
+				config:      CampaignServiceConfig{RenderConcurrency: 2, Recommender: tt.recommender},
+				logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+			}
+
+			messages := svc.buildMessages(context.Background(), campaign, svc.templateSvc.Compile(campaign.BaseTemplate), customers)
+			for i, message := range messages {
+				if message.RenderedContent != tt.want[i] {
+					t.Errorf("messages[%d] = %q, want %q", i, message.RenderedContent, tt.want[i])
+				}
+			}
+		})
+	}
+
+	// Templates without the placeholder never call the service
+	svc := &campaignService{
+		templateSvc: NewTemplateService(nil, nil),
+		config:      CampaignServiceConfig{RenderConcurrency: 2, Recommender: NewRecommender(server.URL, time.Second)},
+		logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	svc.buildMessages(context.Background(), campaign, svc.templateSvc.Compile("Hi {first_name}"), customers)
+	if requests != 1 {
+		t.Errorf("recommendation service called %d times, want once", requests)
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestCampaignService_Resume(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+	messageRepo := mocks.NewMockOutboundMessageRepository(ctrl)
+	queueClient := mocks.NewMockClient(ctrl)
+
+	reason := "provider outage"
+	campaignRepo.EXPECT().GetByID(gomock.Any(), int64(1)).
+		Return(&models.Campaign{ID: 1, Status: models.CampaignStatusPaused, PausedReason: &reason}, nil)
+	campaignRepo.EXPECT().Resume(gomock.Any(), int64(1)).Return(nil)
+
+	// Only the pending messages whose jobs were removed or dropped are listed;
+	// those with a job still queued or delayed are not published twice
+	page := []*models.OutboundMessage{
+		{ID: 2, CampaignID: 1, Status: models.MessageStatusPending},
+		{ID: 4, CampaignID: 1, Status: models.MessageStatusPending},
+	}
+	gomock.InOrder(
+		messageRepo.EXPECT().ListUnqueuedByCampaign(gomock.Any(), int64(1), int64(0), exportPageSize).Return(page, nil),
+		messageRepo.EXPECT().ListUnqueuedByCampaign(gomock.Any(), int64(1), int64(4), exportPageSize).Return(nil, nil),
+	)
+
+	var published []int64
+	queueClient.EXPECT().Publish(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, job *models.MessageJob) error {
+			published = append(published, job.OutboundMessageID)
+			return nil
+		}).
+		Times(2)
+	messageRepo.EXPECT().MarkQueued(gomock.Any(), []int64{2, 4}).Return(nil)
+
+	svc := &campaignService{
+		campaignRepo: campaignRepo,
+		messageRepo:  messageRepo,
+		queueClient:  queueClient,
+		logger:       slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	}
+
+	result, err := svc.Resume(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+
+	if result.MessagesRequeued != 2 || result.Status != models.CampaignStatusSending {
+		t.Errorf("Resume() = %+v, want 2 messages requeued and status sending", result)
+	}
+	if len(published) != 2 || published[0] != 2 || published[1] != 4 {
+		t.Errorf("published = %v, want [2 4]", published)
+	}
+}
+
+func TestCampaignService_Resume_NotPaused(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+
+	// Resume must not touch the repository when the campaign is not paused
+	campaignRepo.EXPECT().GetByID(gomock.Any(), int64(2)).
+		Return(&models.Campaign{ID: 2, Status: models.CampaignStatusSending}, nil)
+
+	svc := &campaignService{
+		campaignRepo: campaignRepo,
+		logger:       slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	}
+
+	_, err := svc.Resume(context.Background(), 2)
+	var appErr *models.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "CONFLICT" {
+		t.Errorf("Resume() of a sending campaign error = %v, want CONFLICT", err)
+	}
+}
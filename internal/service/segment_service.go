@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// SegmentService handles customer segment business logic
+type SegmentService interface {
+	Create(ctx context.Context, req *SegmentRequest) (*models.Segment, error)
+	// GetByID returns the segment with its cached customer count, counting
+	// it first when it has none
+	GetByID(ctx context.Context, id int64) (*SegmentDetails, error)
+	// RefreshCount counts the customers the segment matches now and caches
+	// the count
+	RefreshCount(ctx context.Context, id int64) (*SegmentDetails, error)
+	// ListMembers returns a page of the customers the segment matches now,
+	// refreshing its cached count
+	ListMembers(ctx context.Context, id int64, page, pageSize int) (*SegmentMembersResult, error)
+	List(ctx context.Context, page, pageSize int) (*SegmentListResult, error)
+	Update(ctx context.Context, id int64, req *SegmentRequest) (*models.Segment, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type segmentService struct {
+	segmentRepo  repository.SegmentRepository
+	customerRepo repository.CustomerRepository
+	now          func() time.Time
+	logger       *slog.Logger
+}
+
+// NewSegmentService creates a new segment service
+func NewSegmentService(
+	segmentRepo repository.SegmentRepository,
+	customerRepo repository.CustomerRepository,
+	logger *slog.Logger,
+) SegmentService {
+	return &segmentService{
+		segmentRepo:  segmentRepo,
+		customerRepo: customerRepo,
+		now:          time.Now,
+		logger:       logger,
+	}
+}
+
+// Create saves a new segment
+func (s *segmentService) Create(ctx context.Context, req *SegmentRequest) (*models.Segment, error) {
+	segment := req.segment()
+	if err := segment.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.segmentRepo.Create(ctx, segment); err != nil {
+		s.logger.Error("failed to create segment",
+			slog.String("name", segment.Name),
+			slog.String("error", err.Error()),
+		)
+		return nil, err
+	}
+
+	s.logger.Info("segment created",
+		slog.Int64("segment_id", segment.ID),
+		slog.String("name", segment.Name),
+	)
+
+	return segment, nil
+}
+
+// GetByID retrieves a segment with its cached count
+func (s *segmentService) GetByID(ctx context.Context, id int64) (*SegmentDetails, error) {
+	segment, err := s.segmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.segmentRepo.GetCount(ctx, id)
+	if errors.Is(err, models.ErrNotFound) {
+		return s.count(ctx, segment)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &SegmentDetails{Segment: segment, CustomerCount: count.CustomerCount, CountedAt: count.CountedAt}, nil
+}
+
+// RefreshCount retrieves a segment and counts it again
+func (s *segmentService) RefreshCount(ctx context.Context, id int64) (*SegmentDetails, error) {
+	segment, err := s.segmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.count(ctx, segment)
+}
+
+// ListMembers pages through the segment's customers in ID order. The count
+// behind the pagination is cached as the segment's count.
+func (s *segmentService) ListMembers(ctx context.Context, id int64, page, pageSize int) (*SegmentMembersResult, error) {
+	models.ValidateAndSetDefaults(&page, &pageSize)
+
+	details, err := s.RefreshCount(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	customers, err := s.customerRepo.ListMatching(ctx, details.Filter, page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list segment customers: %w", err)
+	}
+
+	return &SegmentMembersResult{
+		Data:       customers,
+		Pagination: models.NewPaginationResult(page, pageSize, details.CustomerCount),
+	}, nil
+}
+
+// count counts the customers segment matches and caches the count. A count
+// that cannot be cached is still returned.
+func (s *segmentService) count(ctx context.Context, segment *models.Segment) (*SegmentDetails, error) {
+	customers, err := s.customerRepo.CountMatching(ctx, segment.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count segment customers: %w", err)
+	}
+
+	count := &models.SegmentCount{SegmentID: segment.ID, CustomerCount: customers, CountedAt: s.now().UTC()}
+	if err := s.segmentRepo.SaveCount(ctx, count); err != nil {
+		s.logger.Warn("failed to cache segment count",
+			slog.Int64("segment_id", segment.ID),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	return &SegmentDetails{Segment: segment, CustomerCount: count.CustomerCount, CountedAt: count.CountedAt}, nil
+}
+
+// List retrieves segments with pagination
+func (s *segmentService) List(ctx context.Context, page, pageSize int) (*SegmentListResult, error) {
+	segments, totalCount, err := s.segmentRepo.List(ctx, page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list segments: %w", err)
+	}
+
+	models.ValidateAndSetDefaults(&page, &pageSize)
+
+	return &SegmentListResult{
+		Data:       segments,
+		Pagination: models.NewPaginationResult(page, pageSize, totalCount),
+	}, nil
+}
+
+// Update replaces a segment's name, description and filter. Sends already
+// built keep the recipients the old filter matched.
+func (s *segmentService) Update(ctx context.Context, id int64, req *SegmentRequest) (*models.Segment, error) {
+	segment := req.segment()
+	segment.ID = id
+	if err := segment.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.segmentRepo.Update(ctx, segment); err != nil {
+		s.logger.Error("failed to update segment",
+			slog.Int64("segment_id", id),
+			slog.String("error", err.Error()),
+		)
+		return nil, err
+	}
+
+	s.logger.Info("segment updated",
+		slog.Int64("segment_id", id),
+	)
+
+	return segment, nil
+}
+
+// Delete removes a segment
+func (s *segmentService) Delete(ctx context.Context, id int64) error {
+	if err := s.segmentRepo.Delete(ctx, id); err != nil {
+		s.logger.Error("failed to delete segment",
+			slog.Int64("segment_id", id),
+			slog.String("error", err.Error()),
+		)
+		return err
+	}
+
+	s.logger.Info("segment deleted",
+		slog.Int64("segment_id", id),
+	)
+
+	return nil
+}
+
+// segment builds the segment a request describes, trimming its text fields
+func (r *SegmentRequest) segment() *models.Segment {
+	return &models.Segment{
+		Name:        strings.TrimSpace(r.Name),
+		Description: strings.TrimSpace(r.Description),
+		Filter:      trimmedFilter(r.Filter),
+	}
+}
+
+// trimmedFilter returns filter with surrounding whitespace removed from its
+// criteria and condition fields; condition values are matched as given
+func trimmedFilter(filter models.SegmentFilter) models.SegmentFilter {
+	var conditions []models.SegmentCondition
+	for _, condition := range filter.Conditions {
+		condition.Field = strings.TrimSpace(condition.Field)
+		conditions = append(conditions, condition)
+	}
+	return models.SegmentFilter{
+		Location:         strings.TrimSpace(filter.Location),
+		PreferredProduct: strings.TrimSpace(filter.PreferredProduct),
+		PhonePrefix:      strings.TrimSpace(filter.PhonePrefix),
+		Conditions:       conditions,
+	}
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestSegmentService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	segmentRepo := mocks.NewMockSegmentRepository(ctrl)
+	svc := NewSegmentService(segmentRepo, mocks.NewMockCustomerRepository(ctrl), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	segmentRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, segment *models.Segment) error {
+			segment.ID = 1
+			return nil
+		})
+
+	segment, err := svc.Create(context.Background(), &SegmentRequest{
+		Name:   " Nairobi Safaricom ",
+		Filter: models.SegmentFilter{Location: "Nairobi", PhonePrefix: " +2547 "},
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if segment.Name != "Nairobi Safaricom" || segment.Filter.PhonePrefix != "+2547" {
+		t.Errorf("Create() = %+v, want trimmed name and prefix", segment)
+	}
+
+	invalid := []*SegmentRequest{
+		{Filter: models.SegmentFilter{Location: "Nairobi"}},
+		{Name: "Everyone"},
+		{Name: "Bad prefix", Filter: models.SegmentFilter{PhonePrefix: "+254-7"}},
+		{Name: "Plus only", Filter: models.SegmentFilter{PhonePrefix: "+"}},
+	}
+	for _, req := range invalid {
+		var appErr *models.AppError
+		if _, err := svc.Create(context.Background(), req); !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+			t.Errorf("Create(%+v) error = %v, want INVALID_INPUT", req, err)
+		}
+	}
+}
+
+func TestSegmentService_GetByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	segmentRepo := mocks.NewMockSegmentRepository(ctrl)
+	customerRepo := mocks.NewMockCustomerRepository(ctrl)
+	svc := NewSegmentService(segmentRepo, customerRepo, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	countedAt := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	filter := models.SegmentFilter{PreferredProduct: "Shoes"}
+	segmentRepo.EXPECT().GetByID(gomock.Any(), int64(4)).Return(&models.Segment{ID: 4, Name: "Shoes", Filter: filter}, nil)
+	segmentRepo.EXPECT().GetCount(gomock.Any(), int64(4)).Return(&models.SegmentCount{SegmentID: 4, CustomerCount: 42, CountedAt: countedAt}, nil)
+
+	// A cached count is used as is
+	details, err := svc.GetByID(context.Background(), 4)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if details.ID != 4 || details.CustomerCount != 42 || !details.CountedAt.Equal(countedAt) {
+		t.Errorf("GetByID() = %+v, want segment 4 matching 42 customers", details)
+	}
+
+	// A segment without one is counted and the count cached
+	segmentRepo.EXPECT().GetByID(gomock.Any(), int64(5)).Return(&models.Segment{ID: 5, Name: "Nairobi", Filter: filter}, nil)
+	segmentRepo.EXPECT().GetCount(gomock.Any(), int64(5)).Return(nil, models.ErrNotFoundWithMsg("segment with ID 5 has not been counted"))
+	customerRepo.EXPECT().CountMatching(gomock.Any(), filter).Return(int64(7), nil)
+	segmentRepo.EXPECT().SaveCount(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, count *models.SegmentCount) error {
+			if count.SegmentID != 5 || count.CustomerCount != 7 {
+				t.Errorf("SaveCount(%+v), want segment 5 with 7 customers", count)
+			}
+			return nil
+		})
+
+	details, err = svc.GetByID(context.Background(), 5)
+	if err != nil {
+		t.Fatalf("GetByID(uncounted) error = %v", err)
+	}
+	if details.CustomerCount != 7 || details.CountedAt.IsZero() {
+		t.Errorf("GetByID(uncounted) = %+v, want 7 customers counted now", details)
+	}
+}
+
+func TestSegmentService_ListMembers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	segmentRepo := mocks.NewMockSegmentRepository(ctrl)
+	customerRepo := mocks.NewMockCustomerRepository(ctrl)
+	svc := NewSegmentService(segmentRepo, customerRepo, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	filter := models.SegmentFilter{Location: "Nairobi"}
+	segmentRepo.EXPECT().GetByID(gomock.Any(), int64(4)).Return(&models.Segment{ID: 4, Filter: filter}, nil)
+	customerRepo.EXPECT().CountMatching(gomock.Any(), filter).Return(int64(45), nil)
+	// Caching is best effort; the members are listed regardless
+	segmentRepo.EXPECT().SaveCount(gomock.Any(), gomock.Any()).Return(errors.New("connection reset"))
+	customerRepo.EXPECT().ListMatching(gomock.Any(), filter, 3, 20).Return([]*models.Customer{{ID: 41}, {ID: 57}}, nil)
+
+	result, err := svc.ListMembers(context.Background(), 4, 3, 0)
+	if err != nil {
+		t.Fatalf("ListMembers() error = %v", err)
+	}
+	if len(result.Data) != 2 || result.Pagination.TotalCount != 45 || result.Pagination.Page != 3 {
+		t.Errorf("ListMembers() = %d customers, %+v, want 2 on page 3 of 45", len(result.Data), result.Pagination)
+	}
+
+	segmentRepo.EXPECT().GetByID(gomock.Any(), int64(9)).Return(nil, models.ErrNotFoundWithMsg("segment with ID 9 not found"))
+	if _, err := svc.ListMembers(context.Background(), 9, 1, 20); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("ListMembers(unknown) error = %v, want not found", err)
+	}
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestCampaignService_SendCampaign_HeldForApproval(t *testing.T) {
+	campaigns := &campaignStore{
+		all: []*models.Campaign{
+			{ID: 1, Status: models.CampaignStatusDraft, BaseTemplate: "Hi {first_name}"},
+			{ID: 2, Status: models.CampaignStatusDraft, BaseTemplate: "Hi {first_name}"},
+		},
+	}
+	messages := &messageStore{}
+	queueClient := &mockQueueClient{}
+
+	ctrl := gomock.NewController(t)
+	sendJobs := mocks.NewMockSendJobRepository(ctrl)
+
+	var held *models.SendJob
+	sendJobs.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, job *models.SendJob) error {
+			job.ID = 7
+			job.Status = models.SendJobStatusAwaitingApproval
+			held = job
+			return nil
+		})
+	sendJobs.EXPECT().GetByID(gomock.Any(), int64(7)).
+		DoAndReturn(func(ctx context.Context, id int64) (*models.SendJob, error) { return held, nil }).AnyTimes()
+	sendJobs.EXPECT().Approve(gomock.Any(), int64(7), int64(2), models.CampaignStatusSending).
+		DoAndReturn(func(ctx context.Context, id, approverID int64, status string) (bool, error) {
+			campaigns.all[0].Status = status
+			return true, nil
+		})
+
+	svc := NewCampaignService(
+		campaigns.repo(t),
+		(&customerStore{byID: newTestCustomers(10)}).repo(t),
+		messages.repo(t),
+		nil,
+		NewTemplateService(nil, nil),
+		queueClient,
+		CampaignServiceConfig{SendBatchSize: 10, RenderConcurrency: 1, ApprovalThreshold: 5, SendJobs: sendJobs},
+		slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	)
+
+	// At or below the threshold the send goes out at once
+	result, err := svc.SendCampaign(context.Background(), 2, &SendCampaignRequest{CustomerIDs: []int64{1, 2, 3}})
+	if err != nil {
+		t.Fatalf("SendCampaign() below threshold error = %v", err)
+	}
+	if result.SendJob != nil || result.MessagesQueued != 3 {
+		t.Fatalf("SendCampaign() below threshold = %+v, want 3 messages queued without a send job", result)
+	}
+	queueClient.published = nil
+
+	// Above it the messages are built but held
+	sender := int64(1)
+	result, err = svc.SendCampaign(context.Background(), 1, &SendCampaignRequest{Target: SendTargetAll, RequestedBy: &sender})
+	if err != nil {
+		t.Fatalf("SendCampaign() above threshold error = %v", err)
+	}
+	if result.Status != models.CampaignStatusAwaitingApproval || result.SendJob == nil || result.SendJob.ID != 7 {
+		t.Fatalf("SendCampaign() above threshold = %+v, want send job 7 awaiting approval", result)
+	}
+	if held.RecipientCount != 10 || !held.RequestedByUser(sender) {
+		t.Errorf("send job = %+v, want 10 recipients requested by user %d", held, sender)
+	}
+	if campaigns.all[0].Status != models.CampaignStatusAwaitingApproval {
+		t.Errorf("campaign status = %s, want %s", campaigns.all[0].Status, models.CampaignStatusAwaitingApproval)
+	}
+	if len(queueClient.published) != 0 {
+		t.Fatalf("jobs published = %d, want none before approval", len(queueClient.published))
+	}
+
+	// Sending again does not get round the approval
+	if _, err := svc.SendCampaign(context.Background(), 1, &SendCampaignRequest{RequestedBy: &sender}); !errors.Is(err, models.ErrConflict) {
+		t.Errorf("SendCampaign() while awaiting approval error = %v, want conflict", err)
+	}
+
+	// Neither the sender nor an anonymous request can approve it
+	for _, approver := range []*int64{&sender, nil} {
+		var appErr *models.AppError
+		if _, err := svc.ApproveSendJob(context.Background(), 7, approver); !errors.As(err, &appErr) || appErr.Code != "FORBIDDEN" {
+			t.Errorf("ApproveSendJob() by %v error = %v, want FORBIDDEN", approver, err)
+		}
+	}
+	if len(queueClient.published) != 0 {
+		t.Fatalf("jobs published = %d, want none after refused approvals", len(queueClient.published))
+	}
+
+	// Another user's approval releases the messages
+	approver := int64(2)
+	result, err = svc.ApproveSendJob(context.Background(), 7, &approver)
+	if err != nil {
+		t.Fatalf("ApproveSendJob() error = %v", err)
+	}
+	if result.Status != models.CampaignStatusSending || result.MessagesQueued != 10 || len(queueClient.published) != 10 {
+		t.Errorf("ApproveSendJob() = %+v with %d jobs published, want 10 messages queued", result, len(queueClient.published))
+	}
+	if campaigns.all[0].Status != models.CampaignStatusSending {
+		t.Errorf("campaign status = %s, want %s", campaigns.all[0].Status, models.CampaignStatusSending)
+	}
+
+	// And only once
+	if _, err := svc.ApproveSendJob(context.Background(), 7, &approver); !errors.Is(err, models.ErrConflict) {
+		t.Errorf("ApproveSendJob() again error = %v, want conflict", err)
+	}
+}
+
+func TestCampaignService_ApproveSendJob_CampaignCancelled(t *testing.T) {
+	campaigns := &campaignStore{
+		all: []*models.Campaign{
+			{ID: 1, Status: models.CampaignStatusCancelled, BaseTemplate: "Hi {first_name}"},
+		},
+	}
+	queueClient := &mockQueueClient{}
+
+	ctrl := gomock.NewController(t)
+	sendJobs := mocks.NewMockSendJobRepository(ctrl)
+	sendJobs.EXPECT().GetByID(gomock.Any(), int64(7)).
+		Return(&models.SendJob{ID: 7, CampaignID: 1, Status: models.SendJobStatusAwaitingApproval}, nil)
+
+	svc := NewCampaignService(
+		campaigns.repo(t),
+		nil,
+		(&messageStore{}).repo(t),
+		nil,
+		NewTemplateService(nil, nil),
+		queueClient,
+		CampaignServiceConfig{ApprovalThreshold: 5, SendJobs: sendJobs},
+		slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	)
+
+	// A send scheduled by no one can be approved by anyone, but not once its
+	// campaign was cancelled
+	approver := int64(2)
+	if _, err := svc.ApproveSendJob(context.Background(), 7, &approver); !errors.Is(err, models.ErrConflict) {
+		t.Errorf("ApproveSendJob() error = %v, want conflict", err)
+	}
+	if len(queueClient.published) != 0 {
+		t.Errorf("jobs published = %d, want none", len(queueClient.published))
+	}
+}
+
+func TestCampaignService_ApproveSendJob_NotRecorded(t *testing.T) {
+	tests := []struct {
+		name     string
+		approved bool
+		err      error
+		wantErr  error
+	}{
+		{name: "approval fails", err: errors.New("connection reset")},
+		{name: "approved meanwhile", approved: false, wantErr: models.ErrConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			campaigns := &campaignStore{
+				all: []*models.Campaign{
+					{ID: 1, Status: models.CampaignStatusAwaitingApproval, BaseTemplate: "Hi {first_name}"},
+				},
+			}
+			queueClient := &mockQueueClient{}
+
+			ctrl := gomock.NewController(t)
+			sendJobs := mocks.NewMockSendJobRepository(ctrl)
+			sendJobs.EXPECT().GetByID(gomock.Any(), int64(7)).
+				Return(&models.SendJob{ID: 7, CampaignID: 1, Status: models.SendJobStatusAwaitingApproval}, nil)
+			sendJobs.EXPECT().Approve(gomock.Any(), int64(7), int64(2), models.CampaignStatusSending).Return(tt.approved, tt.err)
+
+			svc := NewCampaignService(
+				campaigns.repo(t),
+				nil,
+				(&messageStore{}).repo(t),
+				nil,
+				NewTemplateService(nil, nil),
+				queueClient,
+				CampaignServiceConfig{ApprovalThreshold: 5, SendJobs: sendJobs},
+				slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+			)
+
+			// Without a recorded approval the campaign is not released
+			approver := int64(2)
+			_, err := svc.ApproveSendJob(context.Background(), 7, &approver)
+			if err == nil || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+				t.Errorf("ApproveSendJob() error = %v, want %v", err, tt.wantErr)
+			}
+			if campaigns.all[0].Status != models.CampaignStatusAwaitingApproval {
+				t.Errorf("campaign status = %s, want %s", campaigns.all[0].Status, models.CampaignStatusAwaitingApproval)
+			}
+			if len(queueClient.published) != 0 {
+				t.Errorf("jobs published = %d, want none", len(queueClient.published))
+			}
+		})
+	}
+}
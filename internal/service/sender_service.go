@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// SenderService handles sender ID business logic
+type SenderService interface {
+	SetWarmup(ctx context.Context, senderID string, req *SenderWarmupRequest) (*SenderWarmupStatus, error)
+	GetWarmup(ctx context.Context, senderID string) (*SenderWarmupStatus, error)
+	DeleteWarmup(ctx context.Context, senderID string) error
+	SetRegistration(ctx context.Context, senderID, country string, req *SenderRegistrationRequest) (*models.SenderRegistration, error)
+	// ListRegistrations returns a sender's registrations and the enforced
+	// countries it cannot send SMS to for lack of one
+	ListRegistrations(ctx context.Context, senderID string) (*SenderRegistrations, error)
+	DeleteRegistration(ctx context.Context, senderID, country string) error
+}
+
+type senderService struct {
+	warmupRepo       repository.SenderWarmupRepository
+	registrationRepo repository.SenderRegistrationRepository
+	// enforced are the registration rules the worker enforces
+	enforced []models.SenderRegistrationRule
+	now      func() time.Time
+	logger   *slog.Logger
+}
+
+// NewSenderService creates a new sender service
+func NewSenderService(
+	warmupRepo repository.SenderWarmupRepository,
+	registrationRepo repository.SenderRegistrationRepository,
+	enforced []models.SenderRegistrationRule,
+	logger *slog.Logger,
+) SenderService {
+	return &senderService{
+		warmupRepo:       warmupRepo,
+		registrationRepo: registrationRepo,
+		enforced:         enforced,
+		now:              time.Now,
+		logger:           logger,
+	}
+}
+
+// SetWarmup creates or replaces the warm-up ramp for a sender
+func (s *senderService) SetWarmup(ctx context.Context, senderID string, req *SenderWarmupRequest) (*SenderWarmupStatus, error) {
+	warmup := &models.SenderWarmup{
+		SenderID:        senderID,
+		StartedOn:       models.StartOfDay(s.now()),
+		InitialDailyCap: req.InitialDailyCap,
+		MaxDailyCap:     req.MaxDailyCap,
+	}
+
+	if req.StartedOn != "" {
+		startedOn, err := time.Parse(warmupDateLayout, req.StartedOn)
+		if err != nil {
+			return nil, models.ErrInvalidInput("started_on must be a date in YYYY-MM-DD format")
+		}
+		warmup.StartedOn = startedOn
+	}
+	if warmup.InitialDailyCap == 0 {
+		warmup.InitialDailyCap = models.DefaultWarmupInitialDailyCap
+	}
+	if warmup.MaxDailyCap == 0 {
+		warmup.MaxDailyCap = models.DefaultWarmupMaxDailyCap
+	}
+
+	if err := warmup.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.warmupRepo.Upsert(ctx, warmup); err != nil {
+		s.logger.Error("failed to set sender warm-up",
+			slog.String("sender_id", senderID),
+			slog.String("error", err.Error()),
+		)
+		return nil, fmt.Errorf("failed to set sender warm-up: %w", err)
+	}
+
+	s.logger.Info("sender warm-up set",
+		slog.String("sender_id", senderID),
+		slog.Int("initial_daily_cap", warmup.InitialDailyCap),
+		slog.Int("max_daily_cap", warmup.MaxDailyCap),
+	)
+
+	return s.status(warmup), nil
+}
+
+// GetWarmup retrieves a sender's warm-up ramp and today's cap
+func (s *senderService) GetWarmup(ctx context.Context, senderID string) (*SenderWarmupStatus, error) {
+	warmup, err := s.warmupRepo.GetBySenderID(ctx, senderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.status(warmup), nil
+}
+
+// DeleteWarmup ends a sender's warm-up ramp, lifting its daily cap
+func (s *senderService) DeleteWarmup(ctx context.Context, senderID string) error {
+	if err := s.warmupRepo.Delete(ctx, senderID); err != nil {
+		return err
+	}
+
+	s.logger.Info("sender warm-up removed", slog.String("sender_id", senderID))
+
+	return nil
+}
+
+// status computes today's effective cap for a warm-up policy
+func (s *senderService) status(warmup *models.SenderWarmup) *SenderWarmupStatus {
+	todayCap := warmup.DailyCap(s.now())
+
+	return &SenderWarmupStatus{
+		SenderWarmup:  warmup,
+		TodayDailyCap: todayCap,
+		Complete:      todayCap >= warmup.MaxDailyCap,
+	}
+}
+
+// SetRegistration creates or replaces a sender's registration for a country.
+// The type defaults to what the country's rule requires, and must match it.
+func (s *senderService) SetRegistration(ctx context.Context, senderID, country string, req *SenderRegistrationRequest) (*models.SenderRegistration, error) {
+	registration := &models.SenderRegistration{
+		SenderID:       senderID,
+		Country:        strings.ToUpper(country),
+		Type:           req.Type,
+		RegistrationID: strings.TrimSpace(req.RegistrationID),
+	}
+
+	rule, known := models.SenderRegistrationRules[registration.Country]
+	if known && rule.Applies(senderID) {
+		if registration.Type == "" {
+			registration.Type = rule.Type
+		}
+		if registration.Type != rule.Type {
+			return nil, models.ErrInvalidInput(fmt.Sprintf("%s requires a %s registration", rule.Country, rule.Type))
+		}
+	}
+
+	if err := registration.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.registrationRepo.Upsert(ctx, registration); err != nil {
+		s.logger.Error("failed to set sender registration",
+			slog.String("sender_id", senderID),
+			slog.String("country", registration.Country),
+			slog.String("error", err.Error()),
+		)
+		return nil, fmt.Errorf("failed to set sender registration: %w", err)
+	}
+
+	s.logger.Info("sender registration set",
+		slog.String("sender_id", senderID),
+		slog.String("country", registration.Country),
+		slog.String("type", registration.Type),
+	)
+
+	return registration, nil
+}
+
+// ListRegistrations retrieves a sender's registrations
+func (s *senderService) ListRegistrations(ctx context.Context, senderID string) (*SenderRegistrations, error) {
+	registrations, err := s.registrationRepo.ListBySenderID(ctx, senderID)
+	if err != nil {
+		return nil, err
+	}
+
+	registered := make(map[string]string, len(registrations))
+	for _, registration := range registrations {
+		registered[registration.Country] = registration.Type
+	}
+
+	unregistered := make([]string, 0)
+	for _, rule := range s.enforced {
+		if rule.Applies(senderID) && registered[rule.Country] != rule.Type {
+			unregistered = append(unregistered, rule.Country)
+		}
+	}
+
+	return &SenderRegistrations{
+		SenderID:      senderID,
+		Registrations: registrations,
+		Unregistered:  unregistered,
+	}, nil
+}
+
+// DeleteRegistration removes a sender's registration for a country
+func (s *senderService) DeleteRegistration(ctx context.Context, senderID, country string) error {
+	country = strings.ToUpper(country)
+	if err := s.registrationRepo.Delete(ctx, senderID, country); err != nil {
+		return err
+	}
+
+	s.logger.Info("sender registration removed",
+		slog.String("sender_id", senderID),
+		slog.String("country", country),
+	)
+
+	return nil
+}
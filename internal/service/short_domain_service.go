@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// shortDomainCheckTimeout bounds the DNS lookup and HTTPS request of one
+// short domain health check
+const shortDomainCheckTimeout = 10 * time.Second
+
+// ShortDomainService handles short domain business logic
+type ShortDomainService interface {
+	Create(ctx context.Context, req *ShortDomainRequest) (*models.ShortDomain, error)
+	GetByID(ctx context.Context, id int64) (*models.ShortDomain, error)
+	List(ctx context.Context) ([]*models.ShortDomain, error)
+	Update(ctx context.Context, id int64, req *UpdateShortDomainRequest) (*models.ShortDomain, error)
+	Delete(ctx context.Context, id int64) error
+	// Check runs a health check of the short domain now and records the
+	// result
+	Check(ctx context.Context, id int64) (*models.ShortDomain, error)
+	// SetForCampaign chooses the short domain of a campaign's links
+	SetForCampaign(ctx context.Context, campaignID int64, req *SetShortDomainRequest) (*CampaignShortDomain, error)
+	// ForCampaign returns the short domain a campaign's links would use now
+	ForCampaign(ctx context.Context, campaignID int64) (*CampaignShortDomain, error)
+}
+
+type shortDomainService struct {
+	shortDomainRepo repository.ShortDomainRepository
+	campaignRepo    repository.CampaignRepository
+	probe           func(ctx context.Context, domain string) error
+	now             func() time.Time
+	logger          *slog.Logger
+}
+
+// NewShortDomainService creates a new short domain service
+func NewShortDomainService(
+	shortDomainRepo repository.ShortDomainRepository,
+	campaignRepo repository.CampaignRepository,
+	logger *slog.Logger,
+) ShortDomainService {
+	return &shortDomainService{
+		shortDomainRepo: shortDomainRepo,
+		campaignRepo:    campaignRepo,
+		probe:           probeShortDomain(newShortDomainClient()),
+		now:             time.Now,
+		logger:          logger,
+	}
+}
+
+// Create adds a short domain. It is unchecked, and so not used, until its
+// first health check passes.
+func (s *shortDomainService) Create(ctx context.Context, req *ShortDomainRequest) (*models.ShortDomain, error) {
+	domain := &models.ShortDomain{
+		Domain:    normalizeDomain(req.Domain),
+		IsDefault: req.IsDefault,
+		Enabled:   req.Enabled == nil || *req.Enabled,
+	}
+	if err := domain.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.shortDomainRepo.Create(ctx, domain); err != nil {
+		s.logger.Error("failed to create short domain",
+			slog.String("domain", domain.Domain),
+			slog.String("error", err.Error()),
+		)
+		return nil, err
+	}
+
+	s.logger.Info("short domain created",
+		slog.Int64("short_domain_id", domain.ID),
+		slog.String("domain", domain.Domain),
+	)
+
+	return domain, nil
+}
+
+// GetByID retrieves a short domain by ID
+func (s *shortDomainService) GetByID(ctx context.Context, id int64) (*models.ShortDomain, error) {
+	return s.shortDomainRepo.GetByID(ctx, id)
+}
+
+// List retrieves the account's short domains
+func (s *shortDomainService) List(ctx context.Context) ([]*models.ShortDomain, error) {
+	domains, err := s.shortDomainRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list short domains: %w", err)
+	}
+	return domains, nil
+}
+
+// Update changes whether a short domain is the default and whether it is
+// enabled. Campaigns pick the change up with their next resolution.
+func (s *shortDomainService) Update(ctx context.Context, id int64, req *UpdateShortDomainRequest) (*models.ShortDomain, error) {
+	domain, err := s.shortDomainRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.IsDefault != nil {
+		domain.IsDefault = *req.IsDefault
+	}
+	if req.Enabled != nil {
+		domain.Enabled = *req.Enabled
+	}
+
+	if err := s.shortDomainRepo.Update(ctx, domain); err != nil {
+		s.logger.Error("failed to update short domain",
+			slog.Int64("short_domain_id", id),
+			slog.String("error", err.Error()),
+		)
+		return nil, err
+	}
+
+	s.logger.Info("short domain updated",
+		slog.Int64("short_domain_id", id),
+		slog.Bool("is_default", domain.IsDefault),
+		slog.Bool("enabled", domain.Enabled),
+	)
+
+	return domain, nil
+}
+
+// Delete removes a short domain
+func (s *shortDomainService) Delete(ctx context.Context, id int64) error {
+	if err := s.shortDomainRepo.Delete(ctx, id); err != nil {
+		s.logger.Error("failed to delete short domain",
+			slog.Int64("short_domain_id", id),
+			slog.String("error", err.Error()),
+		)
+		return err
+	}
+
+	s.logger.Info("short domain deleted",
+		slog.Int64("short_domain_id", id),
+	)
+
+	return nil
+}
+
+// Check probes a short domain and records whether it is healthy. A failed
+// probe is a result, not an error; only failing to record it is.
+func (s *shortDomainService) Check(ctx context.Context, id int64) (*models.ShortDomain, error) {
+	domain, err := s.shortDomainRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, shortDomainCheckTimeout)
+	probeErr := s.probe(probeCtx, domain.Domain)
+	cancel()
+
+	check := &models.ShortDomainCheck{DomainID: domain.ID, Status: models.ShortDomainStatusHealthy, CheckedAt: s.now().UTC()}
+	if probeErr != nil {
+		message := probeErr.Error()
+		check.Status = models.ShortDomainStatusUnhealthy
+		check.Error = &message
+	}
+
+	if err := s.shortDomainRepo.RecordCheck(models.WithAccountID(ctx, domain.AccountID), check); err != nil {
+		return nil, err
+	}
+
+	if check.Status != domain.Status {
+		if probeErr != nil {
+			s.logger.Warn("short domain unhealthy",
+				slog.Int64("short_domain_id", domain.ID),
+				slog.String("domain", domain.Domain),
+				slog.String("error", probeErr.Error()),
+			)
+		} else {
+			s.logger.Info("short domain healthy",
+				slog.Int64("short_domain_id", domain.ID),
+				slog.String("domain", domain.Domain),
+			)
+		}
+	}
+
+	domain.Status = check.Status
+	domain.LastError = check.Error
+	domain.CheckedAt = &check.CheckedAt
+	return domain, nil
+}
+
+// SetForCampaign chooses a short domain of the campaign's account for its
+// links. A domain that is disabled or unhealthy can be chosen; the campaign
+// falls back to another domain until it is usable.
+func (s *shortDomainService) SetForCampaign(ctx context.Context, campaignID int64, req *SetShortDomainRequest) (*CampaignShortDomain, error) {
+	campaign, err := s.campaignRepo.GetByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ShortDomainID != nil {
+		// The domain must belong to the campaign's own account
+		if _, err := s.shortDomainRepo.GetByID(models.WithAccountID(ctx, campaign.AccountID), *req.ShortDomainID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.campaignRepo.SetShortDomain(ctx, campaignID, req.ShortDomainID); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("campaign short domain changed",
+		slog.Int64("campaign_id", campaignID),
+		slog.Any("short_domain_id", req.ShortDomainID),
+	)
+
+	campaign.ShortDomainID = req.ShortDomainID
+	return s.resolve(ctx, campaign)
+}
+
+// ForCampaign resolves the short domain of a campaign
+func (s *shortDomainService) ForCampaign(ctx context.Context, campaignID int64) (*CampaignShortDomain, error) {
+	campaign, err := s.campaignRepo.GetByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.resolve(ctx, campaign)
+}
+
+// resolve picks the first usable of the campaign's chosen domain, the
+// account's default and the account's other domains in name order
+func (s *shortDomainService) resolve(ctx context.Context, campaign *models.Campaign) (*CampaignShortDomain, error) {
+	domains, err := s.shortDomainRepo.List(models.WithAccountID(ctx, campaign.AccountID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list short domains: %w", err)
+	}
+
+	result := &CampaignShortDomain{CampaignID: campaign.ID, ShortDomainID: campaign.ShortDomainID}
+	pick := func(source string, match func(*models.ShortDomain) bool) bool {
+		for _, domain := range domains {
+			if domain.Usable() && match(domain) {
+				result.Domain = domain
+				result.Source = source
+				return true
+			}
+		}
+		return false
+	}
+
+	chosen := func(domain *models.ShortDomain) bool {
+		return campaign.ShortDomainID != nil && domain.ID == *campaign.ShortDomainID
+	}
+	isDefault := func(domain *models.ShortDomain) bool { return domain.IsDefault }
+	anyDomain := func(*models.ShortDomain) bool { return true }
+	if !pick(ShortDomainSourceCampaign, chosen) && !pick(ShortDomainSourceDefault, isDefault) {
+		pick(ShortDomainSourceFallback, anyDomain)
+	}
+
+	return result, nil
+}
+
+// normalizeDomain lowercases a domain name and drops surrounding whitespace
+// and a trailing root dot
+func normalizeDomain(domain string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+}
+
+// newShortDomainClient creates the HTTP client of short domain checks. It
+// does not follow redirects: a short domain's root commonly redirects to the
+// brand's site, whose health says nothing about the short domain.
+func newShortDomainClient() *http.Client {
+	return &http.Client{
+		Timeout: shortDomainCheckTimeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// probeShortDomain returns a check that a domain resolves and serves HTTPS
+// with a valid certificate. Any response below 500 passes, as the root of a
+// short domain often has no page of its own.
+func probeShortDomain(client *http.Client) func(ctx context.Context, domain string) error {
+	return func(ctx context.Context, domain string) error {
+		if _, err := net.DefaultResolver.LookupHost(ctx, domain); err != nil {
+			return fmt.Errorf("dns lookup failed: %w", err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, "https://"+domain+"/", nil)
+		if err != nil {
+			return fmt.Errorf("failed to create https request: %w", err)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("https request failed: %w", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("https request returned %s", resp.Status)
+		}
+		return nil
+	}
+}
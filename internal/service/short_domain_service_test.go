@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestShortDomainService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	shortDomainRepo := mocks.NewMockShortDomainRepository(ctrl)
+	svc := NewShortDomainService(shortDomainRepo, mocks.NewMockCampaignRepository(ctrl), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	shortDomainRepo.EXPECT().Create(gomock.Any(), &models.ShortDomain{Domain: "go.example.com", IsDefault: true, Enabled: true}).Return(nil)
+
+	domain, err := svc.Create(context.Background(), &ShortDomainRequest{Domain: " Go.Example.com. ", IsDefault: true})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if domain.Domain != "go.example.com" {
+		t.Errorf("Create() domain = %q, want it normalized", domain.Domain)
+	}
+
+	invalid := []string{"", "localhost", "https://go.example.com", "go.example.com:8443", "*.example.com", "-go.example.com", "10.0.0.1"}
+	for _, name := range invalid {
+		var appErr *models.AppError
+		if _, err := svc.Create(context.Background(), &ShortDomainRequest{Domain: name}); !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+			t.Errorf("Create(%q) error = %v, want INVALID_INPUT", name, err)
+		}
+	}
+}
+
+func TestShortDomainService_Check(t *testing.T) {
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	ctrl := gomock.NewController(t)
+	shortDomainRepo := mocks.NewMockShortDomainRepository(ctrl)
+	svc := NewShortDomainService(shortDomainRepo, mocks.NewMockCampaignRepository(ctrl), slog.New(slog.NewTextHandler(io.Discard, nil))).(*shortDomainService)
+	svc.now = func() time.Time { return now }
+	svc.probe = func(ctx context.Context, domain string) error {
+		if domain == "flagged.example.com" {
+			return errors.New("dns lookup failed: no such host")
+		}
+		return nil
+	}
+
+	shortDomainRepo.EXPECT().GetByID(gomock.Any(), int64(1)).
+		Return(&models.ShortDomain{ID: 1, AccountID: 2, Domain: "go.example.com", Enabled: true, Status: models.ShortDomainStatusUnchecked}, nil)
+	shortDomainRepo.EXPECT().RecordCheck(gomock.Any(), &models.ShortDomainCheck{DomainID: 1, Status: models.ShortDomainStatusHealthy, CheckedAt: now}).Return(nil)
+
+	domain, err := svc.Check(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if !domain.Usable() {
+		t.Errorf("Check() = %+v, want a usable domain", domain)
+	}
+
+	// A failed probe is recorded with its reason, in the domain's account
+	reason := "dns lookup failed: no such host"
+	shortDomainRepo.EXPECT().GetByID(gomock.Any(), int64(2)).
+		Return(&models.ShortDomain{ID: 2, AccountID: 2, Domain: "flagged.example.com", Enabled: true, Status: models.ShortDomainStatusHealthy}, nil)
+	shortDomainRepo.EXPECT().RecordCheck(gomock.Any(), &models.ShortDomainCheck{DomainID: 2, Status: models.ShortDomainStatusUnhealthy, Error: &reason, CheckedAt: now}).
+		DoAndReturn(func(ctx context.Context, check *models.ShortDomainCheck) error {
+			if account := models.AccountIDFromContext(ctx); account != 2 {
+				t.Errorf("RecordCheck() in account %d, want 2", account)
+			}
+			return nil
+		})
+
+	domain, err = svc.Check(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if domain.Usable() || domain.LastError == nil || *domain.LastError != reason {
+		t.Errorf("Check() = %+v, want an unusable domain with the failure", domain)
+	}
+}
+
+func TestShortDomainService_ForCampaign(t *testing.T) {
+	chosenID := int64(1)
+	chosen := &models.ShortDomain{ID: 1, Domain: "promo.example.com", Enabled: true, Status: models.ShortDomainStatusHealthy}
+	flagged := &models.ShortDomain{ID: 1, Domain: "promo.example.com", Enabled: true, Status: models.ShortDomainStatusUnhealthy}
+	defaultDomain := &models.ShortDomain{ID: 2, Domain: "go.example.com", IsDefault: true, Enabled: true, Status: models.ShortDomainStatusHealthy}
+	disabledDefault := &models.ShortDomain{ID: 2, Domain: "go.example.com", IsDefault: true, Status: models.ShortDomainStatusHealthy}
+	spare := &models.ShortDomain{ID: 3, Domain: "lnk.example.com", Enabled: true, Status: models.ShortDomainStatusHealthy}
+	unchecked := &models.ShortDomain{ID: 4, Domain: "new.example.com", Enabled: true, Status: models.ShortDomainStatusUnchecked}
+
+	tests := []struct {
+		name       string
+		chosen     *int64
+		domains    []*models.ShortDomain
+		wantID     int64
+		wantSource string
+	}{
+		{"chosen domain", &chosenID, []*models.ShortDomain{defaultDomain, chosen, spare}, 1, ShortDomainSourceCampaign},
+		{"nothing chosen", nil, []*models.ShortDomain{defaultDomain, chosen, spare}, 2, ShortDomainSourceDefault},
+		{"chosen domain flagged", &chosenID, []*models.ShortDomain{defaultDomain, flagged, spare}, 2, ShortDomainSourceDefault},
+		{"default disabled", &chosenID, []*models.ShortDomain{disabledDefault, flagged, spare}, 3, ShortDomainSourceFallback},
+		{"no usable domain", nil, []*models.ShortDomain{disabledDefault, flagged, unchecked}, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			shortDomainRepo := mocks.NewMockShortDomainRepository(ctrl)
+			campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+			svc := NewShortDomainService(shortDomainRepo, campaignRepo, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			campaignRepo.EXPECT().GetByID(gomock.Any(), int64(10)).Return(&models.Campaign{ID: 10, AccountID: 2, ShortDomainID: tt.chosen}, nil)
+			shortDomainRepo.EXPECT().List(gomock.Any()).Return(tt.domains, nil)
+
+			result, err := svc.ForCampaign(context.Background(), 10)
+			if err != nil {
+				t.Fatalf("ForCampaign() error = %v", err)
+			}
+
+			var gotID int64
+			if result.Domain != nil {
+				gotID = result.Domain.ID
+			}
+			if gotID != tt.wantID || result.Source != tt.wantSource {
+				t.Errorf("ForCampaign() = domain %d from %q, want %d from %q", gotID, result.Source, tt.wantID, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestShortDomainService_SetForCampaignChecksAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	shortDomainRepo := mocks.NewMockShortDomainRepository(ctrl)
+	campaignRepo := mocks.NewMockCampaignRepository(ctrl)
+	svc := NewShortDomainService(shortDomainRepo, campaignRepo, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	// A domain of another account is not found from the campaign's account
+	domainID := int64(5)
+	campaignRepo.EXPECT().GetByID(gomock.Any(), int64(10)).Return(&models.Campaign{ID: 10, AccountID: 2}, nil)
+	shortDomainRepo.EXPECT().GetByID(gomock.Any(), domainID).
+		DoAndReturn(func(ctx context.Context, id int64) (*models.ShortDomain, error) {
+			if account := models.AccountIDFromContext(ctx); account != 2 {
+				t.Errorf("GetByID() in account %d, want 2", account)
+			}
+			return nil, models.ErrNotFoundWithMsg("short domain with ID 5 not found")
+		})
+
+	if _, err := svc.SetForCampaign(context.Background(), 10, &SetShortDomainRequest{ShortDomainID: &domainID}); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("SetForCampaign() error = %v, want not found", err)
+	}
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// simulationTimeout bounds how long a single simulation may run in the background
+const simulationTimeout = 30 * time.Minute
+
+// SimulationSender delivers a rendered message during a simulation. The API
+// wires in the worker's mock sender, so nothing ever reaches a real provider.
+type SimulationSender interface {
+	Send(ctx context.Context, channel, phone, content string) (string, error)
+}
+
+// SimulationService runs campaigns through the send pipeline without delivering anything
+type SimulationService interface {
+	Start(ctx context.Context, campaignID int64, req *SendCampaignRequest) (*models.SimulationRun, error)
+	Get(ctx context.Context, campaignID, simulationID int64) (*models.SimulationRun, error)
+}
+
+// SimulationServiceConfig holds simulation tuning parameters
+type SimulationServiceConfig struct {
+	// SendConcurrency is the number of simulated sends in flight at once
+	SendConcurrency int
+	// WorkerConcurrency is the worker pool size used to project real send duration
+	WorkerConcurrency int
+}
+
+type simulationService struct {
+	campaigns      *campaignService
+	simulationRepo repository.SimulationRepository
+	sender         SimulationSender
+	config         SimulationServiceConfig
+	logger         *slog.Logger
+}
+
+// NewSimulationService creates a new simulation service
+func NewSimulationService(
+	campaignRepo repository.CampaignRepository,
+	customerRepo repository.CustomerRepository,
+	segmentRepo repository.SegmentRepository,
+	simulationRepo repository.SimulationRepository,
+	templateSvc TemplateService,
+	sender SimulationSender,
+	campaignConfig CampaignServiceConfig,
+	config SimulationServiceConfig,
+	logger *slog.Logger,
+) SimulationService {
+	if config.SendConcurrency < 1 {
+		config.SendConcurrency = 1
+	}
+	if config.WorkerConcurrency < 1 {
+		config.WorkerConcurrency = 1
+	}
+
+	// Audience paging and rendering are shared with real sends; the campaign
+	// service is never asked to create or publish messages here
+	campaigns := NewCampaignService(campaignRepo, customerRepo, nil, segmentRepo, templateSvc, nil, campaignConfig, logger).(*campaignService)
+
+	return &simulationService{
+		campaigns:      campaigns,
+		simulationRepo: simulationRepo,
+		sender:         sender,
+		config:         config,
+		logger:         logger,
+	}
+}
+
+// Start records a new simulation and runs it in the background.
+// The returned run is in 'running' status; poll Get for the results.
+func (s *simulationService) Start(ctx context.Context, campaignID int64, req *SendCampaignRequest) (*models.SimulationRun, error) {
+	if req.namesRecipients() {
+		if err := req.Validate(); err != nil {
+			return nil, err
+		}
+	} else if err := req.Exclude.Validate(); err != nil {
+		return nil, err
+	}
+
+	campaign, err := s.campaigns.campaignRepo.GetByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	ctx = models.WithAccountID(ctx, campaign.AccountID)
+
+	req, err = resolveAudience(campaign, req)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &models.SimulationRun{
+		CampaignID:        campaign.ID,
+		Status:            models.SimulationStatusRunning,
+		WorkerConcurrency: s.config.WorkerConcurrency,
+	}
+	if err := s.simulationRepo.Create(ctx, run); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("simulation started",
+		slog.Int64("campaign_id", campaign.ID),
+		slog.Int64("simulation_id", run.ID),
+	)
+
+	// The simulation outlives the request that started it
+	runCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), simulationTimeout)
+	go func() {
+		defer cancel()
+		s.run(runCtx, campaign, req, run)
+	}()
+
+	return run, nil
+}
+
+// Get retrieves a simulation of a campaign
+func (s *simulationService) Get(ctx context.Context, campaignID, simulationID int64) (*models.SimulationRun, error) {
+	return s.simulationRepo.GetByID(ctx, campaignID, simulationID)
+}
+
+// run executes the simulation and stores its results
+func (s *simulationService) run(ctx context.Context, campaign *models.Campaign, req *SendCampaignRequest, run *models.SimulationRun) {
+	if err := s.simulate(ctx, campaign, req, run); err != nil {
+		s.logger.Error("simulation failed",
+			slog.Int64("campaign_id", campaign.ID),
+			slog.Int64("simulation_id", run.ID),
+			slog.String("error", err.Error()),
+		)
+		if err := s.simulationRepo.Fail(context.WithoutCancel(ctx), run.ID, err.Error()); err != nil {
+			s.logger.Error("failed to record simulation failure",
+				slog.Int64("simulation_id", run.ID),
+				slog.String("error", err.Error()),
+			)
+		}
+		return
+	}
+
+	s.logger.Info("simulation completed",
+		slog.Int64("campaign_id", campaign.ID),
+		slog.Int64("simulation_id", run.ID),
+		slog.Int("audience_size", run.AudienceSize),
+		slog.Int64("projected_duration_ms", run.ProjectedDurationMs),
+	)
+}
+
+// simulate pages through the audience exactly like a real send, rendering each
+// batch and passing every message to the simulation sender. Messages land in the
+// shadow table instead of outbound_messages and nothing is queued.
+func (s *simulationService) simulate(ctx context.Context, campaign *models.Campaign, req *SendCampaignRequest, run *models.SimulationRun) error {
+	template, err := s.campaigns.templateSvc.ExpandPartials(ctx, campaign.BaseTemplate)
+	if err != nil {
+		return err
+	}
+	compiled := s.campaigns.templateSvc.Compile(template).ForCampaign(campaign)
+	source, err := s.campaigns.newAudienceSource(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	var renderTime, sendTime, totalLatency time.Duration
+	for batch := 1; ; batch++ {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("simulation stopped before audience batch %d: %w", batch, err)
+		}
+
+		customers, err := source.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch audience batch %d: %w", batch, err)
+		}
+		if len(customers) == 0 {
+			break
+		}
+
+		renderStart := time.Now()
+		messages := s.campaigns.buildMessages(ctx, campaign, compiled, customers)
+		renderTime += time.Since(renderStart)
+
+		sendStart := time.Now()
+		simulated, latency := s.sendBatch(ctx, campaign, run.ID, customers, messages)
+		sendTime += time.Since(sendStart)
+		totalLatency += latency
+
+		if err := s.simulationRepo.InsertMessages(ctx, simulated); err != nil {
+			return err
+		}
+
+		run.AudienceSize += len(simulated)
+		for _, message := range simulated {
+			if message.Status == models.MessageStatusSent {
+				run.SimulatedSent++
+			} else {
+				run.SimulatedFailed++
+			}
+		}
+	}
+
+	if run.AudienceSize == 0 {
+		return fmt.Errorf("no valid customers found to simulate")
+	}
+
+	run.RenderMs = renderTime.Milliseconds()
+	run.SendMs = sendTime.Milliseconds()
+	run.AvgSendLatencyMs = float64(totalLatency.Milliseconds()) / float64(run.AudienceSize)
+	run.ProjectedDurationMs = projectDuration(renderTime, totalLatency, run.WorkerConcurrency).Milliseconds()
+
+	return s.simulationRepo.Complete(ctx, run)
+}
+
+// sendBatch passes a rendered batch to the simulation sender over a bounded pool
+// of SendConcurrency goroutines. It returns the shadow rows in batch order and
+// the summed send latency.
+func (s *simulationService) sendBatch(
+	ctx context.Context,
+	campaign *models.Campaign,
+	simulationID int64,
+	customers []*models.Customer,
+	messages []*models.OutboundMessage,
+) ([]*models.SimulatedMessage, time.Duration) {
+	phones := make(map[int64]string, len(customers))
+	for _, customer := range customers {
+		phones[customer.ID] = customer.Phone
+	}
+
+	simulated := make([]*models.SimulatedMessage, len(messages))
+	latencies := make([]time.Duration, len(messages))
+
+	workers := s.config.SendConcurrency
+	if workers > len(messages) {
+		workers = len(messages)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				message := messages[i]
+
+				start := time.Now()
+				_, err := s.sender.Send(ctx, campaign.Channel, phones[message.CustomerID], message.RenderedContent)
+				latencies[i] = time.Since(start)
+
+				result := &models.SimulatedMessage{
+					SimulationID:    simulationID,
+					CustomerID:      message.CustomerID,
+					Status:          models.MessageStatusSent,
+					RenderedContent: message.RenderedContent,
+					LatencyMs:       int(latencies[i].Milliseconds()),
+				}
+				if err != nil {
+					errMsg := err.Error()
+					result.Status = models.MessageStatusFailed
+					result.LastError = &errMsg
+				}
+				simulated[i] = result
+			}
+		}()
+	}
+
+	for i := range messages {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	var total time.Duration
+	for _, latency := range latencies {
+		total += latency
+	}
+
+	return simulated, total
+}
+
+// projectDuration estimates how long a real send would take: rendering happens
+// up front in the API, then the worker pool drains the queue in parallel
+func projectDuration(renderTime, totalLatency time.Duration, workerConcurrency int) time.Duration {
+	if workerConcurrency < 1 {
+		workerConcurrency = 1
+	}
+	return renderTime + totalLatency/time.Duration(workerConcurrency)
+}
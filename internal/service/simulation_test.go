@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// fixedSender fails sends to one phone number and takes a fixed time per send
+type fixedSender struct {
+	failPhone string
+	latency   time.Duration
+}
+
+func (s *fixedSender) Send(ctx context.Context, channel, phone, content string) (string, error) {
+	time.Sleep(s.latency)
+	if phone == s.failPhone {
+		return "", errors.New("simulated failure")
+	}
+	return "fixed-" + phone, nil
+}
+
+func TestSimulationService_Simulate(t *testing.T) {
+	campaigns := &campaignStore{
+		all: []*models.Campaign{
+			{ID: 1, Channel: models.ChannelSMS, Status: models.CampaignStatusDraft, BaseTemplate: "Hi {first_name}"},
+		},
+	}
+	customers := &customerStore{byID: map[int64]*models.Customer{
+		1: {ID: 1, Phone: "+254700000001", FirstName: "Ann"},
+		2: {ID: 2, Phone: "+254700000002", FirstName: "Ben"},
+		3: {ID: 3, Phone: "+254700000003", FirstName: "Cy"},
+	}}
+	ctrl := gomock.NewController(t)
+	simulationRepo := mocks.NewMockSimulationRepository(ctrl)
+
+	var shadow []*models.SimulatedMessage
+	simulationRepo.EXPECT().InsertMessages(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, messages []*models.SimulatedMessage) error {
+			shadow = append(shadow, messages...)
+			return nil
+		}).
+		Times(2) // three customers in batches of two
+	simulationRepo.EXPECT().Complete(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, run *models.SimulationRun) error {
+			run.Status = models.SimulationStatusCompleted
+			return nil
+		})
+
+	svc := NewSimulationService(
+		campaigns.repo(t),
+		customers.repo(t),
+		nil,
+		simulationRepo,
+		NewTemplateService(nil, nil),
+		&fixedSender{failPhone: "+254700000002", latency: 10 * time.Millisecond},
+		CampaignServiceConfig{SendBatchSize: 2},
+		SimulationServiceConfig{SendConcurrency: 4, WorkerConcurrency: 2},
+		slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	).(*simulationService)
+
+	run := &models.SimulationRun{ID: 1, CampaignID: 1, Status: models.SimulationStatusRunning, WorkerConcurrency: 2}
+
+	req := &SendCampaignRequest{CustomerIDs: []int64{1, 2, 3}}
+	if err := svc.simulate(context.Background(), campaigns.all[0], req, run); err != nil {
+		t.Fatalf("simulate() error = %v", err)
+	}
+
+	if run.Status != models.SimulationStatusCompleted {
+		t.Errorf("status = %s, want completed", run.Status)
+	}
+	if run.AudienceSize != 3 || run.SimulatedSent != 2 || run.SimulatedFailed != 1 {
+		t.Errorf("audience/sent/failed = %d/%d/%d, want 3/2/1", run.AudienceSize, run.SimulatedSent, run.SimulatedFailed)
+	}
+	if run.AvgSendLatencyMs < 10 {
+		t.Errorf("avg send latency = %.1fms, want at least 10ms", run.AvgSendLatencyMs)
+	}
+	// Three 10ms sends spread over two workers take at least 15ms
+	if run.ProjectedDurationMs < 15 {
+		t.Errorf("projected duration = %dms, want at least 15ms", run.ProjectedDurationMs)
+	}
+
+	if len(shadow) != 3 || shadow[0].RenderedContent != "Hi Ann" {
+		t.Errorf("shadow messages = %d, want 3 rendered messages", len(shadow))
+	}
+	if campaigns.all[0].Status != models.CampaignStatusDraft {
+		t.Errorf("campaign status = %s, simulation must not change it", campaigns.all[0].Status)
+	}
+
+	if _, err := svc.Start(context.Background(), 1, &SendCampaignRequest{}); err == nil {
+		t.Error("Start() with no audience should fail validation")
+	}
+}
+
+func TestProjectDuration(t *testing.T) {
+	got := projectDuration(100*time.Millisecond, 10*time.Second, 5)
+	if want := 2100 * time.Millisecond; got != want {
+		t.Errorf("projectDuration() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestCampaignService_StreamMessages(t *testing.T) {
+	messages := &messageStore{}
+	for i := 0; i < 2500; i++ {
+		_ = messages.repo(t).Create(context.Background(), &models.OutboundMessage{CampaignID: 1, CustomerID: int64(i + 1)})
+	}
+	// Messages of other campaigns must not leak into the export
+	_ = messages.repo(t).Create(context.Background(), &models.OutboundMessage{CampaignID: 2, CustomerID: 1})
+
+	svc := &campaignService{
+		campaignRepo: (&campaignStore{all: []*models.Campaign{{ID: 1}, {ID: 2}}}).repo(t),
+		messageRepo:  messages.repo(t),
+		logger:       slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	}
+
+	var pages []int
+	var lastID int64
+	err := svc.StreamMessages(context.Background(), 1, 0, func(page []*models.OutboundMessage) error {
+		pages = append(pages, len(page))
+		for _, message := range page {
+			if message.ID <= lastID {
+				t.Fatalf("message %d streamed out of order after %d", message.ID, lastID)
+			}
+			lastID = message.ID
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamMessages() error = %v", err)
+	}
+
+	want := []int{1000, 1000, 500}
+	if len(pages) != len(want) {
+		t.Fatalf("pages = %v, want %v", pages, want)
+	}
+	for i := range want {
+		if pages[i] != want[i] {
+			t.Errorf("pages = %v, want %v", pages, want)
+			break
+		}
+	}
+}
+
+func TestCampaignService_StreamMessages_NotFound(t *testing.T) {
+	svc := &campaignService{
+		campaignRepo: (&campaignStore{}).repo(t),
+		messageRepo:  (&messageStore{}).repo(t),
+		logger:       slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	}
+
+	called := false
+	err := svc.StreamMessages(context.Background(), 42, 0, func(page []*models.OutboundMessage) error {
+		called = true
+		return nil
+	})
+
+	var appErr *models.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "NOT_FOUND" {
+		t.Errorf("StreamMessages() error = %v, want AppError with NOT_FOUND code", err)
+	}
+	if called {
+		t.Error("callback invoked for missing campaign")
+	}
+}
+
+func TestCampaignService_StreamFailures(t *testing.T) {
+	messages := &messageStore{}
+	for i := 0; i < 1500; i++ {
+		status := models.MessageStatusSent
+		if i%2 == 0 {
+			status = models.MessageStatusFailed
+		}
+		_ = messages.repo(t).Create(context.Background(), &models.OutboundMessage{CampaignID: 1, CustomerID: int64(i + 1), Status: status})
+	}
+	_ = messages.repo(t).Create(context.Background(), &models.OutboundMessage{CampaignID: 1, CustomerID: 9001, Status: models.MessageStatusUndelivered})
+	// Failures of other campaigns must not leak into the export
+	_ = messages.repo(t).Create(context.Background(), &models.OutboundMessage{CampaignID: 2, CustomerID: 1, Status: models.MessageStatusFailed})
+
+	svc := &campaignService{
+		campaignRepo: (&campaignStore{all: []*models.Campaign{{ID: 1}, {ID: 2}}}).repo(t),
+		messageRepo:  messages.repo(t),
+		logger:       slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	}
+
+	total := 0
+	var lastID int64
+	err := svc.StreamFailures(context.Background(), 1, func(page []*models.FailedRecipient) error {
+		for _, recipient := range page {
+			if recipient.MessageID <= lastID {
+				t.Fatalf("message %d exported out of order after %d", recipient.MessageID, lastID)
+			}
+			if recipient.Status == models.MessageStatusSent {
+				t.Fatalf("sent message %d exported as a failure", recipient.MessageID)
+			}
+			lastID = recipient.MessageID
+		}
+		total += len(page)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamFailures() error = %v", err)
+	}
+
+	if total != 751 {
+		t.Errorf("exported %d recipients, want 751", total)
+	}
+}
+
+func TestCampaignService_StreamFailures_NotFound(t *testing.T) {
+	svc := &campaignService{
+		campaignRepo: (&campaignStore{}).repo(t),
+		messageRepo:  (&messageStore{}).repo(t),
+		logger:       slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	}
+
+	err := svc.StreamFailures(context.Background(), 42, func(page []*models.FailedRecipient) error {
+		t.Error("callback invoked for missing campaign")
+		return nil
+	})
+
+	var appErr *models.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "NOT_FOUND" {
+		t.Errorf("StreamFailures() error = %v, want AppError with NOT_FOUND code", err)
+	}
+}
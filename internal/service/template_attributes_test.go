@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestTemplateService_ValidateTemplate_CustomerAttributes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	accountRepo := mocks.NewMockAccountRepository(ctrl)
+	svc := NewTemplateService(nil, accountRepo)
+	ctx := models.WithAccountID(context.Background(), 2)
+
+	accountRepo.EXPECT().GetByID(gomock.Any(), int64(2)).
+		Return(&models.Account{ID: 2, CustomerAttributes: []string{"loyalty_tier", "points2"}}, nil).
+		Times(2)
+
+	if err := svc.ValidateTemplate(ctx, "Hi {first_name}, you are {loyalty_tier} with {points2|number} points"); err != nil {
+		t.Errorf("ValidateTemplate() with declared attributes error = %v", err)
+	}
+
+	// The error lists the keys the account may use
+	var appErr *models.AppError
+	err := svc.ValidateTemplate(ctx, "Hi {first_name}, your {shoe_size} is in")
+	if !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+		t.Fatalf("ValidateTemplate() with an undeclared attribute error = %v, want INVALID_INPUT", err)
+	}
+	if !strings.Contains(err.Error(), "shoe_size") || !strings.Contains(err.Error(), "loyalty_tier, points2") {
+		t.Errorf("ValidateTemplate() error = %q, want the invalid placeholder and the declared attributes", err)
+	}
+
+	// Built-in placeholders need no lookup
+	if err := svc.ValidateTemplate(ctx, "Hi {first_name}"); err != nil {
+		t.Errorf("ValidateTemplate() with built-in placeholders error = %v", err)
+	}
+}
+
+func TestTemplateService_ValidateTemplate_CustomerAttributesOutsideAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	accountRepo := mocks.NewMockAccountRepository(ctrl)
+	svc := NewTemplateService(nil, accountRepo)
+
+	// Without an account, templates are checked against the default account
+	accountRepo.EXPECT().GetByID(gomock.Any(), models.DefaultAccountID).
+		Return(&models.Account{ID: models.DefaultAccountID, CustomerAttributes: []string{"loyalty_tier"}}, nil)
+
+	if err := svc.ValidateTemplate(context.Background(), "You are {loyalty_tier}"); err != nil {
+		t.Errorf("ValidateTemplate() error = %v", err)
+	}
+}
+
+func TestCompiledTemplate_RenderCustomerAttributes(t *testing.T) {
+	svc := NewTemplateService(nil, nil)
+	customer := &models.Customer{
+		FirstName:  "Alice",
+		Attributes: models.CustomerAttributes{"loyalty_tier": "gold"},
+	}
+
+	got, err := svc.Compile("Hi {first_name}, you are {loyalty_tier}{points|default:, welcome}").Render(customer)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if want := "Hi Alice, you are gold, welcome"; got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
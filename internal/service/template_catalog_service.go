@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// TemplateCatalogService serves the read-only catalog of starter templates.
+// Campaigns are created from an entry with catalog_template.
+type TemplateCatalogService interface {
+	// List retrieves the catalog, of one category when category is not empty
+	List(ctx context.Context, category string) ([]*models.CatalogTemplate, error)
+	Get(ctx context.Context, slug string) (*models.CatalogTemplate, error)
+}
+
+type templateCatalogService struct {
+	catalogRepo repository.TemplateCatalogRepository
+	templateSvc TemplateService
+}
+
+// NewTemplateCatalogService creates a new template catalog service
+func NewTemplateCatalogService(catalogRepo repository.TemplateCatalogRepository, templateSvc TemplateService) TemplateCatalogService {
+	return &templateCatalogService{
+		catalogRepo: catalogRepo,
+		templateSvc: templateSvc,
+	}
+}
+
+// List retrieves catalog templates with their placeholders
+func (s *templateCatalogService) List(ctx context.Context, category string) ([]*models.CatalogTemplate, error) {
+	templates, err := s.catalogRepo.List(ctx, category)
+	if err != nil {
+		return nil, err
+	}
+	for _, template := range templates {
+		template.Placeholders = uniquePlaceholders(s.templateSvc.ExtractPlaceholders(template.Content))
+	}
+	return templates, nil
+}
+
+// Get retrieves one catalog template with its placeholders
+func (s *templateCatalogService) Get(ctx context.Context, slug string) (*models.CatalogTemplate, error) {
+	template, err := s.catalogRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	template.Placeholders = uniquePlaceholders(s.templateSvc.ExtractPlaceholders(template.Content))
+	return template, nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestTemplateCatalogService_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	catalogRepo := mocks.NewMockTemplateCatalogRepository(ctrl)
+	svc := NewTemplateCatalogService(catalogRepo, NewTemplateService(nil, nil))
+
+	catalogRepo.EXPECT().List(gomock.Any(), "promo").Return([]*models.CatalogTemplate{
+		{Slug: "promo", Content: "Hi {first_name}, {preferred_product|default:our range} is on offer, {first_name}!"},
+	}, nil)
+
+	templates, err := svc.List(context.Background(), "promo")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if got := templates[0].Placeholders; !slices.Equal(got, []string{"first_name", "preferred_product"}) {
+		t.Errorf("Placeholders = %v, want [first_name preferred_product]", got)
+	}
+}
+
+func TestCampaignService_Create_FromCatalog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	catalogRepo := mocks.NewMockTemplateCatalogRepository(ctrl)
+	svc := &campaignService{
+		templateSvc: NewTemplateService(mocks.NewMockTemplatePartialRepository(ctrl), nil),
+		config:      CampaignServiceConfig{Catalog: catalogRepo},
+		logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	welcome := &models.CatalogTemplate{Slug: "welcome", Name: "Welcome", Channel: models.ChannelSMS, Content: "Hi {first_name}, welcome aboard!"}
+	catalogRepo.EXPECT().GetBySlug(gomock.Any(), "welcome").Return(welcome, nil).Times(2)
+
+	// The catalog fills in the name and channel the request leaves out
+	campaign, err := svc.newCampaign(context.Background(), &CreateCampaignRequest{CatalogTemplate: "welcome"})
+	if err != nil {
+		t.Fatalf("newCampaign() error = %v", err)
+	}
+	if campaign.BaseTemplate != welcome.Content || campaign.Name != "Welcome" || campaign.Channel != models.ChannelSMS {
+		t.Errorf("campaign = %q %q %q, want the catalog template's content, name and channel", campaign.Name, campaign.Channel, campaign.BaseTemplate)
+	}
+
+	campaign, err = svc.newCampaign(context.Background(), &CreateCampaignRequest{Name: "June welcome", Channel: models.ChannelWhatsApp, CatalogTemplate: "welcome"})
+	if err != nil {
+		t.Fatalf("newCampaign(named) error = %v", err)
+	}
+	if campaign.Name != "June welcome" || campaign.Channel != models.ChannelWhatsApp {
+		t.Errorf("campaign = %q %q, want the request's name and channel", campaign.Name, campaign.Channel)
+	}
+
+	// A missing entry is a bad request
+	catalogRepo.EXPECT().GetBySlug(gomock.Any(), "farewell").Return(nil, models.ErrNotFoundWithMsg(`catalog template "farewell" not found`))
+	var appErr *models.AppError
+	_, err = svc.newCampaign(context.Background(), &CreateCampaignRequest{CatalogTemplate: "farewell"})
+	if !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+		t.Errorf("newCampaign(missing entry) error = %v, want INVALID_INPUT", err)
+	}
+
+	// The catalog is one more template source, not combined with the others
+	_, err = svc.newCampaign(context.Background(), &CreateCampaignRequest{BaseTemplate: "Hi", CatalogTemplate: "welcome"})
+	if !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+		t.Errorf("newCampaign(both templates) error = %v, want INVALID_INPUT", err)
+	}
+}
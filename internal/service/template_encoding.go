@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// CheckEncoding validates a template and reports how its fixed text encodes
+// as SMS. Characters outside the GSM 7-bit alphabet cut every segment from 160
+// characters to 70, so each one is reported; with Substitute, smart quotes,
+// dashes and similar characters are first replaced by GSM look-alikes.
+// Placeholder values are not known until send time and are not counted; the
+// text of included partials is.
+func (s *templateService) CheckEncoding(ctx context.Context, req *TemplateEncodingRequest) (*TemplateEncodingResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	template := req.Template
+	if req.Substitute {
+		template = models.SubstituteGSM7(template)
+	}
+	expanded, err := s.ExpandPartials(ctx, template)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ValidateTemplate(ctx, expanded); err != nil {
+		return nil, err
+	}
+
+	fixed := s.placeholderPattern.ReplaceAllString(expanded, "")
+	encoding, single, multi := models.SMSEncoding(fixed)
+
+	result := &TemplateEncodingResult{
+		Template:                   template,
+		Substituted:                template != req.Template,
+		Encoding:                   encoding,
+		SegmentCharacters:          single,
+		MultipartSegmentCharacters: multi,
+		FixedLength:                utf8.RuneCountInString(fixed),
+		FixedSegments:              models.SMSSegments(fixed),
+		UCS2Characters:             models.UCS2Characters(fixed),
+		Warnings:                   make([]string, 0),
+	}
+
+	if len(result.UCS2Characters) > 0 {
+		warning := fmt.Sprintf("%s force UCS-2 encoding: each SMS segment holds %d characters instead of 160",
+			strings.Join(result.UCS2Characters, " "), single)
+		if substitutable := models.UCS2Characters(models.SubstituteGSM7(fixed)); !req.Substitute && len(substitutable) < len(result.UCS2Characters) {
+			warning += "; substitute replaces the smart quotes, dashes and spaces among them"
+		}
+		result.Warnings = append(result.Warnings, warning)
+	}
+	if result.FixedSegments > 1 {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("the template's fixed text alone takes %d SMS segments", result.FixedSegments))
+	}
+
+	return result, nil
+}
+
+// MeasureSMS counts content the way carriers bill it: in GSM-7 septets, or in
+// UTF-16 units once any character needs UCS-2
+func (s *templateService) MeasureSMS(content string) *SMSMeasure {
+	encoding, length := models.SMSLength(content)
+	return &SMSMeasure{
+		Encoding: encoding,
+		Length:   length,
+		Segments: models.SMSSegments(content),
+	}
+}
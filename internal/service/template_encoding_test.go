@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestTemplateService_CheckEncoding(t *testing.T) {
+	svc := NewTemplateService(nil, nil)
+
+	result, err := svc.CheckEncoding(context.Background(), &TemplateEncodingRequest{Template: "Hi {first_name}, don’t miss our sale — ends today"})
+	if err != nil {
+		t.Fatalf("CheckEncoding() error = %v", err)
+	}
+	if result.Encoding != models.SMSEncodingUCS2 || result.SegmentCharacters != 70 || result.MultipartSegmentCharacters != 67 {
+		t.Errorf("CheckEncoding() = %s %d/%d, want UCS-2 70/67", result.Encoding, result.SegmentCharacters, result.MultipartSegmentCharacters)
+	}
+	if strings.Join(result.UCS2Characters, "") != "’—" {
+		t.Errorf("UCS2Characters = %q, want ’ and —", result.UCS2Characters)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "substitute") {
+		t.Errorf("Warnings = %q, want a UCS-2 warning suggesting substitute", result.Warnings)
+	}
+
+	result, err = svc.CheckEncoding(context.Background(), &TemplateEncodingRequest{Template: "Hi {first_name}, don’t miss our sale — ends today", Substitute: true})
+	if err != nil {
+		t.Fatalf("CheckEncoding() with substitute error = %v", err)
+	}
+	if !result.Substituted || result.Template != "Hi {first_name}, don't miss our sale - ends today" {
+		t.Errorf("CheckEncoding() template = %q, want substituted", result.Template)
+	}
+	if result.Encoding != models.SMSEncodingGSM7 || result.SegmentCharacters != 160 || len(result.Warnings) != 0 {
+		t.Errorf("CheckEncoding() = %s %d %q, want GSM-7 160 without warnings", result.Encoding, result.SegmentCharacters, result.Warnings)
+	}
+
+	// Placeholders don't count towards the fixed text
+	result, err = svc.CheckEncoding(context.Background(), &TemplateEncodingRequest{Template: "{first_name} {last_name}"})
+	if err != nil {
+		t.Fatalf("CheckEncoding() error = %v", err)
+	}
+	if result.FixedLength != 1 || result.Encoding != models.SMSEncodingGSM7 {
+		t.Errorf("CheckEncoding() fixed length = %d %s, want 1 GSM-7", result.FixedLength, result.Encoding)
+	}
+
+	if _, err := svc.CheckEncoding(context.Background(), &TemplateEncodingRequest{Template: "Hi {nickname}"}); err == nil {
+		t.Error("CheckEncoding() with an invalid placeholder error = nil, want error")
+	}
+}
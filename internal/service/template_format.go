@@ -0,0 +1,181 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// Template formatters, written as {field|formatter:arg}
+const (
+	formatterNumber = "number" // {field|number} or {field|number:2} for fixed decimals
+	formatterMoney  = "money"  // {field|money:KES}
+	formatterDate   = "format" // {field|format:2 Jan}, a Go time layout
+	// {field|default:there} renders the fallback text when the field is empty
+	formatterDefault = "default"
+)
+
+// defaultLocale is used by campaigns that don't set one
+const defaultLocale = "en"
+
+// maxNumberDecimals is the most decimals {field|number:N} may ask for
+const maxNumberDecimals = 6
+
+// localeFormat holds a locale's number separators
+type localeFormat struct {
+	group   string
+	decimal string
+}
+
+// localeFormats are the locales a campaign can render numbers in
+var localeFormats = map[string]localeFormat{
+	"en": {group: ",", decimal: "."},
+	"sw": {group: ",", decimal: "."},
+	"fr": {group: " ", decimal: ","},
+	"de": {group: ".", decimal: ","},
+	"es": {group: ".", decimal: ","},
+	"pt": {group: ".", decimal: ","},
+}
+
+// validLocales lists the supported locales for error messages
+const validLocales = "en, sw, fr, de, es, pt"
+
+// isValidLocale checks if numbers can be rendered in the locale
+func isValidLocale(locale string) bool {
+	_, ok := localeFormats[locale]
+	return ok
+}
+
+// validateFormatter checks a placeholder's formatter and its argument
+func validateFormatter(field, formatter, arg string) error {
+	switch formatter {
+	case formatterNumber:
+		if arg == "" {
+			return nil
+		}
+		if decimals, err := strconv.Atoi(arg); err != nil || decimals < 0 || decimals > maxNumberDecimals {
+			return models.ErrInvalidInput(fmt.Sprintf("{%s|number:%s}: decimals must be between 0 and %d", field, arg, maxNumberDecimals))
+		}
+	case formatterMoney:
+		if !isCurrencyCode(arg) {
+			return models.ErrInvalidInput(fmt.Sprintf("{%s|money:%s}: currency must be a 3-letter code such as KES", field, arg))
+		}
+	case formatterDate:
+		if strings.TrimSpace(arg) == "" {
+			return models.ErrInvalidInput(fmt.Sprintf("{%s|format}: a date layout such as \"2 Jan\" is required", field))
+		}
+	case formatterDefault:
+		if strings.TrimSpace(arg) == "" {
+			return models.ErrInvalidInput(fmt.Sprintf("{%s|default}: fallback text such as \"there\" is required", field))
+		}
+	default:
+		return models.ErrInvalidInput(fmt.Sprintf("{%s|%s}: unknown formatter (valid formatters are: number, money, format, default)", field, formatter))
+	}
+	return nil
+}
+
+// isCurrencyCode reports whether code looks like an ISO 4217 code
+func isCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// formatValue applies a formatter to a field value. Values that are empty or
+// don't parse as a number or date are rendered unchanged, except that the
+// default formatter replaces an empty or blank value with its fallback.
+func formatValue(value, formatter, arg string, locale localeFormat) string {
+	if formatter == formatterDefault {
+		if strings.TrimSpace(value) == "" {
+			return arg
+		}
+		return value
+	}
+	if value == "" {
+		return value
+	}
+
+	switch formatter {
+	case formatterNumber:
+		n, ok := parseNumber(value)
+		if !ok {
+			return value
+		}
+		decimals := -1
+		if arg != "" {
+			decimals, _ = strconv.Atoi(arg)
+		}
+		return formatNumber(n, decimals, locale)
+	case formatterMoney:
+		n, ok := parseNumber(value)
+		if !ok {
+			return value
+		}
+		return arg + " " + formatNumber(n, 2, locale)
+	case formatterDate:
+		t, ok := parseDate(value)
+		if !ok {
+			return value
+		}
+		return t.Format(arg)
+	default:
+		return value
+	}
+}
+
+// formatNumber renders n with the locale's separators. Negative decimals use
+// as many as needed.
+func formatNumber(n float64, decimals int, locale localeFormat) string {
+	digits := strconv.FormatFloat(n, 'f', decimals, 64)
+
+	sign := ""
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+	whole, fraction, _ := strings.Cut(digits, ".")
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(locale.group)
+		}
+		b.WriteRune(digit)
+	}
+	if fraction != "" {
+		b.WriteString(locale.decimal)
+		b.WriteString(fraction)
+	}
+
+	return b.String()
+}
+
+// parseNumber reads a finite decimal number
+func parseNumber(value string) (float64, bool) {
+	n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, false
+	}
+	return n, true
+}
+
+// parseDate reads an RFC 3339 timestamp or a plain YYYY-MM-DD date
+func parseDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
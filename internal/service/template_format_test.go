@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestFormatValue(t *testing.T) {
+	en := localeFormats["en"]
+	de := localeFormats["de"]
+
+	tests := []struct {
+		name      string
+		value     string
+		formatter string
+		arg       string
+		locale    localeFormat
+		want      string
+	}{
+		{name: "money", value: "1250.5", formatter: "money", arg: "KES", locale: en, want: "KES 1,250.50"},
+		{name: "money in de", value: "1250.5", formatter: "money", arg: "EUR", locale: de, want: "EUR 1.250,50"},
+		{name: "negative money", value: "-1234567", formatter: "money", arg: "KES", locale: en, want: "KES -1,234,567.00"},
+		{name: "number", value: "1234567.125", formatter: "number", locale: en, want: "1,234,567.125"},
+		{name: "number with decimals", value: "999.999", formatter: "number", arg: "1", locale: de, want: "1.000,0"},
+		{name: "small number", value: "42", formatter: "number", locale: en, want: "42"},
+		{name: "date", value: "2025-06-01", formatter: "format", arg: "2 Jan", locale: en, want: "1 Jun"},
+		{name: "timestamp", value: "2025-12-24T18:30:00Z", formatter: "format", arg: "Mon 2 Jan 15:04", locale: en, want: "Wed 24 Dec 18:30"},
+		{name: "not a number", value: "Tea", formatter: "money", arg: "KES", locale: en, want: "Tea"},
+		{name: "infinity", value: "Inf", formatter: "number", locale: en, want: "Inf"},
+		{name: "not a date", value: "soon", formatter: "format", arg: "2 Jan", locale: en, want: "soon"},
+		{name: "empty", value: "", formatter: "money", arg: "KES", locale: en, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatValue(tt.value, tt.formatter, tt.arg, tt.locale); got != tt.want {
+				t.Errorf("formatValue(%q, %s:%s) = %q, want %q", tt.value, tt.formatter, tt.arg, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompiledTemplate_RenderFormatters(t *testing.T) {
+	svc := NewTemplateService(nil, nil)
+	customer := &models.Customer{FirstName: "Alice", PreferredProduct: "1500"}
+
+	compiled := svc.Compile("Hi {first_name}, your {preferred_product|money:KES} voucher is ready")
+
+	got, err := compiled.Render(customer)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if want := "Hi Alice, your KES 1,500.00 voucher is ready"; got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+
+	got, err = compiled.WithLocale("fr").Render(customer)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if want := "Hi Alice, your KES 1 500,00 voucher is ready"; got != want {
+		t.Errorf("Render() in fr = %q, want %q", got, want)
+	}
+
+	if placeholders := svc.ExtractPlaceholders(compiled.Source()); len(placeholders) != 2 || placeholders[1] != "preferred_product" {
+		t.Errorf("ExtractPlaceholders() = %v, want [first_name preferred_product]", placeholders)
+	}
+}
+
+func TestTemplateService_ValidateTemplate_Formatters(t *testing.T) {
+	svc := NewTemplateService(nil, nil)
+
+	valid := []string{
+		"Pay {preferred_product|money:KES}",
+		"{preferred_product|number} points, {preferred_product|number:2} exactly",
+		"See you on {location|format:Mon 2 Jan}",
+		"Hi {first_name|default:there}",
+		"Hi {first_name|default:valued customer: welcome back}",
+	}
+	for _, template := range valid {
+		if err := svc.ValidateTemplate(context.Background(), template); err != nil {
+			t.Errorf("ValidateTemplate(%q) error = %v", template, err)
+		}
+	}
+
+	invalid := []string{
+		"Pay {preferred_product|money:kes}",
+		"Pay {preferred_product|money}",
+		"{preferred_product|number:9}",
+		"{location|format}",
+		"{first_name|upper}",
+		"{amount|money:KES}",
+		"Hi {first_name|default}",
+		"Hi {first_name|default:}",
+		"Hi {first_name|default:   }",
+		"Hi {nickname|default:there}",
+	}
+	for _, template := range invalid {
+		var appErr *models.AppError
+		if err := svc.ValidateTemplate(context.Background(), template); !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+			t.Errorf("ValidateTemplate(%q) error = %v, want INVALID_INPUT", template, err)
+		}
+	}
+}
+
+func TestCompiledTemplate_RenderFallbacks(t *testing.T) {
+	svc := NewTemplateService(nil, nil)
+
+	tests := []struct {
+		name     string
+		template string
+		customer *models.Customer
+		values   map[string]string
+		want     string
+	}{
+		{
+			name:     "missing field uses fallback",
+			template: "Hi {first_name|default:there}, welcome!",
+			customer: &models.Customer{},
+			want:     "Hi there, welcome!",
+		},
+		{
+			name:     "present field ignores fallback",
+			template: "Hi {first_name|default:there}, welcome!",
+			customer: &models.Customer{FirstName: "Alice"},
+			want:     "Hi Alice, welcome!",
+		},
+		{
+			name:     "blank field uses fallback",
+			template: "Hi {first_name|default:there}!",
+			customer: &models.Customer{FirstName: "   "},
+			want:     "Hi there!",
+		},
+		{
+			name:     "fallback keeps spaces and punctuation",
+			template: "Dear {first_name|default:valued customer, as always:} thanks",
+			customer: &models.Customer{},
+			want:     "Dear valued customer, as always: thanks",
+		},
+		{
+			name:     "fallback per placeholder",
+			template: "{first_name|default:Friend} in {location|default:your city} and {location}",
+			customer: &models.Customer{FirstName: "Alice"},
+			want:     "Alice in your city and ",
+		},
+		{
+			name:     "empty resolved value uses fallback",
+			template: "Try {recommended_product|default:our new range}",
+			customer: &models.Customer{PreferredProduct: "Tea"},
+			values:   map[string]string{recommendedProductField: ""},
+			want:     "Try our new range",
+		},
+		{
+			name:     "fallback is not formatted",
+			template: "Hi {first_name|default:0}",
+			customer: &models.Customer{},
+			want:     "Hi 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.Compile(tt.template).RenderWith(tt.customer, tt.values)
+			if err != nil {
+				t.Fatalf("RenderWith() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RenderWith() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if placeholders := svc.ExtractPlaceholders("Hi {first_name|default:there}"); len(placeholders) != 1 || placeholders[0] != "first_name" {
+		t.Errorf("ExtractPlaceholders() = %v, want [first_name]", placeholders)
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// linkPattern finds the http and https URLs in a rendered message
+var linkPattern = regexp.MustCompile(`https?://[^\s<>"]+`)
+
+// linkTrailing are characters that end a sentence rather than a URL
+const linkTrailing = ".,;:!?)'"
+
+// WithUTM returns the template set to append the UTM parameters to every
+// link it renders. slug stands in for an unset utm campaign. A nil utm keeps
+// links as they are.
+func (t *CompiledTemplate) WithUTM(utm *models.CampaignUTM, slug string) *CompiledTemplate {
+	if utm == nil {
+		return t
+	}
+
+	tagged := *t
+	tagged.utm = utm.Params(slug)
+	return &tagged
+}
+
+// ForCampaign returns the template set to render with the campaign's locale
+// and UTM parameters
+func (t *CompiledTemplate) ForCampaign(campaign *models.Campaign) *CompiledTemplate {
+	return t.WithLocale(campaign.Locale).WithUTM(campaign.UTM, campaign.Slug)
+}
+
+// tagLinks appends params to the query of every URL in content. A parameter
+// the URL already has is left as it is, so links tagged by hand keep their
+// own values; text that does not parse as a URL is left untouched.
+func tagLinks(content string, params [][2]string) string {
+	if len(params) == 0 || !strings.Contains(content, "http") {
+		return content
+	}
+
+	return linkPattern.ReplaceAllStringFunc(content, func(match string) string {
+		link := strings.TrimRight(match, linkTrailing)
+		trailing := match[len(link):]
+
+		u, err := url.Parse(link)
+		if err != nil || u.Host == "" {
+			return match
+		}
+
+		query := u.Query()
+		for _, param := range params {
+			if query.Has(param[0]) {
+				continue
+			}
+			if u.RawQuery != "" {
+				u.RawQuery += "&"
+			}
+			u.RawQuery += param[0] + "=" + url.QueryEscape(param[1])
+		}
+		return u.String() + trailing
+	})
+}
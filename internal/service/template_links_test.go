@@ -0,0 +1,104 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestTagLinks(t *testing.T) {
+	params := (&models.CampaignUTM{Source: "sms", Medium: "campaign"}).Params("june-sale")
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "plain link",
+			content: "Shop now https://shop.example/sale",
+			want:    "Shop now https://shop.example/sale?utm_source=sms&utm_medium=campaign&utm_campaign=june-sale",
+		},
+		{
+			name:    "existing query and fragment",
+			content: "https://shop.example/p?id=7#top",
+			want:    "https://shop.example/p?id=7&utm_source=sms&utm_medium=campaign&utm_campaign=june-sale#top",
+		},
+		{
+			name:    "tagged by hand",
+			content: "https://shop.example/?utm_source=flyer",
+			want:    "https://shop.example/?utm_source=flyer&utm_medium=campaign&utm_campaign=june-sale",
+		},
+		{
+			name:    "sentence punctuation",
+			content: "See https://shop.example/sale. Ends (https://shop.example/t)!",
+			want:    "See https://shop.example/sale?utm_source=sms&utm_medium=campaign&utm_campaign=june-sale. Ends (https://shop.example/t?utm_source=sms&utm_medium=campaign&utm_campaign=june-sale)!",
+		},
+		{
+			name:    "several links",
+			content: "http://a.example and https://b.example/x",
+			want:    "http://a.example?utm_source=sms&utm_medium=campaign&utm_campaign=june-sale and https://b.example/x?utm_source=sms&utm_medium=campaign&utm_campaign=june-sale",
+		},
+		{
+			name:    "no links",
+			content: "Reply STOP to opt out",
+			want:    "Reply STOP to opt out",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tagLinks(tt.content, params); got != tt.want {
+				t.Errorf("tagLinks(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompiledTemplate_ForCampaign_TagsRenderedLinks(t *testing.T) {
+	svc := NewTemplateService(nil, nil)
+	customer := &models.Customer{FirstName: "Amina", Attributes: map[string]string{"store": "westlands"}}
+
+	campaign := &models.Campaign{
+		Slug:   "june-sale",
+		Locale: "en",
+		UTM:    &models.CampaignUTM{Source: "sms", Campaign: "June Sale", Content: "{store}"},
+	}
+	compiled := svc.Compile("Hi {first_name}, visit https://shop.example/{store}").ForCampaign(campaign)
+
+	// Links from placeholders are tagged too, and values are escaped
+	got, err := compiled.Render(customer)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	want := "Hi Amina, visit https://shop.example/westlands?utm_source=sms&utm_campaign=June+Sale&utm_content=%7Bstore%7D"
+	if got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+
+	// Without parameters links are left alone
+	campaign.UTM = nil
+	got, _ = svc.Compile("https://shop.example").ForCampaign(campaign).Render(customer)
+	if strings.Contains(got, "utm_") {
+		t.Errorf("Render() without utm = %q, want the link untagged", got)
+	}
+}
+
+func TestCampaignUTM_Validate(t *testing.T) {
+	invalid := []*models.CampaignUTM{
+		{},
+		{Source: "  ", Medium: "sms"},
+		{Source: "sms", Term: strings.Repeat("a", 101)},
+	}
+	for _, utm := range invalid {
+		var appErr *models.AppError
+		if err := utm.Validate(); !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+			t.Errorf("Validate(%+v) error = %v, want INVALID_INPUT", utm, err)
+		}
+	}
+	if err := (&models.CampaignUTM{Source: "sms"}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// TemplatePartialService manages the partials templates include with {>name}
+type TemplatePartialService interface {
+	Create(ctx context.Context, req *TemplatePartialRequest) (*models.TemplatePartial, error)
+	GetByName(ctx context.Context, name string) (*models.TemplatePartial, error)
+	List(ctx context.Context) ([]*models.TemplatePartial, error)
+	// Update replaces a partial's content; unsent campaigns that include it
+	// use the new content from their next validation or send
+	Update(ctx context.Context, name string, req *TemplatePartialRequest) (*models.TemplatePartial, error)
+	Delete(ctx context.Context, name string) error
+}
+
+type templatePartialService struct {
+	partialRepo repository.TemplatePartialRepository
+	templateSvc TemplateService
+	logger      *slog.Logger
+}
+
+// NewTemplatePartialService creates a new template partial service
+func NewTemplatePartialService(
+	partialRepo repository.TemplatePartialRepository,
+	templateSvc TemplateService,
+	logger *slog.Logger,
+) TemplatePartialService {
+	return &templatePartialService{
+		partialRepo: partialRepo,
+		templateSvc: templateSvc,
+		logger:      logger,
+	}
+}
+
+// Create saves a new partial
+func (s *templatePartialService) Create(ctx context.Context, req *TemplatePartialRequest) (*models.TemplatePartial, error) {
+	partial := &models.TemplatePartial{
+		Name:    strings.TrimSpace(req.Name),
+		Content: req.Content,
+	}
+	if err := s.validate(ctx, partial); err != nil {
+		return nil, err
+	}
+
+	if err := s.partialRepo.Create(ctx, partial); err != nil {
+		s.logger.Error("failed to create template partial",
+			slog.String("name", partial.Name),
+			slog.String("error", err.Error()),
+		)
+		return nil, err
+	}
+
+	s.logger.Info("template partial created", slog.String("name", partial.Name))
+
+	return partial, nil
+}
+
+// GetByName retrieves a partial
+func (s *templatePartialService) GetByName(ctx context.Context, name string) (*models.TemplatePartial, error) {
+	return s.partialRepo.GetByName(ctx, name)
+}
+
+// List retrieves every partial
+func (s *templatePartialService) List(ctx context.Context) ([]*models.TemplatePartial, error) {
+	return s.partialRepo.List(ctx)
+}
+
+// Update replaces a partial's content
+func (s *templatePartialService) Update(ctx context.Context, name string, req *TemplatePartialRequest) (*models.TemplatePartial, error) {
+	partial := &models.TemplatePartial{
+		Name:    name,
+		Content: req.Content,
+	}
+	if err := s.validate(ctx, partial); err != nil {
+		return nil, err
+	}
+
+	if err := s.partialRepo.Update(ctx, partial); err != nil {
+		s.logger.Error("failed to update template partial",
+			slog.String("name", name),
+			slog.String("error", err.Error()),
+		)
+		return nil, err
+	}
+
+	s.logger.Info("template partial updated", slog.String("name", name))
+
+	return partial, nil
+}
+
+// Delete removes a partial that no unsent campaign includes
+func (s *templatePartialService) Delete(ctx context.Context, name string) error {
+	if err := s.partialRepo.Delete(ctx, name); err != nil {
+		s.logger.Error("failed to delete template partial",
+			slog.String("name", name),
+			slog.String("error", err.Error()),
+		)
+		return err
+	}
+
+	s.logger.Info("template partial deleted", slog.String("name", name))
+
+	return nil
+}
+
+// validate checks the partial and the placeholders in its content
+func (s *templatePartialService) validate(ctx context.Context, partial *models.TemplatePartial) error {
+	if err := partial.Validate(); err != nil {
+		return err
+	}
+	return s.templateSvc.ValidateTemplate(ctx, partial.Content)
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestTemplateService_ExpandPartials(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	partialRepo := mocks.NewMockTemplatePartialRepository(ctrl)
+	svc := NewTemplateService(partialRepo, nil)
+
+	partialRepo.EXPECT().GetByNames(gomock.Any(), []string{"signature", "footer"}).
+		Return(map[string]*models.TemplatePartial{
+			"footer":    {Name: "footer", Content: "Reply STOP to opt out"},
+			"signature": {Name: "signature", Content: "- Team {location}"},
+		}, nil)
+
+	got, err := svc.ExpandPartials(context.Background(), "Hi {first_name}! {>signature}\n{>footer} {>footer}")
+	if err != nil {
+		t.Fatalf("ExpandPartials() error = %v", err)
+	}
+	if want := "Hi {first_name}! - Team {location}\nReply STOP to opt out Reply STOP to opt out"; got != want {
+		t.Errorf("ExpandPartials() = %q, want %q", got, want)
+	}
+	if err := svc.ValidateTemplate(context.Background(), got); err != nil {
+		t.Errorf("ValidateTemplate() of the expansion error = %v", err)
+	}
+
+	// Templates without partials are not looked up
+	if got, err := svc.ExpandPartials(context.Background(), "Hi {first_name}"); err != nil || got != "Hi {first_name}" {
+		t.Errorf("ExpandPartials() = %q, %v, want the template unchanged", got, err)
+	}
+
+	partialRepo.EXPECT().GetByNames(gomock.Any(), []string{"legal"}).Return(map[string]*models.TemplatePartial{}, nil)
+	var appErr *models.AppError
+	if _, err := svc.ExpandPartials(context.Background(), "Hi {>legal}"); !errors.As(err, &appErr) || appErr.Message != "unknown partials: legal" {
+		t.Errorf("ExpandPartials() error = %v, want unknown partials: legal", err)
+	}
+
+	// Skipping expansion must not send the reference as text
+	if err := svc.ValidateTemplate(context.Background(), "Hi {>footer}"); !errors.As(err, &appErr) {
+		t.Errorf("ValidateTemplate() of an unexpanded template error = %v, want INVALID_INPUT", err)
+	}
+}
+
+func TestTemplatePartialService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	partialRepo := mocks.NewMockTemplatePartialRepository(ctrl)
+	svc := NewTemplatePartialService(partialRepo, NewTemplateService(partialRepo, nil), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	partialRepo.EXPECT().Create(gomock.Any(), &models.TemplatePartial{Name: "footer", Content: "Reply STOP to opt out"}).Return(nil)
+	if _, err := svc.Create(context.Background(), &TemplatePartialRequest{Name: " footer ", Content: "Reply STOP to opt out"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	invalid := []*TemplatePartialRequest{
+		{Name: "Footer", Content: "x"},
+		{Name: "footer-2", Content: "x"},
+		{Name: "footer"},
+		{Name: "footer", Content: "See {>legal}"},
+		{Name: "footer", Content: "Bye {nickname}"},
+	}
+	for _, req := range invalid {
+		var appErr *models.AppError
+		if _, err := svc.Create(context.Background(), req); !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+			t.Errorf("Create(%+v) error = %v, want INVALID_INPUT", req, err)
+		}
+	}
+}
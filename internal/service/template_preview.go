@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// persona is a built-in synthetic customer used to preview templates without
+// real customer data
+type persona struct {
+	name        string
+	description string
+	customer    models.Customer
+}
+
+// syntheticPersonas cover the edge cases template authors most often miss
+var syntheticPersonas = []persona{
+	{
+		name:        "short_name",
+		description: "Typical customer with short values in every field",
+		customer: models.Customer{
+			Phone:            "+254700000001",
+			FirstName:        "Al",
+			LastName:         "Wu",
+			Location:         "Nyeri",
+			PreferredProduct: "Tea",
+		},
+	},
+	{
+		name:        "long_name",
+		description: "Long values that push messages past a single SMS segment",
+		customer: models.Customer{
+			Phone:            "+254700000002",
+			FirstName:        "Maximiliana-Alexandrina",
+			LastName:         "Wolfeschlegelsteinhausenbergerdorff",
+			Location:         "Kakamega County Headquarters",
+			PreferredProduct: "Premium Family Data Bundle 100GB Monthly",
+		},
+	},
+	{
+		name:        "missing_fields",
+		description: "Customer with only a phone number, so every other placeholder is empty",
+		customer: models.Customer{
+			Phone: "+254700000003",
+		},
+	},
+	{
+		name:        "unicode_name",
+		description: "Accented and non-Latin characters, which force UCS-2 encoding on SMS",
+		customer: models.Customer{
+			Phone:            "+254700000004",
+			FirstName:        "Zoë",
+			LastName:         "Nguyễn",
+			Location:         "Münster",
+			PreferredProduct: "Café au lait ☕",
+		},
+	},
+}
+
+// PreviewPersonas renders a template against the built-in synthetic personas
+func (s *templateService) PreviewPersonas(ctx context.Context, req *TemplatePreviewRequest) (*TemplatePreviewResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	expanded, err := s.ExpandPartials(ctx, req.Template)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ValidateTemplate(ctx, expanded); err != nil {
+		return nil, err
+	}
+
+	selected, err := selectPersonas(req.Personas)
+	if err != nil {
+		return nil, err
+	}
+
+	compiled := s.Compile(expanded)
+	placeholders := uniquePlaceholders(s.ExtractPlaceholders(expanded))
+
+	result := &TemplatePreviewResult{
+		Template:     req.Template,
+		Placeholders: placeholders,
+		Previews:     make([]*PersonaPreview, 0, len(selected)),
+	}
+	for _, p := range selected {
+		customer := p.customer
+		rendered, err := compiled.Render(&customer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render persona %s: %w", p.name, err)
+		}
+
+		empty := make([]string, 0)
+		for _, placeholder := range placeholders {
+			if customerFieldValue(&customer, placeholder) == "" {
+				empty = append(empty, placeholder)
+			}
+		}
+
+		encoding, _, _ := models.SMSEncoding(rendered)
+		result.Previews = append(result.Previews, &PersonaPreview{
+			Persona:           p.name,
+			Description:       p.description,
+			Customer:          &customer,
+			Rendered:          rendered,
+			Length:            utf8.RuneCountInString(rendered),
+			Encoding:          encoding,
+			Segments:          models.SMSSegments(rendered),
+			EmptyPlaceholders: empty,
+		})
+	}
+
+	return result, nil
+}
+
+// selectPersonas returns the named personas in request order, or all of them
+func selectPersonas(names []string) ([]persona, error) {
+	if len(names) == 0 {
+		return syntheticPersonas, nil
+	}
+
+	selected := make([]persona, 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, p := range syntheticPersonas {
+			if p.name == name {
+				selected = append(selected, p)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, models.ErrInvalidInput(fmt.Sprintf("unknown persona: %s (valid personas are: %s)", name, strings.Join(personaNames(), ", ")))
+		}
+	}
+
+	return selected, nil
+}
+
+// personaNames lists the built-in persona names
+func personaNames() []string {
+	names := make([]string, len(syntheticPersonas))
+	for i, p := range syntheticPersonas {
+		names[i] = p.name
+	}
+	return names
+}
+
+// uniquePlaceholders drops repeated placeholders, keeping first-use order
+func uniquePlaceholders(placeholders []string) []string {
+	seen := make(map[string]bool, len(placeholders))
+	unique := make([]string, 0, len(placeholders))
+	for _, placeholder := range placeholders {
+		if !seen[placeholder] {
+			seen[placeholder] = true
+			unique = append(unique, placeholder)
+		}
+	}
+	return unique
+}
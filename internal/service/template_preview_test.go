@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestTemplateService_PreviewPersonas(t *testing.T) {
+	svc := NewTemplateService(nil, nil)
+
+	result, err := svc.PreviewPersonas(context.Background(), &TemplatePreviewRequest{Template: "Hi {first_name} {first_name}, {preferred_product} is back!"})
+	if err != nil {
+		t.Fatalf("PreviewPersonas() error = %v", err)
+	}
+
+	if len(result.Previews) != len(syntheticPersonas) {
+		t.Fatalf("previews = %d, want one per persona (%d)", len(result.Previews), len(syntheticPersonas))
+	}
+	if len(result.Placeholders) != 2 {
+		t.Errorf("Placeholders = %v, want first_name and preferred_product once each", result.Placeholders)
+	}
+
+	byPersona := make(map[string]*PersonaPreview, len(result.Previews))
+	for _, preview := range result.Previews {
+		byPersona[preview.Persona] = preview
+	}
+
+	if got := byPersona["short_name"].Rendered; got != "Hi Al Al, Tea is back!" {
+		t.Errorf("short_name rendered = %q", got)
+	}
+	if got := byPersona["missing_fields"].EmptyPlaceholders; len(got) != 2 {
+		t.Errorf("missing_fields empty placeholders = %v, want both", got)
+	}
+	// Length counts characters, not bytes
+	unicode := byPersona["unicode_name"]
+	if want := len([]rune(unicode.Rendered)); unicode.Length != want || unicode.Length == len(unicode.Rendered) {
+		t.Errorf("unicode_name length = %d, want %d characters", unicode.Length, want)
+	}
+}
+
+func TestTemplateService_PreviewPersonas_Invalid(t *testing.T) {
+	svc := NewTemplateService(nil, nil)
+
+	tests := []struct {
+		name string
+		req  *TemplatePreviewRequest
+	}{
+		{name: "empty template", req: &TemplatePreviewRequest{}},
+		{name: "unknown placeholder", req: &TemplatePreviewRequest{Template: "Hi {nickname}"}},
+		{name: "unknown persona", req: &TemplatePreviewRequest{Template: "Hi", Personas: []string{"robot"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.PreviewPersonas(context.Background(), tt.req)
+			var appErr *models.AppError
+			if !errors.As(err, &appErr) || appErr.Code != "INVALID_INPUT" {
+				t.Errorf("PreviewPersonas() error = %v, want INVALID_INPUT", err)
+			}
+		})
+	}
+}
@@ -1,28 +1,187 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
 )
 
 // TemplateService handles template rendering and validation
 type TemplateService interface {
 	Render(template string, customer *models.Customer) (string, error)
-	ValidateTemplate(template string) error
+	Compile(template string) *CompiledTemplate
+	// ValidateTemplate checks template syntax and that every placeholder is a
+	// built-in field or a customer attribute the account of ctx declares
+	ValidateTemplate(ctx context.Context, template string) error
 	ExtractPlaceholders(template string) []string
+	// ExpandPartials replaces each {>name} in template with the partial's
+	// content. Templates are stored unexpanded, so expand them before every
+	// validation and render.
+	ExpandPartials(ctx context.Context, template string) (string, error)
+	PreviewPersonas(ctx context.Context, req *TemplatePreviewRequest) (*TemplatePreviewResult, error)
+	CheckEncoding(ctx context.Context, req *TemplateEncodingRequest) (*TemplateEncodingResult, error)
+	// MeasureSMS reports the encoding, length and segments of a rendered
+	// message
+	MeasureSMS(content string) *SMSMeasure
 }
 
 type templateService struct {
 	placeholderPattern *regexp.Regexp
+	partialPattern     *regexp.Regexp
+	partialRepo        repository.TemplatePartialRepository
+	accountRepo        repository.AccountRepository
 }
 
-// NewTemplateService creates a new template service
-func NewTemplateService() TemplateService {
+// builtinPlaceholders are the customer fields every template may use
+var builtinPlaceholders = []string{"first_name", "last_name", "location", "preferred_product", recommendedProductField, "phone"}
+
+// NewTemplateService creates a new template service. partialRepo holds the
+// partials templates may include; with nil, templates that include one are
+// invalid. accountRepo holds the customer attributes each account's templates
+// may use; with nil, only the built-in placeholders are valid.
+func NewTemplateService(partialRepo repository.TemplatePartialRepository, accountRepo repository.AccountRepository) TemplateService {
 	return &templateService{
-		placeholderPattern: regexp.MustCompile(`\{([a-z_]+)\}`),
+		placeholderPattern: regexp.MustCompile(`\{([a-z_][a-z0-9_]*)(?:\|([A-Za-z_]*)(?::([^{}]*))?)?\}`),
+		partialPattern:     regexp.MustCompile(`\{>([a-z][a-z0-9_]*)\}`),
+		partialRepo:        partialRepo,
+		accountRepo:        accountRepo,
+	}
+}
+
+// CompiledTemplate is a template pre-split into literal and placeholder tokens.
+// Compile once and render it for every recipient to avoid per-customer regex work.
+type CompiledTemplate struct {
+	source string
+	tokens []templateToken
+	size   int // total literal length, used to pre-size the output buffer
+	locale localeFormat
+	// utm holds the query parameters appended to every rendered link
+	utm [][2]string
+}
+
+// templateToken is either a literal chunk of text or a placeholder field name
+// with an optional formatter
+type templateToken struct {
+	literal   string
+	field     string
+	formatter string
+	arg       string
+}
+
+// Compile parses template into a reusable CompiledTemplate
+func (s *templateService) Compile(template string) *CompiledTemplate {
+	compiled := &CompiledTemplate{source: template, locale: localeFormats[defaultLocale]}
+
+	last := 0
+	for _, loc := range s.placeholderPattern.FindAllStringSubmatchIndex(template, -1) {
+		if loc[0] > last {
+			compiled.tokens = append(compiled.tokens, templateToken{literal: template[last:loc[0]]})
+			compiled.size += loc[0] - last
+		}
+		token := templateToken{field: template[loc[2]:loc[3]]}
+		if loc[4] >= 0 {
+			token.formatter = template[loc[4]:loc[5]]
+		}
+		if loc[6] >= 0 {
+			token.arg = template[loc[6]:loc[7]]
+		}
+		compiled.tokens = append(compiled.tokens, token)
+		last = loc[1]
+	}
+	if last < len(template) {
+		compiled.tokens = append(compiled.tokens, templateToken{literal: template[last:]})
+		compiled.size += len(template) - last
+	}
+
+	return compiled
+}
+
+// Source returns the original template text
+func (t *CompiledTemplate) Source() string {
+	return t.source
+}
+
+// WithLocale returns the template set to format numbers for locale. Unknown
+// locales keep the default.
+func (t *CompiledTemplate) WithLocale(locale string) *CompiledTemplate {
+	format, ok := localeFormats[locale]
+	if !ok {
+		return t
+	}
+
+	localized := *t
+	localized.locale = format
+	return &localized
+}
+
+// Uses reports whether the template has a placeholder for field
+func (t *CompiledTemplate) Uses(field string) bool {
+	for _, token := range t.tokens {
+		if token.field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Render replaces placeholders with customer data
+// Missing fields and unknown placeholders are replaced with empty strings,
+// or with the fallback of a {field|default:text} placeholder
+func (t *CompiledTemplate) Render(customer *models.Customer) (string, error) {
+	return t.RenderWith(customer, nil)
+}
+
+// RenderWith renders the template with values taking precedence over the
+// customer's own fields, e.g. a resolved {recommended_product}
+func (t *CompiledTemplate) RenderWith(customer *models.Customer, values map[string]string) (string, error) {
+	if customer == nil {
+		return "", models.ErrInvalidInput("customer cannot be nil")
+	}
+
+	var b strings.Builder
+	b.Grow(t.size + 16*len(t.tokens))
+	for _, token := range t.tokens {
+		if token.field == "" {
+			b.WriteString(token.literal)
+			continue
+		}
+		value, ok := values[token.field]
+		if !ok {
+			value = customerFieldValue(customer, token.field)
+		}
+		if token.formatter != "" {
+			value = formatValue(value, token.formatter, token.arg, t.locale)
+		}
+		b.WriteString(value)
+	}
+
+	return tagLinks(b.String(), t.utm), nil
+}
+
+// customerFieldValue maps a placeholder name to the customer's value, which
+// for a placeholder other than a built-in field is the customer attribute of
+// that name. Unknown placeholders resolve to an empty string.
+func customerFieldValue(customer *models.Customer, field string) string {
+	switch field {
+	case "first_name":
+		return customer.FirstName
+	case "last_name":
+		return customer.LastName
+	case "location":
+		return customer.Location
+	case "preferred_product", recommendedProductField:
+		// Without a recommendation, the customer's own preference is the best guess
+		return customer.PreferredProduct
+	case "phone":
+		return customer.Phone
+	default:
+		return customer.Attributes[field]
 	}
 }
 
@@ -33,68 +192,133 @@ func (s *templateService) Render(template string, customer *models.Customer) (st
 		return "", models.ErrInvalidInput("customer cannot be nil")
 	}
 
-	// Map customer fields to their values
-	fieldMap := map[string]string{
-		"first_name":        customer.FirstName,
-		"last_name":         customer.LastName,
-		"location":          customer.Location,
-		"preferred_product": customer.PreferredProduct,
-		"phone":             customer.Phone,
+	return s.Compile(template).Render(customer)
+}
+
+// ExpandPartials inlines the partials a template includes, looking them all
+// up in one query. Partials cannot include partials, so one pass suffices.
+func (s *templateService) ExpandPartials(ctx context.Context, template string) (string, error) {
+	matches := s.partialPattern.FindAllStringSubmatch(template, -1)
+	if len(matches) == 0 {
+		return template, nil
 	}
 
-	// Replace all placeholders
-	result := s.placeholderPattern.ReplaceAllStringFunc(template, func(match string) string {
-		// Extract field name from {field_name}
-		fieldName := strings.Trim(match, "{}")
+	names := make([]string, 0, len(matches))
+	seen := make(map[string]bool, len(matches))
+	for _, match := range matches {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			names = append(names, match[1])
+		}
+	}
 
-		// Return field value or empty string if not found
-		if value, exists := fieldMap[fieldName]; exists {
-			return value
+	partials := map[string]*models.TemplatePartial{}
+	if s.partialRepo != nil {
+		var err error
+		if partials, err = s.partialRepo.GetByNames(ctx, names); err != nil {
+			return "", err
 		}
+	}
 
-		// Unknown placeholder - return empty string
-		return ""
-	})
+	var unknown []string
+	for _, name := range names {
+		if partials[name] == nil {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return "", models.ErrInvalidInput(fmt.Sprintf("unknown partials: %s", strings.Join(unknown, ", ")))
+	}
 
-	return result, nil
+	return s.partialPattern.ReplaceAllStringFunc(template, func(ref string) string {
+		return partials[ref[2:len(ref)-1]].Content
+	}), nil
 }
 
-// ValidateTemplate checks if template syntax is valid
-func (s *templateService) ValidateTemplate(template string) error {
+// ValidateTemplate checks if template syntax is valid. Partials must have been
+// expanded with ExpandPartials first.
+func (s *templateService) ValidateTemplate(ctx context.Context, template string) error {
 	if template == "" {
 		return models.ErrInvalidInput("template cannot be empty")
 	}
 
-	// Extract all placeholders
-	placeholders := s.ExtractPlaceholders(template)
+	// An unexpanded partial would be sent as literal text
+	if match := s.partialPattern.FindStringSubmatch(template); match != nil {
+		return models.ErrInvalidInput(fmt.Sprintf("partial %s must be expanded before the template is validated", match[1]))
+	}
 
-	// Define valid placeholders
-	validPlaceholders := map[string]bool{
-		"first_name":        true,
-		"last_name":         true,
-		"location":          true,
-		"preferred_product": true,
-		"phone":             true,
+	// Anything but a built-in field must be a declared customer attribute
+	var unknown []string
+	for _, placeholder := range s.ExtractPlaceholders(template) {
+		if !slices.Contains(builtinPlaceholders, placeholder) {
+			unknown = append(unknown, placeholder)
+		}
 	}
 
-	// Check for invalid placeholders
-	var invalidPlaceholders []string
-	for _, placeholder := range placeholders {
-		if !validPlaceholders[placeholder] {
-			invalidPlaceholders = append(invalidPlaceholders, placeholder)
+	if len(unknown) > 0 {
+		attributes, err := s.customerAttributes(ctx)
+		if err != nil {
+			return err
+		}
+
+		var invalidPlaceholders []string
+		for _, placeholder := range unknown {
+			if !slices.Contains(attributes, placeholder) {
+				invalidPlaceholders = append(invalidPlaceholders, placeholder)
+			}
+		}
+
+		if len(invalidPlaceholders) > 0 {
+			valid := strings.Join(builtinPlaceholders, ", ")
+			if len(attributes) > 0 {
+				valid += fmt.Sprintf(" and the customer attributes %s", strings.Join(attributes, ", "))
+			}
+			return models.ErrInvalidInput(
+				fmt.Sprintf("invalid placeholders: %s. Valid placeholders are: %s",
+					strings.Join(invalidPlaceholders, ", "), valid),
+			)
 		}
 	}
 
-	if len(invalidPlaceholders) > 0 {
-		return models.ErrInvalidInput(
-			fmt.Sprintf("invalid placeholders: %s. Valid placeholders are: first_name, last_name, location, preferred_product, phone",
-				strings.Join(invalidPlaceholders, ", ")),
-		)
+	// Check formatters such as {amount|money:KES} and fallbacks such as
+	// {first_name|default:there}
+	for _, match := range s.placeholderPattern.FindAllStringSubmatchIndex(template, -1) {
+		if match[4] < 0 {
+			continue
+		}
+		arg := ""
+		if match[6] >= 0 {
+			arg = template[match[6]:match[7]]
+		}
+		if err := validateFormatter(template[match[2]:match[3]], template[match[4]:match[5]], arg); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// customerAttributes returns the customer attribute keys declared by the
+// account of ctx
+func (s *templateService) customerAttributes(ctx context.Context) ([]string, error) {
+	if s.accountRepo == nil {
+		return nil, nil
+	}
+
+	accountID := models.AccountIDFromContext(ctx)
+	if accountID == 0 {
+		accountID = models.DefaultAccountID
+	}
+
+	account, err := s.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return account.CustomerAttributes, nil
+}
+
 // ExtractPlaceholders returns all placeholders found in template
 func (s *templateService) ExtractPlaceholders(template string) []string {
 	matches := s.placeholderPattern.FindAllStringSubmatch(template, -1)
@@ -130,7 +130,7 @@ func TestTemplateService_Render(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			svc := NewTemplateService()
+			svc := NewTemplateService(nil, nil)
 			got, err := svc.Render(tt.template, tt.customer)
 
 			if (err != nil) != tt.wantErr {
@@ -185,7 +185,7 @@ func TestTemplateService_ExtractPlaceholders(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			svc := NewTemplateService()
+			svc := NewTemplateService(nil, nil)
 			got := svc.ExtractPlaceholders(tt.template)
 
 			if len(got) != len(tt.want) {
@@ -203,7 +203,7 @@ func TestTemplateService_ExtractPlaceholders(t *testing.T) {
 }
 
 func BenchmarkTemplateService_Render(b *testing.B) {
-	svc := NewTemplateService()
+	svc := NewTemplateService(nil, nil)
 	template := "Hi {first_name} {last_name}, check out {preferred_product} in {location}! Call {phone}"
 	customer := &models.Customer{
 		FirstName:        "Alice",
@@ -218,3 +218,66 @@ func BenchmarkTemplateService_Render(b *testing.B) {
 		_, _ = svc.Render(template, customer)
 	}
 }
+
+func TestCompiledTemplate_MatchesRender(t *testing.T) {
+	svc := NewTemplateService(nil, nil)
+	customer := &models.Customer{
+		FirstName:        "Alice",
+		LastName:         "Mwangi",
+		Location:         "Nairobi",
+		PreferredProduct: "Running Shoes",
+		Phone:            "+254712345001",
+	}
+
+	templates := []string{
+		"",
+		"Plain text message",
+		"{first_name}",
+		"Hi {first_name}, yes {first_name}!",
+		"{first_name}{last_name}",
+		"Hi {first_name and {last_name}",
+		"Hi {unknown} {First_Name} { first_name }",
+		"مرحبا {first_name} — {location}",
+	}
+
+	for _, template := range templates {
+		want, err := svc.Render(template, customer)
+		if err != nil {
+			t.Fatalf("Render(%q) error = %v", template, err)
+		}
+
+		compiled := svc.Compile(template)
+		got, err := compiled.Render(customer)
+		if err != nil {
+			t.Fatalf("Compile(%q).Render() error = %v", template, err)
+		}
+
+		if got != want {
+			t.Errorf("Compile(%q).Render() = %q, want %q", template, got, want)
+		}
+		if compiled.Source() != template {
+			t.Errorf("Source() = %q, want %q", compiled.Source(), template)
+		}
+	}
+
+	if _, err := svc.Compile("Hi {first_name}").Render(nil); err == nil {
+		t.Error("Render(nil) error = nil, want error")
+	}
+}
+
+func BenchmarkCompiledTemplate_Render(b *testing.B) {
+	svc := NewTemplateService(nil, nil)
+	compiled := svc.Compile("Hi {first_name} {last_name}, check out {preferred_product} in {location}! Call {phone}")
+	customer := &models.Customer{
+		FirstName:        "Alice",
+		LastName:         "Mwangi",
+		Location:         "Nairobi",
+		PreferredProduct: "Running Shoes",
+		Phone:            "+254712345001",
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = compiled.Render(customer)
+	}
+}
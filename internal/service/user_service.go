@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// UserService manages the operators who may sign in
+type UserService interface {
+	Create(ctx context.Context, req *CreateUserRequest) (*models.User, error)
+	List(ctx context.Context) ([]*models.User, error)
+	UpdateRole(ctx context.Context, id int64, req *UpdateUserRoleRequest) (*models.User, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type userService struct {
+	userRepo repository.UserRepository
+	logger   *slog.Logger
+}
+
+// NewUserService creates a new user service
+func NewUserService(userRepo repository.UserRepository, logger *slog.Logger) UserService {
+	return &userService{
+		userRepo: userRepo,
+		logger:   logger,
+	}
+}
+
+// Create adds a user
+func (s *userService) Create(ctx context.Context, req *CreateUserRequest) (*models.User, error) {
+	user, err := newUser(normalizeEmail(req.Email), req.Password, req.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		s.logger.Error("failed to create user", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	s.logger.Info("user created",
+		slog.Int64("user_id", user.ID),
+		slog.String("role", user.Role),
+	)
+
+	return user, nil
+}
+
+// List retrieves every user
+func (s *userService) List(ctx context.Context) ([]*models.User, error) {
+	return s.userRepo.List(ctx)
+}
+
+// UpdateRole changes a user's role. Tokens already issued keep the old role
+// until they expire.
+func (s *userService) UpdateRole(ctx context.Context, id int64, req *UpdateUserRoleRequest) (*models.User, error) {
+	if !models.IsValidRole(req.Role) {
+		return nil, models.ErrInvalidInput("invalid role (must be 'viewer', 'editor', 'sender' or 'admin')")
+	}
+
+	if err := s.userRepo.UpdateRole(ctx, id, req.Role); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("user role updated",
+		slog.Int64("user_id", id),
+		slog.String("role", req.Role),
+	)
+
+	return s.userRepo.GetByID(ctx, id)
+}
+
+// Delete removes a user
+func (s *userService) Delete(ctx context.Context, id int64) error {
+	if err := s.userRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.logger.Info("user deleted", slog.Int64("user_id", id))
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/events"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// AlertTypeCampaignFailed is raised when every message of a campaign failed
+const AlertTypeCampaignFailed = "campaign_failed"
+
+// Alert is an operational event that needs human attention
+type Alert struct {
+	Type        string    `json:"type"`
+	Message     string    `json:"message"`
+	Channel     string    `json:"channel,omitempty"`
+	CampaignIDs []int64   `json:"campaign_ids,omitempty"`
+	At          time.Time `json:"at"`
+}
+
+// Alerter delivers alerts to operators
+type Alerter interface {
+	Alert(ctx context.Context, alert Alert) error
+}
+
+// logAlerter writes alerts to the log at error level
+type logAlerter struct {
+	logger *slog.Logger
+}
+
+// webhookAlerter posts alerts as JSON to a webhook URL, and logs them
+type webhookAlerter struct {
+	url    string
+	client *http.Client
+	logAlerter
+}
+
+// NewAlerter creates an alerter that posts to webhookURL, or only logs when it is empty
+func NewAlerter(webhookURL string, logger *slog.Logger) Alerter {
+	if webhookURL == "" {
+		return &logAlerter{logger: logger}
+	}
+
+	return &webhookAlerter{
+		url:        webhookURL,
+		client:     &http.Client{Timeout: 5 * time.Second},
+		logAlerter: logAlerter{logger: logger},
+	}
+}
+
+// Alert logs the alert
+func (a *logAlerter) Alert(ctx context.Context, alert Alert) error {
+	a.logger.Error("ALERT",
+		slog.String("type", alert.Type),
+		slog.String("message", alert.Message),
+		slog.String("channel", alert.Channel),
+		slog.Any("campaign_ids", alert.CampaignIDs),
+	)
+	return nil
+}
+
+// Alert logs the alert and posts it to the webhook
+func (a *webhookAlerter) Alert(ctx context.Context, alert Alert) error {
+	_ = a.logAlerter.Alert(ctx, alert)
+
+	if err := postJSON(ctx, a.client, a.url, alert); err != nil {
+		return fmt.Errorf("failed to post alert: %w", err)
+	}
+
+	return nil
+}
+
+// AlertOnFailedCampaigns raises an alert for every campaign completed on bus
+// with all of its messages failed
+func AlertOnFailedCampaigns(bus *events.Bus, alerter Alerter, logger *slog.Logger) {
+	events.Subscribe(bus, func(ctx context.Context, event events.CampaignCompleted) {
+		if event.Status != models.CampaignStatusFailed {
+			return
+		}
+
+		err := alerter.Alert(ctx, Alert{
+			Type:        AlertTypeCampaignFailed,
+			Message:     fmt.Sprintf("all %d messages of the campaign failed; see GET /api/messages?campaign_id=%d for their errors", event.Stats.Failed, event.CampaignID),
+			CampaignIDs: []int64{event.CampaignID},
+			At:          event.At,
+		})
+		if err != nil {
+			logger.Error("failed to send campaign failure alert",
+				slog.Int64("campaign_id", event.CampaignID),
+				slog.String("error", err.Error()),
+			)
+		}
+	})
+}
+
+// postJSON posts v as JSON to url and fails on a non-2xx response
+func postJSON(ctx context.Context, client *http.Client, url string, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// Soft bounce re-attempt tuning
+const (
+	bounceRetryInterval  = 5 * time.Minute
+	bounceRetryBatchSize = 1000
+)
+
+// BounceRetrier sends soft bounced messages again once their account's retry
+// delay has passed. It only makes them pending again; the outbox relay
+// publishes them like any other message that was never queued.
+type BounceRetrier struct {
+	messageRepo repository.OutboundMessageRepository
+	logger      *slog.Logger
+}
+
+// NewBounceRetrier creates a new bounce retrier
+func NewBounceRetrier(messageRepo repository.OutboundMessageRepository, logger *slog.Logger) *BounceRetrier {
+	return &BounceRetrier{
+		messageRepo: messageRepo,
+		logger:      logger,
+	}
+}
+
+// Run re-attempts due soft bounces at start-up and then every few minutes
+// until ctx is done
+func (b *BounceRetrier) Run(ctx context.Context) {
+	ticker := time.NewTicker(bounceRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		b.retry(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// retry re-attempts due soft bounces in batches until none is left
+func (b *BounceRetrier) retry(ctx context.Context) {
+	var total int64
+	for ctx.Err() == nil {
+		retried, due, err := b.messageRepo.RetrySoftBounces(ctx, bounceRetryBatchSize)
+		if err != nil {
+			b.logger.Error("failed to retry soft bounces", slog.String("error", err.Error()))
+			break
+		}
+		total += retried
+		if due < bounceRetryBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		b.logger.Info("soft bounced messages re-attempted", slog.Int64("messages", total))
+	}
+}
@@ -0,0 +1,37 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+)
+
+func TestBounceRetrier_RetriesInBatches(t *testing.T) {
+	// A full batch of unsendable messages must not stop the sweep early
+	due, unsendable := int64(2*bounceRetryBatchSize+10), int64(bounceRetryBatchSize)
+	batches := 0
+
+	repo := mocks.NewMockOutboundMessageRepository(gomock.NewController(t))
+	repo.EXPECT().RetrySoftBounces(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, limit int) (int64, int64, error) {
+		batches++
+		batch := min(due, int64(limit))
+		dropped := min(unsendable, batch)
+		due -= batch
+		unsendable -= dropped
+		return batch - dropped, batch, nil
+	}).AnyTimes()
+
+	NewBounceRetrier(repo, slog.New(slog.NewJSONHandler(os.Stdout, nil))).retry(context.Background())
+
+	if due != 0 {
+		t.Errorf("due = %d, want every due soft bounce handled", due)
+	}
+	if batches != 3 {
+		t.Errorf("batches = %d, want 3", batches)
+	}
+}
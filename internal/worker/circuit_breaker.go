@@ -0,0 +1,156 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// breakerState tracks send outcomes for one channel
+type breakerState struct {
+	failures int       // consecutive failures
+	openedAt time.Time // zero while the circuit is closed
+	retryAt  time.Time // when the next trial send is allowed
+	trial    bool      // a trial send is in flight
+}
+
+// CircuitBreaker stops sending on a channel after consecutive provider failures.
+// While open, jobs for the channel are deferred instead of burning retries; after
+// the cooldown a single trial send decides whether the circuit closes again.
+type CircuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu     sync.Mutex
+	states map[string]*breakerState
+}
+
+// NewCircuitBreaker creates a per-channel circuit breaker that opens after
+// threshold consecutive failures and allows a trial send every cooldown
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+		states:    make(map[string]*breakerState),
+	}
+}
+
+// Wrap returns a sender that reports every send outcome to the breaker
+func (b *CircuitBreaker) Wrap(sender MessageSender) MessageSender {
+	return &breakerSender{sender: sender, breaker: b}
+}
+
+// SetThresholds changes the failure threshold and cooldown. Open circuits keep
+// their current retry time and pick up the new cooldown on their next failure.
+func (b *CircuitBreaker) SetThresholds(threshold int, cooldown time.Duration) {
+	if threshold < 1 {
+		threshold = 1
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.threshold = threshold
+	b.cooldown = cooldown
+}
+
+// Check implements SendGate, deferring jobs while the channel's circuit is open
+func (b *CircuitBreaker) Check(ctx context.Context, campaign *models.Campaign, message *models.OutboundMessage) (time.Time, error) {
+	// Test campaigns go to the provider's sandbox, which the circuit does not track
+	if campaign.IsTest() {
+		return time.Time{}, nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state, ok := b.states[campaign.Channel]
+	if !ok || state.openedAt.IsZero() {
+		return time.Time{}, nil
+	}
+
+	now := b.now()
+	if state.trial || now.Before(state.retryAt) {
+		if state.retryAt.After(now) {
+			return state.retryAt, nil
+		}
+		return now.Add(b.cooldown), nil
+	}
+
+	// Cooldown elapsed: let this job through as the trial send
+	state.trial = true
+	return time.Time{}, nil
+}
+
+// OpenChannels returns the channels whose circuit is open, with the time it opened
+func (b *CircuitBreaker) OpenChannels() map[string]time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	open := make(map[string]time.Time)
+	for channel, state := range b.states {
+		if !state.openedAt.IsZero() {
+			open[channel] = state.openedAt
+		}
+	}
+	return open
+}
+
+// record updates the channel's state with a send outcome
+func (b *CircuitBreaker) record(channel string, sendErr error) {
+	// Shutdown is not a provider failure
+	if errors.Is(sendErr, context.Canceled) {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state, ok := b.states[channel]
+	if !ok {
+		state = &breakerState{}
+		b.states[channel] = state
+	}
+
+	if sendErr == nil {
+		*state = breakerState{}
+		return
+	}
+
+	now := b.now()
+	state.failures++
+	state.trial = false
+
+	if !state.openedAt.IsZero() {
+		// Trial failed: stay open for another cooldown
+		state.retryAt = now.Add(b.cooldown)
+		return
+	}
+
+	if state.failures >= b.threshold {
+		state.openedAt = now
+		state.retryAt = now.Add(b.cooldown)
+	}
+}
+
+// breakerSender reports send outcomes to a circuit breaker
+type breakerSender struct {
+	sender  MessageSender
+	breaker *CircuitBreaker
+}
+
+// Send sends and records the outcome for the channel
+func (s *breakerSender) Send(ctx context.Context, channel, phone, content string) (string, error) {
+	providerMessageID, err := s.sender.Send(ctx, channel, phone, content)
+	s.breaker.record(channel, err)
+	return providerMessageID, err
+}
@@ -0,0 +1,180 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// recordingAlerter records raised alerts
+type recordingAlerter struct {
+	alerts []Alert
+}
+
+func (a *recordingAlerter) Alert(ctx context.Context, alert Alert) error {
+	a.alerts = append(a.alerts, alert)
+	return nil
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(3, 30*time.Second)
+	breaker.now = func() time.Time { return now }
+
+	failing := &testMockSender{shouldFail: true}
+	sender := breaker.Wrap(failing)
+	sms := &models.Campaign{Channel: "sms"}
+	whatsapp := &models.Campaign{Channel: "whatsapp"}
+
+	check := func(campaign *models.Campaign) time.Time {
+		t.Helper()
+		deferUntil, err := breaker.Check(context.Background(), campaign, &models.OutboundMessage{})
+		if err != nil {
+			t.Fatalf("Check() error = %v", err)
+		}
+		return deferUntil
+	}
+
+	for i := 0; i < 3; i++ {
+		if !check(sms).IsZero() {
+			t.Fatalf("circuit opened after %d failures, want 3", i)
+		}
+		_, _ = sender.Send(context.Background(), "sms", "+254712345001", "hi")
+	}
+
+	if got, want := check(sms), now.Add(30*time.Second); !got.Equal(want) {
+		t.Fatalf("open circuit deferred until %v, want %v", got, want)
+	}
+	if !check(whatsapp).IsZero() {
+		t.Error("whatsapp deferred by an sms outage")
+	}
+	if _, ok := breaker.OpenChannels()["sms"]; !ok {
+		t.Error("OpenChannels() is missing sms")
+	}
+
+	// After the cooldown a single trial is let through
+	now = now.Add(31 * time.Second)
+	if !check(sms).IsZero() {
+		t.Fatal("trial send was deferred after cooldown")
+	}
+	if check(sms).IsZero() {
+		t.Fatal("second send allowed while trial is in flight")
+	}
+
+	// Successful trial closes the circuit
+	failing.shouldFail = false
+	_, _ = sender.Send(context.Background(), "sms", "+254712345001", "hi")
+	if !check(sms).IsZero() {
+		t.Error("circuit still open after successful trial")
+	}
+	if len(breaker.OpenChannels()) != 0 {
+		t.Errorf("OpenChannels() = %v, want none", breaker.OpenChannels())
+	}
+}
+
+func TestCircuitBreaker_IgnoresCancellation(t *testing.T) {
+	breaker := NewCircuitBreaker(1, time.Minute)
+	breaker.record("sms", context.Canceled)
+
+	if len(breaker.OpenChannels()) != 0 {
+		t.Error("context cancellation opened the circuit")
+	}
+
+	breaker.record("sms", errors.New("provider down"))
+	if len(breaker.OpenChannels()) != 1 {
+		t.Error("provider failure did not open the circuit")
+	}
+}
+
+func TestOutageMonitor_PausesAfterThreshold(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(1, 30*time.Second)
+	breaker.now = func() time.Time { return now }
+	breaker.record("sms", errors.New("provider down"))
+
+	campaigns := &campaignStore{
+		byID: map[int64]*models.CampaignWithStats{
+			1: {ID: 1, Channel: "sms", Status: models.CampaignStatusSending},
+			2: {ID: 2, Channel: "whatsapp", Status: models.CampaignStatusSending},
+			3: {ID: 3, Channel: "sms", Status: models.CampaignStatusDraft},
+		},
+	}
+	alerter := &recordingAlerter{}
+
+	monitor := NewOutageMonitor(breaker, campaigns.repo(t), alerter, 5*time.Minute, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+	monitor.now = func() time.Time { return now.Add(4 * time.Minute) }
+	monitor.check(context.Background())
+	if campaigns.byID[1].Status != models.CampaignStatusSending {
+		t.Fatal("campaign paused before the outage threshold")
+	}
+
+	monitor.now = func() time.Time { return now.Add(6 * time.Minute) }
+	monitor.check(context.Background())
+
+	if campaigns.byID[1].Status != models.CampaignStatusPaused {
+		t.Errorf("sms campaign status = %s, want paused", campaigns.byID[1].Status)
+	}
+	if campaigns.byID[1].PausedReason == nil {
+		t.Error("paused campaign has no reason")
+	}
+	if campaigns.byID[2].Status != models.CampaignStatusSending {
+		t.Errorf("whatsapp campaign status = %s, want sending", campaigns.byID[2].Status)
+	}
+	if campaigns.byID[3].Status != models.CampaignStatusDraft {
+		t.Errorf("draft campaign status = %s, want draft", campaigns.byID[3].Status)
+	}
+	if len(alerter.alerts) != 1 || alerter.alerts[0].Type != AlertTypeCampaignsPaused {
+		t.Fatalf("alerts = %+v, want one %s alert", alerter.alerts, AlertTypeCampaignsPaused)
+	}
+
+	// Still down: nothing new to pause, so no repeated alert
+	monitor.check(context.Background())
+	if len(alerter.alerts) != 1 {
+		t.Errorf("alerts = %d, want 1", len(alerter.alerts))
+	}
+}
+
+func TestMessageProcessor_Process_SkipsPausedAndSent(t *testing.T) {
+	messages := &messageStore{
+		byID: map[int64]*models.OutboundMessage{
+			1: {ID: 1, CampaignID: 1, CustomerID: 1, Status: models.MessageStatusPending},
+			2: {ID: 2, CampaignID: 2, CustomerID: 1, Status: models.MessageStatusSent},
+		},
+	}
+	campaigns := &campaignStore{
+		byID: map[int64]*models.CampaignWithStats{
+			1: {ID: 1, Channel: "sms", Status: models.CampaignStatusPaused},
+			2: {ID: 2, Channel: "sms", Status: models.CampaignStatusSending},
+		},
+	}
+	customers := &customerStore{
+		byID: map[int64]*models.Customer{1: {ID: 1, Phone: "+254712345001"}},
+	}
+	sender := &testMockSender{}
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	processor := NewMessageProcessor(messages.repo(t), campaigns.repo(t), customers.repo(t), sender, nil, 3, logger)
+
+	for id := int64(1); id <= 2; id++ {
+		if err := processor.Process(context.Background(), &models.MessageJob{OutboundMessageID: id}); err != nil {
+			t.Fatalf("Process(%d) error = %v", id, err)
+		}
+	}
+
+	if len(sender.calls) != 0 {
+		t.Errorf("Expected no sends, got %d", len(sender.calls))
+	}
+	if len(messages.updates) != 0 {
+		t.Errorf("Expected no status updates, got %d", len(messages.updates))
+	}
+	// Only the dropped job of the paused campaign is published again on resume
+	if len(messages.unqueued) != 1 || messages.unqueued[0] != 1 {
+		t.Errorf("unqueued = %v, want [1]", messages.unqueued)
+	}
+}
@@ -0,0 +1,67 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/ratelimit"
+)
+
+const (
+	// inFlightLeaseTTL frees the slot of a worker that died mid-send. It must
+	// outlast the slowest provider call.
+	inFlightLeaseTTL = 2 * time.Minute
+
+	// inFlightRetryDelay is how long a job waits when all of its campaign's
+	// slots are held
+	inFlightRetryDelay = time.Second
+)
+
+// ConcurrencyLimit caps how many messages of a campaign are being sent at
+// once across all workers, for campaigns with max_in_flight set. A send holds
+// a slot of the campaign's shared semaphore until it has been recorded.
+type ConcurrencyLimit struct {
+	semaphore ratelimit.Semaphore
+	logger    *slog.Logger
+}
+
+// NewConcurrencyLimit creates a concurrency limit backed by semaphore
+func NewConcurrencyLimit(semaphore ratelimit.Semaphore, logger *slog.Logger) *ConcurrencyLimit {
+	return &ConcurrencyLimit{semaphore: semaphore, logger: logger}
+}
+
+// Acquire takes a send slot of the campaign. It returns false while all of the
+// campaign's slots are held; the message must not be sent then. The returned
+// release func gives the slot back and must be called once the send is done.
+func (l *ConcurrencyLimit) Acquire(ctx context.Context, campaign *models.Campaign) (func(), bool, error) {
+	if campaign.MaxInFlight == nil {
+		return func() {}, true, nil
+	}
+
+	key := inFlightKey(campaign.ID)
+	lease, ok, err := l.semaphore.Acquire(ctx, key, *campaign.MaxInFlight, inFlightLeaseTTL)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	release := func() {
+		// Give the slot back even if the job's context is done; a slot left
+		// behind would only be freed by its TTL
+		if err := l.semaphore.Release(context.WithoutCancel(ctx), key, lease); err != nil {
+			l.logger.Error("failed to release send slot",
+				slog.Int64("campaign_id", campaign.ID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	return release, true, nil
+}
+
+// inFlightKey is the semaphore key of a campaign's send slots
+func inFlightKey(campaignID int64) string {
+	return fmt.Sprintf("campaign:%d", campaignID)
+}
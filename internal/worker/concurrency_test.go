@@ -0,0 +1,162 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// memorySemaphore is an in-process Semaphore for tests
+type memorySemaphore struct {
+	held     map[string]map[string]bool
+	acquired int
+}
+
+func newMemorySemaphore() *memorySemaphore {
+	return &memorySemaphore{held: make(map[string]map[string]bool)}
+}
+
+func (s *memorySemaphore) Acquire(ctx context.Context, key string, limit int, ttl time.Duration) (string, bool, error) {
+	if len(s.held[key]) >= limit {
+		return "", false, nil
+	}
+	if s.held[key] == nil {
+		s.held[key] = make(map[string]bool)
+	}
+	s.acquired++
+	lease := fmt.Sprintf("lease-%d", s.acquired)
+	s.held[key][lease] = true
+	return lease, true, nil
+}
+func (s *memorySemaphore) Release(ctx context.Context, key, lease string) error {
+	delete(s.held[key], lease)
+	return nil
+}
+func (s *memorySemaphore) Close() error { return nil }
+
+func TestMessageProcessor_Process_MaxInFlight(t *testing.T) {
+	maxInFlight := 1
+	messages := &messageStore{
+		byID: map[int64]*models.OutboundMessage{
+			1: {ID: 1, CampaignID: 1, CustomerID: 1, Status: models.MessageStatusPending, RenderedContent: "Hi Alice!"},
+		},
+	}
+	campaigns := &campaignStore{
+		byID: map[int64]*models.CampaignWithStats{
+			1: {ID: 1, Channel: "sms", Status: models.CampaignStatusSending, MaxInFlight: &maxInFlight, Stats: models.CampaignStats{Total: 1, Pending: 1}},
+		},
+	}
+	customers := &customerStore{
+		byID: map[int64]*models.Customer{
+			1: {ID: 1, Phone: "+254712345001"},
+		},
+	}
+	sender := &testMockSender{}
+	scheduler := &recordingScheduler{}
+	semaphore := newMemorySemaphore()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	processor := NewMessageProcessor(messages.repo(t), campaigns.repo(t), customers.repo(t), sender, scheduler, 3, logger)
+	processor.SetConcurrencyLimit(NewConcurrencyLimit(semaphore, logger))
+
+	// Another worker holds the campaign's only slot
+	other, _, _ := semaphore.Acquire(context.Background(), inFlightKey(1), maxInFlight, inFlightLeaseTTL)
+
+	job := &models.MessageJob{OutboundMessageID: 1, CampaignID: 1}
+	if err := processor.Process(context.Background(), job); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if len(sender.calls) != 0 {
+		t.Fatalf("sends = %d, want none while the slot is held", len(sender.calls))
+	}
+	if len(scheduler.deferred) != 1 {
+		t.Fatalf("deferred jobs = %d, want 1", len(scheduler.deferred))
+	}
+	if got := messages.byID[1].Status; got != models.MessageStatusPending {
+		t.Errorf("message status = %s, want pending", got)
+	}
+
+	// Once the slot is free the message is sent and the slot given back
+	_ = semaphore.Release(context.Background(), inFlightKey(1), other)
+	if err := processor.Process(context.Background(), job); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if len(sender.calls) != 1 {
+		t.Fatalf("sends = %d, want 1", len(sender.calls))
+	}
+	if held := len(semaphore.held[inFlightKey(1)]); held != 0 {
+		t.Errorf("held slots after the send = %d, want 0", held)
+	}
+}
+
+func TestMessageProcessor_Process_MaxInFlightKeepsWarmupQuota(t *testing.T) {
+	maxInFlight := 1
+	senderID := "ACME"
+	now := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+	messages := &messageStore{
+		byID: map[int64]*models.OutboundMessage{
+			1: {ID: 1, CampaignID: 1, CustomerID: 1, Status: models.MessageStatusPending, RenderedContent: "Hi Alice!"},
+		},
+	}
+	campaigns := &campaignStore{
+		byID: map[int64]*models.CampaignWithStats{
+			1: {ID: 1, Channel: "sms", Status: models.CampaignStatusSending, SenderID: &senderID, MaxInFlight: &maxInFlight,
+				Stats: models.CampaignStats{Total: 1, Pending: 1}},
+		},
+	}
+	customers := &customerStore{
+		byID: map[int64]*models.Customer{
+			1: {ID: 1, Phone: "+254712345001"},
+		},
+	}
+
+	warmupRepo := mocks.NewMockSenderWarmupRepository(gomock.NewController(t))
+	warmupRepo.EXPECT().GetBySenderID(gomock.Any(), senderID).
+		Return(&models.SenderWarmup{SenderID: senderID, StartedOn: now, InitialDailyCap: 2, MaxDailyCap: 100}, nil).
+		AnyTimes()
+	quota := &memoryQuota{used: map[string]int{}}
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	gate := &warmupGate{warmupRepo: warmupRepo, quota: quota, now: func() time.Time { return now }, logger: logger}
+
+	scheduler := &recordingScheduler{}
+	semaphore := newMemorySemaphore()
+	processor := NewMessageProcessor(messages.repo(t), campaigns.repo(t), customers.repo(t), &testMockSender{}, scheduler, 3, logger, gate)
+	processor.SetConcurrencyLimit(NewConcurrencyLimit(semaphore, logger))
+
+	// Another worker holds the campaign's only slot, so the job is deferred
+	// before the warm-up gate takes a unit of the day's cap
+	_, _, _ = semaphore.Acquire(context.Background(), inFlightKey(1), maxInFlight, inFlightLeaseTTL)
+
+	if err := processor.Process(context.Background(), &models.MessageJob{OutboundMessageID: 1, CampaignID: 1}); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if len(scheduler.deferred) != 1 {
+		t.Fatalf("deferred jobs = %d, want 1", len(scheduler.deferred))
+	}
+	if len(quota.used) != 0 {
+		t.Errorf("quota used = %v, want none for a job deferred for a slot", quota.used)
+	}
+}
+
+func TestConcurrencyLimit_Acquire_NoLimit(t *testing.T) {
+	semaphore := newMemorySemaphore()
+	limit := NewConcurrencyLimit(semaphore, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+	release, ok, err := limit.Acquire(context.Background(), &models.Campaign{ID: 1})
+	if err != nil || !ok {
+		t.Fatalf("Acquire() = %v, %v, want a slot", ok, err)
+	}
+	release()
+
+	if semaphore.acquired != 0 {
+		t.Errorf("semaphore acquired %d slots, want none for a campaign without max_in_flight", semaphore.acquired)
+	}
+}
@@ -0,0 +1,120 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// AlertTypeCostCapReached is raised when a campaign is paused at its cost cap
+const AlertTypeCostCapReached = "campaign_cost_cap_reached"
+
+// CostGuard tracks what each campaign spends and stops it at its cost cap.
+// The cost of a message is reserved before it is sent and given back if the
+// send fails, so the cap holds even with many workers sending at once.
+type CostGuard struct {
+	campaignRepo repository.CampaignRepository
+	alerter      Alerter
+	now          func() time.Time
+	logger       *slog.Logger
+
+	mu    sync.RWMutex
+	rates map[string]float64
+}
+
+// NewCostGuard creates a cost guard. rates maps a channel to its price per SMS
+// segment, or per message on other channels; channels without a rate are free.
+func NewCostGuard(campaignRepo repository.CampaignRepository, alerter Alerter, rates map[string]float64, logger *slog.Logger) *CostGuard {
+	return &CostGuard{
+		campaignRepo: campaignRepo,
+		alerter:      alerter,
+		rates:        maps.Clone(rates),
+		now:          time.Now,
+		logger:       logger,
+	}
+}
+
+// SetRates replaces the per-channel prices
+func (g *CostGuard) SetRates(rates map[string]float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.rates = maps.Clone(rates)
+}
+
+// Cost returns the price of sending a message on a channel
+func (g *CostGuard) Cost(channel, content string) float64 {
+	g.mu.RLock()
+	rate := g.rates[channel]
+	g.mu.RUnlock()
+
+	return models.MessageCost(channel, content, rate)
+}
+
+// Reserve books the cost of a message against its campaign. It returns false
+// when the message would take the campaign past its cap; the campaign is then
+// paused and an alert raised, and the message must not be sent.
+func (g *CostGuard) Reserve(ctx context.Context, campaign *models.Campaign, cost float64) (bool, error) {
+	if cost <= 0 {
+		return true, nil
+	}
+
+	ok, err := g.campaignRepo.ReserveCost(ctx, campaign.ID, cost)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		return true, nil
+	}
+
+	reason := "cost cap reached"
+	if campaign.MaxCost != nil {
+		reason = fmt.Sprintf("cost cap of %.2f reached", *campaign.MaxCost)
+	}
+
+	// Every worker at the cap gets here; only the one that pauses the campaign alerts
+	paused, err := g.campaignRepo.PauseSending(ctx, campaign.ID, reason)
+	if err != nil {
+		return false, fmt.Errorf("failed to pause campaign at cost cap: %w", err)
+	}
+	if !paused {
+		return false, nil
+	}
+
+	g.logger.Warn("campaign paused at cost cap",
+		slog.Int64("campaign_id", campaign.ID),
+		slog.String("reason", reason),
+	)
+
+	err = g.alerter.Alert(ctx, Alert{
+		Type:        AlertTypeCostCapReached,
+		Message:     reason + "; raise it with PUT /api/campaigns/{id}/max-cost, then resume with POST /api/campaigns/{id}/resume",
+		Channel:     campaign.Channel,
+		CampaignIDs: []int64{campaign.ID},
+		At:          g.now(),
+	})
+	if err != nil {
+		g.logger.Error("failed to send alert", slog.String("error", err.Error()))
+	}
+
+	return false, nil
+}
+
+// Release gives back the reservation of a message that was not sent
+func (g *CostGuard) Release(ctx context.Context, campaignID int64, cost float64) {
+	if cost <= 0 {
+		return
+	}
+
+	if err := g.campaignRepo.ReleaseCost(ctx, campaignID, cost); err != nil {
+		g.logger.Error("failed to release message cost",
+			slog.Int64("campaign_id", campaignID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
@@ -0,0 +1,106 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestCostGuard_Cost(t *testing.T) {
+	guard := NewCostGuard((&campaignStore{}).repo(t), &recordingAlerter{}, map[string]float64{"sms": 0.5, "whatsapp": 0.2}, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+	if got := guard.Cost(models.ChannelSMS, strings.Repeat("a", 200)); got != 1.0 {
+		t.Errorf("two-segment SMS cost = %v, want 1.0", got)
+	}
+	if got := guard.Cost(models.ChannelWhatsApp, strings.Repeat("a", 200)); got != 0.2 {
+		t.Errorf("WhatsApp cost = %v, want 0.2 per message", got)
+	}
+
+	guard.SetRates(nil)
+	if got := guard.Cost(models.ChannelSMS, "Hi"); got != 0 {
+		t.Errorf("cost without a rate = %v, want 0", got)
+	}
+}
+
+func TestMessageProcessor_Process_CostCap(t *testing.T) {
+	maxCost := 2.0
+	messages := &messageStore{byID: map[int64]*models.OutboundMessage{}, updates: []statusUpdate{}}
+	for id := int64(1); id <= 3; id++ {
+		messages.byID[id] = &models.OutboundMessage{ID: id, CampaignID: 1, CustomerID: 1, Status: models.MessageStatusPending, RenderedContent: "Hi Alice!"}
+	}
+	campaigns := &campaignStore{
+		byID: map[int64]*models.CampaignWithStats{
+			1: {ID: 1, Channel: "sms", Status: models.CampaignStatusSending, MaxCost: &maxCost, Stats: models.CampaignStats{Total: 3, Pending: 3}},
+		},
+	}
+	customers := &customerStore{
+		byID: map[int64]*models.Customer{
+			1: {ID: 1, Phone: "+254712345001"},
+		},
+	}
+	sender := &testMockSender{}
+	alerter := &recordingAlerter{}
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	processor := NewMessageProcessor(messages.repo(t), campaigns.repo(t), customers.repo(t), sender, &recordingScheduler{}, 3, logger)
+	processor.SetCostGuard(NewCostGuard(campaigns.repo(t), alerter, map[string]float64{"sms": 1.0}, logger))
+
+	for id := int64(1); id <= 3; id++ {
+		if err := processor.Process(context.Background(), &models.MessageJob{OutboundMessageID: id}); err != nil {
+			t.Fatalf("Process(%d) error = %v", id, err)
+		}
+	}
+
+	if len(sender.calls) != 2 {
+		t.Errorf("sends = %d, want 2 within the cost cap", len(sender.calls))
+	}
+	if got := messages.byID[3].Status; got != models.MessageStatusPending {
+		t.Errorf("message over the cap status = %s, want pending", got)
+	}
+	if len(messages.unqueued) != 1 || messages.unqueued[0] != 3 {
+		t.Errorf("unqueued = %v, want the message over the cap", messages.unqueued)
+	}
+	if got := campaigns.byID[1].Status; got != models.CampaignStatusPaused {
+		t.Errorf("campaign status = %s, want paused", got)
+	}
+	if got := campaigns.costs[1]; got != 2.0 {
+		t.Errorf("accrued cost = %v, want 2.0", got)
+	}
+	if len(alerter.alerts) != 1 || alerter.alerts[0].Type != AlertTypeCostCapReached {
+		t.Fatalf("alerts = %+v, want one %s alert", alerter.alerts, AlertTypeCostCapReached)
+	}
+}
+
+func TestMessageProcessor_Process_FailedSendReleasesCost(t *testing.T) {
+	messages := &messageStore{
+		byID: map[int64]*models.OutboundMessage{
+			1: {ID: 1, CampaignID: 1, CustomerID: 1, Status: models.MessageStatusPending, RenderedContent: "Hi Alice!"},
+		},
+	}
+	campaigns := &campaignStore{
+		byID: map[int64]*models.CampaignWithStats{
+			1: {ID: 1, Channel: "sms", Status: models.CampaignStatusSending},
+		},
+	}
+	customers := &customerStore{
+		byID: map[int64]*models.Customer{
+			1: {ID: 1, Phone: "+254712345001"},
+		},
+	}
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	processor := NewMessageProcessor(messages.repo(t), campaigns.repo(t), customers.repo(t), &testMockSender{shouldFail: true}, &recordingScheduler{}, 3, logger)
+	processor.SetCostGuard(NewCostGuard(campaigns.repo(t), &recordingAlerter{}, map[string]float64{"sms": 1.0}, logger))
+
+	if err := processor.Process(context.Background(), &models.MessageJob{OutboundMessageID: 1}); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	if got := campaigns.costs[1]; got != 0 {
+		t.Errorf("accrued cost = %v, want 0 after a failed send", got)
+	}
+}
@@ -0,0 +1,88 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/ratelimit"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// dailyCapGate enforces the daily send cap of a campaign's account. Once the
+// cap is reached the campaign is paused until the start of the next UTC day.
+type dailyCapGate struct {
+	accountRepo repository.AccountRepository
+	quota       ratelimit.Quota
+	now         func() time.Time
+	logger      *slog.Logger
+}
+
+// NewDailyCapGate creates a send gate enforcing account daily send caps
+func NewDailyCapGate(accountRepo repository.AccountRepository, quota ratelimit.Quota, logger *slog.Logger) CampaignGate {
+	return &dailyCapGate{
+		accountRepo: accountRepo,
+		quota:       quota,
+		now:         time.Now,
+		logger:      logger,
+	}
+}
+
+// Check takes one unit of the account's daily cap, or defers to the next day
+func (g *dailyCapGate) Check(ctx context.Context, campaign *models.Campaign, message *models.OutboundMessage) (time.Time, error) {
+	// Sandbox sends are not billed to the account's allowance
+	if campaign.IsTest() {
+		return time.Time{}, nil
+	}
+
+	account, err := g.accountRepo.GetByID(ctx, campaign.AccountID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	// Sends are counted without a cap too, so usage can be reported
+	limit := math.MaxInt32
+	if account.DailySendCap != nil {
+		limit = *account.DailySendCap
+	}
+
+	day := models.StartOfDay(g.now())
+
+	ok, err := g.quota.Take(ctx, models.DailySendQuotaKey(account.ID), day, 24*time.Hour, limit)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if ok {
+		return time.Time{}, nil
+	}
+
+	g.logger.Info("account daily send cap reached",
+		slog.Int64("account_id", account.ID),
+		slog.Int("daily_send_cap", limit),
+		slog.Int64("message_id", message.ID),
+	)
+
+	return day.Add(24 * time.Hour), nil
+}
+
+// Refund implements QuotaGate
+func (g *dailyCapGate) Refund(ctx context.Context, campaign *models.Campaign, message *models.OutboundMessage) error {
+	if campaign.IsTest() {
+		return nil
+	}
+
+	account, err := g.accountRepo.GetByID(ctx, campaign.AccountID)
+	if err != nil {
+		return fmt.Errorf("failed to get account: %w", err)
+	}
+
+	return g.quota.Refund(ctx, models.DailySendQuotaKey(account.ID), models.StartOfDay(g.now()))
+}
+
+// PauseReason implements CampaignGate
+func (g *dailyCapGate) PauseReason() string {
+	return models.PauseReasonDailyCap
+}
@@ -0,0 +1,164 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestMessageProcessor_Process_DailyCapPausesCampaign(t *testing.T) {
+	now := time.Date(2026, 10, 16, 14, 0, 0, 0, time.UTC)
+	dailyCap := 2
+
+	messages := &messageStore{
+		byID: map[int64]*models.OutboundMessage{},
+	}
+	for id := int64(1); id <= 3; id++ {
+		messages.byID[id] = &models.OutboundMessage{ID: id, CampaignID: 1, CustomerID: 1, Status: models.MessageStatusPending}
+	}
+
+	campaigns := &campaignStore{
+		byID: map[int64]*models.CampaignWithStats{
+			1: {ID: 1, Channel: "sms", Status: models.CampaignStatusSending, Stats: models.CampaignStats{Pending: 3}},
+		},
+	}
+	customers := &customerStore{
+		byID: map[int64]*models.Customer{1: {ID: 1, Phone: "+254712345001"}},
+	}
+
+	ctrl := gomock.NewController(t)
+	accountRepo := mocks.NewMockAccountRepository(ctrl)
+	accountRepo.EXPECT().GetByID(gomock.Any(), gomock.Any()).
+		Return(&models.Account{ID: 2, DailySendCap: &dailyCap}, nil).
+		AnyTimes()
+
+	quota := &memoryQuota{used: map[string]int{}}
+	gate := &dailyCapGate{
+		accountRepo: accountRepo,
+		quota:       quota,
+		now:         func() time.Time { return now },
+		logger:      slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	}
+
+	sender := &testMockSender{}
+	scheduler := &recordingScheduler{}
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	processor := NewMessageProcessor(messages.repo(t), campaigns.repo(t), customers.repo(t), sender, scheduler, 3, logger, gate)
+
+	for id := int64(1); id <= 3; id++ {
+		if err := processor.Process(context.Background(), &models.MessageJob{OutboundMessageID: id}); err != nil {
+			t.Fatalf("Process(%d) error = %v", id, err)
+		}
+	}
+
+	if len(sender.calls) != 2 {
+		t.Errorf("Expected 2 sends under the daily cap, got %d", len(sender.calls))
+	}
+
+	campaign := campaigns.byID[1]
+	if campaign.Status != models.CampaignStatusPaused || campaign.PausedReason == nil || *campaign.PausedReason != models.PauseReasonDailyCap {
+		t.Fatalf("campaign status = %s (reason %v), want paused with %s", campaign.Status, campaign.PausedReason, models.PauseReasonDailyCap)
+	}
+	if want := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC); campaign.ResumeAt == nil || !campaign.ResumeAt.Equal(want) {
+		t.Errorf("resume_at = %v, want %v", campaign.ResumeAt, want)
+	}
+	if messages.byID[3].Status != models.MessageStatusPending {
+		t.Errorf("message over the cap status = %s, want pending", messages.byID[3].Status)
+	}
+
+	used, _ := quota.Used(context.Background(), models.DailySendQuotaKey(2), models.StartOfDay(now))
+	if used != 2 {
+		t.Errorf("usage = %d, want 2", used)
+	}
+}
+
+func TestMessageProcessor_Process_QuotaRefundedUnlessSent(t *testing.T) {
+	now := time.Date(2026, 10, 16, 14, 0, 0, 0, time.UTC)
+	senderID := "ACME"
+
+	tests := []struct {
+		name       string
+		dailyCap   int
+		shouldFail bool
+		wantSends  int
+		wantUsed   int
+	}{
+		{
+			// The second message passes the warm-up gate but is deferred by
+			// the daily cap, which gives the warm-up unit back
+			name:      "daily cap deferral",
+			dailyCap:  1,
+			wantSends: 1,
+			wantUsed:  1,
+		},
+		{
+			name:       "failed sends",
+			dailyCap:   2,
+			shouldFail: true,
+			wantSends:  2,
+			wantUsed:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages := &messageStore{
+				byID: map[int64]*models.OutboundMessage{},
+			}
+			for id := int64(1); id <= 2; id++ {
+				messages.byID[id] = &models.OutboundMessage{ID: id, CampaignID: 1, CustomerID: 1, Status: models.MessageStatusPending}
+			}
+			campaigns := &campaignStore{
+				byID: map[int64]*models.CampaignWithStats{
+					1: {ID: 1, Channel: "sms", Status: models.CampaignStatusSending, SenderID: &senderID,
+						Stats: models.CampaignStats{Pending: 2}},
+				},
+			}
+			customers := &customerStore{
+				byID: map[int64]*models.Customer{1: {ID: 1, Phone: "+254712345001"}},
+			}
+
+			ctrl := gomock.NewController(t)
+			accountRepo := mocks.NewMockAccountRepository(ctrl)
+			accountRepo.EXPECT().GetByID(gomock.Any(), gomock.Any()).
+				Return(&models.Account{ID: 2, DailySendCap: &tt.dailyCap}, nil).
+				AnyTimes()
+			warmupRepo := mocks.NewMockSenderWarmupRepository(ctrl)
+			warmupRepo.EXPECT().GetBySenderID(gomock.Any(), senderID).
+				Return(&models.SenderWarmup{SenderID: senderID, StartedOn: now, InitialDailyCap: 10, MaxDailyCap: 100}, nil).
+				AnyTimes()
+
+			logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+			quota := &memoryQuota{used: map[string]int{}}
+			clock := func() time.Time { return now }
+			warmup := &warmupGate{warmupRepo: warmupRepo, quota: quota, now: clock, logger: logger}
+			daily := &dailyCapGate{accountRepo: accountRepo, quota: quota, now: clock, logger: logger}
+
+			sender := &testMockSender{shouldFail: tt.shouldFail}
+			processor := NewMessageProcessor(messages.repo(t), campaigns.repo(t), customers.repo(t), sender, &recordingScheduler{}, 3, logger, warmup, daily)
+
+			for id := int64(1); id <= 2; id++ {
+				if err := processor.Process(context.Background(), &models.MessageJob{OutboundMessageID: id}); err != nil {
+					t.Fatalf("Process(%d) error = %v", id, err)
+				}
+			}
+
+			if len(sender.calls) != tt.wantSends {
+				t.Errorf("sends = %d, want %d", len(sender.calls), tt.wantSends)
+			}
+			day := models.StartOfDay(now)
+			for _, key := range []string{"warmup:" + senderID, models.DailySendQuotaKey(2)} {
+				if used, _ := quota.Used(context.Background(), key, day); used != tt.wantUsed {
+					t.Errorf("%s usage = %d, want %d", key, used, tt.wantUsed)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// deliveryWindowGate defers jobs processed outside the campaign's delivery windows
+// until the next window opens
+type deliveryWindowGate struct {
+	now func() time.Time
+}
+
+// NewDeliveryWindowGate creates a send gate enforcing campaign delivery windows
+func NewDeliveryWindowGate() CampaignGate {
+	return &deliveryWindowGate{now: time.Now}
+}
+
+// Check returns the next window opening when now is outside every window.
+// Windows are taken in the campaign's timezone.
+func (g *deliveryWindowGate) Check(ctx context.Context, campaign *models.Campaign, message *models.OutboundMessage) (time.Time, error) {
+	now := g.now()
+
+	next := campaign.DeliveryWindows.NextOpenIn(now, campaign.Location())
+	if next.After(now) {
+		return next, nil
+	}
+
+	return time.Time{}, nil
+}
+
+// PauseReason implements CampaignGate
+func (g *deliveryWindowGate) PauseReason() string {
+	return "outside delivery windows"
+}
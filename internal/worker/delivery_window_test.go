@@ -0,0 +1,212 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestDeliveryWindows_NextOpen(t *testing.T) {
+	// Weekdays 09:00-18:00, Saturday 10:00-14:00
+	windows := models.DeliveryWindows{
+		{Days: []string{"mon", "tue", "wed", "thu", "fri"}, StartHour: 9, EndHour: 18},
+		{Days: []string{"sat"}, StartHour: 10, EndHour: 14},
+	}
+
+	// 2026-10-16 is a Friday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{name: "inside window", now: at(16, 12, 30), want: at(16, 12, 30)},
+		{name: "at window start", now: at(16, 9, 0), want: at(16, 9, 0)},
+		{name: "before window same day", now: at(16, 3, 0), want: at(16, 9, 0)},
+		{name: "at window end moves to next day", now: at(16, 18, 0), want: at(17, 10, 0)},
+		{name: "after saturday window skips sunday", now: at(17, 15, 0), want: at(19, 9, 0)},
+		{name: "sunday", now: at(18, 12, 0), want: at(19, 9, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := windows.NextOpen(tt.now); !got.Equal(tt.want) {
+				t.Errorf("NextOpen() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("no windows always open", func(t *testing.T) {
+		now := at(18, 3, 0)
+		if got := models.DeliveryWindows(nil).NextOpen(now); !got.Equal(now) {
+			t.Errorf("NextOpen() = %v, want %v", got, now)
+		}
+	})
+}
+
+func TestDeliveryWindows_NextOpenIn(t *testing.T) {
+	windows := models.DeliveryWindows{{Days: []string{"mon", "tue", "wed", "thu", "fri"}, StartHour: 8, EndHour: 20}}
+
+	nairobi, err := time.LoadLocation("Africa/Nairobi")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		loc  *time.Location
+		now  time.Time
+		want time.Time
+	}{
+		{
+			// 06:00 UTC is 09:00 in Nairobi
+			name: "inside local window",
+			loc:  nairobi,
+			now:  time.Date(2026, 10, 16, 6, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 10, 16, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			// 17:30 UTC is 20:30 on Friday in Nairobi; Monday 08:00 there is 05:00 UTC
+			name: "after local window skips the weekend",
+			loc:  nairobi,
+			now:  time.Date(2026, 10, 16, 17, 30, 0, 0, time.UTC),
+			want: time.Date(2026, 10, 19, 5, 0, 0, 0, time.UTC),
+		},
+		{
+			// 02:00 UTC on Monday is still Sunday evening in New York
+			name: "local day differs from UTC day",
+			loc:  newYork,
+			now:  time.Date(2026, 10, 19, 2, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			// Clocks go back on 2026-11-01, so Monday 08:00 is 13:00 UTC
+			name: "across a daylight saving change",
+			loc:  newYork,
+			now:  time.Date(2026, 10, 31, 12, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 11, 2, 13, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := windows.NextOpenIn(tt.now, tt.loc); !got.Equal(tt.want) {
+				t.Errorf("NextOpenIn() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeliveryWindow_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		window  models.DeliveryWindow
+		wantErr bool
+	}{
+		{name: "valid", window: models.DeliveryWindow{Days: []string{"mon"}, StartHour: 8, EndHour: 20}},
+		{name: "whole day", window: models.DeliveryWindow{Days: []string{"Sun"}, StartHour: 0, EndHour: 24}},
+		{name: "no days", window: models.DeliveryWindow{StartHour: 8, EndHour: 20}, wantErr: true},
+		{name: "unknown day", window: models.DeliveryWindow{Days: []string{"someday"}, StartHour: 8, EndHour: 20}, wantErr: true},
+		{name: "end before start", window: models.DeliveryWindow{Days: []string{"mon"}, StartHour: 20, EndHour: 8}, wantErr: true},
+		{name: "end past midnight", window: models.DeliveryWindow{Days: []string{"mon"}, StartHour: 8, EndHour: 25}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.window.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDeliveryWindowGate_Check(t *testing.T) {
+	now := time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC)
+	gate := &deliveryWindowGate{now: func() time.Time { return now }}
+
+	campaign := &models.Campaign{
+		DeliveryWindows: models.DeliveryWindows{{Days: []string{"fri"}, StartHour: 9, EndHour: 18}},
+	}
+
+	deferUntil, err := gate.Check(context.Background(), campaign, &models.OutboundMessage{ID: 1})
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if want := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC); !deferUntil.Equal(want) {
+		t.Errorf("Check() = %v, want %v", deferUntil, want)
+	}
+
+	campaign.DeliveryWindows = nil
+	deferUntil, err = gate.Check(context.Background(), campaign, &models.OutboundMessage{ID: 1})
+	if err != nil || !deferUntil.IsZero() {
+		t.Errorf("Check() = %v, %v, want zero time for a campaign without windows", deferUntil, err)
+	}
+}
+
+func TestMessageProcessor_Process_PausesOutsideDeliveryWindow(t *testing.T) {
+	// Friday 18:30, after the weekday window has closed
+	now := time.Date(2026, 10, 16, 18, 30, 0, 0, time.UTC)
+	windows := models.DeliveryWindows{
+		{Days: []string{"mon", "tue", "wed", "thu", "fri"}, StartHour: 9, EndHour: 18},
+	}
+
+	messages := &messageStore{
+		byID: map[int64]*models.OutboundMessage{
+			1: {ID: 1, CampaignID: 1, CustomerID: 1, Status: models.MessageStatusPending},
+			2: {ID: 2, CampaignID: 1, CustomerID: 1, Status: models.MessageStatusPending},
+		},
+	}
+	campaigns := &campaignStore{
+		byID: map[int64]*models.CampaignWithStats{
+			1: {ID: 1, Channel: "sms", Status: models.CampaignStatusSending, DeliveryWindows: windows,
+				Stats: models.CampaignStats{Pending: 2}},
+		},
+	}
+	customers := &customerStore{
+		byID: map[int64]*models.Customer{1: {ID: 1, Phone: "+254712345001"}},
+	}
+
+	sender := &testMockSender{}
+	scheduler := &recordingScheduler{}
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	gate := &deliveryWindowGate{now: func() time.Time { return now }}
+	processor := NewMessageProcessor(messages.repo(t), campaigns.repo(t), customers.repo(t), sender, scheduler, 3, logger, gate)
+
+	for id := int64(1); id <= 2; id++ {
+		if err := processor.Process(context.Background(), &models.MessageJob{OutboundMessageID: id}); err != nil {
+			t.Fatalf("Process(%d) error = %v", id, err)
+		}
+	}
+
+	campaign := campaigns.byID[1]
+	if campaign.Status != models.CampaignStatusPaused {
+		t.Fatalf("campaign status = %s, want paused", campaign.Status)
+	}
+	if want := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC); campaign.ResumeAt == nil || !campaign.ResumeAt.Equal(want) {
+		t.Errorf("resume_at = %v, want %v", campaign.ResumeAt, want)
+	}
+	if len(sender.calls) != 0 {
+		t.Errorf("Expected no sends outside the window, got %d", len(sender.calls))
+	}
+	// The jobs are dropped; resuming the campaign requeues its pending messages
+	if len(scheduler.deferred) != 0 {
+		t.Errorf("Expected no deferred jobs, got %d", len(scheduler.deferred))
+	}
+	for id, message := range messages.byID {
+		if message.Status != models.MessageStatusPending {
+			t.Errorf("message %d status = %s, want pending", id, message.Status)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// SendGate decides whether a message may be sent right now.
+// Gates are checked in order before every send; the first one that defers wins.
+type SendGate interface {
+	// Check returns the zero time to let the send proceed, or the time at which
+	// the job should be tried again
+	Check(ctx context.Context, campaign *models.Campaign, message *models.OutboundMessage) (time.Time, error)
+}
+
+// CampaignGate is a send gate whose deferral holds for every message of the
+// campaign, such as a closed delivery window. Instead of deferring each job, the
+// processor pauses the campaign until the deferral time and the scheduler
+// resumes it then, so a send that cannot finish in one window carries on in
+// the next.
+type CampaignGate interface {
+	SendGate
+	// PauseReason is recorded on a campaign paused by the gate
+	PauseReason() string
+}
+
+// QuotaGate is a send gate that uses up a unit of quota, such as a daily cap,
+// for every send it lets through. The processor gives the unit back when the
+// message is not sent after all: a later gate defers it, it loses its claim,
+// it would go over the cost cap or the send fails.
+type QuotaGate interface {
+	SendGate
+	// Refund gives back the unit Check took for the message, if it took one
+	Refund(ctx context.Context, campaign *models.Campaign, message *models.OutboundMessage) error
+}
+
+// JobScheduler publishes jobs to be processed later
+type JobScheduler interface {
+	PublishDelayed(ctx context.Context, job *models.MessageJob, at time.Time) error
+}
@@ -0,0 +1,156 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/queue"
+)
+
+// lagCheckInterval is how often the lag monitor reads the queue lag
+const lagCheckInterval = 15 * time.Second
+
+// lagSignalInterval is how often the scale-up signal is repeated while the lag
+// stays over target, so an autoscaler that missed one still adds workers
+const lagSignalInterval = time.Minute
+
+// Scaling signal types
+const (
+	// SignalQueueLagHigh asks for more workers: the oldest job has waited longer than the target
+	SignalQueueLagHigh = "queue_lag_high"
+	// SignalQueueLagRecovered reports that the lag is back within the target
+	SignalQueueLagRecovered = "queue_lag_recovered"
+)
+
+// ScalingSignal tells an autoscaler whether the workers keep up with the queue
+type ScalingSignal struct {
+	Type          string    `json:"type"`
+	LagSeconds    float64   `json:"lag_seconds"`
+	TargetSeconds float64   `json:"target_seconds"`
+	At            time.Time `json:"at"`
+}
+
+// LagMonitor measures how long the oldest queued job has waited and signals
+// for more workers while that exceeds a target
+type LagMonitor struct {
+	queueClient queue.Client
+	webhookURL  string
+	client      *http.Client
+	now         func() time.Time
+	logger      *slog.Logger
+
+	mu     sync.Mutex
+	target time.Duration
+
+	// overSince is when the lag went over target, zero while it is within
+	overSince   time.Time
+	signalledAt time.Time
+}
+
+// NewLagMonitor creates a lag monitor that posts scaling signals to
+// webhookURL, or only logs them when it is empty. A target of 0 turns
+// monitoring off.
+func NewLagMonitor(queueClient queue.Client, target time.Duration, webhookURL string, logger *slog.Logger) *LagMonitor {
+	return &LagMonitor{
+		queueClient: queueClient,
+		webhookURL:  webhookURL,
+		client:      &http.Client{Timeout: 5 * time.Second},
+		target:      target,
+		now:         time.Now,
+		logger:      logger,
+	}
+}
+
+// SetTarget changes the lag target; 0 turns monitoring off
+func (m *LagMonitor) SetTarget(target time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.target = target
+}
+
+// Run checks the queue lag until ctx is done
+func (m *LagMonitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(lagCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.check(ctx)
+		}
+	}
+}
+
+// check reads the lag and signals on going over target, every
+// lagSignalInterval while it stays over, and once on recovering
+func (m *LagMonitor) check(ctx context.Context) {
+	m.mu.Lock()
+	target := m.target
+	m.mu.Unlock()
+
+	if target <= 0 {
+		return
+	}
+
+	lag, err := m.queueClient.Lag(ctx)
+	if err != nil {
+		m.logger.Error("failed to read queue lag", slog.String("error", err.Error()))
+		return
+	}
+	now := m.now()
+
+	m.logger.Debug("queue lag",
+		slog.Float64("lag_seconds", lag.Seconds()),
+		slog.Float64("target_seconds", target.Seconds()),
+	)
+
+	if lag > target {
+		if m.overSince.IsZero() {
+			m.overSince = now
+			m.logger.Warn("queue lag over target, more workers needed",
+				slog.Float64("lag_seconds", lag.Seconds()),
+				slog.Float64("target_seconds", target.Seconds()),
+			)
+		}
+		if m.signalledAt.IsZero() || now.Sub(m.signalledAt) >= lagSignalInterval {
+			m.signal(ctx, SignalQueueLagHigh, lag, target, now)
+			m.signalledAt = now
+		}
+		return
+	}
+
+	if !m.overSince.IsZero() {
+		m.logger.Info("queue lag back within target",
+			slog.Float64("lag_seconds", lag.Seconds()),
+			slog.Duration("over_target_for", now.Sub(m.overSince)),
+		)
+		m.signal(ctx, SignalQueueLagRecovered, lag, target, now)
+		m.overSince = time.Time{}
+		m.signalledAt = time.Time{}
+	}
+}
+
+// signal posts a scaling signal to the webhook, if one is configured
+func (m *LagMonitor) signal(ctx context.Context, signalType string, lag, target time.Duration, now time.Time) {
+	if m.webhookURL == "" {
+		return
+	}
+
+	err := postJSON(ctx, m.client, m.webhookURL, ScalingSignal{
+		Type:          signalType,
+		LagSeconds:    lag.Seconds(),
+		TargetSeconds: target.Seconds(),
+		At:            now.UTC(),
+	})
+	if err != nil {
+		m.logger.Error("failed to post scaling signal",
+			slog.String("type", signalType),
+			slog.String("error", err.Error()),
+		)
+	}
+}
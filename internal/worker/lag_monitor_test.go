@@ -0,0 +1,93 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+)
+
+func TestLagMonitor_Check(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var signals []ScalingSignal
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var signal ScalingSignal
+		if err := json.NewDecoder(r.Body).Decode(&signal); err != nil {
+			t.Errorf("failed to decode signal: %v", err)
+		}
+		signals = append(signals, signal)
+	}))
+	defer server.Close()
+
+	queueClient := mocks.NewMockClient(ctrl)
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	monitor := NewLagMonitor(queueClient, time.Minute, server.URL, logger)
+
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	steps := []struct {
+		name    string
+		after   time.Duration
+		lag     time.Duration
+		signals []string
+	}{
+		{name: "within target", lag: 30 * time.Second},
+		{name: "over target signals", after: 15 * time.Second, lag: 90 * time.Second, signals: []string{SignalQueueLagHigh}},
+		{name: "still over is not repeated at once", after: 30 * time.Second, lag: 2 * time.Minute, signals: []string{SignalQueueLagHigh}},
+		{name: "still over repeats after the interval", after: time.Minute, lag: 2 * time.Minute, signals: []string{SignalQueueLagHigh, SignalQueueLagHigh}},
+		{name: "recovered", after: 15 * time.Second, lag: 5 * time.Second, signals: []string{SignalQueueLagHigh, SignalQueueLagHigh, SignalQueueLagRecovered}},
+		{name: "recovery is signalled once", after: 15 * time.Second, lag: 5 * time.Second, signals: []string{SignalQueueLagHigh, SignalQueueLagHigh, SignalQueueLagRecovered}},
+	}
+
+	now := start
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			now = now.Add(step.after)
+			monitor.now = func() time.Time { return now }
+			queueClient.EXPECT().Lag(gomock.Any()).Return(step.lag, nil)
+
+			monitor.check(context.Background())
+
+			if len(signals) != len(step.signals) {
+				t.Fatalf("Expected %d signals, got %d", len(step.signals), len(signals))
+			}
+			for i, signal := range signals {
+				if signal.Type != step.signals[i] {
+					t.Errorf("Signal %d: expected %s, got %s", i, step.signals[i], signal.Type)
+				}
+				if signal.TargetSeconds != 60 {
+					t.Errorf("Signal %d: expected target 60, got %v", i, signal.TargetSeconds)
+				}
+			}
+		})
+	}
+}
+
+func TestLagMonitor_CheckDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// No Lag call is expected while the target is 0
+	queueClient := mocks.NewMockClient(ctrl)
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	monitor := NewLagMonitor(queueClient, 0, "", logger)
+
+	monitor.check(context.Background())
+
+	monitor.SetTarget(time.Minute)
+	queueClient.EXPECT().Lag(gomock.Any()).Return(2*time.Minute, nil)
+	monitor.check(context.Background())
+
+	if monitor.overSince.IsZero() {
+		t.Error("Expected lag over target to be recorded")
+	}
+}
@@ -0,0 +1,193 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// twilioLookupURL is the Twilio Lookup v2 endpoint
+const twilioLookupURL = "https://lookups.twilio.com/v2/PhoneNumbers"
+
+// NumberLookupProvider looks up whether a phone number can receive messages
+type NumberLookupProvider interface {
+	Lookup(ctx context.Context, phone string) (*models.NumberLookup, error)
+}
+
+// NewNumberLookupProvider creates the number lookup provider named by
+// provider: ProviderMock, or ProviderTwilio with an "account_sid:auth_token"
+// credential
+func NewNumberLookupProvider(provider, credential string) (NumberLookupProvider, error) {
+	switch provider {
+	case ProviderMock:
+		// The mock reports 97% of numbers reachable
+		return &mockNumberLookup{reachableRate: 0.97}, nil
+	case ProviderTwilio:
+		user, secret, ok := strings.Cut(credential, ":")
+		if !ok || user == "" || secret == "" {
+			// Never echo the credential, it holds a secret
+			return nil, fmt.Errorf("twilio lookup credential must have the form account_sid:auth_token")
+		}
+		return &twilioNumberLookup{
+			baseURL:    twilioLookupURL,
+			accountSID: user,
+			authToken:  secret,
+			client:     &http.Client{Timeout: providerTimeout},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown number lookup provider %q", provider)
+	}
+}
+
+// mockNumberLookup reports numbers reachable at random
+type mockNumberLookup struct {
+	reachableRate float64
+}
+
+// Lookup simulates a lookup
+func (l *mockNumberLookup) Lookup(ctx context.Context, phone string) (*models.NumberLookup, error) {
+	if rand.Float64() > l.reachableRate {
+		return &models.NumberLookup{Phone: phone, Status: "absent_subscriber"}, nil
+	}
+	return &models.NumberLookup{Phone: phone, Reachable: true, Status: "mobile"}, nil
+}
+
+// twilioNumberLookup looks numbers up with Twilio Lookup's line type
+// intelligence
+type twilioNumberLookup struct {
+	baseURL    string
+	accountSID string
+	authToken  string
+	client     *http.Client
+}
+
+// twilioLookupResponse is the part of a Twilio Lookup response we read
+type twilioLookupResponse struct {
+	Valid                bool     `json:"valid"`
+	ValidationErrors     []string `json:"validation_errors"`
+	LineTypeIntelligence *struct {
+		Type string `json:"type"`
+	} `json:"line_type_intelligence"`
+}
+
+// twilioUnreachableLineTypes are line types that cannot receive SMS
+var twilioUnreachableLineTypes = map[string]bool{
+	"landline":  true,
+	"fixedVoip": true,
+	"pager":     true,
+	"voicemail": true,
+}
+
+// Lookup reports an invalid number, or a line type that cannot receive SMS,
+// as unreachable
+func (l *twilioNumberLookup) Lookup(ctx context.Context, phone string) (*models.NumberLookup, error) {
+	endpoint := fmt.Sprintf("%s/%s?Fields=line_type_intelligence", l.baseURL, url.PathEscape(phone))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create twilio lookup request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(l.accountSID, l.authToken)
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call twilio lookup: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("twilio lookup returned status %d: %s", resp.StatusCode, readErrorBody(resp.Body))
+	}
+
+	var result twilioLookupResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode twilio lookup response: %w", err)
+	}
+
+	lookup := &models.NumberLookup{Phone: phone}
+	switch {
+	case !result.Valid:
+		lookup.Status = "invalid"
+		if len(result.ValidationErrors) > 0 {
+			lookup.Status = strings.ToLower(result.ValidationErrors[0])
+		}
+	case result.LineTypeIntelligence == nil || result.LineTypeIntelligence.Type == "":
+		lookup.Reachable = true
+		lookup.Status = "valid"
+	default:
+		lookup.Status = result.LineTypeIntelligence.Type
+		lookup.Reachable = !twilioUnreachableLineTypes[lookup.Status]
+	}
+
+	return lookup, nil
+}
+
+// NumberCheck looks up the numbers of campaigns flagged validate_numbers
+// before they are sent to, so that unreachable numbers are not paid for.
+// Results are cached for models.NumberLookupTTL. Like the RegistrationCheck it
+// never defers a send.
+type NumberCheck struct {
+	lookupRepo repository.NumberLookupRepository
+	provider   NumberLookupProvider
+	now        func() time.Time
+	logger     *slog.Logger
+}
+
+// NewNumberCheck creates a check that looks numbers up with provider
+func NewNumberCheck(lookupRepo repository.NumberLookupRepository, provider NumberLookupProvider, logger *slog.Logger) *NumberCheck {
+	return &NumberCheck{
+		lookupRepo: lookupRepo,
+		provider:   provider,
+		now:        time.Now,
+		logger:     logger,
+	}
+}
+
+// Check returns why the campaign should not message phone, or an empty string
+// when it may. A lookup that fails lets the message through: the provider
+// being down should not hold up the campaign.
+func (c *NumberCheck) Check(ctx context.Context, campaign *models.Campaign, phone string) (string, error) {
+	if !campaign.ValidateNumbers {
+		return "", nil
+	}
+
+	now := c.now()
+	lookup, err := c.lookupRepo.Get(ctx, phone, now.Add(-models.NumberLookupTTL))
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		return "", fmt.Errorf("failed to get cached number lookup: %w", err)
+	}
+
+	if lookup == nil {
+		lookup, err = c.provider.Lookup(ctx, phone)
+		if err != nil {
+			c.logger.Warn("number lookup failed, sending without it",
+				slog.Int64("campaign_id", campaign.ID),
+				slog.String("error", err.Error()),
+			)
+			return "", nil
+		}
+		lookup.CheckedAt = now.UTC()
+
+		// A lookup that cannot be cached is still used
+		if err := c.lookupRepo.Save(ctx, lookup); err != nil {
+			c.logger.Warn("failed to cache number lookup", slog.String("error", err.Error()))
+		}
+	}
+
+	if !lookup.Reachable {
+		return fmt.Sprintf("number unreachable (%s)", lookup.Status), nil
+	}
+
+	return "", nil
+}
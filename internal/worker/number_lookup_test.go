@@ -0,0 +1,135 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// stubNumberLookup reports fixed results and counts its lookups
+type stubNumberLookup struct {
+	results map[string]*models.NumberLookup
+	err     error
+	calls   int
+}
+
+func (l *stubNumberLookup) Lookup(ctx context.Context, phone string) (*models.NumberLookup, error) {
+	l.calls++
+	if l.err != nil {
+		return nil, l.err
+	}
+	result := *l.results[phone]
+	return &result, nil
+}
+
+func TestNumberCheck_Check(t *testing.T) {
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	since := now.Add(-models.NumberLookupTTL)
+
+	ctrl := gomock.NewController(t)
+	lookupRepo := mocks.NewMockNumberLookupRepository(ctrl)
+	// A fresh cached result is used without calling the provider
+	lookupRepo.EXPECT().Get(gomock.Any(), "+254700000001", since).
+		Return(&models.NumberLookup{Phone: "+254700000001", Reachable: true, Status: "mobile"}, nil)
+	// Cache misses are looked up and cached
+	lookupRepo.EXPECT().Get(gomock.Any(), "+254700000002", since).Return(nil, models.ErrNotFoundWithMsg("no lookup"))
+	lookupRepo.EXPECT().Save(gomock.Any(), &models.NumberLookup{Phone: "+254700000002", Status: "landline", CheckedAt: now}).Return(nil)
+
+	provider := &stubNumberLookup{results: map[string]*models.NumberLookup{
+		"+254700000002": {Phone: "+254700000002", Status: "landline"},
+	}}
+	check := NewNumberCheck(lookupRepo, provider, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	check.now = func() time.Time { return now }
+
+	validated := &models.Campaign{ID: 1, ValidateNumbers: true}
+
+	reason, err := check.Check(context.Background(), validated, "+254700000001")
+	if err != nil || reason != "" {
+		t.Errorf("Check(cached reachable) = %q, %v, want no reason", reason, err)
+	}
+	if provider.calls != 0 {
+		t.Errorf("provider called %d times for a cached number, want 0", provider.calls)
+	}
+
+	reason, err = check.Check(context.Background(), validated, "+254700000002")
+	if err != nil || reason != "number unreachable (landline)" {
+		t.Errorf("Check(landline) = %q, %v, want number unreachable (landline)", reason, err)
+	}
+
+	// Campaigns without the flag are not looked up at all
+	if reason, err := check.Check(context.Background(), &models.Campaign{ID: 2}, "+254700000002"); err != nil || reason != "" {
+		t.Errorf("Check(unflagged) = %q, %v, want no reason", reason, err)
+	}
+	if provider.calls != 1 {
+		t.Errorf("provider called %d times, want 1", provider.calls)
+	}
+}
+
+func TestNumberCheck_Check_LookupFailureSends(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	lookupRepo := mocks.NewMockNumberLookupRepository(ctrl)
+	lookupRepo.EXPECT().Get(gomock.Any(), "+254700000001", gomock.Any()).Return(nil, models.ErrNotFoundWithMsg("no lookup"))
+
+	provider := &stubNumberLookup{err: errors.New("lookup provider down")}
+	check := NewNumberCheck(lookupRepo, provider, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	reason, err := check.Check(context.Background(), &models.Campaign{ID: 1, ValidateNumbers: true}, "+254700000001")
+	if err != nil || reason != "" {
+		t.Errorf("Check() = %q, %v, want the message let through", reason, err)
+	}
+}
+
+func TestTwilioNumberLookup_Lookup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "AC123" || pass != "token" || r.URL.Query().Get("Fields") != "line_type_intelligence" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/+15005550001":
+			w.Write([]byte(`{"valid":false,"validation_errors":["TOO_SHORT"]}`))
+		case "/+15005550002":
+			w.Write([]byte(`{"valid":true,"line_type_intelligence":{"type":"landline"}}`))
+		default:
+			w.Write([]byte(`{"valid":true,"line_type_intelligence":{"type":"mobile"}}`))
+		}
+	}))
+	defer server.Close()
+
+	lookup := &twilioNumberLookup{baseURL: server.URL, accountSID: "AC123", authToken: "token", client: server.Client()}
+
+	tests := []struct {
+		phone         string
+		wantReachable bool
+		wantStatus    string
+	}{
+		{phone: "+15005550001", wantStatus: "too_short"},
+		{phone: "+15005550002", wantStatus: "landline"},
+		{phone: "+15005550009", wantReachable: true, wantStatus: "mobile"},
+	}
+
+	for _, tt := range tests {
+		got, err := lookup.Lookup(context.Background(), tt.phone)
+		if err != nil {
+			t.Fatalf("Lookup(%s) error = %v", tt.phone, err)
+		}
+		if got.Reachable != tt.wantReachable || got.Status != tt.wantStatus {
+			t.Errorf("Lookup(%s) = %v %q, want %v %q", tt.phone, got.Reachable, got.Status, tt.wantReachable, tt.wantStatus)
+		}
+	}
+
+	if _, err := NewNumberLookupProvider(ProviderTwilio, "AC123"); err == nil {
+		t.Error("NewNumberLookupProvider() without an auth token, want error")
+	}
+}
@@ -0,0 +1,117 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// outageCheckInterval is how often the monitor looks for sustained outages
+const outageCheckInterval = 10 * time.Second
+
+// AlertTypeCampaignsPaused is raised when campaigns are paused by an outage
+const AlertTypeCampaignsPaused = "campaigns_auto_paused"
+
+// OutageMonitor pauses sending campaigns on a channel whose circuit has stayed
+// open for longer than pauseAfter, and raises an alert
+type OutageMonitor struct {
+	breaker      *CircuitBreaker
+	campaignRepo repository.CampaignRepository
+	alerter      Alerter
+	now          func() time.Time
+	logger       *slog.Logger
+
+	mu         sync.Mutex
+	pauseAfter time.Duration
+}
+
+// NewOutageMonitor creates a new outage monitor
+func NewOutageMonitor(
+	breaker *CircuitBreaker,
+	campaignRepo repository.CampaignRepository,
+	alerter Alerter,
+	pauseAfter time.Duration,
+	logger *slog.Logger,
+) *OutageMonitor {
+	return &OutageMonitor{
+		breaker:      breaker,
+		campaignRepo: campaignRepo,
+		alerter:      alerter,
+		pauseAfter:   pauseAfter,
+		now:          time.Now,
+		logger:       logger,
+	}
+}
+
+// SetPauseAfter changes how long a circuit may stay open before its campaigns are paused
+func (m *OutageMonitor) SetPauseAfter(pauseAfter time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pauseAfter = pauseAfter
+}
+
+// Run checks for sustained outages until ctx is done
+func (m *OutageMonitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(outageCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.check(ctx)
+		}
+	}
+}
+
+// check pauses campaigns on every channel that has been open past the threshold.
+// Pausing only touches campaigns still sending, so repeated checks during the
+// same outage only alert about newly paused campaigns.
+func (m *OutageMonitor) check(ctx context.Context) {
+	now := m.now()
+
+	m.mu.Lock()
+	pauseAfter := m.pauseAfter
+	m.mu.Unlock()
+
+	for channel, openedAt := range m.breaker.OpenChannels() {
+		if now.Sub(openedAt) < pauseAfter {
+			continue
+		}
+
+		reason := fmt.Sprintf("provider outage: %s circuit open since %s", channel, openedAt.UTC().Format(time.RFC3339))
+
+		ids, err := m.campaignRepo.PauseSendingByChannel(ctx, channel, reason)
+		if err != nil {
+			m.logger.Error("failed to pause campaigns",
+				slog.String("channel", channel),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		if len(ids) == 0 {
+			continue
+		}
+
+		m.logger.Warn("campaigns paused by provider outage",
+			slog.String("channel", channel),
+			slog.Any("campaign_ids", ids),
+		)
+
+		err = m.alerter.Alert(ctx, Alert{
+			Type:        AlertTypeCampaignsPaused,
+			Message:     reason + "; resume with POST /api/campaigns/{id}/resume once the provider recovers",
+			Channel:     channel,
+			CampaignIDs: ids,
+			At:          now,
+		})
+		if err != nil {
+			m.logger.Error("failed to send alert", slog.String("error", err.Error()))
+		}
+	}
+}
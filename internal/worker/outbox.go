@@ -0,0 +1,117 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// Outbox relay tuning
+const (
+	relayInterval  = 30 * time.Second
+	relayBatchSize = 1000
+	// relaySettle is how long a message may stay unqueued before the relay
+	// publishes it. It leaves the API time to publish and mark the messages it
+	// has just created, so they are not queued twice.
+	relaySettle = time.Minute
+	// claimLease is how long a worker may hold a message in 'sending' before
+	// the relay returns it to pending. It is well above the longest a send
+	// takes, including waiting for the rate limiter.
+	claimLease = 15 * time.Minute
+)
+
+// JobPublisher publishes jobs to be processed now
+type JobPublisher interface {
+	Publish(ctx context.Context, job *models.MessageJob) error
+}
+
+// OutboxRelay publishes the pending messages that were stored but never
+// queued, such as those of a send that stopped between writing its messages
+// and publishing their jobs. Together with the API marking what it queued,
+// this guarantees every pending message is dispatched at least once. It also
+// reclaims the messages of workers that died while sending them.
+type OutboxRelay struct {
+	messageRepo repository.OutboundMessageRepository
+	publisher   JobPublisher
+	logger      *slog.Logger
+}
+
+// NewOutboxRelay creates a new outbox relay
+func NewOutboxRelay(messageRepo repository.OutboundMessageRepository, publisher JobPublisher, logger *slog.Logger) *OutboxRelay {
+	return &OutboxRelay{
+		messageRepo: messageRepo,
+		publisher:   publisher,
+		logger:      logger,
+	}
+}
+
+// Run relays unqueued messages at start-up and then every interval until ctx is done
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(relayInterval)
+	defer ticker.Stop()
+
+	for {
+		r.relay(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// relay reclaims stale claims, then publishes unqueued messages in batches
+// until none is left
+func (r *OutboxRelay) relay(ctx context.Context) {
+	r.reclaim(ctx)
+
+	publish := func(ctx context.Context, id, campaignID int64) error {
+		return r.publisher.Publish(ctx, &models.MessageJob{OutboundMessageID: id, CampaignID: campaignID})
+	}
+
+	total := 0
+	for ctx.Err() == nil {
+		relayed, err := r.messageRepo.RelayUnqueued(ctx, relaySettle, relayBatchSize, publish)
+		total += relayed
+		if err != nil {
+			r.logger.Error("failed to relay unqueued messages", slog.String("error", err.Error()))
+			break
+		}
+		if relayed < relayBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		r.logger.Warn("queued messages that were never published",
+			slog.Int("messages", total),
+		)
+	}
+}
+
+// reclaim returns the messages whose claim outlived claimLease to pending, in
+// batches until none is left. The relay publishes them once they have settled.
+func (r *OutboxRelay) reclaim(ctx context.Context) {
+	var total int64
+	for ctx.Err() == nil {
+		reclaimed, err := r.messageRepo.ReclaimStale(ctx, time.Now().Add(-claimLease), relayBatchSize)
+		if err != nil {
+			r.logger.Error("failed to reclaim stale messages", slog.String("error", err.Error()))
+			break
+		}
+		total += reclaimed
+		if reclaimed < relayBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		r.logger.Warn("reclaimed messages whose sending worker stopped",
+			slog.Int64("messages", total),
+		)
+	}
+}
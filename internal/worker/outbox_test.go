@@ -0,0 +1,109 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// unqueuedMessages is a fixed set of unqueued messages to relay, and of
+// messages whose claim went stale
+type unqueuedMessages struct {
+	unqueued []int64
+	stale    []int64
+	batches  int
+}
+
+// repo returns a mock repository relaying the set
+func (m *unqueuedMessages) repo(t *testing.T) *mocks.MockOutboundMessageRepository {
+	repo := mocks.NewMockOutboundMessageRepository(gomock.NewController(t))
+	repo.EXPECT().RelayUnqueued(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(m.relay).AnyTimes()
+	repo.EXPECT().ReclaimStale(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(m.reclaim).AnyTimes()
+	return repo
+}
+
+func (m *unqueuedMessages) relay(ctx context.Context, settle time.Duration, limit int, publish func(ctx context.Context, id, campaignID int64) error) (int, error) {
+	m.batches++
+	relayed := 0
+	for len(m.unqueued) > 0 && relayed < limit {
+		if err := publish(ctx, m.unqueued[0], 1); err != nil {
+			return relayed, err
+		}
+		m.unqueued = m.unqueued[1:]
+		relayed++
+	}
+	return relayed, nil
+}
+
+func (m *unqueuedMessages) reclaim(ctx context.Context, claimedBefore time.Time, limit int) (int64, error) {
+	if time.Since(claimedBefore) < claimLease {
+		return 0, errors.New("claims reclaimed before their lease ran out")
+	}
+	n := min(limit, len(m.stale))
+	m.unqueued = append(m.unqueued, m.stale[:n]...)
+	m.stale = m.stale[n:]
+	return int64(n), nil
+}
+
+// recordingPublisher records published jobs and fails after a given count
+type recordingPublisher struct {
+	published []int64
+	failAfter int
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, job *models.MessageJob) error {
+	if p.failAfter > 0 && len(p.published) == p.failAfter {
+		return errors.New("queue unavailable")
+	}
+	p.published = append(p.published, job.OutboundMessageID)
+	return nil
+}
+
+func TestOutboxRelay_PublishesEveryUnqueuedMessage(t *testing.T) {
+	unqueued := make([]int64, relayBatchSize+5)
+	for i := range unqueued {
+		unqueued[i] = int64(i + 1)
+	}
+	messages := &unqueuedMessages{unqueued: unqueued}
+	publisher := &recordingPublisher{}
+
+	NewOutboxRelay(messages.repo(t), publisher, slog.New(slog.NewJSONHandler(os.Stdout, nil))).relay(context.Background())
+
+	if len(publisher.published) != len(unqueued) {
+		t.Errorf("published = %d jobs, want %d", len(publisher.published), len(unqueued))
+	}
+	if messages.batches != 2 {
+		t.Errorf("batches = %d, want 2", messages.batches)
+	}
+}
+
+func TestOutboxRelay_StopsWhenPublishingFails(t *testing.T) {
+	messages := &unqueuedMessages{unqueued: []int64{1, 2, 3}}
+	publisher := &recordingPublisher{failAfter: 1}
+
+	NewOutboxRelay(messages.repo(t), publisher, slog.New(slog.NewJSONHandler(os.Stdout, nil))).relay(context.Background())
+
+	// The rest stay unqueued for the next run
+	if len(messages.unqueued) != 2 || messages.batches != 1 {
+		t.Errorf("unqueued = %v after %d batches, want [2 3] after 1", messages.unqueued, messages.batches)
+	}
+}
+
+func TestOutboxRelay_RepublishesStaleClaims(t *testing.T) {
+	messages := &unqueuedMessages{unqueued: []int64{1}, stale: []int64{2, 3}}
+	publisher := &recordingPublisher{}
+
+	NewOutboxRelay(messages.repo(t), publisher, slog.New(slog.NewJSONHandler(os.Stdout, nil))).relay(context.Background())
+
+	if len(messages.stale) != 0 || len(publisher.published) != 3 {
+		t.Errorf("published = %v with %v still claimed, want [1 2 3] and none", publisher.published, messages.stale)
+	}
+}
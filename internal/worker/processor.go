@@ -2,11 +2,32 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"math/rand/v2"
+	"runtime/debug"
+	"sync"
+	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/events"
 	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/queue"
 	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/tracing"
+)
+
+// tracer starts the spans of provider sends
+var tracer = otel.Tracer("github.com/Raymond9734/campaign-messaging-backend/internal/worker")
+
+// Default retry backoff; the delay doubles with every failed attempt
+const (
+	defaultRetryBaseDelay = 30 * time.Second
+	defaultRetryMaxDelay  = 30 * time.Minute
 )
 
 // MessageProcessor processes message jobs from the queue
@@ -15,31 +36,115 @@ type MessageProcessor struct {
 	campaignRepo repository.CampaignRepository
 	customerRepo repository.CustomerRepository
 	sender       MessageSender
+	sandbox      MessageSender
+	scheduler    JobScheduler
+	gates        []SendGate
+	costs        *CostGuard
+	concurrency  *ConcurrencyLimit
+	registration *RegistrationCheck
+	numbers      *NumberCheck
+	events       *events.Bus
+	history      repository.MessageEventRepository
 	maxRetries   int
+	now          func() time.Time
 	logger       *slog.Logger
+
+	mu             sync.Mutex
+	retryBaseDelay time.Duration
+	retryMaxDelay  time.Duration
 }
 
-// NewMessageProcessor creates a new message processor
+// NewMessageProcessor creates a new message processor.
+// Jobs deferred by one of the gates, and failed sends awaiting a retry, are
+// handed to scheduler.
 func NewMessageProcessor(
 	messageRepo repository.OutboundMessageRepository,
 	campaignRepo repository.CampaignRepository,
 	customerRepo repository.CustomerRepository,
 	sender MessageSender,
+	scheduler JobScheduler,
 	maxRetries int,
 	logger *slog.Logger,
+	gates ...SendGate,
 ) *MessageProcessor {
 	return &MessageProcessor{
 		messageRepo:  messageRepo,
 		campaignRepo: campaignRepo,
 		customerRepo: customerRepo,
 		sender:       sender,
+		scheduler:    scheduler,
+		gates:        gates,
 		maxRetries:   maxRetries,
+		now:          time.Now,
 		logger:       logger,
+
+		retryBaseDelay: defaultRetryBaseDelay,
+		retryMaxDelay:  defaultRetryMaxDelay,
 	}
 }
 
-// Process handles a single message job
-func (p *MessageProcessor) Process(ctx context.Context, job *models.MessageJob) error {
+// SetCostGuard makes the processor book the cost of every send against the
+// campaign's cost cap
+func (p *MessageProcessor) SetCostGuard(costs *CostGuard) {
+	p.costs = costs
+}
+
+// SetConcurrencyLimit makes the processor hold to the max_in_flight limit of
+// every campaign that has one
+func (p *MessageProcessor) SetConcurrencyLimit(limit *ConcurrencyLimit) {
+	p.concurrency = limit
+}
+
+// SetRegistrationCheck makes the processor fail SMS from senders that are not
+// registered for the destination country, where registration is enforced
+func (p *MessageProcessor) SetRegistrationCheck(check *RegistrationCheck) {
+	p.registration = check
+}
+
+// SetNumberCheck makes the processor look up the numbers of campaigns flagged
+// validate_numbers and fail messages to unreachable ones unsent
+func (p *MessageProcessor) SetNumberCheck(check *NumberCheck) {
+	p.numbers = check
+}
+
+// SetEvents makes the processor publish every message outcome and campaign
+// completion on bus
+func (p *MessageProcessor) SetEvents(bus *events.Bus) {
+	p.events = bus
+}
+
+// SetMessageHistory makes the processor record every attempt and outcome as
+// a message event
+func (p *MessageProcessor) SetMessageHistory(history repository.MessageEventRepository) {
+	p.history = history
+}
+
+// SetSandboxSender sets the sender for messages of test campaigns, backed by
+// the providers' sandbox credentials. Without one, test messages are failed
+// rather than sent for real.
+func (p *MessageProcessor) SetSandboxSender(sandbox MessageSender) {
+	p.sandbox = sandbox
+}
+
+// SetRetryBackoff changes the delay before the first retry and the longest delay between retries
+func (p *MessageProcessor) SetRetryBackoff(base, maxDelay time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.retryBaseDelay = base
+	p.retryMaxDelay = maxDelay
+}
+
+// Process handles a single message job.
+// A panic while processing is recovered and recorded as a failed attempt, so a
+// single bad message cannot kill the worker.
+func (p *MessageProcessor) Process(ctx context.Context, job *models.MessageJob) (err error) {
+	defer p.recoverPanic(ctx, job, &err)
+
+	return p.process(ctx, job)
+}
+
+// process sends the message referenced by the job and records the outcome
+func (p *MessageProcessor) process(ctx context.Context, job *models.MessageJob) error {
 	// Fetch the outbound message from database
 	message, err := p.messageRepo.GetByID(ctx, job.OutboundMessageID)
 	if err != nil {
@@ -47,7 +152,23 @@ func (p *MessageProcessor) Process(ctx context.Context, job *models.MessageJob)
 			slog.Int64("message_id", job.OutboundMessageID),
 			slog.String("error", err.Error()),
 		)
-		return fmt.Errorf("failed to fetch message: %w", err)
+		return requeue(fmt.Errorf("failed to fetch message: %w", err))
+	}
+
+	// A job can be delivered more than once (requeues, resumes); never resend
+	if message.WasSent() {
+		p.logger.Info("message already sent, skipping",
+			slog.Int64("message_id", message.ID),
+		)
+		return nil
+	}
+
+	// Another worker holds the message; it is reclaimed should that worker die
+	if message.Status == models.MessageStatusSending {
+		p.logger.Info("message claimed by another worker, skipping",
+			slog.Int64("message_id", message.ID),
+		)
+		return nil
 	}
 
 	// Fetch campaign to get channel information
@@ -57,7 +178,34 @@ func (p *MessageProcessor) Process(ctx context.Context, job *models.MessageJob)
 			slog.Int64("campaign_id", message.CampaignID),
 			slog.String("error", err.Error()),
 		)
-		return fmt.Errorf("failed to fetch campaign: %w", err)
+		return requeue(fmt.Errorf("failed to fetch campaign: %w", err))
+	}
+	// Partials, warm-ups and sender registrations are looked up in the
+	// campaign's account
+	ctx = models.WithAccountID(ctx, campaign.AccountID)
+
+	// Nothing more is sent for a cancelled campaign
+	if campaign.Status == models.CampaignStatusCancelled {
+		return p.skipCancelled(ctx, message)
+	}
+
+	// Jobs of paused campaigns are dropped; resuming the campaign requeues the
+	// messages whose jobs were dropped
+	if campaign.Status == models.CampaignStatusPaused {
+		p.logger.Info("campaign paused, skipping message",
+			slog.Int64("message_id", message.ID),
+			slog.Int64("campaign_id", campaign.ID),
+		)
+		return p.dropJob(ctx, message.ID)
+	}
+
+	// QA traffic must never reach a live provider
+	sender := p.sender
+	if campaign.IsTest() {
+		if p.sandbox == nil {
+			return p.failUnsendable(ctx, message, "no sandbox sender configured for test campaigns")
+		}
+		sender = p.sandbox
 	}
 
 	// Fetch customer to get phone number
@@ -67,9 +215,94 @@ func (p *MessageProcessor) Process(ctx context.Context, job *models.MessageJob)
 			slog.Int64("customer_id", message.CustomerID),
 			slog.String("error", err.Error()),
 		)
-		return fmt.Errorf("failed to fetch customer: %w", err)
+		return requeue(fmt.Errorf("failed to fetch customer: %w", err))
+	}
+
+	// Customers who replied STOP after the send was built are not messaged
+	if customer.IsOptedOut() {
+		return p.failUnsendable(ctx, message, "customer opted out")
+	}
+
+	// Nor are customers snoozed after the send was built
+	if customer.IsSnoozed(p.now()) {
+		return p.failUnsendable(ctx, message, "customer snoozed")
+	}
+
+	// Nor are customers whose number hard bounced since, e.g. when a soft
+	// bounce is re-attempted
+	if customer.IsBounced() {
+		return p.failUnsendable(ctx, message, "customer number bounced")
+	}
+
+	// Carriers filter SMS from unregistered senders; sandbox sends never reach one
+	if p.registration != nil && !campaign.IsTest() {
+		reason, err := p.registration.Check(ctx, campaign, customer.Phone)
+		if err != nil {
+			p.logger.Error("failed to check sender registration",
+				slog.Int64("message_id", message.ID),
+				slog.String("error", err.Error()),
+			)
+			return requeue(err)
+		}
+		if reason != "" {
+			return p.failUnsendable(ctx, message, reason)
+		}
+	}
+
+	// Unreachable numbers are not worth paying for; sandbox sends cost nothing
+	if p.numbers != nil && !campaign.IsTest() {
+		reason, err := p.numbers.Check(ctx, campaign, customer.Phone)
+		if err != nil {
+			p.logger.Error("failed to check number",
+				slog.Int64("message_id", message.ID),
+				slog.String("error", err.Error()),
+			)
+			return requeue(err)
+		}
+		if reason != "" {
+			return p.failUnsendable(ctx, message, reason)
+		}
 	}
 
+	// Wait for a free slot when the campaign already has max_in_flight sends
+	// going; the slot is held until the outcome has been recorded. It is taken
+	// before the gates, as the warm-up and daily cap gates use up quota.
+	if p.concurrency != nil {
+		release, ok, err := p.concurrency.Acquire(ctx, campaign)
+		if err != nil {
+			p.logger.Error("failed to acquire send slot",
+				slog.Int64("message_id", message.ID),
+				slog.String("error", err.Error()),
+			)
+			return requeue(fmt.Errorf("failed to acquire send slot: %w", err))
+		}
+		if !ok {
+			return p.deferJob(ctx, job, p.now().Add(inFlightRetryDelay))
+		}
+		defer release()
+	}
+
+	// Defer the job if a gate (e.g. sender warm-up) doesn't allow sending yet
+	deferUntil, gate, err := p.checkGates(ctx, campaign, message)
+	if err != nil {
+		return err
+	}
+	if !deferUntil.IsZero() {
+		if campaignGate, ok := gate.(CampaignGate); ok {
+			return p.pauseUntil(ctx, job, campaign, campaignGate.PauseReason(), deferUntil)
+		}
+		return p.deferJob(ctx, job, deferUntil)
+	}
+
+	// From here on the quota the gates took is given back unless the message
+	// is sent
+	sent := false
+	defer func() {
+		if !sent {
+			p.refundQuota(ctx, campaign, message, p.gates)
+		}
+	}()
+
 	p.logger.Info("processing message",
 		slog.Int64("message_id", message.ID),
 		slog.Int64("campaign_id", campaign.ID),
@@ -77,8 +310,61 @@ func (p *MessageProcessor) Process(ctx context.Context, job *models.MessageJob)
 		slog.String("channel", campaign.Channel),
 	)
 
+	// Book the cost before sending; at the cap the campaign has been paused and
+	// the message stays pending until it is resumed. Test campaigns are not billed.
+	var cost float64
+	if p.costs != nil && !campaign.IsTest() {
+		cost = p.costs.Cost(campaign.Channel, message.RenderedContent)
+		ok, err := p.costs.Reserve(ctx, campaign, cost)
+		if err != nil {
+			p.logger.Error("failed to reserve message cost",
+				slog.Int64("message_id", message.ID),
+				slog.String("error", err.Error()),
+			)
+			return requeue(fmt.Errorf("failed to reserve message cost: %w", err))
+		}
+		if !ok {
+			p.logger.Info("campaign cost cap reached, skipping message",
+				slog.Int64("message_id", message.ID),
+				slog.Int64("campaign_id", campaign.ID),
+			)
+			return p.dropJob(ctx, message.ID)
+		}
+	}
+
+	// Claim the message last, so only the job that wins the claim sends it
+	claimed, err := p.messageRepo.ClaimMessage(ctx, message.ID)
+	if err != nil {
+		if p.costs != nil {
+			p.costs.Release(ctx, campaign.ID, cost)
+		}
+		p.logger.Error("failed to claim message",
+			slog.Int64("message_id", message.ID),
+			slog.String("error", err.Error()),
+		)
+		return requeue(fmt.Errorf("failed to claim message: %w", err))
+	}
+	if !claimed {
+		if p.costs != nil {
+			p.costs.Release(ctx, campaign.ID, cost)
+		}
+		p.logger.Info("message no longer pending, skipping",
+			slog.Int64("message_id", message.ID),
+		)
+		return nil
+	}
+
 	// Attempt to send the message
-	err = p.sender.Send(ctx, campaign.Channel, customer.Phone, message.RenderedContent)
+	p.recordEvent(ctx, message.ID, models.MessageEventAttempted, fmt.Sprintf("attempt %d via %s", message.RetryCount+1, campaign.Channel))
+	sendCtx, span := tracer.Start(ctx, "send "+campaign.Channel,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.Int64("campaign_id", campaign.ID),
+			attribute.Int64("outbound_message_id", message.ID),
+		),
+	)
+	providerMessageID, err := sender.Send(sendCtx, campaign.Channel, customer.Phone, message.RenderedContent)
+	tracing.End(span, err)
 
 	if err != nil {
 		// Sending failed
@@ -88,21 +374,176 @@ func (p *MessageProcessor) Process(ctx context.Context, job *models.MessageJob)
 			slog.String("error", err.Error()),
 		)
 
+		if p.costs != nil {
+			p.costs.Release(ctx, campaign.ID, cost)
+		}
+
 		return p.handleFailure(ctx, message, err)
 	}
 
 	// Sending succeeded
+	sent = true
 	p.logger.Info("message sent successfully",
 		slog.Int64("message_id", message.ID),
 		slog.String("customer_phone", customer.Phone),
 	)
 
-	return p.handleSuccess(ctx, message)
+	return p.handleSuccess(ctx, message, providerMessageID)
+}
+
+// requeue asks the consumer to put the job back on the queue after a
+// transient error hit before the message was claimed. An acked job would leave
+// the message pending with queued_at set, where neither the relay nor a resume
+// picks it up. A message, campaign or customer that no longer exists is not
+// worth retrying.
+func requeue(err error) error {
+	if errors.Is(err, models.ErrNotFound) || errors.Is(err, queue.ErrRequeue) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", queue.ErrRequeue, err)
+}
+
+// checkGates runs the send gates in order and returns the first deferral time,
+// if any, with the gate that deferred. The quota taken by the gates before it
+// is given back.
+func (p *MessageProcessor) checkGates(ctx context.Context, campaign *models.Campaign, message *models.OutboundMessage) (time.Time, SendGate, error) {
+	for i, gate := range p.gates {
+		deferUntil, err := gate.Check(ctx, campaign, message)
+		if err != nil {
+			p.logger.Error("failed to check send gate",
+				slog.Int64("message_id", message.ID),
+				slog.String("error", err.Error()),
+			)
+			p.refundQuota(ctx, campaign, message, p.gates[:i])
+			return time.Time{}, nil, requeue(fmt.Errorf("failed to check send gate: %w", err))
+		}
+		if !deferUntil.IsZero() {
+			p.refundQuota(ctx, campaign, message, p.gates[:i])
+			return deferUntil, gate, nil
+		}
+	}
+
+	return time.Time{}, nil, nil
+}
+
+// refundQuota gives back the quota the gates took for a message that is not
+// sent after all. A refund that fails is logged; the unit is lost for the day.
+func (p *MessageProcessor) refundQuota(ctx context.Context, campaign *models.Campaign, message *models.OutboundMessage, gates []SendGate) {
+	for _, gate := range gates {
+		quotaGate, ok := gate.(QuotaGate)
+		if !ok {
+			continue
+		}
+		if err := quotaGate.Refund(ctx, campaign, message); err != nil {
+			p.logger.Warn("failed to refund send quota",
+				slog.Int64("message_id", message.ID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// pauseUntil pauses a sending campaign until resumeAt and drops the job; the
+// message stays pending. At resumeAt the scheduler resumes the campaign and
+// requeues the messages whose jobs were dropped. A campaign that cannot be
+// paused, such as a test campaign sent directly, has the job deferred instead.
+func (p *MessageProcessor) pauseUntil(ctx context.Context, job *models.MessageJob, campaign *models.Campaign, reason string, resumeAt time.Time) error {
+	if campaign.Status != models.CampaignStatusSending {
+		return p.deferJob(ctx, job, resumeAt)
+	}
+
+	paused, err := p.campaignRepo.PauseUntil(ctx, campaign.ID, reason, resumeAt)
+	if err != nil {
+		p.logger.Error("failed to pause campaign",
+			slog.Int64("campaign_id", campaign.ID),
+			slog.String("error", err.Error()),
+		)
+		return requeue(fmt.Errorf("failed to pause campaign: %w", err))
+	}
+	if !paused {
+		return p.deferJob(ctx, job, resumeAt)
+	}
+
+	p.logger.Info("campaign paused until it may send again",
+		slog.Int64("campaign_id", campaign.ID),
+		slog.Int64("message_id", job.OutboundMessageID),
+		slog.String("reason", reason),
+		slog.Time("resume_at", resumeAt),
+	)
+
+	return p.dropJob(ctx, job.OutboundMessageID)
+}
+
+// dropJob marks a message unqueued as its job is dropped for a paused
+// campaign, so resuming the campaign publishes it again. Jobs still queued or
+// delayed when the campaign is resumed are left to run as they are.
+func (p *MessageProcessor) dropJob(ctx context.Context, messageID int64) error {
+	if err := p.messageRepo.MarkUnqueued(ctx, messageID); err != nil {
+		p.logger.Error("failed to mark message unqueued",
+			slog.Int64("message_id", messageID),
+			slog.String("error", err.Error()),
+		)
+		return requeue(fmt.Errorf("failed to mark message unqueued: %w", err))
+	}
+
+	return nil
+}
+
+// deferJob schedules the job to be processed again at the given time.
+// The message itself is left untouched, so it stays pending until then.
+func (p *MessageProcessor) deferJob(ctx context.Context, job *models.MessageJob, at time.Time) error {
+	if p.scheduler == nil {
+		return fmt.Errorf("cannot defer message %d: no job scheduler configured", job.OutboundMessageID)
+	}
+
+	if err := p.scheduler.PublishDelayed(ctx, job, at); err != nil {
+		p.logger.Error("failed to defer message",
+			slog.Int64("message_id", job.OutboundMessageID),
+			slog.String("error", err.Error()),
+		)
+		return requeue(fmt.Errorf("failed to defer message: %w", err))
+	}
+
+	p.logger.Info("message deferred",
+		slog.Int64("message_id", job.OutboundMessageID),
+		slog.Time("until", at),
+	)
+
+	return nil
 }
 
-// handleSuccess updates message status to sent
-func (p *MessageProcessor) handleSuccess(ctx context.Context, message *models.OutboundMessage) error {
-	err := p.messageRepo.UpdateStatus(ctx, message.ID, models.MessageStatusSent, nil)
+// recoverPanic records a panic from process as a failed attempt, including the
+// stack trace. The job is retried like any failed send while retries remain;
+// once they are exhausted the message is marked permanently failed, which
+// dead-letters it.
+func (p *MessageProcessor) recoverPanic(ctx context.Context, job *models.MessageJob, errp *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	stack := debug.Stack()
+	p.logger.Error("panic while processing message",
+		slog.Int64("message_id", job.OutboundMessageID),
+		slog.Any("panic", r),
+		slog.String("stack", string(stack)),
+	)
+
+	panicErr := fmt.Errorf("panic: %v\n%s", r, stack)
+
+	message, err := p.messageRepo.GetByID(ctx, job.OutboundMessageID)
+	if err != nil {
+		*errp = fmt.Errorf("failed to record panic: %w", err)
+		return
+	}
+
+	*errp = p.handleFailure(ctx, message, panicErr)
+}
+
+// handleSuccess updates message status to sent, keeping the provider's
+// message ID for its delivery report
+func (p *MessageProcessor) handleSuccess(ctx context.Context, message *models.OutboundMessage, providerMessageID string) error {
+	err := p.messageRepo.MarkSent(ctx, message.ID, providerMessageID)
 	if err != nil {
 		p.logger.Error("failed to update message status to sent",
 			slog.Int64("message_id", message.ID),
@@ -110,6 +551,13 @@ func (p *MessageProcessor) handleSuccess(ctx context.Context, message *models.Ou
 		)
 		return fmt.Errorf("failed to update message status: %w", err)
 	}
+	p.recordEvent(ctx, message.ID, models.MessageEventSent, providerMessageID)
+	p.events.Publish(ctx, events.MessageSent{
+		MessageID:         message.ID,
+		CampaignID:        message.CampaignID,
+		ProviderMessageID: providerMessageID,
+		At:                p.now().UTC(),
+	})
 
 	// Check if all messages for this campaign are complete
 	p.updateCampaignStatusIfComplete(ctx, message.CampaignID)
@@ -117,6 +565,33 @@ func (p *MessageProcessor) handleSuccess(ctx context.Context, message *models.Ou
 	return nil
 }
 
+// failUnsendable marks a message that can never be sent as failed without retrying it
+func (p *MessageProcessor) failUnsendable(ctx context.Context, message *models.OutboundMessage, reason string) error {
+	p.logger.Error("message cannot be sent",
+		slog.Int64("message_id", message.ID),
+		slog.String("reason", reason),
+	)
+
+	if err := p.messageRepo.UpdateStatus(ctx, message.ID, models.MessageStatusFailed, &reason); err != nil {
+		p.logger.Error("failed to update message status to failed",
+			slog.Int64("message_id", message.ID),
+			slog.String("error", err.Error()),
+		)
+		return requeue(fmt.Errorf("failed to update message status: %w", err))
+	}
+	p.recordEvent(ctx, message.ID, models.MessageEventFailed, reason)
+	p.events.Publish(ctx, events.MessageFailed{
+		MessageID:  message.ID,
+		CampaignID: message.CampaignID,
+		Error:      reason,
+		At:         p.now().UTC(),
+	})
+
+	p.updateCampaignStatusIfComplete(ctx, message.CampaignID)
+
+	return nil
+}
+
 // handleFailure handles send failures with retry logic
 func (p *MessageProcessor) handleFailure(ctx context.Context, message *models.OutboundMessage, sendErr error) error {
 	// Increment retry count
@@ -145,6 +620,13 @@ func (p *MessageProcessor) handleFailure(ctx context.Context, message *models.Ou
 			)
 			return err
 		}
+		p.recordEvent(ctx, message.ID, models.MessageEventFailed, errMsg)
+		p.events.Publish(ctx, events.MessageFailed{
+			MessageID:  message.ID,
+			CampaignID: message.CampaignID,
+			Error:      errMsg,
+			At:         p.now().UTC(),
+		})
 
 		// Check if all messages for this campaign are complete
 		p.updateCampaignStatusIfComplete(ctx, message.CampaignID)
@@ -152,26 +634,123 @@ func (p *MessageProcessor) handleFailure(ctx context.Context, message *models.Ou
 		return nil // Job processed (albeit failed)
 	}
 
-	// Still have retries left - update status but keep as failed for retry
+	// Retries left: the message stays pending, keeping the error, until the
+	// delayed retry comes due, so the campaign is not completed in the meantime
+	errMsg := sendErr.Error()
+	if err := p.messageRepo.UpdateStatus(ctx, message.ID, models.MessageStatusPending, &errMsg); err != nil {
+		p.logger.Error("failed to update message status",
+			slog.Int64("message_id", message.ID),
+			slog.String("error", err.Error()),
+		)
+		return err
+	}
+	p.recordEvent(ctx, message.ID, models.MessageEventRetried, errMsg)
+	p.events.Publish(ctx, events.MessageFailed{
+		MessageID:  message.ID,
+		CampaignID: message.CampaignID,
+		Error:      errMsg,
+		Retrying:   true,
+		At:         p.now().UTC(),
+	})
+
+	return p.scheduleRetry(ctx, message, sendErr)
+}
+
+// scheduleRetry publishes the message's job to the delayed set after the
+// backoff for its attempt. Without a scheduler, or if scheduling fails, the
+// consumer is asked to requeue the job straight away so it is not lost.
+func (p *MessageProcessor) scheduleRetry(ctx context.Context, message *models.OutboundMessage, sendErr error) error {
+	attempt := message.RetryCount + 1
+	failure := fmt.Errorf("send failed, retry %d/%d: %w", attempt, p.maxRetries, sendErr)
+
+	if p.scheduler == nil {
+		return fmt.Errorf("%w: %w", queue.ErrRequeue, failure)
+	}
+
+	delay := p.retryDelay(attempt)
+	job := &models.MessageJob{OutboundMessageID: message.ID}
+	if err := p.scheduler.PublishDelayed(ctx, job, p.now().Add(delay)); err != nil {
+		p.logger.Error("failed to schedule retry, requeueing now",
+			slog.Int64("message_id", message.ID),
+			slog.String("error", err.Error()),
+		)
+		return fmt.Errorf("%w: %w", queue.ErrRequeue, failure)
+	}
+
 	p.logger.Info("message will be retried",
 		slog.Int64("message_id", message.ID),
-		slog.Int("retry_count", message.RetryCount+1),
+		slog.Int("retry_count", attempt),
 		slog.Int("max_retries", p.maxRetries),
+		slog.Duration("delay", delay),
 	)
 
-	errMsg := sendErr.Error()
-	if err := p.messageRepo.UpdateStatus(ctx, message.ID, models.MessageStatusFailed, &errMsg); err != nil {
-		p.logger.Error("failed to update message status",
-			slog.Int64("message_id", message.ID),
+	return nil
+}
+
+// skipCancelled marks a message of a cancelled campaign skipped. Cancelling
+// skips the pending messages already, so this only catches a message that was
+// pending again after a failed attempt, or a job that outran the cancellation.
+func (p *MessageProcessor) skipCancelled(ctx context.Context, message *models.OutboundMessage) error {
+	p.logger.Info("campaign cancelled, skipping message",
+		slog.Int64("message_id", message.ID),
+		slog.Int64("campaign_id", message.CampaignID),
+	)
+
+	if message.Status != models.MessageStatusPending {
+		return nil
+	}
+
+	if err := p.messageRepo.UpdateStatus(ctx, message.ID, models.MessageStatusSkipped, nil); err != nil {
+		return requeue(fmt.Errorf("failed to mark message skipped: %w", err))
+	}
+	p.recordEvent(ctx, message.ID, models.MessageEventSkipped, "campaign cancelled")
+
+	return nil
+}
+
+// recordEvent adds a step to the message's history. The history is for
+// support staff, so an event that cannot be recorded is logged and the send
+// carries on.
+func (p *MessageProcessor) recordEvent(ctx context.Context, messageID int64, eventType, detail string) {
+	if p.history == nil {
+		return
+	}
+
+	event := &models.MessageEvent{MessageID: messageID, Type: eventType}
+	if detail != "" {
+		event.Detail = &detail
+	}
+	if err := p.history.Record(ctx, event); err != nil {
+		p.logger.Warn("failed to record message event",
+			slog.Int64("message_id", messageID),
+			slog.String("type", eventType),
 			slog.String("error", err.Error()),
 		)
-		return err
 	}
+}
+
+// retryDelay returns the backoff before the given retry attempt: the base
+// delay doubled for every earlier attempt, capped at the max delay. Half of
+// the delay is jitter, so messages that failed together during an outage do
+// not all retry at the same moment.
+func (p *MessageProcessor) retryDelay(attempt int) time.Duration {
+	p.mu.Lock()
+	base, maxDelay := p.retryBaseDelay, p.retryMaxDelay
+	p.mu.Unlock()
 
-	// Return error so worker can potentially requeue if needed
-	// Note: In this simple implementation, we don't auto-requeue
-	// In production, we might add the job back to queue with exponential backoff
-	return fmt.Errorf("send failed, retry %d/%d: %w", message.RetryCount+1, p.maxRetries, sendErr)
+	delay := base
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + rand.N(delay-half+1)
 }
 
 // updateCampaignStatusIfComplete checks if all messages for a campaign are complete
@@ -187,32 +766,30 @@ func (p *MessageProcessor) updateCampaignStatusIfComplete(ctx context.Context, c
 		return
 	}
 
-	// Check if all messages are complete (no pending messages)
-	if campaign.Stats.Pending > 0 {
+	// A cancelled campaign keeps its status once its last sends finish
+	if campaign.Status == models.CampaignStatusCancelled {
+		return
+	}
+
+	// Check if all messages are complete (no pending or in-flight messages)
+	newStatus, complete := campaign.Stats.FinalStatus()
+	if !complete {
 		p.logger.Info("campaign still has pending messages",
 			slog.Int64("campaign_id", campaignID),
 			slog.Int64("pending", campaign.Stats.Pending),
+			slog.Int64("sending", campaign.Stats.Sending),
 		)
 		return
 	}
 
-	// All messages complete - determine final status
-	var newStatus string
-	if campaign.Stats.Failed > 0 && campaign.Stats.Sent == 0 {
-		// All messages failed
-		newStatus = models.CampaignStatusFailed
-	} else {
-		// At least some messages sent successfully
-		newStatus = models.CampaignStatusSent
-	}
-
 	// Only update if status changed
 	if campaign.Status == newStatus {
 		return
 	}
 
-	// Update campaign status
-	err = p.campaignRepo.UpdateStatus(ctx, campaignID, newStatus)
+	// A campaign whose send is still creating messages is completed by the
+	// send once it has created the last ones
+	completed, err := p.campaignRepo.CompleteSend(ctx, campaignID, newStatus)
 	if err != nil {
 		p.logger.Error("failed to update campaign status",
 			slog.Int64("campaign_id", campaignID),
@@ -221,6 +798,16 @@ func (p *MessageProcessor) updateCampaignStatusIfComplete(ctx context.Context, c
 		)
 		return
 	}
+	if !completed {
+		return
+	}
+
+	p.events.Publish(ctx, events.CampaignCompleted{
+		CampaignID: campaignID,
+		Status:     newStatus,
+		Stats:      campaign.Stats,
+		At:         p.now().UTC(),
+	})
 
 	p.logger.Info("campaign status updated",
 		slog.Int64("campaign_id", campaignID),
@@ -0,0 +1,73 @@
+package worker
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// recordedHistory keeps the message events it is handed
+type recordedHistory struct {
+	events []*models.MessageEvent
+}
+
+func (h *recordedHistory) Record(ctx context.Context, event *models.MessageEvent) error {
+	h.events = append(h.events, event)
+	return nil
+}
+
+func (h *recordedHistory) ListByMessage(ctx context.Context, messageID int64) ([]*models.MessageEvent, error) {
+	return h.events, nil
+}
+
+func TestMessageProcessor_Process_RecordsHistory(t *testing.T) {
+	messages := &messageStore{
+		byID: map[int64]*models.OutboundMessage{
+			1: {ID: 1, CampaignID: 1, CustomerID: 1, Status: models.MessageStatusPending, RenderedContent: "Hi Alice"},
+		},
+	}
+	campaigns := &campaignStore{
+		byID: map[int64]*models.CampaignWithStats{
+			1: {ID: 1, Channel: models.ChannelSMS, Status: models.CampaignStatusSending},
+		},
+	}
+	customers := &customerStore{
+		byID: map[int64]*models.Customer{1: {ID: 1, Phone: "+254712345001", FirstName: "Alice"}},
+	}
+	sender := &testMockSender{shouldFail: true}
+	history := &recordedHistory{}
+
+	processor := NewMessageProcessor(messages.repo(t), campaigns.repo(t), customers.repo(t), sender, &recordingScheduler{}, 3, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	processor.SetMessageHistory(history)
+
+	job := &models.MessageJob{OutboundMessageID: 1}
+	if err := processor.Process(context.Background(), job); err != nil {
+		t.Fatalf("Process(failing) error = %v", err)
+	}
+	sender.shouldFail = false
+	if err := processor.Process(context.Background(), job); err != nil {
+		t.Fatalf("Process(retry) error = %v", err)
+	}
+
+	var types []string
+	for _, event := range history.events {
+		types = append(types, event.Type)
+	}
+	want := []string{models.MessageEventAttempted, models.MessageEventRetried, models.MessageEventAttempted, models.MessageEventSent}
+	if !slices.Equal(types, want) {
+		t.Fatalf("events = %v, want %v", types, want)
+	}
+	if detail := history.events[1].Detail; detail == nil || *detail != "mock sender failed: simulated network error" {
+		t.Errorf("retried detail = %v, want the send error", detail)
+	}
+	if detail := history.events[2].Detail; detail == nil || *detail != "attempt 2 via sms" {
+		t.Errorf("second attempt detail = %v, want attempt 2 via sms", detail)
+	}
+	if detail := history.events[3].Detail; detail == nil || *detail != "test-message-id" {
+		t.Errorf("sent detail = %v, want the provider message ID", detail)
+	}
+}
@@ -5,15 +5,27 @@ import (
 	"errors"
 	"log/slog"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/events"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
 	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/queue"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/ratelimit"
 )
 
-// Mock repositories for testing
-type mockOutboundMessageRepo struct {
-	messages map[int64]*models.OutboundMessage
+// messageStore holds the outbound messages behind a generated repository
+// mock and records the status changes made to them and the messages whose
+// jobs were dropped. claimErr, when set, fails the next claim.
+type messageStore struct {
+	byID     map[int64]*models.OutboundMessage
 	updates  []statusUpdate
+	unqueued []int64
+	claimErr error
 }
 
 type statusUpdate struct {
@@ -22,132 +34,208 @@ type statusUpdate struct {
 	lastError *string
 }
 
-func (m *mockOutboundMessageRepo) GetByID(ctx context.Context, id int64) (*models.OutboundMessage, error) {
-	msg, ok := m.messages[id]
-	if !ok {
-		return nil, models.ErrNotFoundWithMsg("message not found")
-	}
-	return msg, nil
+// repo returns a mock repository reading and updating the store
+func (s *messageStore) repo(t *testing.T) *mocks.MockOutboundMessageRepository {
+	repo := mocks.NewMockOutboundMessageRepository(gomock.NewController(t))
+	repo.EXPECT().GetByID(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, id int64) (*models.OutboundMessage, error) {
+		msg, ok := s.byID[id]
+		if !ok {
+			return nil, models.ErrNotFoundWithMsg("message not found")
+		}
+		// Return a copy, as a database read would
+		copied := *msg
+		return &copied, nil
+	}).AnyTimes()
+	repo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(s.updateStatus).AnyTimes()
+	repo.EXPECT().MarkSent(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, id int64, providerMessageID string) error {
+		msg, ok := s.byID[id]
+		if !ok {
+			return models.ErrNotFoundWithMsg("message not found")
+		}
+		msg.ProviderMessageID = &providerMessageID
+		return s.updateStatus(ctx, id, models.MessageStatusSent, nil)
+	}).AnyTimes()
+	repo.EXPECT().ClaimMessage(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, id int64) (bool, error) {
+		if err := s.claimErr; err != nil {
+			s.claimErr = nil
+			return false, err
+		}
+		msg, ok := s.byID[id]
+		if !ok || msg.Status != models.MessageStatusPending {
+			return false, nil
+		}
+		msg.Status = models.MessageStatusSending
+		return true, nil
+	}).AnyTimes()
+	repo.EXPECT().MarkUnqueued(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, id int64) error {
+		s.unqueued = append(s.unqueued, id)
+		return nil
+	}).AnyTimes()
+	repo.EXPECT().IncrementRetryCount(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, id int64) error {
+		msg, ok := s.byID[id]
+		if !ok {
+			return models.ErrNotFoundWithMsg("message not found")
+		}
+		msg.RetryCount++
+		return nil
+	}).AnyTimes()
+	return repo
 }
 
-func (m *mockOutboundMessageRepo) UpdateStatus(ctx context.Context, id int64, status string, lastError *string) error {
-	msg, ok := m.messages[id]
+func (s *messageStore) updateStatus(ctx context.Context, id int64, status string, lastError *string) error {
+	msg, ok := s.byID[id]
 	if !ok {
 		return models.ErrNotFoundWithMsg("message not found")
 	}
 	msg.Status = status
 	msg.LastError = lastError
-	m.updates = append(m.updates, statusUpdate{id, status, lastError})
-	return nil
-}
-
-func (m *mockOutboundMessageRepo) IncrementRetryCount(ctx context.Context, id int64) error {
-	msg, ok := m.messages[id]
-	if !ok {
-		return models.ErrNotFoundWithMsg("message not found")
-	}
-	msg.RetryCount++
-	return nil
-}
-
-// Unused methods for interface compliance
-func (m *mockOutboundMessageRepo) Create(ctx context.Context, message *models.OutboundMessage) error {
-	return nil
-}
-func (m *mockOutboundMessageRepo) CreateBatch(ctx context.Context, messages []*models.OutboundMessage) error {
-	return nil
-}
-func (m *mockOutboundMessageRepo) List(ctx context.Context, filter models.OutboundMessageFilter) ([]*models.OutboundMessage, int64, error) {
-	return nil, 0, nil
-}
-func (m *mockOutboundMessageRepo) Update(ctx context.Context, message *models.OutboundMessage) error {
-	return nil
-}
-func (m *mockOutboundMessageRepo) GetPendingMessages(ctx context.Context, limit int) ([]*models.OutboundMessage, error) {
-	return nil, nil
-}
-
-type mockCampaignRepo struct {
-	campaigns map[int64]*models.CampaignWithStats
-}
-
-func (m *mockCampaignRepo) GetByID(ctx context.Context, id int64) (*models.Campaign, error) {
-	campaign, ok := m.campaigns[id]
-	if !ok {
-		return nil, models.ErrNotFoundWithMsg("campaign not found")
-	}
-	return &models.Campaign{
-		ID:           campaign.ID,
-		Name:         campaign.Name,
-		Channel:      campaign.Channel,
-		Status:       campaign.Status,
-		BaseTemplate: campaign.BaseTemplate,
-	}, nil
-}
-
-func (m *mockCampaignRepo) GetWithStats(ctx context.Context, id int64) (*models.CampaignWithStats, error) {
-	campaign, ok := m.campaigns[id]
-	if !ok {
-		return nil, models.ErrNotFoundWithMsg("campaign not found")
-	}
-	return campaign, nil
-}
-
-func (m *mockCampaignRepo) UpdateStatus(ctx context.Context, id int64, status string) error {
-	campaign, ok := m.campaigns[id]
-	if !ok {
-		return models.ErrNotFoundWithMsg("campaign not found")
-	}
-	campaign.Status = status
+	s.updates = append(s.updates, statusUpdate{id, status, lastError})
 	return nil
 }
 
-// Unused methods for interface compliance
-func (m *mockCampaignRepo) Create(ctx context.Context, campaign *models.Campaign) error {
-	return nil
-}
-func (m *mockCampaignRepo) List(ctx context.Context, filter models.CampaignFilter) ([]*models.Campaign, int64, error) {
-	return nil, 0, nil
-}
-func (m *mockCampaignRepo) Update(ctx context.Context, campaign *models.Campaign) error {
-	return nil
-}
-func (m *mockCampaignRepo) Delete(ctx context.Context, id int64) error {
-	return nil
+// campaignStore holds the campaigns behind a generated repository mock, the
+// cost reserved for each and those whose send is still building them
+type campaignStore struct {
+	byID     map[int64]*models.CampaignWithStats
+	costs    map[int64]float64
+	building map[int64]bool
 }
 
-type mockCustomerRepo struct {
-	customers map[int64]*models.Customer
+// repo returns a mock repository reading and updating the store
+func (s *campaignStore) repo(t *testing.T) *mocks.MockCampaignRepository {
+	notFound := models.ErrNotFoundWithMsg("campaign not found")
+	repo := mocks.NewMockCampaignRepository(gomock.NewController(t))
+	repo.EXPECT().GetByID(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, id int64) (*models.Campaign, error) {
+		campaign, ok := s.byID[id]
+		if !ok {
+			return nil, notFound
+		}
+		return &models.Campaign{
+			ID:              campaign.ID,
+			Name:            campaign.Name,
+			Channel:         campaign.Channel,
+			Status:          campaign.Status,
+			Environment:     campaign.Environment,
+			BaseTemplate:    campaign.BaseTemplate,
+			SenderID:        campaign.SenderID,
+			DeliveryWindows: campaign.DeliveryWindows,
+			Timezone:        campaign.Timezone,
+			ScheduledAt:     campaign.ScheduledAt,
+			MaxCost:         campaign.MaxCost,
+			MaxInFlight:     campaign.MaxInFlight,
+			PausedReason:    campaign.PausedReason,
+			ResumeAt:        campaign.ResumeAt,
+		}, nil
+	}).AnyTimes()
+	repo.EXPECT().GetWithStats(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, id int64) (*models.CampaignWithStats, error) {
+		campaign, ok := s.byID[id]
+		if !ok {
+			return nil, notFound
+		}
+		return campaign, nil
+	}).AnyTimes()
+	repo.EXPECT().CountMessages(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, id int64) (*models.CampaignStats, models.TxSnapshot, error) {
+		campaign, ok := s.byID[id]
+		if !ok {
+			return nil, models.TxSnapshot{}, notFound
+		}
+		return &campaign.Stats, models.TxSnapshot{}, nil
+	}).AnyTimes()
+	repo.EXPECT().GetCostAccrued(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, id int64) (float64, error) {
+		campaign, ok := s.byID[id]
+		if !ok {
+			return 0, notFound
+		}
+		return campaign.CostAccrued, nil
+	}).AnyTimes()
+	repo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, id int64, status string) error {
+		campaign, ok := s.byID[id]
+		if !ok {
+			return notFound
+		}
+		campaign.Status = status
+		return nil
+	}).AnyTimes()
+	repo.EXPECT().TransitionStatus(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, id int64, from, to string) (bool, error) {
+		campaign, ok := s.byID[id]
+		if !ok || campaign.Status != from {
+			return false, nil
+		}
+		campaign.Status = to
+		return true, nil
+	}).AnyTimes()
+	repo.EXPECT().CompleteSend(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, id int64, status string) (bool, error) {
+		campaign, ok := s.byID[id]
+		if !ok || s.building[id] ||
+			(campaign.Status != models.CampaignStatusSending && campaign.Status != models.CampaignStatusPaused) {
+			return false, nil
+		}
+		campaign.Status = status
+		return true, nil
+	}).AnyTimes()
+	repo.EXPECT().PauseSendingByChannel(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, channel, reason string) ([]int64, error) {
+		ids := []int64{}
+		for _, campaign := range s.byID {
+			if campaign.Channel == channel && campaign.Status == models.CampaignStatusSending {
+				campaign.Status = models.CampaignStatusPaused
+				campaign.PausedReason = &reason
+				ids = append(ids, campaign.ID)
+			}
+		}
+		return ids, nil
+	}).AnyTimes()
+	repo.EXPECT().PauseSending(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, id int64, reason string) (bool, error) {
+		campaign, ok := s.byID[id]
+		if !ok || campaign.Status != models.CampaignStatusSending {
+			return false, nil
+		}
+		campaign.Status = models.CampaignStatusPaused
+		campaign.PausedReason = &reason
+		return true, nil
+	}).AnyTimes()
+	repo.EXPECT().PauseUntil(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, id int64, reason string, resumeAt time.Time) (bool, error) {
+		campaign, ok := s.byID[id]
+		if !ok || !(campaign.Status == models.CampaignStatusSending || campaign.Status == models.CampaignStatusPaused && campaign.ResumeAt != nil) {
+			return false, nil
+		}
+		campaign.Status = models.CampaignStatusPaused
+		campaign.PausedReason = &reason
+		campaign.ResumeAt = &resumeAt
+		return true, nil
+	}).AnyTimes()
+	repo.EXPECT().ReserveCost(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, id int64, amount float64) (bool, error) {
+		if s.costs == nil {
+			s.costs = map[int64]float64{}
+		}
+		if maxCost := s.byID[id].MaxCost; maxCost != nil && s.costs[id]+amount > *maxCost {
+			return false, nil
+		}
+		s.costs[id] += amount
+		return true, nil
+	}).AnyTimes()
+	repo.EXPECT().ReleaseCost(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, id int64, amount float64) error {
+		s.costs[id] -= amount
+		return nil
+	}).AnyTimes()
+	return repo
 }
 
-func (m *mockCustomerRepo) GetByID(ctx context.Context, id int64) (*models.Customer, error) {
-	customer, ok := m.customers[id]
-	if !ok {
-		return nil, models.ErrNotFoundWithMsg("customer not found")
-	}
-	return customer, nil
+// customerStore holds the customers behind a generated repository mock
+type customerStore struct {
+	byID map[int64]*models.Customer
 }
 
-// Unused methods for interface compliance
-func (m *mockCustomerRepo) Create(ctx context.Context, customer *models.Customer) error {
-	return nil
-}
-func (m *mockCustomerRepo) List(ctx context.Context, filter models.CustomerFilter) ([]*models.Customer, int64, error) {
-	return nil, 0, nil
-}
-func (m *mockCustomerRepo) Update(ctx context.Context, customer *models.Customer) error {
-	return nil
-}
-func (m *mockCustomerRepo) Delete(ctx context.Context, id int64) error {
-	return nil
-}
-func (m *mockCustomerRepo) GetByPhone(ctx context.Context, phone string) (*models.Customer, error) {
-	for _, customer := range m.customers {
-		if customer.Phone == phone {
-			return customer, nil
+// repo returns a mock repository reading the store
+func (s *customerStore) repo(t *testing.T) *mocks.MockCustomerRepository {
+	repo := mocks.NewMockCustomerRepository(gomock.NewController(t))
+	repo.EXPECT().GetByID(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, id int64) (*models.Customer, error) {
+		customer, ok := s.byID[id]
+		if !ok {
+			return nil, models.ErrNotFoundWithMsg("customer not found")
 		}
-	}
-	return nil, models.ErrNotFoundWithMsg("customer not found")
+		return customer, nil
+	}).AnyTimes()
+	return repo
 }
 
 type testMockSender struct {
@@ -161,17 +249,17 @@ type sendCall struct {
 	content string
 }
 
-func (m *testMockSender) Send(ctx context.Context, channel, phone, content string) error {
+func (m *testMockSender) Send(ctx context.Context, channel, phone, content string) (string, error) {
 	m.calls = append(m.calls, sendCall{channel, phone, content})
 	if m.shouldFail {
-		return errors.New("mock sender failed: simulated network error")
+		return "", errors.New("mock sender failed: simulated network error")
 	}
-	return nil
+	return "test-message-id", nil
 }
 
 func TestMessageProcessor_Process_Success(t *testing.T) {
-	messageRepo := &mockOutboundMessageRepo{
-		messages: map[int64]*models.OutboundMessage{
+	messages := &messageStore{
+		byID: map[int64]*models.OutboundMessage{
 			1: {
 				ID:              1,
 				CampaignID:      1,
@@ -181,11 +269,10 @@ func TestMessageProcessor_Process_Success(t *testing.T) {
 				RetryCount:      0,
 			},
 		},
-		updates: []statusUpdate{},
 	}
 
-	campaignRepo := &mockCampaignRepo{
-		campaigns: map[int64]*models.CampaignWithStats{
+	campaigns := &campaignStore{
+		byID: map[int64]*models.CampaignWithStats{
 			1: {
 				ID:      1,
 				Name:    "Test Campaign",
@@ -202,8 +289,8 @@ func TestMessageProcessor_Process_Success(t *testing.T) {
 		},
 	}
 
-	customerRepo := &mockCustomerRepo{
-		customers: map[int64]*models.Customer{
+	customers := &customerStore{
+		byID: map[int64]*models.Customer{
 			1: {
 				ID:        1,
 				Phone:     "+254712345001",
@@ -215,7 +302,7 @@ func TestMessageProcessor_Process_Success(t *testing.T) {
 	sender := &testMockSender{shouldFail: false}
 
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
-	processor := NewMessageProcessor(messageRepo, campaignRepo, customerRepo, sender, 3, logger)
+	processor := NewMessageProcessor(messages.repo(t), campaigns.repo(t), customers.repo(t), sender, nil, 3, logger)
 
 	job := &models.MessageJob{OutboundMessageID: 1}
 
@@ -225,11 +312,11 @@ func TestMessageProcessor_Process_Success(t *testing.T) {
 	}
 
 	// Verify message status updated to "sent"
-	if len(messageRepo.updates) != 1 {
-		t.Fatalf("Expected 1 status update, got %d", len(messageRepo.updates))
+	if len(messages.updates) != 1 {
+		t.Fatalf("Expected 1 status update, got %d", len(messages.updates))
 	}
-	if messageRepo.updates[0].status != models.MessageStatusSent {
-		t.Errorf("Message status = %s, want %s", messageRepo.updates[0].status, models.MessageStatusSent)
+	if messages.updates[0].status != models.MessageStatusSent {
+		t.Errorf("Message status = %s, want %s", messages.updates[0].status, models.MessageStatusSent)
 	}
 
 	// Verify sender was called
@@ -251,15 +338,15 @@ func TestMessageProcessor_Process_Failure_WithRetries(t *testing.T) {
 		maxRetries     int
 		wantStatus     string
 		wantRetryCount int
-		wantErr        bool
+		wantRetry      bool
 	}{
 		{
 			name:           "first failure, retry available",
 			retryCount:     0,
 			maxRetries:     3,
-			wantStatus:     models.MessageStatusFailed,
+			wantStatus:     models.MessageStatusPending, // Waiting for the delayed retry
 			wantRetryCount: 1,
-			wantErr:        true,
+			wantRetry:      true,
 		},
 		{
 			name:           "at max retries, becomes permanent",
@@ -267,14 +354,14 @@ func TestMessageProcessor_Process_Failure_WithRetries(t *testing.T) {
 			maxRetries:     3,
 			wantStatus:     models.MessageStatusFailed,
 			wantRetryCount: 3,
-			wantErr:        false, // Permanent failure, no error returned
+			wantRetry:      false,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			messageRepo := &mockOutboundMessageRepo{
-				messages: map[int64]*models.OutboundMessage{
+			messages := &messageStore{
+				byID: map[int64]*models.OutboundMessage{
 					1: {
 						ID:              1,
 						CampaignID:      1,
@@ -284,11 +371,10 @@ func TestMessageProcessor_Process_Failure_WithRetries(t *testing.T) {
 						RetryCount:      tt.retryCount,
 					},
 				},
-				updates: []statusUpdate{},
 			}
 
-			campaignRepo := &mockCampaignRepo{
-				campaigns: map[int64]*models.CampaignWithStats{
+			campaigns := &campaignStore{
+				byID: map[int64]*models.CampaignWithStats{
 					1: {
 						ID:      1,
 						Channel: "sms",
@@ -304,38 +390,50 @@ func TestMessageProcessor_Process_Failure_WithRetries(t *testing.T) {
 				},
 			}
 
-			customerRepo := &mockCustomerRepo{
-				customers: map[int64]*models.Customer{
+			customers := &customerStore{
+				byID: map[int64]*models.Customer{
 					1: {ID: 1, Phone: "+254712345001"},
 				},
 			}
 
 			sender := &testMockSender{shouldFail: true}
+			scheduler := &recordingScheduler{}
+			now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
 
 			logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
-			processor := NewMessageProcessor(messageRepo, campaignRepo, customerRepo, sender, tt.maxRetries, logger)
+			processor := NewMessageProcessor(messages.repo(t), campaigns.repo(t), customers.repo(t), sender, scheduler, tt.maxRetries, logger)
+			processor.now = func() time.Time { return now }
 
 			job := &models.MessageJob{OutboundMessageID: 1}
 
-			err := processor.Process(context.Background(), job)
+			// Retries are scheduled, not returned to the consumer as errors
+			if err := processor.Process(context.Background(), job); err != nil {
+				t.Errorf("Process() error = %v, want nil", err)
+			}
 
-			if (err != nil) != tt.wantErr {
-				t.Errorf("Process() error = %v, wantErr %v", err, tt.wantErr)
+			if got := len(scheduler.deferred) == 1; got != tt.wantRetry {
+				t.Fatalf("delayed retries = %v, want retry %v", scheduler.deferred, tt.wantRetry)
+			}
+			if tt.wantRetry {
+				delay := scheduler.deferred[0].Sub(now)
+				if delay < defaultRetryBaseDelay/2 || delay > defaultRetryBaseDelay {
+					t.Errorf("retry delay = %v, want between %v and %v", delay, defaultRetryBaseDelay/2, defaultRetryBaseDelay)
+				}
 			}
 
 			// Verify status updated
-			if len(messageRepo.updates) == 0 {
+			if len(messages.updates) == 0 {
 				t.Fatal("Expected status update, got none")
 			}
 
-			lastUpdate := messageRepo.updates[len(messageRepo.updates)-1]
+			lastUpdate := messages.updates[len(messages.updates)-1]
 			if lastUpdate.status != tt.wantStatus {
 				t.Errorf("Message status = %s, want %s", lastUpdate.status, tt.wantStatus)
 			}
 
 			// Verify retry count incremented
-			if messageRepo.messages[1].RetryCount != tt.wantRetryCount {
-				t.Errorf("RetryCount = %d, want %d", messageRepo.messages[1].RetryCount, tt.wantRetryCount)
+			if messages.byID[1].RetryCount != tt.wantRetryCount {
+				t.Errorf("RetryCount = %d, want %d", messages.byID[1].RetryCount, tt.wantRetryCount)
 			}
 
 			// Verify error message populated
@@ -346,13 +444,41 @@ func TestMessageProcessor_Process_Failure_WithRetries(t *testing.T) {
 	}
 }
 
+func TestMessageProcessor_RetryDelay(t *testing.T) {
+	processor := NewMessageProcessor(nil, nil, nil, nil, nil, 10, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+	processor.SetRetryBackoff(time.Second, 10*time.Second)
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: time.Second},
+		{attempt: 2, want: 2 * time.Second},
+		{attempt: 3, want: 4 * time.Second},
+		{attempt: 4, want: 8 * time.Second},
+		{attempt: 5, want: 10 * time.Second}, // Capped
+		{attempt: 9, want: 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			delay := processor.retryDelay(tt.attempt)
+			if delay < tt.want/2 || delay > tt.want {
+				t.Fatalf("retryDelay(%d) = %v, want between %v and %v", tt.attempt, delay, tt.want/2, tt.want)
+			}
+		}
+	}
+}
+
 func TestMessageProcessor_Process_CampaignStatusUpdate(t *testing.T) {
 	tests := []struct {
 		name              string
 		initialStatus     string
 		pendingCount      int64
+		sendingCount      int64
 		sentCount         int64
 		failedCount       int64
+		building          bool
 		wantCampaignStatus string
 	}{
 		{
@@ -387,12 +513,30 @@ func TestMessageProcessor_Process_CampaignStatusUpdate(t *testing.T) {
 			failedCount:        0,
 			wantCampaignStatus: "sending", // Should not change
 		},
+		{
+			name:               "still has messages in flight",
+			initialStatus:      "sending",
+			pendingCount:       0,
+			sendingCount:       2,
+			sentCount:          3,
+			failedCount:        0,
+			wantCampaignStatus: "sending", // Should not change
+		},
+		{
+			name:               "send still creating later batches",
+			initialStatus:      "sending",
+			pendingCount:       0,
+			sentCount:          5,
+			failedCount:        0,
+			building:           true,
+			wantCampaignStatus: "sending", // Completed by the send once built
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			messageRepo := &mockOutboundMessageRepo{
-				messages: map[int64]*models.OutboundMessage{
+			messages := &messageStore{
+				byID: map[int64]*models.OutboundMessage{
 					1: {
 						ID:              1,
 						CampaignID:      1,
@@ -402,28 +546,28 @@ func TestMessageProcessor_Process_CampaignStatusUpdate(t *testing.T) {
 						RetryCount:      0,
 					},
 				},
-				updates: []statusUpdate{},
 			}
 
-			campaignRepo := &mockCampaignRepo{
-				campaigns: map[int64]*models.CampaignWithStats{
+			campaigns := &campaignStore{
+				byID: map[int64]*models.CampaignWithStats{
 					1: {
 						ID:      1,
 						Channel: "sms",
 						Status:  tt.initialStatus,
 						Stats: models.CampaignStats{
-							Total:   tt.pendingCount + tt.sentCount + tt.failedCount,
+							Total:   tt.pendingCount + tt.sendingCount + tt.sentCount + tt.failedCount,
 							Pending: tt.pendingCount,
-							Sending: 0,
+							Sending: tt.sendingCount,
 							Sent:    tt.sentCount,
 							Failed:  tt.failedCount,
 						},
 					},
 				},
+				building: map[int64]bool{1: tt.building},
 			}
 
-			customerRepo := &mockCustomerRepo{
-				customers: map[int64]*models.Customer{
+			customers := &customerStore{
+				byID: map[int64]*models.Customer{
 					1: {ID: 1, Phone: "+254712345001"},
 				},
 			}
@@ -431,14 +575,85 @@ func TestMessageProcessor_Process_CampaignStatusUpdate(t *testing.T) {
 			sender := &testMockSender{shouldFail: false}
 
 			logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
-			processor := NewMessageProcessor(messageRepo, campaignRepo, customerRepo, sender, 3, logger)
+			processor := NewMessageProcessor(messages.repo(t), campaigns.repo(t), customers.repo(t), sender, nil, 3, logger)
 
 			job := &models.MessageJob{OutboundMessageID: 1}
 			_ = processor.Process(context.Background(), job)
 
 			// Verify campaign status
-			if campaignRepo.campaigns[1].Status != tt.wantCampaignStatus {
-				t.Errorf("Campaign status = %s, want %s", campaignRepo.campaigns[1].Status, tt.wantCampaignStatus)
+			if campaigns.byID[1].Status != tt.wantCampaignStatus {
+				t.Errorf("Campaign status = %s, want %s", campaigns.byID[1].Status, tt.wantCampaignStatus)
+			}
+		})
+	}
+}
+
+// panickingSender panics on every send
+type panickingSender struct{}
+
+func (s *panickingSender) Send(ctx context.Context, channel, phone, content string) (string, error) {
+	panic("provider client exploded")
+}
+
+func TestMessageProcessor_Process_Panic(t *testing.T) {
+	tests := []struct {
+		name        string
+		retryCount  int
+		wantRequeue bool
+	}{
+		{
+			name:        "retries remaining requeues",
+			retryCount:  0,
+			wantRequeue: true,
+		},
+		{
+			name:        "retries exhausted dead-letters",
+			retryCount:  2,
+			wantRequeue: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages := &messageStore{
+				byID: map[int64]*models.OutboundMessage{
+					1: {ID: 1, CampaignID: 1, CustomerID: 1, Status: models.MessageStatusPending, RetryCount: tt.retryCount},
+				},
+			}
+			campaigns := &campaignStore{
+				byID: map[int64]*models.CampaignWithStats{
+					1: {ID: 1, Channel: "sms", Status: models.CampaignStatusSending},
+				},
+			}
+			customers := &customerStore{
+				byID: map[int64]*models.Customer{
+					1: {ID: 1, Phone: "+254712345001"},
+				},
+			}
+
+			logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+			processor := NewMessageProcessor(messages.repo(t), campaigns.repo(t), customers.repo(t), &panickingSender{}, nil, 3, logger)
+
+			err := processor.Process(context.Background(), &models.MessageJob{OutboundMessageID: 1})
+
+			if got := errors.Is(err, queue.ErrRequeue); got != tt.wantRequeue {
+				t.Errorf("Process() error = %v, requeue = %v, want %v", err, got, tt.wantRequeue)
+			}
+
+			if len(messages.updates) != 1 {
+				t.Fatalf("Expected 1 status update, got %d", len(messages.updates))
+			}
+			update := messages.updates[0]
+			wantStatus := models.MessageStatusFailed
+			if tt.wantRequeue {
+				wantStatus = models.MessageStatusPending
+			}
+			if update.status != wantStatus {
+				t.Errorf("Message status = %s, want %s", update.status, wantStatus)
+			}
+			if update.lastError == nil || !strings.Contains(*update.lastError, "provider client exploded") ||
+				!strings.Contains(*update.lastError, "goroutine") {
+				t.Errorf("lastError should contain the panic value and stack trace, got %v", update.lastError)
 			}
 		})
 	}
@@ -477,7 +692,7 @@ func TestMockSender_Send(t *testing.T) {
 			successes := 0
 
 			for i := 0; i < tt.iterations; i++ {
-				err := sender.Send(context.Background(), "sms", "+254712345001", "test message")
+				_, err := sender.Send(context.Background(), "sms", "+254712345001", "test message")
 				if err == nil {
 					successes++
 				}
@@ -490,3 +705,384 @@ func TestMockSender_Send(t *testing.T) {
 		})
 	}
 }
+
+// fakeLimiter records the keys it was asked to wait on
+type fakeLimiter struct {
+	keys []string
+	err  error
+}
+
+func (l *fakeLimiter) Wait(ctx context.Context, key string) error {
+	l.keys = append(l.keys, key)
+	return l.err
+}
+func (l *fakeLimiter) SetRates(rates map[string]float64) {}
+func (l *fakeLimiter) Stats() map[string]ratelimit.Stats { return nil }
+func (l *fakeLimiter) Close() error                      { return nil }
+
+func TestRateLimitedSender_Send(t *testing.T) {
+	t.Run("waits on the channel and the global rate before sending", func(t *testing.T) {
+		limiter := &fakeLimiter{}
+		inner := &testMockSender{}
+		sender := NewRateLimitedSender(inner, limiter)
+
+		if _, err := sender.Send(context.Background(), "whatsapp", "+254712345001", "hi"); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+		if len(limiter.keys) != 2 || limiter.keys[0] != "whatsapp" || limiter.keys[1] != GlobalRateKey {
+			t.Errorf("limiter keys = %v, want [whatsapp %s]", limiter.keys, GlobalRateKey)
+		}
+		if len(inner.calls) != 1 {
+			t.Errorf("Expected 1 sender call, got %d", len(inner.calls))
+		}
+	})
+
+	t.Run("limiter error skips the send", func(t *testing.T) {
+		limiter := &fakeLimiter{err: context.Canceled}
+		inner := &testMockSender{}
+		sender := NewRateLimitedSender(inner, limiter)
+
+		_, err := sender.Send(context.Background(), "sms", "+254712345001", "hi")
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Send() error = %v, want context.Canceled", err)
+		}
+		if len(inner.calls) != 0 {
+			t.Errorf("Expected no sender calls, got %d", len(inner.calls))
+		}
+	})
+}
+
+func TestSendRates(t *testing.T) {
+	limits := map[string]float64{"sms": 100}
+
+	rates := SendRates(limits, 150)
+	if rates["sms"] != 100 || rates[GlobalRateKey] != 150 || len(rates) != 2 {
+		t.Errorf("SendRates() = %v, want sms=100 and %s=150", rates, GlobalRateKey)
+	}
+	if _, ok := limits[GlobalRateKey]; ok {
+		t.Error("SendRates() modified the channel limits")
+	}
+
+	// Without a global cap only the channels are throttled
+	if rates := SendRates(limits, 0); len(rates) != 1 {
+		t.Errorf("SendRates() without a cap = %v, want only sms", rates)
+	}
+}
+
+func TestMessageProcessor_Process_TestCampaign(t *testing.T) {
+	newStores := func() (*messageStore, *campaignStore, *customerStore) {
+		messages := &messageStore{
+			byID: map[int64]*models.OutboundMessage{
+				1: {ID: 1, CampaignID: 1, CustomerID: 1, Status: models.MessageStatusPending, RenderedContent: "Hi Alice!"},
+			},
+		}
+		campaigns := &campaignStore{
+			byID: map[int64]*models.CampaignWithStats{
+				1: {ID: 1, Channel: "sms", Status: models.CampaignStatusSending, Environment: models.CampaignEnvironmentTest, Stats: models.CampaignStats{Total: 1, Pending: 1}},
+			},
+		}
+		customers := &customerStore{
+			byID: map[int64]*models.Customer{
+				1: {ID: 1, Phone: "+254712345001"},
+			},
+		}
+		return messages, campaigns, customers
+	}
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	t.Run("sent through the sandbox without cost", func(t *testing.T) {
+		messages, campaigns, customers := newStores()
+		live, sandbox := &testMockSender{}, &testMockSender{}
+
+		processor := NewMessageProcessor(messages.repo(t), campaigns.repo(t), customers.repo(t), live, &recordingScheduler{}, 3, logger)
+		processor.SetSandboxSender(sandbox)
+		processor.SetCostGuard(NewCostGuard(campaigns.repo(t), &recordingAlerter{}, map[string]float64{"sms": 1.0}, logger))
+
+		if err := processor.Process(context.Background(), &models.MessageJob{OutboundMessageID: 1}); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+
+		if len(live.calls) != 0 {
+			t.Errorf("live sends = %d, want 0", len(live.calls))
+		}
+		if len(sandbox.calls) != 1 {
+			t.Errorf("sandbox sends = %d, want 1", len(sandbox.calls))
+		}
+		if got := campaigns.costs[1]; got != 0 {
+			t.Errorf("accrued cost = %v, want 0 for a test campaign", got)
+		}
+	})
+
+	t.Run("failed without a sandbox sender", func(t *testing.T) {
+		messages, campaigns, customers := newStores()
+		live := &testMockSender{}
+
+		processor := NewMessageProcessor(messages.repo(t), campaigns.repo(t), customers.repo(t), live, &recordingScheduler{}, 3, logger)
+
+		if err := processor.Process(context.Background(), &models.MessageJob{OutboundMessageID: 1}); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+
+		if len(live.calls) != 0 {
+			t.Errorf("live sends = %d, want 0", len(live.calls))
+		}
+		if got := messages.byID[1].Status; got != models.MessageStatusFailed {
+			t.Errorf("message status = %s, want failed", got)
+		}
+	})
+}
+
+func TestMessageProcessor_Process_OptedOutCustomer(t *testing.T) {
+	optedOutAt := time.Now()
+	messages := &messageStore{
+		byID: map[int64]*models.OutboundMessage{
+			1: {ID: 1, CampaignID: 1, CustomerID: 1, Status: models.MessageStatusPending, RenderedContent: "Hi Alice"},
+		},
+	}
+	campaigns := &campaignStore{
+		byID: map[int64]*models.CampaignWithStats{
+			1: {ID: 1, Channel: "sms", Status: "sending", Stats: models.CampaignStats{Total: 1}},
+		},
+	}
+	customers := &customerStore{
+		byID: map[int64]*models.Customer{
+			1: {ID: 1, Phone: "+254712345001", FirstName: "Alice", OptedOutAt: &optedOutAt},
+		},
+	}
+	sender := &testMockSender{}
+
+	processor := NewMessageProcessor(messages.repo(t), campaigns.repo(t), customers.repo(t), sender, nil, 3, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+	if err := processor.Process(context.Background(), &models.MessageJob{OutboundMessageID: 1}); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	if len(sender.calls) != 0 {
+		t.Errorf("sender called %d times, want no send to an opted-out customer", len(sender.calls))
+	}
+	if message := messages.byID[1]; message.Status != models.MessageStatusFailed || message.LastError == nil || *message.LastError != "customer opted out" {
+		t.Errorf("message = %s %v, want failed as opted out", message.Status, message.LastError)
+	}
+}
+
+func TestMessageProcessor_Process_LostClaim(t *testing.T) {
+	campaigns := &campaignStore{
+		byID: map[int64]*models.CampaignWithStats{
+			1: {ID: 1, Channel: "sms", Status: "sending", Stats: models.CampaignStats{Total: 1}},
+		},
+	}
+	customers := &customerStore{
+		byID: map[int64]*models.Customer{
+			1: {ID: 1, Phone: "+254712345001", FirstName: "Alice"},
+		},
+	}
+	sender := &testMockSender{}
+
+	// A duplicate job read the message while pending, but another worker
+	// claimed it first; nothing else is written to the message
+	messageRepo := mocks.NewMockOutboundMessageRepository(gomock.NewController(t))
+	messageRepo.EXPECT().GetByID(gomock.Any(), int64(1)).
+		Return(&models.OutboundMessage{ID: 1, CampaignID: 1, CustomerID: 1, Status: models.MessageStatusPending, RenderedContent: "Hi Alice"}, nil)
+	messageRepo.EXPECT().ClaimMessage(gomock.Any(), int64(1)).Return(false, nil)
+
+	processor := NewMessageProcessor(messageRepo, campaigns.repo(t), customers.repo(t), sender, nil, 3, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+	if err := processor.Process(context.Background(), &models.MessageJob{OutboundMessageID: 1}); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	if len(sender.calls) != 0 {
+		t.Errorf("sender called %d times, want no send without the claim", len(sender.calls))
+	}
+}
+
+func TestMessageProcessor_Process_TransientErrorRedelivered(t *testing.T) {
+	messages := &messageStore{
+		byID: map[int64]*models.OutboundMessage{
+			1: {ID: 1, CampaignID: 1, CustomerID: 1, Status: models.MessageStatusPending, RenderedContent: "Hi Alice"},
+		},
+		claimErr: errors.New("connection reset by peer"),
+	}
+	campaigns := &campaignStore{
+		byID: map[int64]*models.CampaignWithStats{
+			1: {ID: 1, Channel: "sms", Status: "sending", Stats: models.CampaignStats{Total: 1, Pending: 1}},
+		},
+	}
+	customers := &customerStore{
+		byID: map[int64]*models.Customer{
+			1: {ID: 1, Phone: "+254712345001", FirstName: "Alice"},
+		},
+	}
+	sender := &testMockSender{}
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	processor := NewMessageProcessor(messages.repo(t), campaigns.repo(t), customers.repo(t), sender, nil, 3, logger)
+
+	// The failed claim is handed back to the queue rather than acked, so the
+	// job is delivered again and the message sent
+	client := queue.NewMemoryClient(logger)
+	ctx := context.Background()
+	if err := client.Publish(ctx, &models.MessageJob{OutboundMessageID: 1}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	var attempts []error
+	finished := make(chan struct{}, 2)
+	consumeCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Consume(consumeCtx, func(ctx context.Context, job *models.MessageJob) error {
+			defer func() { finished <- struct{}{} }()
+			err := processor.Process(ctx, job)
+			attempts = append(attempts, err)
+			return err
+		}, 1)
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-finished:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("job handled %d times, want 2", i)
+		}
+	}
+	cancel()
+	<-done
+
+	if !errors.Is(attempts[0], queue.ErrRequeue) {
+		t.Errorf("first attempt error = %v, want a requeue", attempts[0])
+	}
+	if attempts[1] != nil {
+		t.Errorf("second attempt error = %v, want nil", attempts[1])
+	}
+	if len(sender.calls) != 1 || messages.byID[1].Status != models.MessageStatusSent {
+		t.Errorf("sender called %d times, message %s, want it sent once", len(sender.calls), messages.byID[1].Status)
+	}
+}
+
+func TestMessageProcessor_Process_ClaimedElsewhere(t *testing.T) {
+	messages := &messageStore{
+		byID: map[int64]*models.OutboundMessage{
+			1: {ID: 1, CampaignID: 1, CustomerID: 1, Status: models.MessageStatusSending, RenderedContent: "Hi Alice"},
+		},
+	}
+	campaigns := &campaignStore{
+		byID: map[int64]*models.CampaignWithStats{
+			1: {ID: 1, Channel: "sms", Status: "sending", Stats: models.CampaignStats{Total: 1, Sending: 1}},
+		},
+	}
+	sender := &testMockSender{}
+
+	processor := NewMessageProcessor(messages.repo(t), campaigns.repo(t), (&customerStore{}).repo(t), sender, nil, 3, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+	if err := processor.Process(context.Background(), &models.MessageJob{OutboundMessageID: 1}); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	if len(sender.calls) != 0 || len(messages.updates) != 0 {
+		t.Errorf("sender called %d times with updates %v, want the message left to the worker holding it", len(sender.calls), messages.updates)
+	}
+}
+
+func TestMessageProcessor_Process_SnoozedCustomer(t *testing.T) {
+	snoozedUntil := time.Now().Add(24 * time.Hour)
+	messages := &messageStore{
+		byID: map[int64]*models.OutboundMessage{
+			1: {ID: 1, CampaignID: 1, CustomerID: 1, Status: models.MessageStatusPending, RenderedContent: "Hi Alice"},
+		},
+	}
+	campaigns := &campaignStore{
+		byID: map[int64]*models.CampaignWithStats{
+			1: {ID: 1, Channel: "sms", Status: "sending", Stats: models.CampaignStats{Total: 1}},
+		},
+	}
+	customers := &customerStore{
+		byID: map[int64]*models.Customer{
+			1: {ID: 1, Phone: "+254712345001", FirstName: "Alice", SnoozedUntil: &snoozedUntil},
+		},
+	}
+	sender := &testMockSender{}
+
+	processor := NewMessageProcessor(messages.repo(t), campaigns.repo(t), customers.repo(t), sender, nil, 3, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+	if err := processor.Process(context.Background(), &models.MessageJob{OutboundMessageID: 1}); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	if len(sender.calls) != 0 {
+		t.Errorf("sender called %d times, want no send to a snoozed customer", len(sender.calls))
+	}
+	if message := messages.byID[1]; message.Status != models.MessageStatusFailed || message.LastError == nil || *message.LastError != "customer snoozed" {
+		t.Errorf("message = %s %v, want failed as snoozed", message.Status, message.LastError)
+	}
+}
+
+func TestMessageProcessor_Process_CancelledCampaign(t *testing.T) {
+	messages := &messageStore{
+		byID: map[int64]*models.OutboundMessage{
+			1: {ID: 1, CampaignID: 1, CustomerID: 1, Status: models.MessageStatusPending, RenderedContent: "Hi Alice"},
+		},
+	}
+	campaigns := &campaignStore{
+		byID: map[int64]*models.CampaignWithStats{
+			1: {ID: 1, Channel: "sms", Status: models.CampaignStatusCancelled, Stats: models.CampaignStats{Total: 1, Pending: 1}},
+		},
+	}
+	customers := &customerStore{
+		byID: map[int64]*models.Customer{
+			1: {ID: 1, Phone: "+254712345001", FirstName: "Alice"},
+		},
+	}
+	sender := &testMockSender{}
+
+	processor := NewMessageProcessor(messages.repo(t), campaigns.repo(t), customers.repo(t), sender, nil, 3, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+	if err := processor.Process(context.Background(), &models.MessageJob{OutboundMessageID: 1, CampaignID: 1}); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	if len(sender.calls) != 0 {
+		t.Errorf("sender called %d times, want no send for a cancelled campaign", len(sender.calls))
+	}
+	if got := messages.byID[1].Status; got != models.MessageStatusSkipped {
+		t.Errorf("message status = %s, want skipped", got)
+	}
+	if got := campaigns.byID[1].Status; got != models.CampaignStatusCancelled {
+		t.Errorf("campaign status = %s, want cancelled", got)
+	}
+}
+
+func TestMessageProcessor_Process_PublishesEvents(t *testing.T) {
+	messages := &messageStore{
+		byID: map[int64]*models.OutboundMessage{
+			1: {ID: 1, CampaignID: 1, CustomerID: 1, Status: models.MessageStatusPending, RenderedContent: "Hi Alice"},
+		},
+	}
+	campaigns := &campaignStore{
+		byID: map[int64]*models.CampaignWithStats{
+			1: {ID: 1, Name: "Test Campaign", Channel: "sms", Status: "sending", Stats: models.CampaignStats{Total: 1, Sent: 1}},
+		},
+	}
+	customers := &customerStore{
+		byID: map[int64]*models.Customer{
+			1: {ID: 1, Phone: "+254712345001", FirstName: "Alice"},
+		},
+	}
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	processor := NewMessageProcessor(messages.repo(t), campaigns.repo(t), customers.repo(t), &testMockSender{}, nil, 3, logger)
+	bus := events.NewBus(logger)
+	var published []events.Event
+	bus.SubscribeAll(func(ctx context.Context, event events.Event) {
+		published = append(published, event)
+	})
+	processor.SetEvents(bus)
+
+	if err := processor.Process(context.Background(), &models.MessageJob{OutboundMessageID: 1}); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	if len(published) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(published))
+	}
+	if got, ok := published[0].(events.MessageSent); !ok || got.MessageID != 1 {
+		t.Errorf("First event = %+v, want message 1 sent", published[0])
+	}
+	if got, ok := published[1].(events.CampaignCompleted); !ok || got.CampaignID != 1 || got.Status != models.CampaignStatusSent {
+		t.Errorf("Second event = %+v, want campaign 1 completed as sent", published[1])
+	}
+}
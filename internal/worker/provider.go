@@ -0,0 +1,288 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// Provider names: SENDER_PROVIDER picks the SMS provider and
+// WHATSAPP_PROVIDER the WhatsApp one
+const (
+	ProviderMock           = "mock"
+	ProviderAfricasTalking = "africastalking"
+	ProviderTwilio         = "twilio"
+	ProviderMeta           = "meta"
+)
+
+// Provider API endpoints
+const (
+	africasTalkingURL        = "https://api.africastalking.com/version1/messaging"
+	africasTalkingSandboxURL = "https://api.sandbox.africastalking.com/version1/messaging"
+	twilioURL                = "https://api.twilio.com/2010-04-01"
+	metaGraphURL             = "https://graph.facebook.com/v19.0"
+)
+
+// providerTimeout bounds a single provider API call
+const providerTimeout = 10 * time.Second
+
+// ProviderConfig selects and configures the sender for a provider
+type ProviderConfig struct {
+	// Provider is one of ProviderMock, ProviderAfricasTalking, ProviderTwilio
+	// or ProviderMeta
+	Provider string
+	// Credential is the provider's credential: "username:api_key" for
+	// Africa's Talking, "account_sid:auth_token" for Twilio and
+	// "phone_number_id:access_token" for the WhatsApp Cloud API
+	Credential string
+	// From is the sender ID or number messages are sent from; required by Twilio
+	From string
+	// Template is the approved WhatsApp template, as "name:language", that
+	// carries the rendered content; without one Meta messages are plain text
+	Template string
+	// Sandbox sends through the provider's sandbox, for test campaigns
+	Sandbox bool
+}
+
+// NewProviderSender creates the sender for cfg.Provider. Real providers send
+// on their own channel only: SMS, or WhatsApp for Meta.
+func NewProviderSender(cfg ProviderConfig) (MessageSender, error) {
+	if cfg.Provider == ProviderMock {
+		// The mock sandbox accepts every message
+		if cfg.Sandbox {
+			return NewMockSender(1.0), nil
+		}
+		return NewMockSender(0.92), nil
+	}
+	if cfg.Provider != ProviderAfricasTalking && cfg.Provider != ProviderTwilio && cfg.Provider != ProviderMeta {
+		return nil, fmt.Errorf("unknown provider %q", cfg.Provider)
+	}
+
+	user, secret, ok := strings.Cut(cfg.Credential, ":")
+	if !ok || user == "" || secret == "" {
+		// Never echo the credential, it holds a secret
+		return nil, fmt.Errorf("%s credential must have the form user:secret", cfg.Provider)
+	}
+
+	client := &http.Client{Timeout: providerTimeout}
+
+	if cfg.Provider == ProviderMeta {
+		template, err := parseWhatsAppTemplate(cfg.Template)
+		if err != nil {
+			return nil, err
+		}
+		// Meta test phone numbers use the live endpoint
+		return &whatsAppCloudSender{
+			baseURL:       metaGraphURL,
+			phoneNumberID: user,
+			accessToken:   secret,
+			template:      template,
+			client:        client,
+		}, nil
+	}
+
+	if cfg.Provider == ProviderTwilio {
+		if cfg.From == "" {
+			return nil, fmt.Errorf("twilio needs a sender number (SENDER_FROM)")
+		}
+		// Twilio test credentials use the live endpoint
+		return &twilioSender{
+			baseURL:    twilioURL,
+			accountSID: user,
+			authToken:  secret,
+			from:       cfg.From,
+			client:     client,
+		}, nil
+	}
+
+	endpoint := africasTalkingURL
+	if cfg.Sandbox {
+		endpoint = africasTalkingSandboxURL
+	}
+	return &africasTalkingSender{
+		url:      endpoint,
+		username: user,
+		apiKey:   secret,
+		from:     cfg.From,
+		client:   client,
+	}, nil
+}
+
+// channelSender routes each message to the sender for its channel
+type channelSender struct {
+	senders map[string]MessageSender
+}
+
+// NewChannelSender creates a sender per channel from configs, keyed by
+// channel, and routes messages by channel. Messages on a channel without a
+// sender fail.
+func NewChannelSender(configs map[string]ProviderConfig) (MessageSender, error) {
+	senders := make(map[string]MessageSender, len(configs))
+	for channel, cfg := range configs {
+		sender, err := NewProviderSender(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("%s sender: %w", channel, err)
+		}
+		senders[channel] = sender
+	}
+
+	return &channelSender{senders: senders}, nil
+}
+
+// Send passes the message to its channel's sender
+func (s *channelSender) Send(ctx context.Context, channel, phone, content string) (string, error) {
+	sender, ok := s.senders[channel]
+	if !ok {
+		return "", fmt.Errorf("no provider configured for %s messages", channel)
+	}
+
+	return sender.Send(ctx, channel, phone, content)
+}
+
+// africasTalkingSender sends SMS through the Africa's Talking messaging API
+type africasTalkingSender struct {
+	url      string
+	username string
+	apiKey   string
+	from     string
+	client   *http.Client
+}
+
+// africasTalkingResponse is the part of the messaging API response we read
+type africasTalkingResponse struct {
+	SMSMessageData struct {
+		Message    string `json:"Message"`
+		Recipients []struct {
+			StatusCode int    `json:"statusCode"`
+			Status     string `json:"status"`
+			MessageID  string `json:"messageId"`
+		} `json:"Recipients"`
+	} `json:"SMSMessageData"`
+}
+
+// Send posts the message and checks the recipient's status. Status codes
+// 100-102 (processed, sent, queued) mean the provider accepted it.
+func (s *africasTalkingSender) Send(ctx context.Context, channel, phone, content string) (string, error) {
+	if channel != models.ChannelSMS {
+		return "", fmt.Errorf("africastalking does not send %s messages", channel)
+	}
+
+	form := url.Values{
+		"username": {s.username},
+		"to":       {phone},
+		"message":  {content},
+	}
+	if s.from != "" {
+		form.Set("from", s.from)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create africastalking request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("apiKey", s.apiKey)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call africastalking: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("africastalking returned status %d: %s", resp.StatusCode, readErrorBody(resp.Body))
+	}
+
+	var result africasTalkingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode africastalking response: %w", err)
+	}
+
+	recipients := result.SMSMessageData.Recipients
+	if len(recipients) == 0 {
+		return "", fmt.Errorf("africastalking rejected the message: %s", result.SMSMessageData.Message)
+	}
+	if code := recipients[0].StatusCode; code < 100 || code > 102 {
+		return "", fmt.Errorf("africastalking rejected the message: %s (status %d)", recipients[0].Status, code)
+	}
+
+	return recipients[0].MessageID, nil
+}
+
+// twilioSender sends SMS through the Twilio Messages API
+type twilioSender struct {
+	baseURL    string
+	accountSID string
+	authToken  string
+	from       string
+	client     *http.Client
+}
+
+// twilioMessage is the part of a created Twilio message we read
+type twilioMessage struct {
+	SID string `json:"sid"`
+}
+
+// twilioError is the body of a failed Twilio API call
+type twilioError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Send creates a Twilio message; any 2xx response means it was accepted
+// and carries the message SID
+func (s *twilioSender) Send(ctx context.Context, channel, phone, content string) (string, error) {
+	if channel != models.ChannelSMS {
+		return "", fmt.Errorf("twilio does not send %s messages", channel)
+	}
+
+	form := url.Values{
+		"To":   {phone},
+		"From": {s.from},
+		"Body": {content},
+	}
+	endpoint := fmt.Sprintf("%s/Accounts/%s/Messages.json", s.baseURL, url.PathEscape(s.accountSID))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create twilio request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(s.accountSID, s.authToken)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call twilio: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr twilioError
+		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil || apiErr.Message == "" {
+			return "", fmt.Errorf("twilio returned status %d", resp.StatusCode)
+		}
+		return "", fmt.Errorf("twilio returned status %d: %s (code %d)", resp.StatusCode, apiErr.Message, apiErr.Code)
+	}
+
+	var message twilioMessage
+	if err := json.NewDecoder(resp.Body).Decode(&message); err != nil {
+		return "", fmt.Errorf("failed to decode twilio response: %w", err)
+	}
+
+	return message.SID, nil
+}
+
+// readErrorBody returns the start of an error response body for logging
+func readErrorBody(body io.Reader) string {
+	b, _ := io.ReadAll(io.LimitReader(body, 512))
+	return strings.TrimSpace(string(b))
+}
@@ -0,0 +1,149 @@
+package worker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestNewProviderSender(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     ProviderConfig
+		wantErr string
+	}{
+		{name: "mock", cfg: ProviderConfig{Provider: ProviderMock}},
+		{name: "africastalking", cfg: ProviderConfig{Provider: ProviderAfricasTalking, Credential: "acme:key_123"}},
+		{name: "twilio", cfg: ProviderConfig{Provider: ProviderTwilio, Credential: "AC123:token", From: "+15005550006"}},
+		{name: "unknown provider", cfg: ProviderConfig{Provider: "carrier-pigeon"}, wantErr: "unknown provider"},
+		{name: "malformed credential", cfg: ProviderConfig{Provider: ProviderAfricasTalking, Credential: "key_123"}, wantErr: "user:secret"},
+		{name: "twilio without sender", cfg: ProviderConfig{Provider: ProviderTwilio, Credential: "AC123:token"}, wantErr: "SENDER_FROM"},
+		{name: "meta", cfg: ProviderConfig{Provider: ProviderMeta, Credential: "1098:token", Template: "campaign_message:en"}},
+		{name: "meta with malformed template", cfg: ProviderConfig{Provider: ProviderMeta, Credential: "1098:token", Template: "campaign_message"}, wantErr: "name:language"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewProviderSender(tt.cfg)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("NewProviderSender() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("NewProviderSender() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestChannelSender_Send(t *testing.T) {
+	sender, err := NewChannelSender(map[string]ProviderConfig{
+		models.ChannelSMS: {Provider: ProviderMock, Sandbox: true},
+	})
+	if err != nil {
+		t.Fatalf("NewChannelSender() error = %v", err)
+	}
+
+	if _, err := sender.Send(context.Background(), models.ChannelSMS, "+254700000001", "Hi Alice!"); err != nil {
+		t.Errorf("Send() on sms error = %v", err)
+	}
+	if _, err := sender.Send(context.Background(), models.ChannelWhatsApp, "+254700000001", "Hi Alice!"); err == nil || !strings.Contains(err.Error(), "no provider") {
+		t.Errorf("Send() on whatsapp error = %v, want no provider", err)
+	}
+
+	if _, err := NewChannelSender(map[string]ProviderConfig{models.ChannelWhatsApp: {Provider: ProviderMeta}}); err == nil {
+		t.Error("NewChannelSender() without a whatsapp credential error = nil, want error")
+	}
+}
+
+func TestAfricasTalkingSender_Send(t *testing.T) {
+	var form map[string]string
+	status := 101
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("apiKey") != "key_123" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		r.ParseForm()
+		form = map[string]string{"username": r.Form.Get("username"), "to": r.Form.Get("to"), "message": r.Form.Get("message"), "from": r.Form.Get("from")}
+		w.Header().Set("Content-Type", "application/json")
+		if status == 101 {
+			w.Write([]byte(`{"SMSMessageData":{"Message":"Sent to 1/1","Recipients":[{"statusCode":101,"status":"Success","messageId":"ATXid_1"}]}}`))
+			return
+		}
+		w.Write([]byte(`{"SMSMessageData":{"Message":"Sent to 0/1","Recipients":[{"statusCode":403,"status":"InvalidPhoneNumber"}]}}`))
+	}))
+	defer server.Close()
+
+	sender := &africasTalkingSender{url: server.URL, username: "acme", apiKey: "key_123", from: "ACME", client: server.Client()}
+
+	id, err := sender.Send(context.Background(), models.ChannelSMS, "+254700000001", "Hi Alice!")
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if id != "ATXid_1" {
+		t.Errorf("Send() message ID = %q, want ATXid_1", id)
+	}
+	want := map[string]string{"username": "acme", "to": "+254700000001", "message": "Hi Alice!", "from": "ACME"}
+	for key, value := range want {
+		if form[key] != value {
+			t.Errorf("form %s = %q, want %q", key, form[key], value)
+		}
+	}
+
+	status = 403
+	if _, err := sender.Send(context.Background(), models.ChannelSMS, "+254700000001", "Hi Alice!"); err == nil || !strings.Contains(err.Error(), "InvalidPhoneNumber") {
+		t.Errorf("Send() to a rejected number error = %v, want InvalidPhoneNumber", err)
+	}
+
+	if _, err := sender.Send(context.Background(), models.ChannelWhatsApp, "+254700000001", "Hi Alice!"); err == nil {
+		t.Error("Send() on whatsapp succeeded, want an error")
+	}
+}
+
+func TestTwilioSender_Send(t *testing.T) {
+	var path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "AC123" || pass != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"code":20003,"message":"Authenticate"}`))
+			return
+		}
+		r.ParseForm()
+		path, body = r.URL.Path, r.Form.Get("Body")
+		if r.Form.Get("To") == "+15005550001" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":21211,"message":"The 'To' number is not a valid phone number."}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"sid":"SM123","status":"queued"}`))
+	}))
+	defer server.Close()
+
+	sender := &twilioSender{baseURL: server.URL, accountSID: "AC123", authToken: "token", from: "+15005550006", client: server.Client()}
+
+	id, err := sender.Send(context.Background(), models.ChannelSMS, "+15005550009", "Hi Alice!")
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if id != "SM123" {
+		t.Errorf("Send() message ID = %q, want SM123", id)
+	}
+	if path != "/Accounts/AC123/Messages.json" || body != "Hi Alice!" {
+		t.Errorf("request = %s with body %q, want /Accounts/AC123/Messages.json with the message", path, body)
+	}
+
+	if _, err := sender.Send(context.Background(), models.ChannelSMS, "+15005550001", "Hi Alice!"); err == nil || !strings.Contains(err.Error(), "21211") {
+		t.Errorf("Send() to an invalid number error = %v, want code 21211", err)
+	}
+
+	sender.authToken = "wrong"
+	if _, err := sender.Send(context.Background(), models.ChannelSMS, "+15005550009", "Hi Alice!"); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Send() with a bad token error = %v, want status 401", err)
+	}
+}
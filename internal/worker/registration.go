@@ -0,0 +1,62 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// RegistrationCheck blocks SMS from senders that are not registered for the
+// destination country, in the countries whose rules it enforces. Unlike a
+// SendGate it never defers: a send it blocks would not succeed later either.
+type RegistrationCheck struct {
+	registrationRepo repository.SenderRegistrationRepository
+	rules            []models.SenderRegistrationRule
+}
+
+// NewRegistrationCheck creates a check enforcing the registration rules of
+// countries, which must all have a rule in models.SenderRegistrationRules
+func NewRegistrationCheck(registrationRepo repository.SenderRegistrationRepository, countries []string) (*RegistrationCheck, error) {
+	rules, err := models.EnforcedRegistrationRules(countries)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RegistrationCheck{
+		registrationRepo: registrationRepo,
+		rules:            rules,
+	}, nil
+}
+
+// Check returns why the campaign may not message phone, or an empty string
+// when it may. Campaigns without a sender ID use the provider's default
+// sender, which is the provider's to register.
+func (c *RegistrationCheck) Check(ctx context.Context, campaign *models.Campaign, phone string) (string, error) {
+	if campaign.Channel != models.ChannelSMS || campaign.SenderID == nil {
+		return "", nil
+	}
+
+	senderID := *campaign.SenderID
+	rule, ok := models.RuleForPhone(c.rules, phone)
+	if !ok || !rule.Applies(senderID) {
+		return "", nil
+	}
+
+	registration, err := c.registrationRepo.Get(ctx, senderID, rule.Country)
+	if errors.Is(err, models.ErrNotFound) {
+		return fmt.Sprintf("sender %s is not registered for %s", senderID, rule.Country), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get sender registration: %w", err)
+	}
+
+	if registration.Type != rule.Type {
+		return fmt.Sprintf("sender %s has a %s registration for %s, which requires a %s registration",
+			senderID, registration.Type, rule.Country, rule.Type), nil
+	}
+
+	return "", nil
+}
@@ -0,0 +1,115 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestRegistrationCheck_Check(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	registrationRepo := mocks.NewMockSenderRegistrationRepository(ctrl)
+	registrationRepo.EXPECT().Get(gomock.Any(), "SHOP", "KE").
+		Return(&models.SenderRegistration{SenderID: "SHOP", Country: "KE", Type: models.RegistrationTypeSenderID, RegistrationID: "KE-123"}, nil).
+		AnyTimes()
+	registrationRepo.EXPECT().Get(gomock.Any(), "SHOP", "NG").
+		Return(nil, models.ErrNotFoundWithMsg("sender SHOP is not registered for NG")).
+		AnyTimes()
+	registrationRepo.EXPECT().Get(gomock.Any(), "+15005550006", "US").
+		Return(&models.SenderRegistration{SenderID: "+15005550006", Country: "US", Type: models.RegistrationTypeSenderID, RegistrationID: "X"}, nil).
+		AnyTimes()
+
+	check, err := NewRegistrationCheck(registrationRepo, []string{"KE", "NG", "US"})
+	if err != nil {
+		t.Fatalf("NewRegistrationCheck() error = %v", err)
+	}
+
+	sms := func(senderID string) *models.Campaign {
+		return &models.Campaign{Channel: models.ChannelSMS, SenderID: &senderID}
+	}
+
+	tests := []struct {
+		name       string
+		campaign   *models.Campaign
+		phone      string
+		wantReason string
+	}{
+		{name: "registered", campaign: sms("SHOP"), phone: "+254712345001"},
+		{name: "unregistered", campaign: sms("SHOP"), phone: "+2348012345678", wantReason: "sender SHOP is not registered for NG"},
+		{name: "numeric sender outside alphanumeric rule", campaign: sms("40404"), phone: "+2348012345678"},
+		{name: "country not enforced", campaign: sms("SHOP"), phone: "+919812345678"},
+		{name: "no sender ID", campaign: &models.Campaign{Channel: models.ChannelSMS}, phone: "+2348012345678"},
+		{name: "whatsapp", campaign: &models.Campaign{Channel: models.ChannelWhatsApp, SenderID: sms("SHOP").SenderID}, phone: "+2348012345678"},
+		{
+			name:       "wrong registration type",
+			campaign:   sms("+15005550006"),
+			phone:      "+14155550100",
+			wantReason: "sender +15005550006 has a sender_id registration for US, which requires a campaign registration",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, err := check.Check(context.Background(), tt.campaign, tt.phone)
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if reason != tt.wantReason {
+				t.Errorf("Check() = %q, want %q", reason, tt.wantReason)
+			}
+		})
+	}
+
+	if _, err := NewRegistrationCheck(registrationRepo, []string{"ZZ"}); err == nil {
+		t.Error("NewRegistrationCheck() with an unknown country, want error")
+	}
+}
+
+func TestMessageProcessor_Process_UnregisteredSender(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	registrationRepo := mocks.NewMockSenderRegistrationRepository(ctrl)
+	registrationRepo.EXPECT().Get(gomock.Any(), "SHOP", "KE").
+		Return(nil, models.ErrNotFoundWithMsg("sender SHOP is not registered for KE"))
+
+	senderID := "SHOP"
+	messages := &messageStore{
+		byID: map[int64]*models.OutboundMessage{
+			1: {ID: 1, CampaignID: 1, CustomerID: 1, Status: models.MessageStatusPending, RenderedContent: "Hi Alice"},
+		},
+	}
+	campaigns := &campaignStore{
+		byID: map[int64]*models.CampaignWithStats{
+			1: {ID: 1, Channel: "sms", Status: "sending", SenderID: &senderID, Stats: models.CampaignStats{Total: 1}},
+		},
+	}
+	customers := &customerStore{
+		byID: map[int64]*models.Customer{
+			1: {ID: 1, Phone: "+254712345001", FirstName: "Alice"},
+		},
+	}
+	sender := &testMockSender{}
+
+	check, err := NewRegistrationCheck(registrationRepo, []string{"KE"})
+	if err != nil {
+		t.Fatalf("NewRegistrationCheck() error = %v", err)
+	}
+	processor := NewMessageProcessor(messages.repo(t), campaigns.repo(t), customers.repo(t), sender, nil, 3, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+	processor.SetRegistrationCheck(check)
+
+	if err := processor.Process(context.Background(), &models.MessageJob{OutboundMessageID: 1}); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	if len(sender.calls) != 0 {
+		t.Errorf("sender called %d times, want no send from an unregistered sender", len(sender.calls))
+	}
+	if message := messages.byID[1]; message.Status != models.MessageStatusFailed || message.LastError == nil || *message.LastError != "sender SHOP is not registered for KE" {
+		t.Errorf("message = %s %v, want failed for the unregistered sender", message.Status, message.LastError)
+	}
+}
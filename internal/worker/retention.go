@@ -0,0 +1,173 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// Content redaction and change log pruning tuning
+const (
+	redactionInterval  = time.Hour
+	redactionBatchSize = 5000
+)
+
+// ContentRedactor clears the rendered content of sent and failed messages once
+// they are older than the retention period. Message rows, and so delivery
+// records and campaign stats, are kept.
+type ContentRedactor struct {
+	messageRepo repository.OutboundMessageRepository
+	now         func() time.Time
+	logger      *slog.Logger
+
+	mu        sync.Mutex
+	retention time.Duration
+}
+
+// NewContentRedactor creates a new content redactor. A retention of 0 disables redaction.
+func NewContentRedactor(messageRepo repository.OutboundMessageRepository, retention time.Duration, logger *slog.Logger) *ContentRedactor {
+	return &ContentRedactor{
+		messageRepo: messageRepo,
+		retention:   retention,
+		now:         time.Now,
+		logger:      logger,
+	}
+}
+
+// SetRetention changes how long message content is kept
+func (r *ContentRedactor) SetRetention(retention time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.retention = retention
+}
+
+// Run redacts expired content at start-up and then every hour until ctx is done
+func (r *ContentRedactor) Run(ctx context.Context) {
+	ticker := time.NewTicker(redactionInterval)
+	defer ticker.Stop()
+
+	for {
+		r.redact(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// redact clears expired content in batches until none is left, so a large
+// backlog does not hold row locks in one long transaction
+func (r *ContentRedactor) redact(ctx context.Context) {
+	r.mu.Lock()
+	retention := r.retention
+	r.mu.Unlock()
+
+	if retention <= 0 {
+		return
+	}
+
+	cutoff := r.now().Add(-retention)
+
+	var total int64
+	for ctx.Err() == nil {
+		redacted, err := r.messageRepo.RedactContentBefore(ctx, cutoff, redactionBatchSize)
+		if err != nil {
+			r.logger.Error("failed to redact message content", slog.String("error", err.Error()))
+			break
+		}
+		total += redacted
+		if redacted < redactionBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		r.logger.Info("message content redacted",
+			slog.Int64("messages", total),
+			slog.Time("cutoff", cutoff),
+		)
+	}
+}
+
+// ChangeLogPruner deletes change log records once they are older than the
+// retention period. Consumers that fall further behind must resync from a
+// full export.
+type ChangeLogPruner struct {
+	changeRepo repository.ChangeLogRepository
+	now        func() time.Time
+	logger     *slog.Logger
+
+	mu        sync.Mutex
+	retention time.Duration
+}
+
+// NewChangeLogPruner creates a new change log pruner. A retention of 0 disables pruning.
+func NewChangeLogPruner(changeRepo repository.ChangeLogRepository, retention time.Duration, logger *slog.Logger) *ChangeLogPruner {
+	return &ChangeLogPruner{
+		changeRepo: changeRepo,
+		retention:  retention,
+		now:        time.Now,
+		logger:     logger,
+	}
+}
+
+// SetRetention changes how long change records are kept
+func (p *ChangeLogPruner) SetRetention(retention time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.retention = retention
+}
+
+// Run prunes expired records at start-up and then every hour until ctx is done
+func (p *ChangeLogPruner) Run(ctx context.Context) {
+	ticker := time.NewTicker(redactionInterval)
+	defer ticker.Stop()
+
+	for {
+		p.prune(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// prune deletes expired records in batches until none is left
+func (p *ChangeLogPruner) prune(ctx context.Context) {
+	p.mu.Lock()
+	retention := p.retention
+	p.mu.Unlock()
+
+	if retention <= 0 {
+		return
+	}
+
+	cutoff := p.now().Add(-retention)
+
+	var total int64
+	for ctx.Err() == nil {
+		deleted, err := p.changeRepo.DeleteBefore(ctx, cutoff, redactionBatchSize)
+		if err != nil {
+			p.logger.Error("failed to prune change log", slog.String("error", err.Error()))
+			break
+		}
+		total += deleted
+		if deleted < redactionBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		p.logger.Info("change log pruned",
+			slog.Int64("records", total),
+			slog.Time("cutoff", cutoff),
+		)
+	}
+}
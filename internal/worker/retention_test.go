@@ -0,0 +1,103 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// redactableMessages is a fixed number of redactable messages
+type redactableMessages struct {
+	remaining int64
+	cutoffs   []time.Time
+}
+
+// repo returns a mock repository redacting the messages
+func (m *redactableMessages) repo(t *testing.T) *mocks.MockOutboundMessageRepository {
+	repo := mocks.NewMockOutboundMessageRepository(gomock.NewController(t))
+	repo.EXPECT().RedactContentBefore(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(m.redact).AnyTimes()
+	return repo
+}
+
+func (m *redactableMessages) redact(ctx context.Context, before time.Time, limit int) (int64, error) {
+	m.cutoffs = append(m.cutoffs, before)
+	redacted := min(m.remaining, int64(limit))
+	m.remaining -= redacted
+	return redacted, nil
+}
+
+func TestContentRedactor_RedactsInBatches(t *testing.T) {
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	messages := &redactableMessages{remaining: 2*redactionBatchSize + 10}
+
+	redactor := NewContentRedactor(messages.repo(t), 30*24*time.Hour, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+	redactor.now = func() time.Time { return now }
+	redactor.redact(context.Background())
+
+	if messages.remaining != 0 {
+		t.Errorf("remaining = %d, want every expired message redacted", messages.remaining)
+	}
+	if len(messages.cutoffs) != 3 {
+		t.Fatalf("batches = %d, want 3", len(messages.cutoffs))
+	}
+	if want := now.AddDate(0, 0, -30); !messages.cutoffs[0].Equal(want) {
+		t.Errorf("cutoff = %v, want %v", messages.cutoffs[0], want)
+	}
+}
+
+func TestContentRedactor_DisabledWithoutRetention(t *testing.T) {
+	messages := &redactableMessages{remaining: 10}
+
+	redactor := NewContentRedactor(messages.repo(t), 0, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+	redactor.redact(context.Background())
+
+	if len(messages.cutoffs) != 0 {
+		t.Errorf("batches = %d, want none while retention is disabled", len(messages.cutoffs))
+	}
+}
+
+// prunableChangeLog reports a fixed number of expired change records
+type prunableChangeLog struct {
+	remaining int64
+	cutoffs   []time.Time
+}
+
+func (m *prunableChangeLog) ListAfter(ctx context.Context, afterID int64, entity string, settle time.Duration, limit int) ([]*models.ChangeRecord, error) {
+	return nil, nil
+}
+
+func (m *prunableChangeLog) DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	m.cutoffs = append(m.cutoffs, before)
+	deleted := min(m.remaining, int64(limit))
+	m.remaining -= deleted
+	return deleted, nil
+}
+
+func TestChangeLogPruner_PrunesInBatches(t *testing.T) {
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	repo := &prunableChangeLog{remaining: redactionBatchSize + 1}
+
+	pruner := NewChangeLogPruner(repo, 7*24*time.Hour, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+	pruner.now = func() time.Time { return now }
+	pruner.prune(context.Background())
+
+	if repo.remaining != 0 || len(repo.cutoffs) != 2 {
+		t.Fatalf("remaining = %d after %d batches, want 0 after 2", repo.remaining, len(repo.cutoffs))
+	}
+	if want := now.AddDate(0, 0, -7); !repo.cutoffs[0].Equal(want) {
+		t.Errorf("cutoff = %v, want %v", repo.cutoffs[0], want)
+	}
+
+	pruner.SetRetention(0)
+	pruner.prune(context.Background())
+	if len(repo.cutoffs) != 2 {
+		t.Errorf("batches = %d, want no more once retention is disabled", len(repo.cutoffs))
+	}
+}
@@ -0,0 +1,258 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// Scheduled dispatch tuning
+const (
+	// schedulerBatchSize is the most campaigns one replica claims per poll
+	schedulerBatchSize = 10
+	// dispatchLease is how long a claim keeps other replicas away from a
+	// campaign. A dispatch that fails without starting is retried once it lapses.
+	dispatchLease = 10 * time.Minute
+	// schedulerIdlePoll is how often a disabled scheduler checks whether it
+	// has been re-enabled by a config reload
+	schedulerIdlePoll = 30 * time.Second
+)
+
+// AlertTypeScheduledDispatchFailed is raised when a due campaign cannot be sent
+const AlertTypeScheduledDispatchFailed = "scheduled_dispatch_failed"
+
+// DispatchFunc sends a campaign to its bound audience and returns the number of
+// messages queued. Campaign sending lives in the service package, which the
+// worker does not import, so the binary supplies it.
+type DispatchFunc func(ctx context.Context, campaignID int64) (int, error)
+
+// Scheduler sends scheduled campaigns once their scheduled_at has passed.
+// Only campaigns with a bound audience are dispatched; the rest still wait for
+// a manual POST /api/campaigns/{id}/send. Campaigns with prebuild_minutes are
+// built that long ahead and left ready, and their dispatch at scheduled_at
+// only queues the built messages. Campaigns paused outside their delivery
+// windows are resumed once their resume_at has passed. Replicas claim
+// campaigns before handling them, so each campaign is dispatched by one worker.
+type Scheduler struct {
+	campaignRepo repository.CampaignRepository
+	messageRepo  repository.OutboundMessageRepository
+	dispatch     DispatchFunc
+	prebuild     DispatchFunc
+	resume       DispatchFunc
+	alerter      Alerter
+	now          func() time.Time
+	logger       *slog.Logger
+
+	mu       sync.Mutex
+	interval time.Duration
+}
+
+// NewScheduler creates a new scheduler. prebuild builds a campaign's messages
+// without queueing them and returns how many it built; resume resumes a paused
+// campaign and returns how many messages it requeued. An interval of 0
+// disables dispatch.
+func NewScheduler(
+	campaignRepo repository.CampaignRepository,
+	messageRepo repository.OutboundMessageRepository,
+	dispatch DispatchFunc,
+	prebuild DispatchFunc,
+	resume DispatchFunc,
+	alerter Alerter,
+	interval time.Duration,
+	logger *slog.Logger,
+) *Scheduler {
+	return &Scheduler{
+		campaignRepo: campaignRepo,
+		messageRepo:  messageRepo,
+		dispatch:     dispatch,
+		prebuild:     prebuild,
+		resume:       resume,
+		alerter:      alerter,
+		interval:     interval,
+		now:          time.Now,
+		logger:       logger,
+	}
+}
+
+// SetInterval changes how often the scheduler polls for due campaigns
+func (s *Scheduler) SetInterval(interval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.interval = interval
+}
+
+// Run dispatches due campaigns at start-up and then every interval until ctx is done
+func (s *Scheduler) Run(ctx context.Context) {
+	for {
+		s.mu.Lock()
+		interval := s.interval
+		s.mu.Unlock()
+
+		wait := interval
+		if interval > 0 {
+			s.poll(ctx)
+		} else {
+			wait = schedulerIdlePoll
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// poll claims due campaigns until none are left, dispatching each in turn
+func (s *Scheduler) poll(ctx context.Context) {
+	for ctx.Err() == nil {
+		// Stored timestamps carry no zone and are written in UTC
+		now := s.now().UTC()
+
+		ids, err := s.campaignRepo.ClaimDueCampaigns(ctx, now, now.Add(-dispatchLease), schedulerBatchSize)
+		if err != nil {
+			s.logger.Error("failed to claim due campaigns", slog.String("error", err.Error()))
+			return
+		}
+
+		for _, id := range ids {
+			s.dispatchCampaign(ctx, id)
+		}
+
+		if len(ids) < schedulerBatchSize {
+			return
+		}
+	}
+}
+
+// dispatchCampaign sends one claimed campaign, or builds it when it was
+// claimed ahead of its schedule. A campaign that already has messages was
+// partly built by a worker that stopped mid-dispatch; it is moved on to
+// sending, or to ready while not yet due, instead of being built again, which
+// would message its audience twice.
+func (s *Scheduler) dispatchCampaign(ctx context.Context, campaignID int64) {
+	campaign, err := s.campaignRepo.GetByID(ctx, campaignID)
+	if err != nil {
+		s.logger.Error("failed to fetch scheduled campaign",
+			slog.Int64("campaign_id", campaignID),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	// A ready campaign was built ahead; sending it only queues its messages
+	if campaign.Status == models.CampaignStatusReady {
+		s.run(ctx, campaignID, s.dispatch, "scheduled campaign dispatched", "messages_queued")
+		return
+	}
+
+	// A campaign paused outside its delivery windows carries on where it stopped
+	if campaign.Status == models.CampaignStatusPaused {
+		s.run(ctx, campaignID, s.resume, "paused campaign resumed", "messages_requeued")
+		return
+	}
+
+	early := campaign.ScheduledAt != nil && campaign.ScheduledAt.After(s.now())
+
+	existing, err := s.messageRepo.CountByCampaign(ctx, campaignID)
+	if err != nil {
+		s.logger.Error("failed to check scheduled campaign messages",
+			slog.Int64("campaign_id", campaignID),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	if existing > 0 {
+		status := models.CampaignStatusSending
+		if early {
+			status = models.CampaignStatusReady
+		}
+		s.logger.Warn("scheduled campaign already has messages, moving it on",
+			slog.Int64("campaign_id", campaignID),
+			slog.Int64("messages", existing),
+			slog.String("status", status),
+		)
+		// Unless a send claimed the campaign since it was read
+		if _, err := s.campaignRepo.TransitionStatus(ctx, campaignID, models.CampaignStatusScheduled, status); err != nil {
+			s.logger.Error("failed to update campaign status",
+				slog.Int64("campaign_id", campaignID),
+				slog.String("error", err.Error()),
+			)
+		}
+		return
+	}
+
+	if early {
+		s.run(ctx, campaignID, s.prebuild, "scheduled campaign built ahead", "messages_built")
+		return
+	}
+
+	s.run(ctx, campaignID, s.dispatch, "scheduled campaign dispatched", "messages_queued")
+}
+
+// run hands a claimed campaign to fn and deals with its failure
+func (s *Scheduler) run(ctx context.Context, campaignID int64, fn DispatchFunc, done, countKey string) {
+	count, err := fn(ctx, campaignID)
+	if err == nil {
+		s.logger.Info(done,
+			slog.Int64("campaign_id", campaignID),
+			slog.Int(countKey, count),
+		)
+		return
+	}
+
+	var appErr *models.AppError
+	switch {
+	case errors.Is(err, models.ErrConflict), errors.Is(err, models.ErrNotFound):
+		// Sent or deleted since it was claimed
+		s.logger.Info("scheduled campaign no longer due",
+			slog.Int64("campaign_id", campaignID),
+			slog.String("reason", err.Error()),
+		)
+	case errors.As(err, &appErr) && appErr.Code == "INVALID_INPUT":
+		// Retrying cannot help, e.g. the bound audience is empty
+		s.stop(ctx, campaignID, models.CampaignStatusFailed, appErr.Message)
+	case errors.As(err, &appErr) && appErr.Code == "CONFIRMATION_REQUIRED":
+		// Only a person can confirm a large audience; back to draft so they
+		// can send it manually
+		s.stop(ctx, campaignID, models.CampaignStatusDraft, appErr.Message)
+	default:
+		// Transient; the claim lapses and a later poll retries
+		s.logger.Error("failed to dispatch scheduled campaign",
+			slog.Int64("campaign_id", campaignID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// stop moves a campaign that cannot be dispatched out of the scheduled status
+// and raises an alert. A campaign that is no longer scheduled, e.g. because an
+// API send claimed it meanwhile, is left alone.
+func (s *Scheduler) stop(ctx context.Context, campaignID int64, status, reason string) {
+	stopped, err := s.campaignRepo.TransitionStatus(ctx, campaignID, models.CampaignStatusScheduled, status)
+	if err != nil {
+		s.logger.Error("failed to update campaign status",
+			slog.Int64("campaign_id", campaignID),
+			slog.String("error", err.Error()),
+		)
+	}
+	if !stopped {
+		return
+	}
+
+	err = s.alerter.Alert(ctx, Alert{
+		Type:        AlertTypeScheduledDispatchFailed,
+		Message:     fmt.Sprintf("scheduled campaign could not be sent and is now %s: %s", status, reason),
+		CampaignIDs: []int64{campaignID},
+		At:          s.now(),
+	})
+	if err != nil {
+		s.logger.Error("failed to send alert", slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,211 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// claimingCampaignRepo returns a mock repository over the campaigns that
+// hands out a fixed set of due campaigns once
+func claimingCampaignRepo(t *testing.T, campaigns *campaignStore, due []int64) *mocks.MockCampaignRepository {
+	repo := campaigns.repo(t)
+	repo.EXPECT().ClaimDueCampaigns(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(due, nil)
+	repo.EXPECT().ClaimDueCampaigns(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	return repo
+}
+
+// countingMessageRepo returns a mock repository reporting a fixed message
+// count per campaign
+func countingMessageRepo(t *testing.T, counts map[int64]int64) *mocks.MockOutboundMessageRepository {
+	repo := mocks.NewMockOutboundMessageRepository(gomock.NewController(t))
+	repo.EXPECT().CountByCampaign(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, campaignID int64) (int64, error) {
+		return counts[campaignID], nil
+	}).AnyTimes()
+	return repo
+}
+
+func TestScheduler_DispatchesDueCampaigns(t *testing.T) {
+	campaigns := &campaignStore{
+		byID: map[int64]*models.CampaignWithStats{
+			1: {ID: 1, Status: models.CampaignStatusScheduled},
+			2: {ID: 2, Status: models.CampaignStatusScheduled},
+			3: {ID: 3, Status: models.CampaignStatusScheduled},
+			4: {ID: 4, Status: models.CampaignStatusScheduled},
+			5: {ID: 5, Status: models.CampaignStatusScheduled},
+			6: {ID: 6, Status: models.CampaignStatusScheduled},
+		},
+	}
+	campaignRepo := claimingCampaignRepo(t, campaigns, []int64{1, 2, 3, 4, 5, 6})
+	// Campaign 2 was partly built by a worker that stopped mid-dispatch
+	messageRepo := countingMessageRepo(t, map[int64]int64{2: 40})
+	alerter := &recordingAlerter{}
+
+	dispatched := []int64{}
+	dispatch := func(ctx context.Context, campaignID int64) (int, error) {
+		dispatched = append(dispatched, campaignID)
+		switch campaignID {
+		case 3:
+			return 0, models.ErrInvalidInput("no valid customers found to send messages")
+		case 4:
+			return 0, errors.New("connection reset")
+		case 5:
+			return 0, models.ErrConflictWithMsg("campaign already processed")
+		case 6:
+			return 0, models.ErrConfirmationRequired("campaign would send to 20000 recipients")
+		}
+		campaigns.byID[campaignID].Status = models.CampaignStatusSending
+		return 10, nil
+	}
+
+	prebuild := func(ctx context.Context, campaignID int64) (int, error) {
+		t.Errorf("campaign %d built ahead, want it dispatched", campaignID)
+		return 0, nil
+	}
+
+	resume := func(ctx context.Context, campaignID int64) (int, error) {
+		t.Errorf("campaign %d resumed, want it dispatched", campaignID)
+		return 0, nil
+	}
+
+	scheduler := NewScheduler(campaignRepo, messageRepo, dispatch, prebuild, resume, alerter, 30*time.Second, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+	scheduler.poll(context.Background())
+
+	if want := []int64{1, 3, 4, 5, 6}; !slices.Equal(dispatched, want) {
+		t.Fatalf("dispatched = %v, want %v", dispatched, want)
+	}
+
+	wantStatus := map[int64]string{
+		1: models.CampaignStatusSending,
+		2: models.CampaignStatusSending,
+		3: models.CampaignStatusFailed,
+		4: models.CampaignStatusScheduled,
+		5: models.CampaignStatusScheduled,
+		6: models.CampaignStatusDraft,
+	}
+	for id, want := range wantStatus {
+		if got := campaigns.byID[id].Status; got != want {
+			t.Errorf("campaign %d status = %s, want %s", id, got, want)
+		}
+	}
+
+	if len(alerter.alerts) != 2 {
+		t.Fatalf("alerts = %+v, want two %s alerts", alerter.alerts, AlertTypeScheduledDispatchFailed)
+	}
+	for i, want := range []int64{3, 6} {
+		alert := alerter.alerts[i]
+		if alert.Type != AlertTypeScheduledDispatchFailed || len(alert.CampaignIDs) != 1 || alert.CampaignIDs[0] != want {
+			t.Errorf("alert %d = %+v, want %s for campaign %d", i, alert, AlertTypeScheduledDispatchFailed, want)
+		}
+	}
+}
+
+func TestScheduler_PrebuildsCampaignsAhead(t *testing.T) {
+	now := time.Date(2026, 10, 1, 9, 50, 0, 0, time.UTC)
+	at := now.Add(10 * time.Minute)
+	due := now.Add(-time.Second)
+
+	campaigns := &campaignStore{
+		byID: map[int64]*models.CampaignWithStats{
+			1: {ID: 1, Status: models.CampaignStatusScheduled, ScheduledAt: &at},
+			2: {ID: 2, Status: models.CampaignStatusScheduled, ScheduledAt: &at},
+			3: {ID: 3, Status: models.CampaignStatusReady, ScheduledAt: &due},
+		},
+	}
+	campaignRepo := claimingCampaignRepo(t, campaigns, []int64{1, 2, 3})
+	// Campaign 2 was partly built ahead by a worker that stopped
+	messageRepo := countingMessageRepo(t, map[int64]int64{2: 40, 3: 25})
+
+	built, dispatched := []int64{}, []int64{}
+	prebuild := func(ctx context.Context, campaignID int64) (int, error) {
+		built = append(built, campaignID)
+		campaigns.byID[campaignID].Status = models.CampaignStatusReady
+		return 25, nil
+	}
+	dispatch := func(ctx context.Context, campaignID int64) (int, error) {
+		dispatched = append(dispatched, campaignID)
+		campaigns.byID[campaignID].Status = models.CampaignStatusSending
+		return 25, nil
+	}
+
+	resume := func(ctx context.Context, campaignID int64) (int, error) {
+		t.Errorf("campaign %d resumed, want it built or dispatched", campaignID)
+		return 0, nil
+	}
+
+	scheduler := NewScheduler(campaignRepo, messageRepo, dispatch, prebuild, resume, &recordingAlerter{}, 30*time.Second, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+	scheduler.now = func() time.Time { return now }
+	scheduler.poll(context.Background())
+
+	if want := []int64{1}; !slices.Equal(built, want) {
+		t.Errorf("built ahead = %v, want %v", built, want)
+	}
+	// A ready campaign is dispatched even though it has messages
+	if want := []int64{3}; !slices.Equal(dispatched, want) {
+		t.Errorf("dispatched = %v, want %v", dispatched, want)
+	}
+
+	wantStatus := map[int64]string{
+		1: models.CampaignStatusReady,
+		2: models.CampaignStatusReady,
+		3: models.CampaignStatusSending,
+	}
+	for id, want := range wantStatus {
+		if got := campaigns.byID[id].Status; got != want {
+			t.Errorf("campaign %d status = %s, want %s", id, got, want)
+		}
+	}
+}
+
+func TestScheduler_ResumesCampaignsInNextWindow(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 30, 0, time.UTC)
+	resumeAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	reason := "outside delivery windows"
+
+	campaigns := &campaignStore{
+		byID: map[int64]*models.CampaignWithStats{
+			1: {ID: 1, Status: models.CampaignStatusPaused, PausedReason: &reason, ResumeAt: &resumeAt},
+		},
+	}
+	campaignRepo := claimingCampaignRepo(t, campaigns, []int64{1})
+	// The campaign was part sent before its window closed
+	messageRepo := countingMessageRepo(t, map[int64]int64{1: 500})
+
+	resumed := []int64{}
+	resume := func(ctx context.Context, campaignID int64) (int, error) {
+		resumed = append(resumed, campaignID)
+		campaign := campaigns.byID[campaignID]
+		campaign.Status = models.CampaignStatusSending
+		campaign.PausedReason = nil
+		campaign.ResumeAt = nil
+		return 200, nil
+	}
+	dispatch := func(ctx context.Context, campaignID int64) (int, error) {
+		t.Errorf("campaign %d dispatched, want it resumed", campaignID)
+		return 0, nil
+	}
+	prebuild := func(ctx context.Context, campaignID int64) (int, error) {
+		t.Errorf("campaign %d built ahead, want it resumed", campaignID)
+		return 0, nil
+	}
+
+	scheduler := NewScheduler(campaignRepo, messageRepo, dispatch, prebuild, resume, &recordingAlerter{}, 30*time.Second, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+	scheduler.now = func() time.Time { return now }
+	scheduler.poll(context.Background())
+
+	if want := []int64{1}; !slices.Equal(resumed, want) {
+		t.Errorf("resumed = %v, want %v", resumed, want)
+	}
+	if got := campaigns.byID[1].Status; got != models.CampaignStatusSending {
+		t.Errorf("campaign status = %s, want sending", got)
+	}
+}
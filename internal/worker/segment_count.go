@@ -0,0 +1,110 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// segmentCountBatchSize is how many stale segments are read at a time
+const segmentCountBatchSize = 100
+
+// SegmentCounter recounts the customers of segments whose cached count is
+// older than its interval, so segment pages show a recent count without
+// counting on every read
+type SegmentCounter struct {
+	segmentRepo  repository.SegmentRepository
+	customerRepo repository.CustomerRepository
+	interval     time.Duration
+	now          func() time.Time
+	logger       *slog.Logger
+}
+
+// NewSegmentCounter creates a new segment counter. An interval of 0 disables
+// scheduled recounts.
+func NewSegmentCounter(segmentRepo repository.SegmentRepository, customerRepo repository.CustomerRepository, interval time.Duration, logger *slog.Logger) *SegmentCounter {
+	return &SegmentCounter{
+		segmentRepo:  segmentRepo,
+		customerRepo: customerRepo,
+		interval:     interval,
+		now:          time.Now,
+		logger:       logger,
+	}
+}
+
+// Run recounts stale segments at start-up and then every interval until ctx
+// is done
+func (c *SegmentCounter) Run(ctx context.Context) {
+	if c.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		c.recount(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// recount counts every segment of every account last counted over an
+// interval ago. A segment that fails to count is left for the next run.
+func (c *SegmentCounter) recount(ctx context.Context) {
+	now := c.now().UTC()
+	cutoff := now.Add(-c.interval)
+
+	var total int
+	for ctx.Err() == nil {
+		segments, err := c.segmentRepo.ListStaleCounts(ctx, cutoff, segmentCountBatchSize)
+		if err != nil {
+			c.logger.Error("failed to list stale segment counts", slog.String("error", err.Error()))
+			return
+		}
+
+		counted := 0
+		for _, segment := range segments {
+			if c.count(ctx, segment, now) {
+				counted++
+			}
+		}
+		total += counted
+
+		// Stop when the batch was the last, or when nothing in it could be
+		// counted and the same segments would be listed again
+		if len(segments) < segmentCountBatchSize || counted == 0 {
+			break
+		}
+	}
+
+	if total > 0 {
+		c.logger.Info("segments recounted", slog.Int("segments", total))
+	}
+}
+
+// count counts one segment in its own account and caches the count
+func (c *SegmentCounter) count(ctx context.Context, segment *models.Segment, now time.Time) bool {
+	ctx = models.WithAccountID(ctx, segment.AccountID)
+
+	customers, err := c.customerRepo.CountMatching(ctx, segment.Filter)
+	if err == nil {
+		err = c.segmentRepo.SaveCount(ctx, &models.SegmentCount{SegmentID: segment.ID, CustomerCount: customers, CountedAt: now})
+	}
+	if err != nil {
+		c.logger.Error("failed to recount segment",
+			slog.Int64("segment_id", segment.ID),
+			slog.String("error", err.Error()),
+		)
+		return false
+	}
+
+	return true
+}
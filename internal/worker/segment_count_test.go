@@ -0,0 +1,52 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestSegmentCounter_Recount(t *testing.T) {
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	ctrl := gomock.NewController(t)
+	segmentRepo := mocks.NewMockSegmentRepository(ctrl)
+	customerRepo := mocks.NewMockCustomerRepository(ctrl)
+
+	nairobi := models.SegmentFilter{Location: "Nairobi"}
+	shoes := models.SegmentFilter{PreferredProduct: "Shoes"}
+	segmentRepo.EXPECT().ListStaleCounts(gomock.Any(), now.Add(-time.Hour), segmentCountBatchSize).
+		Return([]*models.Segment{{ID: 1, AccountID: 2, Filter: nairobi}, {ID: 2, AccountID: 3, Filter: shoes}}, nil)
+
+	// Each segment is counted in its own account
+	customerRepo.EXPECT().CountMatching(gomock.Any(), nairobi).
+		DoAndReturn(func(ctx context.Context, filter models.SegmentFilter) (int64, error) {
+			if account := models.AccountIDFromContext(ctx); account != 2 {
+				t.Errorf("CountMatching() in account %d, want 2", account)
+			}
+			return 12, nil
+		})
+	segmentRepo.EXPECT().SaveCount(gomock.Any(), &models.SegmentCount{SegmentID: 1, CustomerCount: 12, CountedAt: now}).Return(nil)
+	// A segment that fails to count does not stop the others
+	customerRepo.EXPECT().CountMatching(gomock.Any(), shoes).Return(int64(0), errors.New("statement timeout"))
+
+	counter := NewSegmentCounter(segmentRepo, customerRepo, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	counter.now = func() time.Time { return now }
+	counter.recount(context.Background())
+}
+
+func TestSegmentCounter_DisabledWithoutInterval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	counter := NewSegmentCounter(mocks.NewMockSegmentRepository(ctrl), mocks.NewMockCustomerRepository(ctrl), 0, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	// Returns at once, without listing segments
+	counter.Run(context.Background())
+}
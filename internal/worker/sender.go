@@ -5,11 +5,15 @@ import (
 	"fmt"
 	"math/rand"
 	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/ratelimit"
 )
 
-// MessageSender defines the interface for sending messages
+// MessageSender defines the interface for sending messages. Send returns the
+// provider's ID for the message, which delivery reports refer to; it is empty
+// when the provider assigns none.
 type MessageSender interface {
-	Send(ctx context.Context, channel, phone, content string) error
+	Send(ctx context.Context, channel, phone, content string) (string, error)
 }
 
 // mockSender simulates message sending with 90-95% success rate
@@ -34,7 +38,7 @@ func NewMockSender(successRate float64) MessageSender {
 }
 
 // Send simulates sending a message
-func (s *mockSender) Send(ctx context.Context, channel, phone, content string) error {
+func (s *mockSender) Send(ctx context.Context, channel, phone, content string) (string, error) {
 	// Simulate network delay
 	delay := s.minDelay + time.Duration(rand.Int63n(int64(s.maxDelay-s.minDelay)))
 
@@ -42,14 +46,59 @@ func (s *mockSender) Send(ctx context.Context, channel, phone, content string) e
 	case <-time.After(delay):
 		// Continue
 	case <-ctx.Done():
-		return ctx.Err()
+		return "", ctx.Err()
 	}
 
 	// Randomly fail based on success rate
 	if rand.Float64() > s.successRate {
-		return fmt.Errorf("mock sender failed: simulated network error")
+		return "", fmt.Errorf("mock sender failed: simulated network error")
 	}
 
 	// Success
-	return nil
+	return fmt.Sprintf("mock-%016x", rand.Uint64()), nil
+}
+
+// GlobalRateKey is the limiter key every send waits on in addition to its
+// channel, capping the combined rate of all channels
+const GlobalRateKey = "global"
+
+// SendRates returns the limiter rates for per-channel limits and a global
+// cap; a maxRPS of 0 leaves the combined rate uncapped
+func SendRates(channelLimits map[string]float64, maxRPS float64) map[string]float64 {
+	rates := make(map[string]float64, len(channelLimits)+1)
+	for channel, rate := range channelLimits {
+		rates[channel] = rate
+	}
+	if maxRPS > 0 {
+		rates[GlobalRateKey] = maxRPS
+	}
+	return rates
+}
+
+// rateLimitedSender waits on a shared limiter, keyed by channel, before each send
+type rateLimitedSender struct {
+	sender  MessageSender
+	limiter ratelimit.Limiter
+}
+
+// NewRateLimitedSender wraps sender so that sends respect the limiter's
+// per-channel rate and its global rate
+func NewRateLimitedSender(sender MessageSender, limiter ratelimit.Limiter) MessageSender {
+	return &rateLimitedSender{
+		sender:  sender,
+		limiter: limiter,
+	}
+}
+
+// Send waits for a token for the channel and then a global one, so a send
+// held up by its channel does not sit on a global token, then sends
+func (s *rateLimitedSender) Send(ctx context.Context, channel, phone, content string) (string, error) {
+	if err := s.limiter.Wait(ctx, channel); err != nil {
+		return "", fmt.Errorf("rate limiter: %w", err)
+	}
+	if err := s.limiter.Wait(ctx, GlobalRateKey); err != nil {
+		return "", fmt.Errorf("rate limiter: %w", err)
+	}
+
+	return s.sender.Send(ctx, channel, phone, content)
 }
@@ -0,0 +1,98 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// shortDomainCheckBatchSize is how many short domains due for a check are
+// read at a time
+const shortDomainCheckBatchSize = 50
+
+// ShortDomainChecker runs the health check of every enabled short domain once
+// per interval, so campaigns move off a domain soon after carriers start
+// blocking it
+type ShortDomainChecker struct {
+	shortDomainRepo repository.ShortDomainRepository
+	check           func(ctx context.Context, domainID int64) error
+	interval        time.Duration
+	now             func() time.Time
+	logger          *slog.Logger
+}
+
+// NewShortDomainChecker creates a new short domain checker. check runs and
+// records the health check of one domain. An interval of 0 disables
+// scheduled checks.
+func NewShortDomainChecker(shortDomainRepo repository.ShortDomainRepository, check func(ctx context.Context, domainID int64) error, interval time.Duration, logger *slog.Logger) *ShortDomainChecker {
+	return &ShortDomainChecker{
+		shortDomainRepo: shortDomainRepo,
+		check:           check,
+		interval:        interval,
+		now:             time.Now,
+		logger:          logger,
+	}
+}
+
+// Run checks the domains due for a check at start-up and then every interval
+// until ctx is done
+func (c *ShortDomainChecker) Run(ctx context.Context) {
+	if c.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		c.checkDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkDue checks every enabled domain of every account last checked over an
+// interval ago. A domain whose check cannot be recorded is left for the next
+// run.
+func (c *ShortDomainChecker) checkDue(ctx context.Context) {
+	cutoff := c.now().UTC().Add(-c.interval)
+
+	var total int
+	for ctx.Err() == nil {
+		domains, err := c.shortDomainRepo.ListDueForCheck(ctx, cutoff, shortDomainCheckBatchSize)
+		if err != nil {
+			c.logger.Error("failed to list short domains due for a check", slog.String("error", err.Error()))
+			return
+		}
+
+		checked := 0
+		for _, domain := range domains {
+			if err := c.check(models.WithAccountID(ctx, domain.AccountID), domain.ID); err != nil {
+				c.logger.Error("failed to check short domain",
+					slog.Int64("short_domain_id", domain.ID),
+					slog.String("error", err.Error()),
+				)
+				continue
+			}
+			checked++
+		}
+		total += checked
+
+		// Stop when the batch was the last, or when nothing in it could be
+		// checked and the same domains would be listed again
+		if len(domains) < shortDomainCheckBatchSize || checked == 0 {
+			break
+		}
+	}
+
+	if total > 0 {
+		c.logger.Info("short domains checked", slog.Int("domains", total))
+	}
+}
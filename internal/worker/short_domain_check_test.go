@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestShortDomainChecker_CheckDue(t *testing.T) {
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	ctrl := gomock.NewController(t)
+	shortDomainRepo := mocks.NewMockShortDomainRepository(ctrl)
+	shortDomainRepo.EXPECT().ListDueForCheck(gomock.Any(), now.Add(-15*time.Minute), shortDomainCheckBatchSize).
+		Return([]*models.ShortDomain{{ID: 1, AccountID: 2}, {ID: 2, AccountID: 3}, {ID: 3, AccountID: 3}}, nil)
+
+	// Each domain is checked in its own account, and one that fails to check
+	// does not stop the others
+	var checked []int64
+	check := func(ctx context.Context, domainID int64) error {
+		want := map[int64]int64{1: 2, 2: 3, 3: 3}[domainID]
+		if account := models.AccountIDFromContext(ctx); account != want {
+			t.Errorf("check(%d) in account %d, want %d", domainID, account, want)
+		}
+		checked = append(checked, domainID)
+		if domainID == 2 {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+
+	checker := NewShortDomainChecker(shortDomainRepo, check, 15*time.Minute, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	checker.now = func() time.Time { return now }
+	checker.checkDue(context.Background())
+
+	if len(checked) != 3 {
+		t.Errorf("checked domains %v, want all 3", checked)
+	}
+}
+
+func TestShortDomainChecker_DisabledWithoutInterval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	checker := NewShortDomainChecker(mocks.NewMockShortDomainRepository(ctrl), nil, 0, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	// Returns at once, without listing domains
+	checker.Run(context.Background())
+}
@@ -0,0 +1,79 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/ratelimit"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/repository"
+)
+
+// warmupGate enforces the daily warm-up cap of a campaign's sender ID.
+// Messages over today's cap are deferred to the start of the next UTC day.
+type warmupGate struct {
+	warmupRepo repository.SenderWarmupRepository
+	quota      ratelimit.Quota
+	now        func() time.Time
+	logger     *slog.Logger
+}
+
+// NewWarmupGate creates a send gate enforcing sender warm-up ramps
+func NewWarmupGate(warmupRepo repository.SenderWarmupRepository, quota ratelimit.Quota, logger *slog.Logger) SendGate {
+	return &warmupGate{
+		warmupRepo: warmupRepo,
+		quota:      quota,
+		now:        time.Now,
+		logger:     logger,
+	}
+}
+
+// Check takes one unit of the sender's daily cap, or defers to the next day
+func (g *warmupGate) Check(ctx context.Context, campaign *models.Campaign, message *models.OutboundMessage) (time.Time, error) {
+	// Sandbox sends never reach a carrier, so they do not use up the cap
+	if campaign.SenderID == nil || campaign.IsTest() {
+		return time.Time{}, nil
+	}
+
+	warmup, err := g.warmupRepo.GetBySenderID(ctx, *campaign.SenderID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			// Sender is not warming up
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("failed to get sender warm-up: %w", err)
+	}
+
+	now := g.now()
+	day := models.StartOfDay(now)
+	limit := warmup.DailyCap(now)
+
+	ok, err := g.quota.Take(ctx, "warmup:"+warmup.SenderID, day, 24*time.Hour, limit)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if ok {
+		return time.Time{}, nil
+	}
+
+	g.logger.Info("sender warm-up cap reached",
+		slog.String("sender_id", warmup.SenderID),
+		slog.Int("daily_cap", limit),
+		slog.Int64("message_id", message.ID),
+	)
+
+	return day.Add(24 * time.Hour), nil
+}
+
+// Refund implements QuotaGate. A sender that is not warming up has no
+// counter, so there is nothing to give back.
+func (g *warmupGate) Refund(ctx context.Context, campaign *models.Campaign, message *models.OutboundMessage) error {
+	if campaign.SenderID == nil || campaign.IsTest() {
+		return nil
+	}
+
+	return g.quota.Refund(ctx, "warmup:"+*campaign.SenderID, models.StartOfDay(g.now()))
+}
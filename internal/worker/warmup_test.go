@@ -0,0 +1,133 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/mocks"
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// memoryQuota counts quota usage in memory
+type memoryQuota struct {
+	used map[string]int
+}
+
+func (q *memoryQuota) Take(ctx context.Context, key string, windowStart time.Time, window time.Duration, limit int) (bool, error) {
+	k := key + windowStart.String()
+	if q.used[k] >= limit {
+		return false, nil
+	}
+	q.used[k]++
+	return true, nil
+}
+func (q *memoryQuota) Used(ctx context.Context, key string, windowStart time.Time) (int, error) {
+	return q.used[key+windowStart.String()], nil
+}
+func (q *memoryQuota) Refund(ctx context.Context, key string, windowStart time.Time) error {
+	if k := key + windowStart.String(); q.used[k] > 0 {
+		q.used[k]--
+	}
+	return nil
+}
+func (q *memoryQuota) Close() error { return nil }
+
+// recordingScheduler records deferred jobs
+type recordingScheduler struct {
+	deferred []time.Time
+}
+
+func (s *recordingScheduler) PublishDelayed(ctx context.Context, job *models.MessageJob, at time.Time) error {
+	s.deferred = append(s.deferred, at)
+	return nil
+}
+
+func TestSenderWarmup_DailyCap(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	warmup := &models.SenderWarmup{StartedOn: start, InitialDailyCap: 200, MaxDailyCap: 1000}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want int
+	}{
+		{name: "day 1", now: start.Add(13 * time.Hour), want: 200},
+		{name: "day 2 doubles", now: start.Add(24 * time.Hour), want: 400},
+		{name: "day 3 doubles again", now: start.Add(48 * time.Hour), want: 800},
+		{name: "day 4 capped at max", now: start.Add(72 * time.Hour), want: 1000},
+		{name: "long after start stays at max", now: start.Add(400 * 24 * time.Hour), want: 1000},
+		{name: "before start uses initial cap", now: start.Add(-48 * time.Hour), want: 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := warmup.DailyCap(tt.now); got != tt.want {
+				t.Errorf("DailyCap() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessageProcessor_Process_WarmupDefersExcess(t *testing.T) {
+	senderID := "ACME"
+	now := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+
+	messages := &messageStore{
+		byID: map[int64]*models.OutboundMessage{},
+	}
+	for id := int64(1); id <= 3; id++ {
+		messages.byID[id] = &models.OutboundMessage{ID: id, CampaignID: 1, CustomerID: 1, Status: models.MessageStatusPending}
+	}
+
+	campaigns := &campaignStore{
+		byID: map[int64]*models.CampaignWithStats{
+			1: {ID: 1, Channel: "sms", Status: models.CampaignStatusSending, SenderID: &senderID,
+				Stats: models.CampaignStats{Pending: 1}},
+		},
+	}
+	customers := &customerStore{
+		byID: map[int64]*models.Customer{1: {ID: 1, Phone: "+254712345001"}},
+	}
+
+	ctrl := gomock.NewController(t)
+	warmupRepo := mocks.NewMockSenderWarmupRepository(ctrl)
+	warmupRepo.EXPECT().GetBySenderID(gomock.Any(), senderID).
+		Return(&models.SenderWarmup{SenderID: senderID, StartedOn: now, InitialDailyCap: 2, MaxDailyCap: 100}, nil).
+		AnyTimes()
+
+	gate := &warmupGate{
+		warmupRepo: warmupRepo,
+		quota:      &memoryQuota{used: map[string]int{}},
+		now:        func() time.Time { return now },
+		logger:     slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+	}
+
+	sender := &testMockSender{}
+	scheduler := &recordingScheduler{}
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	processor := NewMessageProcessor(messages.repo(t), campaigns.repo(t), customers.repo(t), sender, scheduler, 3, logger, gate)
+
+	for id := int64(1); id <= 3; id++ {
+		if err := processor.Process(context.Background(), &models.MessageJob{OutboundMessageID: id}); err != nil {
+			t.Fatalf("Process(%d) error = %v", id, err)
+		}
+	}
+
+	if len(sender.calls) != 2 {
+		t.Errorf("Expected 2 sends under the day-1 cap, got %d", len(sender.calls))
+	}
+	if len(scheduler.deferred) != 1 {
+		t.Fatalf("Expected 1 deferred job, got %d", len(scheduler.deferred))
+	}
+	if want := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC); !scheduler.deferred[0].Equal(want) {
+		t.Errorf("deferred until %v, want %v", scheduler.deferred[0], want)
+	}
+	if messages.byID[3].Status != models.MessageStatusPending {
+		t.Errorf("deferred message status = %s, want pending", messages.byID[3].Status)
+	}
+}
@@ -0,0 +1,161 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+// whatsAppCloudSender sends WhatsApp messages through Meta's WhatsApp Business
+// Cloud API. Without a template it sends plain text, which Meta only delivers
+// within 24 hours of the customer's last message. With a template it sends
+// that approved template with the rendered content as its single body
+// parameter, which can start a conversation.
+type whatsAppCloudSender struct {
+	baseURL       string
+	phoneNumberID string
+	accessToken   string
+	template      *whatsAppTemplate
+	client        *http.Client
+}
+
+// whatsAppTemplate is an approved message template, by name and language
+type whatsAppTemplate struct {
+	name     string
+	language string
+}
+
+// whatsAppMessage is the Cloud API payload for a text or template message
+type whatsAppMessage struct {
+	MessagingProduct string                   `json:"messaging_product"`
+	To               string                   `json:"to"`
+	Type             string                   `json:"type"`
+	Text             *whatsAppText            `json:"text,omitempty"`
+	Template         *whatsAppTemplatePayload `json:"template,omitempty"`
+}
+
+type whatsAppText struct {
+	Body string `json:"body"`
+}
+
+type whatsAppTemplatePayload struct {
+	Name       string              `json:"name"`
+	Language   whatsAppLanguage    `json:"language"`
+	Components []whatsAppComponent `json:"components"`
+}
+
+type whatsAppLanguage struct {
+	Code string `json:"code"`
+}
+
+type whatsAppComponent struct {
+	Type       string              `json:"type"`
+	Parameters []whatsAppParameter `json:"parameters"`
+}
+
+type whatsAppParameter struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// whatsAppResponse is the part of a Cloud API response we read
+type whatsAppResponse struct {
+	Messages []struct {
+		ID string `json:"id"`
+	} `json:"messages"`
+	Error *struct {
+		Message string `json:"message"`
+		Code    int    `json:"code"`
+	} `json:"error"`
+}
+
+// parseWhatsAppTemplate reads a "name:language" template setting; an empty
+// setting sends plain text
+func parseWhatsAppTemplate(value string) (*whatsAppTemplate, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	name, language, ok := strings.Cut(value, ":")
+	if !ok || name == "" || language == "" {
+		return nil, fmt.Errorf("whatsapp template must have the form name:language, e.g. campaign_message:en")
+	}
+
+	return &whatsAppTemplate{name: name, language: language}, nil
+}
+
+// payload builds the Cloud API message for a recipient
+func (s *whatsAppCloudSender) payload(phone, content string) whatsAppMessage {
+	message := whatsAppMessage{
+		MessagingProduct: "whatsapp",
+		// The Cloud API takes the number with its country code and no '+'
+		To: strings.TrimPrefix(phone, "+"),
+	}
+
+	if s.template == nil {
+		message.Type = "text"
+		message.Text = &whatsAppText{Body: content}
+		return message
+	}
+
+	message.Type = "template"
+	message.Template = &whatsAppTemplatePayload{
+		Name:     s.template.name,
+		Language: whatsAppLanguage{Code: s.template.language},
+		Components: []whatsAppComponent{{
+			Type:       "body",
+			Parameters: []whatsAppParameter{{Type: "text", Text: content}},
+		}},
+	}
+	return message
+}
+
+// Send posts the message; Meta has accepted it once it returns a message ID
+func (s *whatsAppCloudSender) Send(ctx context.Context, channel, phone, content string) (string, error) {
+	if channel != models.ChannelWhatsApp {
+		return "", fmt.Errorf("whatsapp cloud api does not send %s messages", channel)
+	}
+
+	body, err := json.Marshal(s.payload(phone, content))
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal whatsapp message: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/%s/messages", s.baseURL, url.PathEscape(s.phoneNumberID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create whatsapp request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+s.accessToken)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call whatsapp cloud api: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result whatsAppResponse
+	decodeErr := json.NewDecoder(resp.Body).Decode(&result)
+
+	if resp.StatusCode >= 300 {
+		if decodeErr == nil && result.Error != nil {
+			return "", fmt.Errorf("whatsapp cloud api returned status %d: %s (code %d)", resp.StatusCode, result.Error.Message, result.Error.Code)
+		}
+		return "", fmt.Errorf("whatsapp cloud api returned status %d", resp.StatusCode)
+	}
+	if decodeErr != nil {
+		return "", fmt.Errorf("failed to decode whatsapp response: %w", decodeErr)
+	}
+	if len(result.Messages) == 0 {
+		return "", fmt.Errorf("whatsapp cloud api accepted no message")
+	}
+
+	return result.Messages[0].ID, nil
+}
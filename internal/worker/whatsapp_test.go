@@ -0,0 +1,64 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Raymond9734/campaign-messaging-backend/internal/models"
+)
+
+func TestWhatsAppCloudSender_Send(t *testing.T) {
+	var path string
+	var message whatsAppMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":{"message":"Invalid OAuth access token.","type":"OAuthException","code":190}}`))
+			return
+		}
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&message)
+		w.Write([]byte(`{"messaging_product":"whatsapp","contacts":[{"input":"254700000001","wa_id":"254700000001"}],"messages":[{"id":"wamid.1"}]}`))
+	}))
+	defer server.Close()
+
+	sender := &whatsAppCloudSender{baseURL: server.URL, phoneNumberID: "1098", accessToken: "token", client: server.Client()}
+
+	id, err := sender.Send(context.Background(), models.ChannelWhatsApp, "+254700000001", "Hi Alice!")
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if id != "wamid.1" {
+		t.Errorf("Send() message ID = %q, want wamid.1", id)
+	}
+	if path != "/1098/messages" {
+		t.Errorf("path = %s, want /1098/messages", path)
+	}
+	if message.To != "254700000001" || message.Type != "text" || message.Text == nil || message.Text.Body != "Hi Alice!" {
+		t.Errorf("text message = %+v, want the content as text to 254700000001", message)
+	}
+
+	// With a template the content is the template's body parameter
+	sender.template = &whatsAppTemplate{name: "campaign_message", language: "en"}
+	message = whatsAppMessage{}
+	if _, err := sender.Send(context.Background(), models.ChannelWhatsApp, "+254700000001", "Hi Alice!"); err != nil {
+		t.Fatalf("Send() with template error = %v", err)
+	}
+	if message.Type != "template" || message.Template == nil || message.Template.Name != "campaign_message" ||
+		message.Template.Components[0].Parameters[0].Text != "Hi Alice!" {
+		t.Errorf("template message = %+v, want campaign_message with the content as body parameter", message)
+	}
+
+	sender.accessToken = "expired"
+	if _, err := sender.Send(context.Background(), models.ChannelWhatsApp, "+254700000001", "Hi Alice!"); err == nil || !strings.Contains(err.Error(), "code 190") {
+		t.Errorf("Send() with an expired token error = %v, want code 190", err)
+	}
+
+	if _, err := sender.Send(context.Background(), models.ChannelSMS, "+254700000001", "Hi Alice!"); err == nil {
+		t.Error("Send() on sms succeeded, want an error")
+	}
+}
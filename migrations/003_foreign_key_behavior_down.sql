@@ -0,0 +1,18 @@
+-- CampaignManager System - Rollback Foreign Key Behavior
+-- Restores the original ON DELETE CASCADE constraints on outbound_messages
+
+ALTER TABLE outbound_messages
+    DROP CONSTRAINT IF EXISTS outbound_messages_campaign_id_fkey;
+
+ALTER TABLE outbound_messages
+    ADD CONSTRAINT outbound_messages_campaign_id_fkey
+    FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE CASCADE;
+
+ALTER TABLE outbound_messages
+    DROP CONSTRAINT IF EXISTS outbound_messages_customer_id_fkey;
+
+ALTER TABLE outbound_messages
+    ADD CONSTRAINT outbound_messages_customer_id_fkey
+    FOREIGN KEY (customer_id) REFERENCES customers(id) ON DELETE CASCADE;
+
+DELETE FROM schema_version WHERE version = 3;
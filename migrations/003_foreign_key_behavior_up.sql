@@ -0,0 +1,29 @@
+-- CampaignManager System - Foreign Key Behavior
+-- Replaces the implicit ON DELETE CASCADE on outbound_messages with RESTRICT so
+-- message history can never be removed as a side effect of deleting a campaign
+-- or customer. Deletes with history must go through the API's force/anonymize paths.
+
+-- ========================================
+-- outbound_messages.campaign_id -> campaigns.id
+-- ========================================
+ALTER TABLE outbound_messages
+    DROP CONSTRAINT IF EXISTS outbound_messages_campaign_id_fkey;
+
+ALTER TABLE outbound_messages
+    ADD CONSTRAINT outbound_messages_campaign_id_fkey
+    FOREIGN KEY (campaign_id) REFERENCES campaigns(id) ON DELETE RESTRICT;
+
+-- ========================================
+-- outbound_messages.customer_id -> customers.id
+-- ========================================
+ALTER TABLE outbound_messages
+    DROP CONSTRAINT IF EXISTS outbound_messages_customer_id_fkey;
+
+ALTER TABLE outbound_messages
+    ADD CONSTRAINT outbound_messages_customer_id_fkey
+    FOREIGN KEY (customer_id) REFERENCES customers(id) ON DELETE RESTRICT;
+
+COMMENT ON CONSTRAINT outbound_messages_campaign_id_fkey ON outbound_messages IS 'Campaigns with messages can only be removed via force delete';
+COMMENT ON CONSTRAINT outbound_messages_customer_id_fkey ON outbound_messages IS 'Customers with messages are anonymized instead of deleted';
+
+INSERT INTO schema_version (version, description) VALUES (3, 'Restrict deletes of campaigns/customers referenced by outbound_messages');
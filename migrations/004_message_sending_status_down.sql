@@ -0,0 +1,13 @@
+-- CampaignManager System - Rollback In-flight Message Status
+-- Returns claimed messages to pending and restores the original status check
+
+UPDATE outbound_messages SET status = 'pending' WHERE status = 'sending';
+
+ALTER TABLE outbound_messages
+    DROP CONSTRAINT IF EXISTS outbound_messages_status_check;
+
+ALTER TABLE outbound_messages
+    ADD CONSTRAINT outbound_messages_status_check
+    CHECK (status IN ('pending', 'sent', 'failed'));
+
+DELETE FROM schema_version WHERE version = 4;
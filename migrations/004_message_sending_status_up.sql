@@ -0,0 +1,14 @@
+-- CampaignManager System - In-flight Message Status
+-- Adds the 'sending' status to outbound_messages so that rows claimed by a
+-- worker (SELECT ... FOR UPDATE SKIP LOCKED) are distinguishable from pending ones
+
+ALTER TABLE outbound_messages
+    DROP CONSTRAINT IF EXISTS outbound_messages_status_check;
+
+ALTER TABLE outbound_messages
+    ADD CONSTRAINT outbound_messages_status_check
+    CHECK (status IN ('pending', 'sending', 'sent', 'failed'));
+
+COMMENT ON COLUMN outbound_messages.status IS 'Message lifecycle: pending -> sending (claimed) -> sent/failed';
+
+INSERT INTO schema_version (version, description) VALUES (4, 'Add sending status to outbound_messages');
@@ -0,0 +1,8 @@
+-- CampaignManager System - Rollback Sender Warm-up
+
+DROP TABLE IF EXISTS sender_warmups;
+
+ALTER TABLE campaigns
+    DROP COLUMN IF EXISTS sender_id;
+
+DELETE FROM schema_version WHERE version = 5;
@@ -0,0 +1,28 @@
+-- CampaignManager System - Sender Warm-up
+-- Adds an optional sender ID to campaigns and a warm-up ramp policy per sender.
+-- Newly provisioned numbers start at a small daily cap that doubles each day
+-- until it reaches the policy maximum.
+
+ALTER TABLE campaigns
+    ADD COLUMN IF NOT EXISTS sender_id VARCHAR(32);
+
+COMMENT ON COLUMN campaigns.sender_id IS 'Sender number or alphanumeric ID the campaign is sent from (optional)';
+
+CREATE TABLE IF NOT EXISTS sender_warmups (
+    sender_id VARCHAR(32) PRIMARY KEY,
+    started_on DATE NOT NULL DEFAULT CURRENT_DATE,
+    initial_daily_cap INTEGER NOT NULL DEFAULT 200 CHECK (initial_daily_cap > 0),
+    max_daily_cap INTEGER NOT NULL CHECK (max_daily_cap > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (max_daily_cap >= initial_daily_cap)
+);
+
+DROP TRIGGER IF EXISTS update_sender_warmups_updated_at ON sender_warmups;
+CREATE TRIGGER update_sender_warmups_updated_at BEFORE UPDATE ON sender_warmups
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE sender_warmups IS 'Warm-up ramp policies for newly provisioned sender IDs';
+COMMENT ON COLUMN sender_warmups.started_on IS 'Day 1 of the ramp (UTC); the cap doubles every day after';
+
+INSERT INTO schema_version (version, description) VALUES (5, 'Add campaign sender_id and sender_warmups');
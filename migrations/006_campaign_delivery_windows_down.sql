@@ -0,0 +1,6 @@
+-- CampaignManager System - Rollback Campaign Delivery Windows
+
+ALTER TABLE campaigns
+    DROP COLUMN IF EXISTS delivery_windows;
+
+DELETE FROM schema_version WHERE version = 6;
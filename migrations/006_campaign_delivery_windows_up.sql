@@ -0,0 +1,10 @@
+-- CampaignManager System - Campaign Delivery Windows
+-- Lets campaigns restrict sending to days of the week and hour ranges (UTC).
+-- Jobs processed outside every window are deferred until the next one opens.
+
+ALTER TABLE campaigns
+    ADD COLUMN IF NOT EXISTS delivery_windows JSONB;
+
+COMMENT ON COLUMN campaigns.delivery_windows IS 'Allowed send windows: [{"days": ["mon"], "start_hour": 9, "end_hour": 18}]; NULL means any time';
+
+INSERT INTO schema_version (version, description) VALUES (6, 'Add campaign delivery_windows');
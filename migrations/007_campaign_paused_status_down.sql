@@ -0,0 +1,17 @@
+-- CampaignManager System - Rollback Paused Campaigns
+-- Returns paused campaigns to sending and restores the original status check
+
+UPDATE campaigns SET status = 'sending' WHERE status = 'paused';
+
+ALTER TABLE campaigns
+    DROP COLUMN IF EXISTS paused_reason,
+    DROP COLUMN IF EXISTS paused_at;
+
+ALTER TABLE campaigns
+    DROP CONSTRAINT IF EXISTS campaigns_status_check;
+
+ALTER TABLE campaigns
+    ADD CONSTRAINT campaigns_status_check
+    CHECK (status IN ('draft', 'scheduled', 'sending', 'sent', 'failed'));
+
+DELETE FROM schema_version WHERE version = 7;
@@ -0,0 +1,18 @@
+-- CampaignManager System - Paused Campaigns
+-- Adds the 'paused' campaign status, used when a provider outage stops sending.
+-- paused_reason and paused_at record why and when the campaign was paused.
+
+ALTER TABLE campaigns
+    DROP CONSTRAINT IF EXISTS campaigns_status_check;
+
+ALTER TABLE campaigns
+    ADD CONSTRAINT campaigns_status_check
+    CHECK (status IN ('draft', 'scheduled', 'sending', 'paused', 'sent', 'failed'));
+
+ALTER TABLE campaigns
+    ADD COLUMN IF NOT EXISTS paused_reason TEXT,
+    ADD COLUMN IF NOT EXISTS paused_at TIMESTAMP;
+
+COMMENT ON COLUMN campaigns.status IS 'Campaign lifecycle: draft -> scheduled/sending <-> paused -> sent/failed';
+
+INSERT INTO schema_version (version, description) VALUES (7, 'Add paused campaign status');
@@ -0,0 +1,6 @@
+-- CampaignManager System - Rollback Campaign Simulations
+
+DROP TABLE IF EXISTS simulated_messages;
+DROP TABLE IF EXISTS simulation_runs;
+
+DELETE FROM schema_version WHERE version = 8;
@@ -0,0 +1,40 @@
+-- CampaignManager System - Campaign Simulations
+-- Shadow tables for dry runs of a campaign against the mock sender. Nothing here
+-- is ever queued or delivered; rows only feed projected stats and timing.
+
+CREATE TABLE IF NOT EXISTS simulation_runs (
+    id BIGSERIAL PRIMARY KEY,
+    campaign_id BIGINT NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('running', 'completed', 'failed')),
+    audience_size INTEGER NOT NULL DEFAULT 0,
+    simulated_sent INTEGER NOT NULL DEFAULT 0,
+    simulated_failed INTEGER NOT NULL DEFAULT 0,
+    render_ms BIGINT NOT NULL DEFAULT 0,
+    send_ms BIGINT NOT NULL DEFAULT 0,
+    avg_send_latency_ms DOUBLE PRECISION NOT NULL DEFAULT 0,
+    worker_concurrency INTEGER NOT NULL DEFAULT 1,
+    projected_duration_ms BIGINT NOT NULL DEFAULT 0,
+    error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_simulation_runs_campaign ON simulation_runs(campaign_id, id DESC);
+
+CREATE TABLE IF NOT EXISTS simulated_messages (
+    id BIGSERIAL PRIMARY KEY,
+    simulation_id BIGINT NOT NULL REFERENCES simulation_runs(id) ON DELETE CASCADE,
+    customer_id BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('sent', 'failed')),
+    rendered_content TEXT NOT NULL,
+    last_error TEXT,
+    latency_ms INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_simulated_messages_simulation ON simulated_messages(simulation_id);
+
+COMMENT ON TABLE simulation_runs IS 'Dry runs of campaigns against the mock sender with projected stats and timing';
+COMMENT ON TABLE simulated_messages IS 'Shadow copy of outbound_messages produced by a simulation run';
+
+INSERT INTO schema_version (version, description) VALUES (8, 'Add simulation_runs and simulated_messages');
@@ -0,0 +1,5 @@
+-- CampaignManager System - Rollback Campaign Draft Revisions
+
+DROP TABLE IF EXISTS campaign_revisions;
+
+DELETE FROM schema_version WHERE version = 9;
@@ -0,0 +1,20 @@
+-- CampaignManager System - Campaign Draft Revisions
+-- Every draft autosave or revert is kept as an immutable revision so editor
+-- work is never lost and changes to a campaign can be traced.
+
+CREATE TABLE IF NOT EXISTS campaign_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    campaign_id BIGINT NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    source VARCHAR(20) NOT NULL CHECK (source IN ('autosave', 'revert')),
+    reverted_from INTEGER,
+    draft JSONB NOT NULL,
+    applied BOOLEAN NOT NULL,
+    validation_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (campaign_id, revision)
+);
+
+COMMENT ON TABLE campaign_revisions IS 'Draft history of campaigns; applied revisions were copied onto the campaign';
+
+INSERT INTO schema_version (version, description) VALUES (9, 'Add campaign_revisions');
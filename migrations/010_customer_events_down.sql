@@ -0,0 +1,5 @@
+-- CampaignManager System - Rollback Customer Events
+
+DROP TABLE IF EXISTS customer_events;
+
+DELETE FROM schema_version WHERE version = 10;
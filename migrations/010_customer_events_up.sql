@@ -0,0 +1,18 @@
+-- CampaignManager System - Customer Events
+-- Things that happen to a customer outside of message delivery (profile edits,
+-- opt-in/out, imports). Together with outbound_messages they make up the
+-- customer's activity timeline.
+
+CREATE TABLE IF NOT EXISTS customer_events (
+    id BIGSERIAL PRIMARY KEY,
+    customer_id BIGINT NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    type VARCHAR(30) NOT NULL CHECK (type IN ('created', 'profile_updated', 'anonymized', 'opted_in', 'opted_out', 'imported')),
+    details JSONB,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_events_customer ON customer_events(customer_id, created_at DESC);
+
+COMMENT ON TABLE customer_events IS 'Non-delivery activity of a customer, shown on the customer timeline';
+
+INSERT INTO schema_version (version, description) VALUES (10, 'Add customer_events');
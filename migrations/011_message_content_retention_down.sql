@@ -0,0 +1,14 @@
+-- CampaignManager System - Rollback Message Content Retention
+-- Redacted content cannot be restored; it is left as an empty string.
+
+DROP TRIGGER IF EXISTS update_outbound_messages_updated_at ON outbound_messages;
+CREATE TRIGGER update_outbound_messages_updated_at BEFORE UPDATE ON outbound_messages
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+DROP INDEX IF EXISTS idx_outbound_messages_unredacted;
+
+UPDATE outbound_messages SET rendered_content = '' WHERE rendered_content IS NULL;
+ALTER TABLE outbound_messages ALTER COLUMN rendered_content SET NOT NULL;
+ALTER TABLE outbound_messages DROP COLUMN IF EXISTS content_redacted_at;
+
+DELETE FROM schema_version WHERE version = 11;
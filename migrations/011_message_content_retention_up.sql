@@ -0,0 +1,23 @@
+-- CampaignManager System - Message Content Retention
+-- Rendered message bodies contain customer PII. After the retention period the
+-- worker clears rendered_content of delivered and failed messages; the rest of
+-- the row (status, errors, timestamps) is kept for delivery records and stats.
+
+ALTER TABLE outbound_messages ALTER COLUMN rendered_content DROP NOT NULL;
+ALTER TABLE outbound_messages ADD COLUMN IF NOT EXISTS content_redacted_at TIMESTAMP;
+
+-- Candidates for redaction, oldest first
+CREATE INDEX IF NOT EXISTS idx_outbound_messages_unredacted ON outbound_messages(updated_at)
+    WHERE content_redacted_at IS NULL AND status IN ('sent', 'failed');
+
+-- Redacting a message must not move updated_at, which records when it was
+-- sent or failed
+DROP TRIGGER IF EXISTS update_outbound_messages_updated_at ON outbound_messages;
+CREATE TRIGGER update_outbound_messages_updated_at BEFORE UPDATE ON outbound_messages
+    FOR EACH ROW
+    WHEN (NEW.content_redacted_at IS NOT DISTINCT FROM OLD.content_redacted_at)
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON COLUMN outbound_messages.content_redacted_at IS 'When rendered_content was cleared by the retention policy';
+
+INSERT INTO schema_version (version, description) VALUES (11, 'Add message content retention');
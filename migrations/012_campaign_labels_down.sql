@@ -0,0 +1,5 @@
+-- CampaignManager System - Rollback Campaign Labels
+
+ALTER TABLE campaigns DROP COLUMN IF EXISTS labels;
+
+DELETE FROM schema_version WHERE version = 12;
@@ -0,0 +1,10 @@
+-- CampaignManager System - Campaign Labels
+-- Free-form labels for organizing campaigns. They are part of the portable
+-- campaign definition used to move campaigns between environments.
+
+ALTER TABLE campaigns
+    ADD COLUMN IF NOT EXISTS labels TEXT[] NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN campaigns.labels IS 'Free-form labels, e.g. {"black-friday", "retention"}';
+
+INSERT INTO schema_version (version, description) VALUES (12, 'Add campaign labels');
@@ -0,0 +1,6 @@
+-- CampaignManager System - Rollback Campaign External Keys
+
+DROP INDEX IF EXISTS idx_campaigns_external_key;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS external_key;
+
+DELETE FROM schema_version WHERE version = 13;
@@ -0,0 +1,14 @@
+-- CampaignManager System - Campaign External Keys
+-- A caller-supplied key identifying a campaign managed declaratively
+-- (PUT /api/campaigns/by-key/{external_key}). Campaigns created through the
+-- regular API have no key.
+
+ALTER TABLE campaigns
+    ADD COLUMN IF NOT EXISTS external_key VARCHAR(100);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_campaigns_external_key ON campaigns(external_key)
+    WHERE external_key IS NOT NULL;
+
+COMMENT ON COLUMN campaigns.external_key IS 'Caller-supplied identity for declarative provisioning; NULL for API-created campaigns';
+
+INSERT INTO schema_version (version, description) VALUES (13, 'Add campaign external_key');
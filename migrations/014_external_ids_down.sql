@@ -0,0 +1,8 @@
+-- CampaignManager System - Rollback External IDs
+
+DROP INDEX IF EXISTS idx_campaigns_external_id;
+DROP INDEX IF EXISTS idx_customers_external_id;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS external_id;
+ALTER TABLE customers DROP COLUMN IF EXISTS external_id;
+
+DELETE FROM schema_version WHERE version = 14;
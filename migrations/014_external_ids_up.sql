@@ -0,0 +1,17 @@
+-- CampaignManager System - External IDs
+-- Lets CRMs reference customers and campaigns by their own identifiers instead
+-- of storing ours. Each ID is unique within its table; rows without one are
+-- unaffected.
+
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS external_id VARCHAR(100);
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS external_id VARCHAR(100);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_customers_external_id ON customers(external_id)
+    WHERE external_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_campaigns_external_id ON campaigns(external_id)
+    WHERE external_id IS NOT NULL;
+
+COMMENT ON COLUMN customers.external_id IS 'Identifier of the customer in an external system (e.g. CRM contact ID)';
+COMMENT ON COLUMN campaigns.external_id IS 'Identifier of the campaign in an external system';
+
+INSERT INTO schema_version (version, description) VALUES (14, 'Add customer and campaign external_id');
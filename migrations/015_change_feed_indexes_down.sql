@@ -0,0 +1,6 @@
+-- CampaignManager System - Rollback Change Feed Indexes
+
+DROP INDEX IF EXISTS idx_outbound_messages_updated_at;
+DROP INDEX IF EXISTS idx_campaigns_updated_at;
+
+DELETE FROM schema_version WHERE version = 15;
@@ -0,0 +1,8 @@
+-- CampaignManager System - Change Feed Indexes
+-- Integrations poll campaigns and messages for rows changed since a cursor of
+-- (updated_at, id). These indexes let each poll seek straight to the cursor.
+
+CREATE INDEX IF NOT EXISTS idx_campaigns_updated_at ON campaigns(updated_at, id);
+CREATE INDEX IF NOT EXISTS idx_outbound_messages_updated_at ON outbound_messages(updated_at, id);
+
+INSERT INTO schema_version (version, description) VALUES (15, 'Add change feed indexes on updated_at');
@@ -0,0 +1,14 @@
+-- CampaignManager System - Rollback Change Log
+
+DROP TRIGGER IF EXISTS record_sender_warmups_change ON sender_warmups;
+DROP TRIGGER IF EXISTS record_outbound_messages_update ON outbound_messages;
+DROP TRIGGER IF EXISTS record_outbound_messages_insert_delete ON outbound_messages;
+DROP TRIGGER IF EXISTS record_campaigns_change ON campaigns;
+DROP TRIGGER IF EXISTS record_customers_change ON customers;
+DROP FUNCTION IF EXISTS record_change();
+DROP TABLE IF EXISTS change_log;
+
+DROP TRIGGER IF EXISTS update_simulation_runs_updated_at ON simulation_runs;
+ALTER TABLE simulation_runs DROP COLUMN IF EXISTS updated_at;
+
+DELETE FROM schema_version WHERE version = 16;
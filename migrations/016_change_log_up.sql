@@ -0,0 +1,76 @@
+-- CampaignManager System - Change Log
+-- Every insert, update and delete of the synced entities is recorded by a
+-- trigger, so data warehouses can replay changes instead of taking full dumps.
+-- Append-only tables (campaign_revisions, customer_events) are synced by ID and
+-- are not logged.
+
+-- Every mutable table keeps updated_at current
+ALTER TABLE simulation_runs ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+DROP TRIGGER IF EXISTS update_simulation_runs_updated_at ON simulation_runs;
+CREATE TRIGGER update_simulation_runs_updated_at BEFORE UPDATE ON simulation_runs
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE IF NOT EXISTS change_log (
+    id BIGSERIAL PRIMARY KEY,
+    entity VARCHAR(30) NOT NULL,
+    entity_id VARCHAR(100) NOT NULL,
+    operation VARCHAR(10) NOT NULL CHECK (operation IN ('insert', 'update', 'delete')),
+    data JSONB,
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_change_log_entity ON change_log(entity, id);
+CREATE INDEX IF NOT EXISTS idx_change_log_changed_at ON change_log(changed_at);
+
+-- record_change logs the row touched by the statement. TG_ARGV[0] is the
+-- entity name and TG_ARGV[1] the key column. Deletes are logged without data.
+-- Rendered message bodies are never copied; they are subject to the content
+-- retention policy.
+CREATE OR REPLACE FUNCTION record_change()
+RETURNS TRIGGER AS $$
+DECLARE
+    row_data JSONB;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        row_data := to_jsonb(OLD);
+        INSERT INTO change_log (entity, entity_id, operation)
+        VALUES (TG_ARGV[0], row_data ->> TG_ARGV[1], 'delete');
+        RETURN OLD;
+    END IF;
+
+    row_data := to_jsonb(NEW) - 'rendered_content';
+    INSERT INTO change_log (entity, entity_id, operation, data)
+    VALUES (TG_ARGV[0], row_data ->> TG_ARGV[1], lower(TG_OP), row_data);
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS record_customers_change ON customers;
+CREATE TRIGGER record_customers_change AFTER INSERT OR UPDATE OR DELETE ON customers
+    FOR EACH ROW EXECUTE FUNCTION record_change('customer', 'id');
+
+DROP TRIGGER IF EXISTS record_campaigns_change ON campaigns;
+CREATE TRIGGER record_campaigns_change AFTER INSERT OR UPDATE OR DELETE ON campaigns
+    FOR EACH ROW EXECUTE FUNCTION record_change('campaign', 'id');
+
+-- Redaction only clears content, which is not logged, so it is skipped like
+-- the updated_at trigger skips it
+DROP TRIGGER IF EXISTS record_outbound_messages_insert_delete ON outbound_messages;
+CREATE TRIGGER record_outbound_messages_insert_delete AFTER INSERT OR DELETE ON outbound_messages
+    FOR EACH ROW EXECUTE FUNCTION record_change('outbound_message', 'id');
+
+DROP TRIGGER IF EXISTS record_outbound_messages_update ON outbound_messages;
+CREATE TRIGGER record_outbound_messages_update AFTER UPDATE ON outbound_messages
+    FOR EACH ROW
+    WHEN (NEW.content_redacted_at IS NOT DISTINCT FROM OLD.content_redacted_at)
+    EXECUTE FUNCTION record_change('outbound_message', 'id');
+
+DROP TRIGGER IF EXISTS record_sender_warmups_change ON sender_warmups;
+CREATE TRIGGER record_sender_warmups_change AFTER INSERT OR UPDATE OR DELETE ON sender_warmups
+    FOR EACH ROW EXECUTE FUNCTION record_change('sender_warmup', 'sender_id');
+
+COMMENT ON TABLE change_log IS 'Insert, update and delete records of synced entities for change data capture';
+COMMENT ON COLUMN change_log.data IS 'Row after the change; NULL for deletes and for anonymized customers';
+
+INSERT INTO schema_version (version, description) VALUES (16, 'Add change log and simulation_runs updated_at');
@@ -0,0 +1,5 @@
+-- CampaignManager System - Rollback Campaign Audience
+
+ALTER TABLE campaigns DROP COLUMN IF EXISTS audience;
+
+DELETE FROM schema_version WHERE version = 17;
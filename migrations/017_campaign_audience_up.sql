@@ -0,0 +1,9 @@
+-- CampaignManager System - Campaign Audience
+-- A campaign can carry the audience it is normally sent to, so automation can
+-- send it without repeating the recipients on every call.
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS audience JSONB;
+
+COMMENT ON COLUMN campaigns.audience IS 'Bound audience used when a send names no recipients: {"customer_ids": [...]} or {"target": "all"}';
+
+INSERT INTO schema_version (version, description) VALUES (17, 'Add campaign bound audience');
@@ -0,0 +1,5 @@
+-- CampaignManager System - Rollback Scheduled Campaign Dispatch
+
+ALTER TABLE campaigns DROP COLUMN IF EXISTS dispatch_claimed_at;
+
+DELETE FROM schema_version WHERE version = 18;
@@ -0,0 +1,10 @@
+-- CampaignManager System - Scheduled Campaign Dispatch
+-- The worker's scheduler claims due scheduled campaigns before sending them.
+-- A claim is a lease: another replica skips the campaign until the lease runs
+-- out, so a campaign is dispatched once even with many workers running.
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS dispatch_claimed_at TIMESTAMP;
+
+COMMENT ON COLUMN campaigns.dispatch_claimed_at IS 'When a scheduler replica last claimed the campaign for dispatch (UTC)';
+
+INSERT INTO schema_version (version, description) VALUES (18, 'Add scheduled campaign dispatch claims');
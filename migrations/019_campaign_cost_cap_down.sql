@@ -0,0 +1,7 @@
+-- CampaignManager System - Rollback Campaign Cost Cap
+
+DROP TABLE IF EXISTS campaign_costs;
+
+ALTER TABLE campaigns DROP COLUMN IF EXISTS max_cost;
+
+DELETE FROM schema_version WHERE version = 19;
@@ -0,0 +1,19 @@
+-- CampaignManager System - Campaign Cost Cap
+-- A campaign can carry a maximum spend. Workers reserve the cost of every
+-- message before sending it and pause the campaign once the cap is reached.
+-- Accrued cost lives in its own table so that per-message updates neither
+-- contend with campaign edits nor flood the change log.
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS max_cost NUMERIC(12, 4);
+
+COMMENT ON COLUMN campaigns.max_cost IS 'Maximum spend for the campaign; NULL means no cap';
+
+CREATE TABLE IF NOT EXISTS campaign_costs (
+    campaign_id BIGINT PRIMARY KEY REFERENCES campaigns(id) ON DELETE CASCADE,
+    accrued NUMERIC(14, 4) NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE campaign_costs IS 'Cost of messages sent or being sent per campaign (segments x channel rate)';
+
+INSERT INTO schema_version (version, description) VALUES (19, 'Add campaign cost cap');
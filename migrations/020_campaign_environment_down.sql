@@ -0,0 +1,5 @@
+-- CampaignManager System - Rollback Campaign Environment
+
+ALTER TABLE campaigns DROP COLUMN IF EXISTS environment;
+
+DELETE FROM schema_version WHERE version = 20;
@@ -0,0 +1,12 @@
+-- CampaignManager System - Campaign Environment
+-- Campaigns tagged 'test' are QA traffic: workers send them with the
+-- providers' sandbox credentials, they accrue no cost and campaign listings
+-- leave them out unless asked for.
+
+ALTER TABLE campaigns
+    ADD COLUMN IF NOT EXISTS environment VARCHAR(10) NOT NULL DEFAULT 'live'
+    CHECK (environment IN ('live', 'test'));
+
+COMMENT ON COLUMN campaigns.environment IS 'live, or test for QA campaigns sent through provider sandboxes';
+
+INSERT INTO schema_version (version, description) VALUES (20, 'Add campaign environment');
@@ -0,0 +1,7 @@
+-- CampaignManager System - Rollback Campaign Slugs
+
+DROP INDEX IF EXISTS idx_campaigns_slug;
+
+ALTER TABLE campaigns DROP COLUMN IF EXISTS slug;
+
+DELETE FROM schema_version WHERE version = 21;
@@ -0,0 +1,29 @@
+-- CampaignManager System - Campaign Slugs
+-- Campaign names are free text and often repeated ("October Promo"). Every
+-- campaign gets a unique slug derived from its name when it is created, with
+-- a numeric suffix when the name is taken, e.g. october-promo-2. The slug does
+-- not change when the campaign is renamed.
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS slug VARCHAR(100);
+
+-- Backfill: the first campaign with a name keeps the plain slug, later ones get their ID appended
+WITH bases AS (
+    SELECT id, COALESCE(NULLIF(trim(both '-' from left(regexp_replace(lower(name), '[^a-z0-9]+', '-', 'g'), 80)), ''), 'campaign') AS base
+    FROM campaigns
+    WHERE slug IS NULL
+), numbered AS (
+    SELECT id, base, ROW_NUMBER() OVER (PARTITION BY base ORDER BY id) AS n
+    FROM bases
+)
+UPDATE campaigns c
+SET slug = CASE WHEN numbered.n = 1 THEN numbered.base ELSE numbered.base || '-' || c.id END
+FROM numbered
+WHERE numbered.id = c.id;
+
+ALTER TABLE campaigns ALTER COLUMN slug SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_campaigns_slug ON campaigns(slug);
+
+COMMENT ON COLUMN campaigns.slug IS 'Unique URL-safe name, e.g. october-promo-2; fixed at creation';
+
+INSERT INTO schema_version (version, description) VALUES (21, 'Add campaign slugs');
@@ -0,0 +1,5 @@
+-- CampaignManager System - Rollback Campaign Locale
+
+ALTER TABLE campaigns DROP COLUMN IF EXISTS locale;
+
+DELETE FROM schema_version WHERE version = 22;
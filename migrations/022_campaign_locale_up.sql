@@ -0,0 +1,10 @@
+-- CampaignManager System - Campaign Locale
+-- Template formatters such as {amount|money:KES} render numbers with the
+-- separators of the campaign's locale.
+
+ALTER TABLE campaigns
+    ADD COLUMN IF NOT EXISTS locale VARCHAR(10) NOT NULL DEFAULT 'en';
+
+COMMENT ON COLUMN campaigns.locale IS 'Locale used by template number formatters, e.g. en or de';
+
+INSERT INTO schema_version (version, description) VALUES (22, 'Add campaign locale');
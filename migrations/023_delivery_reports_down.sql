@@ -0,0 +1,20 @@
+-- CampaignManager System - Rollback Delivery Reports
+-- Delivered and undelivered messages go back to 'sent', the status they had
+-- before their delivery report arrived.
+
+UPDATE outbound_messages SET status = 'sent' WHERE status IN ('delivered', 'undelivered');
+
+ALTER TABLE outbound_messages
+    DROP CONSTRAINT IF EXISTS outbound_messages_status_check;
+
+ALTER TABLE outbound_messages
+    ADD CONSTRAINT outbound_messages_status_check
+    CHECK (status IN ('pending', 'sending', 'sent', 'failed'));
+
+COMMENT ON COLUMN outbound_messages.status IS 'Message lifecycle: pending -> sending (claimed) -> sent/failed';
+
+DROP INDEX IF EXISTS idx_outbound_messages_provider_message_id;
+
+ALTER TABLE outbound_messages DROP COLUMN IF EXISTS provider_message_id;
+
+DELETE FROM schema_version WHERE version = 23;
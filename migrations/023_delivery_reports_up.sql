@@ -0,0 +1,23 @@
+-- CampaignManager System - Delivery Reports
+-- Providers report the final delivery status of a message asynchronously.
+-- The worker stores the provider's message ID when a send is accepted, and
+-- delivery reports move sent messages to 'delivered' or 'undelivered'.
+
+ALTER TABLE outbound_messages
+    ADD COLUMN IF NOT EXISTS provider_message_id VARCHAR(100);
+
+CREATE INDEX IF NOT EXISTS idx_outbound_messages_provider_message_id
+    ON outbound_messages(provider_message_id)
+    WHERE provider_message_id IS NOT NULL;
+
+ALTER TABLE outbound_messages
+    DROP CONSTRAINT IF EXISTS outbound_messages_status_check;
+
+ALTER TABLE outbound_messages
+    ADD CONSTRAINT outbound_messages_status_check
+    CHECK (status IN ('pending', 'sending', 'sent', 'failed', 'delivered', 'undelivered'));
+
+COMMENT ON COLUMN outbound_messages.provider_message_id IS 'ID the provider assigned to the message, referenced by its delivery reports';
+COMMENT ON COLUMN outbound_messages.status IS 'Message lifecycle: pending -> sending (claimed) -> sent/failed -> delivered/undelivered (delivery report)';
+
+INSERT INTO schema_version (version, description) VALUES (23, 'Add delivery reports to outbound_messages');
@@ -0,0 +1,9 @@
+-- CampaignManager System - Rollback Customer Opt-Outs
+
+DROP INDEX IF EXISTS idx_customer_events_type_created_at;
+
+ALTER TABLE customer_events DROP COLUMN IF EXISTS campaign_id;
+
+ALTER TABLE customers DROP COLUMN IF EXISTS opted_out_at;
+
+DELETE FROM schema_version WHERE version = 24;
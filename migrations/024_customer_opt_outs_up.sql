@@ -0,0 +1,18 @@
+-- CampaignManager System - Customer Opt-Outs
+-- Customers opt out by replying STOP and back in by replying START. Opted-out
+-- customers receive no campaign messages. Each opt-out event records the
+-- campaign the customer last received, for compliance reports.
+
+ALTER TABLE customers
+    ADD COLUMN IF NOT EXISTS opted_out_at TIMESTAMP;
+
+ALTER TABLE customer_events
+    ADD COLUMN IF NOT EXISTS campaign_id BIGINT REFERENCES campaigns(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_customer_events_type_created_at
+    ON customer_events(type, created_at);
+
+COMMENT ON COLUMN customers.opted_out_at IS 'When the customer opted out of campaign messages; NULL while subscribed';
+COMMENT ON COLUMN customer_events.campaign_id IS 'Campaign an opt-out is attributed to: the last one the customer was sent';
+
+INSERT INTO schema_version (version, description) VALUES (24, 'Add customer opt-outs');
@@ -0,0 +1,11 @@
+-- CampaignManager System - Rollback Customer Segments
+
+DROP INDEX IF EXISTS idx_customers_phone_pattern;
+DROP INDEX IF EXISTS idx_customers_preferred_product;
+DROP INDEX IF EXISTS idx_customers_location;
+
+DROP TABLE IF EXISTS segments;
+
+COMMENT ON COLUMN campaigns.audience IS 'Bound audience used when a send names no recipients: {"customer_ids": [...]} or {"target": "all"}';
+
+DELETE FROM schema_version WHERE version = 25;
@@ -0,0 +1,28 @@
+-- CampaignManager System - Customer Segments
+-- A segment is a saved customer filter. Sends reference it by id and page
+-- through the customers it matches when they are sent, so a segment always
+-- reflects the current customer base.
+
+CREATE TABLE IF NOT EXISTS segments (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    filter JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+DROP TRIGGER IF EXISTS update_segments_updated_at ON segments;
+CREATE TRIGGER update_segments_updated_at BEFORE UPDATE ON segments
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Segment filters match on these columns; phone prefixes need pattern ops
+CREATE INDEX IF NOT EXISTS idx_customers_location ON customers(location);
+CREATE INDEX IF NOT EXISTS idx_customers_preferred_product ON customers(preferred_product);
+CREATE INDEX IF NOT EXISTS idx_customers_phone_pattern ON customers(phone text_pattern_ops);
+
+COMMENT ON TABLE segments IS 'Saved customer filters that sends can target by id';
+COMMENT ON COLUMN campaigns.audience IS 'Bound audience used when a send names no recipients: {"customer_ids": [...]}, {"target": "all"} or {"segment_id": N}';
+COMMENT ON COLUMN segments.filter IS 'Criteria a customer must all match: location, preferred_product, phone_prefix';
+
+INSERT INTO schema_version (version, description) VALUES (25, 'Add customer segments');
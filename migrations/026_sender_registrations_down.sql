@@ -0,0 +1,5 @@
+-- CampaignManager System - Rollback Sender Registrations
+
+DROP TABLE IF EXISTS sender_registrations;
+
+DELETE FROM schema_version WHERE version = 26;
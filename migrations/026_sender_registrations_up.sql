@@ -0,0 +1,26 @@
+-- CampaignManager System - Sender Registrations
+-- Many countries only deliver SMS from registered senders: alphanumeric sender
+-- IDs registered with carriers, DLT headers, or numbers linked to a registered
+-- 10DLC-style campaign. A registration records one sender for one destination
+-- country; the worker fails messages to countries it is configured to enforce
+-- when the campaign's sender has no matching registration.
+
+CREATE TABLE IF NOT EXISTS sender_registrations (
+    sender_id VARCHAR(32) NOT NULL,
+    country CHAR(2) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('sender_id', 'campaign')),
+    registration_id VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (sender_id, country)
+);
+
+DROP TRIGGER IF EXISTS update_sender_registrations_updated_at ON sender_registrations;
+CREATE TRIGGER update_sender_registrations_updated_at BEFORE UPDATE ON sender_registrations
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE sender_registrations IS 'Regulatory registrations of sender IDs per destination country';
+COMMENT ON COLUMN sender_registrations.country IS 'ISO 3166-1 alpha-2 code of the destination country';
+COMMENT ON COLUMN sender_registrations.registration_id IS 'Reference issued by the registry, e.g. a 10DLC campaign ID';
+
+INSERT INTO schema_version (version, description) VALUES (26, 'Add sender_registrations');
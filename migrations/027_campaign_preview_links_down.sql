@@ -0,0 +1,5 @@
+-- CampaignManager System - Rollback Campaign Preview Links
+
+DROP TABLE IF EXISTS campaign_preview_links;
+
+DELETE FROM schema_version WHERE version = 27;
@@ -0,0 +1,18 @@
+-- CampaignManager System - Campaign Preview Links
+-- A preview link lets reviewers without API access see a campaign's message
+-- as it would appear on a phone. The token in the URL is the only credential,
+-- so links expire and can be revoked.
+
+CREATE TABLE IF NOT EXISTS campaign_preview_links (
+    token VARCHAR(64) PRIMARY KEY,
+    campaign_id BIGINT NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaign_preview_links_campaign_id ON campaign_preview_links(campaign_id);
+
+COMMENT ON TABLE campaign_preview_links IS 'Tokenized links to a public, read-only preview of a campaign message';
+COMMENT ON COLUMN campaign_preview_links.token IS 'Random URL token; anyone holding it can view the preview until expires_at';
+
+INSERT INTO schema_version (version, description) VALUES (27, 'Add campaign_preview_links');
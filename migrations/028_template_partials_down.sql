@@ -0,0 +1,5 @@
+-- CampaignManager System - Rollback Template Partials
+
+DROP TABLE IF EXISTS template_partials;
+
+DELETE FROM schema_version WHERE version = 28;
@@ -0,0 +1,19 @@
+-- CampaignManager System - Template Partials
+-- Partials are named snippets, such as a standard footer, that templates
+-- include with {>name}. They are expanded whenever a template is validated or
+-- rendered, so editing a partial changes every campaign that has not been sent.
+
+CREATE TABLE IF NOT EXISTS template_partials (
+    name VARCHAR(64) PRIMARY KEY,
+    content TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+DROP TRIGGER IF EXISTS update_template_partials_updated_at ON template_partials;
+CREATE TRIGGER update_template_partials_updated_at BEFORE UPDATE ON template_partials
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE template_partials IS 'Named template snippets included in campaign templates with {>name}';
+
+INSERT INTO schema_version (version, description) VALUES (28, 'Add template_partials');
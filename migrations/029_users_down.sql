@@ -0,0 +1,5 @@
+-- CampaignManager System - Rollback Users
+
+DROP TABLE IF EXISTS users;
+
+DELETE FROM schema_version WHERE version = 29;
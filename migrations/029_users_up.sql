@@ -0,0 +1,23 @@
+-- CampaignManager System - Users
+-- Human operators sign in with an email and password and receive a JWT. Their
+-- role decides what they may do: viewers read, editors also create and edit,
+-- senders also trigger sends, and admins also delete and administer.
+
+CREATE TABLE IF NOT EXISTS users (
+    id BIGSERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    password_hash VARCHAR(100) NOT NULL,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('viewer', 'editor', 'sender', 'admin')),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+DROP TRIGGER IF EXISTS update_users_updated_at ON users;
+CREATE TRIGGER update_users_updated_at BEFORE UPDATE ON users
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE users IS 'Operators who sign in to the API';
+COMMENT ON COLUMN users.email IS 'Stored lowercase';
+COMMENT ON COLUMN users.password_hash IS 'bcrypt hash of the password';
+
+INSERT INTO schema_version (version, description) VALUES (29, 'Add users');
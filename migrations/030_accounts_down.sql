@@ -0,0 +1,75 @@
+-- CampaignManager System - Rollback Accounts
+-- Only safe while account 1 is the only account: names and keys that are
+-- unique per account become globally unique again.
+
+CREATE OR REPLACE FUNCTION record_change()
+RETURNS TRIGGER AS $$
+DECLARE
+    row_data JSONB;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        row_data := to_jsonb(OLD);
+        INSERT INTO change_log (entity, entity_id, operation)
+        VALUES (TG_ARGV[0], row_data ->> TG_ARGV[1], 'delete');
+        RETURN OLD;
+    END IF;
+
+    row_data := to_jsonb(NEW) - 'rendered_content';
+    INSERT INTO change_log (entity, entity_id, operation, data)
+    VALUES (TG_ARGV[0], row_data ->> TG_ARGV[1], lower(TG_OP), row_data);
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+DROP INDEX IF EXISTS idx_change_log_account;
+DROP INDEX IF EXISTS idx_outbound_messages_account;
+
+ALTER TABLE IF EXISTS sender_registrations DROP CONSTRAINT IF EXISTS sender_registrations_pkey;
+ALTER TABLE IF EXISTS sender_registrations ADD CONSTRAINT sender_registrations_pkey PRIMARY KEY (sender_id, country);
+ALTER TABLE IF EXISTS sender_warmups DROP CONSTRAINT IF EXISTS sender_warmups_pkey;
+ALTER TABLE IF EXISTS sender_warmups ADD CONSTRAINT sender_warmups_pkey PRIMARY KEY (sender_id);
+ALTER TABLE IF EXISTS template_partials DROP CONSTRAINT IF EXISTS template_partials_pkey;
+ALTER TABLE IF EXISTS template_partials ADD CONSTRAINT template_partials_pkey PRIMARY KEY (name);
+
+ALTER TABLE IF EXISTS segments DROP CONSTRAINT IF EXISTS segments_account_id_name_key;
+ALTER TABLE IF EXISTS segments DROP CONSTRAINT IF EXISTS segments_name_key;
+ALTER TABLE IF EXISTS segments ADD CONSTRAINT segments_name_key UNIQUE (name);
+
+DROP INDEX IF EXISTS idx_customers_external_id;
+CREATE UNIQUE INDEX idx_customers_external_id ON customers(external_id)
+    WHERE external_id IS NOT NULL;
+DROP INDEX IF EXISTS idx_campaigns_external_id;
+CREATE UNIQUE INDEX idx_campaigns_external_id ON campaigns(external_id)
+    WHERE external_id IS NOT NULL;
+DROP INDEX IF EXISTS idx_campaigns_external_key;
+CREATE UNIQUE INDEX idx_campaigns_external_key ON campaigns(external_key)
+    WHERE external_key IS NOT NULL;
+DROP INDEX IF EXISTS idx_campaigns_slug;
+CREATE UNIQUE INDEX idx_campaigns_slug ON campaigns(slug);
+
+ALTER TABLE IF EXISTS campaign_preview_links DROP CONSTRAINT IF EXISTS campaign_preview_links_account_campaign_fkey;
+ALTER TABLE IF EXISTS simulation_runs DROP CONSTRAINT IF EXISTS simulation_runs_account_campaign_fkey;
+ALTER TABLE IF EXISTS campaign_revisions DROP CONSTRAINT IF EXISTS campaign_revisions_account_campaign_fkey;
+ALTER TABLE IF EXISTS customer_events DROP CONSTRAINT IF EXISTS customer_events_account_customer_fkey;
+ALTER TABLE IF EXISTS outbound_messages DROP CONSTRAINT IF EXISTS outbound_messages_account_customer_fkey;
+ALTER TABLE IF EXISTS outbound_messages DROP CONSTRAINT IF EXISTS outbound_messages_account_campaign_fkey;
+ALTER TABLE IF EXISTS customers DROP CONSTRAINT IF EXISTS customers_account_id_id_key;
+ALTER TABLE IF EXISTS campaigns DROP CONSTRAINT IF EXISTS campaigns_account_id_id_key;
+
+ALTER TABLE IF EXISTS change_log DROP COLUMN IF EXISTS account_id;
+ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS account_id;
+ALTER TABLE IF EXISTS sender_registrations DROP COLUMN IF EXISTS account_id;
+ALTER TABLE IF EXISTS sender_warmups DROP COLUMN IF EXISTS account_id;
+ALTER TABLE IF EXISTS template_partials DROP COLUMN IF EXISTS account_id;
+ALTER TABLE IF EXISTS segments DROP COLUMN IF EXISTS account_id;
+ALTER TABLE IF EXISTS campaign_preview_links DROP COLUMN IF EXISTS account_id;
+ALTER TABLE IF EXISTS simulation_runs DROP COLUMN IF EXISTS account_id;
+ALTER TABLE IF EXISTS campaign_revisions DROP COLUMN IF EXISTS account_id;
+ALTER TABLE IF EXISTS customer_events DROP COLUMN IF EXISTS account_id;
+ALTER TABLE IF EXISTS outbound_messages DROP COLUMN IF EXISTS account_id;
+ALTER TABLE IF EXISTS campaigns DROP COLUMN IF EXISTS account_id;
+ALTER TABLE IF EXISTS customers DROP COLUMN IF EXISTS account_id;
+
+DROP TABLE IF EXISTS accounts;
+
+DELETE FROM schema_version WHERE version = 30;
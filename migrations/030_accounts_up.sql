@@ -0,0 +1,142 @@
+-- CampaignManager System - Accounts
+-- Every row belongs to an account (tenant). Tenant-owned tables carry an
+-- account_id, and rows that reference a campaign or customer must be in the
+-- same account as it: composite foreign keys on (account_id, campaign_id) and
+-- (account_id, customer_id) make a message to another account's customer
+-- impossible to record. Existing data moves to account 1, the operator's own
+-- account. campaign_costs and simulated_messages only extend rows of
+-- campaigns and simulation_runs and belong to their account.
+
+CREATE TABLE IF NOT EXISTS accounts (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+DROP TRIGGER IF EXISTS update_accounts_updated_at ON accounts;
+CREATE TRIGGER update_accounts_updated_at BEFORE UPDATE ON accounts
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+INSERT INTO accounts (id, name) VALUES (1, 'Default') ON CONFLICT (id) DO NOTHING;
+SELECT setval('accounts_id_seq', GREATEST((SELECT MAX(id) FROM accounts), 1));
+
+-- ========================================
+-- account_id on every tenant-owned table
+-- ========================================
+-- The default backfills existing rows and is then dropped, so an insert that
+-- does not name its account fails instead of landing in account 1.
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS account_id BIGINT NOT NULL DEFAULT 1 REFERENCES accounts(id);
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS account_id BIGINT NOT NULL DEFAULT 1 REFERENCES accounts(id);
+ALTER TABLE outbound_messages ADD COLUMN IF NOT EXISTS account_id BIGINT NOT NULL DEFAULT 1 REFERENCES accounts(id);
+ALTER TABLE customer_events ADD COLUMN IF NOT EXISTS account_id BIGINT NOT NULL DEFAULT 1 REFERENCES accounts(id);
+ALTER TABLE campaign_revisions ADD COLUMN IF NOT EXISTS account_id BIGINT NOT NULL DEFAULT 1 REFERENCES accounts(id);
+ALTER TABLE simulation_runs ADD COLUMN IF NOT EXISTS account_id BIGINT NOT NULL DEFAULT 1 REFERENCES accounts(id);
+ALTER TABLE campaign_preview_links ADD COLUMN IF NOT EXISTS account_id BIGINT NOT NULL DEFAULT 1 REFERENCES accounts(id);
+ALTER TABLE segments ADD COLUMN IF NOT EXISTS account_id BIGINT NOT NULL DEFAULT 1 REFERENCES accounts(id);
+ALTER TABLE template_partials ADD COLUMN IF NOT EXISTS account_id BIGINT NOT NULL DEFAULT 1 REFERENCES accounts(id);
+ALTER TABLE sender_warmups ADD COLUMN IF NOT EXISTS account_id BIGINT NOT NULL DEFAULT 1 REFERENCES accounts(id);
+ALTER TABLE sender_registrations ADD COLUMN IF NOT EXISTS account_id BIGINT NOT NULL DEFAULT 1 REFERENCES accounts(id);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS account_id BIGINT NOT NULL DEFAULT 1 REFERENCES accounts(id);
+ALTER TABLE change_log ADD COLUMN IF NOT EXISTS account_id BIGINT NOT NULL DEFAULT 1 REFERENCES accounts(id);
+
+ALTER TABLE customers ALTER COLUMN account_id DROP DEFAULT;
+ALTER TABLE campaigns ALTER COLUMN account_id DROP DEFAULT;
+ALTER TABLE outbound_messages ALTER COLUMN account_id DROP DEFAULT;
+ALTER TABLE customer_events ALTER COLUMN account_id DROP DEFAULT;
+ALTER TABLE campaign_revisions ALTER COLUMN account_id DROP DEFAULT;
+ALTER TABLE simulation_runs ALTER COLUMN account_id DROP DEFAULT;
+ALTER TABLE campaign_preview_links ALTER COLUMN account_id DROP DEFAULT;
+ALTER TABLE segments ALTER COLUMN account_id DROP DEFAULT;
+ALTER TABLE template_partials ALTER COLUMN account_id DROP DEFAULT;
+ALTER TABLE sender_warmups ALTER COLUMN account_id DROP DEFAULT;
+ALTER TABLE sender_registrations ALTER COLUMN account_id DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN account_id DROP DEFAULT;
+ALTER TABLE change_log ALTER COLUMN account_id DROP DEFAULT;
+
+-- ========================================
+-- Children stay in their parent's account
+-- ========================================
+ALTER TABLE campaigns DROP CONSTRAINT IF EXISTS campaigns_account_id_id_key;
+ALTER TABLE campaigns ADD CONSTRAINT campaigns_account_id_id_key UNIQUE (account_id, id);
+ALTER TABLE customers DROP CONSTRAINT IF EXISTS customers_account_id_id_key;
+ALTER TABLE customers ADD CONSTRAINT customers_account_id_id_key UNIQUE (account_id, id);
+
+ALTER TABLE outbound_messages DROP CONSTRAINT IF EXISTS outbound_messages_account_campaign_fkey;
+ALTER TABLE outbound_messages ADD CONSTRAINT outbound_messages_account_campaign_fkey
+    FOREIGN KEY (account_id, campaign_id) REFERENCES campaigns(account_id, id) ON DELETE RESTRICT;
+ALTER TABLE outbound_messages DROP CONSTRAINT IF EXISTS outbound_messages_account_customer_fkey;
+ALTER TABLE outbound_messages ADD CONSTRAINT outbound_messages_account_customer_fkey
+    FOREIGN KEY (account_id, customer_id) REFERENCES customers(account_id, id) ON DELETE RESTRICT;
+ALTER TABLE customer_events DROP CONSTRAINT IF EXISTS customer_events_account_customer_fkey;
+ALTER TABLE customer_events ADD CONSTRAINT customer_events_account_customer_fkey
+    FOREIGN KEY (account_id, customer_id) REFERENCES customers(account_id, id) ON DELETE CASCADE;
+ALTER TABLE campaign_revisions DROP CONSTRAINT IF EXISTS campaign_revisions_account_campaign_fkey;
+ALTER TABLE campaign_revisions ADD CONSTRAINT campaign_revisions_account_campaign_fkey
+    FOREIGN KEY (account_id, campaign_id) REFERENCES campaigns(account_id, id) ON DELETE CASCADE;
+ALTER TABLE simulation_runs DROP CONSTRAINT IF EXISTS simulation_runs_account_campaign_fkey;
+ALTER TABLE simulation_runs ADD CONSTRAINT simulation_runs_account_campaign_fkey
+    FOREIGN KEY (account_id, campaign_id) REFERENCES campaigns(account_id, id) ON DELETE CASCADE;
+ALTER TABLE campaign_preview_links DROP CONSTRAINT IF EXISTS campaign_preview_links_account_campaign_fkey;
+ALTER TABLE campaign_preview_links ADD CONSTRAINT campaign_preview_links_account_campaign_fkey
+    FOREIGN KEY (account_id, campaign_id) REFERENCES campaigns(account_id, id) ON DELETE CASCADE;
+
+-- ========================================
+-- Names and keys are unique per account
+-- ========================================
+DROP INDEX IF EXISTS idx_campaigns_slug;
+CREATE UNIQUE INDEX idx_campaigns_slug ON campaigns(account_id, slug);
+DROP INDEX IF EXISTS idx_campaigns_external_key;
+CREATE UNIQUE INDEX idx_campaigns_external_key ON campaigns(account_id, external_key)
+    WHERE external_key IS NOT NULL;
+DROP INDEX IF EXISTS idx_campaigns_external_id;
+CREATE UNIQUE INDEX idx_campaigns_external_id ON campaigns(account_id, external_id)
+    WHERE external_id IS NOT NULL;
+DROP INDEX IF EXISTS idx_customers_external_id;
+CREATE UNIQUE INDEX idx_customers_external_id ON customers(account_id, external_id)
+    WHERE external_id IS NOT NULL;
+
+ALTER TABLE segments DROP CONSTRAINT IF EXISTS segments_name_key;
+ALTER TABLE segments DROP CONSTRAINT IF EXISTS segments_account_id_name_key;
+ALTER TABLE segments ADD CONSTRAINT segments_account_id_name_key UNIQUE (account_id, name);
+
+ALTER TABLE template_partials DROP CONSTRAINT IF EXISTS template_partials_pkey;
+ALTER TABLE template_partials ADD CONSTRAINT template_partials_pkey PRIMARY KEY (account_id, name);
+ALTER TABLE sender_warmups DROP CONSTRAINT IF EXISTS sender_warmups_pkey;
+ALTER TABLE sender_warmups ADD CONSTRAINT sender_warmups_pkey PRIMARY KEY (account_id, sender_id);
+ALTER TABLE sender_registrations DROP CONSTRAINT IF EXISTS sender_registrations_pkey;
+ALTER TABLE sender_registrations ADD CONSTRAINT sender_registrations_pkey PRIMARY KEY (account_id, sender_id, country);
+
+-- Users sign in by email alone, so emails stay unique across accounts
+
+-- Per-account listing and change feeds
+CREATE INDEX IF NOT EXISTS idx_outbound_messages_account ON outbound_messages(account_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_change_log_account ON change_log(account_id, id);
+
+-- ========================================
+-- Change log records carry the account of the changed row
+-- ========================================
+CREATE OR REPLACE FUNCTION record_change()
+RETURNS TRIGGER AS $$
+DECLARE
+    row_data JSONB;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        row_data := to_jsonb(OLD);
+        INSERT INTO change_log (account_id, entity, entity_id, operation)
+        VALUES ((row_data ->> 'account_id')::BIGINT, TG_ARGV[0], row_data ->> TG_ARGV[1], 'delete');
+        RETURN OLD;
+    END IF;
+
+    row_data := to_jsonb(NEW) - 'rendered_content';
+    INSERT INTO change_log (account_id, entity, entity_id, operation, data)
+    VALUES ((row_data ->> 'account_id')::BIGINT, TG_ARGV[0], row_data ->> TG_ARGV[1], lower(TG_OP), row_data);
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+COMMENT ON TABLE accounts IS 'Tenants; every customer, campaign and message belongs to one';
+COMMENT ON COLUMN campaigns.account_id IS 'Owning account; rows referencing the campaign must share it';
+COMMENT ON COLUMN customers.account_id IS 'Owning account; rows referencing the customer must share it';
+
+INSERT INTO schema_version (version, description) VALUES (30, 'Add accounts and account_id scoping');
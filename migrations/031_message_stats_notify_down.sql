@@ -0,0 +1,8 @@
+-- CampaignManager System - Rollback Message Stats Notifications
+
+DROP TRIGGER IF EXISTS notify_outbound_messages_delete ON outbound_messages;
+DROP TRIGGER IF EXISTS notify_outbound_messages_update ON outbound_messages;
+DROP TRIGGER IF EXISTS notify_outbound_messages_insert ON outbound_messages;
+DROP FUNCTION IF EXISTS notify_message_stats();
+
+DELETE FROM schema_version WHERE version = 31;
@@ -0,0 +1,77 @@
+-- CampaignManager System - Message Stats Notifications
+-- Every statement that changes the status of outbound messages sends one
+-- notification per campaign on the message_stats channel, carrying the net
+-- change in messages per status. API servers apply these to the counts they
+-- keep in memory for active campaigns instead of counting the messages on
+-- every read. The transaction ID lets a server tell whether a change is
+-- already part of the counts it loaded.
+
+CREATE OR REPLACE FUNCTION notify_message_stats()
+RETURNS TRIGGER AS $$
+DECLARE
+    xid TEXT := pg_current_xact_id()::TEXT;
+    changes REFCURSOR;
+    change RECORD;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        OPEN changes FOR
+            SELECT campaign_id, jsonb_object_agg(status, n) AS counts
+            FROM (SELECT campaign_id, status, COUNT(*) AS n FROM new_rows GROUP BY campaign_id, status) d
+            GROUP BY campaign_id;
+    ELSIF TG_OP = 'DELETE' THEN
+        OPEN changes FOR
+            SELECT campaign_id, jsonb_object_agg(status, n) AS counts
+            FROM (SELECT campaign_id, status, -COUNT(*) AS n FROM old_rows GROUP BY campaign_id, status) d
+            GROUP BY campaign_id;
+    ELSE
+        OPEN changes FOR
+            SELECT campaign_id, jsonb_object_agg(status, n) AS counts
+            FROM (
+                SELECT campaign_id, status, SUM(n) AS n
+                FROM (
+                    SELECT o.campaign_id, o.status, -1 AS n
+                    FROM old_rows o JOIN new_rows r ON r.id = o.id
+                    WHERE r.status <> o.status
+                    UNION ALL
+                    SELECT r.campaign_id, r.status, 1 AS n
+                    FROM old_rows o JOIN new_rows r ON r.id = o.id
+                    WHERE r.status <> o.status
+                ) moves
+                GROUP BY campaign_id, status
+                HAVING SUM(n) <> 0
+            ) d
+            GROUP BY campaign_id;
+    END IF;
+
+    LOOP
+        FETCH changes INTO change;
+        EXIT WHEN NOT FOUND;
+        PERFORM pg_notify('message_stats', json_build_object(
+            'xid', xid,
+            'campaign_id', change.campaign_id,
+            'counts', change.counts
+        )::TEXT);
+    END LOOP;
+    CLOSE changes;
+
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+-- Triggers with transition tables handle a single event each
+DROP TRIGGER IF EXISTS notify_outbound_messages_insert ON outbound_messages;
+CREATE TRIGGER notify_outbound_messages_insert AFTER INSERT ON outbound_messages
+    REFERENCING NEW TABLE AS new_rows
+    FOR EACH STATEMENT EXECUTE FUNCTION notify_message_stats();
+
+DROP TRIGGER IF EXISTS notify_outbound_messages_update ON outbound_messages;
+CREATE TRIGGER notify_outbound_messages_update AFTER UPDATE ON outbound_messages
+    REFERENCING OLD TABLE AS old_rows NEW TABLE AS new_rows
+    FOR EACH STATEMENT EXECUTE FUNCTION notify_message_stats();
+
+DROP TRIGGER IF EXISTS notify_outbound_messages_delete ON outbound_messages;
+CREATE TRIGGER notify_outbound_messages_delete AFTER DELETE ON outbound_messages
+    REFERENCING OLD TABLE AS old_rows
+    FOR EACH STATEMENT EXECUTE FUNCTION notify_message_stats();
+
+INSERT INTO schema_version (version, description) VALUES (31, 'Add message stats notifications');
@@ -0,0 +1,6 @@
+-- CampaignManager System - Rollback Message Outbox
+
+DROP INDEX IF EXISTS idx_outbound_messages_unqueued;
+ALTER TABLE outbound_messages DROP COLUMN IF EXISTS queued_at;
+
+DELETE FROM schema_version WHERE version = 32;
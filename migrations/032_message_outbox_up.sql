@@ -0,0 +1,18 @@
+-- CampaignManager System - Message Outbox
+-- A send stores its messages and then publishes a job for each. queued_at
+-- records that the job was published, so messages whose jobs never were, for
+-- example because the API stopped half-way through a send, can be found and
+-- published by the worker's outbox relay.
+
+ALTER TABLE outbound_messages ADD COLUMN IF NOT EXISTS queued_at TIMESTAMP;
+
+-- Messages stored before the outbox existed were published when they were sent
+UPDATE outbound_messages SET queued_at = created_at WHERE queued_at IS NULL;
+
+-- Messages waiting for the relay, oldest first
+CREATE INDEX IF NOT EXISTS idx_outbound_messages_unqueued ON outbound_messages(updated_at)
+    WHERE queued_at IS NULL AND status = 'pending';
+
+COMMENT ON COLUMN outbound_messages.queued_at IS 'When the job for this message was published; NULL until then';
+
+INSERT INTO schema_version (version, description) VALUES (32, 'Add message outbox');
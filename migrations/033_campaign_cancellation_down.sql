@@ -0,0 +1,25 @@
+-- CampaignManager System - Rollback Campaign Cancellation
+-- Cancelled campaigns and skipped messages are recorded as failed, the
+-- closest status that existed before.
+
+UPDATE campaigns SET status = 'failed' WHERE status = 'cancelled';
+UPDATE outbound_messages SET status = 'failed' WHERE status = 'skipped';
+
+ALTER TABLE campaigns
+    DROP CONSTRAINT IF EXISTS campaigns_status_check;
+
+ALTER TABLE campaigns
+    ADD CONSTRAINT campaigns_status_check
+    CHECK (status IN ('draft', 'scheduled', 'sending', 'paused', 'sent', 'failed'));
+
+ALTER TABLE outbound_messages
+    DROP CONSTRAINT IF EXISTS outbound_messages_status_check;
+
+ALTER TABLE outbound_messages
+    ADD CONSTRAINT outbound_messages_status_check
+    CHECK (status IN ('pending', 'sending', 'sent', 'failed', 'delivered', 'undelivered'));
+
+COMMENT ON COLUMN campaigns.status IS 'Campaign lifecycle: draft -> scheduled/sending <-> paused -> sent/failed';
+COMMENT ON COLUMN outbound_messages.status IS 'Message lifecycle: pending -> sending (claimed) -> sent/failed -> delivered/undelivered (delivery report)';
+
+DELETE FROM schema_version WHERE version = 33;
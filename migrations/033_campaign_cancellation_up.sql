@@ -0,0 +1,22 @@
+-- CampaignManager System - Campaign Cancellation
+-- Adds the 'cancelled' campaign status and the 'skipped' message status. A
+-- cancelled campaign sends nothing more: its pending messages are skipped.
+
+ALTER TABLE campaigns
+    DROP CONSTRAINT IF EXISTS campaigns_status_check;
+
+ALTER TABLE campaigns
+    ADD CONSTRAINT campaigns_status_check
+    CHECK (status IN ('draft', 'scheduled', 'sending', 'paused', 'sent', 'failed', 'cancelled'));
+
+ALTER TABLE outbound_messages
+    DROP CONSTRAINT IF EXISTS outbound_messages_status_check;
+
+ALTER TABLE outbound_messages
+    ADD CONSTRAINT outbound_messages_status_check
+    CHECK (status IN ('pending', 'sending', 'sent', 'failed', 'delivered', 'undelivered', 'skipped'));
+
+COMMENT ON COLUMN campaigns.status IS 'Campaign lifecycle: draft -> scheduled/sending <-> paused -> sent/failed, or cancelled from scheduled, sending or paused';
+COMMENT ON COLUMN outbound_messages.status IS 'Message lifecycle: pending -> sending (claimed) -> sent/failed -> delivered/undelivered (delivery report); pending -> skipped when the campaign is cancelled';
+
+INSERT INTO schema_version (version, description) VALUES (33, 'Add campaign cancellation');
@@ -0,0 +1,5 @@
+-- CampaignManager System - Rollback Campaign Concurrency Limit
+
+ALTER TABLE campaigns DROP COLUMN IF EXISTS max_in_flight;
+
+DELETE FROM schema_version WHERE version = 34;
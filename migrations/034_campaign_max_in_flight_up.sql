@@ -0,0 +1,10 @@
+-- CampaignManager System - Campaign Concurrency Limit
+-- A campaign can limit how many of its messages are being sent at once, for
+-- campaigns whose messages trigger call-backs to a fragile downstream. Workers
+-- hold a slot of a shared Redis semaphore for the duration of every send.
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS max_in_flight INTEGER CHECK (max_in_flight > 0);
+
+COMMENT ON COLUMN campaigns.max_in_flight IS 'Maximum messages of the campaign being sent at once across all workers; NULL means no limit';
+
+INSERT INTO schema_version (version, description) VALUES (34, 'Add campaign concurrency limit');
@@ -0,0 +1,18 @@
+-- CampaignManager System - Rollback Scheduled Campaign Pre-build
+-- Ready campaigns go back to scheduled. Their built messages are kept, and the
+-- scheduler moves a scheduled campaign that already has messages to sending.
+
+UPDATE campaigns SET status = 'scheduled' WHERE status = 'ready';
+
+ALTER TABLE campaigns
+    DROP CONSTRAINT IF EXISTS campaigns_status_check;
+
+ALTER TABLE campaigns
+    ADD CONSTRAINT campaigns_status_check
+    CHECK (status IN ('draft', 'scheduled', 'sending', 'paused', 'sent', 'failed', 'cancelled'));
+
+COMMENT ON COLUMN campaigns.status IS 'Campaign lifecycle: draft -> scheduled/sending <-> paused -> sent/failed, or cancelled from scheduled, sending or paused';
+
+ALTER TABLE campaigns DROP COLUMN IF EXISTS prebuild_minutes;
+
+DELETE FROM schema_version WHERE version = 35;
@@ -0,0 +1,19 @@
+-- CampaignManager System - Scheduled Campaign Pre-build
+-- A scheduled campaign can have its audience resolved and its messages
+-- rendered some minutes before scheduled_at. It is then 'ready', and at
+-- scheduled_at its messages only need to be queued.
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS prebuild_minutes INTEGER CHECK (prebuild_minutes > 0);
+
+COMMENT ON COLUMN campaigns.prebuild_minutes IS 'Minutes before scheduled_at at which the messages are built; NULL builds them at scheduled_at';
+
+ALTER TABLE campaigns
+    DROP CONSTRAINT IF EXISTS campaigns_status_check;
+
+ALTER TABLE campaigns
+    ADD CONSTRAINT campaigns_status_check
+    CHECK (status IN ('draft', 'scheduled', 'ready', 'sending', 'paused', 'sent', 'failed', 'cancelled'));
+
+COMMENT ON COLUMN campaigns.status IS 'Campaign lifecycle: draft -> scheduled (-> ready) /sending <-> paused -> sent/failed, or cancelled from scheduled, ready, sending or paused';
+
+INSERT INTO schema_version (version, description) VALUES (35, 'Add scheduled campaign pre-build');
@@ -0,0 +1,8 @@
+-- CampaignManager System - Rollback Delivery Window Auto-Resume
+-- Campaigns waiting for their next window stay paused and must be resumed by hand.
+
+DROP INDEX IF EXISTS idx_campaigns_resume_at;
+
+ALTER TABLE campaigns DROP COLUMN IF EXISTS resume_at;
+
+DELETE FROM schema_version WHERE version = 36;
@@ -0,0 +1,12 @@
+-- CampaignManager System - Delivery Window Auto-Resume
+-- A campaign that cannot finish within a delivery window is paused when the
+-- window closes. resume_at records when the next window opens; the scheduler
+-- resumes the campaign then and requeues its pending messages.
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS resume_at TIMESTAMP;
+
+COMMENT ON COLUMN campaigns.resume_at IS 'When the scheduler resumes a campaign paused outside its delivery windows; NULL for other campaigns';
+
+CREATE INDEX IF NOT EXISTS idx_campaigns_resume_at ON campaigns(resume_at) WHERE status = 'paused' AND resume_at IS NOT NULL;
+
+INSERT INTO schema_version (version, description) VALUES (36, 'Add delivery window auto-resume');
@@ -0,0 +1,6 @@
+-- CampaignManager System - Rollback Campaign Timezones
+-- Delivery windows are taken in UTC again.
+
+ALTER TABLE campaigns DROP COLUMN IF EXISTS timezone;
+
+DELETE FROM schema_version WHERE version = 37;
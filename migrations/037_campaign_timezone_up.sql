@@ -0,0 +1,10 @@
+-- CampaignManager System - Campaign Timezones
+-- Delivery windows are taken in the campaign's timezone, so a campaign can
+-- keep to local hours such as 08:00-20:00 Africa/Nairobi across daylight
+-- saving changes. Existing campaigns keep their windows in UTC.
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT 'UTC';
+
+COMMENT ON COLUMN campaigns.timezone IS 'IANA timezone the delivery windows are in, e.g. Africa/Nairobi';
+
+INSERT INTO schema_version (version, description) VALUES (37, 'Add campaign timezones');
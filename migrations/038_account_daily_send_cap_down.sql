@@ -0,0 +1,6 @@
+-- CampaignManager System - Rollback Account Daily Send Cap
+-- Campaigns already paused at the cap are still resumed the next day.
+
+ALTER TABLE accounts DROP COLUMN IF EXISTS daily_send_cap;
+
+DELETE FROM schema_version WHERE version = 38;
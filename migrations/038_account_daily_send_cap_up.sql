@@ -0,0 +1,10 @@
+-- CampaignManager System - Account Daily Send Cap
+-- Limits how many messages an account's live campaigns may send per UTC day.
+-- Campaigns that reach the cap are paused with reason daily_cap_reached and
+-- resumed by the scheduler the next day.
+
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS daily_send_cap INTEGER CHECK (daily_send_cap > 0);
+
+COMMENT ON COLUMN accounts.daily_send_cap IS 'Most messages the account may send per UTC day; NULL for no limit';
+
+INSERT INTO schema_version (version, description) VALUES (38, 'Add account daily send cap');
@@ -0,0 +1,8 @@
+-- CampaignManager System - Rollback Customer Attributes
+-- Templates that use an attribute render it as empty text afterwards.
+
+ALTER TABLE accounts DROP COLUMN IF EXISTS customer_attributes;
+
+ALTER TABLE customers DROP COLUMN IF EXISTS attributes;
+
+DELETE FROM schema_version WHERE version = 39;
@@ -0,0 +1,13 @@
+-- CampaignManager System - Customer Attributes
+-- Customers carry free-form attributes that templates use as {attr_name}
+-- placeholders. Each account declares the attribute keys its templates may
+-- use.
+
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}';
+
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS customer_attributes TEXT[] NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN customers.attributes IS 'Custom attributes by key, usable as template placeholders';
+COMMENT ON COLUMN accounts.customer_attributes IS 'Customer attribute keys the account''s templates may use';
+
+INSERT INTO schema_version (version, description) VALUES (39, 'Add customer attributes');
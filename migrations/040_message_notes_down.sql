@@ -0,0 +1,5 @@
+-- CampaignManager System - Rollback Message Notes
+
+DROP TABLE IF EXISTS message_notes;
+
+DELETE FROM schema_version WHERE version = 40;
@@ -0,0 +1,20 @@
+-- CampaignManager System - Message Notes
+-- Support agents record investigation outcomes ("customer confirmed received",
+-- "carrier ticket #123") against a message. Notes belong to the message's
+-- account and go when the message is deleted with its campaign.
+
+CREATE TABLE IF NOT EXISTS message_notes (
+    id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL REFERENCES accounts(id),
+    message_id BIGINT NOT NULL REFERENCES outbound_messages(id) ON DELETE CASCADE,
+    author VARCHAR(255),
+    body TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_notes_message ON message_notes(message_id, id);
+
+COMMENT ON TABLE message_notes IS 'Support notes attached to outbound messages';
+COMMENT ON COLUMN message_notes.author IS 'Email of the user who wrote the note; NULL when authentication is disabled';
+
+INSERT INTO schema_version (version, description) VALUES (40, 'Add message_notes');
@@ -0,0 +1,13 @@
+-- CampaignManager System - Rollback Customer Snooze
+-- Snoozed customers are included in campaigns again, and snooze events leave
+-- their timelines.
+
+DELETE FROM customer_events WHERE type IN ('snoozed', 'unsnoozed');
+
+ALTER TABLE customer_events DROP CONSTRAINT IF EXISTS customer_events_type_check;
+ALTER TABLE customer_events ADD CONSTRAINT customer_events_type_check
+    CHECK (type IN ('created', 'profile_updated', 'anonymized', 'opted_in', 'opted_out', 'imported'));
+
+ALTER TABLE customers DROP COLUMN IF EXISTS snoozed_until;
+
+DELETE FROM schema_version WHERE version = 41;
@@ -0,0 +1,14 @@
+-- CampaignManager System - Customer Snooze
+-- A customer can be kept out of campaigns until a date, e.g. while a
+-- complaint is handled, without opting them out. Snoozing and waking a
+-- customer are recorded on their timeline.
+
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS snoozed_until TIMESTAMP;
+
+COMMENT ON COLUMN customers.snoozed_until IS 'Campaigns skip the customer until this time; NULL when not snoozed';
+
+ALTER TABLE customer_events DROP CONSTRAINT IF EXISTS customer_events_type_check;
+ALTER TABLE customer_events ADD CONSTRAINT customer_events_type_check
+    CHECK (type IN ('created', 'profile_updated', 'anonymized', 'opted_in', 'opted_out', 'imported', 'snoozed', 'unsnoozed'));
+
+INSERT INTO schema_version (version, description) VALUES (41, 'Add customer snooze');
@@ -0,0 +1,12 @@
+-- CampaignManager System - Rollback Message Templates
+
+DROP INDEX IF EXISTS idx_campaigns_template;
+
+ALTER TABLE campaigns
+    DROP COLUMN IF EXISTS template_version,
+    DROP COLUMN IF EXISTS template_id;
+
+DROP TABLE IF EXISTS message_template_versions;
+DROP TABLE IF EXISTS message_templates;
+
+DELETE FROM schema_version WHERE version = 42;
@@ -0,0 +1,42 @@
+-- CampaignManager System - Message Templates
+-- Templates are named, versioned base templates that campaigns are created
+-- from. A campaign copies the content of the version it was created from;
+-- editing a template adds a version and carries it to the campaigns not yet
+-- sent that still hold the previous version's content.
+
+CREATE TABLE IF NOT EXISTS message_templates (
+    id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL REFERENCES accounts(id),
+    name VARCHAR(100) NOT NULL,
+    content TEXT NOT NULL,
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_message_templates_name ON message_templates(account_id, name);
+
+DROP TRIGGER IF EXISTS update_message_templates_updated_at ON message_templates;
+CREATE TRIGGER update_message_templates_updated_at BEFORE UPDATE ON message_templates
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE IF NOT EXISTS message_template_versions (
+    template_id BIGINT NOT NULL REFERENCES message_templates(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    content TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (template_id, version)
+);
+
+ALTER TABLE campaigns
+    ADD COLUMN IF NOT EXISTS template_id BIGINT REFERENCES message_templates(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS template_version INTEGER;
+
+CREATE INDEX IF NOT EXISTS idx_campaigns_template ON campaigns(template_id) WHERE template_id IS NOT NULL;
+
+COMMENT ON TABLE message_templates IS 'Named base templates that campaigns are created from';
+COMMENT ON TABLE message_template_versions IS 'Every version of a message template''s content';
+COMMENT ON COLUMN campaigns.template_id IS 'Message template the campaign was created from, if any';
+COMMENT ON COLUMN campaigns.template_version IS 'Version of the message template the base template holds';
+
+INSERT INTO schema_version (version, description) VALUES (42, 'Add message_templates');
@@ -0,0 +1,17 @@
+-- CampaignManager System - Rollback Bounce Policy
+-- Bounced numbers are included in campaigns again and scheduled soft bounce
+-- re-attempts are dropped.
+
+DROP INDEX IF EXISTS idx_outbound_messages_bounce_retry_at;
+
+ALTER TABLE customers DROP COLUMN IF EXISTS bounced_at;
+
+ALTER TABLE outbound_messages DROP COLUMN IF EXISTS bounce_retry_at;
+ALTER TABLE outbound_messages DROP COLUMN IF EXISTS bounce_retries;
+ALTER TABLE outbound_messages DROP COLUMN IF EXISTS bounce_type;
+
+ALTER TABLE accounts DROP COLUMN IF EXISTS suppress_hard_bounces;
+ALTER TABLE accounts DROP COLUMN IF EXISTS soft_bounce_max_retries;
+ALTER TABLE accounts DROP COLUMN IF EXISTS soft_bounce_retry_hours;
+
+DELETE FROM schema_version WHERE version = 43;
@@ -0,0 +1,32 @@
+-- CampaignManager System - Bounce Policy
+-- Undelivered reports are classified as soft bounces (the handset was off,
+-- the message expired) or hard bounces (the number does not exist or blocks
+-- us). Soft bounces are re-attempted after a delay and hard bounces suppress
+-- the number, both under a policy each account sets.
+
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS soft_bounce_retry_hours INT NOT NULL DEFAULT 4
+    CHECK (soft_bounce_retry_hours BETWEEN 1 AND 72);
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS soft_bounce_max_retries INT NOT NULL DEFAULT 2
+    CHECK (soft_bounce_max_retries BETWEEN 0 AND 10);
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS suppress_hard_bounces BOOLEAN NOT NULL DEFAULT TRUE;
+
+ALTER TABLE outbound_messages ADD COLUMN IF NOT EXISTS bounce_type VARCHAR(10)
+    CHECK (bounce_type IN ('soft', 'hard'));
+ALTER TABLE outbound_messages ADD COLUMN IF NOT EXISTS bounce_retries INT NOT NULL DEFAULT 0;
+ALTER TABLE outbound_messages ADD COLUMN IF NOT EXISTS bounce_retry_at TIMESTAMP;
+
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS bounced_at TIMESTAMP;
+
+-- The worker looks for soft bounces that are due for another attempt
+CREATE INDEX IF NOT EXISTS idx_outbound_messages_bounce_retry_at
+    ON outbound_messages(bounce_retry_at) WHERE bounce_retry_at IS NOT NULL;
+
+COMMENT ON COLUMN accounts.soft_bounce_retry_hours IS 'Hours after a soft bounce before the message is sent again';
+COMMENT ON COLUMN accounts.soft_bounce_max_retries IS 'Re-attempts of a soft bounced message; 0 disables them';
+COMMENT ON COLUMN accounts.suppress_hard_bounces IS 'Whether a hard bounce keeps the number out of later campaigns';
+COMMENT ON COLUMN outbound_messages.bounce_type IS 'soft or hard when the last delivery report was undelivered';
+COMMENT ON COLUMN outbound_messages.bounce_retries IS 'Times the message was sent again after a soft bounce';
+COMMENT ON COLUMN outbound_messages.bounce_retry_at IS 'When the soft bounced message is sent again; NULL when no re-attempt is scheduled';
+COMMENT ON COLUMN customers.bounced_at IS 'When the number hard bounced; campaigns skip it until the phone changes';
+
+INSERT INTO schema_version (version, description) VALUES (43, 'Add bounce policy');
@@ -0,0 +1,8 @@
+-- CampaignManager System - Rollback Number Lookups
+-- Campaigns send to every number again without looking it up first.
+
+DROP TABLE IF EXISTS number_lookups;
+
+ALTER TABLE campaigns DROP COLUMN IF EXISTS validate_numbers;
+
+DELETE FROM schema_version WHERE version = 44;
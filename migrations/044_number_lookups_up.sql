@@ -0,0 +1,20 @@
+-- CampaignManager System - Number Lookups
+-- Campaigns flagged validate_numbers have each number looked up with the
+-- number lookup provider (HLR) before it is sent to, and unreachable numbers
+-- are skipped. Lookup results are cached per phone number.
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS validate_numbers BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Reachability is a fact about the number, not about any account's customer,
+-- so results are shared between accounts
+CREATE TABLE IF NOT EXISTS number_lookups (
+    phone VARCHAR(20) PRIMARY KEY,
+    reachable BOOLEAN NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    checked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON COLUMN campaigns.validate_numbers IS 'Whether numbers are looked up before sending and unreachable ones skipped';
+COMMENT ON TABLE number_lookups IS 'Cached number lookup results; older than 30 days they are looked up again';
+
+INSERT INTO schema_version (version, description) VALUES (44, 'Add number lookups');
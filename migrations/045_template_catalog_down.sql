@@ -0,0 +1,6 @@
+-- CampaignManager System - Rollback Template Catalog
+-- Campaigns created from the catalog keep their content.
+
+DROP TABLE IF EXISTS template_catalog;
+
+DELETE FROM schema_version WHERE version = 45;
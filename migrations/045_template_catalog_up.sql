@@ -0,0 +1,35 @@
+-- CampaignManager System - Template Catalog
+-- A read-only catalog of starter templates that any account can create a
+-- campaign from. Entries are maintained here, not through the API.
+
+CREATE TABLE IF NOT EXISTS template_catalog (
+    slug VARCHAR(50) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    category VARCHAR(50) NOT NULL,
+    description TEXT NOT NULL,
+    channel VARCHAR(20) NOT NULL CHECK (channel IN ('sms', 'whatsapp')),
+    content TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO template_catalog (slug, name, category, description, channel, content) VALUES
+    ('welcome', 'Welcome', 'welcome',
+     'Greets a new customer by name.',
+     'sms', 'Hi {first_name}, welcome aboard! We''re glad to have you in {location|default:our community}.'),
+    ('welcome-whatsapp', 'Welcome (WhatsApp)', 'welcome',
+     'Greets a new customer and points them to their favourite product.',
+     'whatsapp', 'Hi {first_name}, welcome! Thanks for joining us. Take a look at our {preferred_product|default:latest range} whenever you''re ready.'),
+    ('promo', 'Promotion', 'promo',
+     'Announces an offer on the customer''s preferred product.',
+     'sms', 'Hi {first_name}, {preferred_product|default:our best sellers} are on offer this week only. Don''t miss out!'),
+    ('promo-recommended', 'Recommended Promotion', 'promo',
+     'Announces an offer on the product recommended for each customer.',
+     'sms', 'Hi {first_name}, we picked {recommended_product} just for you. Get it at a special price today.'),
+    ('reminder', 'Reminder', 'reminder',
+     'Reminds a customer about an upcoming date or appointment.',
+     'sms', 'Hi {first_name}, a friendly reminder from us. Reply if you have any questions.')
+ON CONFLICT (slug) DO NOTHING;
+
+COMMENT ON TABLE template_catalog IS 'Starter templates, shared by every account, that campaigns can be created from';
+
+INSERT INTO schema_version (version, description) VALUES (45, 'Add template catalog');
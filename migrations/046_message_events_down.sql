@@ -0,0 +1,6 @@
+-- CampaignManager System - Rollback Message Events
+-- Messages keep their status; their send history is lost.
+
+DROP TABLE IF EXISTS message_events;
+
+DELETE FROM schema_version WHERE version = 46;
@@ -0,0 +1,20 @@
+-- CampaignManager System - Message Events
+-- A message's status only says where it ended up. Every step of its send is
+-- also recorded as an event, so support staff can trace what happened to a
+-- specific text. Events go when the message is deleted with its campaign.
+
+CREATE TABLE IF NOT EXISTS message_events (
+    id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL REFERENCES accounts(id),
+    message_id BIGINT NOT NULL REFERENCES outbound_messages(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('queued', 'attempted', 'retried', 'failed', 'sent', 'delivered', 'undelivered', 'skipped')),
+    detail TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_events_message ON message_events(message_id, id);
+
+COMMENT ON TABLE message_events IS 'Send history of outbound messages, one row per step';
+COMMENT ON COLUMN message_events.detail IS 'Error, provider message ID or other context of the step';
+
+INSERT INTO schema_version (version, description) VALUES (46, 'Add message_events');
@@ -0,0 +1,9 @@
+-- CampaignManager System - Rollback Segment Conditions
+-- Segments with conditions on numbers or dates fail to match until removed.
+
+DROP FUNCTION IF EXISTS segment_timestamp(TEXT);
+DROP FUNCTION IF EXISTS segment_numeric(TEXT);
+
+COMMENT ON COLUMN segments.filter IS 'Criteria a customer must all match: location, preferred_product, phone_prefix';
+
+DELETE FROM schema_version WHERE version = 47;
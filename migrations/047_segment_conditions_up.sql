@@ -0,0 +1,26 @@
+-- CampaignManager System - Segment Conditions
+-- Segment filters can hold conditions on custom attributes. Attributes are
+-- stored as text, so comparing them as numbers or dates needs a cast that
+-- yields NULL, and so never matches, for values that are not one.
+
+CREATE OR REPLACE FUNCTION segment_numeric(value TEXT) RETURNS NUMERIC AS $$
+BEGIN
+    RETURN value::NUMERIC;
+EXCEPTION WHEN OTHERS THEN
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION segment_timestamp(value TEXT) RETURNS TIMESTAMPTZ AS $$
+BEGIN
+    RETURN value::TIMESTAMPTZ;
+EXCEPTION WHEN OTHERS THEN
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql STABLE;
+
+COMMENT ON FUNCTION segment_numeric(TEXT) IS 'Attribute value as a number, or NULL when it is not one';
+COMMENT ON FUNCTION segment_timestamp(TEXT) IS 'Attribute value as a timestamp, or NULL when it is not one';
+COMMENT ON COLUMN segments.filter IS 'Criteria a customer must all match: location, preferred_product, phone_prefix and conditions';
+
+INSERT INTO schema_version (version, description) VALUES (47, 'Add segment condition casts');
@@ -0,0 +1,6 @@
+-- CampaignManager System - Rollback Analytics Indexes
+-- Overviews still work, scanning more messages.
+
+DROP INDEX IF EXISTS idx_outbound_messages_account_created_at;
+
+DELETE FROM schema_version WHERE version = 48;
@@ -0,0 +1,7 @@
+-- CampaignManager System - Analytics Indexes
+-- The analytics overview groups an account's messages by the day or week
+-- they were created in. This index lets it read just the period's messages.
+
+CREATE INDEX IF NOT EXISTS idx_outbound_messages_account_created_at ON outbound_messages(account_id, created_at);
+
+INSERT INTO schema_version (version, description) VALUES (48, 'Add analytics indexes');
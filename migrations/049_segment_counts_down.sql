@@ -0,0 +1,6 @@
+-- CampaignManager System - Rollback Segment Counts
+-- Segments are counted whenever they are read again.
+
+DROP TABLE IF EXISTS segment_counts;
+
+DELETE FROM schema_version WHERE version = 49;
@@ -0,0 +1,17 @@
+-- CampaignManager System - Segment Counts
+-- Counting the customers a segment matches scans the customer base, so the
+-- count is cached. Viewing a segment's members or asking for a recount
+-- refreshes it, and the worker recounts stale counts on a schedule.
+
+CREATE TABLE IF NOT EXISTS segment_counts (
+    segment_id BIGINT PRIMARY KEY REFERENCES segments(id) ON DELETE CASCADE,
+    account_id BIGINT NOT NULL REFERENCES accounts(id),
+    customer_count BIGINT NOT NULL,
+    counted_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_segment_counts_counted_at ON segment_counts(counted_at);
+
+COMMENT ON TABLE segment_counts IS 'Cached number of customers each segment matched when last counted';
+
+INSERT INTO schema_version (version, description) VALUES (49, 'Add segment_counts');
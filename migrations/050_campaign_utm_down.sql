@@ -0,0 +1,6 @@
+-- CampaignManager System - Rollback Campaign UTM Parameters
+-- Messages already rendered keep their tagged links.
+
+ALTER TABLE campaigns DROP COLUMN IF EXISTS utm;
+
+DELETE FROM schema_version WHERE version = 50;
@@ -0,0 +1,10 @@
+-- CampaignManager System - Campaign UTM Parameters
+-- A campaign can carry UTM parameters, which are appended to every link in
+-- its messages as they are rendered so web analytics can attribute the
+-- traffic to the campaign.
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS utm JSONB;
+
+COMMENT ON COLUMN campaigns.utm IS 'UTM parameters (source, medium, campaign, term, content) appended to the links of rendered messages; NULL leaves links untagged';
+
+INSERT INTO schema_version (version, description) VALUES (50, 'Add campaign UTM parameters');
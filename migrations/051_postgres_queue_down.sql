@@ -0,0 +1,12 @@
+-- CampaignManager System - Rollback Postgres Queue
+-- Switch QUEUE_BACKEND back to redis first; jobs still queued here are lost.
+
+DROP TABLE IF EXISTS semaphore_leases;
+DROP TABLE IF EXISTS quota_counters;
+DROP TABLE IF EXISTS rate_limit_buckets;
+DROP TABLE IF EXISTS queue_maintenance;
+DROP TABLE IF EXISTS queue_quarantine;
+DROP TABLE IF EXISTS queue_consumers;
+DROP TABLE IF EXISTS queue_jobs;
+
+DELETE FROM schema_version WHERE version = 51;
@@ -0,0 +1,84 @@
+-- CampaignManager System - Postgres Queue
+-- With QUEUE_BACKEND=postgres the job queue, progress events, rate limits,
+-- quotas and send slots are kept in this database instead of Redis, so a
+-- deployment can run without Redis. Consumers take jobs with
+-- SELECT ... FOR UPDATE SKIP LOCKED. The tables stay empty on the Redis
+-- backend.
+
+CREATE TABLE IF NOT EXISTS queue_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    queue VARCHAR(100) NOT NULL,
+    campaign_id BIGINT NOT NULL DEFAULT 0,
+    payload TEXT NOT NULL,
+    ready_at TIMESTAMPTZ NOT NULL,
+    turn BIGINT,
+    consumer VARCHAR(255),
+    taken_at TIMESTAMPTZ
+);
+
+-- Ready jobs in the order they are taken, the last turn of each campaign's
+-- lane, the longest waiting ready job, delayed jobs by due time, and the jobs
+-- each consumer holds
+CREATE INDEX IF NOT EXISTS idx_queue_jobs_ready ON queue_jobs(queue, turn, id) WHERE consumer IS NULL AND turn IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_queue_jobs_lane ON queue_jobs(queue, campaign_id, turn) WHERE consumer IS NULL AND turn IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_queue_jobs_waiting ON queue_jobs(queue, ready_at) WHERE consumer IS NULL AND turn IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_queue_jobs_delayed ON queue_jobs(queue, ready_at) WHERE turn IS NULL;
+CREATE INDEX IF NOT EXISTS idx_queue_jobs_consumer ON queue_jobs(consumer) WHERE consumer IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS queue_consumers (
+    consumer VARCHAR(255) PRIMARY KEY,
+    queue VARCHAR(100) NOT NULL,
+    heartbeat_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_consumers_heartbeat ON queue_consumers(queue, heartbeat_at);
+
+CREATE TABLE IF NOT EXISTS queue_quarantine (
+    id BIGSERIAL PRIMARY KEY,
+    queue VARCHAR(100) NOT NULL,
+    payload TEXT NOT NULL,
+    error TEXT NOT NULL,
+    quarantined_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_quarantine_queue ON queue_quarantine(queue, id);
+
+CREATE TABLE IF NOT EXISTS queue_maintenance (
+    queue VARCHAR(100) PRIMARY KEY,
+    reason TEXT NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS rate_limit_buckets (
+    key VARCHAR(255) PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS quota_counters (
+    key VARCHAR(255) NOT NULL,
+    window_start TIMESTAMPTZ NOT NULL,
+    used INTEGER NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (key, window_start)
+);
+
+CREATE TABLE IF NOT EXISTS semaphore_leases (
+    key VARCHAR(255) NOT NULL,
+    lease VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (key, lease)
+);
+
+COMMENT ON TABLE queue_jobs IS 'Message jobs of the Postgres queue backend, from publish until acknowledged';
+COMMENT ON COLUMN queue_jobs.ready_at IS 'When the job became ready, or for a delayed job when it will';
+COMMENT ON COLUMN queue_jobs.turn IS 'Place in the rotation across campaign lanes; NULL while the job is delayed';
+COMMENT ON COLUMN queue_jobs.consumer IS 'Consumer holding the job in flight; NULL while it waits';
+COMMENT ON TABLE queue_consumers IS 'Running consumers; the jobs of one that misses its heartbeat are returned to the queue';
+COMMENT ON TABLE queue_quarantine IS 'Payloads that could not be decoded or whose handler panicked';
+COMMENT ON TABLE queue_maintenance IS 'Maintenance mode flag per queue; a row means maintenance is on';
+COMMENT ON TABLE rate_limit_buckets IS 'Token bucket per rate limited key, shared by every worker';
+COMMENT ON TABLE quota_counters IS 'Usage per quota key and window, kept for one extra window';
+COMMENT ON TABLE semaphore_leases IS 'Held send slots per key; expired leases are freed on the next acquire';
+
+INSERT INTO schema_version (version, description) VALUES (51, 'Add Postgres queue backend tables');
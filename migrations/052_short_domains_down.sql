@@ -0,0 +1,7 @@
+-- CampaignManager System - Rollback Short Domains
+
+ALTER TABLE campaigns DROP COLUMN IF EXISTS short_domain_id;
+
+DROP TABLE IF EXISTS short_domains;
+
+DELETE FROM schema_version WHERE version = 52;
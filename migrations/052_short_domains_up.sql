@@ -0,0 +1,37 @@
+-- CampaignManager System - Short Domains
+-- Accounts register their own short domains for the links in their
+-- messages, so a domain flagged by carriers only affects the account that
+-- uses it. Each domain is checked for DNS and HTTPS on a schedule; a
+-- campaign uses its chosen domain while it is healthy, and otherwise the
+-- account's default or another healthy domain.
+
+CREATE TABLE IF NOT EXISTS short_domains (
+    id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL REFERENCES accounts(id),
+    domain VARCHAR(253) NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    status VARCHAR(20) NOT NULL DEFAULT 'unchecked' CHECK (status IN ('unchecked', 'healthy', 'unhealthy')),
+    last_error TEXT,
+    checked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_short_domains_domain ON short_domains(domain);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_short_domains_default ON short_domains(account_id) WHERE is_default;
+CREATE INDEX IF NOT EXISTS idx_short_domains_checked_at ON short_domains(checked_at) WHERE enabled;
+
+DROP TRIGGER IF EXISTS update_short_domains_updated_at ON short_domains;
+CREATE TRIGGER update_short_domains_updated_at BEFORE UPDATE ON short_domains
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS short_domain_id BIGINT REFERENCES short_domains(id) ON DELETE SET NULL;
+
+COMMENT ON TABLE short_domains IS 'Short domains accounts use for the links in their messages';
+COMMENT ON COLUMN short_domains.is_default IS 'Used by campaigns without a healthy domain of their own; at most one per account';
+COMMENT ON COLUMN short_domains.status IS 'Result of the last health check: unchecked, healthy or unhealthy';
+COMMENT ON COLUMN short_domains.last_error IS 'Why the last health check failed; NULL when it passed';
+COMMENT ON COLUMN campaigns.short_domain_id IS 'Short domain chosen for the campaign''s links; NULL uses the account''s default';
+
+INSERT INTO schema_version (version, description) VALUES (52, 'Add short_domains');
@@ -0,0 +1,6 @@
+-- CampaignManager System - Rollback Message Claims
+
+DROP INDEX IF EXISTS idx_outbound_messages_claimed;
+ALTER TABLE outbound_messages DROP COLUMN IF EXISTS claimed_at;
+
+DELETE FROM schema_version WHERE version = 53;
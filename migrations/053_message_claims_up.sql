@@ -0,0 +1,14 @@
+-- CampaignManager System - Message Claims
+-- A worker claims a message by moving it from pending to 'sending' before it
+-- sends, so duplicate jobs of the message cannot both send it. claimed_at
+-- starts the claim's lease: the outbox relay returns messages whose claim is
+-- older than the lease to pending, e.g. after the worker holding them died.
+
+ALTER TABLE outbound_messages ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMPTZ;
+
+-- Claims in flight, oldest first
+CREATE INDEX IF NOT EXISTS idx_outbound_messages_claimed ON outbound_messages(claimed_at) WHERE status = 'sending';
+
+COMMENT ON COLUMN outbound_messages.claimed_at IS 'When a worker last claimed the message for sending';
+
+INSERT INTO schema_version (version, description) VALUES (53, 'Add message claims');
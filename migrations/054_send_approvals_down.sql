@@ -0,0 +1,21 @@
+-- CampaignManager System - Rollback Two-Person Send Approval
+-- Campaigns awaiting approval are cancelled and their held messages skipped,
+-- as nothing could release them any more.
+
+UPDATE outbound_messages SET status = 'skipped'
+WHERE status = 'pending' AND campaign_id IN (SELECT id FROM campaigns WHERE status = 'awaiting_approval');
+
+UPDATE campaigns SET status = 'cancelled' WHERE status = 'awaiting_approval';
+
+ALTER TABLE campaigns
+    DROP CONSTRAINT IF EXISTS campaigns_status_check;
+
+ALTER TABLE campaigns
+    ADD CONSTRAINT campaigns_status_check
+    CHECK (status IN ('draft', 'scheduled', 'ready', 'sending', 'paused', 'sent', 'failed', 'cancelled'));
+
+COMMENT ON COLUMN campaigns.status IS 'Campaign lifecycle: draft -> scheduled (-> ready) /sending <-> paused -> sent/failed, or cancelled from scheduled, ready, sending or paused';
+
+DROP TABLE IF EXISTS send_jobs;
+
+DELETE FROM schema_version WHERE version = 54;
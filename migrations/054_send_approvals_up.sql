@@ -0,0 +1,33 @@
+-- CampaignManager System - Two-Person Send Approval
+-- A send to an audience above SEND_APPROVAL_THRESHOLD has its messages built
+-- but not queued. The campaign is 'awaiting_approval' and a send job records
+-- who requested it; a different user approves the job to release the
+-- messages.
+
+CREATE TABLE IF NOT EXISTS send_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL REFERENCES accounts(id),
+    campaign_id BIGINT NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    status VARCHAR(30) NOT NULL DEFAULT 'awaiting_second_approval' CHECK (status IN ('awaiting_second_approval', 'approved')),
+    recipient_count BIGINT NOT NULL,
+    requested_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    approved_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    approved_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_send_jobs_campaign ON send_jobs(campaign_id);
+
+ALTER TABLE campaigns
+    DROP CONSTRAINT IF EXISTS campaigns_status_check;
+
+ALTER TABLE campaigns
+    ADD CONSTRAINT campaigns_status_check
+    CHECK (status IN ('draft', 'scheduled', 'ready', 'awaiting_approval', 'sending', 'paused', 'sent', 'failed', 'cancelled'));
+
+COMMENT ON TABLE send_jobs IS 'Sends above the approval threshold, held until a second user approves them';
+COMMENT ON COLUMN send_jobs.recipient_count IS 'Audience size before exclusions, as checked against the approval threshold';
+COMMENT ON COLUMN send_jobs.requested_by IS 'User who sent the campaign, who cannot approve it; NULL for scheduled sends';
+COMMENT ON COLUMN campaigns.status IS 'Campaign lifecycle: draft -> scheduled (-> ready) (-> awaiting_approval) /sending <-> paused -> sent/failed, or cancelled from scheduled, ready, awaiting_approval, sending or paused';
+
+INSERT INTO schema_version (version, description) VALUES (54, 'Add two-person send approval');
@@ -0,0 +1,5 @@
+-- CampaignManager System - Rollback Campaign Building
+
+ALTER TABLE campaigns DROP COLUMN IF EXISTS building;
+
+DELETE FROM schema_version WHERE version = 55;
@@ -0,0 +1,11 @@
+-- CampaignManager System - Campaign Building
+-- A send creates a campaign's messages in batches while workers are already
+-- sending the first ones. building is set while a send is still creating
+-- messages, so the worker that records the last outcome of an early batch does
+-- not complete the campaign before the later batches exist.
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS building BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN campaigns.building IS 'Whether a send is still creating the campaign''s messages';
+
+INSERT INTO schema_version (version, description) VALUES (55, 'Add campaign building flag');